@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+// alertCmd groups the saved-search commands.
+var alertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Saved searches that report new results",
+	Long: `Save named PubMed queries and report only the results added since the last
+run, e.g. from cron. Alerts are stored in the user database, pubmed.db under
+the pubmed-cli config directory (~/.config/pubmed-cli on Linux; override with
+PUBMED_CLI_CONFIG_DIR).`,
+}
+
+var alertAddCmd = &cobra.Command{
+	Use:   "add <name> <query>",
+	Short: "Save a search; its current results count as already seen",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		book, err := loadAlerts()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		a, err := book.Add(args[0], buildQuery(args[1:]), now)
+		if err != nil {
+			return err
+		}
+		baseline, _, err := alert.Check(cmd.Context(), newEutilsClient(), a, alertLimit(cmd), now)
+		if err != nil {
+			return err
+		}
+		if err := saveAlerts(book); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved alert %q; %d current results marked as seen\n", a.Name, len(baseline))
+		return nil
+	},
+}
+
+var alertListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved alerts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		book, err := loadAlerts()
+		if err != nil {
+			return err
+		}
+		return output.FormatAlerts(cmd.OutOrStdout(), book.Alerts, outputCfg())
+	},
+}
+
+var alertRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a saved alert",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		book, err := loadAlerts()
+		if err != nil {
+			return err
+		}
+		if err := book.Remove(args[0]); err != nil {
+			return err
+		}
+		return saveAlerts(book)
+	},
+}
+
+var alertRunCmd = &cobra.Command{
+	Use:   "run [name...]",
+	Short: "Report new results for all (or the named) alerts",
+	Long: `Search each alert's query for records added to PubMed since its last run and
+print only PMIDs it has not reported before, then mark them seen. Nothing is
+printed to stdout when there is nothing new, so cron mails only real news.
+--csv, --ris, and --bibtex export the new articles.
+
+--webhook (or PUBMED_CLI_WEBHOOK) posts a summary of the new articles when
+there are any: as a chat message to a Slack or Discord incoming webhook, or
+as a JSON event, with each alert's new PMIDs, titles, and links, to any
+other URL.
+
+--email sends an HTML digest of the new articles, with a plain-text
+alternative, to the given addresses when there are any, through the SMTP
+server in PUBMED_CLI_SMTP_HOST (and PUBMED_CLI_SMTP_PORT, default 587;
+PUBMED_CLI_SMTP_USER and PUBMED_CLI_SMTP_PASSWORD to log in; and
+PUBMED_CLI_SMTP_FROM, default the user name, as the sender).`,
+	Example: `  pubmed alert run
+  0 7 * * * pubmed alert run --ris ~/new-papers.ris
+  0 7 * * * pubmed alert run --webhook https://hooks.slack.com/services/T000/B000/XXXX
+  0 7 * * * pubmed alert run --email me@example.org`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mailCfg, mailTo, err := emailConfig()
+		if err != nil {
+			return err
+		}
+		book, err := loadAlerts()
+		if err != nil {
+			return err
+		}
+		selected := book.Alerts
+		if len(args) > 0 {
+			selected = nil
+			for _, name := range args {
+				a := book.Get(name)
+				if a == nil {
+					return fmt.Errorf("no alert named %q", name)
+				}
+				selected = append(selected, a)
+			}
+		}
+
+		client := newEutilsClient()
+		now := time.Now()
+		var (
+			results []alert.Result
+			failed  []string
+			total   int
+		)
+		for _, a := range selected {
+			fresh, truncated, err := alert.Check(cmd.Context(), client, a, alertLimit(cmd), now)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed = append(failed, a.Name)
+				continue
+			}
+			r := alert.Result{Name: a.Name, Query: a.Query, New: fresh, Truncated: truncated}
+			if len(fresh) > 0 {
+				r.Articles, err = fetchInBatches(cmd.Context(), client, fresh)
+				if err != nil {
+					// Non-fatal: PMIDs alone still identify the new records.
+					fmt.Fprintf(os.Stderr, "Warning: could not fetch article details: %v\n", err)
+				}
+			}
+			total += len(fresh)
+			results = append(results, r)
+		}
+
+		if err := saveAlerts(book); err != nil {
+			return err
+		}
+		if err := output.FormatAlertResults(cmd.OutOrStdout(), results, outputCfg()); err != nil {
+			return err
+		}
+		if total == 0 {
+			fmt.Fprintln(os.Stderr, "No new results")
+		} else {
+			notify(cmd.Context(), webhook.AlertEvent(results, now))
+			if mailTo != nil {
+				mailDigest(mailCfg, mailTo, results, now)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("alerts failed: %s", strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+var alertDiffCmd = &cobra.Command{
+	Use:   "diff <name>",
+	Short: "Show how an alert's results changed since an earlier diff",
+	Long: `Search an alert's full query now and compare the result set with the one
+recorded by an earlier diff: the PMIDs that appeared, those that
+disappeared (merged, deleted, or reindexed out of the query), and the
+records whose status changed, such as ahead of print to published, or
+published to retracted, corrected, or under an expression of concern.
+
+Each diff records a snapshot of the result set for later diffs. By default
+the newest snapshot is the baseline; --since picks the newest one taken on
+or before a date. An alert without snapshots is compared with the PMIDs it
+has reported, whose statuses are unknown, so its first diff lists no status
+changes. Result sets are capped at --limit (default 500), so on larger
+alerts records can appear or disappear at the cutoff. --csv, --ris, and
+--bibtex export the articles that appeared or changed.`,
+	Example: `  pubmed alert diff fxs --human
+  pubmed alert diff fxs --since 2024-01-01 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		book, err := loadAlerts()
+		if err != nil {
+			return err
+		}
+		a := book.Get(args[0])
+		if a == nil {
+			return fmt.Errorf("no alert named %q", args[0])
+		}
+		now := time.Now()
+		var since time.Time
+		if flagSince != "" {
+			if since, err = snapshotTime(flagSince, now); err != nil {
+				return fmt.Errorf("invalid --since value %q: %w", flagSince, err)
+			}
+		}
+		baseline, ok := a.SnapshotAt(since)
+		switch {
+		case ok:
+		case len(a.Snapshots) > 0:
+			return fmt.Errorf("alert %q has no snapshot from before %s; the first is from %s",
+				a.Name, flagSince, a.Snapshots[0].Time.Format("2006-01-02"))
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: alert %q has no snapshots yet; comparing with the PMIDs it has reported\n", a.Name)
+			baseline = a.SeenSnapshot()
+		}
+
+		client := newEutilsClient()
+		result, err := client.Search(cmd.Context(), a.Query, &eutils.SearchOptions{Limit: alertLimit(cmd)})
+		if err != nil {
+			return fmt.Errorf("alert %q: %w", a.Name, err)
+		}
+		var articles []eutils.Article
+		if len(result.IDs) > 0 {
+			if articles, err = fetchInBatches(cmd.Context(), client, result.IDs); err != nil {
+				return fmt.Errorf("fetch failed: %w", err)
+			}
+		}
+		current := alert.NewSnapshot(articles, result.Count, now)
+
+		d := alert.Compare(a, baseline, current)
+		listed := make(map[string]bool)
+		for _, id := range d.Appeared {
+			listed[id] = true
+		}
+		for _, c := range d.Changed {
+			listed[c.PMID] = true
+		}
+		for _, art := range articles {
+			if listed[art.PMID] {
+				d.Articles = append(d.Articles, art)
+			}
+		}
+
+		a.AddSnapshot(current)
+		if err := saveAlerts(book); err != nil {
+			return err
+		}
+		return output.FormatAlertDiff(cmd.OutOrStdout(), d, outputCfg())
+	},
+}
+
+// snapshotTime converts a --since value for alert diff to the end of the
+// day (or month or year) it names, so a snapshot taken that day counts.
+func snapshotTime(value string, now time.Time) (time.Time, error) {
+	date, err := parseDateFlag(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range []struct {
+		format string
+		years  int
+		months int
+		days   int
+	}{{"2006/01/02", 0, 0, 1}, {"2006/01", 0, 1, 0}, {"2006", 1, 0, 0}} {
+		if t, err := time.ParseInLocation(layout.format, date, time.Local); err == nil {
+			return t.AddDate(layout.years, layout.months, layout.days).Add(-time.Nanosecond), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s is not a valid date", value)
+}
+
+func init() {
+	alertAddCmd.ValidArgsFunction = completeQueryAfter(1)
+	alertRunCmd.ValidArgsFunction = completeAlertNames
+	alertRemoveCmd.ValidArgsFunction = completeAlertNames
+	alertDiffCmd.ValidArgsFunction = completeAlertNames
+
+	alertCmd.AddCommand(alertAddCmd)
+	alertCmd.AddCommand(alertListCmd)
+	alertCmd.AddCommand(alertRemoveCmd)
+	alertCmd.AddCommand(alertRunCmd)
+	alertCmd.AddCommand(alertDiffCmd)
+}
+
+// alertLimit is --limit when given, otherwise alert.DefaultLimit.
+func alertLimit(cmd *cobra.Command) int {
+	if cmd.Flags().Changed("limit") {
+		return flagLimit
+	}
+	return alert.DefaultLimit
+}
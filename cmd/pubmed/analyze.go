@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultAnalyzeLimit is how many search results analyze commands fetch
+// when --limit is not given; aggregate statistics need more than a page.
+const defaultAnalyzeLimit = 200
+
+// fetchBatchSize caps PMIDs per efetch request to keep URLs reasonable.
+const fetchBatchSize = 200
+
+var (
+	flagAnalyzeQuery string
+	flagAnalyzePMIDs string
+	flagAnalyzeAll   bool
+	flagAnalyzeTop   int
+)
+
+// analyzeCmd groups commands that summarize a set of articles.
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Summarize a set of articles",
+	Long: `Compute aggregate statistics over the articles matching --query, or over an
+explicit --pmids list.`,
+}
+
+// analyzeMeshCmd reports the most frequent MeSH descriptors in a result set.
+var analyzeMeshCmd = &cobra.Command{
+	Use:   "mesh",
+	Short: "Most frequent MeSH headings in a result set",
+	Long: `Fetch the articles matching --query (or listed in --pmids) and count how many
+carry each MeSH descriptor as a major topic, with percentages of the set.
+--all counts every heading, not just major topics. Use --csv to export.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagAnalyzeTop <= 0 {
+			return errUsage("--top must be greater than 0")
+		}
+		articles, err := analyzeArticles(cmd, newEutilsClient(), flagAnalyzeQuery, flagAnalyzePMIDs)
+		if err != nil {
+			return err
+		}
+
+		report := analyze.MeSHFrequency(articles, !flagAnalyzeAll, flagAnalyzeTop)
+		return output.FormatMeSHFrequency(cmd.OutOrStdout(), report, outputCfg())
+	},
+}
+
+// analyzeKeywordsCmd reports the distinctive vocabulary of a result set.
+var analyzeKeywordsCmd = &cobra.Command{
+	Use:   "keywords",
+	Short: "TF-IDF keywords and frequent bigrams in a result set",
+	Long: `Fetch the articles matching --query (or listed in --pmids) and rank the words
+of their titles and abstracts by TF-IDF, list the word pairs used in at least
+two articles, and count author-supplied keywords. Terms the query does not
+already contain are suggested as additions. Use --csv to export.`,
+	Example: `  pubmed analyze keywords --query "fragile x syndrome AND metformin"
+  pubmed analyze keywords --query "fragile x syndrome" --limit 500 --top 30 --csv keywords.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagAnalyzeTop <= 0 {
+			return errUsage("--top must be greater than 0")
+		}
+		articles, err := analyzeArticles(cmd, newEutilsClient(), flagAnalyzeQuery, flagAnalyzePMIDs)
+		if err != nil {
+			return err
+		}
+
+		report := analyze.Keywords(articles, flagAnalyzeQuery, flagAnalyzeTop)
+		return output.FormatKeywords(cmd.OutOrStdout(), report, outputCfg())
+	},
+}
+
+// analyzeAbbreviationsCmd builds a glossary of the abbreviations a result
+// set defines.
+var analyzeAbbreviationsCmd = &cobra.Command{
+	Use:     "abbreviations",
+	Aliases: []string{"glossary"},
+	Short:   "Glossary of the abbreviations a result set defines",
+	Long: `Fetch the articles matching --query (or listed in --pmids) and collect the
+abbreviations their titles and abstracts define as "long form (SF)", such as
+"Hamilton Depression Rating Scale (HAM-D)". Each expansion is taken from the
+text itself (Schwartz-Hearst matching), never guessed, so the glossary can
+be checked against the listed PMIDs. Abbreviations are ranked by how many
+articles define them; other expansions of the same short form, such as CA
+for both cancer antigen and carbonic anhydrase, are listed as variants.
+Use --csv to export.`,
+	Example: `  pubmed analyze abbreviations --query "depression AND ketamine" --limit 100
+  pubmed analyze glossary --pmids 38000001,38000002 --csv glossary.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagAnalyzeTop < 0 {
+			return errUsage("--top must not be negative")
+		}
+		articles, err := analyzeArticles(cmd, newEutilsClient(), flagAnalyzeQuery, flagAnalyzePMIDs)
+		if err != nil {
+			return err
+		}
+
+		report := analyze.Abbreviations(articles, flagAnalyzeTop)
+		return output.FormatAbbreviations(cmd.OutOrStdout(), report, outputCfg())
+	},
+}
+
+// analyzeAffiliationsCmd reports where the authors of a result set work.
+var analyzeAffiliationsCmd = &cobra.Command{
+	Use:     "affiliations",
+	Aliases: []string{"geography"},
+	Short:   "Countries and institutions of the authors of a result set",
+	Long: `Fetch the articles matching --query (or listed in --pmids) and count the
+articles with an author in each country and at each institution, to show
+where research on a topic is being done. Institutions and countries are
+parsed from the affiliation strings PubMed records: the country from the
+last part ("USA", "P.R. China", or a US state), the institution from the
+part naming a university, hospital, or institute rather than a department.
+Percentages are of the articles that give affiliations; PubMed records them
+for most articles since 2014, and for only the first author before. Use
+--csv to export.`,
+	Example: `  pubmed analyze affiliations --query "fragile x syndrome AND metformin"
+  pubmed analyze affiliations --query "fragile x syndrome" --limit 500 --top 50 --csv places.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagAnalyzeTop < 0 {
+			return errUsage("--top must not be negative")
+		}
+		articles, err := analyzeArticles(cmd, newEutilsClient(), flagAnalyzeQuery, flagAnalyzePMIDs)
+		if err != nil {
+			return err
+		}
+
+		report := analyze.Affiliations(articles, flagAnalyzeTop)
+		return output.FormatAffiliations(cmd.OutOrStdout(), report, outputCfg())
+	},
+}
+
+// analyzeArticles resolves the article set for an analyze or network
+// command from its --query or --pmids value.
+func analyzeArticles(cmd *cobra.Command, client *eutils.Client, query, pmidList string) ([]eutils.Article, error) {
+	if (query == "") == (pmidList == "") {
+		return nil, errUsage("exactly one of --query or --pmids is required")
+	}
+
+	var pmids []string
+	if pmidList != "" {
+		var err error
+		pmids, err = parsePMIDArg(pmidList)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --pmids: %w", err)
+		}
+	} else {
+		limit := defaultAnalyzeLimit
+		if cmd.Flags().Changed("limit") {
+			limit = flagLimit
+		}
+		opts := &eutils.SearchOptions{Limit: limit, Sort: strings.ToLower(flagSort)}
+		var err error
+		if opts.MinDate, opts.MaxDate, err = dateRange(); err != nil {
+			return nil, err
+		}
+		result, err := client.Search(cmd.Context(), buildQuery([]string{query}), opts)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		pmids = result.IDs
+	}
+
+	if len(pmids) == 0 {
+		return nil, nil
+	}
+	articles, err := fetchInBatches(cmd.Context(), client, pmids)
+	if err != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+	return articles, nil
+}
+
+// fetchInBatches fetches pmids fetchBatchSize at a time. PMIDs that cannot
+// be fetched are reported as a warning rather than failing the command,
+// unless nothing could be fetched at all.
+func fetchInBatches(ctx context.Context, client *eutils.Client, pmids []string) ([]eutils.Article, error) {
+	if len(pmids) <= fetchBatchSize {
+		return fetchAllWarn(client.FetchAll(ctx, pmids, fetchBatchSize))
+	}
+	// Larger sets are fetched a batch at a time to report progress, with
+	// the failures of each batch gathered into one report.
+	p := newProgress("Fetching articles", len(pmids))
+	defer p.Done()
+	var articles []eutils.Article
+	report := &eutils.FetchErrors{}
+	for start := 0; start < len(pmids); start += fetchBatchSize {
+		chunk := pmids[start:min(start+fetchBatchSize, len(pmids))]
+		batch, err := client.FetchAll(ctx, chunk, fetchBatchSize)
+		articles = append(articles, batch...)
+		var fe *eutils.FetchErrors
+		switch {
+		case errors.As(err, &fe):
+			report.Invalid = append(report.Invalid, fe.Invalid...)
+			report.Failed = append(report.Failed, fe.Failed...)
+		case err != nil:
+			return articles, err
+		}
+		p.Add(len(chunk))
+	}
+	p.Done()
+	if len(report.Invalid) > 0 || len(report.Failed) > 0 {
+		return fetchAllWarn(articles, report)
+	}
+	return articles, nil
+}
+
+// fetchAllWarn passes on the result of FetchAll, reducing its report of
+// records left out to a warning when some articles were retrieved.
+func fetchAllWarn(articles []eutils.Article, err error) ([]eutils.Article, error) {
+	var fe *eutils.FetchErrors
+	if errors.As(err, &fe) && len(articles) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %v; continuing with %d articles\n", fe, len(articles))
+		return articles, nil
+	}
+	return articles, err
+}
+
+func init() {
+	analyzeCmd.PersistentFlags().StringVar(&flagAnalyzeQuery, "query", "", "PubMed query selecting the articles to analyze")
+	analyzeCmd.PersistentFlags().StringVar(&flagAnalyzePMIDs, "pmids", "", "Comma-separated PMIDs to analyze instead of a query")
+
+	analyzeMeshCmd.Flags().BoolVar(&flagAnalyzeAll, "all", false, "Count all headings, not just major topics")
+	analyzeMeshCmd.Flags().IntVar(&flagAnalyzeTop, "top", 20, "Number of headings to report")
+
+	analyzeKeywordsCmd.Flags().IntVar(&flagAnalyzeTop, "top", 20, "Number of keywords, bigrams, and author keywords to report")
+
+	analyzeAbbreviationsCmd.Flags().IntVar(&flagAnalyzeTop, "top", 0, "Number of abbreviations to report (0 for all)")
+
+	analyzeAffiliationsCmd.Flags().IntVar(&flagAnalyzeTop, "top", 20, "Number of countries and institutions to report (0 for all)")
+
+	analyzeCmd.AddCommand(analyzeMeshCmd)
+	analyzeCmd.AddCommand(analyzeKeywordsCmd)
+	analyzeCmd.AddCommand(analyzeAbbreviationsCmd)
+	analyzeCmd.AddCommand(analyzeAffiliationsCmd)
+}
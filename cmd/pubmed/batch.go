@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/batch"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+var flagBatchFailFast bool
+
+// batchCmd groups the batch job commands.
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run many related searches from a YAML spec",
+}
+
+var batchRunCmd = &cobra.Command{
+	Use:   "run <spec.yaml>",
+	Short: "Run every job in a spec and summarize the results",
+	Long: `Run the searches described in a YAML spec, in order, sharing one rate-limited
+client. Each job has a name and a query, optionally an out file to export its
+articles to (format from the extension, as with --out; relative paths are
+resolved against the spec's directory), and limit, year, type, and sort
+settings. Settings missing from a job come from the spec's defaults, then
+from the command-line flags. Articles are fetched once per run, however many
+jobs return them.
+
+Progress is written to stderr and a summary of every job to stdout (--json
+for a machine-readable one). A failed job is reported and the run continues
+unless --fail-fast is set; the command exits non-zero if any job failed.
+--webhook (or PUBMED_CLI_WEBHOOK) posts the summary to a Slack, Discord, or
+generic JSON webhook when the run finishes.
+
+  defaults:
+    year: 2015-2025
+    limit: 100
+  jobs:
+    - name: fxs-trials
+      query: fragile x syndrome AND metformin
+      type: trial
+      out: fxs-trials.ris
+    - name: fxs-reviews
+      query: fragile x syndrome
+      type: review
+      out: fxs-reviews.csv`,
+	Example: `  pubmed batch run jobs.yaml
+  pubmed batch run jobs.yaml --json > summary.json
+  pubmed batch run jobs.yaml --webhook https://discord.com/api/webhooks/123/abc`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := batch.Load(args[0])
+		if err != nil {
+			return err
+		}
+		flags := batch.Settings{Limit: flagLimit, Year: flagYear, Type: flagType, Sort: flagSort}
+		for i := range spec.Jobs {
+			spec.Jobs[i].Settings = spec.Jobs[i].Settings.Merge(flags)
+		}
+
+		r := &batchRunner{client: newEutilsClient(), articles: make(map[string]eutils.Article)}
+		summary := batch.Summary{Spec: spec.Path(), Started: time.Now()}
+		for i, job := range spec.Jobs {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", i+1, len(spec.Jobs), job.Name)
+			res := r.run(cmd.Context(), job)
+			summary.Add(res)
+			if !res.OK() {
+				fmt.Fprintf(os.Stderr, "  failed: %s\n", res.Error)
+				if flagBatchFailFast {
+					break
+				}
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "  %d results", res.Count)
+			if res.Out != "" {
+				fmt.Fprintf(os.Stderr, ", exported %d to %s", res.Exported, res.Out)
+			}
+			fmt.Fprintln(os.Stderr)
+		}
+		summary.Finished = time.Now()
+		notify(cmd.Context(), webhook.BatchEvent(summary))
+
+		if err := output.FormatBatchSummary(cmd.OutOrStdout(), summary, outputCfg()); err != nil {
+			return err
+		}
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d jobs failed", summary.Failed, len(summary.Jobs))
+		}
+		return nil
+	},
+}
+
+func init() {
+	batchRunCmd.Flags().BoolVar(&flagBatchFailFast, "fail-fast", false, "Stop at the first failed job")
+	batchCmd.AddCommand(batchRunCmd)
+}
+
+// batchRunner runs jobs on one client, keeping the articles it has fetched
+// so jobs with overlapping results do not fetch them again.
+type batchRunner struct {
+	client   *eutils.Client
+	articles map[string]eutils.Article
+}
+
+// run executes one job; failures are reported in the result.
+func (r *batchRunner) run(ctx context.Context, job batch.Job) batch.Result {
+	start := time.Now()
+	res := batch.Result{Name: job.Name, Query: withPubType(job.Query, job.Type), PMIDs: []string{}, Out: job.Out}
+	if err := r.search(ctx, job, &res); err != nil {
+		res.Error = err.Error()
+	}
+	res.Seconds = time.Since(start).Seconds()
+	return res
+}
+
+func (r *batchRunner) search(ctx context.Context, job batch.Job, res *batch.Result) error {
+	opts := &eutils.SearchOptions{Limit: job.Limit, Sort: strings.ToLower(job.Sort)}
+	if opts.Sort != "" {
+		if _, ok := allowedSorts[opts.Sort]; !ok {
+			return fmt.Errorf("sort must be one of: relevance, date, cited")
+		}
+	}
+	if job.Year != "" {
+		minDate, maxDate, err := parseYearRange(job.Year)
+		if err != nil {
+			return fmt.Errorf("year %q is invalid: %w", job.Year, err)
+		}
+		opts.MinDate, opts.MaxDate = minDate, maxDate
+	}
+	var format string
+	if job.Out != "" {
+		f, err := output.OutFormat(job.Out, "")
+		if err != nil {
+			return fmt.Errorf("out: %w", err)
+		}
+		format = f
+	}
+
+	result, err := r.client.Search(ctx, res.Query, opts)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	recordSearch(res.Query, opts, result)
+	res.Count = result.Count
+	if result.IDs != nil {
+		res.PMIDs = result.IDs
+	}
+	if format == "" {
+		return nil
+	}
+
+	articles, err := r.fetch(ctx, result.IDs)
+	if err != nil {
+		return fmt.Errorf("fetch failed: %w", err)
+	}
+	annotateArticles(articles)
+	if err := writeBatchOut(job.Out, format, articles); err != nil {
+		return err
+	}
+	res.Exported = len(articles)
+	return nil
+}
+
+// fetch returns the articles for pmids, in order, fetching only those not
+// already retrieved during the run.
+func (r *batchRunner) fetch(ctx context.Context, pmids []string) ([]eutils.Article, error) {
+	var missing []string
+	for _, id := range pmids {
+		if _, ok := r.articles[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		fetched, err := fetchInBatches(ctx, r.client, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range fetched {
+			r.articles[a.PMID] = a
+		}
+	}
+	articles := make([]eutils.Article, 0, len(pmids))
+	for _, id := range pmids {
+		if a, ok := r.articles[id]; ok {
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+// writeBatchOut exports a job's articles to path in the given --out format.
+func writeBatchOut(path, format string, articles []eutils.Article) error {
+	cfg := output.OutputConfig{}
+	var (
+		w io.Writer = io.Discard
+		f *os.File
+	)
+	switch format {
+	case "csv":
+		cfg.CSVFile = path
+	case "tsv":
+		cfg.TSVFile = path
+	case "xlsx":
+		cfg.XLSXFile = path
+	case "ris":
+		cfg.RISFile = path
+	case "bibtex":
+		cfg.BibTeXFile = path
+	case "csl-json":
+		cfg.CSLFile = path
+	case "json", "ndjson":
+		var err error
+		if f, err = os.Create(path); err != nil {
+			return fmt.Errorf("creating %s: %w", path, err)
+		}
+		w = f
+		cfg.JSON, cfg.NDJSON = format == "json", format == "ndjson"
+	}
+	err := output.FormatArticles(w, articles, cfg)
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/bulk"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBulkQuery  string
+	flagBulkDir    string
+	flagBulkResume bool
+	flagBulkLean   bool
+)
+
+// bulkFetchCmd downloads a large result set to a directory, resumably.
+var bulkFetchCmd = &cobra.Command{
+	Use:   "bulk-fetch",
+	Short: "Download every record matching a query to a directory, resumably",
+	Long: `Download every record matching --query, past PubMed's 10,000-record limit,
+into --dir DIR as NDJSON, for building corpora of 100,000 records or more.
+The query is split into ranges of the date records were added to PubMed, as
+export --all does, and each range is written to its own records-NNNN.ndjson
+file, oldest first.
+
+After every page of 200 records, DIR/checkpoint.json records how far each
+range has got. If the download is interrupted, run the same command with
+--resume to continue (--query may then be left out): completed pages are not
+fetched again, and a page cut off midway is discarded and refetched. A range
+whose record count changed since the download began is fetched again from
+its start.
+
+--lean fetches document summaries instead of full records (no abstracts or
+MeSH terms), a fraction of the size. --year, --since, --until, and --limit
+narrow the download as they do for export.`,
+	Example: `  pubmed bulk-fetch --query "autism" --dir autism/
+  pubmed bulk-fetch --query "autism" --dir autism/ --resume
+  pubmed bulk-fetch --query "neoplasms[mh]" --since 2020 --lean --dir cancer/
+  cat autism/records-*.ndjson | jq -r .pmid`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagBulkDir == "" {
+			return fmt.Errorf("bulk-fetch needs --dir DIR")
+		}
+		client := newEutilsClient()
+		query := ""
+		if flagBulkQuery != "" {
+			query = buildQuery([]string{flagBulkQuery})
+		}
+
+		cp, err := bulk.Load(flagBulkDir)
+		switch {
+		case errors.Is(err, bulk.ErrNoCheckpoint):
+			if query == "" {
+				return fmt.Errorf("bulk-fetch needs --query")
+			}
+			search, ranges, total, err := exportAllRanges(cmd, client, query)
+			if err != nil {
+				return err
+			}
+			cp = bulk.New(flagBulkDir, query, search, flagBulkLean, ranges, total, time.Now())
+			if err := cp.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Downloading %d records in %d date ranges to %s\n", total, len(ranges), flagBulkDir)
+		case err != nil:
+			return err
+		case !flagBulkResume:
+			return fmt.Errorf("%s already holds a download; add --resume to continue it, or choose another --dir", flagBulkDir)
+		case query != "" && query != cp.Query:
+			return fmt.Errorf("%s holds a download of %q, not %q", flagBulkDir, cp.Query, query)
+		case cp.Lean != flagBulkLean:
+			return fmt.Errorf("%s holds a download with --lean=%t; resume it with the same setting", flagBulkDir, cp.Lean)
+		default:
+			fmt.Fprintf(os.Stderr, "Resuming: %d/%d records already in %s\n", cp.Fetched(), cp.Total, flagBulkDir)
+		}
+
+		fetchPage := client.FetchHistory
+		if cp.Lean {
+			fetchPage = client.SummaryHistory
+		}
+		p := newProgress("Downloading records", cp.Total)
+		p.Set(cp.Fetched())
+		for i := range cp.Parts {
+			if cp.Done() {
+				break
+			}
+			if cp.Parts[i].Done() {
+				continue
+			}
+			if err := fetchPart(cmd, client, cp, i, fetchPage, p); err != nil {
+				p.Done()
+				return fmt.Errorf("%w (run again with --resume to continue)", err)
+			}
+		}
+		p.Done()
+		fmt.Fprintf(os.Stderr, "Downloaded %d records to %s\n", cp.Fetched(), flagBulkDir)
+		if cp.Fetched() == 0 {
+			return errNoResults("nothing to download")
+		}
+		return nil
+	},
+}
+
+// fetchPart downloads the rest of part i of a bulk download page by page,
+// saving the checkpoint after each page and reporting it to pr.
+func fetchPart(cmd *cobra.Command, client *eutils.Client, cp *bulk.Checkpoint, i int, fetchPage historyPage, pr *progress.Reporter) error {
+	ctx := cmd.Context()
+	opts := cp.Parts[i].Range().Options()
+	opts.Limit = 1
+	result, err := client.Search(ctx, cp.Search, opts)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if p := cp.Parts[i]; result.Count != p.Count {
+		if p.Fetched > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %s now has %d records, not %d; fetching it again\n", p.File, result.Count, p.Count)
+		}
+		cp.Restart(i, result.Count)
+	}
+
+	f, err := cp.Open(i)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for !cp.Parts[i].Done() && !cp.Done() {
+		p := cp.Parts[i]
+		n := min(fetchBatchSize, p.Count-p.Fetched, cp.Total-cp.Fetched())
+		batch, err := fetchPage(ctx, result.WebEnv, result.QueryKey, p.Fetched, n)
+		if err != nil {
+			return fmt.Errorf("fetch failed at record %d: %w", cp.Fetched()+1, err)
+		}
+		if len(batch) == 0 {
+			return fmt.Errorf("fetch failed at record %d: PubMed returned no records", cp.Fetched()+1)
+		}
+		stream, err := output.NewArticleStream(f, output.OutputConfig{NDJSON: true})
+		if err != nil {
+			return err
+		}
+		if err := stream.Write(batch); err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			return err
+		}
+		size, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if err := cp.Advance(i, len(batch), size, time.Now()); err != nil {
+			return err
+		}
+		pr.Set(cp.Fetched())
+	}
+	return nil
+}
+
+func init() {
+	bulkFetchCmd.Flags().StringVar(&flagBulkQuery, "query", "", "PubMed query selecting the records to download")
+	bulkFetchCmd.Flags().StringVar(&flagBulkDir, "dir", "", "Directory for the record files and checkpoint")
+	bulkFetchCmd.Flags().BoolVar(&flagBulkResume, "resume", false, "Continue an interrupted download in --dir")
+	bulkFetchCmd.Flags().BoolVar(&flagBulkLean, "lean", false, "Fetch document summaries (no abstracts or MeSH) to keep the download small")
+	bulkFetchCmd.MarkFlagDirname("dir")
+}
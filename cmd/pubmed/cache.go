@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// responsesSection is the cache directory holding cached NCBI responses.
+const responsesSection = "responses"
+
+var flagCacheOlderThan string
+
+// cacheCmd groups the cache management commands.
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the on-disk cache",
+	Long: `Inspect and manage pubmed-cli's on-disk cache: cached NCBI and API
+responses ("responses"), which --offline replays, and the offline MeSH
+database ("mesh"). The cache lives in the platform cache directory
+(~/.cache/pubmed-cli on Linux); override it with PUBMED_CLI_CACHE_DIR.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size, entries per endpoint, and hit rates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := cache.Dir()
+		if err != nil {
+			return err
+		}
+		report := cache.Report{Dir: root}
+		if report.Sections, err = cache.Sections(root); err != nil {
+			return err
+		}
+		if report.Endpoints, err = cache.NewStore(filepath.Join(root, responsesSection), 0).Stats(); err != nil {
+			return err
+		}
+		return output.FormatCacheReport(cmd.OutOrStdout(), report, outputCfg())
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [section...]",
+	Short: "Delete the cache, or only the named sections (responses, mesh)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := cache.Dir()
+		if err != nil {
+			return err
+		}
+		removed, err := cache.Clear(root, args...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed %d files (%s)\n", removed.Entries, output.FormatBytes(removed.Bytes))
+		return nil
+	},
+	ValidArgsFunction: cobra.FixedCompletions([]string{responsesSection, "mesh"}, cobra.ShellCompDirectiveNoFileComp),
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune --older-than <age>",
+	Short: "Delete cached responses older than an age such as 30d",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		age, err := cache.ParseAge(flagCacheOlderThan)
+		if err != nil {
+			return err
+		}
+		root, err := cache.Dir()
+		if err != nil {
+			return err
+		}
+		removed, err := cache.NewStore(filepath.Join(root, responsesSection), 0).Prune(age)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Pruned %d cached responses (%s)\n", removed.Entries, output.FormatBytes(removed.Bytes))
+		return nil
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().StringVar(&flagCacheOlderThan, "older-than", "", "Remove entries last written before this age (e.g. 30d, 2w, 12h)")
+	cachePruneCmd.MarkFlagRequired("older-than")
+
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd, cachePruneCmd)
+}
+
+// openStores are the response caches used by this run; flushCaches
+// persists their hit and miss counts when the command finishes.
+var openStores []*cache.Store
+
+func flushCaches() {
+	for _, s := range openStores {
+		if err := s.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: saving cache statistics: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refcheck"
+	"github.com/spf13/cobra"
+)
+
+var flagCiteStyle string
+
+// doiArgRe recognizes a DOI argument, bare or as a doi: or doi.org reference.
+var doiArgRe = regexp.MustCompile(`(?i)^(doi:\s*|https?://(dx\.)?doi\.org/)?10\.\d{4,}/\S+$`)
+
+// citeCmd prints formatted citations for PMIDs or DOIs.
+var citeCmd = &cobra.Command{
+	Use:   "cite <pmid|doi> [pmid|doi...]",
+	Short: "Print formatted citations for PMIDs or DOIs",
+	Long: `Fetch each article and print its citation in the chosen --style: apa
+(default), vancouver, ama, bibtex, or ris. Arguments may be PMIDs or DOIs,
+space- or comma-separated; DOIs are resolved through PubMed. Give - to read
+them from stdin. Add --clip to copy the result.`,
+	Example: `  pubmed cite 38000001
+  pubmed cite 10.1186/s11689-024-00001-1 --style vancouver --clip
+  pubmed search "fragile x" --limit 5 --ids-only | pubmed cite -`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := stdinArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		client := newEutilsClient()
+		pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+		if err != nil {
+			return err
+		}
+
+		articles, err := client.Fetch(cmd.Context(), pmids)
+		if err != nil {
+			return fmt.Errorf("fetch failed: %w", err)
+		}
+		articles = output.NormalizeArticles(orderArticles(articles, pmids), flagASCII)
+		return output.FormatCitations(cmd.OutOrStdout(), articles, flagCiteStyle)
+	},
+}
+
+func init() {
+	citeCmd.Flags().StringVar(&flagCiteStyle, "style", "apa", "Citation style: "+strings.Join(output.CitationStyles, ", "))
+	citeCmd.RegisterFlagCompletionFunc("style", cobra.FixedCompletions(output.CitationStyles, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// resolveIDArgs turns PMID and DOI arguments into PMIDs, in argument order.
+// A DOI that is not in PubMed is an error.
+func resolveIDArgs(ctx context.Context, client *eutils.Client, args []string) ([]string, error) {
+	pmids, unmatched, err := resolveIDs(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(unmatched) > 0 {
+		return nil, fmt.Errorf("DOI %s was not found in PubMed", unmatched[0])
+	}
+	if len(pmids) == 0 {
+		return nil, errUsage("at least one PMID or DOI is required")
+	}
+	return pmids, nil
+}
+
+// resolveIDs turns PMID and DOI arguments into PMIDs, in argument order,
+// and returns the normalized DOIs PubMed does not have separately.
+func resolveIDs(ctx context.Context, client *eutils.Client, args []string) (pmids, unmatched []string, err error) {
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if !doiArgRe.MatchString(part) {
+				if err := validatePMID(part); err != nil {
+					return nil, nil, errUsage("%q is neither a PMID nor a DOI", part)
+				}
+				pmids = append(pmids, part)
+				continue
+			}
+
+			doi := refcheck.NormalizeDOI(strings.TrimSpace(strings.TrimPrefix(strings.ToLower(part), "doi:")))
+			result, err := client.Search(ctx, fmt.Sprintf(`"%s"[doi]`, doi), &eutils.SearchOptions{Limit: 1})
+			if err != nil {
+				return nil, nil, fmt.Errorf("resolving DOI %s: %w", doi, err)
+			}
+			if len(result.IDs) == 0 {
+				unmatched = append(unmatched, doi)
+				continue
+			}
+			pmids = append(pmids, result.IDs[0])
+		}
+	}
+	return pmids, unmatched, nil
+}
+
+// orderArticles returns articles in the order of pmids; efetch does not
+// guarantee it. PMIDs that were not returned are skipped.
+func orderArticles(articles []eutils.Article, pmids []string) []eutils.Article {
+	byPMID := make(map[string]eutils.Article, len(articles))
+	for _, a := range articles {
+		byPMID[a.PMID] = a
+	}
+	ordered := make([]eutils.Article, 0, len(pmids))
+	for _, id := range pmids {
+		if a, ok := byPMID[id]; ok {
+			ordered = append(ordered, a)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/compare"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// maxCompare caps the articles one comparison table holds.
+const maxCompare = 5
+
+// compareCmd implements the compare subcommand.
+var compareCmd = &cobra.Command{
+	Use:   "compare <pmid> <pmid> [pmid...]",
+	Short: "Compare articles side by side: design, population, N, outcomes, findings",
+	Long: `Fetch two or more articles (up to 5) and compare their study design,
+population, sample size, outcomes, and main finding in a Markdown table
+(--human for a terminal view, --json, or --csv/--tsv/.xlsx).
+
+Fields are extracted from PubMed indexing (publication types and MeSH check
+tags) and the abstract's structured sections or cue sentences, so they are a
+starting point: verify them against the full text.`,
+	Example: `  pubmed compare 38000001 38000002
+  pubmed compare 38000001 38000002 --human
+  pubmed compare 38000001,38000002,38000003 --out comparison.xlsx`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := stdinArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		client := newEutilsClient()
+		pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+		if err != nil {
+			return err
+		}
+		if len(pmids) < 2 || len(pmids) > maxCompare {
+			return fmt.Errorf("compare takes 2 to %d PMIDs, got %d", maxCompare, len(pmids))
+		}
+		articles, err := client.Fetch(cmd.Context(), pmids)
+		if err != nil {
+			return fmt.Errorf("fetch failed: %w", err)
+		}
+		articles = orderArticles(articles, pmids)
+		if missing := len(pmids) - len(articles); missing > 0 {
+			return fmt.Errorf("%d of the PMIDs were not found in PubMed", missing)
+		}
+		return output.FormatComparison(cmd.OutOrStdout(), compare.Profiles(articles), outputCfg())
+	},
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/spf13/cobra"
+)
+
+// completeQueryTerms completes PubMed query syntax: a word ending in an open
+// field tag ("autism[ti") gets the matching tags, and a word starting with a
+// quote ("fragile) gets MeSH descriptors as "Heading"[mh] clauses.
+func completeQueryTerms(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if i := strings.LastIndex(toComplete, "["); i >= 0 && !strings.Contains(toComplete[i:], "]") {
+		typed := strings.ToLower(toComplete[i+1:])
+		var out []string
+		for _, t := range query.FieldTags {
+			if strings.HasPrefix(t.Tag, typed) {
+				out = append(out, toComplete[:i]+"["+t.Tag+"]\t"+t.Name)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	prefix, ok := strings.CutPrefix(toComplete, `"`)
+	if !ok || len(strings.TrimSpace(prefix)) < 3 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	suggestions, err := newMeshClient().Suggest(cmd.Context(), prefix, mesh.DefaultSuggestLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+	var out []string
+	for _, s := range suggestions {
+		out = append(out, query.Quote(s.Name)+"[mh]\t"+s.UI)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeQueryAfter completes query syntax only from argument n on, for
+// commands whose first arguments are not part of the query.
+func completeQueryAfter(n int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) < n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeQueryTerms(cmd, args, toComplete)
+	}
+}
+
+// completeAlertNames offers the saved alerts not already on the command line.
+func completeAlertNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	book, err := loadAlerts()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+	given := make(map[string]bool, len(args))
+	for _, a := range args {
+		given[a] = true
+	}
+	var out []string
+	for _, a := range book.Alerts {
+		if !given[a.Name] {
+			out = append(out, a.Name+"\t"+a.Query)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCollectionNames offers library collections for the first argument.
+func completeCollectionNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	lib, err := loadLibrary()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+	var out []string
+	for _, c := range lib.Collections {
+		out = append(out, fmt.Sprintf("%s\t%d articles", c.Name, len(c.PMIDs)))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeHistoryIDs offers recorded search numbers, newest first.
+func completeHistoryIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entries, err := loadHistory()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+	var out []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		out = append(out, strconv.Itoa(entries[i].ID)+"\t"+entries[i].Query)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeColumns completes the last entry of a comma-separated --columns list.
+func completeColumns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	done := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		done = toComplete[:i+1]
+	}
+	used := make(map[string]bool)
+	for _, c := range strings.Split(done, ",") {
+		used[c] = true
+	}
+	var out []string
+	for _, name := range output.ArticleColumnNames() {
+		if !used[name] {
+			out = append(out, done+name)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completePublicationTypes offers the --type shorthands with their [pt] clauses.
+func completePublicationTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	out := make([]string, 0, len(publicationTypes))
+	for name, clause := range publicationTypes {
+		out = append(out, name+"\t"+clause)
+	}
+	sort.Strings(out)
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
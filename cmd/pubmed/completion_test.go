@@ -0,0 +1,58 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteQueryTerms_FieldTags(t *testing.T) {
+	got, dir := completeQueryTerms(searchCmd, nil, "autism[ti")
+	if dir != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v", dir)
+	}
+	want := []string{"autism[tiab]\tTitle/Abstract", "autism[ti]\tTitle"}
+	for _, w := range want {
+		if !slices.Contains(got, w) {
+			t.Errorf("completions %q missing %q", got, w)
+		}
+	}
+	for _, c := range got {
+		if !strings.HasPrefix(c, "autism[ti") {
+			t.Errorf("unexpected completion %q", c)
+		}
+	}
+}
+
+func TestCompleteQueryTerms_PlainWord(t *testing.T) {
+	got, _ := completeQueryTerms(searchCmd, nil, "autism")
+	if len(got) != 0 {
+		t.Errorf("plain word should not complete, got %q", got)
+	}
+	got, _ = completeQueryTerms(searchCmd, nil, "autism[ti]")
+	if len(got) != 0 {
+		t.Errorf("closed tag should not complete, got %q", got)
+	}
+}
+
+func TestCompleteColumns(t *testing.T) {
+	got, dir := completeColumns(searchCmd, nil, "pmid,")
+	if dir&cobra.ShellCompDirectiveNoSpace == 0 {
+		t.Error("expected NoSpace directive")
+	}
+	if slices.Contains(got, "pmid,pmid") {
+		t.Error("already chosen column offered again")
+	}
+	if !slices.Contains(got, "pmid,title") {
+		t.Errorf("completions %q missing pmid,title", got)
+	}
+}
+
+func TestCompletePublicationTypes(t *testing.T) {
+	got, _ := completePublicationTypes(searchCmd, nil, "")
+	if len(got) != len(publicationTypes) || !slices.IsSorted(got) {
+		t.Errorf("completions = %q", got)
+	}
+}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/config"
+	"github.com/henrybloomingdale/pubmed-cli/internal/crossref"
+	"github.com/henrybloomingdale/pubmed-cli/internal/email"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/hook"
+	"github.com/henrybloomingdale/pubmed-cli/internal/keychain"
+	"github.com/henrybloomingdale/pubmed-cli/internal/openalex"
+	"github.com/henrybloomingdale/pubmed-cli/internal/semanticscholar"
+	"github.com/henrybloomingdale/pubmed-cli/internal/webhook"
+	"github.com/henrybloomingdale/pubmed-cli/internal/zotero"
+	"github.com/spf13/cobra"
+)
+
+// configKey is a setting the config file can hold: the environment
+// variable that overrides it and, for output defaults, the flag. Secret
+// settings can be kept in the system keychain instead, under Account if it
+// is set or else Name; they need an Env, which secretEnv fills in from the
+// keychain when a command reads it.
+type configKey struct {
+	Name    string
+	Env     string
+	Flag    string
+	Secret  bool
+	Account string
+	Help    string
+}
+
+// keychainRef is the config file value of a setting stored in the keychain
+// by 'pubmed config set-secret'.
+const keychainRef = "@keychain"
+
+func (k configKey) account() string {
+	if k.Account != "" {
+		return k.Account
+	}
+	return k.Name
+}
+
+// configKeys lists every setting, in the order config list shows them.
+var configKeys = []configKey{
+	{Name: "api_key", Env: "NCBI_API_KEY", Flag: "api-key", Secret: true, Help: "NCBI API key"},
+	{Name: "cache_dir", Env: cache.EnvDir, Help: "Response cache directory"},
+	{Name: "no_history", Env: history.EnvDisable, Help: "Set to 1 to stop recording search history"},
+	{Name: "theme", Env: "PUBMED_CLI_THEME", Flag: "theme", Help: "Default --human theme: dark or light"},
+	{Name: "record", Flag: "record", Help: "Set to true to record responses for --offline runs"},
+	{Name: "lang", Flag: "lang", Help: "Default --lang for --human output"},
+	{Name: "limit", Flag: "limit", Help: "Default --limit"},
+	{Name: "sort", Flag: "sort", Help: "Default --sort"},
+	{Name: "columns", Flag: "columns", Help: "Default --columns for CSV and TSV exports"},
+	{Name: "ascii", Flag: "ascii", Help: "Set to true to transliterate output to ASCII"},
+	{Name: "unpaywall_email", Env: fulltext.EnvUnpaywallEmail, Help: "Email for Unpaywall (--oa)"},
+	{Name: "crossref_mailto", Env: crossref.EnvMailto, Help: "Email for Crossref's polite pool"},
+	{Name: "openalex_mailto", Env: openalex.EnvMailto, Help: "Email for OpenAlex's polite pool"},
+	{Name: "s2_api_key", Env: semanticscholar.EnvAPIKey, Secret: true, Help: "Semantic Scholar API key"},
+	{Name: "zotero_api_key", Env: zotero.EnvAPIKey, Secret: true, Account: zotero.KeychainAccount, Help: "Zotero API key"},
+	{Name: "resolver", Env: fulltext.EnvResolver, Help: "Link resolver URL template for pdf"},
+	{Name: "output_hook", Env: hook.EnvOutput, Help: "Command the printed results are piped through"},
+	{Name: "export_hook", Env: hook.EnvExport, Help: "Command run on each export file ($PUBMED_CLI_FILE)"},
+	{Name: "webhook", Env: webhook.EnvURL, Secret: true, Help: "Webhook URL for alert and batch runs"},
+	{Name: "smtp_host", Env: email.EnvHost, Help: "SMTP server for alert --email"},
+	{Name: "smtp_port", Env: email.EnvPort, Help: "SMTP port"},
+	{Name: "smtp_user", Env: email.EnvUsername, Help: "SMTP user name"},
+	{Name: "smtp_password", Env: email.EnvPassword, Secret: true, Help: "SMTP password"},
+	{Name: "smtp_from", Env: email.EnvFrom, Help: "Sender address for alert --email"},
+}
+
+// findConfigKey returns the named setting.
+func findConfigKey(name string) (configKey, error) {
+	for _, k := range configKeys {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	names := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		names[i] = k.Name
+	}
+	return configKey{}, errUsage("unknown config key %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// applyConfig fills settings the environment and flags leave unset from
+// the config file, so the rest of the CLI reads one layered value through
+// its usual environment variable or flag.
+func applyConfig(cmd *cobra.Command) error {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	f, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	for _, name := range f.Keys() {
+		k, err := findConfigKey(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", path, err)
+			continue
+		}
+		value, _ := f.Get(name)
+		if k.Env != "" && os.Getenv(k.Env) != "" {
+			continue
+		}
+		if value == keychainRef {
+			// Read when needed, so commands that never use the secret do
+			// not touch a keychain that may be locked.
+			if k.Env != "" {
+				keychainSecrets[k.Env] = pendingSecret{ctx: cmd.Context(), key: k}
+			}
+			continue
+		}
+		if k.Env != "" {
+			os.Setenv(k.Env, value)
+			continue
+		}
+		if fl := cmd.Flags().Lookup(k.Flag); fl != nil && !fl.Changed && flagDefaultApplies(cmd, k, value) {
+			// Set through the value, not the flag set, so the saved setting
+			// becomes the default rather than a flag the user gave: commands
+			// that treat an explicit --limit as a cap still see none.
+			if err := fl.Value.Set(value); err != nil {
+				return fmt.Errorf("%s: %s: %w", path, name, err)
+			}
+			fl.DefValue = value
+			configDefaults[k.Flag] = true
+		}
+	}
+	return nil
+}
+
+// pendingSecret is a setting the config file keeps in the keychain, not
+// yet read.
+type pendingSecret struct {
+	ctx context.Context
+	key configKey
+}
+
+// keychainSecrets holds, by environment variable, the keychain settings
+// applyConfig deferred.
+var keychainSecrets = make(map[string]pendingSecret)
+
+// secretEnv returns the environment variable env, reading it from the
+// keychain first if the config file keeps it there and the environment
+// does not set it. A keychain that cannot be read is a warning, and the
+// setting is then empty.
+func secretEnv(env string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	p, ok := keychainSecrets[env]
+	if !ok {
+		return ""
+	}
+	delete(keychainSecrets, env)
+	v, err := keychainValue(p.ctx, p.key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", p.key.Name, err)
+		return ""
+	}
+	os.Setenv(env, v)
+	return v
+}
+
+// configDefaults holds the flags whose defaults applyConfig took from the
+// config file.
+var configDefaults = make(map[string]bool)
+
+// flagDefaultApplies reports whether a saved default for k's flag suits
+// cmd, so a setting only some commands accept does not make the others
+// fail: --columns shapes the table exports of search, fetch, export, and
+// dedupe, and the external --sort orders apply to search alone.
+func flagDefaultApplies(cmd *cobra.Command, k configKey, value string) bool {
+	group := commandGroup(cmd)
+	switch k.Flag {
+	case "columns":
+		switch group {
+		case "search", "fetch", "export", "dedupe":
+			return true
+		}
+		return false
+	case "sort":
+		if _, ok := externalSorts[strings.ToLower(value)]; ok {
+			return group == "search"
+		}
+	}
+	return true
+}
+
+// keychainValue reads a setting stored by 'pubmed config set-secret'.
+func keychainValue(ctx context.Context, k configKey) (string, error) {
+	value, err := keychain.Get(ctx, k.account())
+	if errors.Is(err, keychain.ErrNotFound) {
+		return "", fmt.Errorf("not in the keychain; run 'pubmed config set-secret %s'", k.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading the keychain: %w", err)
+	}
+	return value, nil
+}
+
+// configValue resolves name through the layers: flag, environment, then
+// file or keychain. source names the layer the value came from.
+func configValue(cmd *cobra.Command, k configKey, f *config.File) (value, source string) {
+	if k.Flag != "" {
+		if fl := cmd.Flags().Lookup(k.Flag); fl != nil && fl.Changed {
+			return fl.Value.String(), "flag"
+		}
+	}
+	if k.Env != "" {
+		if v := os.Getenv(k.Env); v != "" {
+			return v, "env " + k.Env
+		}
+	}
+	if v, ok := f.Get(k.Name); ok {
+		if v != keychainRef {
+			return v, "file"
+		}
+		if v, err := keychainValue(cmd.Context(), k); err == nil {
+			return v, "keychain"
+		}
+		return "", "keychain (missing)"
+	}
+	return "", ""
+}
+
+// loadConfigFile opens the settings file for the config commands.
+func loadConfigFile() (*config.File, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+// configCmd groups the settings commands.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get and set defaults in the config file",
+	Long: `Manage the config file (~/.config/pubmed-cli/config.json on Linux; override
+the directory with PUBMED_CLI_CONFIG_DIR), the bottom layer of pubmed-cli's
+settings: an environment variable overrides the file, and a flag overrides
+both. The file holds API keys, contact emails, the cache directory, and
+output defaults such as limit, sort, and columns. It is readable only by its
+owner; 'pubmed config list' masks secrets.
+
+API keys and passwords can live in the system keychain instead (macOS
+Keychain, or the Secret Service via secret-tool on Linux): 'pubmed config
+set-secret' reads one from stdin, so it never appears in the file or in
+shell history, and the file records only that the keychain holds it. An
+environment variable still overrides a keychain secret.`,
+	Example: `  pubmed config set-secret api_key < ncbi.key
+  pubmed config set limit 50
+  pubmed config get api_key
+  pubmed config list`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Long: `Print the value a setting takes after layering, from the environment or
+the config file, or nothing if it is unset.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, err := findConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if value, _ := configValue(cmd, k, f); value != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+		}
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:               "set <key> <value>",
+	Short:             "Store a setting in the config file",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, err := findConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		if err := checkConfigValue(k, args[1]); err != nil {
+			return err
+		}
+		if args[1] == keychainRef {
+			return errUsage("use 'pubmed config set-secret %s' to store a secret in the keychain", k.Name)
+		}
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		f.Set(k.Name, args[1])
+		if err := f.Save(); err != nil {
+			return err
+		}
+		if k.Env != "" && os.Getenv(k.Env) != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s is set and overrides the config file\n", k.Env)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Set %s in %s\n", k.Name, f.Path())
+		return nil
+	},
+}
+
+var configSetSecretCmd = &cobra.Command{
+	Use:   "set-secret <key>",
+	Short: "Store a secret read from stdin in the system keychain",
+	Long: `Store an API key or password in the system keychain (macOS Keychain, or
+the Secret Service via secret-tool on Linux) rather than in the config file.
+The secret is read from stdin so it stays out of shell history; the config
+file records only that the keychain holds it. Remove it with 'pubmed config
+unset'.`,
+	Example: `  pubmed config set-secret api_key < ncbi.key
+  pbpaste | pubmed config set-secret s2_api_key`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSecretKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, err := findConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		if !k.Secret {
+			return fmt.Errorf("%s is not a secret; use 'pubmed config set %s <value>'", k.Name, k.Name)
+		}
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", k.Name, err)
+		}
+		secret := strings.TrimSpace(string(data))
+		if secret == "" {
+			return fmt.Errorf("no %s on stdin", k.Name)
+		}
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if err := keychain.Set(cmd.Context(), k.account(), secret); err != nil {
+			return err
+		}
+		f.Set(k.Name, keychainRef)
+		if err := f.Save(); err != nil {
+			return err
+		}
+		if k.Env != "" && os.Getenv(k.Env) != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s is set and overrides the keychain\n", k.Env)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved %s in the keychain\n", k.Name)
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:               "unset <key>",
+	Short:             "Remove a setting from the config file or keychain",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, err := findConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		value, _ := f.Get(k.Name)
+		if !f.Unset(k.Name) {
+			return fmt.Errorf("%s is not set in %s", k.Name, f.Path())
+		}
+		if value == keychainRef {
+			if err := keychain.Delete(cmd.Context(), k.account()); err != nil {
+				return err
+			}
+		}
+		if err := f.Save(); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Unset %s\n", k.Name)
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every setting with its value and source",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tDESCRIPTION")
+		for _, k := range configKeys {
+			value, source := configValue(cmd, k, f)
+			if k.Secret && value != "" {
+				value = maskSecret(value)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", k.Name, value, source, k.Help)
+		}
+		return tw.Flush()
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the config file location",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.DefaultPath()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+// checkConfigValue rejects a value the setting's flag would reject on
+// every later run.
+func checkConfigValue(k configKey, value string) error {
+	if k.Flag == "" {
+		return nil
+	}
+	var err error
+	switch rootCmd.PersistentFlags().Lookup(k.Flag).Value.Type() {
+	case "int":
+		var n int
+		if n, err = strconv.Atoi(value); err == nil && n <= 0 {
+			err = fmt.Errorf("must be greater than 0")
+		}
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return errUsage("invalid %s %q: %w", k.Name, value, err)
+	}
+	return nil
+}
+
+// maskSecret keeps the last four characters of a secret.
+func maskSecret(s string) string {
+	if len(s) <= 4 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// completeConfigKeys completes the key argument of the config commands.
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeKeys(args, toComplete, false)
+}
+
+// completeSecretKeys completes the key argument of config set-secret.
+func completeSecretKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeKeys(args, toComplete, true)
+}
+
+func completeKeys(args []string, toComplete string, secrets bool) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, k := range configKeys {
+		if (!secrets || k.Secret) && strings.HasPrefix(k.Name, toComplete) {
+			names = append(names, k.Name+"\t"+k.Help)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd, configSetCmd, configSetSecretCmd, configUnsetCmd, configListCmd, configPathCmd)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/crossref"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func newCrossrefClient() *crossref.Client {
+	return crossref.NewClient(newBaseClient(), os.Getenv(crossref.EnvMailto))
+}
+
+// enrichFromCrossref fills gaps in articles from Crossref and appends a
+// Crossref record for each DOI PubMed does not have. Lookup failures are
+// warnings: the PubMed records are still complete enough to print.
+func enrichFromCrossref(ctx context.Context, articles []eutils.Article, dois []string) []eutils.Article {
+	client := newCrossrefClient()
+	for i := range articles {
+		if err := client.Fill(ctx, &articles[i]); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: PMID %s: %v\n", articles[i].PMID, err)
+		}
+	}
+	for _, doi := range dois {
+		w, err := client.Work(ctx, doi)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		case w == nil:
+			fmt.Fprintf(os.Stderr, "Warning: DOI %s was found in neither PubMed nor Crossref\n", doi)
+		default:
+			articles = append(articles, w.Article())
+		}
+	}
+	return articles
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// earliestDate and the current date fill the open end of a --since or
+// --until range, since E-utilities needs both bounds.
+const earliestDate = "1800/01/01"
+
+var (
+	relativeDateRe = regexp.MustCompile(`^(\d+)\s*([dwmy])$`)
+	absoluteDateRe = regexp.MustCompile(`^(\d{4})(?:[/-](\d{1,2})(?:[/-](\d{1,2}))?)?$`)
+)
+
+// parseDateFlag converts a --since or --until value to an E-utilities
+// date: today or yesterday; a span back from now, such as 30d, 6w, 6m, or
+// 2y; or a date, YYYY, YYYY/MM, or YYYY/MM/DD (with slashes or dashes). A
+// partial date is passed on as is, so PubMed matches the whole month or
+// year.
+func parseDateFlag(value string, now time.Time) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch v {
+	case "today", "now":
+		return now.Format("2006/01/02"), nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1).Format("2006/01/02"), nil
+	}
+	if m := relativeDateRe.FindStringSubmatch(v); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		var t time.Time
+		switch m[2] {
+		case "d":
+			t = now.AddDate(0, 0, -n)
+		case "w":
+			t = now.AddDate(0, 0, -7*n)
+		case "m":
+			t = monthsBefore(now, n)
+		case "y":
+			t = monthsBefore(now, 12*n)
+		}
+		return t.Format("2006/01/02"), nil
+	}
+	m := absoluteDateRe.FindStringSubmatch(v)
+	if m == nil {
+		return "", errUsage("use today, yesterday, a span such as 30d, 6w, 6m, or 2y, or a date YYYY[/MM[/DD]]")
+	}
+	date, layout := m[1], "2006"
+	if m[2] != "" {
+		date += "/" + fmt.Sprintf("%02s", m[2])
+		layout += "/01"
+	}
+	if m[3] != "" {
+		date += "/" + fmt.Sprintf("%02s", m[3])
+		layout += "/02"
+	}
+	if _, err := time.Parse(layout, date); err != nil {
+		return "", errUsage("%s is not a valid date", value)
+	}
+	return date, nil
+}
+
+// monthsBefore returns the date n months before t, clamped to the end of
+// a shorter month: 1m before March 31 is February 28 (or 29), where
+// time.AddDate would roll over into March.
+func monthsBefore(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m-time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// sinceUntil parses --since and --until, returning "" for either that is
+// not set.
+func sinceUntil(now time.Time) (since, until string, err error) {
+	if flagSince != "" {
+		if since, err = parseDateFlag(flagSince, now); err != nil {
+			return "", "", fmt.Errorf("invalid --since value %q: %w", flagSince, err)
+		}
+	}
+	if flagUntil != "" {
+		if until, err = parseDateFlag(flagUntil, now); err != nil {
+			return "", "", fmt.Errorf("invalid --until value %q: %w", flagUntil, err)
+		}
+	}
+	// A partial --until date covers its whole month or year, so compare
+	// it by its prefix.
+	if since != "" && until != "" && since > until && !strings.HasPrefix(since, until) {
+		return "", "", errUsage("--since %s is after --until %s", flagSince, flagUntil)
+	}
+	return since, until, nil
+}
+
+// dateRange returns the publication-date bounds set by --year, or by
+// --since and --until, for a search's mindate and maxdate; both are empty
+// when none of the flags is set.
+func dateRange() (minDate, maxDate string, err error) {
+	if flagYear != "" {
+		minDate, maxDate, err = parseYearRange(flagYear)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid --year value %q: %w", flagYear, err)
+		}
+		return minDate, maxDate, nil
+	}
+	now := time.Now()
+	since, until, err := sinceUntil(now)
+	if err != nil || (since == "" && until == "") {
+		return "", "", err
+	}
+	if since == "" {
+		since = earliestDate
+	}
+	if until == "" {
+		until = now.Format("2006/01/02")
+	}
+	return since, until, nil
+}
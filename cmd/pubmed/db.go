@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/backup"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+	"github.com/henrybloomingdale/pubmed-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagDBForce bool
+
+// dbCmd groups the user-data backup commands.
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Back up and restore the library, alerts, and search history",
+	Long: `pubmed-cli keeps its user data in one SQLite database, pubmed.db under the
+config directory (~/.config/pubmed-cli on Linux; override with
+PUBMED_CLI_CONFIG_DIR): library collections with their stored articles,
+notes, and tags; saved alerts with the PMIDs they have reported; and the
+search history. The JSON files earlier releases kept there (library.json,
+alerts.json, history.jsonl) are imported the first time the database is
+opened and renamed with an .imported suffix.
+
+db export bundles everything into one JSON file and db import restores it,
+e.g. onto another machine.`,
+}
+
+var dbExportCmd = &cobra.Command{
+	Use:     "export <file>",
+	Short:   "Write a backup of all user data",
+	Example: `  pubmed db export ~/pubmed-backup.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openUserDB(true)
+		if err != nil {
+			return err
+		}
+		a, err := backup.Export(db, time.Now())
+		if err != nil {
+			return err
+		}
+		c, err := a.Counts()
+		if err != nil {
+			return err
+		}
+		if err := a.Write(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Backed up %s to %s\n", backupSummary(c), args[0])
+		return nil
+	},
+}
+
+var dbImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Restore user data from a backup",
+	Long: `Replace the library, alerts, and search history with the contents of a file
+written by 'pubmed db export'. Stores the backup does not hold are left
+alone. Existing data is only replaced with --force.`,
+	Example: `  pubmed db import ~/pubmed-backup.json
+  pubmed db import ~/pubmed-backup.json --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := backup.Read(args[0])
+		if err != nil {
+			return err
+		}
+		db, err := openUserDB(true)
+		if err != nil {
+			return err
+		}
+		held, err := db.Holding()
+		if err != nil {
+			return err
+		}
+		if len(held) > 0 && !flagDBForce {
+			return fmt.Errorf("would replace the %s in %s; rerun with --force (or back them up first with 'pubmed db export')", strings.Join(held, ", "), db.Path())
+		}
+		if err := backup.Import(db, a); err != nil {
+			return err
+		}
+		c, _ := a.Counts()
+		fmt.Fprintf(cmd.OutOrStdout(), "Restored %s from %s\n", backupSummary(c), args[0])
+		return nil
+	},
+}
+
+func init() {
+	dbImportCmd.Flags().BoolVar(&flagDBForce, "force", false, "Replace existing data")
+	dbCmd.AddCommand(dbExportCmd, dbImportCmd)
+}
+
+func backupSummary(c backup.Counts) string {
+	return fmt.Sprintf("%d collections (%d articles), %d alerts, and %d searches", c.Collections, c.Articles, c.Alerts, c.Searches)
+}
+
+// userDB is the open user database, opened on first use and closed by
+// closeUserDB when the command finishes.
+var userDB *store.DB
+
+// openUserDB opens the user database. Without create, a config directory
+// holding neither the database nor the JSON files it replaces yields nil,
+// so commands that only read user data never create one.
+func openUserDB(create bool) (*store.DB, error) {
+	path, err := store.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	if userDB != nil && userDB.Path() == path {
+		return userDB, nil
+	}
+	closeUserDB()
+	if !create && !userDataExists(path) {
+		return nil, nil
+	}
+	db, err := store.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	userDB = db
+	return db, nil
+}
+
+// userDataExists reports whether the database at path, or a JSON store it
+// would import, exists.
+func userDataExists(path string) bool {
+	dir := filepath.Dir(path)
+	for _, p := range []string{path, filepath.Join(dir, store.LegacyLibrary), filepath.Join(dir, store.LegacyAlerts), filepath.Join(dir, store.LegacyHistory)} {
+		if _, err := os.Stat(p); !errors.Is(err, os.ErrNotExist) {
+			return true
+		}
+	}
+	return false
+}
+
+// closeUserDB closes the user database if it is open.
+func closeUserDB() {
+	if userDB != nil {
+		userDB.Close()
+		userDB = nil
+	}
+}
+
+func loadLibrary() (*library.Library, error) {
+	db, err := openUserDB(false)
+	if err != nil || db == nil {
+		return library.New(), err
+	}
+	return db.Library()
+}
+
+func saveLibrary(l *library.Library) error {
+	db, err := openUserDB(true)
+	if err != nil {
+		return err
+	}
+	return db.SaveLibrary(l)
+}
+
+func loadAlerts() (*alert.Book, error) {
+	db, err := openUserDB(false)
+	if err != nil || db == nil {
+		return &alert.Book{}, err
+	}
+	return db.Alerts()
+}
+
+func saveAlerts(b *alert.Book) error {
+	db, err := openUserDB(true)
+	if err != nil {
+		return err
+	}
+	return db.SaveAlerts(b)
+}
+
+func loadHistory() ([]history.Entry, error) {
+	db, err := openUserDB(false)
+	if err != nil || db == nil {
+		return nil, err
+	}
+	return db.History()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/dedupe"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDedupeReport    string
+	flagDedupeThreshold float64
+	flagDedupeNoFetch   bool
+)
+
+// dedupeCmd merges reference exports and removes duplicates.
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe <file> [file...]",
+	Short: "Merge reference exports and remove duplicates",
+	Long: `Read RIS (.ris), CSV/TSV (.csv, .tsv), and PMID-list files (anything else,
+or - for stdin), then keep the first copy of each reference. Records match by
+PMID, then DOI, then title similarity (--threshold) when the first author's
+surname agrees and publication years are within one.
+
+PMID-only records are fetched from PubMed first so they can match exports
+that carry only DOIs or titles (--no-fetch skips this). Write the merged set
+with --out (e.g. merged.ris) or --ris/--bibtex/--csv, and the removed
+duplicates with --report.`,
+	Example: `  pubmed dedupe pubmed.ris embase.ris scopus.csv --out merged.ris --report duplicates.csv`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagDedupeThreshold <= 0 || flagDedupeThreshold > 1 {
+			return errUsage("--threshold must be in (0, 1]")
+		}
+
+		var records []dedupe.Record
+		for _, path := range args {
+			recs, err := dedupe.ReadFile(path, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			records = append(records, recs...)
+		}
+
+		if !flagDedupeNoFetch {
+			if err := fillPMIDOnlyRecords(cmd, records); err != nil {
+				// Non-fatal: PMID-only records still match on PMID.
+				fmt.Fprintf(os.Stderr, "Warning: could not fetch article details: %v\n", err)
+			}
+		}
+
+		res := dedupe.Dedupe(records, flagDedupeThreshold)
+		if flagDedupeReport != "" {
+			if err := output.WriteDuplicatesReport(flagDedupeReport, res); err != nil {
+				return err
+			}
+		}
+		return output.FormatDedupeResult(cmd.OutOrStdout(), res, outputCfg())
+	},
+}
+
+func init() {
+	dedupeCmd.Flags().StringVar(&flagDedupeReport, "report", "", "Write the removed duplicates to this CSV file")
+	dedupeCmd.Flags().Float64Var(&flagDedupeThreshold, "threshold", dedupe.DefaultThreshold, "Title similarity (0-1) for fuzzy matches")
+	dedupeCmd.Flags().BoolVar(&flagDedupeNoFetch, "no-fetch", false, "Do not fetch metadata for PMID-only records")
+}
+
+// fillPMIDOnlyRecords replaces records that carry only a PMID with the
+// fetched article, keeping their provenance.
+func fillPMIDOnlyRecords(cmd *cobra.Command, records []dedupe.Record) error {
+	var pmids []string
+	for _, r := range records {
+		if r.Article.PMID != "" && r.Article.Title == "" {
+			pmids = append(pmids, r.Article.PMID)
+		}
+	}
+	if len(pmids) == 0 {
+		return nil
+	}
+
+	articles, err := fetchInBatches(cmd.Context(), newEutilsClient(), pmids)
+	if err != nil {
+		return err
+	}
+	orderArticles(articles, pmids)
+	byPMID := make(map[string]int, len(articles))
+	for i, a := range articles {
+		byPMID[a.PMID] = i
+	}
+	for i, r := range records {
+		if j, ok := byPMID[r.Article.PMID]; ok && r.Article.Title == "" {
+			records[i].Article = articles[j]
+		}
+	}
+	return nil
+}
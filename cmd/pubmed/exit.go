@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, so scripts and agents can tell failures apart without
+// parsing messages.
+const (
+	exitOK        = 0
+	exitFailure   = 1 // any failure without a code of its own
+	exitUsage     = 2 // invalid command, flags, or arguments
+	exitNoResults = 3 // the search or fetch found nothing
+	exitService   = 4 // NCBI or another API failed or could not be reached, or --offline lacked a response
+	exitFlagged   = 5 // retractions or verify flagged a reference, or lint found an error
+)
+
+// exitKinds names each exit code in --json errors.
+var exitKinds = map[int]string{
+	exitFailure:   "error",
+	exitUsage:     "usage",
+	exitNoResults: "no_results",
+	exitService:   "service",
+	exitFlagged:   "flagged",
+}
+
+// argsChecked is set once cobra has parsed the flags and checked the
+// arguments; errors before then are usage errors.
+var argsChecked bool
+
+// codedError is an error with the exit code it should end the run with.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withExitCode marks err to end the run with code.
+func withExitCode(code int, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// errNoResults reports that a command found nothing; its output has
+// already said so.
+func errNoResults(format string, args ...any) error {
+	return withExitCode(exitNoResults, fmt.Errorf(format, args...))
+}
+
+// errUsage reports invalid arguments or flags found once a command has
+// started, so they exit as cobra's own usage errors do.
+func errUsage(format string, args ...any) error {
+	return withExitCode(exitUsage, fmt.Errorf(format, args...))
+}
+
+// exitCode classifies err.
+func exitCode(err error) int {
+	var coded *codedError
+	var status *ncbi.StatusError
+	var uerr *url.Error
+	var nerr net.Error
+	var offline *ncbi.OfflineError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &coded):
+		return coded.code
+	case !argsChecked:
+		return exitUsage
+	case errors.As(err, &status), errors.As(err, &uerr), errors.As(err, &nerr),
+		errors.As(err, &offline), errors.Is(err, context.DeadlineExceeded):
+		return exitService
+	}
+	return exitFailure
+}
+
+// jsonError is the error --json and --ndjson write to stderr.
+type jsonError struct {
+	Error jsonErrorBody `json:"error"`
+}
+
+type jsonErrorBody struct {
+	Code    int    `json:"code"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// Status is the HTTP status of a failed service request.
+	Status int `json:"status,omitempty"`
+	// Missing lists the requests --offline could not answer from the cache.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// jsonRequested reports whether --json or --ndjson was given, looking at
+// the raw arguments when an error stopped flag parsing.
+func jsonRequested() bool {
+	if flagJSON || flagNDJSON {
+		return true
+	}
+	if argsChecked {
+		return false
+	}
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--":
+			return false
+		case "--json", "--ndjson":
+			return true
+		}
+	}
+	return false
+}
+
+// reportError writes err to w: as a JSON object with --json or --ndjson,
+// or else as text, pointing usage errors at cmd's help.
+func reportError(w io.Writer, cmd *cobra.Command, err error, code int) {
+	if jsonRequested() {
+		body := jsonErrorBody{Code: code, Kind: exitKinds[code], Message: err.Error()}
+		var status *ncbi.StatusError
+		if errors.As(err, &status) {
+			body.Status = status.Code
+		}
+		body.Missing = offlineMissing()
+		json.NewEncoder(w).Encode(jsonError{Error: body})
+		return
+	}
+	fmt.Fprintln(w, "Error:", err)
+	if code == exitUsage && cmd != nil {
+		fmt.Fprintf(w, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	if missing := offlineMissing(); len(missing) > 0 {
+		writeOfflineMissing(w, missing)
+	}
+}
@@ -0,0 +1,614 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/icite"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/progress"
+	"github.com/henrybloomingdale/pubmed-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+// exportMaxRecords is the most records PubMed returns for one search, even
+// through the history server.
+const exportMaxRecords = eutils.MaxHistoryRecords
+
+// exportAllFrom is where export --all starts splitting by Entrez date;
+// no PubMed record was added earlier.
+var exportAllFrom = time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	flagExportPMIDs      string
+	flagExportCollection string
+	flagExportObsidian   string
+	flagExportAll        bool
+	flagExportLean       bool
+	flagExportDryRun     bool
+)
+
+// leanColumns are the default table columns for export --lean, which has
+// no abstracts or MeSH terms to fill the usual ones.
+var leanColumns = []string{"pmid", "title", "authors", "journal", "year", "doi"}
+
+// exportCmd writes every record matching a query or PMID list to a file.
+var exportCmd = &cobra.Command{
+	Use:   "export [query]",
+	Short: "Export all matching records to RIS, BibTeX, CSL-JSON, or CSV",
+	Long: `Export every article matching a query, listed in --pmids, or saved in a
+library collection (--collection), in one shot.
+Query results are paged through the Entrez history server, up to PubMed's
+10,000-record limit (--limit sets a lower cap). --pmids also takes DOIs;
+give - (as --pmids - or the only argument) to read PMIDs or DOIs from stdin,
+separated by whitespace or commas.
+
+The destination is --out FILE, with the format taken from its extension
+(.ris, .bib, .csl.json, .csv, .tsv, .xlsx, .json, .ndjson), or any of the
+--ris, --bibtex, --csv, and --tsv exports.
+
+--oa adds each article's open-access status and best open-access copy from
+Unpaywall (needs UNPAYWALL_EMAIL): as oa_status and oa_url in JSON and the
+--columns of CSV/TSV, as L1 in RIS, and as url in BibTeX. --icite adds NIH
+iCite's Relative Citation Ratio, NIH percentile, and APT score as rcr,
+nih_percentile, and apt.
+
+--obsidian DIR writes a Markdown vault for Obsidian (Notion can import it
+too): one note per article, named by PMID, with YAML frontmatter (title,
+pmid, doi, authors, and MeSH terms as mesh/ tags), wiki-links between
+exported articles that cite each other, and an index note, named after the
+query or collection, that links every article.
+
+--all exports a query's results past the 10,000-record limit by splitting it
+into ranges of the date records were added to PubMed, each small enough for
+one search, and writes every page to the output as it arrives instead of
+holding the whole set in memory. Records come oldest first. It streams to
+--ndjson, CSV, TSV, RIS, and BibTeX; JSON, CSL-JSON, Excel, and --obsidian
+need the whole set and are not available with it.
+
+--lean fetches PubMed's document summaries instead of full records: citation
+fields, DOI, PMCID, language, and publication types, but no abstracts, MeSH
+terms, keywords, or affiliations, and authors with initials only. A summary
+is a fraction of a full record's size, so pair it with --all and a --columns
+selection for exports of 100,000 records or more.
+
+--dry-run checks the scope first: it runs only the searches that count the
+matches (and, with --all, split them by date) and prints the query as
+PubMed translated it, how many records the export would fetch, and the
+requests it would make to each service, with the time they take at NCBI's
+rate limit. Nothing is fetched or written.`,
+	Example: `  pubmed export "fragile x syndrome" --year 2015-2025 --out library.ris
+  pubmed export --pmids 38000001,38000002 --out refs.bib
+  pubmed export --collection fxs-trials --out fxs.csl.json
+  pubmed export --pmids 38000001,38000002 --oa --csv refs.csv --columns pmid,doi,oa_status,oa_url
+  pubmed export "fragile x" --icite --csv evidence.csv --columns pmid,title,year,rcr,nih_percentile,apt
+  pubmed export "fragile x" --year 2020-2025 --obsidian ./vault
+  pubmed export "autism" --all --ndjson > autism.ndjson
+  pubmed export "autism" --all --csv autism.csv --columns pmid,year,title,doi
+  pubmed export "autism" --all --lean --tsv autism.tsv --columns pmid,year,journal,doi
+  pubmed export "autism" --all --icite --dry-run
+  pubmed search "fragile x" --ids-only | pubmed export - --out refs.ris
+  pubmed search "fragile x" --ndjson | jq -r .pmid | pubmed export --pmids - -o refs.csl.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := outputCfg()
+		if !hasExportDestination(cfg) && flagExportObsidian == "" && !flagExportDryRun {
+			return errUsage("export needs a destination: --out FILE (or --ris, --bibtex, --csv, --tsv, --json, --obsidian)")
+		}
+		list := flagExportPMIDs
+		if isStdinArg(args) && list == "" {
+			list, args = "-", nil
+		}
+		if countSet(len(args) > 0, list != "", flagExportCollection != "") != 1 {
+			return errUsage("give one of a query, --pmids, or --collection")
+		}
+		var oa *fulltext.Client
+		if flagOA && !flagExportDryRun {
+			var err error
+			if oa, err = newOAClient(); err != nil {
+				return err
+			}
+		}
+		if flagExportLean {
+			if len(args) == 0 {
+				return errUsage("--lean exports a query, not --pmids or --collection")
+			}
+			if len(cfg.Columns) == 0 {
+				cfg.Columns = leanColumns
+			}
+			for _, c := range cfg.Columns {
+				if c == "abstract" || c == "mesh" {
+					return errUsage("--lean summaries have no %s; drop it from --columns or export without --lean", c)
+				}
+			}
+		}
+		if flagExportAll {
+			if len(args) == 0 {
+				return errUsage("--all exports a query, not --pmids or --collection")
+			}
+			if flagExportObsidian != "" {
+				return errUsage("--obsidian needs the whole set and cannot be used with --all")
+			}
+			if flagSortLoc != "" {
+				return errUsage("--all writes records as they arrive and cannot apply --sort-local")
+			}
+		}
+		if list == "" && len(args) > 0 {
+			warnQueryProblems(strings.Join(args, " "))
+		}
+		if flagExportDryRun {
+			plan, err := planExport(cmd, args, list)
+			if err != nil {
+				return err
+			}
+			return writeExportPlan(cmd.OutOrStdout(), plan, cfg.JSON)
+		}
+		if flagExportAll {
+			return exportAll(cmd, buildQuery(args), cfg, oa)
+		}
+
+		var (
+			articles []eutils.Article
+			err      error
+		)
+		switch {
+		case list != "":
+			articles, err = exportPMIDArticles(cmd, list)
+		case flagExportCollection != "":
+			articles, err = collectionArticles(flagExportCollection)
+		default:
+			articles, err = exportQueryArticles(cmd, buildQuery(args))
+		}
+		if err != nil {
+			return err
+		}
+		if flagSortLoc != "" {
+			eutils.SortArticles(articles, flagSortLoc)
+		}
+		if flagExportCollection == "" {
+			annotateArticles(articles)
+		}
+		if oa != nil {
+			annotateOA(cmd.Context(), oa, articles)
+		}
+		if flagICite {
+			if err := newICiteClient().Annotate(cmd.Context(), articles); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+
+		if flagExportObsidian != "" {
+			title := strings.Join(args, " ")
+			if flagExportCollection != "" {
+				title = flagExportCollection
+			}
+			if err := writeVault(cmd, flagExportObsidian, title, articles); err != nil {
+				return err
+			}
+		}
+
+		w := io.Discard
+		if cfg.JSON || cfg.NDJSON {
+			w = cmd.OutOrStdout()
+		}
+		if err := output.FormatArticles(w, articles, cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d articles\n", len(articles))
+		if len(articles) == 0 {
+			return errNoResults("nothing to export")
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.ValidArgsFunction = completeQueryTerms
+	exportCmd.Flags().StringVar(&flagExportPMIDs, "pmids", "", "Comma-separated PMIDs or DOIs to export, or - to read them from stdin")
+	exportCmd.Flags().StringVar(&flagExportCollection, "collection", "", "Export a library collection (see pubmed lib)")
+	exportCmd.Flags().BoolVar(&flagOA, "oa", false, "Add open-access status and best copy from Unpaywall (needs UNPAYWALL_EMAIL)")
+	exportCmd.Flags().BoolVar(&flagICite, "icite", false, "Add Relative Citation Ratio, NIH percentile, and APT scores from NIH iCite")
+	exportCmd.Flags().StringVar(&flagExportObsidian, "obsidian", "", "Write an Obsidian Markdown vault of linked notes to this directory")
+	exportCmd.Flags().BoolVar(&flagExportAll, "all", false, "Export every match past the 10,000-record limit, streaming pages to the output")
+	exportCmd.Flags().BoolVar(&flagExportLean, "lean", false, "Fetch document summaries (no abstracts or MeSH) to keep very large exports small")
+	exportCmd.Flags().BoolVar(&flagExportDryRun, "dry-run", false, "Print the searches, record count, and requests the export would make, without fetching")
+	exportCmd.MarkFlagDirname("obsidian")
+	exportCmd.RegisterFlagCompletionFunc("collection", completeCollectionNames)
+}
+
+// hasExportDestination reports whether cfg writes records anywhere.
+func hasExportDestination(cfg output.OutputConfig) bool {
+	return cfg.JSON || cfg.NDJSON || cfg.CSVFile != "" || cfg.TSVFile != "" || cfg.XLSXFile != "" ||
+		cfg.RISFile != "" || cfg.BibTeXFile != "" || cfg.CSLFile != ""
+}
+
+// writeVault writes articles as an Obsidian vault in dir, linking the
+// ones that cite each other.
+func writeVault(cmd *cobra.Command, dir, title string, articles []eutils.Article) error {
+	pmids := make([]string, 0, len(articles))
+	for _, a := range articles {
+		if a.PMID != "" {
+			pmids = append(pmids, a.PMID)
+		}
+	}
+	refs, err := newEutilsClient().ReferenceLists(cmd.Context(), pmids)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: citation links unavailable: %v\n", err)
+	}
+	index, err := vault.Write(dir, title, articles, refs)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d notes and %s\n", len(articles), index)
+	return nil
+}
+
+// historyPage fetches one page of a history-server result set.
+type historyPage func(ctx context.Context, webEnv, queryKey string, start, count int) ([]eutils.Article, error)
+
+// exportPages returns full records, or document summaries with --lean.
+func exportPages(client *eutils.Client) historyPage {
+	if flagExportLean {
+		return client.SummaryHistory
+	}
+	return client.FetchHistory
+}
+
+// exportSearch runs an export's search on the history server, returning
+// the result and how many of its records the export takes.
+func exportSearch(cmd *cobra.Command, client *eutils.Client, q string) (*eutils.SearchResult, int, error) {
+	opts := &eutils.SearchOptions{Limit: 1, Sort: strings.ToLower(flagSort)}
+	var err error
+	if opts.MinDate, opts.MaxDate, err = dateRange(); err != nil {
+		return nil, 0, err
+	}
+	result, err := client.Search(cmd.Context(), q, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search failed: %w", err)
+	}
+	total := result.Count
+	if max := exportLimit(cmd, exportMaxRecords); total > max {
+		total = max
+	}
+	return result, total, nil
+}
+
+// exportLimit returns max, lowered to --limit when that is set and lower.
+func exportLimit(cmd *cobra.Command, max int) int {
+	if cmd.Flags().Changed("limit") && flagLimit < max {
+		return flagLimit
+	}
+	return max
+}
+
+// exportQueryArticles pages through a search's history-server result set.
+func exportQueryArticles(cmd *cobra.Command, q string) ([]eutils.Article, error) {
+	client := newEutilsClient()
+	result, total, err := exportSearch(cmd, client, q)
+	if err != nil {
+		return nil, err
+	}
+	if result.Count > total && total == exportMaxRecords {
+		fmt.Fprintf(os.Stderr, "Warning: %d records match; PubMed returns at most %d, narrow the query to export the rest\n", result.Count, total)
+	}
+
+	fetchPage := exportPages(client)
+	articles := make([]eutils.Article, 0, total)
+	var p *progress.Reporter
+	if total > fetchBatchSize {
+		p = newProgress("Fetching articles", total)
+		defer p.Done()
+	}
+	for start := 0; start < total; start += fetchBatchSize {
+		count := fetchBatchSize
+		if start+count > total {
+			count = total - start
+		}
+		batch, err := fetchPage(cmd.Context(), result.WebEnv, result.QueryKey, start, count)
+		if err != nil {
+			return nil, fmt.Errorf("fetch failed at record %d: %w", start+1, err)
+		}
+		articles = append(articles, batch...)
+		p.Add(count)
+	}
+	return articles, nil
+}
+
+// exportAll streams every record matching q to the exports in cfg, one
+// Entrez-date range at a time.
+func exportAll(cmd *cobra.Command, q string, cfg output.OutputConfig, oa *fulltext.Client) error {
+	if err := output.StreamableConfig(cfg); err != nil {
+		return errUsage("--all: %w", err)
+	}
+	ctx := cmd.Context()
+	client := newEutilsClient()
+	q, ranges, total, err := exportAllRanges(cmd, client, q)
+	if err != nil {
+		return err
+	}
+
+	stream, err := output.NewArticleStream(cmd.OutOrStdout(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	fetchPage := exportPages(client)
+	// One iCite client for the whole export, so its requests share one
+	// rate limit.
+	var ic *icite.Client
+	if flagICite {
+		ic = newICiteClient()
+	}
+	p := newProgress("Exporting articles", total)
+	defer p.Done()
+	for _, r := range ranges {
+		if stream.Count() >= total {
+			break
+		}
+		opts := r.Options()
+		opts.Limit = 1
+		result, err := client.Search(ctx, q, opts)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		n := min(result.Count, total-stream.Count())
+		for start := 0; start < n; start += fetchBatchSize {
+			batch, err := fetchPage(ctx, result.WebEnv, result.QueryKey, start, min(fetchBatchSize, n-start))
+			if err != nil {
+				return fmt.Errorf("fetch failed at record %d: %w", stream.Count()+1, err)
+			}
+			annotateArticles(batch)
+			if oa != nil {
+				annotateOA(ctx, oa, batch)
+			}
+			if ic != nil {
+				if err := ic.Annotate(ctx, batch); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+			if err := stream.Write(batch); err != nil {
+				return err
+			}
+			p.Set(stream.Count())
+		}
+	}
+	if err := stream.Close(); err != nil {
+		return err
+	}
+	p.Done()
+	fmt.Fprintf(os.Stderr, "Exported %d articles\n", stream.Count())
+	if stream.Count() == 0 {
+		return errNoResults("nothing to export")
+	}
+	return nil
+}
+
+// exportAllRanges splits the records matching q by Entrez date for
+// export --all, returning the query as searched, the ranges, and how many
+// records the export takes.
+func exportAllRanges(cmd *cobra.Command, client *eutils.Client, q string) (string, []eutils.DateRange, int, error) {
+	minDate, maxDate, err := dateRange()
+	if err != nil {
+		return "", nil, 0, err
+	}
+	if minDate != "" {
+		// The date ranges filter on the Entrez date, so the publication
+		// dates go in the query.
+		q = fmt.Sprintf(`(%s) AND ("%s"[dp] : "%s"[dp])`, q, minDate, maxDate)
+	}
+	ranges, err := client.SplitByEntrezDate(cmd.Context(), q, exportAllFrom, time.Now(), exportMaxRecords-1)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	total := 0
+	for _, r := range ranges {
+		total += r.Count
+	}
+	return q, ranges, exportLimit(cmd, total), nil
+}
+
+// exportPMIDArticles fetches a --pmids list of PMIDs or DOIs, reading
+// stdin for "-".
+func exportPMIDArticles(cmd *cobra.Command, list string) ([]eutils.Article, error) {
+	ids, err := stdinArgs(cmd, []string{list})
+	if err != nil {
+		return nil, err
+	}
+	client := newEutilsClient()
+	pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --pmids: %w", err)
+	}
+
+	articles, err := fetchInBatches(cmd.Context(), client, pmids)
+	if err != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+	return articles, nil
+}
+
+// readIDs reads PMIDs or DOIs separated by whitespace or commas, leaving
+// validation to resolveIDArgs.
+func readIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		for _, id := range strings.Split(scanner.Text(), ",") {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, scanner.Err()
+}
+
+// exportPlan is what export --dry-run reports: the scope of an export and
+// the requests it would make.
+type exportPlan struct {
+	Query       string `json:"query,omitempty"`
+	Translation string `json:"query_translation,omitempty"`
+	Matches     int    `json:"matches"`
+	Records     int    `json:"records"`
+	// Ranges is how many Entrez-date ranges --all splits the query into.
+	Ranges   int           `json:"ranges,omitempty"`
+	Requests []planRequest `json:"requests"`
+	// NCBISeconds is the least time the NCBI requests take at the rate
+	// limit in force.
+	NCBISeconds float64  `json:"ncbi_seconds"`
+	Notes       []string `json:"notes,omitempty"`
+}
+
+// planRequest counts the requests an export would make to one service.
+type planRequest struct {
+	Service  string `json:"service"`
+	Requests int    `json:"requests"`
+	NCBI     bool   `json:"ncbi"`
+	What     string `json:"what"`
+}
+
+// planExport works out what an export would fetch, running only the
+// searches that count the records.
+func planExport(cmd *cobra.Command, args []string, list string) (*exportPlan, error) {
+	client := newEutilsClient()
+	plan := &exportPlan{}
+	fetch := "efetch"
+	if flagExportLean {
+		fetch = "esummary"
+	}
+	switch {
+	case list != "":
+		args, err := stdinArgs(cmd, []string{list})
+		if err != nil {
+			return nil, err
+		}
+		var ids, dois int
+		for _, arg := range args {
+			for _, id := range strings.Split(arg, ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					ids++
+					if doiArgRe.MatchString(id) {
+						dois++
+					}
+				}
+			}
+		}
+		plan.Matches, plan.Records = ids, ids
+		plan.add("esearch", dois, true, "DOI lookups")
+		plan.add(fetch, pages(ids, fetchBatchSize), true, fmt.Sprintf("%d records, %d per request", ids, fetchBatchSize))
+	case flagExportCollection != "":
+		articles, err := collectionArticles(flagExportCollection)
+		if err != nil {
+			return nil, err
+		}
+		plan.Matches, plan.Records = len(articles), len(articles)
+		plan.Notes = append(plan.Notes, "collections are exported from the local library without fetching")
+	case flagExportAll:
+		q, ranges, total, err := exportAllRanges(cmd, client, buildQuery(args))
+		if err != nil {
+			return nil, err
+		}
+		plan.Query, plan.Ranges, plan.Records = q, len(ranges), total
+		for _, r := range ranges {
+			plan.Matches += r.Count
+		}
+		fetches, left := 0, total
+		for _, r := range ranges {
+			n := min(r.Count, left)
+			fetches += pages(n, fetchBatchSize)
+			left -= n
+		}
+		plan.add("esearch", len(ranges), true, "one history search per date range")
+		plan.add(fetch, fetches, true, fmt.Sprintf("%d records, %d per request", total, fetchBatchSize))
+	default:
+		q := buildQuery(args)
+		result, total, err := exportSearch(cmd, client, q)
+		if err != nil {
+			return nil, err
+		}
+		plan.Query, plan.Translation, plan.Matches, plan.Records = q, result.QueryTranslation, result.Count, total
+		plan.add("esearch", 1, true, "history search")
+		plan.add(fetch, pages(total, fetchBatchSize), true, fmt.Sprintf("%d records, %d per request", total, fetchBatchSize))
+		if result.Count > total && total == exportMaxRecords {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("PubMed returns at most %d records per search; use --all for the rest", exportMaxRecords))
+		}
+	}
+
+	if flagExportObsidian != "" {
+		plan.add("elink", pages(plan.Records, eutils.LinkBatchSize), true, "reference lists for vault links")
+	}
+	if flagICite {
+		plan.add("iCite", pages(plan.Records, icite.BatchSize), false, fmt.Sprintf("%d PMIDs per request", icite.BatchSize))
+	}
+	if flagOA {
+		plan.add("Unpaywall", plan.Records, false, "one lookup per article with a DOI, at most")
+	}
+
+	rate, limit := ncbi.RateWithoutKey, "without an API key"
+	if client.APIKey != "" {
+		rate, limit = ncbi.RateWithKey, "with an API key"
+	}
+	ncbiRequests := 0
+	for _, r := range plan.Requests {
+		if r.NCBI {
+			ncbiRequests += r.Requests
+		}
+	}
+	plan.NCBISeconds = float64(ncbiRequests) / float64(rate)
+	plan.Notes = append(plan.Notes, fmt.Sprintf("NCBI allows %d requests/s %s", rate, limit))
+	return plan, nil
+}
+
+func (p *exportPlan) add(service string, requests int, ncbi bool, what string) {
+	if requests > 0 {
+		p.Requests = append(p.Requests, planRequest{Service: service, Requests: requests, NCBI: ncbi, What: what})
+	}
+}
+
+// pages returns how many requests of size fetch n records.
+func pages(n, size int) int {
+	return (n + size - 1) / size
+}
+
+// writeExportPlan prints plan as text, or as JSON with asJSON.
+func writeExportPlan(w io.Writer, plan *exportPlan, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+	if plan.Query != "" {
+		fmt.Fprintf(w, "Query:        %s\n", plan.Query)
+	}
+	if plan.Translation != "" {
+		fmt.Fprintf(w, "Translation:  %s\n", plan.Translation)
+	}
+	fmt.Fprintf(w, "Matches:      %d\n", plan.Matches)
+	fmt.Fprintf(w, "Records:      %d\n", plan.Records)
+	if plan.Ranges > 0 {
+		fmt.Fprintf(w, "Date ranges:  %d\n", plan.Ranges)
+	}
+	if len(plan.Requests) > 0 {
+		fmt.Fprintln(w, "Requests:")
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, r := range plan.Requests {
+			fmt.Fprintf(tw, "  %s\t%d\t%s\n", r.Service, r.Requests, r.What)
+		}
+		tw.Flush()
+		fmt.Fprintf(w, "NCBI time:    at least %s\n", time.Duration(plan.NCBISeconds*float64(time.Second)).Round(time.Second))
+	}
+	for _, n := range plan.Notes {
+		fmt.Fprintf(w, "Note: %s\n", n)
+	}
+	fmt.Fprintln(w, "Dry run: nothing was fetched or written.")
+	return nil
+}
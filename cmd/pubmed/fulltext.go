@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFullTextPDF   bool
+	flagFullTextDir   string
+	flagFullTextEmail string
+)
+
+// fulltextCmd finds and prints or downloads open-access full text.
+var fulltextCmd = &cobra.Command{
+	Use:   "fulltext <pmid|doi> [pmid|doi...]",
+	Short: "Print or download open-access full text with its license",
+	Long: `Find open-access full text for each article and report its license.
+Articles in PMC are read from their PMC XML, printed as plain text with
+section headings; --pdf looks for the PDF from the PMC Open Access Subset
+instead and reports its link. Articles outside PMC fall back to the best
+open-access copy Unpaywall knows for their DOI, or to a green open-access
+copy in a repository when the best copy has no file. Unpaywall needs a
+contact email (--email or UNPAYWALL_EMAIL).
+
+--dir saves each article as <pmid>.xml or <pmid>.pdf instead of printing it.
+Give - to read PMIDs or DOIs from stdin.`,
+	Example: `  pubmed fulltext 38000001
+  pubmed fulltext 38000001 38000002 --pdf --dir papers/
+  pubmed fulltext 10.1186/s11689-024-00001-1 --email me@example.org --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := stdinArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		client := newEutilsClient()
+		pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+		if err != nil {
+			return err
+		}
+		articles, err := client.Fetch(cmd.Context(), pmids)
+		if err != nil {
+			return fmt.Errorf("fetch failed: %w", err)
+		}
+		articles = orderArticles(articles, pmids)
+
+		email := flagFullTextEmail
+		if email == "" {
+			email = os.Getenv(fulltext.EnvUnpaywallEmail)
+		}
+		ft := fulltext.NewClient(newBaseClient(), email)
+		results := make([]fulltext.Result, 0, len(articles))
+		for _, a := range articles {
+			r, err := ft.Resolve(cmd.Context(), a, flagFullTextPDF)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: PMID %s: %v\n", a.PMID, err)
+			}
+			if r.Found() && flagFullTextDir != "" {
+				if err := ft.Save(cmd.Context(), &r, flagFullTextDir); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: PMID %s: %v\n", a.PMID, err)
+				}
+			}
+			results = append(results, r)
+		}
+		if email == "" {
+			for _, r := range results {
+				if !r.Found() && r.DOI != "" {
+					fmt.Fprintf(os.Stderr, "Note: set --email or %s to look up open-access copies outside PMC through Unpaywall\n", fulltext.EnvUnpaywallEmail)
+					break
+				}
+			}
+		}
+		return output.FormatFullText(cmd.OutOrStdout(), results, outputCfg())
+	},
+}
+
+func init() {
+	fulltextCmd.Flags().BoolVar(&flagFullTextPDF, "pdf", false, "Get the PDF rather than PMC XML text")
+	fulltextCmd.Flags().StringVar(&flagFullTextDir, "dir", "", "Save full text files to this directory instead of printing")
+	fulltextCmd.Flags().StringVar(&flagFullTextEmail, "email", "", "Contact email for Unpaywall lookups (or set "+fulltext.EnvUnpaywallEmail+")")
+	fulltextCmd.MarkFlagDirname("dir")
+}
+
+// newOAClient returns the Unpaywall client for --oa, which needs a contact
+// email.
+func newOAClient() (*fulltext.Client, error) {
+	email := os.Getenv(fulltext.EnvUnpaywallEmail)
+	if email == "" {
+		return nil, fmt.Errorf("--oa needs a contact email for Unpaywall: set %s", fulltext.EnvUnpaywallEmail)
+	}
+	return fulltext.NewClient(newBaseClient(), email), nil
+}
+
+// annotateOA adds Unpaywall's open-access status and best copy to articles
+// with a DOI. Lookup failures are warnings.
+func annotateOA(ctx context.Context, ft *fulltext.Client, articles []eutils.Article) {
+	for i := range articles {
+		if err := ft.Annotate(ctx, &articles[i]); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: DOI %s: %v\n", articles[i].DOI, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagGrantsQuery string
+	flagGrantsPMIDs string
+	flagGrantsTop   int
+)
+
+// grantsCmd aggregates the funding acknowledged across a result set.
+var grantsCmd = &cobra.Command{
+	Use:   "grants",
+	Short: "Funding agencies and grant numbers across a result set",
+	Long: `Fetch the articles matching --query (or listed in --pmids) and count the
+articles each funding agency appears on, with the grant numbers cited, from
+the GrantList NLM indexes. Agencies are named as NLM records them, such as
+"NIMH NIH HHS"; many non-NIH funders are listed without grant numbers, and
+articles without a GrantList may still have been funded. Use --csv to export
+one row per agency.`,
+	Example: `  pubmed grants --query "fragile x syndrome AND metformin" --human
+  pubmed grants --query "fragile x syndrome" --limit 500 --csv funders.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagGrantsTop < 0 {
+			return errUsage("--top must not be negative")
+		}
+		articles, err := analyzeArticles(cmd, newEutilsClient(), flagGrantsQuery, flagGrantsPMIDs)
+		if err != nil {
+			return err
+		}
+		return output.FormatGrants(cmd.OutOrStdout(), analyze.Grants(articles, flagGrantsTop), outputCfg())
+	},
+}
+
+func init() {
+	grantsCmd.Flags().StringVar(&flagGrantsQuery, "query", "", "PubMed query selecting the articles")
+	grantsCmd.Flags().StringVar(&flagGrantsPMIDs, "pmids", "", "Comma-separated PMIDs to use instead of a query")
+	grantsCmd.Flags().IntVar(&flagGrantsTop, "top", 20, "Number of agencies to report (0 for all)")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/graph"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagGraphDepth     int
+	flagGraphFile      string
+	flagGraphFormat    string
+	flagGraphDirection string
+	flagGraphMaxNodes  int
+)
+
+// graphCmd exports the citation network around an article.
+var graphCmd = &cobra.Command{
+	Use:   "graph <pmid>",
+	Short: "Export a citation graph (DOT or GraphML)",
+	Long: `Walk cited-by and reference links outward from an article and write the
+resulting citation graph for Graphviz (DOT) or Gephi/yEd (GraphML). Nodes carry
+the PMID, year, and title; edges point from the citing article to the cited one.
+
+--depth sets how many hops to follow, --limit caps the links followed per
+article in each direction, and --max-nodes bounds the whole graph. The format
+comes from --format or the --file extension (.graphml), defaulting to DOT.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validatePMID(args[0]); err != nil {
+			return fmt.Errorf("invalid PMID: %w", err)
+		}
+		if flagGraphDepth < 1 {
+			return errUsage("--depth must be at least 1")
+		}
+		if flagGraphMaxNodes < 1 {
+			return errUsage("--max-nodes must be at least 1")
+		}
+		format := flagGraphFormat
+		if format == "" {
+			format = graph.FormatForPath(flagGraphFile)
+		}
+
+		client := newEutilsClient()
+		g, err := graph.Build(cmd.Context(), client, args[0], graph.Options{
+			Depth:     flagGraphDepth,
+			Direction: flagGraphDirection,
+			PerNode:   flagLimit,
+			MaxNodes:  flagGraphMaxNodes,
+		})
+		if err != nil {
+			return fmt.Errorf("citation graph failed: %w", err)
+		}
+		if g.Truncated {
+			fmt.Fprintf(os.Stderr, "Warning: stopped at %d articles (--max-nodes)\n", flagGraphMaxNodes)
+		}
+
+		articles, err := fetchInBatches(cmd.Context(), client, g.PMIDs())
+		if err != nil {
+			// Non-fatal: the graph is still useful with PMID-only nodes.
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch article details: %v\n", err)
+		}
+		g.Annotate(articles)
+
+		cfg := outputCfg()
+		if flagGraphFile == "" {
+			if cfg.JSON {
+				return output.FormatCitationGraph(cmd.OutOrStdout(), g, "", cfg)
+			}
+			return graph.Write(cmd.OutOrStdout(), g, format)
+		}
+
+		f, err := os.Create(flagGraphFile)
+		if err != nil {
+			return fmt.Errorf("creating graph file: %w", err)
+		}
+		defer f.Close()
+		if err := graph.Write(f, g, format); err != nil {
+			return fmt.Errorf("writing graph: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing graph: %w", err)
+		}
+		return output.FormatCitationGraph(cmd.OutOrStdout(), g, flagGraphFile, cfg)
+	},
+}
+
+func init() {
+	graphCmd.Flags().IntVar(&flagGraphDepth, "depth", 1, "Link hops to follow from the article")
+	graphCmd.Flags().StringVar(&flagGraphFile, "file", "", "Write the graph to this file instead of stdout")
+	graphCmd.Flags().StringVar(&flagGraphFormat, "format", "", "Graph format: dot or graphml (default from --file extension)")
+	graphCmd.Flags().StringVar(&flagGraphDirection, "direction", graph.DirectionBoth, "Links to follow: both, cited-by, or references")
+	graphCmd.Flags().IntVar(&flagGraphMaxNodes, "max-nodes", graph.DefaultMaxNodes, "Maximum articles in the graph")
+	graphCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{graph.FormatDOT, graph.FormatGraphML}, cobra.ShellCompDirectiveNoFileComp))
+	graphCmd.RegisterFlagCompletionFunc("direction", cobra.FixedCompletions(
+		[]string{graph.DirectionBoth, graph.DirectionCitedBy, graph.DirectionReferences}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd groups the search history commands.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List, rerun, and compare past searches",
+	Long: `Every search is recorded with its query, PubMed's translation, the result
+count, and the PMIDs retrieved, in the user database, pubmed.db under the
+pubmed-cli config directory (~/.config/pubmed-cli on Linux; override with PUBMED_CLI_CONFIG_DIR).
+Set PUBMED_CLI_NO_HISTORY=1 to stop recording.`,
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show recent searches (--limit, default 20)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := loadHistory()
+		if err != nil {
+			return err
+		}
+		if len(entries) > flagLimit {
+			entries = entries[len(entries)-flagLimit:]
+		}
+		return output.FormatHistory(cmd.OutOrStdout(), entries, outputCfg())
+	},
+}
+
+var historyRerunCmd = &cobra.Command{
+	Use:   "rerun <N>",
+	Short: "Run search N again with its original options",
+	Long: `Run a recorded search again with its original limit, sort, and date range.
+Output flags work as for search. A summary of what changed since the original
+run is printed to stderr; use "pubmed history diff" for the details.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := loadHistory()
+		if err != nil {
+			return err
+		}
+		orig, err := historyEntry(entries, args[0])
+		if err != nil {
+			return err
+		}
+		opts := orig.Options
+		result, err := runSearch(cmd, newEutilsClient(), orig.Query, &opts)
+		if err != nil {
+			return err
+		}
+		d := history.Compare(orig, history.Entry{Translation: result.QueryTranslation, Count: result.Count, IDs: result.IDs})
+		fmt.Fprintf(os.Stderr, "Since #%d: %d → %d results, %d new and %d gone among retrieved PMIDs\n",
+			orig.ID, orig.Count, result.Count, len(d.Added), len(d.Removed))
+		if d.TranslationChanged() {
+			fmt.Fprintln(os.Stderr, "PubMed now translates this query differently.")
+		}
+		return nil
+	},
+}
+
+var historyDiffCmd = &cobra.Command{
+	Use:   "diff <N> <M>",
+	Short: "Compare the counts, translations, and PMIDs of two searches",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := loadHistory()
+		if err != nil {
+			return err
+		}
+		from, err := historyEntry(entries, args[0])
+		if err != nil {
+			return err
+		}
+		to, err := historyEntry(entries, args[1])
+		if err != nil {
+			return err
+		}
+		return output.FormatHistoryDiff(cmd.OutOrStdout(), history.Compare(from, to), outputCfg())
+	},
+}
+
+func init() {
+	historyRerunCmd.ValidArgsFunction = completeHistoryIDs
+	historyDiffCmd.ValidArgsFunction = completeHistoryIDs
+	historyCmd.AddCommand(historyListCmd, historyRerunCmd, historyDiffCmd)
+}
+
+// historyEntry finds the entry numbered arg ("3" or "#3").
+func historyEntry(entries []history.Entry, arg string) (history.Entry, error) {
+	if len(arg) > 0 && arg[0] == '#' {
+		arg = arg[1:]
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return history.Entry{}, errUsage("invalid history entry %q: use the number shown by pubmed history list", arg)
+	}
+	return history.Find(entries, id)
+}
+
+// recordSearch appends a search to the history. Failures only warn, so a
+// read-only config directory never breaks searching.
+func recordSearch(q string, opts *eutils.SearchOptions, result *eutils.SearchResult) {
+	if os.Getenv(history.EnvDisable) != "" {
+		return
+	}
+	db, err := openUserDB(true)
+	if err == nil {
+		_, err = db.AppendHistory(history.Entry{
+			Time:        time.Now(),
+			Query:       q,
+			Translation: result.QueryTranslation,
+			Options:     *opts,
+			Count:       result.Count,
+			IDs:         result.IDs,
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not record search history: %v\n", err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/hook"
+	"github.com/spf13/cobra"
+)
+
+var flagNoHooks bool
+
+// The output hook's state while the command runs: its output streams
+// through hookWriter to hookOut. hookStart is when the command began, so
+// only export files it wrote are hooked.
+var (
+	hookWriter *hook.Writer
+	hookOut    io.Writer
+	hookStart  time.Time
+)
+
+// unhookedCommands are the command groups hooks do not run for: settings,
+// shell completion, the server, and the interactive screens.
+var unhookedCommands = map[string]bool{
+	"completion": true,
+	"config":     true,
+	"refine":     true,
+	"screen":     true,
+	"serve":      true,
+}
+
+// hookCommand returns the configured hook command in env, or "" when it
+// is unset, --no-hooks is given, or cmd does not run hooks.
+func hookCommand(cmd *cobra.Command, env string) string {
+	if flagNoHooks || unhookedCommands[commandGroup(cmd)] {
+		return ""
+	}
+	return strings.TrimSpace(os.Getenv(env))
+}
+
+// hookEnv is the environment hooks run with, naming the command.
+func hookEnv(cmd *cobra.Command) []string {
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	return []string{hook.EnvCommand + "=" + name}
+}
+
+// startOutputHook sends the command's output through the output hook, if
+// one is set, as it is written.
+func startOutputHook(cmd *cobra.Command) {
+	hookStart = time.Now()
+	command := hookCommand(cmd, hook.EnvOutput)
+	if command == "" {
+		return
+	}
+	hookOut = cmd.OutOrStdout()
+	hookWriter = hook.NewWriter(cmd.Context(), command, hookEnv(cmd), hookOut, os.Stderr)
+	cmd.SetOut(hookWriter)
+}
+
+// finishOutputHook ends the output hook's input and waits for it to write
+// the rest of its output. It runs for a command that failed too, so a
+// report printed before a non-zero exit is still post-processed.
+func finishOutputHook(cmd *cobra.Command) error {
+	if hookWriter == nil {
+		return nil
+	}
+	w := hookWriter
+	hookWriter = nil
+	cmd.SetOut(hookOut)
+	return w.Close()
+}
+
+// exportFile is an export the command wrote and its format.
+type exportFile struct {
+	path   string
+	format string
+}
+
+// runExportHooks runs the export hook once for each export file the
+// command wrote, with the file and its format in the environment.
+func runExportHooks(cmd *cobra.Command) error {
+	command := hookCommand(cmd, hook.EnvExport)
+	if command == "" {
+		return nil
+	}
+	for _, f := range exportFiles(cmd) {
+		env := append(hookEnv(cmd), hook.EnvFile+"="+f.path, hook.EnvFormat+"="+f.format)
+		if err := hook.Run(cmd.Context(), command, env, os.Stderr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportFiles lists the export files named by the command's flags that it
+// wrote, skipping any left from an earlier run.
+func exportFiles(cmd *cobra.Command) []exportFile {
+	var files []exportFile
+	seen := make(map[string]bool)
+	add := func(path, format string) {
+		if path == "" || seen[path] {
+			return
+		}
+		info, err := os.Stat(path)
+		// File times may be stored to the second.
+		if err != nil || !info.Mode().IsRegular() || info.ModTime().Before(hookStart.Truncate(time.Second)) {
+			return
+		}
+		seen[path] = true
+		files = append(files, exportFile{path: path, format: format})
+	}
+	// Looked up on the command, so a local flag shadowing an export flag
+	// (as triage --ris does) is found too.
+	for _, name := range []string{"csv", "tsv", "ris", "bibtex"} {
+		if fl := cmd.Flags().Lookup(name); fl != nil {
+			add(fl.Value.String(), name)
+		}
+	}
+	add(outXLSX, "xlsx")
+	add(outCSL, "csl-json")
+	if flagNDJSON {
+		add(outFile, "ndjson")
+	} else {
+		add(outFile, "json")
+	}
+	return files
+}
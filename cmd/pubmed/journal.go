@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/journal"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/spf13/cobra"
+)
+
+// defaultJournalLimit is how many catalog matches journal shows without --limit.
+const defaultJournalLimit = 5
+
+var flagJournalYears int
+
+// journalCmd looks up journals in the NLM Catalog.
+var journalCmd = &cobra.Command{
+	Use:   "journal <name|ISSN>",
+	Short: "Look up a journal in the NLM Catalog",
+	Long: `Resolve a journal title, abbreviation, or ISSN through the NLM Catalog and
+show its MEDLINE (ISO) abbreviation, ISSNs, publisher, whether MEDLINE
+currently indexes it, and its PubMed article counts for the last --years
+years (0 skips the counts). Exact matches are listed first.
+
+Use pubmed journal check to validate the journal names in a query.`,
+	Example: `  pubmed journal "Journal of Neurodevelopmental Disorders" --human
+  pubmed journal 1866-1955 --json
+  pubmed journal check '"fragile x"[mh] AND "J Neurodev Disord"[ta]'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagJournalYears < 0 {
+			return errUsage("--years must not be negative")
+		}
+		limit := defaultJournalLimit
+		if cmd.Flags().Changed("limit") {
+			limit = flagLimit
+		}
+
+		base := newBaseClient()
+		journals, err := journal.NewClient(base).Lookup(cmd.Context(), strings.Join(args, " "), limit)
+		if err != nil {
+			return fmt.Errorf("journal lookup failed: %w", err)
+		}
+
+		if flagJournalYears > 0 {
+			to := time.Now().Year()
+			from := to - flagJournalYears + 1
+			counter := eutils.NewClientWithBase(base)
+			for i, j := range journals {
+				clause := j.PubMedClause()
+				if clause == "" {
+					continue
+				}
+				trends, err := analyze.Trends(cmd.Context(), counter, []string{clause}, from, to)
+				if err != nil {
+					return fmt.Errorf("article counts failed: %w", err)
+				}
+				journals[i].Counts = trends[0].Counts
+			}
+		}
+		return output.FormatJournals(cmd.OutOrStdout(), journals, outputCfg())
+	},
+}
+
+var journalCheckCmd = &cobra.Command{
+	Use:   "check <query>",
+	Short: "Check that a query's journal names match indexed titles",
+	Long: `Find the journal terms in a PubMed query ([ta], [journal], [jt], [is]) and
+look each up in the NLM Catalog. Terms that are not an exact title,
+abbreviation, or ISSN are reported with the closest journal and the clause
+to use instead; PubMed silently returns nothing for a misspelled [ta].`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		terms := query.JournalTerms(strings.Join(args, " "))
+		checks, err := journal.NewClient(newBaseClient()).CheckTerms(cmd.Context(), terms)
+		if err != nil {
+			return fmt.Errorf("journal check failed: %w", err)
+		}
+		return output.FormatJournalChecks(cmd.OutOrStdout(), checks, outputCfg())
+	},
+}
+
+func init() {
+	journalCmd.Flags().IntVar(&flagJournalYears, "years", 5, "Years of PubMed article counts to show (0 to skip)")
+	journalCmd.AddCommand(journalCheckCmd)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagLibPMIDs string
+	flagLibQuery string
+	flagLibAll   bool
+)
+
+// libCmd groups the local library commands.
+var libCmd = &cobra.Command{
+	Use:   "lib",
+	Short: "Keep named collections of articles across sessions",
+	Long: `Collect articles into named collections kept in the user database, pubmed.db
+under the pubmed-cli config directory (~/.config/pubmed-cli on Linux; override with
+PUBMED_CLI_CONFIG_DIR). Article metadata is stored with the collection, so
+showing or exporting one needs no network access. export and screen take
+--collection to work on a collection instead of a live query.`,
+	Example: `  pubmed lib add fxs-trials 38000001 38000002
+  pubmed lib add fxs-trials --query "fragile x syndrome" --type trial --limit 50
+  pubmed search "fragile x" --ids-only | pubmed lib add fxs -
+  pubmed lib show fxs-trials --human
+  pubmed lib search "calcium signaling"
+  pubmed export --collection fxs-trials --out fxs.ris`,
+}
+
+var libAddCmd = &cobra.Command{
+	Use:   "add <collection> [pmid|doi...]",
+	Short: "Add articles to a collection, creating it if needed",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lib, err := loadLibrary()
+		if err != nil {
+			return err
+		}
+		articles, err := libArticles(cmd, lib, args[1:])
+		if err != nil {
+			return err
+		}
+		added, err := lib.Add(args[0], articles, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := saveLibrary(lib); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Added %d articles to %q (%d total)\n", added, args[0], len(lib.Get(args[0]).PMIDs))
+		return nil
+	},
+}
+
+var libRemoveCmd = &cobra.Command{
+	Use:   "remove <collection> [pmid...]",
+	Short: "Remove articles from a collection, or the collection itself with --all",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lib, err := loadLibrary()
+		if err != nil {
+			return err
+		}
+		name := args[0]
+		switch {
+		case flagLibAll && len(args) > 1:
+			return errUsage("give PMIDs or --all, not both")
+		case flagLibAll:
+			if err := lib.Delete(name); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted collection %q\n", name)
+		case len(args) == 1:
+			return errUsage("give the PMIDs to remove, or --all to delete the collection")
+		default:
+			ids, err := stdinArgs(cmd, args[1:])
+			if err != nil {
+				return err
+			}
+			pmids, err := normalizePMIDArgs(ids)
+			if err != nil {
+				return err
+			}
+			removed, err := lib.Remove(name, pmids, time.Now())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %d articles from %q\n", removed, name)
+		}
+		return saveLibrary(lib)
+	},
+}
+
+var libListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List collections",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lib, err := loadLibrary()
+		if err != nil {
+			return err
+		}
+		return output.FormatCollections(cmd.OutOrStdout(), lib.Collections, outputCfg())
+	},
+}
+
+var libShowCmd = &cobra.Command{
+	Use:   "show <collection>",
+	Short: "Print or export a collection's articles",
+	Long: `Print a collection's articles from the stored metadata. Output and export
+flags work as for fetch (--human, --json, --ris, --out refs.bib, ...).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		articles, err := collectionArticles(args[0])
+		if err != nil {
+			return err
+		}
+		return output.FormatArticles(cmd.OutOrStdout(), articles, outputCfg())
+	},
+}
+
+var libSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the stored articles and notes offline",
+	Long: `Rank every article stored in the library, across all collections, and every
+article with notes or tags, by how well its title, abstract, MeSH terms,
+keywords, notes, and tags match the query. Every word must match; word
+endings are ignored ("signaling" finds "signals"), and a trailing * matches
+a prefix. No network access is needed. --limit caps the hits; output and
+export flags work as for fetch.`,
+	Example: `  pubmed lib search "calcium signaling"
+  pubmed lib search "metformin fragile" --human
+  pubmed lib search "neuro*" --ris hits.ris`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lib, err := loadLibrary()
+		if err != nil {
+			return err
+		}
+		hits := lib.Search(strings.Join(args, " "))
+		if len(hits) > flagLimit {
+			hits = hits[:flagLimit]
+		}
+		articles := make([]eutils.Article, len(hits))
+		for i, h := range hits {
+			articles[i] = h.Article
+		}
+		lib.Annotate(articles)
+		return output.FormatArticles(cmd.OutOrStdout(), articles, outputCfg())
+	},
+}
+
+func init() {
+	libAddCmd.Flags().StringVar(&flagLibPMIDs, "pmids", "", "Comma-separated PMIDs or DOIs to add, or - to read them from stdin")
+	libAddCmd.Flags().StringVar(&flagLibQuery, "query", "", "Add the results of this PubMed query (--limit caps them)")
+	libRemoveCmd.Flags().BoolVar(&flagLibAll, "all", false, "Delete the whole collection")
+
+	for _, c := range []*cobra.Command{libAddCmd, libRemoveCmd, libShowCmd} {
+		c.ValidArgsFunction = completeCollectionNames
+	}
+	libCmd.AddCommand(libAddCmd, libRemoveCmd, libListCmd, libShowCmd, libSearchCmd)
+}
+
+// annotateArticles attaches the library's notes and tags to articles so
+// cards and exports include them. An unreadable library only warns.
+func annotateArticles(articles []eutils.Article) {
+	if len(articles) == 0 {
+		return
+	}
+	lib, err := loadLibrary()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: notes and tags unavailable: %v\n", err)
+		return
+	}
+	lib.Annotate(articles)
+}
+
+// collectionArticles loads the named collection's stored articles.
+func collectionArticles(name string) ([]eutils.Article, error) {
+	lib, err := loadLibrary()
+	if err != nil {
+		return nil, err
+	}
+	articles, err := lib.CollectionArticles(name)
+	if err != nil {
+		return nil, err
+	}
+	lib.Annotate(articles)
+	return articles, nil
+}
+
+// libArticles resolves the articles lib add was given: PMID or DOI
+// arguments, --pmids, or --query. Metadata already in the library is not refetched.
+func libArticles(cmd *cobra.Command, lib *library.Library, args []string) ([]eutils.Article, error) {
+	if countSet(len(args) > 0, flagLibPMIDs != "", flagLibQuery != "") != 1 {
+		return nil, errUsage("give PMIDs as arguments, --pmids, or --query")
+	}
+	if flagLibQuery != "" {
+		return exportQueryArticles(cmd, buildQuery([]string{flagLibQuery}))
+	}
+
+	if len(args) == 0 {
+		args = []string{flagLibPMIDs}
+	}
+	ids, err := stdinArgs(cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	client := newEutilsClient()
+	pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[string]eutils.Article)
+	if missing := lib.Missing(pmids); len(missing) > 0 {
+		batch, err := fetchInBatches(cmd.Context(), client, missing)
+		if err != nil {
+			return nil, fmt.Errorf("fetch failed: %w", err)
+		}
+		for _, a := range batch {
+			fetched[a.PMID] = a
+		}
+	}
+	articles := make([]eutils.Article, 0, len(pmids))
+	for _, id := range pmids {
+		a, ok := lib.Articles[id]
+		if !ok {
+			if a, ok = fetched[id]; !ok {
+				fmt.Fprintf(os.Stderr, "Warning: PMID %s not found in PubMed; skipped\n", id)
+				continue
+			}
+		}
+		articles = append(articles, a)
+	}
+	return articles, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/spf13/cobra"
+)
+
+var flagNoLint bool
+
+// lintCmd checks a query before it is run.
+var lintCmd = &cobra.Command{
+	Use:   "lint <query>",
+	Short: "Check a query for mistakes PubMed silently ignores",
+	Long: `Check a PubMed query for unbalanced quotes, parentheses, and brackets,
+field tags PubMed does not know (checked against EInfo's field list),
+Boolean operators with nothing to combine, lowercase or/not, and mixed
+AND and OR without parentheses. The query is then sent to PubMed, which
+reports phrases and fields it could not use, and the translation it
+searched is shown word by word against the query: "-" marks what PubMed
+dropped and "+" what it added.
+
+PubMed runs a query with these mistakes anyway and returns a result set
+that looks plausible. search and export run the same offline checks and
+warn on stderr; --no-lint turns that off.
+
+Exits with code 5 when the query has errors.`,
+	Example: `  pubmed lint '"fragile x syndrome"[mh] AND sleep[tiabs]'
+  pubmed lint '(autism OR asd AND sleep' --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q := strings.Join(args, " ")
+		client := newEutilsClient()
+
+		fields, err := client.SearchFields(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: checking field tags against the built-in list: %v\n", err)
+		}
+		report := &query.LintReport{Query: q, Problems: query.Lint(q, fields)}
+
+		result, err := client.Search(cmd.Context(), q, &eutils.SearchOptions{Limit: 1})
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		report.Problems = append(report.Problems, query.NoticeProblems(result.Notices)...)
+		if result.QueryTranslation != "" {
+			report.Translation = result.QueryTranslation
+			report.Diff = query.TranslationDiff(q, result.QueryTranslation)
+		}
+
+		if err := output.FormatLint(cmd.OutOrStdout(), report, outputCfg()); err != nil {
+			return err
+		}
+		if n := report.Errors(); n > 0 {
+			return withExitCode(exitFlagged, fmt.Errorf("the query has %d error(s)", n))
+		}
+		return nil
+	},
+}
+
+// warnQueryProblems runs the offline query checks and warns on stderr
+// about what they find, unless --no-lint is set.
+func warnQueryProblems(q string) {
+	if flagNoLint {
+		return
+	}
+	for _, p := range query.Lint(q, nil) {
+		msg := p.Message
+		if p.Hint != "" {
+			msg += " (" + p.Hint + ")"
+		}
+		fmt.Fprintf(os.Stderr, "Warning: query %s: %s\n", p.Severity, msg)
+	}
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{searchCmd, exportCmd} {
+		cmd.Flags().BoolVar(&flagNoLint, "no-lint", false, "Do not warn about mistakes in the query")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/email"
+)
+
+var flagEmail string
+
+func init() {
+	alertRunCmd.Flags().StringVar(&flagEmail, "email", "", "Email an HTML digest of new results to these comma-separated addresses (needs "+email.EnvHost+")")
+}
+
+// emailConfig reads the SMTP settings when --email is set, so a missing
+// setting fails before any alert is checked and marked seen.
+func emailConfig() (email.Config, []string, error) {
+	if flagEmail == "" {
+		return email.Config{}, nil, nil
+	}
+	var to []string
+	for _, addr := range strings.Split(flagEmail, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	if len(to) == 0 {
+		return email.Config{}, nil, errUsage("--email needs at least one address")
+	}
+	secretEnv(email.EnvPassword)
+	c, err := email.ConfigFromEnv()
+	return c, to, err
+}
+
+// mailDigest sends the digest of results to to. Failures are warnings:
+// the results were already printed.
+func mailDigest(c email.Config, to []string, results []alert.Result, now time.Time) {
+	if flagOffline {
+		fmt.Fprintln(os.Stderr, "Warning: not sending the email digest with --offline")
+		return
+	}
+	subject, text, html, err := email.AlertDigest(results, now)
+	if err == nil {
+		err = email.Send(c, email.Message{From: c.From, To: to, Subject: subject, Text: text, HTML: html, Date: now})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
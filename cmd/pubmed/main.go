@@ -2,32 +2,89 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/x/ansi"
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/clipboard"
+	"github.com/henrybloomingdale/pubmed-cli/internal/crossref"
 	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/i18n"
+	"github.com/henrybloomingdale/pubmed-cli/internal/icite"
 	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
 	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+	"github.com/henrybloomingdale/pubmed-cli/internal/openalex"
 	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refine"
+	"github.com/henrybloomingdale/pubmed-cli/internal/semanticscholar"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagJSON   bool
-	flagHuman  bool
-	flagFull   bool
-	flagCSV    string
-	flagRIS    string
-	flagLimit  int
-	flagSort   string
-	flagYear   string
-	flagType   string
-	flagAPIKey string
+	flagJSON       bool
+	flagNDJSON     bool
+	flagHuman      bool
+	flagFull       bool
+	flagCSV        string
+	flagTSV        string
+	flagColumns    string
+	flagRIS        string
+	flagBibTeX     string
+	flagOut        string
+	flagOutFmt     string
+	flagLimit      int
+	flagSort       string
+	flagSortLoc    string
+	flagYear       string
+	flagSince      string
+	flagUntil      string
+	flagType       string
+	flagAPIKey     string
+	flagNoCache    bool
+	flagOffline    bool
+	flagRecord     bool
+	flagNoProgress bool
+	flagNoColor    bool
+	flagTheme      string
+	flagClip       bool
+	flagASCII      bool
+	flagVerbose    bool
+	flagLang       string
+
+	flagMeshExpand bool
+	flagIDsOnly    bool
+	flagHighlight  string
+	flagCrossref   bool
+	flagOA         bool
+	flagOpenAlex   bool
+	flagS2         bool
+	flagICite      bool
 )
 
+// Targets resolved from --out by resolveOutFlag. Most formats reuse the
+// --csv, --tsv, --ris, and --bibtex paths; these cover the rest.
+var (
+	outXLSX    string   // Excel export path
+	outCSL     string   // CSL-JSON export path
+	outFile    string   // JSON/NDJSON destination that replaces stdout
+	outFileRef *os.File // open handle for outFile, closed after the command runs
+)
+
+// clipBuf collects the command's stdout output for --clip.
+var clipBuf *bytes.Buffer
+
 const (
 	projectName = "pubmed-cli"
 	projectURL  = "https://github.com/drpedapati/pubmed-cli"
@@ -41,11 +98,36 @@ var allowedSorts = map[string]struct{}{
 	"relevance": {},
 	"date":      {},
 	"cited":     {},
+	"citations": {},
+	"influence": {},
+	"rcr":       {},
+}
+
+// externalSorts are the --sort orders PubMed cannot produce, applied to
+// search results from another service's metrics.
+var externalSorts = map[string]struct{}{
+	"citations": {},
+	"influence": {},
+	"rcr":       {},
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	flushCaches()
+	closeUserDB()
+	if err != nil {
+		// A failed command skips the post-run step, so its output has not
+		// been through the output hook yet.
+		if herr := finishOutputHook(cmd); herr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", herr)
+		}
+		code := exitCode(err)
+		reportError(os.Stderr, cmd, err, code)
+		os.Exit(code)
+	}
+	if missing := offlineMissing(); len(missing) > 0 {
+		fmt.Fprint(os.Stderr, "Warning: some results are incomplete. ")
+		writeOfflineMissing(os.Stderr, missing)
 	}
 }
 
@@ -53,8 +135,57 @@ var rootCmd = &cobra.Command{
 	Use:   "pubmed",
 	Short: "pubmed-cli: production-focused PubMed E-utilities CLI",
 	Long:  `pubmed-cli is a production-focused command-line interface for searching and retrieving articles from NCBI PubMed using the E-utilities API.`,
+	// main reports errors itself, with an exit code for each kind.
+	SilenceErrors: true,
+	SilenceUsage:  true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return validateGlobalFlags(cmd)
+		argsChecked = true
+		if commandGroup(cmd) != "config" {
+			if err := applyConfig(cmd); err != nil {
+				return err
+			}
+		}
+		if err := validateGlobalFlags(cmd); err != nil {
+			return withExitCode(exitUsage, err)
+		}
+		if err := i18n.Set(flagLang); err != nil {
+			return withExitCode(exitUsage, fmt.Errorf("--lang: %w", err))
+		}
+		if outFile != "" {
+			f, err := os.Create(outFile)
+			if err != nil {
+				return fmt.Errorf("creating --out file: %w", err)
+			}
+			outFileRef = f
+			cmd.SetOut(f)
+		}
+		if flagClip {
+			clipBuf = &bytes.Buffer{}
+			cmd.SetOut(io.MultiWriter(cmd.OutOrStdout(), clipBuf))
+		}
+		startOutputHook(cmd)
+		return output.ConfigureStyles(styleOptions())
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if err := finishOutputHook(cmd); err != nil {
+			return err
+		}
+		if outFileRef != nil {
+			err := outFileRef.Close()
+			outFileRef = nil
+			if err != nil {
+				return err
+			}
+		}
+		if clipBuf != nil {
+			text := ansi.Strip(clipBuf.String())
+			clipBuf = nil
+			if err := clipboard.Copy(cmd.Context(), text); err != nil {
+				return fmt.Errorf("--clip: %w", err)
+			}
+			fmt.Fprintln(os.Stderr, "Copied to clipboard")
+		}
+		return runExportHooks(cmd)
 	},
 }
 
@@ -64,76 +195,253 @@ func init() {
 	rootCmd.SetHelpTemplate(rootCmd.HelpTemplate() + cliHelpFooter())
 
 	rootCmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "Output as structured JSON")
+	rootCmd.PersistentFlags().BoolVar(&flagNDJSON, "ndjson", false, "Output one JSON object per line (search, fetch, link commands)")
 	rootCmd.PersistentFlags().BoolVarP(&flagHuman, "human", "H", false, "Rich colorful terminal output")
 	rootCmd.PersistentFlags().BoolVar(&flagFull, "full", false, "Show full abstract (with --human)")
 	rootCmd.PersistentFlags().StringVar(&flagCSV, "csv", "", "Export results to CSV file")
+	rootCmd.PersistentFlags().StringVar(&flagTSV, "tsv", "", "Export results to TSV file")
+	rootCmd.PersistentFlags().StringVar(&flagColumns, "columns", "", "Article fields and order for --csv/--tsv (e.g. pmid,year,title,doi,mesh)")
 	rootCmd.PersistentFlags().StringVar(&flagRIS, "ris", "", "Export results to RIS file")
+	rootCmd.PersistentFlags().StringVar(&flagBibTeX, "bibtex", "", "Export results to BibTeX file")
+	rootCmd.PersistentFlags().StringVarP(&flagOut, "out", "o", "", "Write results to FILE, with the format inferred from its extension")
+	rootCmd.PersistentFlags().StringVar(&flagOutFmt, "out-format", "", "Format for --out: "+strings.Join(output.OutFormats, ", ")+" (default from extension)")
 	rootCmd.PersistentFlags().IntVar(&flagLimit, "limit", 20, "Maximum number of results")
-	rootCmd.PersistentFlags().StringVar(&flagSort, "sort", "", "Sort order: relevance, date, or cited")
+	rootCmd.PersistentFlags().StringVar(&flagSort, "sort", "", "Sort order: relevance, date, cited, citations (OpenAlex), influence (Semantic Scholar), or rcr (iCite); the last three for search only")
+	rootCmd.PersistentFlags().StringVar(&flagSortLoc, "sort-local", "", "Reorder fetched records: "+strings.Join(eutils.LocalSortOrders, ", ")+" (search, fetch, and export)")
 	rootCmd.PersistentFlags().StringVar(&flagYear, "year", "", "Filter by year range (e.g., 2020-2025)")
+	rootCmd.PersistentFlags().StringVar(&flagSince, "since", "", "Only records published since DATE: 30d, 6w, 6m, 2y, today, or YYYY[/MM[/DD]]")
+	rootCmd.PersistentFlags().StringVar(&flagUntil, "until", "", "Only records published up to DATE: today, yesterday, 6m, or YYYY[/MM[/DD]]")
 	rootCmd.PersistentFlags().StringVar(&flagType, "type", "", "Filter by publication type (review, trial, meta-analysis)")
 	rootCmd.PersistentFlags().StringVar(&flagAPIKey, "api-key", "", "NCBI API key (or set NCBI_API_KEY env var)")
+	rootCmd.PersistentFlags().BoolVar(&flagNoCache, "no-cache", false, "Bypass the on-disk response cache")
+	rootCmd.PersistentFlags().BoolVar(&flagOffline, "offline", false, "Make no network requests: answer from the response cache, the offline MeSH database, and the library")
+	rootCmd.PersistentFlags().BoolVar(&flagRecord, "record", false, "Record API responses in the response cache for later --offline runs")
+	rootCmd.PersistentFlags().BoolVar(&flagNoHooks, "no-hooks", false, "Do not run the configured output and export hooks")
+	rootCmd.PersistentFlags().BoolVar(&flagNoProgress, "no-progress", false, "Do not report the progress of long fetches and checks on stderr")
+	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Log each NCBI and API request (parameters, status, latency) and rate-limit wait to stderr")
+	rootCmd.PersistentFlags().BoolVar(&flagNoColor, "no-color", false, "Disable colors in --human output (also honors NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&flagASCII, "ascii", false, "Transliterate article text to ASCII (é→e, α→alpha) for legacy reference managers")
+	rootCmd.PersistentFlags().BoolVar(&flagClip, "clip", false, "Also copy the printed output to the system clipboard")
+	rootCmd.PersistentFlags().StringVar(&flagLang, "lang", "", "Language for --human search, fetch, link, MeSH, and full-text results: "+strings.Join(i18n.Languages, ", ")+" (default from LC_ALL, LC_MESSAGES, or LANG)")
+	rootCmd.PersistentFlags().StringVar(&flagTheme, "theme", "", "Color theme for --human output: dark or light (default $PUBMED_CLI_THEME or dark)")
+
+	searchCmd.Flags().BoolVar(&flagMeshExpand, "mesh-expand", false, "Expand free-text concepts to MeSH descriptors and entry terms")
+	searchCmd.Flags().BoolVar(&flagIDsOnly, "ids-only", false, "Print only the matching PMIDs, one per line (for piping into fetch, cite, or export -)")
+	fetchCmd.Flags().BoolVar(&flagS2, "s2", false, "Add influential citation counts and TLDR summaries from Semantic Scholar")
+	fetchCmd.Flags().BoolVar(&flagICite, "icite", false, "Add Relative Citation Ratio, NIH percentile, and APT scores from NIH iCite")
+	fetchCmd.Flags().BoolVar(&flagOpenAlex, "openalex", false, "Add citation counts, concepts, and institutions from OpenAlex")
+	fetchCmd.Flags().BoolVar(&flagOA, "oa", false, "Add open-access status and best copy from Unpaywall (needs UNPAYWALL_EMAIL)")
+	fetchCmd.Flags().BoolVar(&flagCrossref, "crossref", false, "Fill gaps from Crossref and resolve DOIs that are not in PubMed")
+	fetchCmd.Flags().StringVar(&flagHighlight, "highlight", "", "Highlight this query's terms in --human titles and abstracts")
+
+	searchCmd.ValidArgsFunction = completeQueryTerms
+	trendsCmd.ValidArgsFunction = completeQueryTerms
+	rootCmd.RegisterFlagCompletionFunc("type", completePublicationTypes)
+	rootCmd.RegisterFlagCompletionFunc("columns", completeColumns)
+	rootCmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions([]string{"relevance", "date", "cited", "citations", "influence", "rcr"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("sort-local", cobra.FixedCompletions(eutils.LocalSortOrders, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("out-format", cobra.FixedCompletions(output.OutFormats, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("lang", cobra.FixedCompletions(i18n.Languages, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("theme", cobra.FixedCompletions([]string{"dark", "light"}, cobra.ShellCompDirectiveNoFileComp))
 
 	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(refineCmd)
 	rootCmd.AddCommand(fetchCmd)
+	rootCmd.AddCommand(fulltextCmd)
+	rootCmd.AddCommand(pdfCmd)
+	rootCmd.AddCommand(citeCmd)
+	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(citedByCmd)
 	rootCmd.AddCommand(referencesCmd)
 	rootCmd.AddCommand(relatedCmd)
 	rootCmd.AddCommand(meshCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(grantsCmd)
+	rootCmd.AddCommand(triageCmd)
+	rootCmd.AddCommand(bulkFetchCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(networkCmd)
+	rootCmd.AddCommand(timelineCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(summarizeCmd)
+	rootCmd.AddCommand(picoCmd)
+	rootCmd.AddCommand(batchCmd)
+	rootCmd.AddCommand(trendsCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(libCmd)
+	rootCmd.AddCommand(noteCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(alertCmd)
+	rootCmd.AddCommand(todayCmd)
+	rootCmd.AddCommand(zoteroCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(dedupeCmd)
+	rootCmd.AddCommand(screenCmd)
+	rootCmd.AddCommand(journalCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(refcheckCmd)
+	rootCmd.AddCommand(retractionsCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(schemaCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
+// styleOptions resolves human-output styling from flags and the environment:
+// NO_COLOR (https://no-color.org) or --no-color disables color, TERM=dumb
+// also falls back to ASCII glyphs, and PUBMED_CLI_THEME sets the default theme.
+func styleOptions() output.StyleOptions {
+	dumb := os.Getenv("TERM") == "dumb"
+	theme := flagTheme
+	if theme == "" {
+		theme = os.Getenv("PUBMED_CLI_THEME")
+	}
+	return output.StyleOptions{
+		Color: !flagNoColor && os.Getenv("NO_COLOR") == "" && !dumb,
+		ASCII: dumb,
+		Theme: strings.ToLower(theme),
+	}
+}
+
 func outputCfg() output.OutputConfig {
 	return output.OutputConfig{
-		JSON:    flagJSON,
-		Human:   flagHuman,
-		Full:    flagFull,
-		CSVFile: flagCSV,
-		RISFile: flagRIS,
+		JSON:       flagJSON,
+		NDJSON:     flagNDJSON,
+		Human:      flagHuman,
+		Full:       flagFull,
+		CSVFile:    flagCSV,
+		TSVFile:    flagTSV,
+		XLSXFile:   outXLSX,
+		Columns:    exportColumns(),
+		RISFile:    flagRIS,
+		BibTeXFile: flagBibTeX,
+		CSLFile:    outCSL,
+		ASCII:      flagASCII,
+	}
+}
+
+// exportColumns returns the --columns selection, already checked by
+// validateGlobalFlags, or nil for each table's default columns.
+func exportColumns() []string {
+	if flagColumns == "" {
+		return nil
 	}
+	columns, _ := output.ParseColumns(flagColumns)
+	return columns
 }
 
 func newBaseClient() *ncbi.BaseClient {
 	apiKey := flagAPIKey
 	if apiKey == "" {
-		apiKey = os.Getenv("NCBI_API_KEY")
+		apiKey = secretEnv("NCBI_API_KEY")
 	}
 	var opts []ncbi.Option
 	if apiKey != "" {
 		opts = append(opts, ncbi.WithAPIKey(apiKey))
 	}
-	return ncbi.NewBaseClient(opts...)
+	if flagVerbose {
+		opts = append(opts, ncbi.WithLog(os.Stderr))
+	}
+	// --record keeps responses for --offline runs, which replay them however
+	// old they are. Without it nothing is written, so the cache only grows
+	// when asked to.
+	if flagRecord || flagOffline {
+		if store := responseCache(0); store != nil {
+			opts = append(opts, ncbi.WithCache(store))
+		}
+	}
+	if flagOffline {
+		opts = append(opts, ncbi.WithOffline())
+	}
+	c := ncbi.NewBaseClient(opts...)
+	baseClients = append(baseClients, c)
+	return c
 }
 
 func newEutilsClient() *eutils.Client {
-	return eutils.NewClientWithBase(newBaseClient())
+	c := eutils.NewClientWithBase(newBaseClient())
+	if flagOffline {
+		c.Local = libraryArticles()
+	}
+	return c
 }
 
-func newMeshClient() *mesh.Client {
-	return mesh.NewClient(newBaseClient())
+func newOpenAlexClient() *openalex.Client {
+	return openalex.NewClient(newBaseClient(), os.Getenv(openalex.EnvMailto))
 }
 
-func buildQuery(args []string) string {
-	query := strings.Join(args, " ")
+func newSemanticScholarClient() *semanticscholar.Client {
+	return semanticscholar.NewClient(newBaseClient(), secretEnv(semanticscholar.EnvAPIKey))
+}
 
-	// Add publication type filter — multi-word types must be quoted.
-	if flagType != "" {
-		typeMap := map[string]string{
-			"review":        `"review"[pt]`,
-			"trial":         `"clinical trial"[pt]`,
-			"meta-analysis": `"meta-analysis"[pt]`,
-			"randomized":    `"randomized controlled trial"[pt]`,
-			"case-report":   `"case reports"[pt]`,
-		}
-		if mapped, ok := typeMap[strings.ToLower(flagType)]; ok {
-			query += " AND " + mapped
-		} else {
-			query += fmt.Sprintf(` AND "%s"[pt]`, flagType)
+func newICiteClient() *icite.Client {
+	return icite.NewClient(newBaseClient())
+}
+
+func newMeshClient() *mesh.Client {
+	var opts []mesh.ClientOption
+	if dir, err := meshLocalDir(); err == nil {
+		db, err := mesh.OpenLocalDB(dir)
+		switch {
+		case err == nil:
+			opts = append(opts, mesh.WithLocalDB(db))
+		case !errors.Is(err, mesh.ErrNoLocalDB):
+			fmt.Fprintf(os.Stderr, "Warning: ignoring local MeSH database: %v\n", err)
 		}
 	}
+	if store := responseCache(mesh.CacheTTL); store != nil {
+		opts = append(opts, mesh.WithCache(store))
+	}
+	// MeSH keeps its own response cache, which online runs read too.
+	base := newBaseClient()
+	base.Cache = nil
+	return mesh.NewClient(base, opts...)
+}
 
-	return query
+// responseCache returns the shared on-disk NCBI response cache, or nil when
+// disabled with --no-cache or when no cache directory is available. Under
+// --offline entries never expire.
+func responseCache(ttl time.Duration) *cache.Store {
+	if flagNoCache {
+		return nil
+	}
+	if flagOffline {
+		ttl = 0
+	}
+	dir, err := cache.Subdir(responsesSection)
+	if err != nil {
+		return nil
+	}
+	store := cache.NewStore(dir, ttl)
+	openStores = append(openStores, store)
+	return store
+}
+
+// publicationTypes maps --type shorthands to PubMed [pt] clauses.
+var publicationTypes = map[string]string{
+	"review":        `"review"[pt]`,
+	"trial":         `"clinical trial"[pt]`,
+	"meta-analysis": `"meta-analysis"[pt]`,
+	"randomized":    `"randomized controlled trial"[pt]`,
+	"case-report":   `"case reports"[pt]`,
+}
+
+func buildQuery(args []string) string {
+	return withPubType(strings.Join(args, " "), flagType)
+}
+
+// withPubType adds a publication type filter to query; multi-word types
+// must be quoted.
+func withPubType(query, pubType string) string {
+	if pubType == "" {
+		return query
+	}
+	if mapped, ok := publicationTypes[strings.ToLower(pubType)]; ok {
+		return query + " AND " + mapped
+	}
+	return query + fmt.Sprintf(` AND "%s"[pt]`, pubType)
 }
 
 func parseYearRange(value string) (string, string, error) {
@@ -173,9 +481,34 @@ func validateGlobalFlags(cmd *cobra.Command) error {
 		return fmt.Errorf("--limit must be greater than 0")
 	}
 
+	if err := resolveOutFlag(cmd); err != nil {
+		return err
+	}
+
+	if flagOffline && flagNoCache {
+		return fmt.Errorf("--offline answers from the cache, so it cannot be combined with --no-cache")
+	}
+	if flagRecord && (flagOffline || flagNoCache) {
+		return fmt.Errorf("--record writes to the response cache from the network, so it cannot be combined with --offline or --no-cache")
+	}
+
 	if flagSort != "" {
 		if _, ok := allowedSorts[strings.ToLower(flagSort)]; !ok {
-			return fmt.Errorf("--sort must be one of: relevance, date, cited")
+			return fmt.Errorf("--sort must be one of: relevance, date, cited, citations, influence, rcr")
+		}
+		if _, ok := externalSorts[strings.ToLower(flagSort)]; ok && commandGroup(cmd) != "search" {
+			return fmt.Errorf("--sort %s is only supported for search", strings.ToLower(flagSort))
+		}
+	}
+
+	if flagSortLoc != "" {
+		if !slices.Contains(eutils.LocalSortOrders, flagSortLoc) {
+			return fmt.Errorf("--sort-local must be one of: %s", strings.Join(eutils.LocalSortOrders, ", "))
+		}
+		switch commandGroup(cmd) {
+		case "search", "fetch", "export":
+		default:
+			return fmt.Errorf("--sort-local is only supported for search, fetch, and export")
 		}
 	}
 
@@ -183,18 +516,132 @@ func validateGlobalFlags(cmd *cobra.Command) error {
 		if _, _, err := parseYearRange(flagYear); err != nil {
 			return fmt.Errorf("--year %q is invalid: %w", flagYear, err)
 		}
+		if flagSince != "" || flagUntil != "" {
+			return fmt.Errorf("--year cannot be combined with --since or --until")
+		}
+	}
+	if _, _, err := sinceUntil(time.Now()); err != nil {
+		return err
 	}
 
-	if flagRIS != "" {
-		switch cmd.Name() {
-		case "search", "mesh":
-			return fmt.Errorf("--ris is not supported for %q; use fetch, cited-by, references, or related", cmd.Name())
+	if flagNDJSON {
+		if flagJSON {
+			return fmt.Errorf("--json and --ndjson are mutually exclusive")
+		}
+		switch commandGroup(cmd) {
+		case "search", "fetch", "cited-by", "references", "related", "export":
+		default:
+			return fmt.Errorf("--ndjson is not supported for %q; use search, fetch, cited-by, references, or related", cmd.Name())
+		}
+	}
+
+	if flagColumns != "" {
+		// A saved default is simply unused by a run without a table export.
+		if flagCSV == "" && flagTSV == "" && outXLSX == "" && !configDefaults["columns"] {
+			return fmt.Errorf("--columns requires --csv, --tsv, or a table --out")
+		}
+		switch commandGroup(cmd) {
+		case "search", "fetch", "export", "dedupe":
+		default:
+			return fmt.Errorf("--columns is not supported for %q; use search, fetch, export, or dedupe", cmd.Name())
+		}
+		if _, err := output.ParseColumns(flagColumns); err != nil {
+			return fmt.Errorf("--columns is invalid: %w", err)
+		}
+	}
+
+	citationExports := []struct{ flag, style, value string }{
+		{"--ris", "ris", flagRIS},
+		{"--bibtex", "bibtex", flagBibTeX},
+		{"CSL-JSON --out", "csl-json", outCSL},
+	}
+	for _, e := range citationExports {
+		if e.value == "" || citationCommands[strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")] {
+			continue
+		}
+		if commandGroup(cmd) == "cite" {
+			return fmt.Errorf("%s is not supported for %q; use --style %s", e.flag, cmd.Name(), e.style)
 		}
+		return fmt.Errorf("%s is not supported for %q; use search, fetch, cited-by, references, or related", e.flag, cmd.Name())
 	}
 
 	return nil
 }
 
+// citationCommands are the commands, by path below the root, that write
+// the --ris, --bibtex, and CSL-JSON --out exports. Every other command
+// rejects them, so a new command does not silently ignore them.
+var citationCommands = map[string]bool{
+	"search":        true,
+	"fetch":         true,
+	"cited-by":      true,
+	"references":    true,
+	"related":       true,
+	"export":        true,
+	"dedupe":        true,
+	"today":         true,
+	"alert run":     true,
+	"alert diff":    true,
+	"history rerun": true,
+	"lib show":      true,
+	"lib search":    true,
+	"screen log":    true,
+}
+
+// resolveOutFlag maps --out onto the export it names, inferring the format
+// from the file extension unless --out-format overrides it. JSON and NDJSON
+// are written where stdout would go.
+func resolveOutFlag(cmd *cobra.Command) error {
+	if flagOut == "" {
+		// graph has its own --out and reads --out-format as its --format fallback.
+		if flagOutFmt != "" && commandGroup(cmd) != "graph" {
+			return fmt.Errorf("--out-format requires --out")
+		}
+		return nil
+	}
+
+	format, err := output.OutFormat(flagOut, flagOutFmt)
+	if err != nil {
+		return fmt.Errorf("--out: %w", err)
+	}
+
+	targets := map[string]struct {
+		flag string
+		path *string
+	}{
+		"csv":      {"--csv", &flagCSV},
+		"tsv":      {"--tsv", &flagTSV},
+		"xlsx":     {"", &outXLSX},
+		"ris":      {"--ris", &flagRIS},
+		"bibtex":   {"--bibtex", &flagBibTeX},
+		"csl-json": {"", &outCSL},
+		"json":     {"", &outFile},
+		"ndjson":   {"", &outFile},
+	}
+	t := targets[format]
+	if *t.path != "" && *t.path != flagOut {
+		return fmt.Errorf("--out and %s both set a %s export; use one", t.flag, format)
+	}
+	*t.path = flagOut
+
+	switch format {
+	case "json":
+		flagJSON = true
+	case "ndjson":
+		flagNDJSON = true
+	}
+	return nil
+}
+
+// commandGroup returns the top-level command name, so subcommands such as
+// "mesh tree" validate the same way as their parent.
+func commandGroup(cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
+
 func cliBrandingText() string {
 	return fmt.Sprintf("%s %s\nGitHub: %s\nIssues: %s", projectName, version, projectURL, issuesURL)
 }
@@ -205,12 +652,12 @@ func cliHelpFooter() string {
 
 func validatePMID(pmid string) error {
 	if pmid == "" {
-		return fmt.Errorf("PMID cannot be empty")
+		return errUsage("PMID cannot be empty")
 	}
 
 	for _, r := range pmid {
 		if r < '0' || r > '9' {
-			return fmt.Errorf("PMID %q is invalid: only digits are allowed", pmid)
+			return errUsage("PMID %q is invalid: only digits are allowed", pmid)
 		}
 	}
 
@@ -227,7 +674,7 @@ func parsePMIDArg(pmidArg string) ([]string, error) {
 	for _, p := range raw {
 		p = strings.TrimSpace(p)
 		if p == "" {
-			return nil, fmt.Errorf("PMID cannot be empty")
+			return nil, errUsage("PMID cannot be empty")
 		}
 		if err := validatePMID(p); err != nil {
 			return nil, err
@@ -238,6 +685,17 @@ func parsePMIDArg(pmidArg string) ([]string, error) {
 	return pmids, nil
 }
 
+// countSet reports how many of a command's mutually exclusive inputs were given.
+func countSet(given ...bool) int {
+	n := 0
+	for _, g := range given {
+		if g {
+			n++
+		}
+	}
+	return n
+}
+
 func normalizePMIDArgs(args []string) ([]string, error) {
 	normalized := make([]string, 0, len(args))
 	for _, arg := range args {
@@ -251,6 +709,35 @@ func normalizePMIDArgs(args []string) ([]string, error) {
 	return normalized, nil
 }
 
+// stdinArgs replaces a "-" argument with the IDs read from stdin, so
+// commands compose with search --ids-only.
+func stdinArgs(cmd *cobra.Command, args []string) ([]string, error) {
+	var out []string
+	read := false
+	for _, arg := range args {
+		if arg != "-" {
+			out = append(out, arg)
+			continue
+		}
+		if read {
+			return nil, fmt.Errorf("- can only be given once")
+		}
+		read = true
+		ids, err := readIDs(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		out = append(out, ids...)
+	}
+	return out, nil
+}
+
+// isStdinArg reports whether args is just "-", which commands that
+// otherwise take a query read as a PMID list on stdin.
+func isStdinArg(args []string) bool {
+	return len(args) == 1 && args[0] == "-"
+}
+
 // searchCmd implements the search subcommand.
 var searchCmd = &cobra.Command{
 	Use:   "search <query>",
@@ -259,71 +746,321 @@ var searchCmd = &cobra.Command{
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := newEutilsClient()
-		query := buildQuery(args)
-		cfg := outputCfg()
+		q := buildQuery(args)
+		warnQueryProblems(strings.Join(args, " "))
+
+		if flagMeshExpand {
+			expanded, expansions, err := query.Expand(cmd.Context(), strings.Join(args, " "), newMeshClient())
+			if err != nil {
+				return fmt.Errorf("MeSH expansion failed: %w", err)
+			}
+			for _, e := range expansions {
+				fmt.Fprintf(os.Stderr, "Expanded %q → %s (%s)\n", e.Concept, e.Descriptor, e.UI)
+			}
+			q = buildQuery([]string{expanded})
+		}
 
 		opts := &eutils.SearchOptions{
 			Limit: flagLimit,
 			Sort:  strings.ToLower(flagSort),
 		}
 
-		if flagYear != "" {
-			minDate, maxDate, err := parseYearRange(flagYear)
+		var err error
+		if opts.MinDate, opts.MaxDate, err = dateRange(); err != nil {
+			return err
+		}
+
+		result, err := runSearch(cmd, client, q, opts)
+		if err != nil {
+			return err
+		}
+		if result.Count == 0 {
+			relaxed, err := suggestRelaxed(cmd, client, q, opts)
 			if err != nil {
-				return fmt.Errorf("invalid --year value %q: %w", flagYear, err)
+				return err
+			}
+			if relaxed == nil || relaxed.Count == 0 {
+				return errNoResults("no articles match %s", q)
 			}
-			opts.MinDate = minDate
-			opts.MaxDate = maxDate
 		}
+		return nil
+	},
+}
 
-		result, err := client.Search(cmd.Context(), query, opts)
+// sortExternally reorders ids by an external sort's metric and returns a
+// function that adds the metric's data to the fetched articles.
+func sortExternally(ctx context.Context, order string, ids []string) (func([]eutils.Article), error) {
+	switch order {
+	case "citations":
+		works, err := newOpenAlexClient().Works(ctx, ids)
 		if err != nil {
-			return fmt.Errorf("search failed: %w", err)
+			return nil, err
 		}
+		openalex.SortByCitations(ids, works)
+		return func(articles []eutils.Article) {
+			for i := range articles {
+				if w, ok := works[articles[i].PMID]; ok {
+					openalex.Enrich(&articles[i], w)
+				}
+			}
+		}, nil
+	case "influence":
+		papers, err := newSemanticScholarClient().Papers(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		semanticscholar.SortByInfluence(ids, papers)
+		return func(articles []eutils.Article) {
+			for i := range articles {
+				if p, ok := papers[articles[i].PMID]; ok {
+					semanticscholar.Enrich(&articles[i], p)
+				}
+			}
+		}, nil
+	case "rcr":
+		pubs, err := newICiteClient().Pubs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		icite.SortByRCR(ids, pubs)
+		return func(articles []eutils.Article) {
+			for i := range articles {
+				if p, ok := pubs[articles[i].PMID]; ok {
+					icite.Enrich(&articles[i], p)
+				}
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sort %q", order)
+}
 
-		// Auto-fetch articles for --human or --csv (rich table/export)
-		var articles []eutils.Article
-		if (cfg.Human || cfg.CSVFile != "") && len(result.IDs) > 0 {
-			articles, err = client.Fetch(cmd.Context(), result.IDs)
-			if err != nil {
-				// Non-fatal: fall back to PMID-only display
-				fmt.Fprintf(os.Stderr, "Warning: could not fetch article details: %v\n", err)
-				articles = nil
+// sortLocally applies --sort-local to fetched articles and returns ids in
+// the same order, any without a fetched article left at the end.
+func sortLocally(articles []eutils.Article, ids []string) []string {
+	eutils.SortArticles(articles, flagSortLoc) // order checked in validateGlobalFlags
+	sorted := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(articles))
+	for _, a := range articles {
+		sorted = append(sorted, a.PMID)
+		seen[a.PMID] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			sorted = append(sorted, id)
+		}
+	}
+	return sorted
+}
+
+// runSearch runs a search, records it in the history, and prints the
+// result (fetching article details when the output needs them).
+func runSearch(cmd *cobra.Command, client *eutils.Client, q string, opts *eutils.SearchOptions) (*eutils.SearchResult, error) {
+	cfg := outputCfg()
+	_, external := externalSorts[opts.Sort]
+	searchOpts := opts
+	if external {
+		// PubMed cannot sort by these: take its most relevant records and
+		// reorder them by the external metric.
+		relevance := *opts
+		relevance.Sort = ""
+		searchOpts = &relevance
+	}
+	result, err := client.Search(cmd.Context(), q, searchOpts)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	recordSearch(q, opts, result)
+
+	var enrich func([]eutils.Article)
+	if external && len(result.IDs) > 0 {
+		if enrich, err = sortExternally(cmd.Context(), opts.Sort, result.IDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if flagIDsOnly && flagSortLoc == "" {
+		for _, id := range result.IDs {
+			fmt.Fprintln(cmd.OutOrStdout(), id)
+		}
+		return result, nil
+	}
+
+	// Auto-fetch articles for --human, exports, or --ndjson (rich table/export/stream)
+	var articles []eutils.Article
+	citations := cfg.RISFile != "" || cfg.BibTeXFile != "" || cfg.CSLFile != ""
+	if (cfg.Human || cfg.CSVFile != "" || cfg.TSVFile != "" || cfg.XLSXFile != "" || cfg.NDJSON || citations || flagSortLoc != "") && len(result.IDs) > 0 {
+		articles, err = client.Fetch(cmd.Context(), result.IDs)
+		if err != nil && citations {
+			return nil, fmt.Errorf("failed to fetch articles for citation export: %w", err)
+		}
+		if err != nil {
+			// Non-fatal: fall back to PMID-only display
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch article details: %v\n", err)
+			articles = nil
+		}
+		annotateArticles(articles)
+		if enrich != nil {
+			enrich(articles)
+			articles = orderArticles(articles, result.IDs)
+		}
+		if flagSortLoc != "" && len(articles) > 0 {
+			result.IDs = sortLocally(articles, result.IDs)
+		}
+	}
+	if flagIDsOnly {
+		for _, id := range result.IDs {
+			fmt.Fprintln(cmd.OutOrStdout(), id)
+		}
+		return result, nil
+	}
+
+	if cfg.Human && len(articles) > 0 {
+		translation := result.QueryTranslation
+		if translation == "" {
+			translation = q
+		}
+		cfg.Highlight = highlightTerms(cmd.Context(), translation)
+		if !flagNoSuggest {
+			for _, t := range refine.MissingMeSH(q, result.QueryTranslation, articles, maxSuggestions) {
+				cfg.Suggestions = append(cfg.Suggestions, output.Suggestion{
+					Clause: fmt.Sprintf(`"%s"[mh]`, t.Term), Articles: t.Count, Sample: len(articles)})
 			}
 		}
+	}
 
-		return output.FormatSearchResult(os.Stdout, result, articles, cfg)
-	},
+	return result, output.FormatSearchResult(cmd.OutOrStdout(), result, articles, cfg)
 }
 
 // fetchCmd implements the fetch subcommand.
 var fetchCmd = &cobra.Command{
-	Use:   "fetch <pmid> [pmid...]",
+	Use:   "fetch <pmid|doi> [pmid|doi...]",
 	Short: "Fetch full article details",
-	Long:  `Retrieve full article details including abstract, authors, DOI, and MeSH terms for one or more PMIDs.`,
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Retrieve full article details including abstract, authors, DOI, and MeSH terms
+for one or more PMIDs or DOIs. Give - to read them from stdin, separated by
+whitespace or commas.
+
+--crossref fills gaps in each record from Crossref (a missing DOI, found by
+title; page range, volume, issue, license, and reference count) and
+resolves DOIs that are not in PubMed to Crossref records. Filled fields are
+listed in crossref_fields, and Crossref-only records have source "crossref"
+and no PMID. Set ` + crossref.EnvMailto + ` to use Crossref's polite pool.
+
+--oa adds each article's open-access status (oa_status) and best
+open-access copy (oa_url) from Unpaywall, which needs UNPAYWALL_EMAIL.
+--openalex adds citation counts, concept tags, and author institutions from
+OpenAlex (set ` + openalex.EnvMailto + ` to use its polite pool). --s2 adds
+Semantic Scholar's influential citation count and one-sentence TLDR summary
+(set ` + semanticscholar.EnvAPIKey + ` for a higher rate limit). --icite adds
+NIH iCite's Relative Citation Ratio, its NIH percentile, and the Approximate
+Potential to Translate score (rcr, nih_percentile, apt).
+
+When some records cannot be fetched, the rest are still printed, the IDs
+left out are listed on stderr, and the command exits non-zero (4 when a
+request failed).`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := stdinArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		var oa *fulltext.Client
+		if flagOA {
+			if oa, err = newOAClient(); err != nil {
+				return err
+			}
+		}
 		client := newEutilsClient()
-		pmids, err := normalizePMIDArgs(args)
+		pmids, unmatched, err := resolveIDs(cmd.Context(), client, ids)
 		if err != nil {
-			return fmt.Errorf("invalid PMID(s): %w", err)
+			return err
+		}
+		if len(unmatched) > 0 && !flagCrossref {
+			return fmt.Errorf("DOI %s was not found in PubMed (add --crossref to resolve it there)", unmatched[0])
+		}
+		if len(pmids) == 0 && len(unmatched) == 0 {
+			return errUsage("at least one PMID or DOI is required")
 		}
 
-		articles, err := client.Fetch(cmd.Context(), pmids)
-		if err != nil {
-			return fmt.Errorf("fetch failed: %w", err)
+		var articles []eutils.Article
+		var partial *eutils.FetchErrors
+		if len(pmids) > 0 {
+			articles, err = client.FetchAll(cmd.Context(), pmids, fetchBatchSize)
+			if err != nil && !errors.As(err, &partial) {
+				return fmt.Errorf("fetch failed: %w", err)
+			}
+		}
+		annotateArticles(articles)
+		if flagCrossref {
+			articles = enrichFromCrossref(cmd.Context(), articles, unmatched)
+		}
+		if oa != nil {
+			annotateOA(cmd.Context(), oa, articles)
+		}
+		if flagOpenAlex {
+			if err := newOpenAlexClient().Annotate(cmd.Context(), articles); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		if flagS2 {
+			if err := newSemanticScholarClient().Annotate(cmd.Context(), articles); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		if flagICite {
+			if err := newICiteClient().Annotate(cmd.Context(), articles); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		if flagSortLoc != "" {
+			eutils.SortArticles(articles, flagSortLoc)
 		}
 
-		return output.FormatArticles(os.Stdout, articles, outputCfg())
+		cfg := outputCfg()
+		if cfg.Human && flagHighlight != "" {
+			cfg.Highlight = highlightTerms(cmd.Context(), flagHighlight)
+		}
+
+		if err := output.FormatArticles(cmd.OutOrStdout(), articles, cfg); err != nil {
+			return err
+		}
+		if partial != nil {
+			return fetchIncomplete(partial)
+		}
+		if len(articles) == 0 {
+			return errNoResults("none of the IDs were found")
+		}
+		return nil
 	},
 }
 
+// fetchIncomplete warns which IDs fetch left out and returns the error that
+// ends the run: a service failure when a request failed, and otherwise a
+// plain one for IDs that are not PMIDs.
+func fetchIncomplete(fe *eutils.FetchErrors) error {
+	ids := fe.PMIDs()
+	fmt.Fprintf(os.Stderr, "Warning: not fetched: %s\n", strings.Join(ids, ", "))
+	err := fmt.Errorf("fetch incomplete: %w", fe)
+	if len(fe.Failed) > 0 {
+		return withExitCode(exitService, err)
+	}
+	return err
+}
+
 // citedByCmd implements the cited-by subcommand.
 var citedByCmd = &cobra.Command{
 	Use:   "cited-by <pmid>",
 	Short: "Find papers that cite this article",
-	Long:  `Find papers in PubMed that cite the given article.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Find papers in PubMed that cite the given article.
+
+--stance reads the PMC full text of the first --limit citing papers, finds
+the sentences that cite the article, and classifies each paper's citation
+as supporting, contrasting, or mentioning from cue phrases such as
+"consistent with" or "in contrast to". Papers without PMC full text are
+reported as unknown. The classification is a triage aid; read the quoted
+sentences before relying on it.`,
+	Example: `  pubmed cited-by 38000001 --human
+  pubmed cited-by 38000001 --stance --limit 50 --csv stances.csv`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := validatePMID(args[0]); err != nil {
 			return fmt.Errorf("invalid PMID: %w", err)
@@ -335,6 +1072,9 @@ var citedByCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("cited-by lookup failed: %w", err)
 		}
+		if flagStance {
+			return runStance(cmd, client, args[0], result)
+		}
 
 		return formatLinkResults(cmd, client, result, "cited-by")
 	},
@@ -384,18 +1124,57 @@ var relatedCmd = &cobra.Command{
 	},
 }
 
+// maxHighlightLookups caps the MeSH lookups made for highlight synonyms.
+const maxHighlightLookups = 3
+
+// highlightTerms returns the terms of q (a query or its PubMed translation)
+// to highlight in human output, adding entry-term synonyms for MeSH
+// descriptors. A failed lookup only costs that descriptor's synonyms.
+func highlightTerms(ctx context.Context, q string) []string {
+	var (
+		terms   []string
+		lookups int
+		client  *mesh.Client
+	)
+	for _, t := range query.HighlightTerms(q) {
+		terms = append(terms, t.Text)
+		if !t.MeSH || lookups >= maxHighlightLookups {
+			continue
+		}
+		if client == nil {
+			client = newMeshClient()
+		}
+		lookups++
+		record, err := client.Lookup(ctx, t.Text)
+		if err != nil {
+			continue
+		}
+		for _, entry := range record.EntryTerms {
+			// Inverted forms ("Syndrome, Fragile X") never occur in running text.
+			if !strings.Contains(entry, ", ") {
+				terms = append(terms, entry)
+			}
+		}
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	return terms
+}
+
 // formatLinkResults handles output for link commands, fetching article details for human mode.
 func formatLinkResults(cmd *cobra.Command, client *eutils.Client, result *eutils.LinkResult, linkType string) error {
 	cfg := outputCfg()
 
-	// If RIS export is requested with no links, still create/clear the target file.
-	if len(result.Links) == 0 && cfg.RISFile != "" {
-		if err := output.FormatArticles(io.Discard, []eutils.Article{}, output.OutputConfig{RISFile: cfg.RISFile}); err != nil {
-			return fmt.Errorf("RIS export failed: %w", err)
+	citeCfg := output.OutputConfig{RISFile: cfg.RISFile, BibTeXFile: cfg.BibTeXFile, CSLFile: cfg.CSLFile}
+	citations := cfg.RISFile != "" || cfg.BibTeXFile != "" || cfg.CSLFile != ""
+
+	// If a citation export is requested with no links, still create/clear the target file.
+	if len(result.Links) == 0 && citations {
+		if err := output.FormatArticles(io.Discard, []eutils.Article{}, citeCfg); err != nil {
+			return err
 		}
 	}
 
-	needsArticles := cfg.Human || cfg.RISFile != ""
+	needsArticles := cfg.Human || citations
 
 	var (
 		articles []eutils.Article
@@ -403,7 +1182,7 @@ func formatLinkResults(cmd *cobra.Command, client *eutils.Client, result *eutils
 		fetchErr error
 	)
 
-	// For human and/or RIS mode, fetch article details for linked IDs.
+	// For human mode and/or citation exports, fetch article details for linked IDs.
 	if needsArticles && len(result.Links) > 0 {
 		limit = flagLimit
 		if limit > len(result.Links) {
@@ -415,37 +1194,39 @@ func formatLinkResults(cmd *cobra.Command, client *eutils.Client, result *eutils
 		}
 
 		articles, fetchErr = client.Fetch(cmd.Context(), pmids)
+		annotateArticles(articles)
 	}
 
-	if cfg.RISFile != "" {
+	if citations {
 		if fetchErr != nil {
-			return fmt.Errorf("failed to export RIS: %w", fetchErr)
+			return fmt.Errorf("failed to export citations: %w", fetchErr)
 		}
-		if err := output.FormatArticles(io.Discard, articles, output.OutputConfig{RISFile: cfg.RISFile}); err != nil {
-			return fmt.Errorf("RIS export failed: %w", err)
+		if err := output.FormatArticles(io.Discard, articles, citeCfg); err != nil {
+			return err
 		}
 	}
 
-	// For JSON or plain text, output links after optional RIS export.
-	if cfg.JSON || !cfg.Human {
-		return output.FormatLinks(os.Stdout, result, linkType, cfg)
+	// For JSON, NDJSON, or plain text, output links after optional citation export.
+	if cfg.JSON || cfg.NDJSON || !cfg.Human {
+		return output.FormatLinks(cmd.OutOrStdout(), result, linkType, cfg)
 	}
 
 	if len(result.Links) == 0 {
-		return output.FormatLinks(os.Stdout, result, linkType, cfg)
+		return output.FormatLinks(cmd.OutOrStdout(), result, linkType, cfg)
 	}
 
 	if fetchErr != nil {
 		// Fall back to PMID-only display if fetch fails in human mode.
-		return output.FormatLinks(os.Stdout, result, linkType, cfg)
+		return output.FormatLinks(cmd.OutOrStdout(), result, linkType, cfg)
 	}
 
+	articles = output.NormalizeArticles(articles, flagASCII)
 	articleMap := make(map[string]eutils.Article)
 	for _, a := range articles {
 		articleMap[a.PMID] = a
 	}
 
-	return output.FormatLinksWithArticles(os.Stdout, result, articles, articleMap, linkType, limit)
+	return output.FormatLinksWithArticles(cmd.OutOrStdout(), result, articles, articleMap, linkType, limit)
 }
 
 // meshCmd implements the mesh subcommand.
@@ -463,7 +1244,7 @@ var meshCmd = &cobra.Command{
 			return fmt.Errorf("MeSH lookup failed: %w", err)
 		}
 
-		return output.FormatMeSHRecord(os.Stdout, record, outputCfg())
+		return output.FormatMeSHRecord(cmd.OutOrStdout(), record, outputCfg())
 	},
 }
 
@@ -1,17 +1,48 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refine"
+	"github.com/henrybloomingdale/pubmed-cli/internal/semanticscholar"
+	"github.com/henrybloomingdale/pubmed-cli/internal/webhook"
 	"github.com/spf13/cobra"
 )
 
 func resetGlobalFlags() {
 	flagType = ""
 	flagYear = ""
+	flagSince = ""
+	flagUntil = ""
 	flagSort = ""
 	flagRIS = ""
+	flagBibTeX = ""
+	flagJSON = false
+	flagNDJSON = false
+	flagCSV = ""
+	flagTSV = ""
+	flagColumns = ""
+	flagOut = ""
+	flagOutFmt = ""
+	outXLSX = ""
+	outCSL = ""
+	outFile = ""
 	flagLimit = 20
 }
 
@@ -150,6 +181,19 @@ func TestValidateGlobalFlags(t *testing.T) {
 		t.Fatal("expected error for invalid sort")
 	}
 
+	resetGlobalFlags()
+	flagSort = "citations"
+	if err := validateGlobalFlags(&cobra.Command{Use: "search"}); err != nil {
+		t.Fatalf("unexpected error for --sort citations on search: %v", err)
+	}
+	if err := validateGlobalFlags(&cobra.Command{Use: "export"}); err == nil {
+		t.Fatal("expected --sort citations to be rejected outside search")
+	}
+	flagSort = "influence"
+	if err := validateGlobalFlags(&cobra.Command{Use: "timeline"}); err == nil {
+		t.Fatal("expected --sort influence to be rejected outside search")
+	}
+
 	resetGlobalFlags()
 	flagYear = "2025-2020"
 	if err := validateGlobalFlags(&cobra.Command{Use: "search"}); err == nil {
@@ -168,8 +212,8 @@ func TestValidateGlobalFlags(t *testing.T) {
 func TestValidateGlobalFlags_RISScope(t *testing.T) {
 	resetGlobalFlags()
 	flagRIS = "/tmp/out.ris"
-	if err := validateGlobalFlags(&cobra.Command{Use: "search"}); err == nil {
-		t.Fatal("expected --ris to be rejected for search")
+	if err := validateGlobalFlags(&cobra.Command{Use: "search"}); err != nil {
+		t.Fatalf("expected --ris to be accepted for search, got: %v", err)
 	}
 
 	resetGlobalFlags()
@@ -178,6 +222,17 @@ func TestValidateGlobalFlags_RISScope(t *testing.T) {
 		t.Fatal("expected --ris to be rejected for mesh")
 	}
 
+	resetGlobalFlags()
+	flagRIS = "/tmp/out.ris"
+	root := &cobra.Command{Use: "pubmed"}
+	meshParent := &cobra.Command{Use: "mesh"}
+	tree := &cobra.Command{Use: "tree"}
+	root.AddCommand(meshParent)
+	meshParent.AddCommand(tree)
+	if err := validateGlobalFlags(tree); err == nil {
+		t.Fatal("expected --ris to be rejected for mesh subcommands")
+	}
+
 	resetGlobalFlags()
 	flagRIS = "/tmp/out.ris"
 	if err := validateGlobalFlags(&cobra.Command{Use: "fetch"}); err != nil {
@@ -185,6 +240,177 @@ func TestValidateGlobalFlags_RISScope(t *testing.T) {
 	}
 }
 
+func TestValidateGlobalFlags_CitationAllowlist(t *testing.T) {
+	for path := range citationCommands {
+		c, _, err := rootCmd.Find(strings.Fields(path))
+		if err != nil || strings.TrimPrefix(c.CommandPath(), "pubmed ") != path {
+			t.Errorf("citationCommands lists %q, which is not a command", path)
+		}
+	}
+
+	resetGlobalFlags()
+	defer resetGlobalFlags()
+	flagRIS = "/tmp/out.ris"
+	root := &cobra.Command{Use: "pubmed"}
+	lib := &cobra.Command{Use: "lib"}
+	show, added := &cobra.Command{Use: "show"}, &cobra.Command{Use: "new-command"}
+	root.AddCommand(lib, added)
+	lib.AddCommand(show)
+	if err := validateGlobalFlags(show); err != nil {
+		t.Errorf("expected --ris to be accepted for lib show, got: %v", err)
+	}
+	if err := validateGlobalFlags(added); err == nil {
+		t.Error("expected --ris to be rejected for a command not on the allowlist")
+	}
+}
+
+func TestValidateGlobalFlags_BibTeXScope(t *testing.T) {
+	resetGlobalFlags()
+	flagBibTeX = "/tmp/out.bib"
+	for _, use := range []string{"search", "fetch", "related"} {
+		if err := validateGlobalFlags(&cobra.Command{Use: use}); err != nil {
+			t.Errorf("expected --bibtex to be accepted for %s, got: %v", use, err)
+		}
+	}
+	if err := validateGlobalFlags(&cobra.Command{Use: "mesh"}); err == nil {
+		t.Error("expected --bibtex to be rejected for mesh")
+	}
+	resetGlobalFlags()
+}
+
+func TestValidateGlobalFlags_NDJSON(t *testing.T) {
+	resetGlobalFlags()
+	flagNDJSON = true
+	for _, use := range []string{"search", "fetch", "cited-by", "references", "related"} {
+		if err := validateGlobalFlags(&cobra.Command{Use: use}); err != nil {
+			t.Errorf("expected --ndjson to be accepted for %s, got: %v", use, err)
+		}
+	}
+	if err := validateGlobalFlags(&cobra.Command{Use: "mesh"}); err == nil {
+		t.Error("expected --ndjson to be rejected for mesh")
+	}
+
+	flagJSON = true
+	if err := validateGlobalFlags(&cobra.Command{Use: "fetch"}); err == nil {
+		t.Error("expected --json with --ndjson to be rejected")
+	}
+	resetGlobalFlags()
+}
+
+func TestValidateGlobalFlags_Columns(t *testing.T) {
+	resetGlobalFlags()
+	flagColumns = "pmid,year,title"
+	if err := validateGlobalFlags(&cobra.Command{Use: "fetch"}); err == nil {
+		t.Error("expected --columns without an export file to be rejected")
+	}
+
+	flagCSV = "/tmp/out.csv"
+	if err := validateGlobalFlags(&cobra.Command{Use: "fetch"}); err != nil {
+		t.Errorf("expected --columns to be accepted for fetch, got: %v", err)
+	}
+	if err := validateGlobalFlags(&cobra.Command{Use: "dedupe"}); err != nil {
+		t.Errorf("expected --columns to be accepted for dedupe, got: %v", err)
+	}
+	if err := validateGlobalFlags(&cobra.Command{Use: "mesh"}); err == nil {
+		t.Error("expected --columns to be rejected for mesh")
+	}
+
+	flagColumns = "pmid,citations"
+	if err := validateGlobalFlags(&cobra.Command{Use: "fetch"}); err == nil {
+		t.Error("expected unknown column to be rejected")
+	}
+	resetGlobalFlags()
+}
+
+func TestValidateGlobalFlags_Out(t *testing.T) {
+	t.Cleanup(resetGlobalFlags)
+
+	tests := []struct {
+		out, format string
+		cmd         *cobra.Command
+		check       func() bool
+	}{
+		{"refs.csv", "", fetchCmd, func() bool { return flagCSV == "refs.csv" }},
+		{"refs.bib", "", searchCmd, func() bool { return flagBibTeX == "refs.bib" }},
+		{"refs.xlsx", "", meshCmd, func() bool { return outXLSX == "refs.xlsx" }},
+		{"results.json", "", meshCmd, func() bool { return flagJSON && outFile == "results.json" }},
+		{"results.txt", "ndjson", fetchCmd, func() bool { return flagNDJSON && outFile == "results.txt" }},
+	}
+	for _, tt := range tests {
+		resetGlobalFlags()
+		flagOut, flagOutFmt = tt.out, tt.format
+		if err := validateGlobalFlags(tt.cmd); err != nil {
+			t.Errorf("--out %s: unexpected error: %v", tt.out, err)
+			continue
+		}
+		if !tt.check() {
+			t.Errorf("--out %s: export not resolved", tt.out)
+		}
+	}
+
+	resetGlobalFlags()
+	flagOut = "refs.ris"
+	if err := validateGlobalFlags(meshCmd); err == nil {
+		t.Error("expected RIS --out to be rejected for mesh")
+	}
+
+	resetGlobalFlags()
+	flagOut, flagCSV = "a.csv", "b.csv"
+	if err := validateGlobalFlags(fetchCmd); err == nil {
+		t.Error("expected --out and --csv conflict")
+	}
+
+	resetGlobalFlags()
+	flagOut = "refs.docx"
+	if err := validateGlobalFlags(fetchCmd); err == nil {
+		t.Error("expected unknown extension to be rejected")
+	}
+
+	resetGlobalFlags()
+	flagOutFmt = "csv"
+	if err := validateGlobalFlags(fetchCmd); err == nil {
+		t.Error("expected --out-format without --out to be rejected")
+	}
+	if err := validateGlobalFlags(graphCmd); err != nil {
+		t.Errorf("graph should accept --out-format: %v", err)
+	}
+
+	resetGlobalFlags()
+	flagOut, flagColumns = "refs.xlsx", "pmid,title"
+	if err := validateGlobalFlags(fetchCmd); err != nil {
+		t.Errorf("--columns with an .xlsx --out: unexpected error: %v", err)
+	}
+}
+
+func TestStyleOptions(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("PUBMED_CLI_THEME", "Light")
+	flagNoColor, flagTheme = false, ""
+	defer func() { flagNoColor, flagTheme = false, "" }()
+
+	opts := styleOptions()
+	if !opts.Color || opts.ASCII || opts.Theme != "light" {
+		t.Errorf("expected color with light theme from env, got %+v", opts)
+	}
+
+	flagTheme = "dark"
+	if opts := styleOptions(); opts.Theme != "dark" {
+		t.Errorf("expected --theme to override env, got %q", opts.Theme)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if opts := styleOptions(); opts.Color {
+		t.Error("expected NO_COLOR to disable color")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "dumb")
+	if opts := styleOptions(); opts.Color || !opts.ASCII {
+		t.Errorf("expected dumb terminal to use plain ASCII, got %+v", opts)
+	}
+}
+
 func TestNormalizePMIDArgs(t *testing.T) {
 	pmids, err := normalizePMIDArgs([]string{"38000001, 38000002", "38000003"})
 	if err != nil {
@@ -236,3 +462,602 @@ func TestCLIHelpFooterIncludesIssueLocation(t *testing.T) {
 		t.Fatalf("help footer missing issues URL: %q", footer)
 	}
 }
+
+func TestSchemaCmd(t *testing.T) {
+	var buf bytes.Buffer
+	schemaCmd.SetOut(&buf)
+	t.Cleanup(func() { schemaCmd.SetOut(nil) })
+
+	if err := schemaCmd.RunE(schemaCmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "fetch") || !strings.Contains(buf.String(), output.SchemaVersion) {
+		t.Fatalf("schema list missing entries: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := schemaCmd.RunE(schemaCmd, []string{"search"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"schema_version"`) {
+		t.Fatalf("search schema missing schema_version: %q", buf.String())
+	}
+	if err := schemaCmd.RunE(schemaCmd, []string{"qa"}); err == nil {
+		t.Fatal("expected unknown schema error")
+	}
+}
+
+func TestResolveIDArgs_PMIDs(t *testing.T) {
+	pmids, err := resolveIDArgs(nil, nil, []string{"111,222", " 333 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(pmids, " ") != "111 222 333" {
+		t.Fatalf("unexpected PMIDs: %v", pmids)
+	}
+
+	if _, err := resolveIDArgs(nil, nil, []string{"not-an-id"}); err == nil {
+		t.Fatal("expected invalid argument error")
+	}
+
+	for _, doi := range []string{"10.1186/s11689-024-00001-1", "doi:10.1000/xyz", "https://doi.org/10.1000/XYZ"} {
+		if !doiArgRe.MatchString(doi) {
+			t.Errorf("expected %q to be recognized as a DOI", doi)
+		}
+	}
+	if doiArgRe.MatchString("38000001") {
+		t.Error("PMID should not be recognized as a DOI")
+	}
+}
+
+func TestOrderArticles(t *testing.T) {
+	articles := []eutils.Article{{PMID: "2"}, {PMID: "1"}}
+	ordered := orderArticles(articles, []string{"1", "3", "2"})
+	if len(ordered) != 2 || ordered[0].PMID != "1" || ordered[1].PMID != "2" {
+		t.Fatalf("unexpected order: %+v", ordered)
+	}
+}
+
+func TestReadIDs(t *testing.T) {
+	ids, err := readIDs(strings.NewReader("111\n222,333\n\n  444 \n10.1000/xyz,\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ids, " ") != "111 222 333 444 10.1000/xyz" {
+		t.Fatalf("unexpected IDs: %v", ids)
+	}
+
+	if _, err := resolveIDArgs(nil, nil, []string{"111", "abc"}); err == nil {
+		t.Fatal("expected invalid ID error")
+	}
+}
+
+func TestStdinArgs(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader("111\n10.1000/xyz, 222\n"))
+	ids, err := stdinArgs(cmd, []string{"999", "-", "333"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ids, " ") != "999 111 10.1000/xyz 222 333" {
+		t.Fatalf("unexpected IDs: %v", ids)
+	}
+
+	if _, err := stdinArgs(cmd, []string{"-", "-"}); err == nil {
+		t.Fatal("expected error for repeated -")
+	}
+	if !isStdinArg([]string{"-"}) || isStdinArg([]string{"-", "x"}) || isStdinArg([]string{"autism"}) {
+		t.Fatal("isStdinArg misclassified its arguments")
+	}
+}
+
+func TestServeKeys(t *testing.T) {
+	t.Cleanup(func() { flagServeKeysFile = "" })
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte("# team keys\nalpha\n\n  beta  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envServeKeys, "gamma, ")
+	flagServeKeysFile = path
+
+	keys, err := serveKeys()
+	if err != nil {
+		t.Fatalf("serveKeys: %v", err)
+	}
+	if strings.Join(keys, ",") != "gamma,alpha,beta" {
+		t.Errorf("keys = %q", keys)
+	}
+}
+
+func TestLoopbackAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:80":   true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+	} {
+		if got := loopbackAddr(addr); got != want {
+			t.Errorf("loopbackAddr(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestHistoryEntry(t *testing.T) {
+	entries := []history.Entry{{ID: 1, Query: "a"}, {ID: 2, Query: "b"}}
+	for _, arg := range []string{"2", "#2"} {
+		if e, err := historyEntry(entries, arg); err != nil || e.Query != "b" {
+			t.Errorf("historyEntry(%q) = %+v, %v", arg, e, err)
+		}
+	}
+	for _, arg := range []string{"", "x", "0", "3"} {
+		if _, err := historyEntry(entries, arg); err == nil {
+			t.Errorf("historyEntry(%q): expected error", arg)
+		}
+	}
+}
+
+func TestTodayQuery(t *testing.T) {
+	t.Setenv(alert.EnvConfigDir, t.TempDir())
+	book, err := loadAlerts()
+	if err != nil {
+		t.Fatalf("loading alerts: %v", err)
+	}
+	if _, err := book.Add("fxs", "fragile x syndrome", time.Now()); err != nil {
+		t.Fatalf("adding alert: %v", err)
+	}
+	if err := saveAlerts(book); err != nil {
+		t.Fatalf("saving alerts: %v", err)
+	}
+	t.Cleanup(func() { flagTodayDays, flagTodayJournal = alert.DefaultDays, "" })
+	resetGlobalFlags()
+	flagTodayDays = 1
+
+	tests := []struct {
+		args         []string
+		journal      string
+		topic, query string
+	}{
+		{[]string{"fxs"}, "", "fxs", "fragile x syndrome"},
+		{[]string{"autism", "eeg"}, "", "autism eeg", "autism eeg"},
+		{nil, "Brain", "Brain", `"Brain"[ta]`},
+		{[]string{"fxs"}, "Brain", "fxs in Brain", `(fragile x syndrome) AND "Brain"[ta]`},
+	}
+	for _, tt := range tests {
+		flagTodayJournal = tt.journal
+		topic, q, err := todayQuery(tt.args)
+		if err != nil || topic != tt.topic || q != tt.query {
+			t.Errorf("todayQuery(%q, journal %q) = %q, %q, %v; want %q, %q", tt.args, tt.journal, topic, q, err, tt.topic, tt.query)
+		}
+	}
+
+	flagTodayJournal = ""
+	if _, _, err := todayQuery(nil); err == nil {
+		t.Error("expected an error without a topic")
+	}
+	flagTodayDays = 0
+	if _, _, err := todayQuery([]string{"fxs"}); err == nil {
+		t.Error("expected an error for --days 0")
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(alert.EnvConfigDir, dir)
+	t.Setenv("UNPAYWALL_EMAIL", "")
+	t.Setenv("CROSSREF_MAILTO", "env@example.org")
+	t.Setenv(semanticscholar.EnvAPIKey, "env-key")
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{
+  "s2_api_key": "@keychain",
+  "limit": "50",
+  "sort": "date",
+  "unpaywall_email": "file@example.org",
+  "crossref_mailto": "file@example.org"
+}`), 0o600)
+
+	cmd := &cobra.Command{Use: "test"}
+	var limit int
+	var sort string
+	cmd.Flags().IntVar(&limit, "limit", 20, "")
+	cmd.Flags().StringVar(&sort, "sort", "", "")
+	cmd.Flags().Set("sort", "relevance")
+
+	if err := applyConfig(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if limit != 50 {
+		t.Errorf("limit = %d, want 50 from the file", limit)
+	}
+	if sort != "relevance" {
+		t.Errorf("sort = %q, want the flag to override the file", sort)
+	}
+	if got := os.Getenv("UNPAYWALL_EMAIL"); got != "file@example.org" {
+		t.Errorf("UNPAYWALL_EMAIL = %q, want it filled from the file", got)
+	}
+	if got := os.Getenv("CROSSREF_MAILTO"); got != "env@example.org" {
+		t.Errorf("CROSSREF_MAILTO = %q, want the environment to override the file", got)
+	}
+	if got := os.Getenv(semanticscholar.EnvAPIKey); got != "env-key" {
+		t.Errorf("%s = %q, want the environment to override the keychain", semanticscholar.EnvAPIKey, got)
+	}
+}
+
+func TestApplyConfig_DefersKeychain(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(alert.EnvConfigDir, dir)
+	t.Setenv(webhook.EnvURL, "")
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"webhook": "@keychain"}`), 0o600)
+	defer delete(keychainSecrets, webhook.EnvURL)
+
+	if err := applyConfig(&cobra.Command{Use: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(webhook.EnvURL); got != "" {
+		t.Errorf("%s = %q, want the keychain left unread", webhook.EnvURL, got)
+	}
+	if _, ok := keychainSecrets[webhook.EnvURL]; !ok {
+		t.Fatal("expected the keychain setting to be deferred")
+	}
+	// The environment still wins once a command reads the setting.
+	t.Setenv(webhook.EnvURL, "https://example.org/hook")
+	if got := secretEnv(webhook.EnvURL); got != "https://example.org/hook" {
+		t.Errorf("secretEnv = %q, want the environment's value", got)
+	}
+}
+
+func TestWriteExportPlan(t *testing.T) {
+	plan := &exportPlan{Query: "autism", Matches: 52000, Records: 52000, Ranges: 7, NCBISeconds: 95}
+	plan.add("esearch", 7, true, "one history search per date range")
+	plan.add("efetch", 0, true, "skipped when empty")
+	var buf bytes.Buffer
+	if err := writeExportPlan(&buf, plan, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Query:        autism\n", "Date ranges:  7\n", "  esearch  7  one history search", "at least 1m35s\n", "nothing was fetched"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plan output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "efetch") {
+		t.Errorf("plan lists a service with no requests:\n%s", out)
+	}
+	if pages(401, fetchBatchSize) != 3 || pages(0, fetchBatchSize) != 0 {
+		t.Error("pages miscounts requests")
+	}
+}
+
+func TestExitCode_UsageInRunE(t *testing.T) {
+	argsChecked = true
+	defer func() { argsChecked = false }()
+	defer func() { flagSince, flagUntil = "", "" }()
+
+	if err := noteCmd.RunE(noteCmd, []string{"38000001x"}); exitCode(err) != exitUsage {
+		t.Errorf("note with a malformed PMID: %v exits %d, want %d", err, exitCode(err), exitUsage)
+	}
+	flagSince, flagUntil = "6x", ""
+	if _, _, err := dateRange(); exitCode(err) != exitUsage {
+		t.Errorf("bad --since span: %v exits %d, want %d", err, exitCode(err), exitUsage)
+	}
+	flagSince, flagUntil = "2025", "2020"
+	if _, _, err := dateRange(); exitCode(err) != exitUsage {
+		t.Errorf("--since after --until: %v exits %d, want %d", err, exitCode(err), exitUsage)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	defer func() { argsChecked = false }()
+	argsChecked = false
+	if got := exitCode(errors.New("unknown flag: --bogus")); got != exitUsage {
+		t.Errorf("error before the run: got %d, want %d", got, exitUsage)
+	}
+
+	argsChecked = true
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, exitOK},
+		{errors.New("boom"), exitFailure},
+		{errNoResults("no articles match %s", "x"), exitNoResults},
+		{fmt.Errorf("search failed: %w", &ncbi.StatusError{Code: 502}), exitService},
+		{fmt.Errorf("executing request: %w", &url.Error{Op: "Get", URL: "u", Err: errors.New("no such host")}), exitService},
+		{fmt.Errorf("fetch request failed: %w", &ncbi.OfflineError{Request: "efetch.fcgi?id=1"}), exitService},
+		{withExitCode(exitFlagged, errors.New("1 of 2 references are retracted")), exitFlagged},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+
+	flagJSON = true
+	defer func() { flagJSON = false }()
+	var buf bytes.Buffer
+	err := fmt.Errorf("search failed: %w", &ncbi.StatusError{Code: 502})
+	reportError(&buf, nil, err, exitCode(err))
+	var got jsonError
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("stderr is not JSON: %v\n%s", err, buf.String())
+	}
+	if got.Error.Code != exitService || got.Error.Kind != "service" || got.Error.Status != 502 {
+		t.Errorf("unexpected error object %+v", got.Error)
+	}
+}
+
+func TestParseDateFlag(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct{ in, want string }{
+		{"today", "2026/03/31"},
+		{"yesterday", "2026/03/30"},
+		{"30d", "2026/03/01"},
+		{"2w", "2026/03/17"},
+		{"6m", "2025/09/30"}, // September has no 31st
+		{"1m", "2026/02/28"},
+		{"2y", "2024/03/31"},
+		{"2020", "2020"},
+		{"2020-3", "2020/03"},
+		{"2020/03/05", "2020/03/05"},
+	}
+	for _, tt := range tests {
+		got, err := parseDateFlag(tt.in, now)
+		if err != nil || got != tt.want {
+			t.Errorf("parseDateFlag(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "6x", "2020/13", "2020/02/30", "last week"} {
+		if _, err := parseDateFlag(bad, now); err == nil {
+			t.Errorf("parseDateFlag(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseDateFlag_MonthEnds(t *testing.T) {
+	tests := []struct {
+		now      time.Time
+		in, want string
+	}{
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), "1m", "2024/02/29"},
+		{time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), "1m", "2025/02/28"},
+		{time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC), "1m", "2025/04/30"},
+		{time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), "2m", "2024/11/30"},
+		{time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), "14m", "2024/01/15"},
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "1y", "2023/02/28"},
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "4y", "2020/02/29"},
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "12m", "2023/02/28"},
+		{time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), "1y", "2023/12/31"},
+	}
+	for _, tt := range tests {
+		got, err := parseDateFlag(tt.in, tt.now)
+		if err != nil || got != tt.want {
+			t.Errorf("parseDateFlag(%q) at %s = %q, %v; want %q", tt.in, tt.now.Format("2006-01-02"), got, err, tt.want)
+		}
+	}
+}
+
+func TestSinceUntil(t *testing.T) {
+	defer func() { flagSince, flagUntil = "", "" }()
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	flagSince, flagUntil = "2021/06", "2021"
+	if _, _, err := sinceUntil(now); err != nil {
+		t.Errorf("a month within the --until year should be accepted: %v", err)
+	}
+	flagSince, flagUntil = "2022", "2021/12/31"
+	if _, _, err := sinceUntil(now); err == nil {
+		t.Error("expected --since after --until to fail")
+	}
+
+	flagSince, flagUntil = "6m", ""
+	minDate, maxDate, err := dateRange()
+	if err != nil || minDate == "" || maxDate != time.Now().Format("2006/01/02") {
+		t.Errorf("dateRange() = %q, %q, %v; want --until to default to today", minDate, maxDate, err)
+	}
+	flagSince, flagUntil = "", "2020"
+	if minDate, _, _ := dateRange(); minDate != earliestDate {
+		t.Errorf("dateRange() min = %q, want %q", minDate, earliestDate)
+	}
+}
+
+func TestRelaxedOptions(t *testing.T) {
+	opts := &eutils.SearchOptions{Limit: 20, MinDate: "2024", MaxDate: "2024"}
+	for _, p := range refine.Relax("autism AND ketamine", true) {
+		got := relaxedOptions(p, opts)
+		if p.Clause == "" {
+			if got.MinDate != "" || got.MaxDate != "" || got.Limit != 20 {
+				t.Errorf("%s: options = %+v, want the dates dropped", p.Description, got)
+			}
+			if opts.MinDate != "2024" {
+				t.Error("relaxedOptions changed the original options")
+			}
+		} else if got != opts {
+			t.Errorf("%s: options changed", p.Description)
+		}
+	}
+}
+
+func TestSnapshotTime(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.Local)
+	for value, want := range map[string]time.Time{
+		"2024-01-01": time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond),
+		"2024/02":    time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond),
+		"2023":       time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond),
+		"7d":         time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond),
+	} {
+		got, err := snapshotTime(value, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("snapshotTime(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := snapshotTime("soon", now); err == nil {
+		t.Error("expected an invalid date to be rejected")
+	}
+}
+
+func TestExportFiles(t *testing.T) {
+	dir := t.TempDir()
+	fresh := filepath.Join(dir, "refs.ris")
+	stale := filepath.Join(dir, "old.csv")
+	for _, path := range []string{fresh, stale} {
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+	hookStart = time.Now().Add(-time.Minute)
+	t.Cleanup(func() { hookStart = time.Time{} })
+
+	cmd := &cobra.Command{Use: "search"}
+	cmd.Flags().String("ris", fresh, "")
+	cmd.Flags().String("csv", stale, "")
+	cmd.Flags().String("tsv", filepath.Join(dir, "missing.tsv"), "")
+	got := exportFiles(cmd)
+	want := []exportFile{{path: fresh, format: "ris"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exportFiles = %+v, want %+v", got, want)
+	}
+}
+
+// configTestCommand returns the named subcommand of a root carrying the
+// output flags, parsed with args, as applyConfig sees it.
+func configTestCommand(t *testing.T, name string, args ...string) *cobra.Command {
+	t.Helper()
+	resetGlobalFlags()
+	configDefaults = make(map[string]bool)
+	t.Cleanup(func() {
+		resetGlobalFlags()
+		configDefaults = make(map[string]bool)
+	})
+	root := &cobra.Command{Use: "pubmed"}
+	root.PersistentFlags().IntVar(&flagLimit, "limit", 20, "")
+	root.PersistentFlags().StringVar(&flagSort, "sort", "", "")
+	root.PersistentFlags().StringVar(&flagColumns, "columns", "", "")
+	root.PersistentFlags().StringVar(&flagCSV, "csv", "", "")
+	cmd := &cobra.Command{Use: name}
+	root.AddCommand(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestApplyConfig_SavedDefaults(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(alert.EnvConfigDir, dir)
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{
+  "limit": "50",
+  "sort": "citations",
+  "columns": "pmid,title"
+}`), 0o600)
+
+	// A saved --columns does not demand an export from other commands.
+	cmd := configTestCommand(t, "schema")
+	if err := applyConfig(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if flagColumns != "" {
+		t.Errorf("schema: columns = %q, want the saved default left unapplied", flagColumns)
+	}
+	if err := validateGlobalFlags(cmd); err != nil {
+		t.Errorf("schema with saved columns and sort: %v", err)
+	}
+
+	// Nor a search without a table export, which just leaves it unused.
+	cmd = configTestCommand(t, "search")
+	if err := applyConfig(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if flagColumns != "pmid,title" || flagSort != "citations" {
+		t.Errorf("search: columns, sort = %q, %q; want the saved defaults", flagColumns, flagSort)
+	}
+	if err := validateGlobalFlags(cmd); err != nil {
+		t.Errorf("search with saved columns and no export: %v", err)
+	}
+
+	// A saved external sort applies to search only.
+	cmd = configTestCommand(t, "export")
+	if err := applyConfig(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if flagSort != "" {
+		t.Errorf("export: sort = %q, want the search-only default left unapplied", flagSort)
+	}
+	if err := validateGlobalFlags(cmd); err != nil {
+		t.Errorf("export with saved sort: %v", err)
+	}
+
+	// A saved limit is the default, not an explicit --limit that caps
+	// exports and alerts.
+	if flagLimit != 50 {
+		t.Errorf("limit = %d, want 50 from the file", flagLimit)
+	}
+	if cmd.Flags().Changed("limit") {
+		t.Error("a saved limit was marked as given on the command line")
+	}
+	if got := exportLimit(cmd, 10000); got != 10000 {
+		t.Errorf("exportLimit = %d, want the saved limit not to cap the export", got)
+	}
+	if fl := cmd.Flags().Lookup("limit"); fl.DefValue != "50" {
+		t.Errorf("limit default = %q, want 50 shown in help", fl.DefValue)
+	}
+
+	// An explicit flag still overrides the file.
+	cmd = configTestCommand(t, "export", "--limit", "5")
+	if err := applyConfig(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if flagLimit != 5 || exportLimit(cmd, 10000) != 5 {
+		t.Errorf("limit = %d, want the flag to override the file and cap the export", flagLimit)
+	}
+}
+
+// TestGlobalOutNotShadowed keeps -o/--out meaning the same file on every
+// command: a command writing elsewhere names its own flag.
+func TestGlobalOutNotShadowed(t *testing.T) {
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		flags := c.LocalNonPersistentFlags()
+		if flags.Lookup("out") != nil || flags.ShorthandLookup("o") != nil {
+			t.Errorf("%s defines its own --out or -o", c.CommandPath())
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
+
+func TestNewBaseClient_RecordsOnlyWhenAsked(t *testing.T) {
+	t.Setenv(cache.EnvDir, t.TempDir())
+	defer func() { flagRecord, flagOffline = false, false }()
+
+	for _, tc := range []struct {
+		record, offline, cached bool
+	}{
+		{false, false, false},
+		{true, false, true},
+		{false, true, true},
+	} {
+		flagRecord, flagOffline = tc.record, tc.offline
+		if got := newBaseClient().Cache != nil; got != tc.cached {
+			t.Errorf("--record=%v --offline=%v: cache attached = %v, want %v", tc.record, tc.offline, got, tc.cached)
+		}
+	}
+}
+
+func TestFetchIncomplete(t *testing.T) {
+	failed := &eutils.FetchErrors{Failed: []eutils.FetchError{{PMIDs: []string{"2", "3"}, Err: "HTTP 502"}}}
+	err := fetchIncomplete(failed)
+	if exitCode(err) != exitService || !strings.Contains(err.Error(), "2 PMIDs not fetched") {
+		t.Errorf("fetchIncomplete(failed) = %v (exit %d)", err, exitCode(err))
+	}
+	argsChecked = true
+	defer func() { argsChecked = false }()
+	if err := fetchIncomplete(&eutils.FetchErrors{Invalid: []string{"x1"}}); exitCode(err) != exitFailure {
+		t.Errorf("fetchIncomplete(invalid) exit = %d, want %d", exitCode(err), exitFailure)
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagMeshYear      int
+	flagMeshSCR       bool
+	flagMeshQualifier string
+	flagMeshMajor     bool
+	flagMeshClass     bool
+	flagMeshDepth     int
+	flagMeshMarkdown  bool
+)
+
+// meshTreeCmd prints where a descriptor sits in the MeSH hierarchy.
+var meshTreeCmd = &cobra.Command{
+	Use:   "tree <term>",
+	Short: "Show a MeSH term's position in the hierarchy",
+	Long: `Resolve a MeSH term (or descriptor UI such as D005600) and print each of its
+tree positions with the ancestor chain from the category root and its children.
+--depth shows further levels of descendants; nodes with hidden children show
+their count. --markdown prints nested Markdown lists for notes and reports.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagMeshDepth < 1 {
+			return errUsage("--depth must be at least 1")
+		}
+		client := newMeshClient()
+		term := strings.Join(args, " ")
+
+		h, err := client.Tree(cmd.Context(), term, flagMeshDepth)
+		if err != nil {
+			return fmt.Errorf("MeSH tree lookup failed: %w", err)
+		}
+
+		if flagMeshMarkdown {
+			return output.FormatMeSHTreeMarkdown(cmd.OutOrStdout(), h)
+		}
+		return output.FormatMeSHTree(cmd.OutOrStdout(), h, outputCfg())
+	},
+}
+
+// meshSuggestCmd lists descriptors whose heading or entry terms match a prefix.
+var meshSuggestCmd = &cobra.Command{
+	Use:   "suggest <prefix>",
+	Short: "Suggest MeSH terms matching a prefix",
+	Long: `List MeSH descriptors whose heading or entry terms start with (or contain) the
+given text, best matches first. Entry-term matches show the synonym that matched,
+so "heart attack" suggests Myocardial Infarction. Uses the offline database when
+installed via "pubmed mesh download".`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newMeshClient()
+		prefix := strings.Join(args, " ")
+
+		limit := mesh.DefaultSuggestLimit
+		if cmd.Flags().Changed("limit") {
+			limit = flagLimit
+		}
+
+		suggestions, err := client.Suggest(cmd.Context(), prefix, limit)
+		if err != nil {
+			return fmt.Errorf("MeSH suggest failed: %w", err)
+		}
+
+		return output.FormatMeSHSuggestions(cmd.OutOrStdout(), prefix, suggestions, outputCfg())
+	},
+}
+
+// meshQualifiersCmd lists qualifiers, optionally those allowed with a descriptor.
+var meshQualifiersCmd = &cobra.Command{
+	Use:   "qualifiers [term]",
+	Short: "List MeSH qualifiers (subheadings)",
+	Long: `List MeSH qualifiers such as drug therapy (DT) or epidemiology (EP). Given a
+term, list only the qualifiers NLM allows with that descriptor.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all := mesh.Qualifiers()
+		if len(args) == 0 {
+			return output.FormatMeSHQualifiers(cmd.OutOrStdout(), nil, all, outputCfg())
+		}
+
+		record, err := newMeshClient().Lookup(cmd.Context(), strings.Join(args, " "))
+		if err != nil {
+			return fmt.Errorf("MeSH lookup failed: %w", err)
+		}
+		var allowed []mesh.Qualifier
+		for _, q := range all {
+			if record.AllowsQualifier(q) {
+				allowed = append(allowed, q)
+			}
+		}
+		return output.FormatMeSHQualifiers(cmd.OutOrStdout(), record, allowed, outputCfg())
+	},
+}
+
+// meshQueryCmd builds a validated MeSH search clause for use with search.
+var meshQueryCmd = &cobra.Command{
+	Use:   "query <term>",
+	Short: "Build a MeSH search clause, optionally with a qualifier",
+	Long: `Resolve a term to its descriptor and print a PubMed clause such as
+"Fragile X Syndrome/drug therapy"[mh]. The qualifier may be a name or
+abbreviation and is checked against the descriptor's allowable qualifiers.
+
+With --class, a drug is replaced by its pharmacological action classes
+(losartan → "Angiotensin II Type 1 Receptor Blockers"[pa] OR member drugs).
+
+  pubmed search "$(pubmed mesh query fxs --qualifier DT) AND 2020:2025[dp]"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newMeshClient()
+		record, err := client.Lookup(cmd.Context(), strings.Join(args, " "))
+		if err != nil {
+			return fmt.Errorf("MeSH lookup failed: %w", err)
+		}
+
+		if flagMeshClass {
+			clause, err := classClause(cmd.Context(), client, record)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), clause)
+			return nil
+		}
+
+		descriptor := record.Name
+		if record.IsSupplementary() && len(record.HeadingMappedTo) > 0 {
+			descriptor = record.HeadingMappedTo[0]
+			fmt.Fprintf(os.Stderr, "Note: %s is a supplementary concept; using mapped heading %s\n", record.Name, descriptor)
+		}
+
+		var qualifier string
+		if flagMeshQualifier != "" {
+			q, err := mesh.LookupQualifier(flagMeshQualifier)
+			if err != nil {
+				return err
+			}
+			if !record.AllowsQualifier(q) {
+				return fmt.Errorf("qualifier %q is not allowed with %s; see: pubmed mesh qualifiers %q", q.Name, record.Name, record.Name)
+			}
+			qualifier = q.Name
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), query.MeSHTerm(descriptor, qualifier, flagMeshMajor))
+		return nil
+	},
+}
+
+// meshMembersCmd lists the substances in a pharmacological action class.
+var meshMembersCmd = &cobra.Command{
+	Use:   "members <action>",
+	Short: "List drugs with a MeSH pharmacological action",
+	Long: `List the descriptors and supplementary concepts that carry a MeSH
+Pharmacological Action, e.g. "Angiotensin II Type 1 Receptor Blockers".
+A drug's own actions are shown by "pubmed mesh <drug>".`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		action := strings.Join(args, " ")
+
+		limit := mesh.DefaultMemberLimit
+		if cmd.Flags().Changed("limit") {
+			limit = flagLimit
+		}
+
+		members, err := newMeshClient().ActionMembers(cmd.Context(), action, limit)
+		if err != nil {
+			return fmt.Errorf("MeSH members lookup failed: %w", err)
+		}
+
+		return output.FormatMeSHRecords(cmd.OutOrStdout(), "Members of "+action, members, outputCfg())
+	},
+}
+
+// classClause ORs the action-class clauses for a drug. A record that is
+// itself an action heading (no actions of its own) is used as the class.
+func classClause(ctx context.Context, client *mesh.Client, record *mesh.MeSHRecord) (string, error) {
+	actions := record.PharmacologicalActions
+	if len(actions) == 0 {
+		actions = []string{record.Name}
+	}
+
+	var clauses []string
+	for _, action := range actions {
+		members, err := client.ActionMembers(ctx, action, mesh.DefaultMemberLimit)
+		if err != nil {
+			return "", fmt.Errorf("MeSH members lookup failed: %w", err)
+		}
+		if len(members) == 0 {
+			continue
+		}
+		names := make([]string, len(members))
+		for i, m := range members {
+			names[i] = m.Name
+		}
+		clauses = append(clauses, query.ActionClause(action, names))
+	}
+	if len(clauses) == 0 {
+		return "", fmt.Errorf("%s has no pharmacological actions", record.Name)
+	}
+	if len(clauses) == 1 {
+		return clauses[0], nil
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", nil
+}
+
+// meshMapCmd maps a natural-language question to candidate descriptors.
+var meshMapCmd = &cobra.Command{
+	Use:   "map <text>",
+	Short: "Map a natural-language question to MeSH descriptors",
+	Long: `Run free text through PubMed's automatic term mapping and report the MeSH
+descriptors it recognizes, with a confidence for each:
+
+  heading       1.00  the phrase is the descriptor name
+  entry term    0.90  the phrase is a listed synonym
+  term mapping  0.70  PubMed mapped the phrase through related vocabularies
+
+Phrases searched only as free text are listed as unmapped.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mappings, err := query.MapConcepts(cmd.Context(), strings.Join(args, " "), newEutilsClient(), newMeshClient())
+		if err != nil {
+			return fmt.Errorf("MeSH mapping failed: %w", err)
+		}
+		return output.FormatConceptMappings(cmd.OutOrStdout(), mappings, outputCfg())
+	},
+}
+
+// meshDownloadCmd installs the annual descriptor file for offline lookups.
+var meshDownloadCmd = &cobra.Command{
+	Use:   "download",
+	Short: "Download the MeSH descriptor file for offline lookups",
+	Long: `Download NLM's annual ASCII MeSH descriptor file (dYYYY.bin) into the cache
+and index it. With --supplemental, also install the Supplementary Concept Record
+file (cYYYY.bin) covering drugs, chemicals, and rare diseases. Once installed,
+mesh lookups and tree navigation are served locally without NCBI calls; terms
+missing from the files still fall back to NCBI.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagOffline {
+			return fmt.Errorf("mesh download needs the network; run it without --offline")
+		}
+		dir, err := meshLocalDir()
+		if err != nil {
+			return err
+		}
+
+		url := mesh.DescriptorFileURL(flagMeshYear)
+		fmt.Fprintf(os.Stderr, "Downloading %s...\n", url)
+
+		// The descriptor file is tens of megabytes; allow far longer than API calls.
+		hc := &http.Client{Timeout: 10 * time.Minute}
+		db, err := mesh.DownloadLocalDB(cmd.Context(), hc, url, dir)
+		if err != nil {
+			return fmt.Errorf("MeSH download failed: %w", err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Installed %d MeSH records from %s into %s\n", len(db.Records), db.Source, dir)
+		return nil
+	},
+}
+
+// meshLocalDir is where the offline MeSH database lives inside the cache.
+func meshLocalDir() (string, error) {
+	return cache.Subdir("mesh")
+}
+
+// completeMeSHTerms offers descriptor names for shell completion of
+// multi-word term arguments.
+func completeMeSHTerms(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := strings.TrimSpace(strings.Join(append(args, toComplete), " "))
+	if prefix == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	suggestions, err := newMeshClient().Suggest(cmd.Context(), prefix, mesh.DefaultSuggestLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+
+	// Earlier words are already on the command line; complete only the remainder.
+	typed := strings.TrimSpace(strings.Join(args, " "))
+	var out []string
+	for _, s := range suggestions {
+		name := s.Name
+		if typed != "" {
+			if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(typed)+" ") {
+				continue
+			}
+			name = name[len(typed)+1:]
+		}
+		out = append(out, fmt.Sprintf("%s\t%s", name, s.UI))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	meshCmd.ValidArgsFunction = completeMeSHTerms
+	meshTreeCmd.ValidArgsFunction = completeMeSHTerms
+	meshQualifiersCmd.ValidArgsFunction = completeMeSHTerms
+	meshQueryCmd.ValidArgsFunction = completeMeSHTerms
+
+	meshTreeCmd.Flags().IntVar(&flagMeshDepth, "depth", 1, "Levels of descendants to show beneath the term")
+	meshTreeCmd.Flags().BoolVar(&flagMeshMarkdown, "markdown", false, "Render the tree as nested Markdown lists")
+
+	meshQueryCmd.Flags().StringVarP(&flagMeshQualifier, "qualifier", "q", "", "Qualifier name or abbreviation (e.g. \"drug therapy\" or DT)")
+	meshQueryCmd.Flags().BoolVar(&flagMeshMajor, "major", false, "Restrict to major topic [majr]")
+	meshQueryCmd.Flags().BoolVar(&flagMeshClass, "class", false, "Expand a drug to its pharmacological action classes")
+	meshQueryCmd.MarkFlagsMutuallyExclusive("class", "qualifier")
+	meshQueryCmd.MarkFlagsMutuallyExclusive("class", "major")
+	meshQueryCmd.RegisterFlagCompletionFunc("qualifier", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for _, q := range mesh.Qualifiers() {
+			names = append(names, q.Name+"\t"+q.Abbreviation)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	meshDownloadCmd.Flags().IntVar(&flagMeshYear, "year", time.Now().Year(), "MeSH edition year to download")
+	meshDownloadCmd.Flags().BoolVar(&flagMeshSCR, "supplemental", false, "Also download Supplementary Concept Records (large)")
+
+	meshCmd.AddCommand(meshTreeCmd)
+	meshCmd.AddCommand(meshSuggestCmd)
+	meshCmd.AddCommand(meshQualifiersCmd)
+	meshCmd.AddCommand(meshQueryCmd)
+	meshCmd.AddCommand(meshMembersCmd)
+	meshCmd.AddCommand(meshMapCmd)
+	meshCmd.AddCommand(meshDownloadCmd)
+}
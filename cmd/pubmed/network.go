@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/network"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagNetworkQuery      string
+	flagNetworkPMIDs      string
+	flagNetworkType       string
+	flagNetworkFile       string
+	flagNetworkFormat     string
+	flagNetworkTop        int
+	flagNetworkMaxAuthors int
+)
+
+// networkCmd builds co-author and citation networks over a result set.
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Co-author or citation network of a result set, with centrality",
+	Long: `Fetch the articles matching --query (or listed in --pmids) and build a
+network over them: --type coauthor links authors who share an article, with
+edges weighted by the number of shared articles; --type citation links the
+articles that cite one another, from each article's PubMed reference list.
+
+Each node gets its degree, degree centrality, betweenness, and PageRank, and
+the most central (--top, by PageRank) are summarized. --file writes the whole
+network as GraphML (for Gephi or yEd) or JSON, by extension or --format;
+--json prints it, and --csv/--tsv export the node table.
+
+--limit sets how many search results are included (default 200). Authors are
+matched by last name and initials, so common names may merge; at most
+--max-authors are taken from each article.`,
+	Example: `  pubmed network --query "fragile x syndrome AND metformin" --type coauthor
+  pubmed network --query "fragile x syndrome" --type citation --limit 100 --file citations.graphml
+  pubmed network --pmids 38000001,38000002 --type coauthor --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagNetworkType != network.TypeCoauthor && flagNetworkType != network.TypeCitation {
+			return errUsage("--type must be coauthor or citation")
+		}
+		if flagNetworkTop < 0 {
+			return errUsage("--top must not be negative")
+		}
+		format := flagNetworkFormat
+		if format == "" && flagNetworkFile != "" {
+			if format = network.FormatForPath(flagNetworkFile); format == "" {
+				return fmt.Errorf("cannot tell the network format from %q; use a .graphml or .json file or --format", flagNetworkFile)
+			}
+		}
+		if format != "" && format != network.FormatGraphML && format != network.FormatJSON {
+			return errUsage("--format must be graphml or json")
+		}
+
+		client := newEutilsClient()
+		articles, err := analyzeArticles(cmd, client, flagNetworkQuery, flagNetworkPMIDs)
+		if err != nil {
+			return err
+		}
+		var n *network.Network
+		if flagNetworkType == network.TypeCoauthor {
+			n = network.Coauthor(articles, flagNetworkMaxAuthors)
+		} else {
+			n, err = network.Citation(cmd.Context(), client, articles)
+			if err != nil {
+				return fmt.Errorf("citation network failed: %w", err)
+			}
+		}
+		n.Query = flagNetworkQuery
+
+		if flagNetworkFile == "" {
+			if format != "" && !flagJSON {
+				return network.Write(cmd.OutOrStdout(), n, format)
+			}
+			return output.FormatNetwork(cmd.OutOrStdout(), n, "", flagNetworkTop, outputCfg())
+		}
+		f, err := os.Create(flagNetworkFile)
+		if err != nil {
+			return fmt.Errorf("creating network file: %w", err)
+		}
+		defer f.Close()
+		if err := network.Write(f, n, format); err != nil {
+			return fmt.Errorf("writing network: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing network: %w", err)
+		}
+		return output.FormatNetwork(cmd.OutOrStdout(), n, flagNetworkFile, flagNetworkTop, outputCfg())
+	},
+}
+
+func init() {
+	networkCmd.Flags().StringVar(&flagNetworkQuery, "query", "", "PubMed query selecting the articles")
+	networkCmd.Flags().StringVar(&flagNetworkPMIDs, "pmids", "", "Comma-separated PMIDs to use instead of a query")
+	networkCmd.Flags().StringVar(&flagNetworkType, "type", network.TypeCoauthor, "Network type: coauthor or citation")
+	networkCmd.Flags().StringVar(&flagNetworkFile, "file", "", "Write the network to this file (.graphml or .json)")
+	networkCmd.Flags().StringVar(&flagNetworkFormat, "format", "", "Network format: graphml or json (default from --file extension)")
+	networkCmd.Flags().IntVar(&flagNetworkTop, "top", 10, "Most central nodes to summarize (0 for all)")
+	networkCmd.Flags().IntVar(&flagNetworkMaxAuthors, "max-authors", network.DefaultMaxAuthors, "Authors taken from each article")
+	networkCmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions([]string{network.TypeCoauthor, network.TypeCitation}, cobra.ShellCompDirectiveNoFileComp))
+	networkCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{network.FormatGraphML, network.FormatJSON}, cobra.ShellCompDirectiveNoFileComp))
+}
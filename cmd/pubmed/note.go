@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagNoteClear bool
+	flagTagFind   string
+)
+
+// noteCmd adds and shows free-text notes on an article.
+var noteCmd = &cobra.Command{
+	Use:   "note <pmid> [text]",
+	Short: "Add a note to an article, or show its notes",
+	Long: `Attach a free-text note to a PMID in the local library. Notes appear in
+fetch, search, and lib show article cards and are exported with references
+(RIS N1, BibTeX annote, CSL-JSON note). Without text, the PMID's notes are
+printed; --clear deletes them.`,
+	Example: `  pubmed note 38000001 "Primary outcome null; see supplement table 3"
+  pubmed note 38000001
+  pubmed note 38000001 --clear`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validatePMID(args[0]); err != nil {
+			return err
+		}
+		lib, err := loadLibrary()
+		if err != nil {
+			return err
+		}
+		pmid, text := args[0], strings.Join(args[1:], " ")
+		switch {
+		case flagNoteClear && text != "":
+			return errUsage("give note text or --clear, not both")
+		case flagNoteClear:
+			lib.ClearNotes(pmid)
+		case text == "":
+			a := lib.Annotations[pmid]
+			if a == nil || len(a.Notes) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No notes on PMID %s\n", pmid)
+				return nil
+			}
+			for _, n := range a.Notes {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", n.Added.Local().Format("2006-01-02 15:04"), n.Text)
+			}
+			return nil
+		default:
+			if err := lib.AddNote(pmid, text, time.Now()); err != nil {
+				return err
+			}
+		}
+		return saveLibrary(lib)
+	},
+}
+
+// tagCmd adds, removes, and lists tags on an article.
+var tagCmd = &cobra.Command{
+	Use:   "tag <pmid> [+tag|-tag...]",
+	Short: "Tag an article (+name adds, -name removes), or show its tags",
+	Long: `Add (+name) or remove (-name) tags on a PMID in the local library. Tags
+appear in article cards and are exported as keywords (RIS KW, BibTeX
+keywords, CSL-JSON keyword, the tags CSV column). Without changes, the
+PMID's tags are printed; --find lists the PMIDs carrying a tag.`,
+	Example: `  pubmed tag 38000001 +key-paper +rct
+  pubmed tag 38000001 -rct
+  pubmed tag --find key-paper | pubmed export --pmids - --out key-papers.ris`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if flagTagFind != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lib, err := loadLibrary()
+		if err != nil {
+			return err
+		}
+		if flagTagFind != "" {
+			for _, id := range lib.Tagged(flagTagFind) {
+				fmt.Fprintln(cmd.OutOrStdout(), id)
+			}
+			return nil
+		}
+		pmid := args[0]
+		if err := validatePMID(pmid); err != nil {
+			return err
+		}
+		if len(args) > 1 {
+			if err := lib.Tag(pmid, args[1:]); err != nil {
+				return err
+			}
+			if err := saveLibrary(lib); err != nil {
+				return err
+			}
+		}
+		var tags []string
+		if a := lib.Annotations[pmid]; a != nil {
+			tags = a.Tags
+		}
+		if len(tags) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No tags on PMID %s\n", pmid)
+			return nil
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), strings.Join(tags, " "))
+		return nil
+	},
+}
+
+func init() {
+	noteCmd.Flags().BoolVar(&flagNoteClear, "clear", false, "Delete the PMID's notes")
+	tagCmd.Flags().StringVar(&flagTagFind, "find", "", "List the PMIDs carrying this tag")
+	// "-name" after the PMID is a tag removal, not a flag.
+	tagCmd.Flags().SetInterspersed(false)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// baseClients are the API clients made by this run, whose refused
+// requests offlineMissing collects under --offline.
+var baseClients []*ncbi.BaseClient
+
+// offlineMissing lists the requests --offline could not answer from the
+// cache, in the order first made.
+func offlineMissing() []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, c := range baseClients {
+		for _, m := range c.Missing() {
+			if !seen[m] {
+				seen[m] = true
+				missing = append(missing, m)
+			}
+		}
+	}
+	return missing
+}
+
+// writeOfflineMissing lists the requests --offline could not answer.
+func writeOfflineMissing(w io.Writer, missing []string) {
+	fmt.Fprintf(w, "Not in the cache (%d); run the command once online to cache them, or drop --offline:\n", len(missing))
+	for _, m := range missing {
+		fmt.Fprintf(w, "  %s\n", m)
+	}
+}
+
+// libraryArticles returns the articles saved in the library, which an
+// offline fetch serves without a request, or nil if there are none.
+func libraryArticles() map[string]eutils.Article {
+	l, err := loadLibrary()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring library: %v\n", err)
+		return nil
+	}
+	return l.Articles
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagPDFDir      string
+	flagPDFResolver string
+	flagPDFNoSave   bool
+)
+
+// pdfCmd downloads article PDFs from open-access sources or through an
+// institutional resolver.
+var pdfCmd = &cobra.Command{
+	Use:   "pdf <pmid|doi> [pmid|doi...]",
+	Short: "Download article PDFs, through your institution's resolver if needed",
+	Long: `Find and download a PDF for each article, recording where it came from: the
+PMC Open Access Subset first, then the best open-access copy Unpaywall knows
+(with UNPAYWALL_EMAIL), then a link built from your institution's link
+resolver or proxy template (--resolver or ` + fulltext.EnvResolver + `).
+
+Templates may use {doi}, {pmid}, and {pmcid}, which are query-escaped, and
+{url}, the article's https://doi.org link, for EZproxy-style prefixes:
+
+  https://ezproxy.example.edu/login?url={url}
+  https://resolver.example.edu/openurl?id=doi:{doi}
+
+PDFs are saved to --dir as <pmid>.pdf. A resolver link that answers with a
+sign-in page rather than a PDF is reported but not saved; open it in a
+browser signed in to your library. --no-download only reports the links.
+Give - to read PMIDs or DOIs from stdin.`,
+	Example: `  pubmed pdf 38000001
+  pubmed pdf 38000001 38000002 --dir papers/
+  pubmed pdf 10.1186/s11689-024-00001-1 --resolver "https://ezproxy.example.edu/login?url={url}" --no-download`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := stdinArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		client := newEutilsClient()
+		pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+		if err != nil {
+			return err
+		}
+		articles, err := client.Fetch(cmd.Context(), pmids)
+		if err != nil {
+			return fmt.Errorf("fetch failed: %w", err)
+		}
+		articles = orderArticles(articles, pmids)
+
+		ft := fulltext.NewClient(newBaseClient(), os.Getenv(fulltext.EnvUnpaywallEmail))
+		ft.Resolver = flagPDFResolver
+		if ft.Resolver == "" {
+			ft.Resolver = os.Getenv(fulltext.EnvResolver)
+		}
+		results := make([]fulltext.Result, 0, len(articles))
+		for _, a := range articles {
+			r, err := ft.ResolvePDF(cmd.Context(), a)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: PMID %s: %v\n", a.PMID, err)
+			}
+			if r.Found() && !flagPDFNoSave {
+				if err := ft.Save(cmd.Context(), &r, flagPDFDir); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: PMID %s: %v\n", a.PMID, err)
+				}
+			}
+			results = append(results, r)
+		}
+		if ft.Resolver == "" {
+			for _, r := range results {
+				if !r.Found() {
+					fmt.Fprintf(os.Stderr, "Note: set --resolver or %s to build links through your institution's access\n", fulltext.EnvResolver)
+					break
+				}
+			}
+		}
+		return output.FormatFullText(cmd.OutOrStdout(), results, outputCfg())
+	},
+}
+
+func init() {
+	pdfCmd.Flags().StringVar(&flagPDFDir, "dir", ".", "Directory to save PDFs in")
+	pdfCmd.Flags().StringVar(&flagPDFResolver, "resolver", "", "Link resolver or proxy URL template, e.g. https://ezproxy.example.edu/login?url={url} (or set "+fulltext.EnvResolver+")")
+	pdfCmd.Flags().BoolVar(&flagPDFNoSave, "no-download", false, "Report the PDF links without downloading them")
+	pdfCmd.MarkFlagDirname("dir")
+}
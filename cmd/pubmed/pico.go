@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/compare"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// picoCmd implements the pico subcommand.
+var picoCmd = &cobra.Command{
+	Use:   "pico <pmid|doi> [pmid|doi...]",
+	Short: "Extract population, intervention, comparator, and outcomes from articles",
+	Long: `Fetch articles and extract each one's PICO elements (population,
+intervention, comparator, outcomes) with its study design and sample size,
+one row per article: --json, --csv/--tsv/.xlsx for an evidence table, or
+--human. Give - to read PMIDs from stdin.
+
+Elements come from structured abstract sections (PARTICIPANTS,
+INTERVENTIONS, MAIN OUTCOMES AND MEASURES), "X versus Y" and "effect of X
+on" phrasing in the title, control conditions named in the abstract
+(placebo, usual care, wait-list), and PubMed indexing. Empty fields were not
+found; verify the rest against the full text.`,
+	Example: `  pubmed pico 38000001 --human
+  pubmed search "fragile x syndrome AND metformin" --ids-only | pubmed pico - --csv pico.csv`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := stdinArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		client := newEutilsClient()
+		pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+		if err != nil {
+			return err
+		}
+		articles, err := client.Fetch(cmd.Context(), pmids)
+		if err != nil {
+			return fmt.Errorf("fetch failed: %w", err)
+		}
+		articles = orderArticles(articles, pmids)
+		if len(articles) == 0 {
+			return errNoResults("none of the PMIDs were found in PubMed")
+		}
+		rows := make([]compare.PICO, len(articles))
+		for i, a := range articles {
+			rows[i] = compare.ExtractPICO(a)
+		}
+		return output.FormatPICO(cmd.OutOrStdout(), rows, outputCfg())
+	},
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/henrybloomingdale/pubmed-cli/internal/progress"
+)
+
+// newProgress reports the progress of a long operation on stderr: a bar
+// when stderr is a terminal and a log line every few seconds otherwise.
+// With --no-progress it returns nil, which reports nothing.
+func newProgress(label string, total int) *progress.Reporter {
+	if flagNoProgress {
+		return nil
+	}
+	return progress.New(os.Stderr, label, total, term.IsTerminal(os.Stderr.Fd()))
+}
@@ -119,9 +119,9 @@ Output formats:
 		// Primary output.
 		cfg := outputCfg()
 		if cfg.Human {
-			return refcheck.FormatHuman(os.Stdout, report)
+			return refcheck.FormatHuman(cmd.OutOrStdout(), report)
 		}
-		return refcheck.FormatJSON(os.Stdout, report)
+		return refcheck.FormatJSON(cmd.OutOrStdout(), report)
 	},
 }
 
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refine"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRefineSave string
+	flagNoSuggest  bool
+)
+
+// refineCmd implements the interactive query refinement loop.
+var refineCmd = &cobra.Command{
+	Use:   "refine <query>",
+	Short: "Refine a query interactively, one proposed change at a time",
+	Long: `Run a query and refine it in rounds. Each round shows the result count and
+top hits, then proposes specific refinements with the count each would give:
+adding a MeSH descriptor that is a major topic of many top results,
+restricting to recent years when there are many results, and NOT clauses
+that drop animal-only studies or editorials, letters, and comments found
+among them.
+
+Accept a refinement by number, reject one (x 2) so it is not offered again,
+edit the query by hand (e), undo the last change (u), and finish (d) when
+the query is right. The session is written to stderr and the final query to
+stdout, so it can be captured; --save also stores it as an alert.`,
+	Example: `  pubmed refine "fragile x syndrome AND metformin"
+  q=$(pubmed refine "autism eeg") && pubmed search "$q" --limit 50
+  pubmed refine "fragile x syndrome" --save fxs-weekly`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newEutilsClient()
+		s := &refine.Session{Client: client, In: cmd.InOrStdin(), Out: os.Stderr}
+		q, err := s.Run(cmd.Context(), buildQuery(args))
+		if errors.Is(err, refine.ErrQuit) {
+			fmt.Fprintln(os.Stderr, "Quit without a query.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), q)
+		if flagRefineSave == "" {
+			return nil
+		}
+
+		book, err := loadAlerts()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		a, err := book.Add(flagRefineSave, q, now)
+		if err != nil {
+			return err
+		}
+		baseline, _, err := alert.Check(cmd.Context(), client, a, alertLimit(cmd), now)
+		if err != nil {
+			return err
+		}
+		if err := saveAlerts(book); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Saved alert %q; %d current results marked as seen\n", a.Name, len(baseline))
+		return nil
+	},
+}
+
+// maxSuggestions bounds the MeSH descriptors human search output offers
+// to add.
+const maxSuggestions = 3
+
+// maxRelaxations bounds the relaxed queries counted after an empty search.
+const maxRelaxations = 8
+
+// suggestRelaxed follows a search that found nothing by counting relaxed
+// versions of q and listing those that find something on stderr. In a
+// terminal it offers to run one, returning its result; otherwise it
+// returns nil.
+func suggestRelaxed(cmd *cobra.Command, client *eutils.Client, q string, opts *eutils.SearchOptions) (*eutils.SearchResult, error) {
+	if flagNoSuggest {
+		return nil, nil
+	}
+	proposals := refine.Relax(q, opts.MinDate != "" || opts.MaxDate != "")
+	if len(proposals) > maxRelaxations {
+		proposals = proposals[:maxRelaxations]
+	}
+	var found []refine.Proposal
+	for _, p := range proposals {
+		n, err := client.Count(cmd.Context(), p.Query, relaxedOptions(p, opts))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not count a relaxed query: %v\n", err)
+			return nil, nil
+		}
+		if n > 0 {
+			p.Count = n
+			found = append(found, p)
+		}
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Count > found[j].Count })
+
+	fmt.Fprintln(os.Stderr, "No results. These broader queries find some:")
+	for i, p := range found {
+		fmt.Fprintf(os.Stderr, "  [%d] %s -> %d results\n      %s\n", i+1, p.Description, p.Count, p.Query)
+	}
+	if flagJSON || flagNDJSON || !term.IsTerminal(os.Stdin.Fd()) || !term.IsTerminal(os.Stderr.Fd()) {
+		return nil, nil
+	}
+	fmt.Fprintf(os.Stderr, "Run one? [1-%d, Enter to skip] ", len(found))
+	line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(found) {
+		return nil, nil
+	}
+	p := found[choice-1]
+	return runSearch(cmd, client, p.Query, relaxedOptions(p, opts))
+}
+
+// relaxedOptions returns the search options for a relaxed query: opts
+// without its date limits for a proposal that removes them.
+func relaxedOptions(p refine.Proposal, opts *eutils.SearchOptions) *eutils.SearchOptions {
+	if p.Kind != refine.KindDate || p.Clause != "" {
+		return opts
+	}
+	undated := *opts
+	undated.MinDate, undated.MaxDate = "", ""
+	return &undated
+}
+
+func init() {
+	refineCmd.Flags().StringVar(&flagRefineSave, "save", "", "Save the final query as an alert with this name")
+	searchCmd.Flags().BoolVar(&flagNoSuggest, "no-suggest", false, "Do not suggest MeSH terms to add, or broader queries when nothing matches")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/dedupe"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refcheck"
+	"github.com/henrybloomingdale/pubmed-cli/internal/retraction"
+	"github.com/spf13/cobra"
+)
+
+var flagRetractionsRIS string
+
+// retractionsCmd implements the retractions subcommand.
+var retractionsCmd = &cobra.Command{
+	Use:   "retractions [pmid|doi...]",
+	Short: "Flag retracted, corrected, or expression-of-concern references",
+	Long: `Check a reference list for articles PubMed marks as retracted, corrected
+(with an erratum or corrected and republished), or under an expression of
+concern, and list the notices against them.
+
+References are PMIDs or DOIs given as arguments (or - to read them from
+stdin), or the records of a RIS file given with --ris; RIS records without a
+PMID are matched by DOI, and those that cannot be matched are reported.
+Here --ris names the file to check, not an export.
+
+The command exits non-zero when any reference is flagged, so it can gate a
+manuscript build or CI job.`,
+	Example: `  pubmed retractions 31000001 10.1000/example.123
+  pubmed retractions --ris references.ris
+  pubmed retractions --ris references.ris --csv flagged.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (len(args) == 0) == (flagRetractionsRIS == "") {
+			return errUsage("give PMIDs or DOIs, or a reference list with --ris")
+		}
+		client := newEutilsClient()
+		var refs []reference
+		if flagRetractionsRIS != "" {
+			r, err := risReferences(cmd.Context(), client, flagRetractionsRIS)
+			if err != nil {
+				return err
+			}
+			refs = r
+		} else {
+			ids, err := stdinArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			pmids, err := resolveIDArgs(cmd.Context(), client, ids)
+			if err != nil {
+				return err
+			}
+			for _, id := range pmids {
+				refs = append(refs, reference{pmid: id})
+			}
+		}
+
+		report, err := checkReferences(cmd.Context(), client, refs)
+		if err != nil {
+			return err
+		}
+		for _, label := range report.Unmatched {
+			fmt.Fprintf(os.Stderr, "Warning: %s was not found in PubMed\n", label)
+		}
+		if err := output.FormatRetractionReport(cmd.OutOrStdout(), report, outputCfg()); err != nil {
+			return err
+		}
+		if n := len(report.Flagged); n > 0 {
+			return withExitCode(exitFlagged, fmt.Errorf("%d of %d references are retracted, corrected, or under an expression of concern", n, report.Checked))
+		}
+		return nil
+	},
+}
+
+func init() {
+	// A local --ris shadows the global RIS export for this command.
+	retractionsCmd.Flags().StringVar(&flagRetractionsRIS, "ris", "", "Check the references in this RIS file")
+}
+
+// reference is one entry of the list being checked: a label for reports
+// (empty for PMIDs given directly) and its PMID, if it was matched.
+type reference struct {
+	label string
+	pmid  string
+}
+
+// risReferences reads a RIS file, matching records without a PMID by DOI.
+func risReferences(ctx context.Context, client *eutils.Client, path string) ([]reference, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	source := filepath.Base(path)
+	records, err := dedupe.ReadRIS(f, source)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no references found in %s", path)
+	}
+
+	refs := make([]reference, len(records))
+	for i, rec := range records {
+		refs[i] = reference{label: fmt.Sprintf("%s #%d", source, rec.Index), pmid: rec.Article.PMID}
+		if refs[i].pmid != "" || rec.Article.DOI == "" {
+			continue
+		}
+		doi := refcheck.NormalizeDOI(rec.Article.DOI)
+		result, err := client.Search(ctx, fmt.Sprintf(`"%s"[doi]`, doi), &eutils.SearchOptions{Limit: 1})
+		if err != nil {
+			return nil, fmt.Errorf("resolving DOI %s: %w", doi, err)
+		}
+		if len(result.IDs) > 0 {
+			refs[i].pmid = result.IDs[0]
+		}
+	}
+	return refs, nil
+}
+
+// checkReferences fetches the matched references and checks each one.
+// References with no PMID, or whose PMID PubMed did not return, are
+// reported as unmatched.
+func checkReferences(ctx context.Context, client *eutils.Client, refs []reference) (retraction.Report, error) {
+	var pmids []string
+	seen := make(map[string]bool)
+	for _, r := range refs {
+		if r.pmid != "" && !seen[r.pmid] {
+			seen[r.pmid] = true
+			pmids = append(pmids, r.pmid)
+		}
+	}
+	byPMID := make(map[string]eutils.Article, len(pmids))
+	if len(pmids) > 0 {
+		fetched, err := fetchInBatches(ctx, client, pmids)
+		if err != nil {
+			return retraction.Report{}, fmt.Errorf("fetch failed: %w", err)
+		}
+		for _, a := range fetched {
+			byPMID[a.PMID] = a
+		}
+	}
+
+	var (
+		articles  []eutils.Article
+		labels    []string
+		unmatched []string
+	)
+	for _, r := range refs {
+		a, ok := byPMID[r.pmid]
+		if !ok {
+			label := r.label
+			if label == "" {
+				label = "PMID " + r.pmid
+			}
+			unmatched = append(unmatched, label)
+			continue
+		}
+		articles = append(articles, a)
+		labels = append(labels, r.label)
+	}
+	report := retraction.NewReport(articles, labels)
+	report.Unmatched = unmatched
+	return report, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd prints the JSON Schema for a structured output.
+var schemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Print JSON Schemas for --json and --ndjson output",
+	Long: `Without arguments, list the available schemas. With a name, print that
+schema as a JSON Schema (draft 2020-12) document.
+
+Every --json and --ndjson object carries a schema_version field. Minor bumps
+only add optional fields; major bumps remove, rename, or retype fields.`,
+	Example: `  pubmed schema
+  pubmed schema fetch > fetch.schema.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		if len(args) == 0 {
+			fmt.Fprintf(out, "Schema version %s\n\n", output.SchemaVersion)
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			for _, s := range output.SchemaNames() {
+				fmt.Fprintf(tw, "  %s\t%s\n", s[0], s[1])
+			}
+			return tw.Flush()
+		}
+
+		data, err := output.JSONSchema(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	},
+}
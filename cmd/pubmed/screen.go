@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/screen"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagScreenLog        string
+	flagScreenPMIDs      string
+	flagScreenCollection string
+	flagScreenDecision   string
+)
+
+// screenCmd opens the interactive title/abstract screening UI.
+var screenCmd = &cobra.Command{
+	Use:   "screen [query]",
+	Short: "Screen titles and abstracts interactively",
+	Long: `Step through articles one at a time and mark each include (i), exclude (e),
+or maybe (m); u clears a decision, arrow keys move, j/k scroll the abstract,
+and q quits. Decisions are saved to the log file (--log, default
+screening.json) as they are made, so a session can be resumed by running
+pubmed screen again with the same log.
+
+Articles come from a query (all results, up to PubMed's 10,000-record
+limit, or --limit), --pmids (- or a lone - argument reads them from stdin),
+or a library --collection; new articles are appended to an existing log.
+Use pubmed screen log to print or export the decisions.`,
+	Example: `  pubmed screen "fragile x syndrome AND metformin" --year 2010-2025
+  pubmed screen --log fxs.json        # resume
+  pubmed screen log --log fxs.json --csv screening.csv
+  pubmed screen log --decision include --ris included.ris`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if hasExportDestination(outputCfg()) {
+			return fmt.Errorf("screen does not export; use pubmed screen log with --csv, --ris, or --out")
+		}
+		list := flagScreenPMIDs
+		if isStdinArg(args) && list == "" {
+			list, args = "-", nil
+		}
+		if countSet(len(args) > 0, list != "", flagScreenCollection != "") > 1 {
+			return errUsage("give one of a query, --pmids, or --collection")
+		}
+		l, err := screen.Load(flagScreenLog)
+		if err != nil {
+			return err
+		}
+
+		var articles []eutils.Article
+		switch {
+		case list != "":
+			articles, err = exportPMIDArticles(cmd, list)
+		case flagScreenCollection != "":
+			articles, err = collectionArticles(flagScreenCollection)
+		case len(args) > 0:
+			q := buildQuery(args)
+			articles, err = exportQueryArticles(cmd, q)
+			if l.Query == "" {
+				l.Query = q
+			}
+		case len(l.Items) == 0:
+			return errUsage("nothing to screen: give a query, --pmids, or --collection, or --log an existing screening log")
+		}
+		if err != nil {
+			return err
+		}
+		if len(articles) > 0 {
+			if l.Created.IsZero() {
+				l.Created = time.Now()
+			}
+			added := l.Add(articles)
+			if err := l.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Added %d articles to %s\n", added, l.Path())
+		}
+
+		if !term.IsTerminal(os.Stdout.Fd()) {
+			return fmt.Errorf("screening needs an interactive terminal; use pubmed screen log to review decisions")
+		}
+		if err := screen.Run(l, cmd.OutOrStdout()); err != nil {
+			return err
+		}
+		p := l.Progress()
+		fmt.Fprintf(os.Stderr, "%d/%d screened (%d include, %d exclude, %d maybe); saved to %s\n",
+			p.Decided(), p.Total, p.Include, p.Exclude, p.Maybe, l.Path())
+		return nil
+	},
+}
+
+var screenLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Print or export screening decisions",
+	Long: `Print the screening log with a tally of decisions. --csv, --tsv, and .xlsx
+--out exports carry one row per article (PMID, decision, time, title,
+journal, year, DOI); --ris, --bibtex, and .csl.json exports carry the
+articles themselves, so --decision include --ris included.ris exports the
+included set.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagScreenDecision != "" && flagScreenDecision != "undecided" && !screen.ValidDecision(flagScreenDecision) {
+			return errUsage("invalid --decision %q (use include, exclude, maybe, or undecided)", flagScreenDecision)
+		}
+		if _, err := os.Stat(flagScreenLog); err != nil {
+			return fmt.Errorf("no screening log at %s", flagScreenLog)
+		}
+		l, err := screen.Load(flagScreenLog)
+		if err != nil {
+			return err
+		}
+		return output.FormatScreeningLog(cmd.OutOrStdout(), l.Filter(flagScreenDecision), outputCfg())
+	},
+}
+
+func init() {
+	screenCmd.PersistentFlags().StringVar(&flagScreenLog, "log", screen.DefaultPath, "Screening log file (created if missing)")
+	screenCmd.Flags().StringVar(&flagScreenPMIDs, "pmids", "", "Comma-separated PMIDs or DOIs to screen, or - to read them from stdin")
+	screenCmd.Flags().StringVar(&flagScreenCollection, "collection", "", "Screen a library collection (see pubmed lib)")
+	screenCmd.RegisterFlagCompletionFunc("collection", completeCollectionNames)
+	screenLogCmd.Flags().StringVar(&flagScreenDecision, "decision", "", "Only articles with this decision: include, exclude, maybe, or undecided")
+	screenLogCmd.RegisterFlagCompletionFunc("decision", cobra.FixedCompletions(
+		append(append([]string{}, screen.Decisions...), "undecided"), cobra.ShellCompDirectiveNoFileComp))
+	screenCmd.ValidArgsFunction = completeQueryTerms
+	screenCmd.AddCommand(screenLogCmd)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// envServeKeys holds comma-separated client API keys for pubmed serve.
+const envServeKeys = "PUBMED_CLI_SERVE_KEYS"
+
+var (
+	flagServeAddr     string
+	flagServeKeysFile string
+	flagServeRate     float64
+	flagServeBurst    int
+)
+
+// serveCmd runs the JSON HTTP API.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve search, fetch, links, and MeSH as a JSON HTTP API",
+	Long: `Run a long-lived HTTP server exposing the same lookups as the CLI, with the
+same JSON as --json:
+
+  GET /v1/search?q=...&limit=20&sort=date&mindate=2020&maxdate=2025&fetch=true
+  GET /v1/fetch?pmid=38000001,38000002
+  GET /v1/cited-by?pmid=...   /v1/references?pmid=...   /v1/related?pmid=...
+  GET /v1/mesh?term=...
+  GET /healthz
+
+Clients authenticate with "Authorization: Bearer KEY" or "X-API-Key: KEY".
+Keys come from --keys-file (one per line) and PUBMED_CLI_SERVE_KEYS
+(comma-separated); without keys the server only listens on loopback
+addresses. Each client (key, or IP without auth) is limited to --rate
+requests per second with bursts of --burst, and all clients share the NCBI
+rate limit of the server's own API key.`,
+	Example: `  PUBMED_CLI_SERVE_KEYS=team-secret pubmed serve --addr :8080
+  curl -H "Authorization: Bearer team-secret" "localhost:8080/v1/search?q=fragile+x&limit=5"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keys, err := serveKeys()
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 && !loopbackAddr(flagServeAddr) {
+			return fmt.Errorf("refusing to serve %s without API keys; set %s or --keys-file", flagServeAddr, envServeKeys)
+		}
+
+		srv := server.New(newEutilsClient(), newMeshClient(), server.Options{
+			APIKeys: keys,
+			Rate:    flagServeRate,
+			Burst:   flagServeBurst,
+			Log:     os.Stderr,
+		})
+		httpSrv := &http.Server{
+			Addr:              flagServeAddr,
+			Handler:           srv.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		errc := make(chan error, 1)
+		go func() { errc <- httpSrv.ListenAndServe() }()
+		auth := "API keys required"
+		if !srv.Authenticated() {
+			auth = "no authentication"
+		}
+		fmt.Fprintf(os.Stderr, "Serving on %s (%s)\n", flagServeAddr, auth)
+
+		select {
+		case err := <-errc:
+			return err
+		case <-ctx.Done():
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&flagServeAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&flagServeKeysFile, "keys-file", "", "File of accepted client API keys, one per line")
+	serveCmd.Flags().Float64Var(&flagServeRate, "rate", server.DefaultRate, "Requests per second allowed per client")
+	serveCmd.Flags().IntVar(&flagServeBurst, "burst", server.DefaultBurst, "Request burst allowed per client")
+}
+
+// serveKeys collects client keys from PUBMED_CLI_SERVE_KEYS and --keys-file,
+// skipping blank lines and # comments in the file.
+func serveKeys() ([]string, error) {
+	var keys []string
+	for _, k := range strings.Split(os.Getenv(envServeKeys), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	if flagServeKeysFile == "" {
+		return keys, nil
+	}
+	f, err := os.Open(flagServeKeysFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading --keys-file: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading --keys-file: %w", err)
+	}
+	return keys, nil
+}
+
+// loopbackAddr reports whether addr only accepts local connections.
+func loopbackAddr(addr string) bool {
+	host := addr
+	if i := strings.LastIndex(addr, ":"); i >= 0 {
+		host = addr[:i]
+	}
+	host = strings.Trim(host, "[]")
+	return host == "localhost" || host == "::1" || strings.HasPrefix(host, "127.")
+}
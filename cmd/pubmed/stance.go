@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/stance"
+	"github.com/spf13/cobra"
+)
+
+var flagStance bool
+
+func init() {
+	citedByCmd.Flags().BoolVar(&flagStance, "stance", false, "Read the citing papers' PMC full text and classify how each cites the article")
+}
+
+// runStance reports how the first --limit citing articles of pmid cite
+// it, from the sentences that cite it in their PMC full text.
+func runStance(cmd *cobra.Command, client *eutils.Client, pmid string, result *eutils.LinkResult) error {
+	cfg := outputCfg()
+	if cfg.NDJSON {
+		return errUsage("--ndjson is not supported with --stance; use --json")
+	}
+	cited, err := client.Fetch(cmd.Context(), []string{pmid})
+	if err != nil {
+		return fmt.Errorf("fetch failed: %w", err)
+	}
+	var doi string
+	if len(cited) > 0 {
+		doi = cited[0].DOI
+	}
+
+	limit := min(flagLimit, len(result.Links))
+	pmids := make([]string, limit)
+	for i := range pmids {
+		pmids[i] = result.Links[i].ID
+	}
+	var articles []eutils.Article
+	if len(pmids) > 0 {
+		if articles, err = client.Fetch(cmd.Context(), pmids); err != nil {
+			return fmt.Errorf("fetch failed: %w", err)
+		}
+	}
+
+	ft := fulltext.NewClient(newBaseClient(), "")
+	citations := make([]stance.Citation, len(articles))
+	for i, a := range articles {
+		var contexts []string
+		if a.PMCID != "" {
+			contexts, err = citingContexts(cmd, ft, a.PMCID, pmid, doi)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: PMID %s: %v\n", a.PMID, err)
+			}
+		}
+		citations[i] = stance.NewCitation(a, contexts)
+	}
+
+	if cfg.RISFile != "" || cfg.BibTeXFile != "" || cfg.CSLFile != "" {
+		citeCfg := output.OutputConfig{RISFile: cfg.RISFile, BibTeXFile: cfg.BibTeXFile, CSLFile: cfg.CSLFile}
+		if err := output.FormatArticles(io.Discard, articles, citeCfg); err != nil {
+			return err
+		}
+	}
+	return output.FormatStanceReport(cmd.OutOrStdout(), stance.NewReport(pmid, len(result.Links), citations), cfg)
+}
+
+// citingContexts returns the sentences of a PMC article that cite the
+// paper with pmid or doi, or nil when PMC has only its front matter.
+func citingContexts(cmd *cobra.Command, ft *fulltext.Client, pmcid, pmid, doi string) ([]string, error) {
+	data, doc, err := ft.PMC(cmd.Context(), pmcid)
+	if err != nil || !doc.HasBody {
+		return nil, err
+	}
+	return stance.Contexts(data, pmid, doi)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/timeline"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTimelineFrom   string
+	flagTimelineFormat string
+	flagTimelineFile   string
+)
+
+// timelineCmd renders a chronological timeline of a topic or of the
+// articles citing a landmark paper.
+var timelineCmd = &cobra.Command{
+	Use:   "timeline [query]",
+	Short: "Chronological timeline of key papers for a topic or a landmark paper",
+	Long: `Render the key papers for a topic, or a landmark article and the papers citing
+it (--from <pmid>), as a timeline grouped by year, with each paper's main
+finding in one line.
+
+For a topic, the top --limit search results (PubMed's Best Match order, or
+--sort) are placed on the timeline; --year and --type narrow the search. For
+a landmark, the first --limit citing articles are.
+
+Summaries are taken from each abstract's conclusions, or its closing
+sentence, so they are the authors' words rather than a synthesis. Output is
+Markdown by default, or a standalone HTML page with --format html (or an
+--file ending in .html); --json prints the timeline's data.`,
+	Example: `  pubmed timeline "fragile x syndrome AND metformin" --limit 30
+  pubmed timeline --from 38000001 --format html --file timeline.html`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (len(args) == 0) == (flagTimelineFrom == "") {
+			return errUsage("give a query or a landmark PMID with --from")
+		}
+		format := flagTimelineFormat
+		if format == "" {
+			format = timeline.FormatMarkdown
+			if ext := strings.ToLower(filepath.Ext(flagTimelineFile)); ext == ".html" || ext == ".htm" {
+				format = timeline.FormatHTML
+			}
+		}
+		if format != timeline.FormatMarkdown && format != timeline.FormatHTML {
+			return errUsage("--format must be markdown or html")
+		}
+
+		client := newEutilsClient()
+		var (
+			tl  *timeline.Timeline
+			err error
+		)
+		if flagTimelineFrom != "" {
+			tl, err = landmarkTimeline(cmd, client, flagTimelineFrom)
+		} else {
+			tl, err = topicTimeline(cmd, client, args)
+		}
+		if err != nil {
+			return err
+		}
+		if tl.Len() == 0 {
+			return fmt.Errorf("no articles found for the timeline")
+		}
+
+		if flagTimelineFile == "" {
+			return output.FormatTimeline(cmd.OutOrStdout(), tl, format, outputCfg())
+		}
+		f, err := os.Create(flagTimelineFile)
+		if err != nil {
+			return fmt.Errorf("creating timeline file: %w", err)
+		}
+		defer f.Close()
+		if err := output.FormatTimeline(f, tl, format, outputCfg()); err != nil {
+			return fmt.Errorf("writing timeline: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing timeline: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Timeline of %d articles written to %s\n", tl.Len(), flagTimelineFile)
+		return nil
+	},
+}
+
+func init() {
+	timelineCmd.Flags().StringVar(&flagTimelineFrom, "from", "", "Landmark PMID: show it and the articles citing it")
+	timelineCmd.Flags().StringVar(&flagTimelineFormat, "format", "", "Timeline format: markdown or html (default from --file extension, else markdown)")
+	timelineCmd.Flags().StringVar(&flagTimelineFile, "file", "", "Write the timeline to this file instead of stdout")
+	timelineCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{timeline.FormatMarkdown, timeline.FormatHTML}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// topicTimeline places the top search results for a query on a timeline.
+func topicTimeline(cmd *cobra.Command, client *eutils.Client, args []string) (*timeline.Timeline, error) {
+	q := buildQuery(args)
+	opts := &eutils.SearchOptions{Limit: flagLimit, Sort: strings.ToLower(flagSort)}
+	var err error
+	if opts.MinDate, opts.MaxDate, err = dateRange(); err != nil {
+		return nil, err
+	}
+	result, err := client.Search(cmd.Context(), q, opts)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	recordSearch(q, opts, result)
+	articles, err := fetchInBatches(cmd.Context(), client, result.IDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+	tl := timeline.Build("Timeline: "+strings.Join(args, " "), orderArticles(articles, result.IDs), "")
+	tl.Query = q
+	return tl, nil
+}
+
+// landmarkTimeline places a landmark article and the articles citing it on
+// a timeline.
+func landmarkTimeline(cmd *cobra.Command, client *eutils.Client, pmid string) (*timeline.Timeline, error) {
+	if err := validatePMID(pmid); err != nil {
+		return nil, fmt.Errorf("invalid --from PMID: %w", err)
+	}
+	links, err := client.CitedBy(cmd.Context(), pmid)
+	if err != nil {
+		return nil, fmt.Errorf("cited-by lookup failed: %w", err)
+	}
+	pmids := []string{pmid}
+	for _, l := range links.Links {
+		if len(pmids) > flagLimit {
+			break
+		}
+		pmids = append(pmids, l.ID)
+	}
+	articles, err := fetchInBatches(cmd.Context(), client, pmids)
+	if err != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+	articles = orderArticles(articles, pmids)
+	if len(articles) == 0 || articles[0].PMID != pmid {
+		return nil, fmt.Errorf("PMID %s was not found in PubMed", pmid)
+	}
+	return timeline.Build("Citation timeline: "+articles[0].Title, articles, pmid), nil
+}
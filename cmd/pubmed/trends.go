@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultTrendYears is how many years trends covers without --year.
+const defaultTrendYears = 10
+
+// trendsCmd charts publication counts per year.
+var trendsCmd = &cobra.Command{
+	Use:   "trends <query> [query...]",
+	Short: "Publication counts per year for one or more queries",
+	Long: `Count the articles matching each query in every year of --year (default:
+the last 10 years, including the current partial year) and chart them. Each
+argument is a separate query, so quote multi-word queries. Counts come from
+count-only searches, one per query and year.
+
+Use --csv or --json to export the numbers.`,
+	Example: `  pubmed trends "fragile x syndrome" "angelman syndrome" --year 2000-2025 --human
+  pubmed trends "lecanemab" --csv lecanemab.csv`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to := time.Now().Year()
+		from := to - defaultTrendYears + 1
+		if flagYear != "" {
+			minYear, maxYear, err := parseYearRange(flagYear)
+			if err != nil {
+				return errUsage("invalid --year value %q: %w", flagYear, err)
+			}
+			from, _ = strconv.Atoi(minYear)
+			to, _ = strconv.Atoi(maxYear)
+		}
+		// Trends count whole years, so --since and --until give theirs.
+		since, until, err := sinceUntil(time.Now())
+		if err != nil {
+			return err
+		}
+		if since != "" {
+			from, _ = strconv.Atoi(since[:4])
+		}
+		if until != "" {
+			to, _ = strconv.Atoi(until[:4])
+			if since == "" {
+				from = to - defaultTrendYears + 1
+			}
+		}
+
+		queries := make([]string, len(args))
+		for i, q := range args {
+			queries[i] = buildQuery([]string{q})
+		}
+		trends, err := analyze.Trends(cmd.Context(), newEutilsClient(), queries, from, to)
+		if err != nil {
+			return fmt.Errorf("trend counts failed: %w", err)
+		}
+		// Label each trend with the query as typed, without the --type filter.
+		for i := range trends {
+			trends[i].Query = args[i]
+		}
+		return output.FormatTrends(cmd.OutOrStdout(), trends, outputCfg())
+	},
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/triage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTriageRIS     string
+	flagTriageTop     int
+	flagTriageIDsOnly bool
+)
+
+// triageCmd ranks a list of articles against a question.
+var triageCmd = &cobra.Command{
+	Use:   "triage <question> [pmid|doi...]",
+	Short: "Rank a list of articles by relevance to a question",
+	Long: `Fetch a list of articles and rank them by how closely their titles,
+abstracts, MeSH terms, and keywords match a question, with a score, the
+question terms each article lacks, and the sentence that matches best.
+Articles are PMIDs or DOIs given after the question (or - to read them from
+stdin), or the records of a RIS file given with --ris; here --ris names the
+file to rank, not an export.
+
+Ranking is lexical (BM25), so it orders a list for reading rather than
+judging relevance: an article that answers the question in other words
+ranks low. --ids-only prints the ranked PMIDs for piping into other
+commands.`,
+	Example: `  pubmed triage "does metformin improve language in fragile x syndrome" 38000001 38000002 --human
+  pubmed search "fragile x syndrome" --limit 100 --ids-only | pubmed triage "metformin and language" - --top 10
+  pubmed triage "ketamine for suicidal ideation" --ris included.ris --csv triage.csv`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		question := args[0]
+		if len(triage.Terms(question)) == 0 {
+			return fmt.Errorf("the question has no terms to rank by")
+		}
+		if (len(args) == 1) == (flagTriageRIS == "") {
+			return errUsage("give PMIDs or DOIs after the question, or a reference list with --ris")
+		}
+		if flagTriageTop < 0 {
+			return errUsage("--top must not be negative")
+		}
+
+		client := newEutilsClient()
+		var pmids []string
+		if flagTriageRIS != "" {
+			refs, err := risReferences(cmd.Context(), client, flagTriageRIS)
+			if err != nil {
+				return err
+			}
+			seen := make(map[string]bool)
+			for _, r := range refs {
+				switch {
+				case r.pmid == "":
+					fmt.Fprintf(os.Stderr, "Warning: %s was not found in PubMed\n", r.label)
+				case !seen[r.pmid]:
+					seen[r.pmid] = true
+					pmids = append(pmids, r.pmid)
+				}
+			}
+		} else {
+			ids, err := stdinArgs(cmd, args[1:])
+			if err != nil {
+				return err
+			}
+			if pmids, err = resolveIDArgs(cmd.Context(), client, ids); err != nil {
+				return err
+			}
+		}
+		if len(pmids) == 0 {
+			return errNoResults("none of the references were found in PubMed")
+		}
+
+		articles, err := fetchInBatches(cmd.Context(), client, pmids)
+		if err != nil {
+			return fmt.Errorf("fetch failed: %w", err)
+		}
+		articles = orderArticles(articles, pmids)
+		if len(articles) == 0 {
+			return errNoResults("none of the PMIDs were found in PubMed")
+		}
+
+		results := triage.Rank(question, articles)
+		if flagTriageTop > 0 && len(results) > flagTriageTop {
+			results = results[:flagTriageTop]
+		}
+		if flagTriageIDsOnly {
+			for _, r := range results {
+				fmt.Fprintln(cmd.OutOrStdout(), r.PMID)
+			}
+			return nil
+		}
+		return output.FormatTriage(cmd.OutOrStdout(), question, results, outputCfg())
+	},
+}
+
+func init() {
+	// A local --ris shadows the global RIS export for this command.
+	triageCmd.Flags().StringVar(&flagTriageRIS, "ris", "", "Rank the references in this RIS file")
+	triageCmd.Flags().IntVar(&flagTriageTop, "top", 0, "Number of articles to report (0 for all)")
+	triageCmd.Flags().BoolVar(&flagTriageIDsOnly, "ids-only", false, "Print only the ranked PMIDs, one per line")
+}
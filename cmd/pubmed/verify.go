@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/progress"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refcheck"
+	"github.com/spf13/cobra"
+)
+
+var flagVerifyFile string
+
+// verifyCmd checks citations given as text against PubMed.
+var verifyCmd = &cobra.Command{
+	Use:   "verify <citation...>",
+	Short: "Check free-text citations against PubMed and flag mismatches",
+	Long: `Find the article each citation refers to and check what the citation claims
+about it. A citation can be a formatted reference or free text such as
+"Smith et al. 2019 showed X in NEJM", from which the first author, year,
+journal, and topic words are read.
+
+Each argument is one citation; --file reads a list of them, such as the
+reference section of a manuscript, one per numbered entry or paragraph
+(- reads stdin). References giving journal, year, volume, first page, and
+first author are matched with ECitMatch; the rest are searched for by PMID,
+DOI, title, and author, year, and topic. The match's first author, year,
+journal, title, pages, and DOI are then compared with the citation's.
+
+Statuses are those of refcheck: a citation whose article exists but with
+other details is VERIFIED_WITH_CORRECTION, one no search finds is
+NOT_IN_PUBMED, and one whose first author publishes on other topics or
+whose DOI does not resolve is POSSIBLY_FABRICATED. A CANDIDATE match is the
+closest article found for a citation too vague to confirm.
+
+The command exits non-zero when any citation is not found, possibly
+fabricated, or contradicted by its match, so it can check a draft's
+references in CI. --csv exports the report.`,
+	Example: `  pubmed verify "Smith et al. 2019 showed metformin improves language in NEJM" --human
+  pubmed verify "Bear MF, Huber KM, Warren ST. The mGluR theory of fragile X mental retardation. Trends Neurosci. 2004;27(7):370-377."
+  pubmed verify --file references.txt --csv report.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (len(args) == 0) == (flagVerifyFile == "") {
+			return errUsage("give citations as arguments, or a file of them with --file")
+		}
+		var refs []refcheck.ParsedReference
+		if flagVerifyFile != "" {
+			text, err := readVerifyFile(cmd, flagVerifyFile)
+			if err != nil {
+				return err
+			}
+			refs = refcheck.ParseCitations(text)
+			if len(refs) == 0 {
+				return fmt.Errorf("no citations found in %s", flagVerifyFile)
+			}
+		} else {
+			for i, arg := range args {
+				ref := refcheck.ParseCitation(arg)
+				ref.Index = i + 1
+				refs = append(refs, ref)
+			}
+		}
+
+		ctx := cmd.Context()
+		client := newEutilsClient()
+		resolver := refcheck.NewResolver(client)
+		detector := refcheck.NewHallucinationDetector(client)
+		results := make([]refcheck.VerifiedReference, len(refs))
+		var p *progress.Reporter
+		if len(refs) > 1 {
+			p = newProgress("Checking citations", len(refs))
+		}
+		for i, ref := range refs {
+			if len(ref.Authors) == 0 && ref.Title == "" && ref.PMID == "" && ref.DOI == "" && len(ref.Keywords) == 0 {
+				fmt.Fprintf(os.Stderr, "Warning: citation %d names no author, title, or identifier to search for\n", ref.Index)
+			}
+			results[i] = resolver.ResolveCitation(ctx, ref)
+			detector.Check(ctx, ref, &results[i])
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			p.Add(1)
+		}
+		p.Done()
+
+		source := ""
+		if flagVerifyFile != "" && flagVerifyFile != "-" {
+			source = flagVerifyFile
+		}
+		report := refcheck.BuildReport(source, results, nil)
+		if flagCSV != "" {
+			f, err := os.Create(flagCSV)
+			if err != nil {
+				return fmt.Errorf("failed to create CSV file: %w", err)
+			}
+			defer f.Close()
+			if err := refcheck.FormatCSV(f, report); err != nil {
+				return fmt.Errorf("failed to write CSV: %w", err)
+			}
+		}
+		var err error
+		if outputCfg().Human {
+			err = refcheck.FormatHuman(cmd.OutOrStdout(), report)
+		} else {
+			err = refcheck.FormatJSON(cmd.OutOrStdout(), report)
+		}
+		if err != nil {
+			return err
+		}
+
+		flagged := 0
+		for _, r := range results {
+			if r.Status == refcheck.StatusNotInPubMed || r.Status == refcheck.StatusPossiblyFabricated || len(r.Corrections) > 0 {
+				flagged++
+			}
+		}
+		if flagged > 0 {
+			return withExitCode(exitFlagged, fmt.Errorf("%d of %d citations were not found or do not match PubMed", flagged, len(results)))
+		}
+		return nil
+	},
+}
+
+// readVerifyFile reads the citation list at path, or stdin for -.
+func readVerifyFile(cmd *cobra.Command, path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		return string(data), err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".docx") {
+		return "", fmt.Errorf("%s is a Word document; check it with pubmed refcheck", path)
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&flagVerifyFile, "file", "", "Read citations from this file, one per numbered entry or paragraph (- for stdin)")
+	verifyCmd.MarkFlagFilename("file", "txt", "md")
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/webhook"
+)
+
+// webhookTimeout bounds a webhook POST, so a slow endpoint cannot hold up
+// a cron job.
+const webhookTimeout = 30 * time.Second
+
+var flagWebhook string
+
+func init() {
+	usage := "POST a summary to this Slack, Discord, or JSON webhook URL (or set " + webhook.EnvURL + ")"
+	alertRunCmd.Flags().StringVar(&flagWebhook, "webhook", "", usage)
+	batchRunCmd.Flags().StringVar(&flagWebhook, "webhook", "", usage)
+}
+
+// notify posts e to the --webhook or PUBMED_CLI_WEBHOOK URL, if either is
+// set. Failures are warnings: the results were already printed.
+func notify(ctx context.Context, e webhook.Event) {
+	u := flagWebhook
+	if u == "" {
+		u = secretEnv(webhook.EnvURL)
+	}
+	if u == "" {
+		return
+	}
+	if flagOffline {
+		fmt.Fprintln(os.Stderr, "Warning: not posting to the webhook with --offline")
+		return
+	}
+	if err := webhook.Post(ctx, &http.Client{Timeout: webhookTimeout}, u, e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
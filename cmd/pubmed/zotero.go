@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/keychain"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"github.com/henrybloomingdale/pubmed-cli/internal/zotero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagZoteroPMIDs      string
+	flagZoteroLib        string
+	flagZoteroCollection string
+	flagZoteroGroup      string
+)
+
+// zoteroCmd groups the Zotero Web API commands.
+var zoteroCmd = &cobra.Command{
+	Use:   "zotero",
+	Short: "Send references to a Zotero library",
+	Long: `Create Zotero items directly from PubMed records through the Zotero Web API.
+
+The API key (create one at https://www.zotero.org/settings/keys with write
+access) is read from ZOTERO_API_KEY, or else from the system keychain, where
+'pubmed zotero login' stores it (macOS Keychain, or the Secret Service via
+secret-tool on Linux).`,
+}
+
+var zoteroLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Check a Zotero API key read from stdin and save it in the keychain",
+	Example: `  pubmed zotero login < zotero.key
+  pbpaste | pubmed zotero login`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("reading API key: %w", err)
+		}
+		key := strings.TrimSpace(string(data))
+		if key == "" {
+			return fmt.Errorf("no API key on stdin")
+		}
+		info, err := zotero.NewClient(newBaseClient(), key, "").Key(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if err := keychain.Set(cmd.Context(), zotero.KeychainAccount, key); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved Zotero API key for %s (%s)\n", info.Username, zotero.UserLibrary(info.UserID))
+		return nil
+	},
+}
+
+var zoteroPushCmd = &cobra.Command{
+	Use:   "push [query]",
+	Short: "Create Zotero items for a search, PMID list, or library collection",
+	Long: `Create a Zotero item for every article matching a query, listed in --pmids
+(PMIDs or DOIs; - reads them from stdin), or saved in a library collection
+(--lib). Items carry the abstract, MeSH terms as tags, and the PMID and
+PMCID in Extra; a linked-URL attachment points at the PMC full text, and
+with --oa at the best open-access copy from Unpaywall (needs
+UNPAYWALL_EMAIL). Notes saved with 'pubmed note' become child notes.
+
+Items go into --collection, created when the library has no collection by
+that name, in your personal library or in the group given by --group.`,
+	Example: `  pubmed zotero push "fragile x syndrome" --year 2020-2025 --limit 50 --collection "My Review"
+  pubmed zotero push --pmids 38000001,38000002 --collection "My Review" --oa
+  pubmed zotero push --lib fxs-trials --collection "FXS trials" --group 123456
+  pubmed search "fragile x" --ids-only | pubmed zotero push - --collection "My Review"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list := flagZoteroPMIDs
+		if isStdinArg(args) && list == "" {
+			list, args = "-", nil
+		}
+		if countSet(len(args) > 0, list != "", flagZoteroLib != "") != 1 {
+			return errUsage("give one of a query, --pmids, or --lib")
+		}
+		client, err := newZoteroClient(cmd)
+		if err != nil {
+			return err
+		}
+		var oa *fulltext.Client
+		if flagOA {
+			if oa, err = newOAClient(); err != nil {
+				return err
+			}
+		}
+
+		var articles []eutils.Article
+		switch {
+		case list != "":
+			articles, err = exportPMIDArticles(cmd, list)
+		case flagZoteroLib != "":
+			articles, err = collectionArticles(flagZoteroLib)
+		default:
+			articles, err = exportQueryArticles(cmd, buildQuery(args))
+		}
+		if err != nil {
+			return err
+		}
+		if flagZoteroLib == "" {
+			annotateArticles(articles)
+		}
+		if oa != nil {
+			annotateOA(cmd.Context(), oa, articles)
+		}
+		if len(articles) == 0 {
+			return fmt.Errorf("no articles to push")
+		}
+
+		var collection string
+		if flagZoteroCollection != "" {
+			if collection, err = client.Collection(cmd.Context(), flagZoteroCollection); err != nil {
+				return err
+			}
+		}
+		r, err := client.Push(cmd.Context(), articles, collection)
+		if r != nil {
+			r.Collection = flagZoteroCollection
+			if ferr := output.FormatZoteroReport(cmd.OutOrStdout(), r, outputCfg()); ferr != nil && err == nil {
+				err = ferr
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	zoteroPushCmd.ValidArgsFunction = completeQueryTerms
+	zoteroPushCmd.Flags().StringVar(&flagZoteroPMIDs, "pmids", "", "Comma-separated PMIDs or DOIs to push, or - to read them from stdin")
+	zoteroPushCmd.Flags().StringVar(&flagZoteroLib, "lib", "", "Push a library collection (see pubmed lib)")
+	zoteroPushCmd.Flags().StringVar(&flagZoteroCollection, "collection", "", "Zotero collection to add the items to, created if missing")
+	zoteroPushCmd.Flags().StringVar(&flagZoteroGroup, "group", "", "Push to the Zotero group library with this ID instead of your own")
+	zoteroPushCmd.Flags().BoolVar(&flagOA, "oa", false, "Attach the best open-access copy from Unpaywall (needs UNPAYWALL_EMAIL)")
+	zoteroPushCmd.RegisterFlagCompletionFunc("lib", completeCollectionNames)
+	zoteroCmd.AddCommand(zoteroLoginCmd, zoteroPushCmd)
+}
+
+// newZoteroClient returns a client for the --group library, or the API
+// key owner's personal library.
+func newZoteroClient(cmd *cobra.Command) (*zotero.Client, error) {
+	key := secretEnv(zotero.EnvAPIKey)
+	if key == "" {
+		var err error
+		key, err = keychain.Get(cmd.Context(), zotero.KeychainAccount)
+		if errors.Is(err, keychain.ErrNotFound) {
+			return nil, fmt.Errorf("no Zotero API key: set %s or run 'pubmed zotero login'", zotero.EnvAPIKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading Zotero API key from the keychain (or set %s): %w", zotero.EnvAPIKey, err)
+		}
+	}
+	client := zotero.NewClient(newBaseClient(), key, "")
+	if flagZoteroGroup != "" {
+		client.Library = zotero.GroupLibrary(flagZoteroGroup)
+		return client, nil
+	}
+	info, err := client.Key(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	client.Library = zotero.UserLibrary(info.UserID)
+	return client, nil
+}
@@ -0,0 +1,148 @@
+// Package alert stores named PubMed searches and reports the results that
+// are new since each search last ran.
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// EnvConfigDir overrides where pubmed-cli keeps user data such as alerts.
+const EnvConfigDir = "PUBMED_CLI_CONFIG_DIR"
+
+// DefaultLimit caps the PMIDs one run retrieves per alert.
+const DefaultLimit = 500
+
+// lookback widens each run's Entrez-date window so records indexed late on
+// the previous run's day are not missed; the seen set removes repeats.
+const lookback = 2 * 24 * time.Hour
+
+// Searcher runs PubMed searches. *eutils.Client satisfies it.
+type Searcher interface {
+	Search(ctx context.Context, query string, opts *eutils.SearchOptions) (*eutils.SearchResult, error)
+}
+
+// Result is what one alert found on a run.
+type Result struct {
+	Name      string           `json:"name"`
+	Query     string           `json:"query"`
+	New       []string         `json:"new"`
+	Truncated bool             `json:"truncated,omitempty"`
+	Articles  []eutils.Article `json:"articles,omitempty"`
+}
+
+// Alert is a saved search and the PMIDs it has already reported.
+type Alert struct {
+	Name    string    `json:"name"`
+	Query   string    `json:"query"`
+	Created time.Time `json:"created"`
+	LastRun time.Time `json:"last_run"`
+	Seen    []string  `json:"seen"`
+	// Snapshots are the result sets recorded by alert diff, oldest first.
+	Snapshots []Snapshot `json:"snapshots,omitempty"`
+}
+
+// Book is the set of saved alerts. internal/store keeps it in the user
+// database.
+type Book struct {
+	Alerts []*Alert `json:"alerts"`
+}
+
+// Load reads alerts saved as JSON at path, as releases before the user
+// database kept them; a missing file is an empty book.
+func Load(path string) (*Book, error) {
+	b := &Book{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading alerts: %w", err)
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Get returns the named alert, or nil.
+func (b *Book) Get(name string) *Alert {
+	for _, a := range b.Alerts {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// Add saves a new alert. It fails if the name is taken.
+func (b *Book) Add(name, query string, now time.Time) (*Alert, error) {
+	if name == "" || query == "" {
+		return nil, fmt.Errorf("an alert needs a name and a query")
+	}
+	if b.Get(name) != nil {
+		return nil, fmt.Errorf("alert %q already exists", name)
+	}
+	a := &Alert{Name: name, Query: query, Created: now}
+	b.Alerts = append(b.Alerts, a)
+	sort.Slice(b.Alerts, func(i, j int) bool { return b.Alerts[i].Name < b.Alerts[j].Name })
+	return a, nil
+}
+
+// Remove deletes the named alert.
+func (b *Book) Remove(name string) error {
+	for i, a := range b.Alerts {
+		if a.Name == name {
+			b.Alerts = append(b.Alerts[:i], b.Alerts[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no alert named %q", name)
+}
+
+// Check searches for a's query and returns the PMIDs it has not reported
+// before, newest first, then marks them seen. The first check of an alert
+// covers the whole result set (up to limit) and establishes its baseline.
+// Truncated is set when more records matched than limit allowed; LastRun
+// then stays where it was, so a check with a higher limit searches the same
+// window again and reaches the records this one left out.
+func Check(ctx context.Context, s Searcher, a *Alert, limit int, now time.Time) (fresh []string, truncated bool, err error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	opts := &eutils.SearchOptions{Limit: limit}
+	if !a.LastRun.IsZero() {
+		opts.DateType = "edat"
+		opts.MinDate = a.LastRun.Add(-lookback).Format("2006/01/02")
+		opts.MaxDate = "3000"
+	}
+
+	result, err := s.Search(ctx, a.Query, opts)
+	if err != nil {
+		return nil, false, fmt.Errorf("alert %q: %w", a.Name, err)
+	}
+
+	seen := make(map[string]bool, len(a.Seen))
+	for _, id := range a.Seen {
+		seen[id] = true
+	}
+	for _, id := range result.IDs {
+		if !seen[id] {
+			seen[id] = true
+			fresh = append(fresh, id)
+			a.Seen = append(a.Seen, id)
+		}
+	}
+	truncated = result.Count > len(result.IDs)
+	if !truncated {
+		a.LastRun = now
+	}
+	return fresh, truncated, nil
+}
@@ -0,0 +1,181 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+type fakeSearcher struct {
+	ids   []string
+	count int // matches reported, when more than ids
+	opts  *eutils.SearchOptions
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, query string, opts *eutils.SearchOptions) (*eutils.SearchResult, error) {
+	f.opts = opts
+	return &eutils.SearchResult{Count: max(f.count, len(f.ids)), IDs: f.ids}, nil
+}
+
+func TestBook_AddLoad(t *testing.T) {
+	dir := t.TempDir()
+	if b, err := Load(filepath.Join(dir, "missing.json")); err != nil || len(b.Alerts) != 0 {
+		t.Fatalf("missing file should load as empty: %+v, %v", b, err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	b := &Book{}
+	if _, err := b.Add("fxs", "fragile x syndrome", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := b.Add("asd", "autism", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := b.Add("fxs", "other", now); err == nil {
+		t.Error("expected duplicate name to be rejected")
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "alerts.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(loaded.Alerts) != 2 || loaded.Alerts[0].Name != "asd" {
+		t.Fatalf("expected alerts sorted by name, got %+v", loaded.Alerts)
+	}
+	if a := loaded.Get("fxs"); a == nil || a.Query != "fragile x syndrome" {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+
+	if err := loaded.Remove("asd"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := loaded.Remove("asd"); err == nil {
+		t.Error("expected removing a missing alert to fail")
+	}
+}
+
+func TestCheck_ReportsOnlyNewPMIDs(t *testing.T) {
+	a := &Alert{Name: "fxs", Query: "fragile x"}
+	s := &fakeSearcher{ids: []string{"3", "2", "1"}}
+	first := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	fresh, _, err := Check(context.Background(), s, a, 0, first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(fresh, ",") != "3,2,1" {
+		t.Errorf("first check should report the baseline, got %v", fresh)
+	}
+	if s.opts.MinDate != "" || s.opts.Limit != DefaultLimit {
+		t.Errorf("first check should search without a date window, got %+v", s.opts)
+	}
+
+	s.ids = []string{"5", "4", "3"}
+	fresh, _, err = Check(context.Background(), s, a, 100, first.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(fresh, ",") != "5,4" {
+		t.Errorf("expected only new PMIDs, got %v", fresh)
+	}
+	if s.opts.DateType != "edat" || s.opts.MinDate != "2026/03/08" {
+		t.Errorf("expected an Entrez-date window from two days before the last run, got %+v", s.opts)
+	}
+	if !a.LastRun.Equal(first.Add(24 * time.Hour)) {
+		t.Errorf("expected LastRun to advance, got %v", a.LastRun)
+	}
+	if len(a.Seen) != 5 {
+		t.Errorf("expected 5 seen PMIDs, got %v", a.Seen)
+	}
+}
+
+func TestCheck_TruncatedKeepsLastRun(t *testing.T) {
+	last := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	a := &Alert{Name: "fxs", Query: "fragile x", LastRun: last, Seen: []string{"1"}}
+	s := &fakeSearcher{ids: []string{"5", "4"}, count: 4}
+	now := last.Add(24 * time.Hour)
+
+	fresh, truncated, err := Check(context.Background(), s, a, 2, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !truncated || strings.Join(fresh, ",") != "5,4" {
+		t.Errorf("got %v, truncated %v; want 5,4 truncated", fresh, truncated)
+	}
+	if !a.LastRun.Equal(last) {
+		t.Errorf("LastRun advanced to %v on a truncated run", a.LastRun)
+	}
+
+	// A run with a higher limit searches the same window and reaches the rest.
+	s.ids, s.count = []string{"5", "4", "3", "2"}, 0
+	fresh, truncated, err = Check(context.Background(), s, a, 10, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if truncated || strings.Join(fresh, ",") != "3,2" {
+		t.Errorf("got %v, truncated %v; want 3,2", fresh, truncated)
+	}
+	if s.opts.MinDate != "2026/03/08" || !a.LastRun.Equal(now.Add(time.Hour)) {
+		t.Errorf("window from %s, LastRun %v", s.opts.MinDate, a.LastRun)
+	}
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	a := &Alert{Name: "fxs", Query: "fragile x", Seen: []string{"9", "10", "11"}}
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.AddDate(0, 1, 0)
+	first := NewSnapshot([]eutils.Article{
+		{PMID: "9"},
+		{PMID: "10", PublicationStatus: "aheadofprint"},
+		{PMID: "11", PublicationStatus: "ppublish"},
+	}, 3, t0)
+	second := NewSnapshot([]eutils.Article{
+		{PMID: "10", PublicationStatus: "ppublish"},
+		{PMID: "11", PublicationStatus: "ppublish", PublicationTypes: []string{"Retracted Publication"}},
+		{PMID: "12", PublicationStatus: "aheadofprint"},
+	}, 4, t1)
+
+	d := Compare(a, first, second)
+	if strings.Join(d.Appeared, ",") != "12" || strings.Join(d.Disappeared, ",") != "9" {
+		t.Errorf("Appeared = %v, Disappeared = %v", d.Appeared, d.Disappeared)
+	}
+	want := []Change{{PMID: "11", From: Published, To: "retracted"}, {PMID: "10", From: AheadOfPrint, To: Published}}
+	if len(d.Changed) != 2 || d.Changed[0] != want[0] || d.Changed[1] != want[1] {
+		t.Errorf("Changed = %+v, want %+v", d.Changed, want)
+	}
+	if !d.Truncated || d.SeenBaseline {
+		t.Errorf("Truncated, SeenBaseline = %v, %v; want true, false", d.Truncated, d.SeenBaseline)
+	}
+
+	d = Compare(a, a.SeenSnapshot(), second)
+	if !d.SeenBaseline || len(d.Changed) != 0 || strings.Join(d.Appeared, ",") != "12" {
+		t.Errorf("seen baseline diff = %+v", d)
+	}
+
+	for i := 0; i < MaxSnapshots+2; i++ {
+		a.AddSnapshot(Snapshot{Time: t0.AddDate(0, 0, i)})
+	}
+	if len(a.Snapshots) != MaxSnapshots || !a.Snapshots[0].Time.Equal(t0.AddDate(0, 0, 2)) {
+		t.Errorf("kept %d snapshots from %v", len(a.Snapshots), a.Snapshots[0].Time)
+	}
+	if s, ok := a.SnapshotAt(t0.AddDate(0, 0, 5)); !ok || !s.Time.Equal(t0.AddDate(0, 0, 5)) {
+		t.Errorf("SnapshotAt = %v, %v", s.Time, ok)
+	}
+	if _, ok := a.SnapshotAt(t0); ok {
+		t.Error("SnapshotAt before the first snapshot should find none")
+	}
+}
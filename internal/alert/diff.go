@@ -0,0 +1,154 @@
+package alert
+
+import (
+	"sort"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/retraction"
+)
+
+// MaxSnapshots caps the snapshots kept per alert; older ones are dropped.
+const MaxSnapshots = 20
+
+// Record statuses a snapshot tracks, besides the retraction statuses.
+const (
+	AheadOfPrint = "ahead of print"
+	Published    = "published"
+)
+
+// Snapshot is an alert's result set at one time, with each record's
+// status.
+type Snapshot struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+	// Status maps each PMID retrieved to its status: retracted, expression
+	// of concern, corrected, ahead of print, or published.
+	Status map[string]string `json:"status"`
+	// seen marks a baseline built from the PMIDs an alert has seen.
+	seen bool
+}
+
+// Status is the status a snapshot records for a: its most serious
+// retraction status, if any, and otherwise whether it is still ahead of
+// print.
+func Status(a eutils.Article) string {
+	if f := retraction.Check(a); f.Flagged() {
+		return f.Statuses[0]
+	}
+	if a.PublicationStatus == "aheadofprint" {
+		return AheadOfPrint
+	}
+	return Published
+}
+
+// NewSnapshot records the status of articles at now. count is how many
+// records matched, which may exceed len(articles).
+func NewSnapshot(articles []eutils.Article, count int, now time.Time) Snapshot {
+	s := Snapshot{Time: now, Count: count, Status: make(map[string]string, len(articles))}
+	for _, a := range articles {
+		s.Status[a.PMID] = Status(a)
+	}
+	return s
+}
+
+// AddSnapshot appends s to a's snapshots, keeping the newest MaxSnapshots.
+func (a *Alert) AddSnapshot(s Snapshot) {
+	a.Snapshots = append(a.Snapshots, s)
+	if n := len(a.Snapshots); n > MaxSnapshots {
+		a.Snapshots = a.Snapshots[n-MaxSnapshots:]
+	}
+}
+
+// SnapshotAt returns the newest snapshot taken at or before t; with a zero
+// t, the newest of all.
+func (a *Alert) SnapshotAt(t time.Time) (Snapshot, bool) {
+	for i := len(a.Snapshots) - 1; i >= 0; i-- {
+		if t.IsZero() || !a.Snapshots[i].Time.After(t) {
+			return a.Snapshots[i], true
+		}
+	}
+	return Snapshot{}, false
+}
+
+// Change is a record whose status differs between two snapshots.
+type Change struct {
+	PMID string `json:"pmid"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Diff compares an alert's result set at two times.
+type Diff struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	// From is when the earlier result set was taken. SeenBaseline is set
+	// when there was no snapshot to compare with, so the earlier set is
+	// the PMIDs the alert had seen by its last run.
+	From         time.Time `json:"from"`
+	SeenBaseline bool      `json:"seen_baseline,omitempty"`
+	To           time.Time `json:"to"`
+	FromCount    int       `json:"from_count"`
+	ToCount      int       `json:"to_count"`
+	// Appeared and Disappeared are the PMIDs in only one result set, and
+	// Changed the records whose status changed.
+	Appeared    []string `json:"appeared"`
+	Disappeared []string `json:"disappeared"`
+	Changed     []Change `json:"changed"`
+	// Truncated is set when either result set was cut off at the limit, so
+	// some PMIDs may have appeared or disappeared only at the cutoff.
+	Truncated bool             `json:"truncated,omitempty"`
+	Articles  []eutils.Article `json:"articles,omitempty"`
+}
+
+// Compare diffs two snapshots of a. A record whose earlier status is
+// unknown, as in a baseline built from the seen PMIDs, is not reported as
+// changed. PMIDs are listed newest first.
+func Compare(a *Alert, from, to Snapshot) Diff {
+	d := Diff{
+		Name: a.Name, Query: a.Query, From: from.Time, To: to.Time, SeenBaseline: from.seen,
+		FromCount: from.Count, ToCount: to.Count,
+		Appeared: []string{}, Disappeared: []string{}, Changed: []Change{},
+		Truncated: from.Count > len(from.Status) || to.Count > len(to.Status),
+	}
+	for id, status := range to.Status {
+		before, ok := from.Status[id]
+		switch {
+		case !ok:
+			d.Appeared = append(d.Appeared, id)
+		case before != "" && before != status:
+			d.Changed = append(d.Changed, Change{PMID: id, From: before, To: status})
+		}
+	}
+	for id := range from.Status {
+		if _, ok := to.Status[id]; !ok {
+			d.Disappeared = append(d.Disappeared, id)
+		}
+	}
+	sortNewest(d.Appeared)
+	sortNewest(d.Disappeared)
+	sort.Slice(d.Changed, func(i, j int) bool { return newer(d.Changed[i].PMID, d.Changed[j].PMID) })
+	return d
+}
+
+// sortNewest sorts PMIDs in descending numeric order, newest first.
+func sortNewest(ids []string) {
+	sort.Slice(ids, func(i, j int) bool { return newer(ids[i], ids[j]) })
+}
+
+func newer(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
+
+// SeenSnapshot is a baseline for an alert without snapshots: the PMIDs it
+// has reported, with unknown statuses, as of its last run.
+func (a *Alert) SeenSnapshot() Snapshot {
+	s := Snapshot{Time: a.LastRun, Count: len(a.Seen), Status: make(map[string]string, len(a.Seen)), seen: true}
+	for _, id := range a.Seen {
+		s.Status[id] = ""
+	}
+	return s
+}
@@ -0,0 +1,57 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// DefaultDays is the window Recent covers when none is given: records added
+// to PubMed since yesterday.
+const DefaultDays = 1
+
+// Digest is what a topic added to PubMed in its recent window.
+type Digest struct {
+	Topic     string           `json:"topic"`
+	Query     string           `json:"query"`
+	Days      int              `json:"days"`
+	Since     string           `json:"since"`
+	Count     int              `json:"count"`
+	PMIDs     []string         `json:"pmids"`
+	Truncated bool             `json:"truncated,omitempty"`
+	Articles  []eutils.Article `json:"articles,omitempty"`
+}
+
+// Recent searches for records matching query that entered PubMed (by Entrez
+// date) in the last days days, up to limit PMIDs. Unlike Check it keeps no
+// state, so the same window can be browsed any number of times.
+func Recent(ctx context.Context, s Searcher, topic, query string, days, limit int, now time.Time) (*Digest, error) {
+	if days <= 0 {
+		days = DefaultDays
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	since := now.AddDate(0, 0, -days).Format("2006/01/02")
+	opts := &eutils.SearchOptions{
+		Limit:    limit,
+		DateType: "edat",
+		MinDate:  since,
+		MaxDate:  "3000",
+	}
+	result, err := s.Search(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", topic, err)
+	}
+	return &Digest{
+		Topic:     topic,
+		Query:     query,
+		Days:      days,
+		Since:     since,
+		Count:     result.Count,
+		PMIDs:     result.IDs,
+		Truncated: result.Count > len(result.IDs),
+	}, nil
+}
@@ -0,0 +1,35 @@
+package alert
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecent_SearchesEntrezDateWindow(t *testing.T) {
+	s := &fakeSearcher{ids: []string{"3", "2", "1"}}
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	d, err := Recent(context.Background(), s, "fxs", "fragile x", 7, 2, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.opts.DateType != "edat" || s.opts.MinDate != "2026/03/03" || s.opts.Limit != 2 {
+		t.Errorf("expected a 7-day Entrez-date window, got %+v", s.opts)
+	}
+	if d.Topic != "fxs" || d.Since != "2026/03/03" || d.Count != 3 {
+		t.Errorf("unexpected digest: %+v", d)
+	}
+	if strings.Join(d.PMIDs, ",") != "3,2,1" {
+		t.Errorf("unexpected PMIDs: %v", d.PMIDs)
+	}
+
+	d, err = Recent(context.Background(), s, "fxs", "fragile x", 0, 0, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Days != DefaultDays || s.opts.MinDate != "2026/03/09" || s.opts.Limit != DefaultLimit {
+		t.Errorf("expected defaults, got days=%d opts=%+v", d.Days, s.opts)
+	}
+}
@@ -0,0 +1,207 @@
+package analyze
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Abbreviation is a short form the articles define, such as HAM-D, with
+// the expansion they give it most often.
+type Abbreviation struct {
+	Short string `json:"short"`
+	Long  string `json:"long"`
+	// Articles is how many articles define Short, and PMIDs lists them.
+	Articles int      `json:"articles"`
+	PMIDs    []string `json:"pmids"`
+	// Variants are the other expansions the articles give, most common
+	// first: "CA" may be cancer antigen in one abstract and carbonic
+	// anhydrase in another.
+	Variants []string `json:"variants,omitempty"`
+}
+
+// AbbreviationReport is a glossary of the abbreviations a set of articles
+// defines.
+type AbbreviationReport struct {
+	Articles      int            `json:"articles"`
+	Abbreviations []Abbreviation `json:"abbreviations"`
+}
+
+// Abbreviations builds a glossary from the "long form (SF)" definitions in
+// the titles and abstracts of articles, most widely defined first. Every
+// expansion is taken from the text itself, so none is guessed. top limits
+// the list; zero keeps everything.
+func Abbreviations(articles []eutils.Article, top int) *AbbreviationReport {
+	type expansion struct {
+		display string
+		pmids   []string
+	}
+	type entry struct {
+		pmids      []string
+		seen       map[string]bool
+		expansions map[string]*expansion
+	}
+	entries := make(map[string]*entry)
+
+	for _, a := range articles {
+		for _, d := range findDefinitions(a.Title + ". " + a.Abstract) {
+			e := entries[d.short]
+			if e == nil {
+				e = &entry{seen: make(map[string]bool), expansions: make(map[string]*expansion)}
+				entries[d.short] = e
+			}
+			key := strings.ToLower(d.long)
+			x := e.expansions[key]
+			if x == nil {
+				x = &expansion{display: d.long}
+				e.expansions[key] = x
+			}
+			if !e.seen[a.PMID] {
+				e.seen[a.PMID] = true
+				e.pmids = append(e.pmids, a.PMID)
+			}
+			if len(x.pmids) == 0 || x.pmids[len(x.pmids)-1] != a.PMID {
+				x.pmids = append(x.pmids, a.PMID)
+			}
+		}
+	}
+
+	r := &AbbreviationReport{Articles: len(articles)}
+	for short, e := range entries {
+		xs := make([]*expansion, 0, len(e.expansions))
+		for _, x := range e.expansions {
+			xs = append(xs, x)
+		}
+		sort.Slice(xs, func(i, j int) bool {
+			if len(xs[i].pmids) != len(xs[j].pmids) {
+				return len(xs[i].pmids) > len(xs[j].pmids)
+			}
+			return xs[i].display < xs[j].display
+		})
+		ab := Abbreviation{Short: short, Long: xs[0].display, Articles: len(e.pmids), PMIDs: e.pmids}
+		for _, x := range xs[1:] {
+			ab.Variants = append(ab.Variants, x.display)
+		}
+		r.Abbreviations = append(r.Abbreviations, ab)
+	}
+	sort.Slice(r.Abbreviations, func(i, j int) bool {
+		a, b := r.Abbreviations[i], r.Abbreviations[j]
+		if a.Articles != b.Articles {
+			return a.Articles > b.Articles
+		}
+		return a.Short < b.Short
+	})
+	if top > 0 && len(r.Abbreviations) > top {
+		r.Abbreviations = r.Abbreviations[:top]
+	}
+	return r
+}
+
+// definition is one "long form (SF)" pair found in a text.
+type definition struct {
+	short, long string
+}
+
+// findDefinitions finds abbreviations defined as "long form (SF)" with the
+// algorithm of Schwartz and Hearst (Pac Symp Biocomput 2003): each letter
+// and digit of the short form must appear, in order, in the words before
+// the parenthesis, and its first character must start a word.
+func findDefinitions(text string) []definition {
+	var defs []definition
+	for {
+		open := strings.IndexByte(text, '(')
+		if open < 0 {
+			return defs
+		}
+		end := strings.IndexByte(text[open:], ')')
+		if end < 0 {
+			return defs
+		}
+		inner := text[open+1 : open+end]
+		before := text[:open]
+		text = text[open+end+1:]
+
+		short := strings.TrimSpace(inner)
+		if i := strings.IndexAny(short, ",;"); i >= 0 {
+			short = strings.TrimSpace(short[:i])
+		}
+		if !isShortForm(short) {
+			continue
+		}
+		// The long form lies in the same sentence or clause, within
+		// min(|SF|+5, 2|SF|) words of the parenthesis.
+		if i := strings.LastIndexAny(before, ".;:()[]"); i >= 0 {
+			before = before[i+1:]
+		}
+		words := strings.Fields(before)
+		n := min(len([]rune(short))+5, 2*len([]rune(short)))
+		if len(words) > n {
+			words = words[len(words)-n:]
+		}
+		if long := bestLongForm(short, strings.Join(words, " ")); long != "" {
+			defs = append(defs, definition{short: short, long: long})
+		}
+	}
+}
+
+// isShortForm reports whether s looks like an abbreviation: 2 to 10
+// characters in at most two words, starting with a letter or digit and
+// including a capital letter.
+func isShortForm(s string) bool {
+	runes := []rune(s)
+	if len(runes) < 2 || len(runes) > 10 || len(strings.Fields(s)) > 2 {
+		return false
+	}
+	if !unicode.IsLetter(runes[0]) && !unicode.IsDigit(runes[0]) {
+		return false
+	}
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// bestLongForm matches short against the end of candidate, right to left,
+// and returns the shortest span of whole words that contains its letters
+// and digits in order, or "" if there is none.
+func bestLongForm(short, candidate string) string {
+	s := []rune(strings.ToLower(short))
+	l := []rune(candidate)
+	lower := []rune(strings.ToLower(candidate))
+	li := len(l) - 1
+	for si := len(s) - 1; si >= 0; si-- {
+		c := s[si]
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			continue
+		}
+		// The short form's first character must begin a word of the
+		// long form.
+		for li >= 0 && (lower[li] != c || (si == 0 && li > 0 && isWordRune(l[li-1]))) {
+			li--
+		}
+		if li < 0 {
+			return ""
+		}
+		li--
+	}
+	start := 0
+	for i := li; i >= 0; i-- {
+		if unicode.IsSpace(l[i]) {
+			start = i + 1
+			break
+		}
+	}
+	long := strings.TrimSpace(string(l[start:]))
+	if len([]rune(long)) <= len(s) || strings.Contains(long, short) {
+		return ""
+	}
+	return long
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
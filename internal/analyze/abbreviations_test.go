@@ -0,0 +1,49 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestFindDefinitions(t *testing.T) {
+	text := "Depression was rated on the Hamilton Depression Rating Scale (HAM-D; 17 items). " +
+		"Patients took sodium-glucose cotransporter-2 (SGLT-2) inhibitors or selective serotonin reuptake inhibitors (SSRIs). " +
+		"Sample size (n = 40) and p values (p < 0.05) are not abbreviations, nor is (Figure 2)."
+	got := findDefinitions(text)
+	want := []definition{
+		{"HAM-D", "Hamilton Depression Rating Scale"},
+		{"SGLT-2", "sodium-glucose cotransporter-2"},
+		{"SSRIs", "selective serotonin reuptake inhibitors"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findDefinitions = %q, want %q", got, want)
+	}
+}
+
+func TestAbbreviations(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", Abstract: "We measured carbonic anhydrase (CA) activity with electroencephalography (EEG)."},
+		{PMID: "2", Abstract: "Resting electroencephalography (EEG) showed gamma excess. Electroencephalography (EEG) was repeated."},
+		{PMID: "3", Title: "Cancer antigen (CA) levels", Abstract: "Cancer Antigen (CA) 125 was measured."},
+		{PMID: "4", Abstract: "Serum cancer antigen (CA) rose."},
+	}
+	r := Abbreviations(articles, 0)
+	if r.Articles != 4 || len(r.Abbreviations) != 2 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	ca := r.Abbreviations[0]
+	if ca.Short != "CA" || ca.Long != "Cancer antigen" || ca.Articles != 3 || !reflect.DeepEqual(ca.PMIDs, []string{"1", "3", "4"}) {
+		t.Errorf("CA = %+v", ca)
+	}
+	if !reflect.DeepEqual(ca.Variants, []string{"carbonic anhydrase"}) {
+		t.Errorf("CA variants = %q", ca.Variants)
+	}
+	if eeg := r.Abbreviations[1]; eeg.Short != "EEG" || eeg.Articles != 2 || len(eeg.Variants) != 0 {
+		t.Errorf("EEG = %+v", eeg)
+	}
+	if r := Abbreviations(articles, 1); len(r.Abbreviations) != 1 {
+		t.Errorf("top 1 kept %d", len(r.Abbreviations))
+	}
+}
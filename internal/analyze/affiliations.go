@@ -0,0 +1,272 @@
+package analyze
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// PlaceCount is how many articles of a set have an author at one country
+// or institution.
+type PlaceCount struct {
+	Name string `json:"name"`
+	// Country is where an institution is, the country its affiliations
+	// name most often.
+	Country string `json:"country,omitempty"`
+	// Articles is how many articles have an author there, and Percent their
+	// share of the articles with affiliations.
+	Articles int      `json:"articles"`
+	Percent  float64  `json:"percent"`
+	PMIDs    []string `json:"pmids"`
+}
+
+// AffiliationReport summarizes where the authors of a set of articles work.
+type AffiliationReport struct {
+	Articles int `json:"articles"`
+	// Affiliated is how many articles give at least one affiliation.
+	Affiliated   int          `json:"affiliated"`
+	Countries    []PlaceCount `json:"countries"`
+	Institutions []PlaceCount `json:"institutions"`
+}
+
+// Affiliation is the institution and country parsed from an author
+// affiliation string; either may be empty.
+type Affiliation struct {
+	Institution string
+	Country     string
+}
+
+// Affiliations counts the articles with an author in each country and at
+// each institution, most first, from the affiliation strings PubMed
+// records. An article counts once per place however many of its authors
+// work there. top limits each list; zero keeps everything.
+func Affiliations(articles []eutils.Article, top int) *AffiliationReport {
+	type entry struct {
+		count     PlaceCount
+		countries map[string]int
+	}
+	r := &AffiliationReport{Articles: len(articles)}
+	countries := make(map[string]*entry)
+	institutions := make(map[string]*entry)
+	add := func(m map[string]*entry, name, country, pmid string, seen map[string]bool) {
+		key := strings.ToLower(name)
+		e := m[key]
+		if e == nil {
+			e = &entry{count: PlaceCount{Name: name}, countries: make(map[string]int)}
+			m[key] = e
+		}
+		if country != "" {
+			e.countries[country]++
+		}
+		if !seen[key] {
+			seen[key] = true
+			e.count.Articles++
+			e.count.PMIDs = append(e.count.PMIDs, pmid)
+		}
+	}
+
+	for _, a := range articles {
+		seenCountry := make(map[string]bool)
+		seenInstitution := make(map[string]bool)
+		affiliated := false
+		for _, au := range a.Authors {
+			for _, aff := range ParseAffiliation(au.Affiliation) {
+				affiliated = true
+				if aff.Country != "" {
+					add(countries, aff.Country, "", a.PMID, seenCountry)
+				}
+				if aff.Institution != "" {
+					add(institutions, aff.Institution, aff.Country, a.PMID, seenInstitution)
+				}
+			}
+		}
+		if affiliated {
+			r.Affiliated++
+		}
+	}
+
+	list := func(m map[string]*entry) []PlaceCount {
+		out := make([]PlaceCount, 0, len(m))
+		for _, e := range m {
+			best := 0
+			for c, n := range e.countries {
+				if n > best || (n == best && c < e.count.Country) {
+					e.count.Country, best = c, n
+				}
+			}
+			if r.Affiliated > 0 {
+				e.count.Percent = 100 * float64(e.count.Articles) / float64(r.Affiliated)
+			}
+			out = append(out, e.count)
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].Articles != out[j].Articles {
+				return out[i].Articles > out[j].Articles
+			}
+			return out[i].Name < out[j].Name
+		})
+		if top > 0 && len(out) > top {
+			out = out[:top]
+		}
+		return out
+	}
+	r.Countries = list(countries)
+	r.Institutions = list(institutions)
+	return r
+}
+
+var (
+	// emailRe matches the contact address many affiliations end with,
+	// with its "Electronic address:" label.
+	emailRe = regexp.MustCompile(`(?i)(electronic address:\s*)?\S+@\S+`)
+	// postcodeRe matches digits, postal codes, and the like that accompany
+	// a country or state, such as "MA 02115" or "SE-171 77".
+	postcodeRe = regexp.MustCompile(`\b[A-Z]{0,2}-?\d[\d\s-]*\b`)
+)
+
+// ParseAffiliation splits an affiliation string, which may list several
+// affiliations separated by semicolons, into institutions and countries.
+// The country is read from the last comma-separated part, with US states
+// standing for the United States; the institution is the part naming a
+// university, hospital, institute, or the like, preferring universities
+// over departments within them.
+func ParseAffiliation(s string) []Affiliation {
+	var out []Affiliation
+	for _, part := range strings.Split(emailRe.ReplaceAllString(s, ""), ";") {
+		var segments []string
+		for _, seg := range strings.Split(part, ",") {
+			seg = strings.TrimFunc(seg, func(r rune) bool { return unicode.IsSpace(r) || r == '.' })
+			if seg = strings.Join(strings.Fields(seg), " "); seg != "" {
+				segments = append(segments, seg)
+			}
+		}
+		if len(segments) == 0 {
+			continue
+		}
+		aff := Affiliation{Country: country(segments)}
+		best := 0
+		for _, seg := range segments {
+			if score := institutionScore(seg); score > best {
+				aff.Institution, best = seg, score
+			}
+		}
+		if len(aff.Institution) > 4 && strings.EqualFold(aff.Institution[:4], "the ") {
+			aff.Institution = aff.Institution[4:]
+		}
+		if aff.Institution != "" || aff.Country != "" {
+			out = append(out, aff)
+		}
+	}
+	return out
+}
+
+// country reads the country from the last segments of an affiliation, or
+// returns "" if they name none.
+func country(segments []string) string {
+	for i := len(segments) - 1; i >= 0 && i >= len(segments)-2; i-- {
+		seg := strings.TrimSpace(postcodeRe.ReplaceAllString(segments[i], " "))
+		if name, ok := countryNames[strings.ToLower(seg)]; ok {
+			return name
+		}
+		if usStates[strings.ToLower(seg)] {
+			return "United States"
+		}
+		for _, f := range strings.Fields(seg) {
+			if len(f) == 2 && usStates[strings.ToLower(f)] && strings.ToUpper(f) == f {
+				return "United States"
+			}
+		}
+	}
+	return ""
+}
+
+// institutionScore ranks how likely an affiliation segment is to name an
+// institution: universities first, then hospitals, institutes, and other
+// organizations, and never departments and other units within them.
+func institutionScore(seg string) int {
+	lower := strings.ToLower(seg)
+	for _, unit := range []string{"department", "dept", "division", "section", "unit ", "laboratory", "lab ", "program", "service", "faculty", "graduate program"} {
+		if strings.HasPrefix(lower, unit) {
+			return 0
+		}
+	}
+	for _, w := range []string{"universi", "universidad", "universität", "université", "università", "college"} {
+		if strings.Contains(lower, w) {
+			return 3
+		}
+	}
+	for _, w := range []string{"hospital", "institut", "clinic", "medical school", "school of medicine", "centre", "center", "foundation", "academy", "council", "ministry", "agency", "national"} {
+		if strings.Contains(lower, w) {
+			return 2
+		}
+	}
+	for _, w := range strings.Fields(lower) {
+		if w == "inc" || w == "ltd" || w == "gmbh" {
+			return 2
+		}
+	}
+	return 0
+}
+
+// countryNames maps the lowercase spellings affiliations use to one name
+// per country.
+var countryNames = map[string]string{}
+
+// countryAliases lists the other spellings of country names.
+var countryAliases = map[string][]string{
+	"United States":  {"usa", "u.s.a", "us", "u.s", "united states of america"},
+	"United Kingdom": {"uk", "u.k", "england", "scotland", "wales", "northern ireland", "great britain"},
+	"China":          {"p.r. china", "pr china", "p. r. china", "people's republic of china", "peoples republic of china", "prc"},
+	"South Korea":    {"korea", "republic of korea", "korea (south)"},
+	"Taiwan":         {"republic of china", "roc"},
+	"Netherlands":    {"the netherlands", "holland"},
+	"Iran":           {"islamic republic of iran"},
+	"Russia":         {"russian federation"},
+	"Czech Republic": {"czechia"},
+	"Türkiye":        {"turkey", "turkiye"},
+	"Viet Nam":       {"vietnam"},
+	"Brazil":         {"brasil"},
+	"Germany":        {"deutschland"},
+	"Spain":          {"españa"},
+	"Italy":          {"italia"},
+	"Mexico":         {"méxico"},
+	"Hong Kong":      {"hong kong sar"},
+}
+
+func init() {
+	for _, name := range []string{
+		"Argentina", "Australia", "Austria", "Bangladesh", "Belgium", "Canada", "Chile",
+		"Colombia", "Croatia", "Denmark", "Egypt", "Ethiopia", "Finland", "France",
+		"Ghana", "Greece", "Hungary", "India", "Indonesia", "Ireland", "Israel", "Japan",
+		"Kenya", "Lebanon", "Malaysia", "New Zealand", "Nigeria", "Norway", "Pakistan",
+		"Peru", "Philippines", "Poland", "Portugal", "Qatar", "Romania", "Saudi Arabia",
+		"Serbia", "Singapore", "Slovenia", "South Africa", "Sweden", "Switzerland",
+		"Thailand", "Tunisia", "Uganda", "Ukraine", "United Arab Emirates", "Uruguay",
+	} {
+		countryNames[strings.ToLower(name)] = name
+	}
+	for name, aliases := range countryAliases {
+		countryNames[strings.ToLower(name)] = name
+		for _, a := range aliases {
+			countryNames[a] = name
+		}
+	}
+	for _, s := range strings.Fields("al alabama ak alaska az arizona ar arkansas ca california co colorado " +
+		"ct connecticut de delaware dc fl florida ga georgia hi hawaii id idaho il illinois in indiana " +
+		"ia iowa ks kansas ky kentucky la louisiana me maine md maryland ma massachusetts mi michigan " +
+		"mn minnesota ms mississippi mo missouri mt montana ne nebraska nv nevada nh nj nm ny nc nd " +
+		"oh ohio ok oklahoma or oregon pa pennsylvania ri sc sd tn tennessee tx texas ut utah vt vermont " +
+		"va virginia wa washington wv wi wisconsin wy wyoming") {
+		usStates[s] = true
+	}
+	for _, s := range []string{"new hampshire", "new jersey", "new mexico", "new york", "north carolina",
+		"north dakota", "rhode island", "south carolina", "south dakota", "west virginia", "district of columbia"} {
+		usStates[s] = true
+	}
+}
+
+// usStates holds the US state names and postal abbreviations, lowercase.
+var usStates = map[string]bool{}
@@ -0,0 +1,59 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestParseAffiliation(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Affiliation
+	}{
+		{"Department of Psychiatry, Massachusetts General Hospital, Boston, MA 02114, USA. jdoe@mgh.harvard.edu.",
+			[]Affiliation{{"Massachusetts General Hospital", "United States"}}},
+		{"Department of Neurology, the First Affiliated Hospital of Anhui Medical University, Hefei, Anhui Province, P.R. China",
+			[]Affiliation{{"First Affiliated Hospital of Anhui Medical University", "China"}}},
+		{"Division of Child Neurology, Stanford University, Palo Alto, CA",
+			[]Affiliation{{"Stanford University", "United States"}}},
+		{"Institut Pasteur, Paris, France; Department of Genetics, University of Oxford, Oxford OX1 3QX, UK. Electronic address: a@b.org.",
+			[]Affiliation{{"Institut Pasteur", "France"}, {"University of Oxford", "United Kingdom"}}},
+		{"Seoul, Republic of Korea", []Affiliation{{"", "South Korea"}}},
+		{"Department of Pediatrics", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseAffiliation(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAffiliation(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAffiliations(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", Authors: []eutils.Author{
+			{Affiliation: "Department of Psychiatry, Stanford University, Stanford, CA, USA"},
+			{Affiliation: "Stanford University School of Medicine, Stanford, California"},
+			{Affiliation: "University of Toronto, Toronto, ON, Canada"},
+		}},
+		{PMID: "2", Authors: []eutils.Author{{Affiliation: "University of Toronto, Toronto, Ontario, Canada"}}},
+		{PMID: "3", Authors: []eutils.Author{{LastName: "Roe"}}},
+	}
+	r := Affiliations(articles, 0)
+	if r.Articles != 3 || r.Affiliated != 2 {
+		t.Fatalf("Articles, Affiliated = %d, %d; want 3, 2", r.Articles, r.Affiliated)
+	}
+	if len(r.Countries) != 2 || r.Countries[0].Name != "Canada" || r.Countries[0].Articles != 2 || r.Countries[0].Percent != 100 {
+		t.Errorf("Countries = %+v", r.Countries)
+	}
+	if r.Countries[1].Name != "United States" || !reflect.DeepEqual(r.Countries[1].PMIDs, []string{"1"}) {
+		t.Errorf("Countries[1] = %+v", r.Countries[1])
+	}
+	if len(r.Institutions) != 3 || r.Institutions[0].Name != "University of Toronto" || r.Institutions[0].Country != "Canada" {
+		t.Errorf("Institutions = %+v", r.Institutions)
+	}
+	if top := Affiliations(articles, 1); len(top.Countries) != 1 || len(top.Institutions) != 1 {
+		t.Errorf("top 1 kept %d countries, %d institutions", len(top.Countries), len(top.Institutions))
+	}
+}
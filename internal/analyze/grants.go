@@ -0,0 +1,89 @@
+package analyze
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// AgencyCount is the funding one agency gave a set of articles.
+type AgencyCount struct {
+	Agency  string `json:"agency"`
+	Country string `json:"country,omitempty"`
+	// Articles is how many articles acknowledge the agency, and Percent
+	// their share of the set.
+	Articles int     `json:"articles"`
+	Percent  float64 `json:"percent"`
+	// GrantIDs are the distinct grant numbers cited, most cited first.
+	GrantIDs []string `json:"grant_ids,omitempty"`
+	PMIDs    []string `json:"pmids"`
+}
+
+// GrantReport summarizes the funding acknowledged across a set of articles.
+type GrantReport struct {
+	Articles int `json:"articles"`
+	// Funded is how many articles list at least one grant.
+	Funded   int           `json:"funded"`
+	Agencies []AgencyCount `json:"agencies"`
+}
+
+// Grants counts the articles each funding agency appears on, most first.
+// Agencies are grouped by name, ignoring case, as NLM records it ("NIMH NIH
+// HHS"). top limits the list; zero keeps every agency.
+func Grants(articles []eutils.Article, top int) *GrantReport {
+	type entry struct {
+		count   AgencyCount
+		grants  map[string]int
+		ordered []string
+	}
+	r := &GrantReport{Articles: len(articles)}
+	entries := make(map[string]*entry)
+	for _, a := range articles {
+		if len(a.Grants) > 0 {
+			r.Funded++
+		}
+		seen := make(map[string]bool)
+		for _, g := range a.Grants {
+			key := strings.ToLower(g.Agency)
+			e := entries[key]
+			if e == nil {
+				e = &entry{count: AgencyCount{Agency: g.Agency, Country: g.Country}, grants: make(map[string]int)}
+				entries[key] = e
+			}
+			if !seen[key] {
+				seen[key] = true
+				e.count.Articles++
+				e.count.PMIDs = append(e.count.PMIDs, a.PMID)
+			}
+			if g.ID != "" {
+				if e.grants[g.ID] == 0 {
+					e.ordered = append(e.ordered, g.ID)
+				}
+				e.grants[g.ID]++
+			}
+		}
+	}
+
+	r.Agencies = make([]AgencyCount, 0, len(entries))
+	for _, e := range entries {
+		ids := e.ordered
+		sort.SliceStable(ids, func(i, j int) bool { return e.grants[ids[i]] > e.grants[ids[j]] })
+		e.count.GrantIDs = ids
+		if r.Articles > 0 {
+			e.count.Percent = 100 * float64(e.count.Articles) / float64(r.Articles)
+		}
+		r.Agencies = append(r.Agencies, e.count)
+	}
+	sort.Slice(r.Agencies, func(i, j int) bool {
+		a, b := r.Agencies[i], r.Agencies[j]
+		if a.Articles != b.Articles {
+			return a.Articles > b.Articles
+		}
+		return a.Agency < b.Agency
+	})
+	if top > 0 && len(r.Agencies) > top {
+		r.Agencies = r.Agencies[:top]
+	}
+	return r
+}
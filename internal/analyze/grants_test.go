@@ -0,0 +1,33 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestGrants(t *testing.T) {
+	nimh := func(id string) eutils.Grant {
+		return eutils.Grant{ID: id, Agency: "NIMH NIH HHS", Country: "United States"}
+	}
+	articles := []eutils.Article{
+		{PMID: "1", Grants: []eutils.Grant{nimh("R01 MH2"), nimh("R01 MH1"), {Agency: "Wellcome Trust"}}},
+		{PMID: "2", Grants: []eutils.Grant{nimh("R01 MH1"), {Agency: "nimh nih hhs", ID: "R01 MH1"}}},
+		{PMID: "3"},
+	}
+	r := Grants(articles, 0)
+	if r.Articles != 3 || r.Funded != 2 {
+		t.Errorf("articles, funded = %d, %d; want 3, 2", r.Articles, r.Funded)
+	}
+	want := []AgencyCount{
+		{Agency: "NIMH NIH HHS", Country: "United States", Articles: 2, Percent: 200.0 / 3, GrantIDs: []string{"R01 MH1", "R01 MH2"}, PMIDs: []string{"1", "2"}},
+		{Agency: "Wellcome Trust", Articles: 1, Percent: 100.0 / 3, PMIDs: []string{"1"}},
+	}
+	if !reflect.DeepEqual(r.Agencies, want) {
+		t.Errorf("agencies = %+v\nwant %+v", r.Agencies, want)
+	}
+	if got := Grants(articles, 1).Agencies; len(got) != 1 {
+		t.Errorf("top 1 kept %d agencies", len(got))
+	}
+}
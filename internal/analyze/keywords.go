@@ -0,0 +1,277 @@
+package analyze
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Keyword is a term ranked by TF-IDF across a set of articles.
+type Keyword struct {
+	Term  string  `json:"term"`
+	Score float64 `json:"score"`
+	// Articles is how many titles or abstracts use the term.
+	Articles int `json:"articles"`
+}
+
+// KeywordReport is the distinctive vocabulary of a set of articles.
+type KeywordReport struct {
+	Articles int       `json:"articles"`
+	Keywords []Keyword `json:"keywords"`
+	// Bigrams are adjacent word pairs used in at least two articles, most
+	// widespread first; their Score is the number of uses.
+	Bigrams []Keyword `json:"bigrams"`
+	// AuthorKeywords counts the articles listing each author keyword.
+	AuthorKeywords []TermCount `json:"author_keywords"`
+	// Suggestions are frequent terms the query does not already contain.
+	Suggestions []string `json:"suggestions"`
+}
+
+// maxSuggestions bounds KeywordReport.Suggestions.
+const maxSuggestions = 10
+
+// Keywords extracts TF-IDF keywords and frequent bigrams from the titles and
+// abstracts of articles, counts their author keywords, and suggests terms
+// to add to query. top limits each list; zero keeps everything.
+//
+// A term's score sums its length-normalized frequency in each article,
+// weighted by smoothed inverse document frequency, so terms that are
+// frequent in some articles but not boilerplate in all of them rank first.
+func Keywords(articles []eutils.Article, query string, top int) *KeywordReport {
+	r := &KeywordReport{Articles: len(articles)}
+	type stat struct {
+		tf      float64
+		df      int
+		uses    int
+		display string
+	}
+	terms := make(map[string]*stat)
+	bigrams := make(map[string]*stat)
+	authorKW := make(map[string]*TermCount)
+
+	for _, a := range articles {
+		clauses := tokenize(a.Title + ". " + a.Abstract)
+		total := 0
+		for _, c := range clauses {
+			total += len(c)
+		}
+		seenTerm := make(map[string]bool)
+		seenBigram := make(map[string]bool)
+		for _, c := range clauses {
+			for i, w := range c {
+				s, ok := terms[w]
+				if !ok {
+					s = &stat{display: w}
+					terms[w] = s
+				}
+				s.tf += 1 / float64(total)
+				if !seenTerm[w] {
+					seenTerm[w] = true
+					s.df++
+				}
+				if i == 0 {
+					continue
+				}
+				pair := c[i-1] + " " + w
+				b, ok := bigrams[pair]
+				if !ok {
+					b = &stat{display: pair}
+					bigrams[pair] = b
+				}
+				b.uses++
+				if !seenBigram[pair] {
+					seenBigram[pair] = true
+					b.df++
+				}
+			}
+		}
+
+		seenKW := make(map[string]bool)
+		for _, kw := range a.Keywords {
+			key := strings.ToLower(strings.TrimSpace(kw))
+			if key == "" || seenKW[key] {
+				continue
+			}
+			seenKW[key] = true
+			tc, ok := authorKW[key]
+			if !ok {
+				tc = &TermCount{Term: strings.TrimSpace(kw)}
+				authorKW[key] = tc
+			}
+			tc.Count++
+		}
+	}
+
+	n := float64(len(articles))
+	r.Keywords = make([]Keyword, 0, len(terms))
+	for _, s := range terms {
+		idf := math.Log((1+n)/(1+float64(s.df))) + 1
+		r.Keywords = append(r.Keywords, Keyword{Term: s.display, Score: s.tf * idf, Articles: s.df})
+	}
+	sort.Slice(r.Keywords, func(i, j int) bool {
+		a, b := r.Keywords[i], r.Keywords[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Term < b.Term
+	})
+
+	r.Bigrams = make([]Keyword, 0)
+	for _, s := range bigrams {
+		if s.df < 2 {
+			continue
+		}
+		r.Bigrams = append(r.Bigrams, Keyword{Term: s.display, Score: float64(s.uses), Articles: s.df})
+	}
+	sort.Slice(r.Bigrams, func(i, j int) bool {
+		a, b := r.Bigrams[i], r.Bigrams[j]
+		if a.Articles != b.Articles {
+			return a.Articles > b.Articles
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Term < b.Term
+	})
+
+	r.AuthorKeywords = make([]TermCount, 0, len(authorKW))
+	for _, tc := range authorKW {
+		if n > 0 {
+			tc.Percent = 100 * float64(tc.Count) / n
+		}
+		r.AuthorKeywords = append(r.AuthorKeywords, *tc)
+	}
+	sort.Slice(r.AuthorKeywords, func(i, j int) bool {
+		a, b := r.AuthorKeywords[i], r.AuthorKeywords[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Term < b.Term
+	})
+
+	if top > 0 {
+		r.Keywords = r.Keywords[:min(top, len(r.Keywords))]
+		r.Bigrams = r.Bigrams[:min(top, len(r.Bigrams))]
+		r.AuthorKeywords = r.AuthorKeywords[:min(top, len(r.AuthorKeywords))]
+	}
+	r.Suggestions = suggest(r, query)
+	return r
+}
+
+// suggest picks terms not already in query or an earlier suggestion,
+// alternating bigrams, author keywords, and single keywords so no one list
+// crowds out the others. Phrases are quoted so they can be pasted into a
+// query.
+func suggest(r *KeywordReport, query string) []string {
+	covered := strings.ToLower(query)
+	out := []string{}
+	add := func(term string) {
+		lower := strings.ToLower(term)
+		if len(out) >= maxSuggestions || strings.Contains(covered, lower) {
+			return
+		}
+		covered += " " + lower
+		if strings.Contains(term, " ") {
+			term = `"` + term + `"`
+		}
+		out = append(out, term)
+	}
+	for i := 0; len(out) < maxSuggestions; i++ {
+		if i >= len(r.Bigrams) && i >= len(r.AuthorKeywords) && i >= len(r.Keywords) {
+			break
+		}
+		if i < len(r.Bigrams) {
+			add(r.Bigrams[i].Term)
+		}
+		if i < len(r.AuthorKeywords) && r.AuthorKeywords[i].Count > 1 {
+			add(r.AuthorKeywords[i].Term)
+		}
+		if i < len(r.Keywords) {
+			add(r.Keywords[i].Term)
+		}
+	}
+	return out
+}
+
+// tokenize lowercases text and splits it into clauses of content words:
+// punctuation, stopwords, and dropped words end a clause, so bigrams never
+// span them. Words shorter than three characters and bare numbers are
+// dropped.
+func tokenize(text string) [][]string {
+	var (
+		clauses [][]string
+		clause  []string
+		word    strings.Builder
+	)
+	endClause := func() {
+		if len(clause) > 0 {
+			clauses = append(clauses, clause)
+			clause = nil
+		}
+	}
+	endWord := func() {
+		w := strings.Trim(word.String(), "-")
+		word.Reset()
+		switch {
+		case w == "":
+		case len([]rune(w)) < 3 || stopwords[w] || isNumber(w):
+			endClause()
+		default:
+			clause = append(clause, w)
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-':
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			endWord()
+			endClause()
+		}
+	}
+	endWord()
+	endClause()
+	return clauses
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) && r != '.' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// stopwords are common English function words plus the boilerplate of
+// abstracts, which say nothing about a topic.
+var stopwords = func() map[string]bool {
+	words := strings.Fields(`
+		about above after again against all also although among and any are
+		because been before being below between both but can could did does
+		doing done down during each either few for from further had has have
+		having her here hers him his how however into its itself just less
+		many may might more most much must near neither nor not now off once
+		only other our ours out over own per same she should since some such
+		than that the their theirs them then there these they this those
+		through thus too under until upon very via was were what when where
+		whether which while who whom whose why will with within without
+		would yet you your
+		aim aimed aims analysis analyses analyzed assessed associated
+		background compared conclusion conclusions data design evaluated
+		findings found group groups included including increased investigated
+		measured method methods objective objectives observed outcome
+		outcomes participants performed present purpose reported result
+		results revealed showed shown significant significantly studies study
+		suggest suggests total use used using well`)
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}()
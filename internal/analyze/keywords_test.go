@@ -0,0 +1,48 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("Metformin improved EEG power in 12 adolescents; the gamma-band effect (n = 40) persisted.")
+	want := [][]string{{"metformin", "improved", "eeg", "power"}, {"adolescents"}, {"gamma-band", "effect"}, {"persisted"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", Title: "Metformin in fragile X syndrome", Abstract: "Metformin reduced irritability. Gamma power fell.",
+			Keywords: []string{"Metformin", "EEG"}},
+		{PMID: "2", Title: "Gamma power in fragile X syndrome", Abstract: "Resting gamma power was elevated.",
+			Keywords: []string{"EEG", "eeg"}},
+		{PMID: "3", Title: "Lovastatin trial", Abstract: "Lovastatin was well tolerated."},
+	}
+	r := Keywords(articles, "fragile x syndrome", 5)
+	if r.Articles != 3 {
+		t.Errorf("Articles = %d", r.Articles)
+	}
+	if len(r.Keywords) != 5 {
+		t.Fatalf("expected 5 keywords, got %+v", r.Keywords)
+	}
+	// Concentrated in one short article, lovastatin outranks the shared terms.
+	if r.Keywords[0].Term != "lovastatin" || r.Keywords[1].Articles != 2 {
+		t.Errorf("unexpected keywords: %+v", r.Keywords)
+	}
+	if len(r.Bigrams) == 0 || r.Bigrams[0].Term != "gamma power" || r.Bigrams[0].Articles != 2 || r.Bigrams[0].Score != 3 {
+		t.Errorf("bigrams = %+v", r.Bigrams)
+	}
+	if len(r.AuthorKeywords) != 2 || r.AuthorKeywords[0].Term != "EEG" || r.AuthorKeywords[0].Count != 2 {
+		t.Errorf("author keywords = %+v", r.AuthorKeywords)
+	}
+	// Terms in the query or an earlier suggestion are not suggested again.
+	want := []string{`"gamma power"`, "EEG", "lovastatin", "tolerated", "trial"}
+	if !reflect.DeepEqual(r.Suggestions, want) {
+		t.Errorf("suggestions = %q, want %q", r.Suggestions, want)
+	}
+}
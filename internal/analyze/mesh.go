@@ -0,0 +1,74 @@
+// Package analyze computes aggregate statistics over sets of PubMed articles.
+package analyze
+
+import (
+	"sort"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// TermCount is how many articles in a set carry a descriptor.
+type TermCount struct {
+	Term    string  `json:"term"`
+	UI      string  `json:"ui,omitempty"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+// MeSHReport summarizes descriptor frequency across a set of articles.
+type MeSHReport struct {
+	Articles  int         `json:"articles"`
+	Indexed   int         `json:"indexed"`
+	MajorOnly bool        `json:"major_only"`
+	Terms     []TermCount `json:"terms"`
+}
+
+// MeSHFrequency counts the articles carrying each descriptor, most frequent
+// first. With majorOnly, only major-topic headings count, including those
+// major through a starred qualifier, as in PubMed's [majr]. Percentages are of
+// all articles, so unindexed (in-process) records pull them down honestly.
+// top limits the result; zero keeps every term.
+func MeSHFrequency(articles []eutils.Article, majorOnly bool, top int) *MeSHReport {
+	report := &MeSHReport{Articles: len(articles), MajorOnly: majorOnly}
+	counts := make(map[string]*TermCount)
+
+	for _, a := range articles {
+		if len(a.MeSHTerms) > 0 {
+			report.Indexed++
+		}
+		seen := make(map[string]bool)
+		for _, m := range a.MeSHTerms {
+			if majorOnly && !m.Major() {
+				continue
+			}
+			if seen[m.Descriptor] {
+				continue
+			}
+			seen[m.Descriptor] = true
+			tc, ok := counts[m.Descriptor]
+			if !ok {
+				tc = &TermCount{Term: m.Descriptor, UI: m.DescriptorUI}
+				counts[m.Descriptor] = tc
+			}
+			tc.Count++
+		}
+	}
+
+	report.Terms = make([]TermCount, 0, len(counts))
+	for _, tc := range counts {
+		if report.Articles > 0 {
+			tc.Percent = 100 * float64(tc.Count) / float64(report.Articles)
+		}
+		report.Terms = append(report.Terms, *tc)
+	}
+	sort.Slice(report.Terms, func(i, j int) bool {
+		if report.Terms[i].Count != report.Terms[j].Count {
+			return report.Terms[i].Count > report.Terms[j].Count
+		}
+		return report.Terms[i].Term < report.Terms[j].Term
+	})
+	if top > 0 && len(report.Terms) > top {
+		report.Terms = report.Terms[:top]
+	}
+	return report
+}
@@ -0,0 +1,39 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestMeSHFrequency(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", MeSHTerms: []eutils.MeSHTerm{
+			{Descriptor: "Fragile X Syndrome", DescriptorUI: "D005600", MajorTopic: true},
+			{Descriptor: "Humans"},
+		}},
+		{PMID: "2", MeSHTerms: []eutils.MeSHTerm{
+			{Descriptor: "Fragile X Syndrome", DescriptorUI: "D005600", MajorTopic: true},
+			{Descriptor: "Electroencephalography", MajorQualifier: true},
+			{Descriptor: "Humans"},
+		}},
+		{PMID: "3"}, // not yet indexed
+		{PMID: "4", MeSHTerms: []eutils.MeSHTerm{{Descriptor: "Humans"}}},
+	}
+
+	r := MeSHFrequency(articles, true, 0)
+	if r.Articles != 4 || r.Indexed != 3 {
+		t.Errorf("expected 4 articles, 3 indexed; got %d, %d", r.Articles, r.Indexed)
+	}
+	if len(r.Terms) != 2 {
+		t.Fatalf("expected 2 major terms, got %+v", r.Terms)
+	}
+	if r.Terms[0].Term != "Fragile X Syndrome" || r.Terms[0].Count != 2 || r.Terms[0].Percent != 50 {
+		t.Errorf("unexpected top term: %+v", r.Terms[0])
+	}
+
+	all := MeSHFrequency(articles, false, 1)
+	if len(all.Terms) != 1 || all.Terms[0].Term != "Humans" || all.Terms[0].Count != 3 {
+		t.Errorf("expected Humans first when counting all headings, got %+v", all.Terms)
+	}
+}
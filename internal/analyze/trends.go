@@ -0,0 +1,52 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Counter reports how many records match a query. *eutils.Client
+// satisfies it.
+type Counter interface {
+	Count(ctx context.Context, query string, opts *eutils.SearchOptions) (int, error)
+}
+
+// YearCount is the number of records published in one year.
+type YearCount struct {
+	Year  int `json:"year"`
+	Count int `json:"count"`
+}
+
+// Trend is a query's publication count per year.
+type Trend struct {
+	Query  string      `json:"query"`
+	Total  int         `json:"total"`
+	Counts []YearCount `json:"counts"`
+}
+
+// Trends counts the records matching each query in every year from from to
+// to inclusive, using one count-only search per query and year.
+func Trends(ctx context.Context, c Counter, queries []string, from, to int) ([]Trend, error) {
+	if from > to {
+		return nil, fmt.Errorf("year range must be ascending")
+	}
+
+	trends := make([]Trend, 0, len(queries))
+	for _, q := range queries {
+		t := Trend{Query: q, Counts: make([]YearCount, 0, to-from+1)}
+		for year := from; year <= to; year++ {
+			y := strconv.Itoa(year)
+			n, err := c.Count(ctx, q, &eutils.SearchOptions{MinDate: y, MaxDate: y})
+			if err != nil {
+				return nil, fmt.Errorf("counting %q in %d: %w", q, year, err)
+			}
+			t.Counts = append(t.Counts, YearCount{Year: year, Count: n})
+			t.Total += n
+		}
+		trends = append(trends, t)
+	}
+	return trends, nil
+}
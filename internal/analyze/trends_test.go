@@ -0,0 +1,49 @@
+package analyze
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+type fakeCounter map[string]int
+
+func (f fakeCounter) Count(ctx context.Context, query string, opts *eutils.SearchOptions) (int, error) {
+	if opts.MinDate != opts.MaxDate {
+		return 0, errors.New("expected a single-year range")
+	}
+	n, ok := f[query+" "+opts.MinDate]
+	if !ok {
+		return 0, errors.New("unexpected query " + query)
+	}
+	return n, nil
+}
+
+func TestTrends(t *testing.T) {
+	counter := fakeCounter{
+		"autism 2020": 10, "autism 2021": 15, "autism 2022": 30,
+		"fxs 2020": 1, "fxs 2021": 0, "fxs 2022": 2,
+	}
+	trends, err := Trends(context.Background(), counter, []string{"autism", "fxs"}, 2020, 2022)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trends) != 2 {
+		t.Fatalf("expected 2 trends, got %d", len(trends))
+	}
+	if trends[0].Total != 55 || trends[1].Total != 3 {
+		t.Errorf("unexpected totals: %d, %d", trends[0].Total, trends[1].Total)
+	}
+	if got := trends[0].Counts[2]; got.Year != 2022 || got.Count != 30 {
+		t.Errorf("unexpected 2022 count: %+v", got)
+	}
+
+	if _, err := Trends(context.Background(), counter, []string{"missing"}, 2020, 2020); err == nil {
+		t.Error("expected count errors to propagate")
+	}
+	if _, err := Trends(context.Background(), counter, []string{"autism"}, 2022, 2020); err == nil {
+		t.Error("expected descending range to be rejected")
+	}
+}
@@ -0,0 +1,155 @@
+// Package backup bundles pubmed-cli's user data (the library, saved
+// alerts, and search history) into one file and restores it.
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+	"github.com/henrybloomingdale/pubmed-cli/internal/store"
+)
+
+// Version is the archive format version Export writes and Import accepts.
+const Version = 1
+
+// Archive is the content of a backup file.
+type Archive struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	// Library and Alerts hold the stores as JSON documents.
+	Library json.RawMessage `json:"library,omitempty"`
+	Alerts  json.RawMessage `json:"alerts,omitempty"`
+	History []history.Entry `json:"history,omitempty"`
+}
+
+// Counts summarizes what an archive holds.
+type Counts struct {
+	Collections int
+	Articles    int
+	Alerts      int
+	Searches    int
+}
+
+// Export reads the user database into an archive; empty stores are left
+// out.
+func Export(db *store.DB, now time.Time) (*Archive, error) {
+	a := &Archive{Version: Version, Created: now.UTC()}
+	l, err := db.Library()
+	if err != nil {
+		return nil, err
+	}
+	if len(l.Collections) > 0 || len(l.Annotations) > 0 {
+		if a.Library, err = json.Marshal(l); err != nil {
+			return nil, err
+		}
+	}
+	b, err := db.Alerts()
+	if err != nil {
+		return nil, err
+	}
+	if len(b.Alerts) > 0 {
+		if a.Alerts, err = json.Marshal(b); err != nil {
+			return nil, err
+		}
+	}
+	if a.History, err = db.History(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// stores parses the archive's library and alerts; either is nil when the
+// archive does not hold it.
+func (a *Archive) stores() (*library.Library, *alert.Book, error) {
+	var l *library.Library
+	if len(a.Library) > 0 {
+		l = library.New()
+		if err := json.Unmarshal(a.Library, l); err != nil {
+			return nil, nil, fmt.Errorf("parsing library: %w", err)
+		}
+	}
+	var b *alert.Book
+	if len(a.Alerts) > 0 {
+		b = &alert.Book{}
+		if err := json.Unmarshal(a.Alerts, b); err != nil {
+			return nil, nil, fmt.Errorf("parsing alerts: %w", err)
+		}
+	}
+	return l, b, nil
+}
+
+// Counts checks that the archive's stores parse and counts their contents.
+func (a *Archive) Counts() (Counts, error) {
+	var c Counts
+	l, b, err := a.stores()
+	if err != nil {
+		return c, err
+	}
+	if l != nil {
+		c.Collections, c.Articles = len(l.Collections), len(l.Articles)
+	}
+	if b != nil {
+		c.Alerts = len(b.Alerts)
+	}
+	c.Searches = len(a.History)
+	return c, nil
+}
+
+// Read parses a backup file.
+func Read(path string) (*Archive, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading backup: %w", err)
+	}
+	var a Archive
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if a.Version < 1 || a.Version > Version {
+		return nil, fmt.Errorf("%s: unsupported backup version %d", path, a.Version)
+	}
+	if _, err := a.Counts(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &a, nil
+}
+
+// Write saves the archive to path.
+func (a *Archive) Write(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+// Import replaces the database's stores with the archive's contents in one
+// transaction. Stores the archive does not hold are left alone.
+func Import(db *store.DB, a *Archive) error {
+	l, b, err := a.stores()
+	if err != nil {
+		return err
+	}
+	return db.Replace(l, b, a.History)
+}
+
+// writeFile replaces path atomically.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+	"github.com/henrybloomingdale/pubmed-cli/internal/store"
+)
+
+func openDB(t *testing.T) *store.DB {
+	t.Helper()
+	db, err := store.Open(filepath.Join(t.TempDir(), store.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestExportImport(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	src := openDB(t)
+
+	l := library.New()
+	l.Add("fxs", []eutils.Article{{PMID: "1", Title: "One"}, {PMID: "2", Title: "Two"}}, now)
+	l.AddNote("1", "key paper", now)
+	if err := src.SaveLibrary(l); err != nil {
+		t.Fatal(err)
+	}
+	b := &alert.Book{}
+	b.Add("fxs-trials", "fragile x", now)
+	if err := src.SaveAlerts(b); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{"fragile x", "autism"} {
+		if _, err := src.AppendHistory(history.Entry{Query: q, Time: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := Export(src, now)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "backup.json")
+	if err := a.Write(file); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	read, err := Read(file)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	c, err := read.Counts()
+	if err != nil || c != (Counts{Collections: 1, Articles: 2, Alerts: 1, Searches: 2}) {
+		t.Errorf("Counts = %+v, %v", c, err)
+	}
+
+	dst := openDB(t)
+	if held, err := dst.Holding(); err != nil || len(held) != 0 {
+		t.Errorf("Holding on a new database = %v, %v", held, err)
+	}
+	if err := Import(dst, read); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if held, err := dst.Holding(); err != nil || len(held) != 3 {
+		t.Errorf("Holding after import = %v, %v", held, err)
+	}
+	rl, err := dst.Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if articles, err := rl.CollectionArticles("fxs"); err != nil || len(articles) != 2 {
+		t.Errorf("restored collection = %+v, %v", articles, err)
+	}
+	if ann := rl.Annotations["1"]; ann == nil || len(ann.Notes) != 1 {
+		t.Errorf("restored annotations = %+v", rl.Annotations)
+	}
+	rb, err := dst.Alerts()
+	if err != nil || rb.Get("fxs-trials") == nil {
+		t.Errorf("restored alerts = %+v, %v", rb, err)
+	}
+	rh, err := dst.History()
+	if err != nil || len(rh) != 2 || rh[1].ID != 2 || rh[1].Query != "autism" {
+		t.Errorf("restored history = %+v, %v", rh, err)
+	}
+}
+
+func TestRead_RejectsUnknownVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "backup.json")
+	if err := (&Archive{Version: Version + 1}).Write(file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(file); err == nil {
+		t.Error("expected an unsupported version to be rejected")
+	}
+}
@@ -0,0 +1,156 @@
+// Package batch reads YAML job specs that describe many related searches
+// with shared settings, and summarizes what a run of them produced.
+package batch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Settings are the search options a job can set or inherit from the
+// spec's defaults.
+type Settings struct {
+	Limit int    `yaml:"limit,omitempty" json:"limit,omitempty"`
+	Year  string `yaml:"year,omitempty" json:"year,omitempty"`
+	Type  string `yaml:"type,omitempty" json:"type,omitempty"`
+	Sort  string `yaml:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// Merge returns s with its unset fields taken from defaults.
+func (s Settings) Merge(defaults Settings) Settings {
+	if s.Limit == 0 {
+		s.Limit = defaults.Limit
+	}
+	if s.Year == "" {
+		s.Year = defaults.Year
+	}
+	if s.Type == "" {
+		s.Type = defaults.Type
+	}
+	if s.Sort == "" {
+		s.Sort = defaults.Sort
+	}
+	return s
+}
+
+// Job is one search in a spec.
+type Job struct {
+	Name  string `yaml:"name"`
+	Query string `yaml:"query"`
+	// Out is an optional export of the job's articles; its format comes
+	// from the extension, as with --out.
+	Out      string `yaml:"out,omitempty"`
+	Settings `yaml:",inline"`
+}
+
+// Spec is a batch file: shared defaults and the jobs to run, in order.
+type Spec struct {
+	path     string
+	Defaults Settings `yaml:"defaults"`
+	Jobs     []Job    `yaml:"jobs"`
+}
+
+// Load reads and validates the spec at path. Each job's settings are merged
+// with the defaults, and relative out paths are resolved against the
+// spec's directory.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading batch spec: %w", err)
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.path = path
+	dir := filepath.Dir(path)
+	for i := range s.Jobs {
+		if out := s.Jobs[i].Out; out != "" && !filepath.IsAbs(out) {
+			s.Jobs[i].Out = filepath.Join(dir, out)
+		}
+	}
+	return s, nil
+}
+
+// Parse decodes a spec, rejecting unknown keys so typos are not silently
+// ignored.
+func Parse(data []byte) (*Spec, error) {
+	var s Spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no jobs defined")
+		}
+		return nil, err
+	}
+	if len(s.Jobs) == 0 {
+		return nil, fmt.Errorf("no jobs defined")
+	}
+	seen := make(map[string]bool, len(s.Jobs))
+	for i := range s.Jobs {
+		j := &s.Jobs[i]
+		if j.Name == "" {
+			j.Name = fmt.Sprintf("job-%d", i+1)
+		}
+		if seen[j.Name] {
+			return nil, fmt.Errorf("duplicate job name %q", j.Name)
+		}
+		seen[j.Name] = true
+		if j.Query == "" {
+			return nil, fmt.Errorf("job %q has no query", j.Name)
+		}
+		if j.Limit < 0 {
+			return nil, fmt.Errorf("job %q: limit must be positive", j.Name)
+		}
+		j.Settings = j.Settings.Merge(s.Defaults)
+	}
+	return &s, nil
+}
+
+// Path returns the file the spec was loaded from.
+func (s *Spec) Path() string {
+	return s.path
+}
+
+// Result is what one job produced.
+type Result struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	Count int    `json:"count"`
+	// PMIDs are the IDs the job retrieved (up to its limit).
+	PMIDs    []string `json:"pmids"`
+	Out      string   `json:"out,omitempty"`
+	Exported int      `json:"exported,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Seconds  float64  `json:"seconds"`
+}
+
+// OK reports whether the job succeeded.
+func (r Result) OK() bool {
+	return r.Error == ""
+}
+
+// Summary is the outcome of running a spec.
+type Summary struct {
+	Spec     string    `json:"spec"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Jobs     []Result  `json:"jobs"`
+	Failed   int       `json:"failed"`
+}
+
+// Add records a job's result.
+func (s *Summary) Add(r Result) {
+	s.Jobs = append(s.Jobs, r)
+	if !r.OK() {
+		s.Failed++
+	}
+}
@@ -0,0 +1,87 @@
+package batch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(`
+defaults:
+  limit: 50
+  year: 2015-2025
+jobs:
+  - name: trials
+    query: fragile x syndrome
+    type: trial
+    limit: 10
+  - query: autism AND metformin
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(s.Jobs))
+	}
+	first := s.Jobs[0]
+	if first.Limit != 10 || first.Year != "2015-2025" || first.Type != "trial" {
+		t.Errorf("first job settings not merged: %+v", first.Settings)
+	}
+	second := s.Jobs[1]
+	if second.Name != "job-2" || second.Limit != 50 {
+		t.Errorf("second job = %+v", second)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := map[string]string{
+		"empty":         ``,
+		"no jobs":       "defaults:\n  limit: 5\n",
+		"no query":      "jobs:\n  - name: a\n",
+		"duplicate":     "jobs:\n  - name: a\n    query: x\n  - name: a\n    query: y\n",
+		"unknown field": "jobs:\n  - name: a\n    query: x\n    limt: 5\n",
+		"negative":      "jobs:\n  - query: x\n    limit: -1\n",
+	}
+	for name, spec := range cases {
+		if _, err := Parse([]byte(spec)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoad_ResolvesOutPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "jobs.yaml")
+	spec := "jobs:\n  - query: x\n    out: x.ris\n  - query: y\n    out: /tmp/y.csv\n"
+	if err := os.WriteFile(path, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Jobs[0].Out != filepath.Join(dir, "x.ris") {
+		t.Errorf("relative out = %q", s.Jobs[0].Out)
+	}
+	if s.Jobs[1].Out != "/tmp/y.csv" {
+		t.Errorf("absolute out = %q", s.Jobs[1].Out)
+	}
+	if s.Path() != path {
+		t.Errorf("Path() = %q", s.Path())
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil || !strings.Contains(err.Error(), "reading batch spec") {
+		t.Errorf("expected read error, got %v", err)
+	}
+}
+
+func TestSummary_Add(t *testing.T) {
+	var s Summary
+	s.Add(Result{Name: "a"})
+	s.Add(Result{Name: "b", Error: "search failed"})
+	if len(s.Jobs) != 2 || s.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+}
@@ -0,0 +1,170 @@
+// Package bulk keeps the checkpoint of a resumable bulk download: the date
+// ranges a query was split into and how much of each has been written, so
+// an interrupted download continues where it stopped without fetching
+// completed pages again.
+package bulk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// CheckpointFile is the checkpoint's name in the download directory.
+const CheckpointFile = "checkpoint.json"
+
+// ErrNoCheckpoint is returned by Load for a directory without a checkpoint.
+var ErrNoCheckpoint = errors.New("no checkpoint")
+
+// Part is one Entrez-date range of the download and the file its records
+// go to.
+type Part struct {
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Count int       `json:"count"`
+	File  string    `json:"file"`
+	// Fetched is how many of the range's records are in File, and Bytes the
+	// file's size after the last completed page; anything past it was cut
+	// off by an interruption and is discarded on resume.
+	Fetched int   `json:"fetched"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// Done reports whether every record of the range has been written.
+func (p Part) Done() bool {
+	return p.Fetched >= p.Count
+}
+
+// Range returns the part's date range for a search.
+func (p Part) Range() eutils.DateRange {
+	return eutils.DateRange{From: p.From, To: p.To, Count: p.Count}
+}
+
+// Checkpoint is the state of a bulk download, saved in its directory after
+// every page.
+type Checkpoint struct {
+	dir string
+	// Query is the query as given, and Search the query as searched, with
+	// any publication-date limits added.
+	Query   string    `json:"query"`
+	Search  string    `json:"search"`
+	Lean    bool      `json:"lean,omitempty"`
+	Started time.Time `json:"started"`
+	Updated time.Time `json:"updated"`
+	// Total is how many records the download takes, which --limit may set
+	// below the sum of the parts.
+	Total int    `json:"total"`
+	Parts []Part `json:"parts"`
+}
+
+// New starts a checkpoint in dir for the records of query, searched as
+// search, in ranges, one file per range.
+func New(dir, query, search string, lean bool, ranges []eutils.DateRange, total int, now time.Time) *Checkpoint {
+	c := &Checkpoint{dir: dir, Query: query, Search: search, Lean: lean, Started: now, Updated: now, Total: total}
+	for i, r := range ranges {
+		c.Parts = append(c.Parts, Part{
+			From: r.From, To: r.To, Count: r.Count,
+			File: fmt.Sprintf("records-%04d.ndjson", i+1),
+		})
+	}
+	return c
+}
+
+// Load reads the checkpoint in dir, or returns ErrNoCheckpoint.
+func Load(dir string) (*Checkpoint, error) {
+	path := filepath.Join(dir, CheckpointFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCheckpoint
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	c := &Checkpoint{dir: dir}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the checkpoint, replacing the old one atomically.
+func (c *Checkpoint) Save() error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", c.dir, err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(c.dir, CheckpointFile)
+	if err := os.WriteFile(path+".tmp", append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Fetched is how many records have been written across the parts.
+func (c *Checkpoint) Fetched() int {
+	n := 0
+	for _, p := range c.Parts {
+		n += p.Fetched
+	}
+	return n
+}
+
+// Done reports whether the download is complete.
+func (c *Checkpoint) Done() bool {
+	if c.Fetched() >= c.Total {
+		return true
+	}
+	for _, p := range c.Parts {
+		if !p.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Open opens part i's file for appending, cut back to the size recorded
+// after its last completed page.
+func (c *Checkpoint) Open(i int) (*os.File, error) {
+	p := c.Parts[i]
+	f, err := os.OpenFile(filepath.Join(c.dir, p.File), os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(p.Bytes); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(p.Bytes, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Advance records that n more records of part i have been written and
+// synced, leaving its file size bytes, and saves the checkpoint.
+func (c *Checkpoint) Advance(i, n int, size int64, now time.Time) error {
+	c.Parts[i].Fetched += n
+	c.Parts[i].Bytes = size
+	c.Updated = now
+	return c.Save()
+}
+
+// Restart discards what part i has written, for a range whose records
+// changed since the download began, and records its new count.
+func (c *Checkpoint) Restart(i, count int) {
+	c.Parts[i].Count = count
+	c.Parts[i].Fetched = 0
+	c.Parts[i].Bytes = 0
+}
@@ -0,0 +1,68 @@
+package bulk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "corpus")
+	if _, err := Load(dir); !errors.Is(err, ErrNoCheckpoint) {
+		t.Fatalf("Load of an empty directory = %v, want ErrNoCheckpoint", err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ranges := []eutils.DateRange{
+		{From: now.AddDate(-2, 0, 0), To: now.AddDate(-1, 0, 0), Count: 3},
+		{From: now.AddDate(-1, 0, 1), To: now, Count: 2},
+	}
+	c := New(dir, "autism", "autism", false, ranges, 5, now)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// One page of the first part completes; a second is cut off midway.
+	f, err := c.Open(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"pmid\":\"1\"}\n{\"pmid\":\"2\"}\n")
+	if err := c.Advance(0, 2, 26, now); err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"pmid\":\"3\"")
+	f.Close()
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Query != "autism" || loaded.Fetched() != 2 || loaded.Done() || loaded.Parts[0].Done() {
+		t.Fatalf("loaded checkpoint = %+v", loaded)
+	}
+	f, err = loaded.Open(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"pmid\":\"3\"}\n")
+	f.Close()
+	data, err := os.ReadFile(filepath.Join(dir, loaded.Parts[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"pmid\":\"1\"}\n{\"pmid\":\"2\"}\n{\"pmid\":\"3\"}\n"; string(data) != want {
+		t.Errorf("resumed file = %q, want %q", data, want)
+	}
+
+	loaded.Advance(0, 1, int64(len(data)), now)
+	loaded.Restart(1, 4)
+	loaded.Parts[1].Fetched = 4
+	if !loaded.Done() || loaded.Fetched() != 7 {
+		t.Errorf("Done, Fetched = %v, %d; want true, 7", loaded.Done(), loaded.Fetched())
+	}
+}
@@ -0,0 +1,50 @@
+// Package cache locates and manages pubmed-cli's on-disk cache.
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EnvDir overrides the cache location when set.
+const EnvDir = "PUBMED_CLI_CACHE_DIR"
+
+// Dir returns the root cache directory, honoring PUBMED_CLI_CACHE_DIR and
+// otherwise using the platform cache directory (e.g. ~/.cache/pubmed-cli).
+func Dir() (string, error) {
+	if d := os.Getenv(EnvDir); d != "" {
+		return d, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user cache directory: %w", err)
+	}
+	return filepath.Join(base, "pubmed-cli"), nil
+}
+
+// Path joins elem onto the cache root and ensures the parent directory exists.
+func Path(elem ...string) (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	p := filepath.Join(append([]string{root}, elem...)...)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
+	}
+	return p, nil
+}
+
+// Subdir returns a directory under the cache root, creating it if needed.
+func Subdir(elem ...string) (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	d := filepath.Join(append([]string{root}, elem...)...)
+	if err := os.MkdirAll(d, 0o755); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
+	}
+	return d, nil
+}
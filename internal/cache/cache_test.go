@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir_EnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvDir, dir)
+
+	got, err := Dir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != dir {
+		t.Errorf("expected %q, got %q", dir, got)
+	}
+}
+
+func TestPath_CreatesParent(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvDir, dir)
+
+	p, err := Path("mesh", "d2025.bin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p != filepath.Join(dir, "mesh", "d2025.bin") {
+		t.Errorf("unexpected path %q", p)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "mesh")); err != nil || !info.IsDir() {
+		t.Errorf("expected mesh directory to be created, err=%v", err)
+	}
+}
+
+func TestSubdir_Creates(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvDir, dir)
+
+	d, err := Subdir("mesh")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info, err := os.Stat(d); err != nil || !info.IsDir() {
+		t.Errorf("expected %s to be a directory, err=%v", d, err)
+	}
+}
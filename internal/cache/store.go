@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store is a file-backed key/value cache. Entries older than the TTL are
+// treated as missing; a zero TTL never expires.
+//
+// Keys of the form "<endpoint>?<params>" are filed per endpoint so Stats
+// can break usage down by endpoint. Lookups are counted in memory until
+// Flush persists them.
+type Store struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+
+	mu     sync.Mutex
+	counts map[string]counts
+}
+
+// NewStore returns a Store rooted at dir.
+func NewStore(dir string, ttl time.Duration) *Store {
+	return &Store{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// Get returns the cached value for key if present and fresh.
+func (s *Store) Get(key string) ([]byte, bool) {
+	data, ok := s.lookup(key)
+	s.count(key, ok)
+	return data, ok
+}
+
+func (s *Store) lookup(key string) ([]byte, bool) {
+	p := s.path(key)
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, false
+	}
+	if s.ttl > 0 && s.now().Sub(info.ModTime()) > s.ttl {
+		return nil, false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (s *Store) count(key string, hit bool) {
+	ep := endpoint(key)
+	if ep == "" {
+		ep = "other"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.counts = make(map[string]counts)
+	}
+	c := s.counts[ep]
+	if hit {
+		c.Hits++
+	} else {
+		c.Misses++
+	}
+	s.counts[ep] = c
+}
+
+// Put stores data under key, replacing any existing entry atomically.
+func (s *Store) Put(key string, data []byte) error {
+	return s.writeFile(s.path(key), data)
+}
+
+func (s *Store) writeFile(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// path files entries under their endpoint and shards them by the first
+// byte of the key's hash.
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(s.dir, filepath.FromSlash(endpoint(key)), name[:2], name)
+}
+
+// endpoint returns the part of key before "?" when it is a safe relative
+// path such as "mesh/esearch.fcgi", and "" otherwise.
+func endpoint(key string) string {
+	ep, _, ok := strings.Cut(key, "?")
+	if !ok || ep == "" {
+		return ""
+	}
+	for _, part := range strings.Split(ep, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ""
+		}
+		for _, r := range part {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+				return ""
+			}
+		}
+	}
+	return ep
+}
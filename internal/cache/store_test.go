@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_PutGet(t *testing.T) {
+	s := NewStore(t.TempDir(), time.Hour)
+
+	if _, ok := s.Get("missing"); ok {
+		t.Fatal("expected miss for unknown key")
+	}
+	if err := s.Put("k", []byte("v1")); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if err := s.Put("k", []byte("v2")); err != nil {
+		t.Fatalf("overwrite failed: %v", err)
+	}
+	got, ok := s.Get("k")
+	if !ok || string(got) != "v2" {
+		t.Errorf("Get = %q, %v; want v2", got, ok)
+	}
+}
+
+func TestStore_Expiry(t *testing.T) {
+	s := NewStore(t.TempDir(), time.Hour)
+	if err := s.Put("k", []byte("v")); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, ok := s.Get("k"); ok {
+		t.Error("expected stale entry to miss")
+	}
+
+	s.ttl = 0
+	if _, ok := s.Get("k"); !ok {
+		t.Error("expected zero TTL to never expire")
+	}
+}
@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statsFile holds a Store's persisted hit and miss counts.
+const statsFile = "stats.json"
+
+// Usage summarizes the entries in one part of the cache.
+type Usage struct {
+	Name    string    `json:"name"`
+	Entries int       `json:"entries"`
+	Bytes   int64     `json:"bytes"`
+	Oldest  time.Time `json:"oldest,omitzero"`
+	Newest  time.Time `json:"newest,omitzero"`
+	// Hits and Misses are lookups recorded since the counts were last cleared.
+	Hits   int64 `json:"hits,omitempty"`
+	Misses int64 `json:"misses,omitempty"`
+}
+
+// HitRate is the fraction of lookups served from the cache, or 0 before
+// any lookup.
+func (u Usage) HitRate() float64 {
+	if u.Hits+u.Misses == 0 {
+		return 0
+	}
+	return float64(u.Hits) / float64(u.Hits+u.Misses)
+}
+
+func (u *Usage) add(info fs.FileInfo) {
+	u.Entries++
+	u.Bytes += info.Size()
+	if t := info.ModTime(); u.Oldest.IsZero() || t.Before(u.Oldest) {
+		u.Oldest = t
+	}
+	if t := info.ModTime(); t.After(u.Newest) {
+		u.Newest = t
+	}
+}
+
+// Report describes the whole cache for "pubmed cache stats".
+type Report struct {
+	Dir      string  `json:"dir"`
+	Sections []Usage `json:"sections"`
+	// Endpoints breaks the response cache down by NCBI endpoint.
+	Endpoints []Usage `json:"endpoints,omitempty"`
+}
+
+// Sections reports the size of each top-level directory under root
+// (e.g. "responses" and "mesh"), sorted by name. A missing root has none.
+func Sections(root string) ([]Usage, error) {
+	out := []Usage{}
+	dirs, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		u := Usage{Name: d.Name()}
+		err := walkFiles(filepath.Join(root, d.Name()), func(_ string, info fs.FileInfo) error {
+			u.add(info)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+// Clear removes the named sections under root, or everything when none
+// are given, and returns what was removed.
+func Clear(root string, sections ...string) (Usage, error) {
+	var removed Usage
+	targets := []string{root}
+	if len(sections) > 0 {
+		targets = targets[:0]
+		for _, s := range sections {
+			if s == "" || s != filepath.Base(s) || s == "." || s == ".." {
+				return removed, fmt.Errorf("invalid cache section %q", s)
+			}
+			targets = append(targets, filepath.Join(root, s))
+		}
+	}
+	for _, t := range targets {
+		err := walkFiles(t, func(_ string, info fs.FileInfo) error {
+			removed.add(info)
+			return nil
+		})
+		if err != nil {
+			return removed, err
+		}
+		if err := os.RemoveAll(t); err != nil {
+			return removed, fmt.Errorf("clearing cache: %w", err)
+		}
+	}
+	return removed, nil
+}
+
+// Stats reports the store's entries and recorded lookups per endpoint,
+// sorted by endpoint. Entries stored without an endpoint are reported
+// under "other".
+func (s *Store) Stats() ([]Usage, error) {
+	byName := make(map[string]*Usage)
+	get := func(name string) *Usage {
+		u, ok := byName[name]
+		if !ok {
+			u = &Usage{Name: name}
+			byName[name] = u
+		}
+		return u
+	}
+	err := walkFiles(s.dir, func(p string, info fs.FileInfo) error {
+		if ep, ok := s.entryEndpoint(p); ok {
+			get(ep).add(info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.loadCounts()
+	if err != nil {
+		return nil, err
+	}
+	for ep, c := range counts {
+		u := get(ep)
+		u.Hits, u.Misses = c.Hits, c.Misses
+	}
+
+	out := make([]Usage, 0, len(byName))
+	for _, u := range byName {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Prune removes entries last written more than age ago and returns what
+// was removed.
+func (s *Store) Prune(age time.Duration) (Usage, error) {
+	removed := Usage{Name: "pruned"}
+	cutoff := s.now().Add(-age)
+	err := walkFiles(s.dir, func(p string, info fs.FileInfo) error {
+		if _, ok := s.entryEndpoint(p); !ok || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(p); err != nil {
+			return fmt.Errorf("pruning cache: %w", err)
+		}
+		removed.add(info)
+		return nil
+	})
+	return removed, err
+}
+
+// entryEndpoint reports the endpoint of the entry at p, and false for
+// files that are not entries (stats and partial writes).
+func (s *Store) entryEndpoint(p string) (string, bool) {
+	rel, err := filepath.Rel(s.dir, p)
+	if err != nil || rel == statsFile || strings.HasPrefix(filepath.Base(rel), ".tmp-") {
+		return "", false
+	}
+	// Entries are <endpoint>/<shard>/<hash>; older ones have no endpoint.
+	ep := filepath.Dir(filepath.Dir(rel))
+	if ep == "." {
+		return "other", true
+	}
+	return filepath.ToSlash(ep), true
+}
+
+// counts are the hits and misses recorded for one endpoint.
+type counts struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// Flush adds the lookups counted since the last Flush to the store's
+// persisted statistics.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	pending := s.counts
+	s.counts = nil
+	s.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	saved, err := s.loadCounts()
+	if err != nil {
+		return err
+	}
+	for ep, c := range pending {
+		c.Hits += saved[ep].Hits
+		c.Misses += saved[ep].Misses
+		saved[ep] = c
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	return s.writeFile(filepath.Join(s.dir, statsFile), data)
+}
+
+func (s *Store) loadCounts() (map[string]counts, error) {
+	out := make(map[string]counts)
+	data, err := os.ReadFile(filepath.Join(s.dir, statsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache statistics: %w", err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing cache statistics: %w", err)
+	}
+	return out, nil
+}
+
+// walkFiles calls fn for each regular file under dir; a missing dir has none.
+func walkFiles(dir string, fn func(string, fs.FileInfo) error) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		return fn(p, info)
+	})
+	if err != nil {
+		return fmt.Errorf("reading cache: %w", err)
+	}
+	return nil
+}
+
+// ParseAge parses a prune age such as "30d", "2w", or any time.Duration
+// string ("36h").
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w, or 12h)", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w, or 12h)", s)
+	}
+	return d, nil
+}
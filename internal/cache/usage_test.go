@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_StatsPerEndpoint(t *testing.T) {
+	s := NewStore(t.TempDir(), 0)
+	for _, k := range []string{"mesh/esearch.fcgi?term=a", "mesh/esearch.fcgi?term=b", "mesh/efetch.fcgi?id=1", "plain"} {
+		if err := s.Put(k, []byte("data")); err != nil {
+			t.Fatalf("put %s: %v", k, err)
+		}
+	}
+	s.Get("mesh/esearch.fcgi?term=a")
+	s.Get("mesh/esearch.fcgi?term=zzz")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	s.Get("mesh/esearch.fcgi?term=b")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	want := []Usage{
+		{Name: "mesh/efetch.fcgi", Entries: 1, Bytes: 4},
+		{Name: "mesh/esearch.fcgi", Entries: 2, Bytes: 8, Hits: 2, Misses: 1},
+		{Name: "other", Entries: 1, Bytes: 4},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got %d endpoints: %+v", len(stats), stats)
+	}
+	for i, w := range want {
+		g := stats[i]
+		if g.Name != w.Name || g.Entries != w.Entries || g.Bytes != w.Bytes || g.Hits != w.Hits || g.Misses != w.Misses {
+			t.Errorf("endpoint %d = %+v, want %+v", i, g, w)
+		}
+	}
+	if r := stats[1].HitRate(); r < 0.66 || r > 0.67 {
+		t.Errorf("hit rate = %v", r)
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	s := NewStore(t.TempDir(), 0)
+	s.Put("mesh/esearch.fcgi?term=old", []byte("old"))
+	s.Put("mesh/esearch.fcgi?term=new", []byte("new"))
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(s.path("mesh/esearch.fcgi?term=old"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := s.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if removed.Entries != 1 {
+		t.Errorf("removed %d entries, want 1", removed.Entries)
+	}
+	if _, ok := s.Get("mesh/esearch.fcgi?term=old"); ok {
+		t.Error("old entry survived prune")
+	}
+	if _, ok := s.Get("mesh/esearch.fcgi?term=new"); !ok {
+		t.Error("fresh entry was pruned")
+	}
+}
+
+func TestSectionsAndClear(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "mesh"), 0o755)
+	os.WriteFile(filepath.Join(root, "mesh", "desc.bin"), []byte("12345"), 0o644)
+	NewStore(filepath.Join(root, "responses"), 0).Put("k", []byte("12"))
+
+	sections, err := Sections(root)
+	if err != nil {
+		t.Fatalf("sections: %v", err)
+	}
+	if len(sections) != 2 || sections[0].Name != "mesh" || sections[0].Bytes != 5 || sections[1].Entries != 1 {
+		t.Fatalf("sections = %+v", sections)
+	}
+
+	if _, err := Clear(root, "../x"); err == nil {
+		t.Error("expected error for path outside the cache")
+	}
+	removed, err := Clear(root, "responses")
+	if err != nil || removed.Entries != 1 {
+		t.Fatalf("clear = %+v, %v", removed, err)
+	}
+	if sections, _ = Sections(root); len(sections) != 1 {
+		t.Errorf("after clear sections = %+v", sections)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "12h": 12 * time.Hour} {
+		got, err := ParseAge(in)
+		if err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "-3d", "soon"} {
+		if _, err := ParseAge(in); err == nil {
+			t.Errorf("ParseAge(%q): expected error", in)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package clipboard copies text to the system clipboard using the platform's
+// command-line tools, so no cgo or display-server bindings are needed.
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// candidates lists clipboard commands to try, in order, for an OS. On Linux
+// and the BSDs wl-copy comes first under Wayland, otherwise the X11 tools.
+func candidates(goos string, wayland bool) [][]string {
+	switch goos {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	x11 := [][]string{
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+	wl := []string{"wl-copy"}
+	if wayland {
+		return append([][]string{wl}, x11...)
+	}
+	return append(x11, wl)
+}
+
+// findCommand returns the first available clipboard command for goos.
+func findCommand(goos string, wayland bool, lookPath func(string) (string, error)) ([]string, error) {
+	var names []string
+	for _, c := range candidates(goos, wayland) {
+		path, err := lookPath(c[0])
+		if err == nil {
+			return append([]string{path}, c[1:]...), nil
+		}
+		names = append(names, c[0])
+	}
+	return nil, fmt.Errorf("no clipboard tool found (install one of: %s)", strings.Join(names, ", "))
+}
+
+// Copy places text on the system clipboard.
+func Copy(ctx context.Context, text string) error {
+	argv, err := findCommand(runtime.GOOS, os.Getenv("WAYLAND_DISPLAY") != "", exec.LookPath)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s failed: %w: %s", argv[0], err, msg)
+		}
+		return fmt.Errorf("%s failed: %w", argv[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package clipboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func lookPathOf(available ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, a := range available {
+			if a == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		goos      string
+		wayland   bool
+		available []string
+		want      string
+	}{
+		{"darwin", false, []string{"pbcopy"}, "/usr/bin/pbcopy"},
+		{"windows", false, []string{"clip.exe"}, "/usr/bin/clip.exe"},
+		{"linux", false, []string{"xsel", "wl-copy"}, "/usr/bin/xsel --clipboard --input"},
+		{"linux", true, []string{"xclip", "wl-copy"}, "/usr/bin/wl-copy"},
+		{"linux", true, []string{"xclip"}, "/usr/bin/xclip -selection clipboard"},
+		{"freebsd", false, []string{"xclip"}, "/usr/bin/xclip -selection clipboard"},
+	}
+	for _, tt := range tests {
+		argv, err := findCommand(tt.goos, tt.wayland, lookPathOf(tt.available...))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.goos, err)
+			continue
+		}
+		if got := strings.Join(argv, " "); got != tt.want {
+			t.Errorf("%s (wayland=%v): got %q, want %q", tt.goos, tt.wayland, got, tt.want)
+		}
+	}
+
+	_, err := findCommand("linux", false, lookPathOf())
+	if err == nil || !strings.Contains(err.Error(), "xclip") {
+		t.Errorf("expected error naming the tools to install, got %v", err)
+	}
+}
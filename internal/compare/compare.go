@@ -0,0 +1,306 @@
+// Package compare profiles articles side by side: study design,
+// population, sample size, outcomes, and main finding. Profiles are
+// extracted heuristically from PubMed indexing (publication types, MeSH
+// check tags) and the abstract, so they should be checked against the
+// full text.
+package compare
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Profile is the comparable summary of one article. Empty fields were
+// not found.
+type Profile struct {
+	PMID       string `json:"pmid"`
+	Title      string `json:"title"`
+	Journal    string `json:"journal,omitempty"`
+	Year       string `json:"year,omitempty"`
+	Design     string `json:"design,omitempty"`
+	Population string `json:"population,omitempty"`
+	// SampleSize is the largest participant count the abstract reports, or 0.
+	SampleSize int    `json:"sample_size,omitempty"`
+	Outcomes   string `json:"outcomes,omitempty"`
+	Findings   string `json:"findings,omitempty"`
+}
+
+// Field is one comparable row of a Profile.
+type Field struct {
+	Name  string
+	Value func(p Profile) string
+}
+
+// Fields lists the compared rows in display order.
+var Fields = []Field{
+	{"Design", func(p Profile) string { return p.Design }},
+	{"Population", func(p Profile) string { return p.Population }},
+	{"N", func(p Profile) string {
+		if p.SampleSize == 0 {
+			return ""
+		}
+		return strconv.Itoa(p.SampleSize)
+	}},
+	{"Outcomes", func(p Profile) string { return p.Outcomes }},
+	{"Findings", func(p Profile) string { return p.Findings }},
+}
+
+// Profiles extracts a profile for each article, in order.
+func Profiles(articles []eutils.Article) []Profile {
+	out := make([]Profile, len(articles))
+	for i, a := range articles {
+		out[i] = Extract(a)
+	}
+	return out
+}
+
+// Extract builds the profile of one article.
+func Extract(a eutils.Article) Profile {
+	return Profile{
+		PMID:       a.PMID,
+		Title:      a.Title,
+		Journal:    a.JournalAbbrev,
+		Year:       a.Year,
+		Design:     design(a),
+		Population: population(a),
+		SampleSize: sampleSize(a.Abstract),
+		Outcomes:   outcomes(a),
+		Findings:   findings(a),
+	}
+}
+
+// designTypes maps publication types and design MeSH headings to a design,
+// most specific first.
+var designTypes = []struct{ term, design string }{
+	{"Meta-Analysis", "Meta-analysis"},
+	{"Systematic Review", "Systematic review"},
+	{"Randomized Controlled Trial", "Randomized controlled trial"},
+	{"Controlled Clinical Trial", "Controlled clinical trial"},
+	{"Clinical Trial, Phase I", "Phase I trial"},
+	{"Clinical Trial, Phase II", "Phase II trial"},
+	{"Clinical Trial, Phase III", "Phase III trial"},
+	{"Clinical Trial, Phase IV", "Phase IV trial"},
+	{"Clinical Trial", "Clinical trial"},
+	{"Cohort Studies", "Cohort study"},
+	{"Case-Control Studies", "Case-control study"},
+	{"Cross-Sectional Studies", "Cross-sectional study"},
+	{"Observational Study", "Observational study"},
+	{"Case Reports", "Case report"},
+	{"Review", "Review"},
+}
+
+// designCues are abstract phrases that refine or stand in for indexing.
+var designCues = []struct{ phrase, label string }{
+	{"double-blind", "double-blind"},
+	{"single-blind", "single-blind"},
+	{"open-label", "open-label"},
+	{"placebo-controlled", "placebo-controlled"},
+	{"crossover", "crossover"},
+	{"multicenter", "multicenter"},
+	{"multicentre", "multicenter"},
+	{"prospective", "prospective"},
+	{"retrospective", "retrospective"},
+}
+
+var abstractDesigns = []struct{ phrase, design string }{
+	{"meta-analysis", "Meta-analysis"},
+	{"systematic review", "Systematic review"},
+	{"randomized controlled trial", "Randomized controlled trial"},
+	{"randomised controlled trial", "Randomized controlled trial"},
+	{"randomized", "Randomized trial"},
+	{"randomised", "Randomized trial"},
+	{"cohort", "Cohort study"},
+	{"case-control", "Case-control study"},
+	{"cross-sectional", "Cross-sectional study"},
+	{"case report", "Case report"},
+}
+
+func design(a eutils.Article) string {
+	indexed := make(map[string]bool)
+	for _, t := range a.PublicationTypes {
+		indexed[t] = true
+	}
+	for _, m := range a.MeSHTerms {
+		indexed[m.Descriptor] = true
+	}
+	text := strings.ToLower(a.Title + " " + a.Abstract)
+
+	base := ""
+	for _, d := range designTypes {
+		if indexed[d.term] {
+			base = d.design
+			break
+		}
+	}
+	if base == "" {
+		for _, d := range abstractDesigns {
+			if strings.Contains(text, d.phrase) {
+				base = d.design
+				break
+			}
+		}
+	}
+	if base == "" {
+		return ""
+	}
+	var cues []string
+	for _, c := range designCues {
+		if strings.Contains(text, c.phrase) && !slices.Contains(cues, c.label) {
+			cues = append(cues, c.label)
+		}
+	}
+	if len(cues) == 0 {
+		return base
+	}
+	return base + " (" + strings.Join(cues, ", ") + ")"
+}
+
+// ageGroups are MEDLINE age check tags in age order.
+var ageGroups = []struct{ tag, label string }{
+	{"Infant, Newborn", "newborns"},
+	{"Infant", "infants"},
+	{"Child, Preschool", "preschool children"},
+	{"Child", "children"},
+	{"Adolescent", "adolescents"},
+	{"Young Adult", "young adults"},
+	{"Adult", "adults"},
+	{"Middle Aged", "middle-aged adults"},
+	{"Aged", "older adults"},
+	{"Aged, 80 and over", "adults 80+"},
+}
+
+func population(a eutils.Article) string {
+	if s := section(a, "PARTICIPANTS", "PATIENTS", "POPULATION", "SUBJECTS", "SETTING AND PARTICIPANTS", "SETTING"); s != "" {
+		return firstSentence(s)
+	}
+	tags := make(map[string]bool)
+	for _, m := range a.MeSHTerms {
+		tags[m.Descriptor] = true
+	}
+	var parts []string
+	switch {
+	case tags["Humans"]:
+		parts = append(parts, "Humans")
+	case tags["Animals"]:
+		parts = append(parts, "Animals")
+	}
+	var ages []string
+	for _, g := range ageGroups {
+		if tags[g.tag] {
+			ages = append(ages, g.label)
+		}
+	}
+	if len(ages) > 0 {
+		parts = append(parts, strings.Join(ages, ", "))
+	}
+	switch {
+	case tags["Male"] && tags["Female"]:
+		parts = append(parts, "male and female")
+	case tags["Male"]:
+		parts = append(parts, "male")
+	case tags["Female"]:
+		parts = append(parts, "female")
+	}
+	return strings.Join(parts, "; ")
+}
+
+var (
+	nEquals   = regexp.MustCompile(`\b[nN]\s*=\s*(\d{1,3}(?:,\d{3})+|\d+)`)
+	nSubjects = regexp.MustCompile(`(?i)\b(\d{1,3}(?:,\d{3})+|\d+)\s+(?:[a-z-]+\s+){0,3}?(?:participants|patients|subjects|individuals|children|adults|adolescents|infants|women|men|people|persons|volunteers|cases|veterans|pregnancies|residents)\b`)
+)
+
+// sampleSize returns the largest participant count in the abstract,
+// which is usually the total enrolled.
+func sampleSize(abstract string) int {
+	best := 0
+	for _, re := range []*regexp.Regexp{nEquals, nSubjects} {
+		for _, m := range re.FindAllStringSubmatch(abstract, -1) {
+			n, err := strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+			if err == nil && n > best && n < 100_000_000 {
+				best = n
+			}
+		}
+	}
+	return best
+}
+
+var outcomeCues = []string{"primary outcome", "primary end point", "primary endpoint", "main outcome", "outcome measure", "outcomes were", "outcome was"}
+
+func outcomes(a eutils.Article) string {
+	if s := section(a, "MAIN OUTCOMES AND MEASURES", "MAIN OUTCOME MEASURES", "OUTCOME MEASURES", "OUTCOMES", "MEASURES", "MEASUREMENTS"); s != "" {
+		return firstSentence(s)
+	}
+	for _, s := range sentences(a.Abstract) {
+		lower := strings.ToLower(s)
+		for _, cue := range outcomeCues {
+			if strings.Contains(lower, cue) {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func findings(a eutils.Article) string {
+	if s := section(a, "CONCLUSIONS", "CONCLUSION", "CONCLUSIONS AND RELEVANCE", "INTERPRETATION", "DISCUSSION"); s != "" {
+		return firstSentence(s)
+	}
+	if s := section(a, "RESULTS", "FINDINGS"); s != "" {
+		return firstSentence(s)
+	}
+	all := sentences(a.Abstract)
+	for _, s := range all {
+		lower := strings.ToLower(s)
+		if strings.HasPrefix(lower, "in conclusion") || strings.HasPrefix(lower, "these findings") ||
+			strings.HasPrefix(lower, "our findings") || strings.HasPrefix(lower, "our results") {
+			return s
+		}
+	}
+	if len(all) > 0 {
+		return all[len(all)-1]
+	}
+	return ""
+}
+
+// section returns the text of the first structured-abstract section whose
+// label matches one of labels, ignoring case.
+func section(a eutils.Article, labels ...string) string {
+	for _, want := range labels {
+		for _, s := range a.AbstractSections {
+			if strings.EqualFold(strings.TrimSpace(s.Label), want) {
+				return strings.TrimSpace(s.Text)
+			}
+		}
+	}
+	return ""
+}
+
+// sentenceEnd matches the end of a sentence: a period, question mark, or
+// exclamation point followed by space and a capital letter or digit.
+var sentenceEnd = regexp.MustCompile(`[.?!]\s+[A-Z0-9]`)
+
+func sentences(text string) []string {
+	text = strings.TrimSpace(text)
+	var out []string
+	for text != "" {
+		loc := sentenceEnd.FindStringIndex(text)
+		if loc == nil {
+			out = append(out, text)
+			break
+		}
+		out = append(out, strings.TrimSpace(text[:loc[0]+1]))
+		text = strings.TrimSpace(text[loc[1]-1:])
+	}
+	return out
+}
+
+func firstSentence(text string) string {
+	if s := sentences(text); len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
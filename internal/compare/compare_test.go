@@ -0,0 +1,68 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestExtract_StructuredTrial(t *testing.T) {
+	a := eutils.Article{
+		PMID:             "1",
+		Title:            "Metformin for fragile X syndrome: a randomized trial",
+		PublicationTypes: []string{"Journal Article", "Randomized Controlled Trial"},
+		Abstract:         "This double-blind, placebo-controlled study randomized 1,024 participants (n = 512 per arm).",
+		AbstractSections: []eutils.AbstractSection{
+			{Label: "PARTICIPANTS", Text: "Children aged 6 to 12 years with FXS. Recruited at three sites."},
+			{Label: "MAIN OUTCOMES AND MEASURES", Text: "Change in ABC-C irritability score at week 16."},
+			{Label: "RESULTS", Text: "Scores fell in both arms."},
+			{Label: "CONCLUSIONS", Text: "Metformin did not improve irritability. Larger trials are needed."},
+		},
+	}
+	p := Extract(a)
+	want := Profile{
+		PMID:       "1",
+		Title:      a.Title,
+		Design:     "Randomized controlled trial (double-blind, placebo-controlled)",
+		Population: "Children aged 6 to 12 years with FXS.",
+		SampleSize: 1024,
+		Outcomes:   "Change in ABC-C irritability score at week 16.",
+		Findings:   "Metformin did not improve irritability.",
+	}
+	if p != want {
+		t.Errorf("got  %+v\nwant %+v", p, want)
+	}
+}
+
+func TestExtract_UnstructuredFallbacks(t *testing.T) {
+	a := eutils.Article{
+		PMID: "2",
+		Abstract: "We conducted a retrospective cohort study of 350 adult patients. " +
+			"The primary outcome was 30-day mortality. Mortality was 12%. " +
+			"In conclusion, early treatment was associated with lower mortality.",
+		MeSHTerms: []eutils.MeSHTerm{{Descriptor: "Humans"}, {Descriptor: "Adult"}, {Descriptor: "Aged"}, {Descriptor: "Female"}},
+	}
+	p := Extract(a)
+	if p.Design != "Cohort study (retrospective)" {
+		t.Errorf("design = %q", p.Design)
+	}
+	if p.Population != "Humans; adults, older adults; female" {
+		t.Errorf("population = %q", p.Population)
+	}
+	if p.SampleSize != 350 {
+		t.Errorf("sample size = %d", p.SampleSize)
+	}
+	if p.Outcomes != "The primary outcome was 30-day mortality." {
+		t.Errorf("outcomes = %q", p.Outcomes)
+	}
+	if p.Findings != "In conclusion, early treatment was associated with lower mortality." {
+		t.Errorf("findings = %q", p.Findings)
+	}
+}
+
+func TestExtract_Empty(t *testing.T) {
+	p := Extract(eutils.Article{PMID: "3", Title: "Editorial"})
+	if p.Design != "" || p.Population != "" || p.SampleSize != 0 || p.Outcomes != "" || p.Findings != "" {
+		t.Errorf("expected empty profile, got %+v", p)
+	}
+}
@@ -0,0 +1,92 @@
+package compare
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// PICO is an article's population, intervention, comparator, and outcomes,
+// with its design and sample size. Empty fields were not found.
+type PICO struct {
+	PMID         string `json:"pmid"`
+	Title        string `json:"title"`
+	Year         string `json:"year,omitempty"`
+	Population   string `json:"population,omitempty"`
+	Intervention string `json:"intervention,omitempty"`
+	Comparator   string `json:"comparator,omitempty"`
+	Outcomes     string `json:"outcomes,omitempty"`
+	Design       string `json:"design,omitempty"`
+	// SampleSize is the largest participant count the abstract reports, or 0.
+	SampleSize int `json:"sample_size,omitempty"`
+}
+
+var (
+	// versusRe splits a title or sentence comparing two treatments.
+	versusRe = regexp.MustCompile(`(?i)^(.+?)\s+(?:versus|vs\.?|compared (?:with|to))\s+(.+?)(?:\s+(?:for|in|on|among|after|during)\s.*|[:;.].*)?$`)
+	// effectOfRe finds the treatment in "effect of X on/in ...".
+	effectOfRe = regexp.MustCompile(`(?i)\b(?:effects?|efficacy|effectiveness|safety|impact|use) of\s+(.+?)\s+(?:on|in|for|among|versus|vs\.?|compared)\b`)
+	// comparatorCues are control conditions named in abstracts, most
+	// specific first.
+	comparatorCues = []struct{ phrase, label string }{
+		{"placebo", "Placebo"},
+		{"usual care", "Usual care"},
+		{"standard care", "Standard care"},
+		{"standard of care", "Standard of care"},
+		{"treatment as usual", "Treatment as usual"},
+		{"wait-list", "Wait-list"},
+		{"waitlist", "Wait-list"},
+		{"sham", "Sham"},
+		{"no treatment", "No treatment"},
+	}
+)
+
+// ExtractPICO builds the PICO profile of one article from its structured
+// abstract sections, title, and cue phrases, and from the design, sample
+// size, population, and outcomes compare extracts.
+func ExtractPICO(a eutils.Article) PICO {
+	p := Extract(a)
+	intervention, comparator := interventions(a)
+	return PICO{
+		PMID:         a.PMID,
+		Title:        a.Title,
+		Year:         a.Year,
+		Population:   p.Population,
+		Intervention: intervention,
+		Comparator:   comparator,
+		Outcomes:     p.Outcomes,
+		Design:       p.Design,
+		SampleSize:   p.SampleSize,
+	}
+}
+
+// interventions finds the treatment studied and what it was compared with.
+func interventions(a eutils.Article) (intervention, comparator string) {
+	if s := section(a, "INTERVENTION", "INTERVENTIONS", "EXPOSURE", "EXPOSURES"); s != "" {
+		intervention = firstSentence(s)
+		if m := versusRe.FindStringSubmatch(strings.TrimSuffix(intervention, ".")); m != nil {
+			comparator = strings.TrimSpace(m[2])
+		}
+	}
+	title := strings.TrimSuffix(strings.TrimSpace(a.Title), ".")
+	if intervention == "" {
+		if m := versusRe.FindStringSubmatch(title); m != nil {
+			intervention, comparator = strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+		} else if m := effectOfRe.FindStringSubmatch(title); m != nil {
+			intervention = strings.TrimSpace(m[1])
+		} else if m := effectOfRe.FindStringSubmatch(a.Abstract); m != nil {
+			intervention = strings.TrimSpace(m[1])
+		}
+	}
+	if comparator == "" && intervention != "" {
+		lower := strings.ToLower(a.Abstract)
+		for _, c := range comparatorCues {
+			if strings.Contains(lower, c.phrase) {
+				comparator = c.label
+				break
+			}
+		}
+	}
+	return intervention, comparator
+}
@@ -0,0 +1,51 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestExtractPICO(t *testing.T) {
+	tests := []struct {
+		name                     string
+		a                        eutils.Article
+		intervention, comparator string
+	}{
+		{
+			name: "structured section",
+			a: eutils.Article{AbstractSections: []eutils.AbstractSection{
+				{Label: "INTERVENTIONS", Text: "Metformin versus placebo for 16 weeks. Doses were titrated."},
+			}},
+			intervention: "Metformin versus placebo for 16 weeks.",
+			comparator:   "placebo",
+		},
+		{
+			name:         "versus title",
+			a:            eutils.Article{Title: "Melatonin versus cognitive behavioral therapy for insomnia in autism."},
+			intervention: "Melatonin",
+			comparator:   "cognitive behavioral therapy",
+		},
+		{
+			name: "effect-of title with control cue",
+			a: eutils.Article{
+				Title:    "Effect of ketamine on depressive symptoms: a randomized trial",
+				Abstract: "Patients received ketamine or a saline placebo infusion.",
+			},
+			intervention: "ketamine",
+			comparator:   "Placebo",
+		},
+		{
+			name: "none",
+			a:    eutils.Article{Title: "Prevalence of autism in Europe", Abstract: "We surveyed registries."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ExtractPICO(tt.a)
+			if p.Intervention != tt.intervention || p.Comparator != tt.comparator {
+				t.Errorf("intervention, comparator = %q, %q; want %q, %q", p.Intervention, p.Comparator, tt.intervention, tt.comparator)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package compare
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Summary is a short triage summary of one article, built from sentences
+// of its abstract rather than generated text.
+type Summary struct {
+	PMID    string `json:"pmid"`
+	Title   string `json:"title"`
+	Year    string `json:"year,omitempty"`
+	Summary string `json:"summary"`
+	// Sources lists where the sentences came from: "tldr" for Semantic
+	// Scholar's one-sentence summary, "abstract", and "indexing" for the
+	// study design taken from publication types.
+	Sources []string `json:"sources,omitempty"`
+}
+
+// aimCues begin the sentence of an unstructured abstract that states what
+// the study set out to do.
+var aimCues = []string{"we aimed", "we sought", "we investigated", "we examined", "we evaluated",
+	"we assessed", "we compared", "we tested", "the aim", "the objective", "the purpose",
+	"this study", "in this study", "here we", "here, we", "to determine", "to evaluate",
+	"to assess", "to examine", "to investigate", "to compare"}
+
+// Summarize returns up to three sentences on a: what it studied (tldr,
+// Semantic Scholar's summary, when given, or else the abstract's aim), its
+// design and size, and its main finding.
+func Summarize(a eutils.Article, tldr string) Summary {
+	s := Summary{PMID: a.PMID, Title: a.Title, Year: a.Year}
+	var parts []string
+	source := func(src string) {
+		if !slices.Contains(s.Sources, src) {
+			s.Sources = append(s.Sources, src)
+		}
+	}
+
+	if tldr = strings.TrimSpace(tldr); tldr != "" {
+		parts = append(parts, sentence(tldr))
+		source("tldr")
+	} else if aim := aim(a); aim != "" {
+		parts = append(parts, aim)
+		source("abstract")
+	}
+
+	p := Extract(a)
+	switch {
+	case p.Design != "" && p.SampleSize > 0:
+		parts = append(parts, fmt.Sprintf("%s of %d participants.", p.Design, p.SampleSize))
+		source("indexing")
+	case p.Design != "":
+		parts = append(parts, p.Design+".")
+		source("indexing")
+	}
+
+	if f := p.Findings; f != "" && !slices.Contains(parts, f) {
+		parts = append(parts, f)
+		source("abstract")
+	}
+	s.Summary = strings.Join(parts, " ")
+	return s
+}
+
+// aim returns the sentence stating what a study set out to do.
+func aim(a eutils.Article) string {
+	if s := section(a, "OBJECTIVE", "OBJECTIVES", "AIM", "AIMS", "PURPOSE", "IMPORTANCE", "BACKGROUND", "INTRODUCTION"); s != "" {
+		return firstSentence(s)
+	}
+	if len(a.AbstractSections) > 0 {
+		return ""
+	}
+	for _, s := range sentences(a.Abstract) {
+		lower := strings.ToLower(s)
+		for _, cue := range aimCues {
+			if strings.HasPrefix(lower, cue) {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// sentence ends s with a period unless it already ends a sentence.
+func sentence(s string) string {
+	if strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
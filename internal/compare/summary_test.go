@@ -0,0 +1,57 @@
+package compare
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestSummarize(t *testing.T) {
+	a := eutils.Article{
+		PMID:             "1",
+		Title:            "Metformin for fragile X syndrome: a randomized trial",
+		Year:             "2024",
+		PublicationTypes: []string{"Journal Article", "Randomized Controlled Trial"},
+		AbstractSections: []eutils.AbstractSection{
+			{Label: "OBJECTIVE", Text: "To test whether metformin reduces irritability in FXS. Secondary aims followed."},
+			{Label: "METHODS", Text: "We randomized 120 children."},
+			{Label: "CONCLUSIONS", Text: "Metformin did not improve irritability. Larger trials are needed."},
+		},
+	}
+	a.Abstract = "To test whether metformin reduces irritability in FXS. Secondary aims followed. We randomized 120 children. Metformin did not improve irritability. Larger trials are needed."
+
+	got := Summarize(a, "")
+	want := Summary{
+		PMID:  "1",
+		Title: a.Title,
+		Year:  "2024",
+		Summary: "To test whether metformin reduces irritability in FXS. " +
+			"Randomized controlled trial of 120 participants. Metformin did not improve irritability.",
+		Sources: []string{"abstract", "indexing"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	got = Summarize(a, "Metformin did not reduce irritability in children with fragile X syndrome")
+	if !strings.HasPrefix(got.Summary, "Metformin did not reduce irritability in children with fragile X syndrome. ") {
+		t.Errorf("summary should open with the TLDR: %q", got.Summary)
+	}
+	if !reflect.DeepEqual(got.Sources, []string{"tldr", "indexing", "abstract"}) {
+		t.Errorf("sources = %v", got.Sources)
+	}
+}
+
+func TestSummarize_Unstructured(t *testing.T) {
+	a := eutils.Article{
+		PMID:     "2",
+		Abstract: "Sleep problems are common in autism. We examined melatonin use in 80 adolescents. In conclusion, melatonin was well tolerated.",
+	}
+	got := Summarize(a, "").Summary
+	want := "We examined melatonin use in 80 adolescents. In conclusion, melatonin was well tolerated."
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+// Package config reads and writes pubmed-cli's settings file, the bottom
+// layer of its configuration: environment variables override the file, and
+// flags override both.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+)
+
+// File is the settings file: a flat JSON object of key to string value.
+type File struct {
+	path   string
+	values map[string]string
+}
+
+// DefaultPath returns the settings file location, honoring
+// PUBMED_CLI_CONFIG_DIR and otherwise using the platform config directory
+// (e.g. ~/.config/pubmed-cli/config.json).
+func DefaultPath() (string, error) {
+	if d := os.Getenv(alert.EnvConfigDir); d != "" {
+		return filepath.Join(d, "config.json"), nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(base, "pubmed-cli", "config.json"), nil
+}
+
+// Load reads the settings stored at path; a missing file has none.
+func Load(path string) (*File, error) {
+	f := &File{path: path, values: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	if err := json.Unmarshal(data, &f.values); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if f.values == nil {
+		f.values = make(map[string]string)
+	}
+	return f, nil
+}
+
+// Path returns the file's location.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Get returns the value stored for key and whether there is one.
+func (f *File) Get(key string) (string, bool) {
+	v, ok := f.values[key]
+	return v, ok
+}
+
+// Set stores value for key.
+func (f *File) Set(key, value string) {
+	f.values[key] = value
+}
+
+// Unset removes key and reports whether it was set.
+func (f *File) Unset(key string) bool {
+	_, ok := f.values[key]
+	delete(f.values, key)
+	return ok
+}
+
+// Keys returns the keys with stored values, sorted.
+func (f *File) Keys() []string {
+	keys := make([]string, 0, len(f.values))
+	for k := range f.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Save writes the settings back to their file, replacing it atomically.
+// The file is readable only by its owner, since it may hold API keys.
+func (f *File) Save() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(f.values, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
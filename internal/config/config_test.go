@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "config.json")
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.Get("api_key"); ok {
+		t.Error("missing file should have no settings")
+	}
+	f.Set("api_key", "abc")
+	f.Set("limit", "50")
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("config file mode = %o, want 600", perm)
+	}
+
+	g, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := g.Get("api_key"); v != "abc" {
+		t.Errorf("api_key = %q", v)
+	}
+	if !reflect.DeepEqual(g.Keys(), []string{"api_key", "limit"}) {
+		t.Errorf("Keys = %v", g.Keys())
+	}
+	if !g.Unset("limit") || g.Unset("limit") {
+		t.Error("Unset should report whether the key was set")
+	}
+}
+
+func TestDefaultPath_ConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PUBMED_CLI_CONFIG_DIR", dir)
+	got, err := DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != filepath.Join(dir, "config.json") {
+		t.Errorf("DefaultPath = %q", got)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte("not json"), 0o600)
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for invalid config")
+	}
+}
@@ -0,0 +1,262 @@
+// Package crossref fills gaps in PubMed records from Crossref metadata and
+// resolves DOIs that are not indexed in PubMed.
+package crossref
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refcheck"
+)
+
+// DefaultURL is the Crossref REST API.
+const DefaultURL = "https://api.crossref.org"
+
+// EnvMailto sets the contact email sent with Crossref requests, which
+// routes them to Crossref's faster "polite" pool.
+const EnvMailto = "CROSSREF_MAILTO"
+
+// Source marks records and fields that came from Crossref.
+const Source = "crossref"
+
+// MinTitleSimilarity is the token overlap a Crossref title search hit needs
+// with the record's title before its DOI is adopted.
+const MinTitleSimilarity = 0.9
+
+// Client queries the Crossref REST API. Requests share the NCBI base
+// client's HTTP client, rate limit, and response size cap.
+type Client struct {
+	*ncbi.BaseClient
+	URL string
+	// Mailto identifies the caller to Crossref; it is optional.
+	Mailto string
+}
+
+// NewClient creates a Crossref client using an existing NCBI base client.
+func NewClient(base *ncbi.BaseClient, mailto string) *Client {
+	return &Client{BaseClient: base, URL: DefaultURL, Mailto: mailto}
+}
+
+// Work is the part of a Crossref work record pubmed-cli uses.
+type Work struct {
+	DOI            string   `json:"DOI"`
+	Type           string   `json:"type"`
+	Title          []string `json:"title"`
+	ContainerTitle []string `json:"container-title"`
+	ShortContainer []string `json:"short-container-title"`
+	Volume         string   `json:"volume"`
+	Issue          string   `json:"issue"`
+	Page           string   `json:"page"`
+	Author         []struct {
+		Given  string `json:"given"`
+		Family string `json:"family"`
+		Name   string `json:"name"`
+	} `json:"author"`
+	Issued struct {
+		DateParts [][]int `json:"date-parts"`
+	} `json:"issued"`
+	License []struct {
+		URL            string `json:"URL"`
+		ContentVersion string `json:"content-version"`
+	} `json:"license"`
+	ReferenceCount int `json:"reference-count"`
+}
+
+// Work returns Crossref's record for doi, or nil when Crossref does not
+// know it.
+func (c *Client) Work(ctx context.Context, doi string) (*Work, error) {
+	body, err := c.get(ctx, c.URL+"/works/"+url.PathEscape(doi), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Crossref lookup of %s failed: %w", doi, err)
+	}
+	if body == nil {
+		return nil, nil
+	}
+	var resp struct {
+		Message Work `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing Crossref response: %w", err)
+	}
+	return &resp.Message, nil
+}
+
+// FindDOI searches Crossref for a's title and first author and returns the
+// DOI of the top hit when its title matches a's closely (and its year, when
+// both are known), or "" when nothing matches.
+func (c *Client) FindDOI(ctx context.Context, a eutils.Article) (string, error) {
+	title := refcheck.NormalizeTitle(a.Title)
+	if title == "" {
+		return "", nil
+	}
+	params := url.Values{}
+	params.Set("query.bibliographic", a.Title)
+	if len(a.Authors) > 0 && a.Authors[0].LastName != "" {
+		params.Set("query.author", a.Authors[0].LastName)
+	}
+	params.Set("rows", "1")
+	params.Set("select", "DOI,title,issued")
+	body, err := c.get(ctx, c.URL+"/works", params)
+	if err != nil {
+		return "", fmt.Errorf("Crossref search failed: %w", err)
+	}
+	if body == nil {
+		return "", nil
+	}
+	var resp struct {
+		Message struct {
+			Items []Work `json:"items"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("parsing Crossref response: %w", err)
+	}
+	if len(resp.Message.Items) == 0 {
+		return "", nil
+	}
+	hit := resp.Message.Items[0]
+	if refcheck.TokenJaccard(title, refcheck.NormalizeTitle(first(hit.Title))) < MinTitleSimilarity {
+		return "", nil
+	}
+	if year := hit.year(); a.Year != "" && year != "" && year != a.Year {
+		return "", nil
+	}
+	return refcheck.NormalizeDOI(hit.DOI), nil
+}
+
+// Article converts w to a record for a DOI that is not in PubMed. It has
+// no PMID and its Source is "crossref".
+func (w *Work) Article() eutils.Article {
+	a := eutils.Article{
+		Title:          first(w.Title),
+		Journal:        first(w.ContainerTitle),
+		JournalAbbrev:  first(w.ShortContainer),
+		Volume:         w.Volume,
+		Issue:          w.Issue,
+		Pages:          w.Page,
+		Year:           w.year(),
+		Month:          w.month(),
+		DOI:            refcheck.NormalizeDOI(w.DOI),
+		License:        w.license(),
+		ReferenceCount: w.ReferenceCount,
+		Source:         Source,
+	}
+	if w.Type != "" {
+		a.PublicationTypes = []string{w.Type}
+	}
+	for _, au := range w.Author {
+		author := eutils.Author{LastName: au.Family, ForeName: au.Given, Initials: initials(au.Given)}
+		if au.Family == "" {
+			author.CollectiveName = au.Name
+		}
+		author.DisplayName = author.FullName()
+		a.Authors = append(a.Authors, author)
+	}
+	return a
+}
+
+// Enrich fills a's empty DOI, page, volume, issue, license, and reference
+// count fields from w and records each one it filled in
+// a.CrossrefFields. Fields PubMed supplied are never overwritten.
+func Enrich(a *eutils.Article, w *Work) {
+	fill := func(name string, dst *string, v string) {
+		if *dst == "" && v != "" {
+			*dst = v
+			a.CrossrefFields = append(a.CrossrefFields, name)
+		}
+	}
+	fill("doi", &a.DOI, refcheck.NormalizeDOI(w.DOI))
+	fill("volume", &a.Volume, w.Volume)
+	fill("issue", &a.Issue, w.Issue)
+	fill("pages", &a.Pages, w.Page)
+	fill("license", &a.License, w.license())
+	if a.ReferenceCount == 0 && w.ReferenceCount > 0 {
+		a.ReferenceCount = w.ReferenceCount
+		a.CrossrefFields = append(a.CrossrefFields, "reference_count")
+	}
+}
+
+// Fill enriches a from Crossref, first finding its DOI by title when it
+// has none. Records Crossref cannot match are left unchanged.
+func (c *Client) Fill(ctx context.Context, a *eutils.Article) error {
+	doi := a.DOI
+	if doi == "" {
+		var err error
+		if doi, err = c.FindDOI(ctx, *a); err != nil || doi == "" {
+			return err
+		}
+	}
+	w, err := c.Work(ctx, doi)
+	if err != nil || w == nil {
+		return err
+	}
+	Enrich(a, w)
+	return nil
+}
+
+func (w *Work) year() string {
+	if len(w.Issued.DateParts) == 0 || len(w.Issued.DateParts[0]) == 0 || w.Issued.DateParts[0][0] == 0 {
+		return ""
+	}
+	return strconv.Itoa(w.Issued.DateParts[0][0])
+}
+
+func (w *Work) month() string {
+	if len(w.Issued.DateParts) == 0 || len(w.Issued.DateParts[0]) < 2 {
+		return ""
+	}
+	return strconv.Itoa(w.Issued.DateParts[0][1])
+}
+
+// license is the URL of the version-of-record license, or of the first
+// license listed.
+func (w *Work) license() string {
+	for _, l := range w.License {
+		if l.ContentVersion == "vor" {
+			return l.URL
+		}
+	}
+	if len(w.License) > 0 {
+		return w.License[0].URL
+	}
+	return ""
+}
+
+func first(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return strings.Join(strings.Fields(s[0]), " ")
+}
+
+// initials derives PubMed-style initials ("JA") from given names.
+func initials(given string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(given, func(r rune) bool { return r == ' ' || r == '-' || r == '.' }) {
+		for _, r := range part {
+			b.WriteRune(r)
+			break
+		}
+	}
+	return strings.ToUpper(b.String())
+}
+
+// get performs a GET with the caller's mailto, if any.
+func (c *Client) get(ctx context.Context, u string, params url.Values) ([]byte, error) {
+	if c.Mailto != "" {
+		if params == nil {
+			params = url.Values{}
+		}
+		params.Set("mailto", c.Mailto)
+	}
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	return c.GetURL(ctx, u)
+}
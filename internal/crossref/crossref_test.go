@@ -0,0 +1,103 @@
+package crossref
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// newTestClient serves one known work and a title search that finds it.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	work, err := os.ReadFile(filepath.Join("..", "..", "testdata", "crossref_work.json"))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("mailto") != "me@example.org" {
+			t.Errorf("missing mailto: %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/works/10.1186/s11689-024-09999-1":
+			w.Write(work)
+		case "/works":
+			if r.URL.Query().Get("query.author") != "Martin" {
+				t.Errorf("expected an author query, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"message":{"items":[{"DOI":"10.1186/S11689-024-09999-1",
+"title":["Metformin in fragile X syndrome: a preprint-era trial"],"issued":{"date-parts":[[2024]]}}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient(ncbi.NewBaseClient(ncbi.WithHTTPClient(srv.Client())), "me@example.org")
+	c.URL = srv.URL
+	return c
+}
+
+func TestWork_Article(t *testing.T) {
+	c := newTestClient(t)
+	w, err := c.Work(context.Background(), "10.1186/s11689-024-09999-1")
+	if err != nil || w == nil {
+		t.Fatalf("Work = %v, %v", w, err)
+	}
+	a := w.Article()
+	if a.PMID != "" || a.Source != Source || a.DOI != "10.1186/s11689-024-09999-1" {
+		t.Errorf("unexpected identifiers: %+v", a)
+	}
+	if a.Title != "Metformin in fragile X syndrome: a preprint-era trial" || a.JournalAbbrev != "J Neurodev Disord" {
+		t.Errorf("unexpected title or journal: %q, %q", a.Title, a.JournalAbbrev)
+	}
+	if a.Year != "2024" || a.Month != "3" || a.Pages != "12-20" || a.ReferenceCount != 42 {
+		t.Errorf("unexpected citation fields: %+v", a)
+	}
+	if a.License != "https://creativecommons.org/licenses/by/4.0" {
+		t.Errorf("expected the version-of-record license, got %q", a.License)
+	}
+	if len(a.Authors) != 2 || a.Authors[0].Initials != "JP" || a.Authors[1].CollectiveName != "FXS Trials Network" {
+		t.Errorf("unexpected authors: %+v", a.Authors)
+	}
+
+	if w, err := c.Work(context.Background(), "10.1000/unknown"); err != nil || w != nil {
+		t.Errorf("unknown DOI: got %v, %v", w, err)
+	}
+}
+
+func TestFill(t *testing.T) {
+	c := newTestClient(t)
+	a := eutils.Article{
+		PMID:    "38000001",
+		Title:   "Metformin in fragile X syndrome: a preprint-era trial.",
+		Authors: []eutils.Author{{LastName: "Martin"}},
+		Year:    "2024",
+		Volume:  "15",
+	}
+	if err := c.Fill(context.Background(), &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.DOI != "10.1186/s11689-024-09999-1" || a.Pages != "12-20" || a.ReferenceCount != 42 {
+		t.Errorf("expected gaps filled, got %+v", a)
+	}
+	if a.Volume != "15" {
+		t.Errorf("PubMed's volume should be kept, got %q", a.Volume)
+	}
+	if got := strings.Join(a.CrossrefFields, ","); got != "doi,issue,pages,license,reference_count" {
+		t.Errorf("unexpected provenance: %s", got)
+	}
+
+	other := eutils.Article{Title: "An unrelated title about something else", Authors: []eutils.Author{{LastName: "Martin"}}}
+	if err := c.Fill(context.Background(), &other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.DOI != "" || len(other.CrossrefFields) != 0 {
+		t.Errorf("a dissimilar title should not be matched, got %+v", other)
+	}
+}
@@ -0,0 +1,180 @@
+// Package dedupe merges reference sets from several sources (PubMed,
+// Embase, Scopus exports, PMID lists) and removes duplicate records.
+package dedupe
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/refcheck"
+)
+
+// DefaultThreshold is the title similarity (token Jaccard) at or above which
+// two records with compatible first author and year are duplicates.
+const DefaultThreshold = 0.9
+
+// Match reasons, strongest first.
+const (
+	ByPMID  = "pmid"
+	ByDOI   = "doi"
+	ByTitle = "title"
+)
+
+// Record is one reference read from an input.
+type Record struct {
+	Source  string         `json:"source"`
+	Index   int            `json:"index"` // 1-based position within Source
+	Article eutils.Article `json:"article"`
+}
+
+// Duplicate pairs a dropped record with the record it duplicates.
+type Duplicate struct {
+	Kept       Record  `json:"kept"`
+	Dropped    Record  `json:"dropped"`
+	Reason     string  `json:"reason"`
+	Similarity float64 `json:"similarity,omitempty"`
+}
+
+// Result is a merged, deduplicated set.
+type Result struct {
+	Input      int         `json:"input"`
+	Records    []Record    `json:"records"`
+	Duplicates []Duplicate `json:"duplicates"`
+}
+
+// Dedupe keeps the first occurrence of each reference, in input order.
+// Records match on PMID, then normalized DOI, then title similarity of at
+// least threshold when first-author surnames agree and publication years
+// are within one (e-pub versus print); missing author or year never blocks
+// a match, but a different PMID or DOI always does. Identifiers missing
+// from a kept record are filled in from its duplicates, and every
+// identifier a duplicate carried is matched to the kept record, so that
+// later records can match on them.
+func Dedupe(records []Record, threshold float64) Result {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	res := Result{Input: len(records)}
+	byPMID := make(map[string]int)
+	byDOI := make(map[string]int)
+	byYear := make(map[int][]int) // year (0 when unknown) → kept indices
+
+	for _, rec := range records {
+		a := rec.Article
+		doi := refcheck.NormalizeDOI(a.DOI)
+		title := refcheck.NormalizeTitle(a.Title)
+
+		match, reason, sim := -1, "", 0.0
+		if i, ok := byPMID[a.PMID]; ok && a.PMID != "" {
+			match, reason = i, ByPMID
+		} else if i, ok := byDOI[doi]; ok && doi != "" {
+			match, reason = i, ByDOI
+		} else if title != "" {
+			match, sim = titleMatch(res.Records, byYear, a, title, threshold)
+			reason = ByTitle
+		}
+
+		if match >= 0 {
+			kept := &res.Records[match]
+			res.Duplicates = append(res.Duplicates, Duplicate{Kept: *kept, Dropped: rec, Reason: reason, Similarity: sim})
+			fillIdentifiers(kept, rec.Article)
+			for _, id := range []string{kept.Article.PMID, a.PMID} {
+				if id != "" {
+					byPMID[id] = match
+				}
+			}
+			for _, d := range []string{refcheck.NormalizeDOI(kept.Article.DOI), doi} {
+				if d != "" {
+					byDOI[d] = match
+				}
+			}
+			continue
+		}
+
+		i := len(res.Records)
+		res.Records = append(res.Records, rec)
+		if a.PMID != "" {
+			byPMID[a.PMID] = i
+		}
+		if doi != "" {
+			byDOI[doi] = i
+		}
+		year, _ := strconv.Atoi(a.Year)
+		byYear[year] = append(byYear[year], i)
+	}
+	return res
+}
+
+// titleMatch finds the most similar kept record with a compatible year,
+// first author, and identifiers, returning its index and similarity, or -1.
+func titleMatch(kept []Record, byYear map[int][]int, a eutils.Article, title string, threshold float64) (int, float64) {
+	year, _ := strconv.Atoi(a.Year)
+	var candidates []int
+	if year == 0 {
+		for _, ids := range byYear {
+			candidates = append(candidates, ids...)
+		}
+	} else {
+		for _, y := range []int{0, year - 1, year, year + 1} {
+			candidates = append(candidates, byYear[y]...)
+		}
+	}
+
+	best, bestSim := -1, 0.0
+	for _, i := range candidates {
+		k := kept[i].Article
+		if !sameFirstAuthor(a, k) || conflictingIDs(a, k) {
+			continue
+		}
+		sim := refcheck.TokenJaccard(title, refcheck.NormalizeTitle(k.Title))
+		if sim >= threshold && (sim > bestSim || (sim == bestSim && i < best)) {
+			best, bestSim = i, sim
+		}
+	}
+	return best, bestSim
+}
+
+// sameFirstAuthor compares first-author surnames, ignoring case and
+// punctuation; an unknown author is compatible with anything.
+func sameFirstAuthor(a, b eutils.Article) bool {
+	if len(a.Authors) == 0 || len(b.Authors) == 0 {
+		return true
+	}
+	return surname(a.Authors[0]) == surname(b.Authors[0])
+}
+
+// conflictingIDs reports whether a and b carry different PMIDs or DOIs,
+// which makes them different records however alike their titles are.
+func conflictingIDs(a, b eutils.Article) bool {
+	if a.PMID != "" && b.PMID != "" && a.PMID != b.PMID {
+		return true
+	}
+	da, db := refcheck.NormalizeDOI(a.DOI), refcheck.NormalizeDOI(b.DOI)
+	return da != "" && db != "" && da != db
+}
+
+func surname(au eutils.Author) string {
+	name := au.LastName
+	if name == "" {
+		name = au.CollectiveName
+	}
+	return refcheck.NormalizeTitle(name)
+}
+
+// fillIdentifiers copies identifiers and metadata the kept record lacks.
+func fillIdentifiers(kept *Record, from eutils.Article) {
+	k := &kept.Article
+	if k.PMID == "" {
+		k.PMID = from.PMID
+	}
+	if k.DOI == "" {
+		k.DOI = from.DOI
+	}
+	if k.PMCID == "" {
+		k.PMCID = from.PMCID
+	}
+	if strings.TrimSpace(k.Abstract) == "" {
+		k.Abstract = from.Abstract
+	}
+}
@@ -0,0 +1,92 @@
+package dedupe
+
+import (
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func rec(source string, a eutils.Article) Record {
+	return Record{Source: source, Article: a}
+}
+
+func TestDedupe(t *testing.T) {
+	smith := []eutils.Author{{LastName: "Smith"}}
+	records := []Record{
+		rec("pubmed", eutils.Article{PMID: "1", Title: "Metformin in fragile X syndrome", Year: "2024", Authors: smith}),
+		rec("embase", eutils.Article{DOI: "10.1/ABC", Title: "Metformin in Fragile X Syndrome.", Year: "2023", Authors: smith}),
+		rec("pubmed", eutils.Article{PMID: "2", DOI: "10.1/abc", Title: "Unrelated"}),
+		rec("list", eutils.Article{PMID: "1"}),
+		rec("embase", eutils.Article{Title: "Metformin in fragile X syndrome", Year: "2024", Authors: []eutils.Author{{LastName: "Jones"}}}),
+		rec("embase", eutils.Article{Title: "Metformin in fragile X syndrome", Year: "2019", Authors: smith}),
+	}
+
+	res := Dedupe(records, 0)
+	if res.Input != 6 {
+		t.Errorf("expected input count 6, got %d", res.Input)
+	}
+	if len(res.Records) != 3 {
+		t.Fatalf("expected 3 unique records, got %d: %+v", len(res.Records), res.Records)
+	}
+	if len(res.Duplicates) != 3 {
+		t.Fatalf("expected 3 duplicates, got %d", len(res.Duplicates))
+	}
+
+	// Title match across sources fills the DOI in, so the later DOI record matches too.
+	if d := res.Duplicates[0]; d.Reason != ByTitle || d.Dropped.Source != "embase" || d.Similarity != 1 {
+		t.Errorf("unexpected first duplicate: %+v", d)
+	}
+	if res.Records[0].Article.DOI != "10.1/ABC" {
+		t.Errorf("expected DOI copied onto kept record, got %q", res.Records[0].Article.DOI)
+	}
+	if d := res.Duplicates[1]; d.Reason != ByDOI || d.Dropped.Article.PMID != "2" {
+		t.Errorf("unexpected second duplicate: %+v", d)
+	}
+	if d := res.Duplicates[2]; d.Reason != ByPMID || d.Dropped.Source != "list" {
+		t.Errorf("unexpected third duplicate: %+v", d)
+	}
+
+	// Different first author and a distant year keep otherwise identical titles apart.
+	if res.Records[1].Article.Authors[0].LastName != "Jones" || res.Records[2].Article.Year != "2019" {
+		t.Errorf("unexpected unique records: %+v", res.Records)
+	}
+}
+
+func TestDedupe_ConflictingIdentifiers(t *testing.T) {
+	smith := []eutils.Author{{LastName: "Smith"}}
+	title := "Metformin in fragile X syndrome"
+	records := []Record{
+		rec("pubmed", eutils.Article{PMID: "1", DOI: "10.1/a", Title: title, Year: "2024", Authors: smith}),
+		rec("pubmed", eutils.Article{PMID: "2", DOI: "10.1/c", Title: title, Year: "2024", Authors: smith}),
+		rec("embase", eutils.Article{DOI: "10.1/b", Title: title, Year: "2024", Authors: smith}),
+		rec("scopus", eutils.Article{Title: title, Year: "2024", Authors: smith}),
+	}
+
+	res := Dedupe(records, 0)
+	// Neither a different PMID nor a different DOI is a title duplicate;
+	// the record without identifiers still is.
+	if len(res.Records) != 3 || len(res.Duplicates) != 1 {
+		t.Fatalf("expected 3 records and 1 duplicate, got %d and %d", len(res.Records), len(res.Duplicates))
+	}
+	if d := res.Duplicates[0]; d.Reason != ByTitle || d.Dropped.Source != "scopus" {
+		t.Errorf("unexpected duplicate: %+v", d)
+	}
+}
+
+func TestDedupe_DroppedIdentifiersMatchLater(t *testing.T) {
+	records := []Record{
+		rec("pubmed", eutils.Article{PMID: "1", DOI: "10.1/a", Title: "Metformin trial"}),
+		// Same PMID, so dropped, but it carries a DOI variant of its own.
+		rec("embase", eutils.Article{PMID: "1", DOI: "10.1/a-erratum", Title: "Metformin trial"}),
+		// Shares only the dropped record's DOI.
+		rec("scopus", eutils.Article{DOI: "10.1/A-ERRATUM", Title: "Something else"}),
+	}
+
+	res := Dedupe(records, 0)
+	if len(res.Records) != 1 || len(res.Duplicates) != 2 {
+		t.Fatalf("expected 1 record and 2 duplicates, got %d and %d", len(res.Records), len(res.Duplicates))
+	}
+	if d := res.Duplicates[1]; d.Reason != ByDOI || d.Dropped.Source != "scopus" || d.Kept.Article.PMID != "1" {
+		t.Errorf("unexpected duplicate: %+v", d)
+	}
+}
@@ -0,0 +1,278 @@
+package dedupe
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+var (
+	// risLineRe matches a tagged RIS line such as "TI  - Title".
+	risLineRe = regexp.MustCompile(`^([A-Z][A-Z0-9])  -(?: (.*))?$`)
+	// pmidValueRe finds a PMID in RIS values marked "PMID:" and in PubMed
+	// URLs. Bare numbers are not PMIDs: reference managers put record
+	// numbers and database accession numbers in ID and AN.
+	pmidValueRe = regexp.MustCompile(`(?i)^pmid:?\s*(\d{1,9})$|pubmed\.ncbi\.nlm\.nih\.gov/(\d{1,9})`)
+	// doiURLRe finds a DOI inside a doi.org link.
+	doiURLRe = regexp.MustCompile(`(?i)doi\.org/(10\.\S+)`)
+	// yearValueRe finds the first four-digit year in a date field.
+	yearValueRe = regexp.MustCompile(`\b(1[89]\d\d|2\d\d\d)\b`)
+)
+
+// ReadFile reads records from path, choosing the parser by extension:
+// .ris for RIS, .csv and .tsv for tables, anything else as a PMID list.
+// A path of "-" reads a PMID list from stdin.
+func ReadFile(path string, stdin io.Reader) ([]Record, error) {
+	if path == "-" {
+		return ReadPMIDs(stdin, "stdin")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	source := filepath.Base(path)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ris":
+		return ReadRIS(f, source)
+	case ".csv":
+		return ReadTable(f, source, ',')
+	case ".tsv", ".tab":
+		return ReadTable(f, source, '\t')
+	default:
+		return ReadPMIDs(f, source)
+	}
+}
+
+// ReadRIS parses RIS records, as exported by PubMed, Embase, Scopus, and
+// citation managers.
+func ReadRIS(r io.Reader, source string) ([]Record, error) {
+	var (
+		records []Record
+		a       eutils.Article
+		open    bool
+		last    string
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), "\r")
+		m := risLineRe.FindStringSubmatch(line)
+		if m == nil {
+			// Continuation of a wrapped value (abstracts, long titles).
+			if open && strings.TrimSpace(line) != "" {
+				appendRIS(&a, last, strings.TrimSpace(line))
+			}
+			continue
+		}
+		tag, value := m[1], strings.TrimSpace(m[2])
+		switch tag {
+		case "TY":
+			a, open = eutils.Article{}, true
+		case "ER":
+			if open {
+				records = append(records, Record{Source: source, Index: len(records) + 1, Article: a})
+			}
+			open = false
+		default:
+			if open {
+				setRIS(&a, tag, value)
+			}
+		}
+		last = tag
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+	return records, nil
+}
+
+func setRIS(a *eutils.Article, tag, value string) {
+	if value == "" {
+		return
+	}
+	switch tag {
+	case "TI", "T1":
+		a.Title = value
+	case "AU", "A1":
+		a.Authors = append(a.Authors, parseAuthor(value))
+	case "PY", "Y1", "DA":
+		if a.Year == "" {
+			a.Year = yearValueRe.FindString(value)
+		}
+	case "JO", "JF", "T2":
+		if a.Journal == "" {
+			a.Journal = value
+		}
+	case "JA", "J2":
+		a.JournalAbbrev = value
+	case "VL":
+		a.Volume = value
+	case "IS":
+		a.Issue = value
+	case "SP":
+		a.Pages = value
+	case "EP":
+		if a.Pages != "" {
+			a.Pages += "-" + value
+		}
+	case "DO":
+		a.DOI = value
+	case "AB", "N2":
+		a.Abstract = value
+	case "ID", "AN", "C1", "UR", "L2":
+		if a.PMID == "" {
+			if m := pmidValueRe.FindStringSubmatch(value); m != nil {
+				a.PMID = m[1] + m[2]
+			}
+		}
+		if a.DOI == "" {
+			if m := doiURLRe.FindStringSubmatch(value); m != nil {
+				a.DOI = m[1]
+			}
+		}
+	}
+}
+
+func appendRIS(a *eutils.Article, tag, value string) {
+	switch tag {
+	case "TI", "T1":
+		a.Title += " " + value
+	case "AB", "N2":
+		a.Abstract += " " + value
+	}
+}
+
+// tableColumns maps normalized header names from common exports to fields.
+var tableColumns = map[string]string{
+	"pmid": "pmid", "pubmed id": "pmid", "pubmed_id": "pmid", "pubmedid": "pmid",
+	"doi":   "doi",
+	"title": "title", "article title": "title", "document title": "title",
+	"authors": "authors", "author": "authors", "author names": "authors", "author full names": "authors", "first_author": "authors",
+	"year": "year", "publication year": "year", "pubyear": "year", "py": "year", "create date": "year",
+	"journal": "journal", "journal/book": "journal", "source title": "journal", "source": "journal",
+	"volume": "volume", "issue": "issue", "pages": "pages",
+	"abstract": "abstract",
+}
+
+// ReadTable parses a CSV or TSV export with a header row. Columns are
+// matched by name (PMID, DOI, Title, Authors, Year, Journal, ...) so PubMed,
+// Embase, Scopus, and pubmed-cli exports all work.
+func ReadTable(r io.Reader, source string, sep rune) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = sep
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	fields := make([]string, len(rows[0]))
+	found := false
+	for i, h := range rows[0] {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		fields[i] = tableColumns[h]
+		found = found || fields[i] != ""
+	}
+	if !found {
+		return nil, fmt.Errorf("%s: no recognizable columns (expected PMID, DOI, Title, Authors, Year)", source)
+	}
+
+	records := make([]Record, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		var a eutils.Article
+		for i, value := range row {
+			if i >= len(fields) {
+				break
+			}
+			value = strings.TrimSpace(value)
+			switch fields[i] {
+			case "pmid":
+				a.PMID = value
+			case "doi":
+				a.DOI = value
+			case "title":
+				a.Title = value
+			case "authors":
+				a.Authors = parseAuthorList(value)
+			case "year":
+				a.Year = yearValueRe.FindString(value)
+			case "journal":
+				a.Journal = value
+			case "volume":
+				a.Volume = value
+			case "issue":
+				a.Issue = value
+			case "pages":
+				a.Pages = value
+			case "abstract":
+				a.Abstract = value
+			}
+		}
+		records = append(records, Record{Source: source, Index: len(records) + 1, Article: a})
+	}
+	return records, nil
+}
+
+// ReadPMIDs reads PMIDs separated by whitespace or commas.
+func ReadPMIDs(r io.Reader, source string) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		for _, id := range strings.Split(scanner.Text(), ",") {
+			if id == "" {
+				continue
+			}
+			if strings.Trim(id, "0123456789") != "" {
+				return nil, fmt.Errorf("%s: %q is not a PMID", source, id)
+			}
+			records = append(records, Record{Source: source, Index: len(records) + 1, Article: eutils.Article{PMID: id}})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+	return records, nil
+}
+
+// parseAuthorList splits "Smith J; Doe R" or "Smith J, Doe R" into authors.
+func parseAuthorList(s string) []eutils.Author {
+	sep := ","
+	if strings.Contains(s, ";") {
+		sep = ";"
+	}
+	var authors []eutils.Author
+	for _, name := range strings.Split(s, sep) {
+		if name = strings.TrimSpace(name); name != "" {
+			authors = append(authors, parseAuthor(name))
+		}
+	}
+	return authors
+}
+
+// parseAuthor reads "Smith, John A" or "Smith JA" into an author.
+func parseAuthor(name string) eutils.Author {
+	var au eutils.Author
+	if last, fore, ok := strings.Cut(name, ","); ok {
+		au.LastName, au.ForeName = strings.TrimSpace(last), strings.TrimSpace(fore)
+	} else if i := strings.LastIndex(name, " "); i > 0 {
+		au.LastName, au.Initials = name[:i], name[i+1:]
+	} else {
+		au.LastName = name
+	}
+	au.DisplayName = au.FullName()
+	return au
+}
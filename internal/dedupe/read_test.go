@@ -0,0 +1,137 @@
+package dedupe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleRIS = `TY  - JOUR
+TI  - Metformin in fragile X syndrome:
+      a randomized trial
+AU  - Smith, Jane A
+AU  - Doe, Robert
+PY  - 2024/03/01
+JO  - Journal of Neurodevelopmental Disorders
+VL  - 16
+SP  - 12
+EP  - 19
+DO  - 10.1186/S11689-024-00001-1
+ID  - PMID:38000001
+ER  -
+
+TY  - JOUR
+T1  - Another title
+A1  - Lee, K
+Y1  - 2023
+UR  - https://doi.org/10.1000/xyz
+ER  -
+`
+
+func TestReadRIS(t *testing.T) {
+	records, err := ReadRIS(strings.NewReader(sampleRIS), "embase.ris")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	a := records[0].Article
+	if a.Title != "Metformin in fragile X syndrome: a randomized trial" {
+		t.Errorf("expected wrapped title to be joined, got %q", a.Title)
+	}
+	if a.PMID != "38000001" || a.Year != "2024" || a.Pages != "12-19" || a.Volume != "16" {
+		t.Errorf("unexpected fields: %+v", a)
+	}
+	if len(a.Authors) != 2 || a.Authors[0].LastName != "Smith" || a.Authors[0].ForeName != "Jane A" {
+		t.Errorf("unexpected authors: %+v", a.Authors)
+	}
+	if records[0].Source != "embase.ris" || records[1].Index != 2 {
+		t.Errorf("unexpected provenance: %+v", records[1])
+	}
+	if records[1].Article.DOI != "10.1000/xyz" {
+		t.Errorf("expected DOI from doi.org URL, got %q", records[1].Article.DOI)
+	}
+}
+
+func TestReadRIS_RecordNumbers(t *testing.T) {
+	// EndNote record numbers and accession numbers are not PMIDs, so two
+	// different records sharing one are not merged.
+	data := `TY  - JOUR
+TI  - Metformin in fragile X syndrome
+ID  - 12
+AN  - 2019345678
+ER  -
+
+TY  - JOUR
+TI  - Sleep in autism
+ID  - 12
+ER  -
+
+TY  - JOUR
+TI  - Lovastatin in fragile X syndrome
+UR  - https://pubmed.ncbi.nlm.nih.gov/38000002/
+ER  -
+`
+	records, err := ReadRIS(strings.NewReader(data), "endnote.ris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	for _, r := range records[:2] {
+		if r.Article.PMID != "" {
+			t.Errorf("record %d: took %q from a record number as its PMID", r.Index, r.Article.PMID)
+		}
+	}
+	if got := records[2].Article.PMID; got != "38000002" {
+		t.Errorf("expected PMID from the PubMed URL, got %q", got)
+	}
+	if res := Dedupe(records, 0); len(res.Duplicates) != 0 {
+		t.Errorf("records sharing an ID number were merged: %+v", res.Duplicates)
+	}
+}
+
+func TestReadTable(t *testing.T) {
+	data := "\ufeffPMID,Title,Authors,Journal/Book,Publication Year,DOI\n" +
+		"38000001,Metformin in fragile X syndrome,\"Smith J, Doe R\",J Neurodev Disord,2024,10.1186/x\n"
+	records, err := ReadTable(strings.NewReader(data), "pubmed.csv", ',')
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	a := records[0].Article
+	if a.PMID != "38000001" || a.Year != "2024" || a.Journal != "J Neurodev Disord" || a.DOI != "10.1186/x" {
+		t.Errorf("unexpected fields: %+v", a)
+	}
+	if len(a.Authors) != 2 || a.Authors[0].LastName != "Smith" || a.Authors[0].Initials != "J" {
+		t.Errorf("unexpected authors: %+v", a.Authors)
+	}
+
+	if _, err := ReadTable(strings.NewReader("foo,bar\n1,2\n"), "x.csv", ','); err == nil {
+		t.Error("expected error for unrecognized columns")
+	}
+}
+
+func TestReadFile_PMIDList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pmids.txt")
+	if err := os.WriteFile(path, []byte("111\n222, 333\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ReadFile(path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 3 || records[2].Article.PMID != "333" || records[0].Source != "pmids.txt" {
+		t.Errorf("unexpected records: %+v", records)
+	}
+
+	if _, err := ReadFile("-", strings.NewReader("12 abc")); err == nil {
+		t.Error("expected non-PMID token to be rejected")
+	}
+}
@@ -0,0 +1,120 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// digestEntry is one new article in a digest.
+type digestEntry struct {
+	PMID    string
+	Title   string
+	Authors string
+	Source  string
+	URL     string
+}
+
+// digestAlert is one alert's new articles in a digest.
+type digestAlert struct {
+	Name      string
+	Query     string
+	Truncated bool
+	Entries   []digestEntry
+}
+
+var digestHTML = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html><body style="font-family: -apple-system, Helvetica, Arial, sans-serif; max-width: 720px; color: #222">
+<h1 style="font-size: 20px">{{.Heading}}</h1>
+{{range .Alerts}}<h2 style="font-size: 16px; margin-bottom: 2px">{{.Name}}: {{len .Entries}} new</h2>
+<div style="color: #777; font-size: 12px; margin-bottom: 8px">{{.Query}}</div>
+<ol>
+{{range .Entries}}<li style="margin-bottom: 8px"><a href="{{.URL}}">{{.Title}}</a>{{if .Authors}}<br><span style="font-size: 13px">{{.Authors}}</span>{{end}}{{if .Source}}<br><span style="font-size: 13px; color: #555"><i>{{.Source}}</i></span>{{end}}</li>
+{{end}}</ol>
+{{if .Truncated}}<p style="font-size: 13px; color: #a60">More new results than the alert's limit; raise --limit to see them all.</p>
+{{end}}{{end}}<p style="font-size: 11px; color: #999">Sent by pubmed-cli alert run.</p>
+</body></html>
+`))
+
+// AlertDigest builds the email reporting the new articles in results;
+// alerts with nothing new are left out.
+func AlertDigest(results []alert.Result, now time.Time) (subject, text, html string, err error) {
+	var alerts []digestAlert
+	total := 0
+	for _, r := range results {
+		if len(r.New) == 0 {
+			continue
+		}
+		byPMID := make(map[string]eutils.Article, len(r.Articles))
+		for _, a := range r.Articles {
+			byPMID[a.PMID] = a
+		}
+		da := digestAlert{Name: r.Name, Query: r.Query, Truncated: r.Truncated}
+		for _, pmid := range r.New {
+			da.Entries = append(da.Entries, newEntry(pmid, byPMID[pmid]))
+		}
+		alerts = append(alerts, da)
+		total += len(r.New)
+	}
+
+	noun := "results"
+	if total == 1 {
+		noun = "result"
+	}
+	heading := fmt.Sprintf("PubMed alerts: %d new %s", total, noun)
+	subject = heading + " (" + now.Format("2006-01-02") + ")"
+
+	var t strings.Builder
+	t.WriteString(heading + "\n")
+	for _, a := range alerts {
+		fmt.Fprintf(&t, "\n%s: %d new\n%s\n", a.Name, len(a.Entries), a.Query)
+		for i, e := range a.Entries {
+			fmt.Fprintf(&t, "\n%d. %s\n", i+1, e.Title)
+			if e.Authors != "" {
+				fmt.Fprintf(&t, "   %s\n", e.Authors)
+			}
+			if e.Source != "" {
+				fmt.Fprintf(&t, "   %s\n", e.Source)
+			}
+			fmt.Fprintf(&t, "   %s\n", e.URL)
+		}
+		if a.Truncated {
+			t.WriteString("\nMore new results than the alert's limit; raise --limit to see them all.\n")
+		}
+	}
+
+	var h bytes.Buffer
+	if err := digestHTML.Execute(&h, struct {
+		Heading string
+		Alerts  []digestAlert
+	}{heading, alerts}); err != nil {
+		return "", "", "", fmt.Errorf("rendering digest: %w", err)
+	}
+	return subject, t.String(), h.String(), nil
+}
+
+func newEntry(pmid string, a eutils.Article) digestEntry {
+	e := digestEntry{PMID: pmid, Title: a.Title, URL: "https://pubmed.ncbi.nlm.nih.gov/" + pmid + "/"}
+	if e.Title == "" {
+		e.Title = "PMID " + pmid
+	}
+	names := make([]string, 0, 3)
+	for i, au := range a.Authors {
+		if i == 3 {
+			names = append(names, "et al.")
+			break
+		}
+		names = append(names, au.FullName())
+	}
+	e.Authors = strings.Join(names, ", ")
+	e.Source = strings.TrimSpace(a.Journal + " " + a.Year)
+	if a.DOI != "" {
+		e.Source = strings.TrimSpace(e.Source + ". doi:" + a.DOI)
+	}
+	return e
+}
@@ -0,0 +1,49 @@
+package email
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestAlertDigest(t *testing.T) {
+	results := []alert.Result{
+		{Name: "quiet", Query: "q", New: []string{}},
+		{Name: "fxs-trials", Query: "fragile x <syndrome>", New: []string{"39000001", "39000002"}, Truncated: true,
+			Articles: []eutils.Article{{
+				PMID: "39000001", Title: "Metformin & behavior", Journal: "J Neurodev Disord", Year: "2026", DOI: "10.1000/x",
+				Authors: []eutils.Author{{LastName: "Smith", ForeName: "Jane"}, {LastName: "Doe", ForeName: "Al"}, {LastName: "Roe", ForeName: "Bo"}, {LastName: "Poe", ForeName: "Ed"}},
+			}}},
+	}
+	subject, text, html, err := AlertDigest(results, time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subject != "PubMed alerts: 2 new results (2026-03-02)" {
+		t.Errorf("subject = %q", subject)
+	}
+	for _, want := range []string{
+		"fxs-trials: 2 new\nfragile x <syndrome>\n",
+		"1. Metformin & behavior\n   Jane Smith, Al Doe, Bo Roe, et al.\n   J Neurodev Disord 2026. doi:10.1000/x\n   https://pubmed.ncbi.nlm.nih.gov/39000001/\n",
+		"2. PMID 39000002\n",
+		"raise --limit",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "quiet") || strings.Contains(html, "quiet") {
+		t.Error("alerts with nothing new should be left out")
+	}
+	for _, want := range []string{
+		`<a href="https://pubmed.ncbi.nlm.nih.gov/39000001/">Metformin &amp; behavior</a>`,
+		"fragile x &lt;syndrome&gt;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML missing %q:\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package email sends saved-search alert digests as HTML email over SMTP,
+// so a cron job can stand in for MyNCBI alerts.
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables holding the SMTP settings.
+const (
+	EnvHost     = "PUBMED_CLI_SMTP_HOST"
+	EnvPort     = "PUBMED_CLI_SMTP_PORT"
+	EnvUsername = "PUBMED_CLI_SMTP_USER"
+	EnvPassword = "PUBMED_CLI_SMTP_PASSWORD"
+	EnvFrom     = "PUBMED_CLI_SMTP_FROM"
+)
+
+// DefaultPort is the SMTP submission port, which upgrades to TLS with
+// STARTTLS. Port 465 is spoken over TLS from the start.
+const DefaultPort = "587"
+
+// Config is how to reach the SMTP server.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	// From is the sender address; it defaults to Username.
+	From string
+}
+
+// ConfigFromEnv reads the SMTP settings from the environment.
+func ConfigFromEnv() (Config, error) {
+	c := Config{
+		Host:     os.Getenv(EnvHost),
+		Port:     os.Getenv(EnvPort),
+		Username: os.Getenv(EnvUsername),
+		Password: os.Getenv(EnvPassword),
+		From:     os.Getenv(EnvFrom),
+	}
+	if c.Port == "" {
+		c.Port = DefaultPort
+	}
+	if c.From == "" {
+		c.From = c.Username
+	}
+	if c.Host == "" {
+		return c, fmt.Errorf("email needs an SMTP server: set %s (and %s, %s, %s as needed)", EnvHost, EnvUsername, EnvPassword, EnvFrom)
+	}
+	if c.From == "" {
+		return c, fmt.Errorf("email needs a sender address: set %s or %s", EnvFrom, EnvUsername)
+	}
+	return c, nil
+}
+
+// Message is an email with plain-text and HTML versions of its body.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+	Date    time.Time
+}
+
+// Bytes encodes m as a multipart/alternative MIME message.
+func (m Message) Bytes() []byte {
+	var b bytes.Buffer
+	boundary := newBoundary()
+	header := func(k, v string) { fmt.Fprintf(&b, "%s: %s\r\n", k, v) }
+	header("From", m.From)
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", m.Date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+	b.WriteString("\r\n")
+	for _, part := range []struct{ typ, body string }{{"text/plain", m.Text}, {"text/html", m.HTML}} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		header("Content-Type", part.typ+"; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		qp := quotedprintable.NewWriter(&b)
+		qp.Write([]byte(part.body))
+		qp.Close()
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes()
+}
+
+func newBoundary() string {
+	var buf [12]byte
+	rand.Read(buf[:])
+	return "pubmed-cli-" + hex.EncodeToString(buf[:])
+}
+
+// Send delivers m through the server in c, authenticating when c has a
+// username.
+func Send(c Config, m Message) error {
+	addr := net.JoinHostPort(c.Host, c.Port)
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+	if c.Port != "465" {
+		if err := smtp.SendMail(addr, auth, c.From, m.To, m.Bytes()); err != nil {
+			return fmt.Errorf("sending email: %w", err)
+		}
+		return nil
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: c.Host})
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	defer client.Close()
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(c.From); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	for _, to := range m.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("sending email to %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	if _, err := w.Write(m.Bytes()); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	return client.Quit()
+}
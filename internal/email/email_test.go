@@ -0,0 +1,73 @@
+package email
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvHost, "")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("expected an error without an SMTP host")
+	}
+	t.Setenv(EnvHost, "smtp.example.org")
+	t.Setenv(EnvUsername, "me@example.org")
+	t.Setenv(EnvPort, "")
+	t.Setenv(EnvFrom, "")
+	c, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Port != DefaultPort || c.From != "me@example.org" {
+		t.Errorf("unexpected config: %+v", c)
+	}
+}
+
+func TestMessageBytes(t *testing.T) {
+	m := Message{
+		From:    "alerts@example.org",
+		To:      []string{"a@example.org", "b@example.org"},
+		Subject: "PubMed alerts: 2 new results — fragile X",
+		Text:    "plain body",
+		HTML:    "<p>html body</p>",
+		Date:    time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC),
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(m.Bytes()))
+	if err != nil {
+		t.Fatalf("parsing message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != m.Subject {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+	if msg.Header.Get("To") != "a@example.org, b@example.org" {
+		t.Errorf("To = %q", msg.Header.Get("To"))
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Content-Type: %v", err)
+	}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading part: %v", err)
+		}
+		body, _ := io.ReadAll(p)
+		bodies = append(bodies, p.Header.Get("Content-Type")+"|"+strings.TrimSpace(string(body)))
+	}
+	want := []string{"text/plain; charset=utf-8|plain body", "text/html; charset=utf-8|<p>html body</p>"}
+	if strings.Join(bodies, "\n") != strings.Join(want, "\n") {
+		t.Errorf("parts = %q, want %q", bodies, want)
+	}
+}
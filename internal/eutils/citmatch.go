@@ -0,0 +1,66 @@
+package eutils
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Citation is a reference for ECitMatch, which finds the PMID of an
+// article from its journal, year, volume, first page, and first author.
+// Fields left empty are not matched on.
+type Citation struct {
+	Journal   string // journal title or NLM abbreviation, such as "N Engl J Med"
+	Year      string
+	Volume    string
+	FirstPage string
+	Author    string // first author, as "smith j" or a last name
+	Key       string // returned with the match to tell citations apart
+}
+
+// MatchCitations looks up citations with ECitMatch and returns the PMID
+// found for each, keyed by Key. Citations ECitMatch cannot match, or
+// matches ambiguously, are left out.
+func (c *Client) MatchCitations(ctx context.Context, citations []Citation) (map[string]string, error) {
+	if len(citations) == 0 {
+		return map[string]string{}, nil
+	}
+	clean := func(s string) string {
+		return strings.Join(strings.Fields(strings.NewReplacer("|", " ", "\r", " ").Replace(s)), " ")
+	}
+	lines := make([]string, len(citations))
+	for i, cit := range citations {
+		lines[i] = strings.Join([]string{
+			clean(cit.Journal), clean(cit.Year), clean(cit.Volume),
+			clean(cit.FirstPage), clean(cit.Author), clean(cit.Key),
+		}, "|") + "|"
+	}
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("retmode", "xml")
+	params.Set("bdata", strings.Join(lines, "\r"))
+
+	body, err := c.DoGet(ctx, "ecitmatch.cgi", params)
+	if err != nil {
+		return nil, fmt.Errorf("ecitmatch request failed: %w", err)
+	}
+	return parseCitationMatches(body), nil
+}
+
+// parseCitationMatches reads ECitMatch's reply, one line per citation
+// with the PMID, or NOT_FOUND or AMBIGUOUS, after the echoed key.
+func parseCitationMatches(body []byte) map[string]string {
+	matches := make(map[string]string)
+	for _, line := range strings.FieldsFunc(string(body), func(r rune) bool { return r == '\n' || r == '\r' }) {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) < 7 {
+			continue
+		}
+		key, pmid := fields[5], strings.TrimSpace(fields[6])
+		if pmid != "" && strings.Trim(pmid, "0123456789") == "" {
+			matches[key] = pmid
+		}
+	}
+	return matches
+}
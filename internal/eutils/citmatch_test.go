@@ -0,0 +1,39 @@
+package eutils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatchCitations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/ecitmatch.cgi" || q.Get("db") != "pubmed" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		lines := strings.Split(q.Get("bdata"), "\r")
+		if len(lines) != 3 || lines[0] != "proc natl acad sci u s a|1991|88|3248|mann bj|1|" {
+			t.Errorf("unexpected bdata %q", q.Get("bdata"))
+		}
+		w.Write([]byte("proc natl acad sci u s a|1991|88|3248|mann bj|1|2014248\n" +
+			"science|1987|235|182|palmenberg ac|2|NOT_FOUND;INVALID_JOURNAL\n" +
+			"lancet|2019|1|1|smith|3|AMBIGUOUS (12 citations)\n"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	matches, err := c.MatchCitations(context.Background(), []Citation{
+		{Journal: "proc natl acad sci u s a", Year: "1991", Volume: "88", FirstPage: "3248", Author: "mann bj", Key: "1"},
+		{Journal: "science", Year: "1987", Volume: "235", FirstPage: "182", Author: "palmenberg ac", Key: "2"},
+		{Journal: "lancet|", Year: "2019", Volume: "1", FirstPage: "1", Author: "smith", Key: "3"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 || matches["1"] != "2014248" {
+		t.Errorf("unexpected matches: %v", matches)
+	}
+}
@@ -18,6 +18,9 @@ const (
 // and response size guards.
 type Client struct {
 	*ncbi.BaseClient
+	// Local holds articles, such as the library's, that Fetch returns
+	// without a request when the client is offline.
+	Local map[string]Article
 }
 
 // Option configures a Client (alias for ncbi.Option).
@@ -2,6 +2,7 @@ package eutils
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sort"
@@ -130,7 +131,7 @@ func TestClient_ConcurrentRateLimitNoKey(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, _ = c.Search(context.Background(), "test", nil)
+			_, _ = c.Search(context.Background(), fmt.Sprintf("test %d", i), nil)
 		}()
 	}
 	wg.Wait()
@@ -185,7 +186,7 @@ func TestClient_ConcurrentRateLimitWithKey(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, _ = c.Search(context.Background(), "test", nil)
+			_, _ = c.Search(context.Background(), fmt.Sprintf("test %d", i), nil)
 		}()
 	}
 	wg.Wait()
@@ -0,0 +1,92 @@
+package eutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// monthNumbers maps PubMed's month abbreviations to numbers. Seasons are
+// left out: "Winter 2020" carries only its year.
+var monthNumbers = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// isoDate joins date parts into as much of YYYY-MM-DD as they give:
+// "2024", "2024-03", or "2024-03-15". month may be a number or an English
+// abbreviation; unparseable months and days are dropped.
+func isoDate(year, month, day string) string {
+	year = strings.TrimSpace(year)
+	if len(year) != 4 {
+		return ""
+	}
+	m := parseMonth(month)
+	if m == 0 {
+		return year
+	}
+	d, err := strconv.Atoi(strings.TrimSpace(day))
+	if err != nil || d < 1 || d > 31 {
+		return fmt.Sprintf("%s-%02d", year, m)
+	}
+	return fmt.Sprintf("%s-%02d-%02d", year, m, d)
+}
+
+func parseMonth(s string) int {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		if n >= 1 && n <= 12 {
+			return n
+		}
+		return 0
+	}
+	if len(s) < 3 {
+		return 0
+	}
+	return monthNumbers[strings.ToLower(s[:3])]
+}
+
+// pubISODate converts a PubDate to an ISO date, reading free-text
+// MedlineDates such as "2020 Jan-Feb" (2020-01) or "1998 Dec-1999 Jan"
+// (1998-12) from their first year and month.
+func pubISODate(pd xmlPubDate) string {
+	if pd.Year != "" {
+		return isoDate(pd.Year, pd.Month, pd.Day)
+	}
+	return textISODate(pd.MedlineDate)
+}
+
+// textISODate reads a date written as "2024 Mar 15", "2020 Jan-Feb",
+// "Winter 2020", or "2019-2020", as in MedlineDate and ESummary pubdate.
+func textISODate(s string) string {
+	year := yearRe.FindString(s)
+	if year == "" {
+		return ""
+	}
+	rest := strings.FieldsFunc(s[strings.Index(s, year)+4:], func(r rune) bool {
+		return r == ' ' || r == '-' || r == '/'
+	})
+	var month, day string
+	if len(rest) > 0 && parseMonth(rest[0]) != 0 && !isYear(rest[0]) {
+		month = rest[0]
+		if len(rest) > 1 && len(rest[1]) <= 2 {
+			day = rest[1]
+		}
+	}
+	return isoDate(year, month, day)
+}
+
+func isYear(s string) bool {
+	return len(s) == 4 && yearRe.MatchString(s)
+}
+
+// electronicDate returns the ISO date of the record's electronic
+// publication, from ArticleDate DateType="Electronic".
+func electronicDate(dates []xmlArticleDate) string {
+	for _, d := range dates {
+		if d.DateType == "Electronic" {
+			return isoDate(d.Year, d.Month, d.Day)
+		}
+	}
+	return ""
+}
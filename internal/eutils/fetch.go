@@ -1,12 +1,15 @@
 package eutils
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
 	"fmt"
 	"html"
+	"io"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -18,10 +21,8 @@ var yearRe = regexp.MustCompile(`\d{4}`)
 
 // XML structures for parsing PubMed EFetch responses.
 
-type pubmedArticleSet struct {
-	XMLName  xml.Name        `xml:"PubmedArticleSet"`
-	Articles []pubmedArticle `xml:"PubmedArticle"`
-}
+// A PubmedArticleSet mixes PubmedArticle and PubmedBookArticle elements;
+// parseArticles decodes each in document order.
 
 type pubmedArticle struct {
 	Citation   medlineCitation `xml:"MedlineCitation"`
@@ -29,9 +30,11 @@ type pubmedArticle struct {
 }
 
 type medlineCitation struct {
-	PMID            xmlPMID            `xml:"PMID"`
-	Article         xmlArticle         `xml:"Article"`
-	MeshHeadingList xmlMeshHeadingList `xml:"MeshHeadingList"`
+	PMID                xmlPMID                `xml:"PMID"`
+	Article             xmlArticle             `xml:"Article"`
+	MeshHeadingList     xmlMeshHeadingList     `xml:"MeshHeadingList"`
+	CommentsCorrections []xmlCommentCorrection `xml:"CommentsCorrectionsList>CommentsCorrections"`
+	Keywords            []xmlInnerContent      `xml:"KeywordList>Keyword"`
 }
 
 type xmlPMID struct {
@@ -46,6 +49,61 @@ type xmlArticle struct {
 	Language            []string               `xml:"Language"`
 	PublicationTypeList xmlPublicationTypeList `xml:"PublicationTypeList"`
 	Pagination          xmlPagination          `xml:"Pagination"`
+	ELocationIDs        []xmlELocationID       `xml:"ELocationID"`
+	ArticleDates        []xmlArticleDate       `xml:"ArticleDate"`
+	Grants              []xmlGrant             `xml:"GrantList>Grant"`
+}
+
+// xmlArticleDate is a date the publisher gave the article, such as its
+// electronic publication.
+type xmlArticleDate struct {
+	DateType string `xml:"DateType,attr"`
+	Year     string `xml:"Year"`
+	Month    string `xml:"Month"`
+	Day      string `xml:"Day"`
+}
+
+// xmlELocationID is an electronic location, such as a DOI, given in the
+// citation itself; some records have no DOI in their ArticleIdList.
+type xmlELocationID struct {
+	Type  string `xml:"EIdType,attr"`
+	Value string `xml:",chardata"`
+}
+
+// pubmedBookArticle is an NCBI Bookshelf record: a whole book, or a
+// chapter (with its own title and authors) of one.
+type pubmedBookArticle struct {
+	Document xmlBookDocument `xml:"BookDocument"`
+}
+
+type xmlBookDocument struct {
+	PMID                xmlPMID                `xml:"PMID"`
+	ArticleIDList       xmlArticleIDList       `xml:"ArticleIdList"`
+	Book                xmlBook                `xml:"Book"`
+	ArticleTitle        xmlInnerContent        `xml:"ArticleTitle"`
+	Language            []string               `xml:"Language"`
+	AuthorList          xmlAuthorList          `xml:"AuthorList"`
+	PublicationTypes    []xmlPublicationType   `xml:"PublicationType"`
+	Abstract            xmlAbstract            `xml:"Abstract"`
+	Keywords            []xmlInnerContent      `xml:"KeywordList>Keyword"`
+	ELocationIDs        []xmlELocationID       `xml:"ELocationID"`
+	Pagination          xmlPagination          `xml:"Pagination"`
+	CommentsCorrections []xmlCommentCorrection `xml:"CommentsCorrectionsList>CommentsCorrections"`
+}
+
+type xmlBook struct {
+	Publisher       xmlPublisher     `xml:"Publisher"`
+	BookTitle       xmlInnerContent  `xml:"BookTitle"`
+	PubDate         xmlPubDate       `xml:"PubDate"`
+	AuthorList      xmlAuthorList    `xml:"AuthorList"`
+	Volume          string           `xml:"Volume"`
+	CollectionTitle xmlInnerContent  `xml:"CollectionTitle"`
+	ELocationIDs    []xmlELocationID `xml:"ELocationID"`
+}
+
+type xmlPublisher struct {
+	Name     string `xml:"PublisherName"`
+	Location string `xml:"PublisherLocation"`
 }
 
 type xmlJournal struct {
@@ -84,6 +142,7 @@ type xmlAbstractText struct {
 
 type xmlAuthorList struct {
 	Complete string      `xml:"CompleteYN,attr"`
+	Type     string      `xml:"Type,attr"`
 	Authors  []xmlAuthor `xml:"Author"`
 }
 
@@ -134,8 +193,22 @@ type xmlQualifierName struct {
 	Name       string `xml:",chardata"`
 }
 
+type xmlGrant struct {
+	GrantID string `xml:"GrantID"`
+	Acronym string `xml:"Acronym"`
+	Agency  string `xml:"Agency"`
+	Country string `xml:"Country"`
+}
+
+type xmlCommentCorrection struct {
+	RefType   string  `xml:"RefType,attr"`
+	RefSource string  `xml:"RefSource"`
+	PMID      xmlPMID `xml:"PMID"`
+}
+
 type pubmedData struct {
-	ArticleIDList xmlArticleIDList `xml:"ArticleIdList"`
+	PublicationStatus string           `xml:"PublicationStatus"`
+	ArticleIDList     xmlArticleIDList `xml:"ArticleIdList"`
 }
 
 type xmlArticleIDList struct {
@@ -152,6 +225,11 @@ func (c *Client) Fetch(ctx context.Context, pmids []string) ([]Article, error) {
 	if len(pmids) == 0 {
 		return nil, fmt.Errorf("at least one PMID is required")
 	}
+	if c.Offline && c.Local != nil {
+		if articles, ok := c.localArticles(pmids); ok {
+			return articles, nil
+		}
+	}
 
 	params := url.Values{}
 	params.Set("db", "pubmed")
@@ -167,19 +245,198 @@ func (c *Client) Fetch(ctx context.Context, pmids []string) ([]Article, error) {
 	return parseArticles(body)
 }
 
-// parseArticles parses PubMed XML into Article structs.
-func parseArticles(data []byte) ([]Article, error) {
-	var articleSet pubmedArticleSet
-	if err := xml.Unmarshal(data, &articleSet); err != nil {
-		return nil, fmt.Errorf("parsing PubMed XML: %w", err)
+// localArticles returns the articles for pmids from Local, if it holds
+// all of them.
+func (c *Client) localArticles(pmids []string) ([]Article, bool) {
+	articles := make([]Article, 0, len(pmids))
+	for _, id := range pmids {
+		a, ok := c.Local[id]
+		if !ok {
+			return nil, false
+		}
+		articles = append(articles, a)
+	}
+	return articles, true
+}
+
+// FetchHistory retrieves up to count articles starting at offset start from
+// a result set stored on the Entrez history server (SearchResult.WebEnv and
+// QueryKey), in the search's sort order. It pages through large sets without
+// sending every PMID back to NCBI.
+func (c *Client) FetchHistory(ctx context.Context, webEnv, queryKey string, start, count int) ([]Article, error) {
+	if webEnv == "" || queryKey == "" {
+		return nil, fmt.Errorf("history fetch requires a WebEnv and query key")
+	}
+
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("WebEnv", webEnv)
+	params.Set("query_key", queryKey)
+	params.Set("retstart", strconv.Itoa(start))
+	params.Set("retmax", strconv.Itoa(count))
+	params.Set("rettype", "xml")
+	params.Set("retmode", "xml")
+
+	body, err := c.DoGet(ctx, "efetch.fcgi", params)
+	if err != nil {
+		return nil, fmt.Errorf("fetch request failed: %w", err)
+	}
+
+	return parseArticles(body)
+}
+
+// FetchError records PMIDs a FetchAll could not retrieve.
+type FetchError struct {
+	PMIDs []string `json:"pmids"`
+	Err   string   `json:"error"`
+}
+
+// FetchErrors is the report FetchAll returns alongside the articles it did
+// retrieve when some PMIDs failed.
+type FetchErrors struct {
+	// Invalid holds IDs that are not PMIDs and were never sent.
+	Invalid []string `json:"invalid,omitempty"`
+	// Failed holds the chunks whose requests failed.
+	Failed []FetchError `json:"failed,omitempty"`
+}
+
+// PMIDs returns every ID the fetch did not retrieve.
+func (e *FetchErrors) PMIDs() []string {
+	ids := append([]string(nil), e.Invalid...)
+	for _, f := range e.Failed {
+		ids = append(ids, f.PMIDs...)
+	}
+	return ids
+}
+
+func (e *FetchErrors) Error() string {
+	var parts []string
+	if len(e.Invalid) > 0 {
+		parts = append(parts, fmt.Sprintf("%d invalid PMIDs (%s)", len(e.Invalid), strings.Join(e.Invalid, ", ")))
+	}
+	if n := len(e.PMIDs()) - len(e.Invalid); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d PMIDs not fetched (%s)", n, e.Failed[0].Err))
 	}
+	return strings.Join(parts, "; ")
+}
 
-	articles := make([]Article, 0, len(articleSet.Articles))
-	for _, pa := range articleSet.Articles {
-		article := convertArticle(pa)
-		articles = append(articles, article)
+// FetchAll retrieves pmids in chunks of size. Unlike Fetch it does not fail
+// as a whole: IDs that are not PMIDs are skipped, and a chunk whose request
+// fails is retried as two halves, so one bad response costs at most half a
+// chunk. It returns the articles it retrieved and, when anything was left
+// out, a *FetchErrors describing what. Only a canceled context ends it early.
+func (c *Client) FetchAll(ctx context.Context, pmids []string, size int) ([]Article, error) {
+	if size <= 0 {
+		size = len(pmids)
+	}
+	report := &FetchErrors{}
+	valid := make([]string, 0, len(pmids))
+	for _, id := range pmids {
+		if isPMID(id) {
+			valid = append(valid, id)
+		} else {
+			report.Invalid = append(report.Invalid, id)
+		}
+	}
+
+	var articles []Article
+	fetch := func(ids []string) error {
+		batch, err := c.Fetch(ctx, ids)
+		if err == nil {
+			articles = append(articles, batch...)
+		}
+		return err
+	}
+	for start := 0; start < len(valid); start += size {
+		chunk := valid[start:min(start+size, len(valid))]
+		err := fetch(chunk)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return articles, ctx.Err()
+		}
+		if len(chunk) == 1 {
+			report.Failed = append(report.Failed, FetchError{PMIDs: chunk, Err: err.Error()})
+			continue
+		}
+		half := len(chunk) / 2
+		for _, part := range [][]string{chunk[:half], chunk[half:]} {
+			if err := fetch(part); err != nil {
+				if ctx.Err() != nil {
+					return articles, ctx.Err()
+				}
+				report.Failed = append(report.Failed, FetchError{PMIDs: part, Err: err.Error()})
+			}
+		}
 	}
 
+	if len(report.Invalid) > 0 || len(report.Failed) > 0 {
+		return articles, report
+	}
+	return articles, nil
+}
+
+// isPMID reports whether id is a plain PubMed ID.
+func isPMID(id string) bool {
+	if id == "" || len(id) > 9 {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseArticles parses PubMed XML into Article structs, journal articles
+// and Bookshelf records alike, in the order NCBI returned them.
+func parseArticles(data []byte) ([]Article, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		articles []Article
+		sawSet   bool
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing PubMed XML: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "PubmedArticleSet":
+			sawSet = true
+		case "PubmedArticle":
+			var pa pubmedArticle
+			if err := dec.DecodeElement(&pa, &se); err != nil {
+				return nil, fmt.Errorf("parsing PubMed XML: %w", err)
+			}
+			articles = append(articles, convertArticle(pa))
+		case "PubmedBookArticle":
+			var pb pubmedBookArticle
+			if err := dec.DecodeElement(&pb, &se); err != nil {
+				return nil, fmt.Errorf("parsing PubMed XML: %w", err)
+			}
+			articles = append(articles, convertBookArticle(pb))
+		default:
+			if !sawSet {
+				return nil, fmt.Errorf("parsing PubMed XML: expected PubmedArticleSet, got <%s>", se.Name.Local)
+			}
+		}
+	}
+	if !sawSet {
+		return nil, fmt.Errorf("parsing PubMed XML: no PubmedArticleSet")
+	}
+	if articles == nil {
+		articles = []Article{}
+	}
 	return articles, nil
 }
 
@@ -189,6 +446,19 @@ func cleanInnerXML(s string) string {
 	return strings.TrimSpace(html.UnescapeString(stripped))
 }
 
+// trackedCommentTypes are the CommentsCorrections RefTypes kept on an
+// Article; comments, citations, and reprints are left out.
+var trackedCommentTypes = map[string]bool{
+	"RetractionIn":                true,
+	"RetractionOf":                true,
+	"ErratumIn":                   true,
+	"ErratumFor":                  true,
+	"ExpressionOfConcernIn":       true,
+	"ExpressionOfConcernFor":      true,
+	"CorrectedandRepublishedIn":   true,
+	"CorrectedandRepublishedFrom": true,
+}
+
 // extractYearFromMedlineDate extracts the first 4-digit year from a MedlineDate string.
 // Common formats: "2020 Jan-Feb", "2019-2020", "Winter 2020", "2020".
 func extractYearFromMedlineDate(md string) string {
@@ -217,36 +487,144 @@ func convertArticle(pa pubmedArticle) Article {
 	} else if pd.MedlineDate != "" {
 		a.Year = extractYearFromMedlineDate(pd.MedlineDate)
 	}
+	a.PubDate = pubISODate(pd)
+	a.EPubDate = electronicDate(xa.ArticleDates)
 
 	// Language
 	if len(xa.Language) > 0 {
 		a.Language = xa.Language[0]
 	}
 
-	// Abstract sections — use cleanInnerXML to handle nested tags
-	for _, at := range xa.Abstract.AbstractTexts {
-		text := cleanInnerXML(at.Inner)
+	setAbstract(&a, xa.Abstract)
+	a.Authors = convertAuthors(xa.AuthorList)
+
+	// Article IDs (DOI, PMCID), with the citation's e-location DOI as a
+	// fallback
+	for _, aid := range pa.PubmedData.ArticleIDList.ArticleIDs {
+		switch aid.IDType {
+		case "doi":
+			a.DOI = aid.Value
+		case "pmc":
+			a.PMCID = aid.Value
+		}
+	}
+	if a.DOI == "" {
+		a.DOI = eLocationDOI(xa.ELocationIDs)
+	}
+	a.PublicationStatus = strings.TrimSpace(pa.PubmedData.PublicationStatus)
+
+	// MeSH terms
+	for _, mh := range mc.MeshHeadingList.MeshHeadings {
+		term := MeSHTerm{
+			Descriptor:   mh.Descriptor.Name,
+			DescriptorUI: mh.Descriptor.UI,
+			MajorTopic:   mh.Descriptor.MajorTopic == "Y",
+		}
+		for _, q := range mh.Qualifiers {
+			term.Qualifiers = append(term.Qualifiers, q.Name)
+			if q.MajorTopic == "Y" {
+				term.MajorQualifier = true
+			}
+		}
+		a.MeSHTerms = append(a.MeSHTerms, term)
+	}
+
+	// Publication types
+	for _, pt := range xa.PublicationTypeList.Types {
+		a.PublicationTypes = append(a.PublicationTypes, pt.Name)
+	}
+
+	a.Keywords = convertKeywords(mc.Keywords)
+	a.Grants = convertGrants(mc.Article.Grants)
+	a.CommentsCorrections = convertCommentsCorrections(mc.CommentsCorrections)
+
+	return a
+}
+
+// convertBookArticle converts a Bookshelf record. A chapter keeps its own
+// title and authors and names its book in BookTitle; a whole book takes
+// the book's title and authors.
+func convertBookArticle(pb pubmedBookArticle) Article {
+	d := pb.Document
+	b := d.Book
+
+	a := Article{
+		PMID:            d.PMID.Value,
+		Title:           cleanInnerXML(d.ArticleTitle.Inner),
+		BookTitle:       cleanInnerXML(b.BookTitle.Inner),
+		Publisher:       b.Publisher.Name,
+		PublisherPlace:  b.Publisher.Location,
+		CollectionTitle: cleanInnerXML(b.CollectionTitle.Inner),
+		Volume:          b.Volume,
+		Pages:           d.Pagination.MedlinePgn,
+	}
+	if a.Title == "" {
+		a.Title, a.BookTitle = a.BookTitle, ""
+	}
+	if a.CollectionTitle == a.BookTitle || a.CollectionTitle == a.Title {
+		a.CollectionTitle = ""
+	}
+
+	if b.PubDate.Year != "" {
+		a.Year = b.PubDate.Year
+		a.Month = b.PubDate.Month
+	} else if b.PubDate.MedlineDate != "" {
+		a.Year = extractYearFromMedlineDate(b.PubDate.MedlineDate)
+	}
+	a.PubDate = pubISODate(b.PubDate)
+	if len(d.Language) > 0 {
+		a.Language = d.Language[0]
+	}
+
+	setAbstract(&a, d.Abstract)
+	// Editors are listed with the book; a chapter's authors, or a whole
+	// book's, with the document.
+	a.Authors = convertAuthors(d.AuthorList)
+	if len(a.Authors) == 0 && b.AuthorList.Type != "editors" {
+		a.Authors = convertAuthors(b.AuthorList)
+	}
+
+	a.DOI = eLocationDOI(d.ELocationIDs)
+	if a.DOI == "" {
+		a.DOI = eLocationDOI(b.ELocationIDs)
+	}
+	for _, pt := range d.PublicationTypes {
+		a.PublicationTypes = append(a.PublicationTypes, pt.Name)
+	}
+	a.Keywords = convertKeywords(d.Keywords)
+	a.CommentsCorrections = convertCommentsCorrections(d.CommentsCorrections)
+
+	return a
+}
+
+// setAbstract fills a's abstract sections and full abstract text.
+func setAbstract(a *Article, abs xmlAbstract) {
+	// Use cleanInnerXML to handle nested tags
+	for _, at := range abs.AbstractTexts {
 		a.AbstractSections = append(a.AbstractSections, AbstractSection{
 			Label: at.Label,
-			Text:  text,
+			Text:  cleanInnerXML(at.Inner),
 		})
 	}
-
-	// Build full abstract text
-	if len(a.AbstractSections) > 0 {
-		var parts []string
-		for _, s := range a.AbstractSections {
-			if s.Label != "" {
-				parts = append(parts, s.Label+": "+s.Text)
-			} else {
-				parts = append(parts, s.Text)
-			}
+	if len(a.AbstractSections) == 0 {
+		return
+	}
+	var parts []string
+	for _, s := range a.AbstractSections {
+		if s.Label != "" {
+			parts = append(parts, s.Label+": "+s.Text)
+		} else {
+			parts = append(parts, s.Text)
 		}
-		a.Abstract = strings.Join(parts, "\n\n")
 	}
+	a.Abstract = strings.Join(parts, "\n\n")
+}
 
-	// Authors — support both individual and collective names
-	for _, au := range xa.AuthorList.Authors {
+// convertAuthors supports both individual and collective names, skipping
+// authors PubMed marks invalid.
+func convertAuthors(list xmlAuthorList) []Author {
+	var authors []Author
+	for _, au := range list.Authors {
 		if au.ValidYN == "N" {
 			continue
 		}
@@ -262,36 +640,63 @@ func convertArticle(pa pubmedArticle) Article {
 			author.Affiliation = au.AffiliationInfo[0].Affiliation
 		}
 		author.DisplayName = author.FullName()
-		a.Authors = append(a.Authors, author)
+		authors = append(authors, author)
 	}
+	return authors
+}
 
-	// Article IDs (DOI, PMCID)
-	for _, aid := range pa.PubmedData.ArticleIDList.ArticleIDs {
-		switch aid.IDType {
-		case "doi":
-			a.DOI = aid.Value
-		case "pmc":
-			a.PMCID = aid.Value
+// convertKeywords returns the author-supplied keywords.
+func convertKeywords(kws []xmlInnerContent) []string {
+	var out []string
+	for _, kw := range kws {
+		if text := cleanInnerXML(kw.Inner); text != "" {
+			out = append(out, text)
 		}
 	}
+	return out
+}
 
-	// MeSH terms
-	for _, mh := range mc.MeshHeadingList.MeshHeadings {
-		term := MeSHTerm{
-			Descriptor:   mh.Descriptor.Name,
-			DescriptorUI: mh.Descriptor.UI,
-			MajorTopic:   mh.Descriptor.MajorTopic == "Y",
-		}
-		for _, q := range mh.Qualifiers {
-			term.Qualifiers = append(term.Qualifiers, q.Name)
+// convertGrants returns the grants with an agency, trimmed.
+func convertGrants(grants []xmlGrant) []Grant {
+	var out []Grant
+	for _, g := range grants {
+		agency := strings.TrimSpace(g.Agency)
+		if agency == "" {
+			continue
 		}
-		a.MeSHTerms = append(a.MeSHTerms, term)
+		out = append(out, Grant{
+			ID:      strings.TrimSpace(g.GrantID),
+			Acronym: strings.TrimSpace(g.Acronym),
+			Agency:  agency,
+			Country: strings.TrimSpace(g.Country),
+		})
 	}
+	return out
+}
 
-	// Publication types
-	for _, pt := range xa.PublicationTypeList.Types {
-		a.PublicationTypes = append(a.PublicationTypes, pt.Name)
+// convertCommentsCorrections keeps the retractions, errata, and
+// expressions of concern linked to a record.
+func convertCommentsCorrections(ccs []xmlCommentCorrection) []CommentCorrection {
+	var out []CommentCorrection
+	for _, cc := range ccs {
+		if !trackedCommentTypes[cc.RefType] {
+			continue
+		}
+		out = append(out, CommentCorrection{
+			Type:   cc.RefType,
+			Source: strings.TrimSpace(cc.RefSource),
+			PMID:   strings.TrimSpace(cc.PMID.Value),
+		})
 	}
+	return out
+}
 
-	return a
+// eLocationDOI returns the DOI among ids, if any.
+func eLocationDOI(ids []xmlELocationID) string {
+	for _, id := range ids {
+		if id.Type == "doi" {
+			return strings.TrimSpace(id.Value)
+		}
+	}
+	return ""
 }
@@ -2,9 +2,16 @@ package eutils
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
 )
 
 func TestFetch_StructuredAbstract(t *testing.T) {
@@ -91,6 +98,9 @@ func TestFetch_StructuredAbstract(t *testing.T) {
 	if a.Year != "2024" {
 		t.Errorf("expected year '2024', got %q", a.Year)
 	}
+	if a.PubDate != "2024-03" || a.EPubDate != "2024-01-15" {
+		t.Errorf("expected dates 2024-03 and 2024-01-15, got %q and %q", a.PubDate, a.EPubDate)
+	}
 
 	// DOI
 	if a.DOI != "10.1038/s41380-024-02456-7" {
@@ -235,6 +245,118 @@ func TestFetch_MedlineDate(t *testing.T) {
 	if a.Year != "2020" {
 		t.Errorf("expected year '2020' from MedlineDate, got %q", a.Year)
 	}
+	if a.PubDate != "2020-01" {
+		t.Errorf("expected pub date 2020-01 from MedlineDate, got %q", a.PubDate)
+	}
+}
+
+func TestFetch_CommentsCorrections(t *testing.T) {
+	fixture := loadTestdata(t, "efetch_retracted.xml")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	articles, err := c.Fetch(context.Background(), []string{"31000001", "31000005"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("expected 2 articles, got %d", len(articles))
+	}
+
+	// CommentIn links are not kept.
+	want := []CommentCorrection{
+		{Type: "ErratumIn", Source: "J Neurodev Disord. 2020;13:1", PMID: "31000003"},
+		{Type: "RetractionIn", Source: "J Neurodev Disord. 2021;14:5", PMID: "31000004"},
+	}
+	if got := articles[0].CommentsCorrections; !reflect.DeepEqual(got, want) {
+		t.Errorf("CommentsCorrections = %+v, want %+v", got, want)
+	}
+	if got := articles[1].CommentsCorrections; len(got) != 1 || got[0].Type != "ExpressionOfConcernIn" || got[0].PMID != "" {
+		t.Errorf("unexpected expression of concern: %+v", got)
+	}
+}
+
+func TestFetch_Keywords(t *testing.T) {
+	fixture := loadTestdata(t, "efetch_nested_tags.xml")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	articles, err := c.Fetch(context.Background(), []string{"20000003"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("expected 1 article, got %d", len(articles))
+	}
+
+	// Markup is stripped and blank keywords dropped.
+	want := []string{"FMR1", "GABA receptors"}
+	if got := articles[0].Keywords; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keywords = %q, want %q", got, want)
+	}
+}
+
+func TestParseArticles_Grants(t *testing.T) {
+	data := `<PubmedArticleSet><PubmedArticle><MedlineCitation><PMID>1</PMID><Article>
+<ArticleTitle>Funded</ArticleTitle>
+<GrantList CompleteYN="Y">
+<Grant><GrantID>R01 MH123456</GrantID><Acronym>MH</Acronym><Agency>NIMH NIH HHS</Agency><Country>United States</Country></Grant>
+<Grant><Agency> Wellcome Trust </Agency><Country>United Kingdom</Country></Grant>
+<Grant><GrantID>orphan</GrantID></Grant>
+</GrantList>
+</Article></MedlineCitation></PubmedArticle></PubmedArticleSet>`
+	articles, err := parseArticles([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Grant{
+		{ID: "R01 MH123456", Acronym: "MH", Agency: "NIMH NIH HHS", Country: "United States"},
+		{Agency: "Wellcome Trust", Country: "United Kingdom"},
+	}
+	if got := articles[0].Grants; !reflect.DeepEqual(got, want) {
+		t.Errorf("Grants = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseArticles_PublicationStatus(t *testing.T) {
+	data := `<PubmedArticleSet><PubmedArticle><MedlineCitation><PMID>1</PMID><Article>
+<ArticleTitle>Early</ArticleTitle></Article></MedlineCitation>
+<PubmedData><PublicationStatus>aheadofprint</PublicationStatus></PubmedData></PubmedArticle></PubmedArticleSet>`
+	articles, err := parseArticles([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := articles[0].PublicationStatus; got != "aheadofprint" {
+		t.Errorf("PublicationStatus = %q, want aheadofprint", got)
+	}
+}
+
+func TestParseArticles_MajorQualifier(t *testing.T) {
+	data := `<PubmedArticleSet><PubmedArticle><MedlineCitation><PMID>1</PMID><Article>
+<ArticleTitle>T</ArticleTitle></Article><MeshHeadingList>
+<MeshHeading><DescriptorName UI="D005600" MajorTopicYN="N">Fragile X Syndrome</DescriptorName>
+<QualifierName UI="Q000188" MajorTopicYN="Y">drug therapy</QualifierName></MeshHeading>
+<MeshHeading><DescriptorName UI="D006801" MajorTopicYN="N">Humans</DescriptorName></MeshHeading>
+</MeshHeadingList></MedlineCitation></PubmedArticle></PubmedArticleSet>`
+	articles, err := parseArticles([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fxs, humans := articles[0].MeSHTerms[0], articles[0].MeSHTerms[1]
+	if fxs.MajorTopic || !fxs.MajorQualifier || !fxs.Major() {
+		t.Errorf("starred qualifier: %+v, want MajorTopic false and MajorQualifier true", fxs)
+	}
+	if humans.Major() {
+		t.Errorf("unstarred heading reported as major: %+v", humans)
+	}
 }
 
 func TestFetch_CollectiveAuthor(t *testing.T) {
@@ -373,3 +495,173 @@ func TestFetch_ServerError(t *testing.T) {
 		t.Error("expected error for server error, got nil")
 	}
 }
+
+func TestFetchHistory(t *testing.T) {
+	fixture := loadTestdata(t, "efetch_response.xml")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("WebEnv"); got != "MCID_abc" {
+			t.Errorf("expected WebEnv=MCID_abc, got %q", got)
+		}
+		if got := q.Get("query_key"); got != "1" {
+			t.Errorf("expected query_key=1, got %q", got)
+		}
+		if got := q.Get("retstart"); got != "400" {
+			t.Errorf("expected retstart=400, got %q", got)
+		}
+		if got := q.Get("retmax"); got != "200" {
+			t.Errorf("expected retmax=200, got %q", got)
+		}
+		if q.Has("id") {
+			t.Error("history fetch should not send PMIDs")
+		}
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	articles, err := c.FetchHistory(context.Background(), "MCID_abc", "1", 400, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(articles) != 1 || articles[0].PMID != "38123456" {
+		t.Fatalf("unexpected articles: %+v", articles)
+	}
+
+	if _, err := c.FetchHistory(context.Background(), "", "1", 0, 10); err == nil {
+		t.Error("expected error without WebEnv")
+	}
+}
+
+func TestFetchAll_PartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := r.URL.Query().Get("id")
+		mu.Lock()
+		requests = append(requests, ids)
+		mu.Unlock()
+		// PMID 4 always breaks its request.
+		if strings.Contains(","+ids+",", ",4,") {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("<PubmedArticleSet>"))
+		for _, id := range strings.Split(ids, ",") {
+			fmt.Fprintf(w, "<PubmedArticle><MedlineCitation><PMID>%s</PMID></MedlineCitation></PubmedArticle>", id)
+		}
+		w.Write([]byte("</PubmedArticleSet>"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	articles, err := c.FetchAll(context.Background(), []string{"1", "2", "3", "4", "5", "6", "x7", "7"}, 4)
+
+	var got []string
+	for _, a := range articles {
+		got = append(got, a.PMID)
+	}
+	if strings.Join(got, ",") != "1,2,5,6,7" {
+		t.Errorf("articles = %v", got)
+	}
+	var fe *FetchErrors
+	if !errors.As(err, &fe) {
+		t.Fatalf("expected *FetchErrors, got %v", err)
+	}
+	if strings.Join(fe.Invalid, ",") != "x7" {
+		t.Errorf("Invalid = %v", fe.Invalid)
+	}
+	if len(fe.Failed) != 1 || strings.Join(fe.Failed[0].PMIDs, ",") != "3,4" {
+		t.Errorf("Failed = %+v", fe.Failed)
+	}
+	if strings.Join(fe.PMIDs(), ",") != "x7,3,4" {
+		t.Errorf("PMIDs() = %v", fe.PMIDs())
+	}
+	if !strings.Contains(err.Error(), "2 PMIDs not fetched") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	want := "1,2,3,4 1,2 3,4 5,6,7"
+	if strings.Join(requests, " ") != want {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+
+	if _, err := c.FetchAll(context.Background(), []string{"1", "2"}, 4); err != nil {
+		t.Errorf("expected no error when everything is fetched, got %v", err)
+	}
+}
+
+func TestFetch_BookRecords(t *testing.T) {
+	fixture := loadTestdata(t, "efetch_book.xml")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	articles, err := c.Fetch(context.Background(), []string{"20301558", "38000003", "21249951"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(articles) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(articles))
+	}
+
+	chapter, article, book := articles[0], articles[1], articles[2]
+	if chapter.PMID != "20301558" || chapter.Title != "FMR1 Disorders" || chapter.BookTitle != "GeneReviews®" {
+		t.Errorf("chapter = %+v", chapter)
+	}
+	if chapter.Publisher != "University of Washington, Seattle" || chapter.PublisherPlace != "Seattle (WA)" || chapter.CollectionTitle != "" {
+		t.Errorf("chapter publisher = %q, %q, collection %q", chapter.Publisher, chapter.PublisherPlace, chapter.CollectionTitle)
+	}
+	if len(chapter.Authors) != 2 || chapter.Authors[0].FullName() != "Jessica Ezzell Hunter" || chapter.Authors[0].Affiliation != "Emory University" {
+		t.Errorf("chapter authors should be the chapter's, not the editors: %+v", chapter.Authors)
+	}
+	if chapter.Year != "1993" || chapter.Language != "eng" || len(chapter.AbstractSections) != 2 || !strings.HasPrefix(chapter.Abstract, "CLINICAL CHARACTERISTICS: ") {
+		t.Errorf("chapter year %q, language %q, abstract %q", chapter.Year, chapter.Language, chapter.Abstract)
+	}
+	if !reflect.DeepEqual(chapter.PublicationTypes, []string{"Review"}) || !reflect.DeepEqual(chapter.Keywords, []string{"FMR1"}) {
+		t.Errorf("chapter types %v, keywords %v", chapter.PublicationTypes, chapter.Keywords)
+	}
+	if !chapter.IsBook() || article.IsBook() || !book.IsBook() {
+		t.Error("IsBook should hold for Bookshelf records only")
+	}
+
+	if article.DOI != "10.1371/journal.pone.0300001" {
+		t.Errorf("expected the e-location DOI, got %q", article.DOI)
+	}
+
+	if book.Title != "Preventing Medication Errors" || book.BookTitle != "" || book.Year != "2007" || book.DOI != "10.17226/11623" {
+		t.Errorf("book = %+v", book)
+	}
+	if len(book.Authors) != 1 || book.Authors[0].CollectiveName == "" {
+		t.Errorf("book authors = %+v", book.Authors)
+	}
+}
+
+func TestParseArticles_RejectsOtherDocuments(t *testing.T) {
+	for _, data := range []string{"", "<eSearchResult/>", "<PubmedArticleSet><PubmedArticle>"} {
+		if _, err := parseArticles([]byte(data)); err == nil {
+			t.Errorf("parseArticles(%q): expected an error", data)
+		}
+	}
+	articles, err := parseArticles([]byte("<PubmedArticleSet></PubmedArticleSet>"))
+	if err != nil || articles == nil || len(articles) != 0 {
+		t.Errorf("empty set = %v, %v", articles, err)
+	}
+}
+
+func TestFetch_OfflineLocal(t *testing.T) {
+	c := NewClient(WithBaseURL("http://127.0.0.1:1"), ncbi.WithOffline())
+	c.Local = map[string]Article{"1": {PMID: "1", Title: "One"}, "2": {PMID: "2", Title: "Two"}}
+
+	articles, err := c.Fetch(context.Background(), []string{"2", "1"})
+	if err != nil || len(articles) != 2 || articles[0].Title != "Two" {
+		t.Errorf("Fetch = %+v, %v", articles, err)
+	}
+	_, err = c.Fetch(context.Background(), []string{"1", "3"})
+	var oe *ncbi.OfflineError
+	if !errors.As(err, &oe) || !strings.Contains(oe.Request, "id=1,3") {
+		t.Errorf("expected an OfflineError for the missing PMID, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package eutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// SearchField is a PubMed search field as EInfo describes it: the short
+// name ESearch accepts in a field tag, such as TIAB, and its full name.
+type SearchField struct {
+	Name        string `json:"name"`
+	FullName    string `json:"fullname"`
+	Description string `json:"description"`
+}
+
+type einfoResponse struct {
+	Result struct {
+		DBInfo json.RawMessage `json:"dbinfo"`
+	} `json:"einforesult"`
+}
+
+type einfoDB struct {
+	FieldList []SearchField `json:"fieldlist"`
+}
+
+// SearchFields returns PubMed's search fields from EInfo.
+func (c *Client) SearchFields(ctx context.Context) ([]SearchField, error) {
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("retmode", "json")
+
+	body, err := c.DoGet(ctx, "einfo.fcgi", params)
+	if err != nil {
+		return nil, fmt.Errorf("einfo request failed: %w", err)
+	}
+	return parseSearchFields(body)
+}
+
+func parseSearchFields(body []byte) ([]SearchField, error) {
+	var resp einfoResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing einfo response: %w", err)
+	}
+	// dbinfo is a list with one entry for the database, or in some
+	// versions of the service the entry itself.
+	var dbs []einfoDB
+	if err := json.Unmarshal(resp.Result.DBInfo, &dbs); err != nil {
+		var db einfoDB
+		if err := json.Unmarshal(resp.Result.DBInfo, &db); err != nil {
+			return nil, fmt.Errorf("parsing einfo database info: %w", err)
+		}
+		dbs = []einfoDB{db}
+	}
+	var fields []SearchField
+	for _, db := range dbs {
+		fields = append(fields, db.FieldList...)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("einfo returned no search fields")
+	}
+	return fields, nil
+}
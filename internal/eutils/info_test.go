@@ -0,0 +1,31 @@
+package eutils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchFields(t *testing.T) {
+	for _, dbinfo := range []string{
+		`[{"dbname":"pubmed","fieldlist":[{"name":"TIAB","fullname":"Title/Abstract"},{"name":"MESH","fullname":"MeSH Terms"}]}]`,
+		`{"dbname":"pubmed","fieldlist":[{"name":"TIAB","fullname":"Title/Abstract"},{"name":"MESH","fullname":"MeSH Terms"}]}`,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/einfo.fcgi" || r.URL.Query().Get("db") != "pubmed" {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+			w.Write([]byte(`{"einforesult":{"dbinfo":` + dbinfo + `}}`))
+		}))
+		c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+		fields, err := c.SearchFields(context.Background())
+		srv.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(fields) != 2 || fields[0].Name != "TIAB" || fields[1].FullName != "MeSH Terms" {
+			t.Errorf("unexpected fields: %+v", fields)
+		}
+	}
+}
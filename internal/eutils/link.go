@@ -87,6 +87,47 @@ func (c *Client) References(ctx context.Context, pmid string) (*LinkResult, erro
 	return c.link(ctx, pmid, linkRefs, false)
 }
 
+// LinkBatchSize is the most PMIDs one batched ELink request names.
+const LinkBatchSize = 100
+
+// ReferenceLists returns the PubMed references of each of pmids that has
+// any, by PMID. ELink answers for each article separately, LinkBatchSize
+// at a time.
+func (c *Client) ReferenceLists(ctx context.Context, pmids []string) (map[string][]string, error) {
+	refs := make(map[string][]string, len(pmids))
+	for start := 0; start < len(pmids); start += LinkBatchSize {
+		params := url.Values{}
+		params.Set("dbfrom", "pubmed")
+		params.Set("db", "pubmed")
+		params["id"] = pmids[start:min(start+LinkBatchSize, len(pmids))]
+		params.Set("linkname", linkRefs)
+		params.Set("retmode", "json")
+
+		body, err := c.DoGet(ctx, "elink.fcgi", params)
+		if err != nil {
+			return nil, fmt.Errorf("link request failed: %w", err)
+		}
+		var resp elinkResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("parsing link response: %w", err)
+		}
+		for _, ls := range resp.LinkSets {
+			if len(ls.IDs) != 1 {
+				continue
+			}
+			for _, lsdb := range ls.LinkSetDBs {
+				if lsdb.LinkName != linkRefs {
+					continue
+				}
+				for _, link := range lsdb.Links {
+					refs[ls.IDs[0]] = append(refs[ls.IDs[0]], link.id)
+				}
+			}
+		}
+	}
+	return refs, nil
+}
+
 // Related returns similar articles for the given PMID with relevance scores.
 func (c *Client) Related(ctx context.Context, pmid string) (*LinkResult, error) {
 	return c.link(ctx, pmid, linkRelated, true)
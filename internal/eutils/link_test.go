@@ -197,3 +197,28 @@ func TestLink_ServerError(t *testing.T) {
 		t.Error("expected error for server error")
 	}
 }
+
+func TestReferenceLists(t *testing.T) {
+	fixture := loadTestdata(t, "elink_refs_batch.json")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q["id"]; len(got) != 2 || got[0] != "38000001" || got[1] != "38000002" {
+			t.Errorf("expected one id parameter per PMID, got %v", got)
+		}
+		if got := q.Get("linkname"); got != "pubmed_pubmed_refs" {
+			t.Errorf("expected linkname=pubmed_pubmed_refs, got %q", got)
+		}
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	refs, err := c.ReferenceLists(context.Background(), []string{"38000001", "38000002"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(refs) != 1 || len(refs["38000002"]) != 2 || refs["38000002"][0] != "38000001" {
+		t.Errorf("unexpected references: %v", refs)
+	}
+}
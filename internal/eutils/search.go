@@ -14,13 +14,23 @@ type esearchResponse struct {
 }
 
 type esearchResult struct {
-	Count            string   `json:"count"`
-	RetMax           string   `json:"retmax"`
-	RetStart         string   `json:"retstart"`
-	IDList           []string `json:"idlist"`
-	QueryTranslation string   `json:"querytranslation"`
-	WebEnv           string   `json:"webenv"`
-	QueryKey         string   `json:"querykey"`
+	Count            string        `json:"count"`
+	RetMax           string        `json:"retmax"`
+	RetStart         string        `json:"retstart"`
+	IDList           []string      `json:"idlist"`
+	TranslationSet   []Translation `json:"translationset"`
+	QueryTranslation string        `json:"querytranslation"`
+	WebEnv           string        `json:"webenv"`
+	QueryKey         string        `json:"querykey"`
+	ErrorList        struct {
+		PhrasesNotFound []string `json:"phrasesnotfound"`
+		FieldsNotFound  []string `json:"fieldsnotfound"`
+	} `json:"errorlist"`
+	WarningList struct {
+		PhrasesIgnored        []string `json:"phrasesignored"`
+		QuotedPhrasesNotFound []string `json:"quotedphrasesnotfound"`
+		OutputMessages        []string `json:"outputmessages"`
+	} `json:"warninglist"`
 }
 
 // Search performs an ESearch query against PubMed.
@@ -44,7 +54,7 @@ func (c *Client) Search(ctx context.Context, query string, opts *SearchOptions)
 			params.Set("sort", opts.Sort)
 		}
 		if opts.MinDate != "" && opts.MaxDate != "" {
-			params.Set("datetype", "pdat")
+			params.Set("datetype", dateType(opts))
 			params.Set("mindate", opts.MinDate)
 			params.Set("maxdate", opts.MaxDate)
 		}
@@ -74,7 +84,57 @@ func (c *Client) Search(ctx context.Context, query string, opts *SearchOptions)
 		Count:            count,
 		IDs:              resp.Result.IDList,
 		QueryTranslation: resp.Result.QueryTranslation,
+		Translations:     resp.Result.TranslationSet,
 		WebEnv:           resp.Result.WebEnv,
 		QueryKey:         resp.Result.QueryKey,
+		Notices: SearchNotices{
+			PhrasesNotFound:       resp.Result.ErrorList.PhrasesNotFound,
+			FieldsNotFound:        resp.Result.ErrorList.FieldsNotFound,
+			PhrasesIgnored:        resp.Result.WarningList.PhrasesIgnored,
+			QuotedPhrasesNotFound: resp.Result.WarningList.QuotedPhrasesNotFound,
+			Messages:              resp.Result.WarningList.OutputMessages,
+		},
 	}, nil
 }
+
+// Count returns how many records match query without retrieving any IDs.
+// Only the date range in opts applies.
+func (c *Client) Count(ctx context.Context, query string, opts *SearchOptions) (int, error) {
+	if query == "" {
+		return 0, fmt.Errorf("search query cannot be empty")
+	}
+
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("term", query)
+	params.Set("retmode", "json")
+	params.Set("rettype", "count")
+	if opts != nil && opts.MinDate != "" && opts.MaxDate != "" {
+		params.Set("datetype", dateType(opts))
+		params.Set("mindate", opts.MinDate)
+		params.Set("maxdate", opts.MaxDate)
+	}
+
+	body, err := c.DoGet(ctx, "esearch.fcgi", params)
+	if err != nil {
+		return 0, fmt.Errorf("count request failed: %w", err)
+	}
+
+	var resp esearchResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, fmt.Errorf("parsing count response: %w", err)
+	}
+	count, err := strconv.Atoi(resp.Result.Count)
+	if err != nil {
+		return 0, fmt.Errorf("parsing search result count %q: %w", resp.Result.Count, err)
+	}
+	return count, nil
+}
+
+// dateType returns the esearch datetype for opts, defaulting to publication date.
+func dateType(opts *SearchOptions) string {
+	if opts.DateType != "" {
+		return opts.DateType
+	}
+	return "pdat"
+}
@@ -58,6 +58,9 @@ func TestSearch_BasicQuery(t *testing.T) {
 	if result.QueryTranslation == "" {
 		t.Error("expected non-empty query translation")
 	}
+	if len(result.Translations) != 1 || result.Translations[0].From != "fragile x syndrome" {
+		t.Errorf("expected translation set from fixture, got %+v", result.Translations)
+	}
 	if result.WebEnv == "" {
 		t.Error("expected non-empty WebEnv")
 	}
@@ -195,3 +198,53 @@ func TestSearch_RateLimitError(t *testing.T) {
 		t.Error("expected error for rate limit, got nil")
 	}
 }
+
+func TestCount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("rettype"); got != "count" {
+			t.Errorf("expected rettype=count, got %q", got)
+		}
+		if got := q.Get("mindate") + "-" + q.Get("maxdate"); got != "2020-2020" {
+			t.Errorf("expected 2020-2020 date range, got %q", got)
+		}
+		if got := q.Get("datetype"); got != "pdat" {
+			t.Errorf("expected datetype=pdat, got %q", got)
+		}
+		w.Write([]byte(`{"esearchresult":{"count":"1234"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	count, err := c.Count(context.Background(), "autism", &SearchOptions{MinDate: "2020", MaxDate: "2020"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1234 {
+		t.Errorf("expected 1234, got %d", count)
+	}
+
+	if _, err := c.Count(context.Background(), "", nil); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestSearch_Notices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"esearchresult":{"count":"0","idlist":[],
+			"errorlist":{"phrasesnotfound":["zzqx"],"fieldsnotfound":["tiabs"]},
+			"warninglist":{"phrasesignored":["the"],"quotedphrasesnotfound":["\"no such phrase\""],"outputmessages":["No items found."]}}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	result, err := c.Search(context.Background(), "zzqx[tiabs]", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n := result.Notices
+	if len(n.PhrasesNotFound) != 1 || len(n.FieldsNotFound) != 1 || n.FieldsNotFound[0] != "tiabs" ||
+		len(n.PhrasesIgnored) != 1 || len(n.QuotedPhrasesNotFound) != 1 || len(n.Messages) != 1 {
+		t.Errorf("unexpected notices: %+v", n)
+	}
+}
@@ -0,0 +1,75 @@
+package eutils
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// LocalSortOrders are the orders SortArticles accepts.
+var LocalSortOrders = []string{"pubdate", "epubdate", "firstauthor"}
+
+// SortArticles reorders fetched articles in place, keeping the existing
+// order among ties:
+//
+//   - pubdate: newest publication date first, by PubDate, so MedlineDate
+//     records sort by their first month instead of by year alone.
+//   - epubdate: newest first by electronic publication date, falling back
+//     to PubDate for articles without one.
+//   - firstauthor: A to Z by the first author's last name, then forename.
+//
+// Articles missing the key sort last.
+func SortArticles(articles []Article, order string) error {
+	var key func(Article) string
+	desc := true
+	switch order {
+	case "pubdate":
+		key = func(a Article) string { return a.PubDate }
+	case "epubdate":
+		key = func(a Article) string { return cmp.Or(a.EPubDate, a.PubDate) }
+	case "firstauthor":
+		key = firstAuthorKey
+		desc = false
+	default:
+		return fmt.Errorf("unknown sort order %q (available: %s)", order, strings.Join(LocalSortOrders, ", "))
+	}
+	slices.SortStableFunc(articles, func(a, b Article) int {
+		ka, kb := key(a), key(b)
+		switch {
+		case ka == kb:
+			return 0
+		case ka == "":
+			return 1
+		case kb == "":
+			return -1
+		case desc:
+			return datePrefixCompare(kb, ka)
+		default:
+			return strings.Compare(ka, kb)
+		}
+	})
+	return nil
+}
+
+// datePrefixCompare compares ISO dates of differing precision, ranking a
+// bare "2024" below "2024-03" so that, newest first, a year-only date
+// follows the dated months of its year.
+func datePrefixCompare(a, b string) int {
+	n := min(len(a), len(b))
+	if c := strings.Compare(a[:n], b[:n]); c != 0 {
+		return c
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+func firstAuthorKey(a Article) string {
+	if len(a.Authors) == 0 {
+		return ""
+	}
+	au := a.Authors[0]
+	if au.CollectiveName != "" {
+		return strings.ToLower(au.CollectiveName)
+	}
+	return strings.ToLower(au.LastName + "\x00" + cmp.Or(au.ForeName, au.Initials))
+}
@@ -0,0 +1,58 @@
+package eutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextISODate(t *testing.T) {
+	tests := map[string]string{
+		"2024 Mar 15":       "2024-03-15",
+		"2020 Jan-Feb":      "2020-01",
+		"1998 Dec-1999 Jan": "1998-12",
+		"Winter 2020":       "2020",
+		"2019-2020":         "2019",
+		"2024":              "2024",
+		"":                  "",
+	}
+	for in, want := range tests {
+		if got := textISODate(in); got != want {
+			t.Errorf("textISODate(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := isoDate("2024", "01", "5"); got != "2024-01-05" {
+		t.Errorf("isoDate numeric = %q", got)
+	}
+}
+
+func TestSortArticles(t *testing.T) {
+	base := []Article{
+		{PMID: "1", PubDate: "2020", EPubDate: "2021-06-01", Authors: []Author{{LastName: "smith", ForeName: "Ann"}}},
+		{PMID: "2", PubDate: "2020-11"},
+		{PMID: "3", PubDate: "2021-01-05", Authors: []Author{{LastName: "Adams", ForeName: "Zoe"}}},
+		{PMID: "4", Authors: []Author{{LastName: "Smith", ForeName: "Al"}}},
+		{PMID: "5", PubDate: "2020-11"},
+	}
+
+	tests := []struct{ order, want string }{
+		{"pubdate", "3,2,5,1,4"},
+		{"epubdate", "1,3,2,5,4"},
+		{"firstauthor", "3,4,1,2,5"},
+	}
+	for _, tt := range tests {
+		articles := append([]Article(nil), base...)
+		if err := SortArticles(articles, tt.order); err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, len(articles))
+		for i, a := range articles {
+			ids[i] = a.PMID
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.order, got, tt.want)
+		}
+	}
+	if err := SortArticles(base, "title"); err == nil {
+		t.Error("expected error for unknown order")
+	}
+}
@@ -0,0 +1,74 @@
+package eutils
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxHistoryRecords is the most records PubMed returns for one search, even
+// through the history server.
+const MaxHistoryRecords = 10000
+
+// entrezDateLayout is the E-utilities date format.
+const entrezDateLayout = "2006/01/02"
+
+// DateRange is an inclusive span of Entrez dates, the days records were
+// added to PubMed, and how many of a query's records fall in it.
+type DateRange struct {
+	From  time.Time
+	To    time.Time
+	Count int
+}
+
+// Options returns search options restricting a search to the range.
+func (r DateRange) Options() *SearchOptions {
+	return &SearchOptions{
+		MinDate:  r.From.Format(entrezDateLayout),
+		MaxDate:  r.To.Format(entrezDateLayout),
+		DateType: "edat",
+	}
+}
+
+// SplitByEntrezDate partitions query's records added between from and to
+// into date ranges of at most max records each, oldest first, so a result
+// set larger than PubMed's per-search limit can be retrieved range by
+// range. Every record has exactly one Entrez date, so the ranges neither
+// overlap nor miss records. It fails if more than max records were added
+// on a single day.
+func (c *Client) SplitByEntrezDate(ctx context.Context, query string, from, to time.Time, max int) ([]DateRange, error) {
+	from, to = day(from), day(to)
+	var ranges []DateRange
+	var split func(r DateRange) error
+	split = func(r DateRange) error {
+		n, err := c.Count(ctx, query, r.Options())
+		if err != nil {
+			return err
+		}
+		r.Count = n
+		switch {
+		case n == 0:
+			return nil
+		case n <= max:
+			ranges = append(ranges, r)
+			return nil
+		case !r.From.Before(r.To):
+			return fmt.Errorf("%d records were added to PubMed on %s, more than the %d one search can return", n, r.From.Format(time.DateOnly), max)
+		}
+		days := int(r.To.Sub(r.From).Hours() / 24)
+		mid := r.From.AddDate(0, 0, days/2)
+		if err := split(DateRange{From: r.From, To: mid}); err != nil {
+			return err
+		}
+		return split(DateRange{From: mid.AddDate(0, 0, 1), To: r.To})
+	}
+	if err := split(DateRange{From: from, To: to}); err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// day truncates t to midnight UTC on its calendar day.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,68 @@
+package eutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitByEntrezDate(t *testing.T) {
+	// Records added per day: a busy 2024-03-05 and a scattering elsewhere.
+	added := map[string]int{
+		"2023/12/31": 2,
+		"2024/01/10": 3,
+		"2024/03/05": 4,
+		"2024/06/01": 1,
+		"2024/06/02": 2,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("datetype") != "edat" || q.Get("rettype") != "count" {
+			t.Errorf("unexpected request %s", r.URL.RawQuery)
+		}
+		n := 0
+		for d, c := range added {
+			if d >= q.Get("mindate") && d <= q.Get("maxdate") {
+				n += c
+			}
+		}
+		fmt.Fprintf(w, `{"esearchresult":{"count":"%d"}}`, n)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	from := time.Date(2023, 12, 1, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	ranges, err := c.SplitByEntrezDate(context.Background(), "autism", from, to, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	total := 0
+	var spans []string
+	for i, r := range ranges {
+		if r.Count == 0 || r.Count > 4 {
+			t.Errorf("range %d has %d records", i, r.Count)
+		}
+		if i > 0 && !r.From.After(ranges[i-1].To) {
+			t.Errorf("range %d overlaps the previous one", i)
+		}
+		total += r.Count
+		spans = append(spans, r.Options().MinDate+"-"+r.Options().MaxDate)
+	}
+	if total != 12 {
+		t.Errorf("ranges cover %d records, want 12: %v", total, spans)
+	}
+	if ranges[0].From.Hour() != 0 {
+		t.Errorf("ranges should start at midnight, got %v", ranges[0].From)
+	}
+
+	added["2024/03/05"] = 5
+	_, err = c.SplitByEntrezDate(context.Background(), "autism", from, to, 4)
+	if err == nil || !strings.Contains(err.Error(), "2024-03-05") {
+		t.Errorf("expected an error naming the busy day, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package eutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// docSumResponse is the JSON esummary.fcgi returns for the pubmed db.
+type docSumResponse struct {
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// docSum holds the fields of a PubMed document summary that map onto
+// Article.
+type docSum struct {
+	UID               string         `json:"uid"`
+	PubDate           string         `json:"pubdate"`
+	EPubDate          string         `json:"epubdate"`
+	Source            string         `json:"source"`
+	FullJournalName   string         `json:"fulljournalname"`
+	Authors           []docSumAuthor `json:"authors"`
+	Title             string         `json:"title"`
+	Volume            string         `json:"volume"`
+	Issue             string         `json:"issue"`
+	Pages             string         `json:"pages"`
+	Lang              []string       `json:"lang"`
+	PubType           []string       `json:"pubtype"`
+	ArticleIDs        []docSumID     `json:"articleids"`
+	BookTitle         string         `json:"booktitle"`
+	PublisherName     string         `json:"publishername"`
+	PublisherLocation string         `json:"publisherlocation"`
+	Error             string         `json:"error"`
+}
+
+// docSumAuthor is a summary author: "Smith JA", or a collective name.
+type docSumAuthor struct {
+	Name     string `json:"name"`
+	AuthType string `json:"authtype"`
+}
+
+type docSumID struct {
+	IDType string `json:"idtype"`
+	Value  string `json:"value"`
+}
+
+// SummaryHistory is the lean counterpart of FetchHistory: it retrieves
+// document summaries instead of full records, so articles have citation
+// fields, DOI, PMCID, language, and publication types but no abstract, MeSH
+// terms, keywords, affiliations, or full author forenames. A summary is a
+// fraction of the size of a full record, which keeps very large exports
+// fast and small.
+func (c *Client) SummaryHistory(ctx context.Context, webEnv, queryKey string, start, count int) ([]Article, error) {
+	if webEnv == "" || queryKey == "" {
+		return nil, fmt.Errorf("history fetch requires a WebEnv and query key")
+	}
+
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("WebEnv", webEnv)
+	params.Set("query_key", queryKey)
+	params.Set("retstart", strconv.Itoa(start))
+	params.Set("retmax", strconv.Itoa(count))
+	params.Set("retmode", "json")
+
+	body, err := c.DoGet(ctx, "esummary.fcgi", params)
+	if err != nil {
+		return nil, fmt.Errorf("summary request failed: %w", err)
+	}
+	return parseSummaries(body)
+}
+
+// parseSummaries converts an esummary response to articles in the order of
+// its uids list.
+func parseSummaries(body []byte) ([]Article, error) {
+	var resp docSumResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing summaries: %w", err)
+	}
+	var uids []string
+	if raw, ok := resp.Result["uids"]; ok {
+		if err := json.Unmarshal(raw, &uids); err != nil {
+			return nil, fmt.Errorf("parsing summary uids: %w", err)
+		}
+	}
+
+	articles := make([]Article, 0, len(uids))
+	var ds docSum
+	for _, uid := range uids {
+		raw, ok := resp.Result[uid]
+		if !ok {
+			continue
+		}
+		ds = docSum{}
+		if err := json.Unmarshal(raw, &ds); err != nil {
+			return nil, fmt.Errorf("parsing summary %s: %w", uid, err)
+		}
+		if ds.Error != "" {
+			continue
+		}
+		articles = append(articles, convertSummary(ds))
+	}
+	return articles, nil
+}
+
+func convertSummary(ds docSum) Article {
+	a := Article{
+		PMID:             ds.UID,
+		Title:            ds.Title,
+		Journal:          ds.FullJournalName,
+		JournalAbbrev:    ds.Source,
+		Volume:           ds.Volume,
+		Issue:            ds.Issue,
+		Pages:            ds.Pages,
+		PublicationTypes: ds.PubType,
+		BookTitle:        ds.BookTitle,
+		Publisher:        ds.PublisherName,
+		PublisherPlace:   ds.PublisherLocation,
+	}
+	// pubdate reads "2023 Jan 15", "2023 Jan-Feb", or "2023".
+	if fields := strings.Fields(ds.PubDate); len(fields) > 0 {
+		a.Year = yearRe.FindString(fields[0])
+		if len(fields) > 1 && a.Year == fields[0] {
+			a.Month = fields[1]
+		}
+	}
+	if a.Year == "" {
+		a.Year = yearRe.FindString(ds.PubDate)
+	}
+	a.PubDate = textISODate(ds.PubDate)
+	a.EPubDate = textISODate(ds.EPubDate)
+	if len(ds.Lang) > 0 {
+		a.Language = ds.Lang[0]
+	}
+	for _, id := range ds.ArticleIDs {
+		switch id.IDType {
+		case "doi":
+			a.DOI = id.Value
+		case "pmc":
+			a.PMCID = id.Value
+		}
+	}
+	for _, au := range ds.Authors {
+		author := Author{}
+		if au.AuthType == "CollectiveName" {
+			author.CollectiveName = au.Name
+		} else if i := strings.LastIndex(au.Name, " "); i > 0 {
+			author.LastName = au.Name[:i]
+			author.ForeName = au.Name[i+1:]
+			author.Initials = au.Name[i+1:]
+		} else {
+			author.LastName = au.Name
+		}
+		author.DisplayName = author.FullName()
+		a.Authors = append(a.Authors, author)
+	}
+	return a
+}
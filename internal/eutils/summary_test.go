@@ -0,0 +1,77 @@
+package eutils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSummaryHistory(t *testing.T) {
+	fixture := loadTestdata(t, "esummary_pubmed.json")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/esummary.fcgi" {
+			t.Errorf("expected esummary.fcgi, got %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("WebEnv") != "MCID_abc" || q.Get("query_key") != "1" || q.Get("retstart") != "200" || q.Get("retmax") != "200" {
+			t.Errorf("unexpected params: %s", r.URL.RawQuery)
+		}
+		if q.Get("retmode") != "json" {
+			t.Errorf("expected retmode=json, got %q", q.Get("retmode"))
+		}
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithAPIKey("test"))
+	articles, err := c.SummaryHistory(context.Background(), "MCID_abc", "1", 200, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("expected 2 articles, got %d", len(articles))
+	}
+
+	a := articles[0]
+	if a.PMID != "38123456" || a.Title != "Testing summaries of fragile X syndrome." {
+		t.Errorf("unexpected article: %+v", a)
+	}
+	if a.Journal != "Journal of neurodevelopmental disorders" || a.JournalAbbrev != "J Neurodev Disord" {
+		t.Errorf("journal = %q / %q", a.Journal, a.JournalAbbrev)
+	}
+	if a.Year != "2024" || a.Month != "Mar" {
+		t.Errorf("date = %q %q, want 2024 Mar", a.Year, a.Month)
+	}
+	if a.PubDate != "2024-03-15" || a.EPubDate != "2024-01-02" {
+		t.Errorf("dates = %q / %q", a.PubDate, a.EPubDate)
+	}
+	if a.DOI != "10.1000/fxs.2024" || a.PMCID != "PMC1234567" {
+		t.Errorf("ids = %q %q", a.DOI, a.PMCID)
+	}
+	if a.Language != "eng" || len(a.PublicationTypes) != 2 {
+		t.Errorf("language %q, types %v", a.Language, a.PublicationTypes)
+	}
+	if len(a.Authors) != 2 {
+		t.Fatalf("expected 2 authors, got %+v", a.Authors)
+	}
+	if au := a.Authors[0]; au.LastName != "Hunter" || au.Initials != "JE" {
+		t.Errorf("author = %+v", au)
+	}
+	if au := a.Authors[1]; au.CollectiveName != "Fragile X Consortium" {
+		t.Errorf("collective author = %+v", au)
+	}
+	if a.Abstract != "" || len(a.MeSHTerms) != 0 {
+		t.Error("summaries should carry no abstract or MeSH terms")
+	}
+
+	book := articles[1]
+	if !book.IsBook() || book.BookTitle != "GeneReviews" || book.PublisherPlace != "Seattle (WA)" {
+		t.Errorf("unexpected book chapter: %+v", book)
+	}
+
+	if _, err := c.SummaryHistory(context.Background(), "", "1", 0, 10); err == nil {
+		t.Error("expected error without WebEnv")
+	}
+}
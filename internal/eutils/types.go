@@ -3,11 +3,32 @@ package eutils
 
 // SearchResult represents the result of an ESearch query.
 type SearchResult struct {
-	Count            int      `json:"count"`
-	IDs              []string `json:"ids"`
-	QueryTranslation string   `json:"query_translation"`
-	WebEnv           string   `json:"web_env,omitempty"`
-	QueryKey         string   `json:"query_key,omitempty"`
+	Count            int           `json:"count"`
+	IDs              []string      `json:"ids"`
+	QueryTranslation string        `json:"query_translation"`
+	Translations     []Translation `json:"translations,omitempty"`
+	WebEnv           string        `json:"web_env,omitempty"`
+	QueryKey         string        `json:"query_key,omitempty"`
+	// Notices are the problems PubMed found with the query's terms. They
+	// are reported by pubmed lint rather than in search output.
+	Notices SearchNotices `json:"-"`
+}
+
+// SearchNotices are the errors and warnings ESearch reports for a query:
+// phrases and field tags it could not use, and phrases it dropped.
+type SearchNotices struct {
+	PhrasesNotFound       []string `json:"phrases_not_found,omitempty"`
+	FieldsNotFound        []string `json:"fields_not_found,omitempty"`
+	PhrasesIgnored        []string `json:"phrases_ignored,omitempty"`
+	QuotedPhrasesNotFound []string `json:"quoted_phrases_not_found,omitempty"`
+	Messages              []string `json:"messages,omitempty"`
+}
+
+// Translation is one phrase PubMed's automatic term mapping rewrote,
+// e.g. "heart attack" → "myocardial infarction"[MeSH Terms] OR ....
+type Translation struct {
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 // Article represents a PubMed article with parsed fields.
@@ -24,11 +45,89 @@ type Article struct {
 	Pages            string            `json:"pages,omitempty"`
 	Year             string            `json:"year"`
 	Month            string            `json:"month,omitempty"`
-	DOI              string            `json:"doi,omitempty"`
-	PMCID            string            `json:"pmcid,omitempty"`
-	MeSHTerms        []MeSHTerm        `json:"mesh_terms,omitempty"`
-	PublicationTypes []string          `json:"publication_types"`
-	Language         string            `json:"language"`
+	// PubDate and EPubDate are ISO dates, as precise as the record gives
+	// ("2024", "2024-03", or "2024-03-15"): the issue's publication date
+	// and the article's electronic publication, when it has one.
+	PubDate          string     `json:"pub_date,omitempty"`
+	EPubDate         string     `json:"epub_date,omitempty"`
+	DOI              string     `json:"doi,omitempty"`
+	PMCID            string     `json:"pmcid,omitempty"`
+	MeSHTerms        []MeSHTerm `json:"mesh_terms,omitempty"`
+	PublicationTypes []string   `json:"publication_types"`
+	Language         string     `json:"language"`
+	// PublicationStatus is PubMed's status for the article: aheadofprint
+	// until it appears in an issue, then ppublish or epublish.
+	PublicationStatus string `json:"publication_status,omitempty"`
+	// BookTitle, Publisher, PublisherPlace, and CollectionTitle describe
+	// NCBI Bookshelf records: BookTitle is the book a chapter belongs to
+	// (empty for a whole book, whose title is Title), and CollectionTitle
+	// the series, such as GeneReviews.
+	BookTitle       string `json:"book_title,omitempty"`
+	Publisher       string `json:"publisher,omitempty"`
+	PublisherPlace  string `json:"publisher_place,omitempty"`
+	CollectionTitle string `json:"collection_title,omitempty"`
+	// Keywords are the author-supplied keywords, when the record has them.
+	Keywords []string `json:"keywords,omitempty"`
+	// Grants are the funding the record lists, as NLM indexes it.
+	Grants []Grant `json:"grants,omitempty"`
+	// CommentsCorrections links the article to retraction notices, errata,
+	// and expressions of concern (and the reverse for such notices).
+	CommentsCorrections []CommentCorrection `json:"comments_corrections,omitempty"`
+	// License and ReferenceCount come from Crossref; PubMed does not supply
+	// them.
+	License        string `json:"license,omitempty"`
+	ReferenceCount int    `json:"reference_count,omitempty"`
+	// OAStatus (gold, hybrid, bronze, green, or closed) and OAURL, the best
+	// open-access copy, come from Unpaywall.
+	OAStatus string `json:"oa_status,omitempty"`
+	OAURL    string `json:"oa_url,omitempty"`
+	// CitationCount, Concepts, and Institutions come from OpenAlex.
+	CitationCount int      `json:"citation_count,omitempty"`
+	Concepts      []string `json:"concepts,omitempty"`
+	Institutions  []string `json:"institutions,omitempty"`
+	// InfluentialCitationCount and TLDR, a one-sentence summary, come from
+	// Semantic Scholar.
+	InfluentialCitationCount int    `json:"influential_citation_count,omitempty"`
+	TLDR                     string `json:"tldr,omitempty"`
+	// RCR (Relative Citation Ratio), NIHPercentile, and APT (Approximate
+	// Potential to Translate) come from NIH iCite; nil means iCite has no
+	// value, as for papers too recent to have an RCR.
+	RCR           *float64 `json:"rcr,omitempty"`
+	NIHPercentile *float64 `json:"nih_percentile,omitempty"`
+	APT           *float64 `json:"apt,omitempty"`
+	// Source is "crossref" for a record resolved from Crossref because its
+	// DOI is not in PubMed, and empty for PubMed records.
+	Source string `json:"source,omitempty"`
+	// CrossrefFields lists the fields Crossref filled in a PubMed record,
+	// e.g. "doi" or "pages".
+	CrossrefFields []string `json:"crossref_fields,omitempty"`
+	// Notes and Tags are the user's own annotations from the local
+	// library; PubMed never supplies them.
+	Notes []string `json:"notes,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// IsBook reports whether a is a Bookshelf book or chapter rather than a
+// journal article.
+func (a Article) IsBook() bool {
+	return a.Publisher != "" || a.BookTitle != ""
+}
+
+// CommentCorrection is a PubMed CommentsCorrections link, such as
+// RetractionIn or ErratumIn, to the notice's citation and PMID.
+type CommentCorrection struct {
+	Type   string `json:"type"`
+	Source string `json:"source,omitempty"`
+	PMID   string `json:"pmid,omitempty"`
+}
+
+// Grant is one entry of an article's GrantList: a grant or contract number
+// (often empty for non-NIH funders) and the agency that awarded it.
+type Grant struct {
+	ID      string `json:"id,omitempty"`
+	Acronym string `json:"acronym,omitempty"`
+	Agency  string `json:"agency"`
+	Country string `json:"country,omitempty"`
 }
 
 // AbstractSection represents a labeled section of a structured abstract.
@@ -58,12 +157,21 @@ func (a Author) FullName() string {
 	return a.ForeName + " " + a.LastName
 }
 
-// MeSHTerm represents a MeSH heading with optional qualifiers.
+// MeSHTerm represents a MeSH heading with optional qualifiers. MajorTopic
+// is the descriptor's own major-topic flag; MajorQualifier is set when one
+// of its qualifiers is starred instead.
 type MeSHTerm struct {
-	Descriptor   string   `json:"descriptor"`
-	DescriptorUI string   `json:"descriptor_ui"`
-	MajorTopic   bool     `json:"major_topic"`
-	Qualifiers   []string `json:"qualifiers,omitempty"`
+	Descriptor     string   `json:"descriptor"`
+	DescriptorUI   string   `json:"descriptor_ui"`
+	MajorTopic     bool     `json:"major_topic"`
+	MajorQualifier bool     `json:"major_qualifier,omitempty"`
+	Qualifiers     []string `json:"qualifiers,omitempty"`
+}
+
+// Major reports whether the heading is a major topic as PubMed's [majr]
+// searches it: the descriptor or any of its qualifiers is starred.
+func (m MeSHTerm) Major() bool {
+	return m.MajorTopic || m.MajorQualifier
 }
 
 // LinkResult represents the result of an ELink query.
@@ -84,4 +192,7 @@ type SearchOptions struct {
 	Sort    string `json:"sort,omitempty"`
 	MinDate string `json:"min_date,omitempty"`
 	MaxDate string `json:"max_date,omitempty"`
+	// DateType selects the date MinDate/MaxDate filter on: "pdat"
+	// (publication date, the default) or "edat" (date added to PubMed).
+	DateType string `json:"date_type,omitempty"`
 }
@@ -0,0 +1,391 @@
+// Package fulltext finds open-access full text for PubMed articles: the
+// PMC XML and PDF of articles in the PMC Open Access Subset, with Unpaywall
+// as a fallback for articles outside PMC.
+package fulltext
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// Service endpoints.
+const (
+	DefaultOAURL        = "https://www.ncbi.nlm.nih.gov/pmc/utils/oa/oa.fcgi"
+	DefaultUnpaywallURL = "https://api.unpaywall.org/v2"
+	// EnvUnpaywallEmail sets the contact email Unpaywall requires.
+	EnvUnpaywallEmail = "UNPAYWALL_EMAIL"
+)
+
+// Sources of full text.
+const (
+	SourcePMC       = "pmc"
+	SourceUnpaywall = "unpaywall"
+)
+
+// Result is where one article's open-access full text was found.
+type Result struct {
+	PMID  string `json:"pmid"`
+	PMCID string `json:"pmcid,omitempty"`
+	DOI   string `json:"doi,omitempty"`
+	// Source is SourcePMC, SourceUnpaywall, or empty when no open-access
+	// copy was found.
+	Source     string `json:"source,omitempty"`
+	Format     string `json:"format,omitempty"`
+	URL        string `json:"url,omitempty"`
+	License    string `json:"license,omitempty"`
+	LicenseURL string `json:"license_url,omitempty"`
+	// HostType is where an Unpaywall copy is hosted: HostPublisher or
+	// HostRepository.
+	HostType string `json:"host_type,omitempty"`
+	// Path is where the full text was saved, when downloaded.
+	Path string `json:"path,omitempty"`
+	// Document is the parsed PMC XML, when available.
+	Document *Document `json:"document,omitempty"`
+	// raw is the PMC XML, kept for saving.
+	raw []byte
+}
+
+// Found reports whether an open-access copy was found.
+func (r Result) Found() bool {
+	return r.Source != ""
+}
+
+// Client resolves full text through PMC and Unpaywall. PMC requests share
+// the NCBI base client's rate limit.
+type Client struct {
+	*ncbi.BaseClient
+	OAURL        string
+	UnpaywallURL string
+	// Email identifies the caller to Unpaywall; without one, Unpaywall is
+	// not consulted.
+	Email string
+	// Resolver is an institutional link resolver or proxy URL template
+	// (see ResolverURL) for ResolvePDF; it is optional.
+	Resolver string
+}
+
+// NewClient creates a full-text client using an existing NCBI base client.
+func NewClient(base *ncbi.BaseClient, email string) *Client {
+	return &Client{BaseClient: base, OAURL: DefaultOAURL, UnpaywallURL: DefaultUnpaywallURL, Email: email}
+}
+
+// Resolve finds full text for a fetched article: its PMC XML (or, when pdf
+// is set, the PDF from the PMC Open Access Subset), falling back to an
+// Unpaywall open-access location for its DOI.
+func (c *Client) Resolve(ctx context.Context, a eutils.Article, pdf bool) (Result, error) {
+	r := Result{PMID: a.PMID, PMCID: a.PMCID, DOI: a.DOI}
+	if a.PMCID != "" {
+		if err := c.resolvePMC(ctx, &r, pdf); err != nil {
+			return r, err
+		}
+		if r.Found() {
+			return r, nil
+		}
+	}
+	if a.DOI == "" || c.Email == "" {
+		return r, nil
+	}
+	oa, err := c.Lookup(ctx, a.DOI)
+	if err != nil || oa == nil {
+		return r, err
+	}
+	loc, format := oa.pick(pdf)
+	if loc != nil {
+		r.Source, r.Format, r.License, r.HostType = SourceUnpaywall, format, loc.License, loc.HostType
+		r.URL = loc.URL
+		if format == "pdf" {
+			r.URL = loc.PDFURL
+		}
+	}
+	return r, nil
+}
+
+// Annotate sets a's OAStatus and OAURL from Unpaywall. Articles without a
+// DOI, or every article when no Email is set, are left unchanged.
+func (c *Client) Annotate(ctx context.Context, a *eutils.Article) error {
+	if a.DOI == "" || c.Email == "" {
+		return nil
+	}
+	oa, err := c.Lookup(ctx, a.DOI)
+	if err != nil || oa == nil {
+		return err
+	}
+	a.OAStatus = oa.Status
+	if loc, format := oa.pick(false); loc != nil {
+		a.OAURL = loc.URL
+		if format == "pdf" {
+			a.OAURL = loc.PDFURL
+		}
+	}
+	return nil
+}
+
+func (c *Client) resolvePMC(ctx context.Context, r *Result, pdf bool) error {
+	if pdf {
+		link, err := c.OAPDF(ctx, r.PMCID)
+		if err != nil || link == nil {
+			return err
+		}
+		r.Source, r.Format, r.URL, r.License = SourcePMC, "pdf", link.URL, link.License
+		return nil
+	}
+	data, doc, err := c.PMC(ctx, r.PMCID)
+	if err != nil || !doc.HasBody {
+		return err
+	}
+	r.Source, r.Format = SourcePMC, "xml"
+	r.URL = "https://pmc.ncbi.nlm.nih.gov/articles/" + r.PMCID + "/"
+	r.License, r.LicenseURL = doc.License, doc.LicenseURL
+	r.Document, r.raw = doc, data
+	return nil
+}
+
+// PMC fetches and parses an article's PMC XML.
+func (c *Client) PMC(ctx context.Context, pmcid string) ([]byte, *Document, error) {
+	params := url.Values{}
+	params.Set("db", "pmc")
+	params.Set("id", strings.TrimPrefix(strings.ToUpper(pmcid), "PMC"))
+	params.Set("retmode", "xml")
+	body, err := c.DoGet(ctx, "efetch.fcgi", params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PMC fetch failed: %w", err)
+	}
+	doc, err := ParseJATS(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", pmcid, err)
+	}
+	return body, doc, nil
+}
+
+// Link is a file offered by the PMC Open Access web service.
+type Link struct {
+	Format  string `xml:"format,attr"`
+	URL     string `xml:"href,attr"`
+	License string
+}
+
+type oaResponse struct {
+	Records []struct {
+		License string `xml:"license,attr"`
+		Links   []Link `xml:"link"`
+	} `xml:"records>record"`
+}
+
+// OAPDF returns the PDF the PMC Open Access web service lists for pmcid, or
+// nil when the article is not in the Open Access Subset or has no PDF.
+func (c *Client) OAPDF(ctx context.Context, pmcid string) (*Link, error) {
+	body, err := c.get(ctx, c.OAURL+"?id="+url.QueryEscape(pmcid))
+	if err != nil {
+		return nil, fmt.Errorf("PMC OA lookup failed: %w", err)
+	}
+	if body == nil {
+		return nil, nil
+	}
+	var resp oaResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing PMC OA response: %w", err)
+	}
+	for _, rec := range resp.Records {
+		for _, l := range rec.Links {
+			if l.Format == "pdf" {
+				l.License = rec.License
+				// The service lists FTP links; the same paths are served over HTTPS.
+				l.URL = strings.Replace(l.URL, "ftp://", "https://", 1)
+				return &l, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Location is an Unpaywall open-access location.
+type Location struct {
+	URL      string `json:"url"`
+	PDFURL   string `json:"url_for_pdf"`
+	License  string `json:"license"`
+	HostType string `json:"host_type"`
+	Version  string `json:"version"`
+}
+
+// Host types of Unpaywall locations. Repository copies are "green" open
+// access.
+const (
+	HostPublisher  = "publisher"
+	HostRepository = "repository"
+)
+
+// OA is Unpaywall's open-access record for a DOI. Status is one of gold,
+// hybrid, bronze, green, or closed.
+type OA struct {
+	IsOA      bool       `json:"is_oa"`
+	Status    string     `json:"oa_status"`
+	Best      *Location  `json:"best_oa_location"`
+	Locations []Location `json:"oa_locations"`
+}
+
+// pick chooses the location to use and its format: the best location's PDF,
+// otherwise a PDF from another location (repository copies first), and
+// unless pdf is set, the best location's landing page.
+func (oa *OA) pick(pdf bool) (*Location, string) {
+	if !oa.IsOA {
+		return nil, ""
+	}
+	if oa.Best != nil && oa.Best.PDFURL != "" {
+		return oa.Best, "pdf"
+	}
+	for _, host := range []string{HostRepository, HostPublisher} {
+		for i, loc := range oa.Locations {
+			if loc.HostType == host && loc.PDFURL != "" {
+				return &oa.Locations[i], "pdf"
+			}
+		}
+	}
+	if !pdf && oa.Best != nil && oa.Best.URL != "" {
+		return oa.Best, "html"
+	}
+	return nil, ""
+}
+
+// Lookup returns Unpaywall's open-access record for doi, or nil when
+// Unpaywall does not know the DOI.
+func (c *Client) Lookup(ctx context.Context, doi string) (*OA, error) {
+	u := c.UnpaywallURL + "/" + url.PathEscape(doi) + "?email=" + url.QueryEscape(c.Email)
+	body, err := c.get(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("Unpaywall lookup failed: %w", err)
+	}
+	if body == nil {
+		return nil, nil
+	}
+	var oa OA
+	if err := json.Unmarshal(body, &oa); err != nil {
+		return nil, fmt.Errorf("parsing Unpaywall response: %w", err)
+	}
+	return &oa, nil
+}
+
+// Unpaywall returns the best open-access location Unpaywall knows for doi,
+// or nil when there is none.
+func (c *Client) Unpaywall(ctx context.Context, doi string) (*Location, error) {
+	oa, err := c.Lookup(ctx, doi)
+	if err != nil || oa == nil || !oa.IsOA {
+		return nil, err
+	}
+	return oa.Best, nil
+}
+
+// get performs a rate-limited GET outside the E-utilities endpoints. A 404
+// returns a nil body, so lookups of unknown IDs are not errors.
+func (c *Client) get(ctx context.Context, u string) ([]byte, error) {
+	resp, err := c.open(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if int64(len(body)) > c.MaxBytes {
+		return nil, fmt.Errorf("response exceeds maximum size of %d bytes", c.MaxBytes)
+	}
+	return body, nil
+}
+
+func (c *Client) open(ctx context.Context, u string) (*http.Response, error) {
+	if c.Offline {
+		return nil, c.Refuse("GET " + u)
+	}
+	if err := c.Limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit wait: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	return c.HTTPClient.Do(req)
+}
+
+// Save writes r's full text into dir as <pmid>.xml or <pmid>.pdf, setting
+// r.Path. HTML landing pages are not downloaded, nor is a PDF link that
+// answers with something else, such as a proxy's sign-in page.
+func (c *Client) Save(ctx context.Context, r *Result, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	dest := filepath.Join(dir, r.PMID+"."+r.Format)
+	switch r.Format {
+	case "xml":
+		if err := writeFile(dest, bytes.NewReader(r.raw)); err != nil {
+			return err
+		}
+	case "pdf":
+		resp, err := c.open(ctx, r.URL)
+		if err != nil {
+			return fmt.Errorf("downloading %s: %w", r.URL, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("downloading %s: HTTP %d", r.URL, resp.StatusCode)
+		}
+		body := bufio.NewReader(io.LimitReader(resp.Body, c.MaxBytes))
+		if magic, _ := body.Peek(len(pdfMagic)); string(magic) != pdfMagic {
+			return fmt.Errorf("downloading %s: got %s, not a PDF (open the link in a browser signed in to your library)", r.URL, contentType(resp))
+		}
+		if err := writeFile(dest, body); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	r.Path = dest
+	return nil
+}
+
+// pdfMagic starts every PDF file.
+const pdfMagic = "%PDF-"
+
+func contentType(resp *http.Response) string {
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		return ct
+	}
+	return "an unknown content type"
+}
+
+// writeFile writes src to path through a temporary file.
+func writeFile(path string, src io.Reader) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", tmp, err)
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return os.Rename(tmp, path)
+}
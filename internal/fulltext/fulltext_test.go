@@ -0,0 +1,168 @@
+package fulltext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// newTestClient serves PMC efetch, the PMC OA service, Unpaywall, a PDF,
+// and a proxy sign-in page from one test server.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	article := loadTestdata(t, "pmc_efetch.xml")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/efetch.fcgi":
+			if r.URL.Query().Get("db") != "pmc" || r.URL.Query().Get("id") != "10000001" {
+				t.Errorf("unexpected efetch query %s", r.URL.RawQuery)
+			}
+			w.Write(article)
+		case r.URL.Path == "/oa":
+			if r.URL.Query().Get("id") != "PMC10000001" {
+				w.Write([]byte(`<OA><error code="idIsNotOpenAccess">not OA</error></OA>`))
+				return
+			}
+			w.Write([]byte(`<OA><records><record id="PMC10000001" license="CC BY">
+<link format="tgz" href="ftp://` + r.Host + `/a.tar.gz"/>
+<link format="pdf" href="ftp://` + r.Host + `/a.pdf"/>
+</record></records></OA>`))
+		case r.URL.Path == "/unpaywall/10.1000/oa":
+			if r.URL.Query().Get("email") != "me@example.org" {
+				t.Errorf("missing Unpaywall email: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"is_oa":true,"best_oa_location":{"url":"https://repo.example.org/oa","url_for_pdf":"","license":"cc-by-nc","host_type":"repository"}}`))
+		case r.URL.Path == "/unpaywall/10.1000/green":
+			w.Write([]byte(`{"is_oa":true,"oa_status":"bronze",
+"best_oa_location":{"url":"https://publisher.example.org/a","url_for_pdf":"","host_type":"publisher"},
+"oa_locations":[{"url":"https://publisher.example.org/a","url_for_pdf":"","host_type":"publisher"},
+{"url":"https://repo.example.org/a","url_for_pdf":"https://repo.example.org/a.pdf","license":"cc-by","host_type":"repository","version":"acceptedVersion"}]}`))
+		case r.URL.Path == "/unpaywall/10.1000/closed":
+			w.Write([]byte(`{"is_oa":false,"oa_status":"closed","best_oa_location":null}`))
+		case r.URL.Path == "/a.pdf":
+			w.Write([]byte("%PDF-1.7"))
+		case r.URL.Path == "/login":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Sign in</html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient(ncbi.NewBaseClient(ncbi.WithBaseURL(srv.URL), ncbi.WithAPIKey("test-key"), ncbi.WithHTTPClient(srv.Client())), "me@example.org")
+	c.OAURL = srv.URL + "/oa"
+	c.UnpaywallURL = srv.URL + "/unpaywall"
+	return c
+}
+
+func TestResolve_PMCXML(t *testing.T) {
+	c := newTestClient(t)
+	r, err := c.Resolve(context.Background(), eutils.Article{PMID: "1", PMCID: "PMC10000001", DOI: "10.1000/oa"}, false)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if r.Source != SourcePMC || r.Format != "xml" || r.Document == nil || r.License != "open-access" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	dir := t.TempDir()
+	if err := c.Save(context.Background(), &r, dir); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "1.xml"))
+	if err != nil || !strings.Contains(string(data), "<article-title>") {
+		t.Fatalf("saved XML missing: %v", err)
+	}
+}
+
+func TestResolve_PMCPDF(t *testing.T) {
+	c := newTestClient(t)
+	r, err := c.Resolve(context.Background(), eutils.Article{PMID: "1", PMCID: "PMC10000001"}, true)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if r.Format != "pdf" || r.License != "CC BY" || !strings.HasPrefix(r.URL, "https://") {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	// The test server speaks plain HTTP, so point the download at it directly.
+	r.URL = strings.Replace(r.URL, "https://", "http://", 1)
+	dir := t.TempDir()
+	if err := c.Save(context.Background(), &r, dir); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if r.Path != filepath.Join(dir, "1.pdf") {
+		t.Errorf("Path = %q", r.Path)
+	}
+}
+
+func TestResolve_Unpaywall(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	r, err := c.Resolve(ctx, eutils.Article{PMID: "2", DOI: "10.1000/oa"}, false)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if r.Source != SourceUnpaywall || r.Format != "html" || r.URL != "https://repo.example.org/oa" || r.License != "cc-by-nc" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	// No PDF is offered, so a PDF request finds nothing.
+	if r, err := c.Resolve(ctx, eutils.Article{PMID: "2", DOI: "10.1000/oa"}, true); err != nil || r.Found() {
+		t.Fatalf("expected no PDF, got %+v (%v)", r, err)
+	}
+	for _, doi := range []string{"10.1000/closed", "10.1000/unknown"} {
+		if r, err := c.Resolve(ctx, eutils.Article{PMID: "3", DOI: doi}, false); err != nil || r.Found() {
+			t.Errorf("%s: expected nothing, got %+v (%v)", doi, r, err)
+		}
+	}
+
+	c.Email = ""
+	if r, err := c.Resolve(ctx, eutils.Article{PMID: "2", DOI: "10.1000/oa"}, false); err != nil || r.Found() {
+		t.Errorf("Unpaywall should be skipped without an email, got %+v (%v)", r, err)
+	}
+}
+
+func TestResolve_GreenFallback(t *testing.T) {
+	c := newTestClient(t)
+	for _, pdf := range []bool{false, true} {
+		r, err := c.Resolve(context.Background(), eutils.Article{PMID: "4", DOI: "10.1000/green"}, pdf)
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if r.Format != "pdf" || r.URL != "https://repo.example.org/a.pdf" || r.HostType != HostRepository || r.License != "cc-by" {
+			t.Errorf("pdf=%v: expected the repository PDF, got %+v", pdf, r)
+		}
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	tests := []struct {
+		doi, status, url string
+	}{
+		{"10.1000/oa", "", "https://repo.example.org/oa"},
+		{"10.1000/green", "bronze", "https://repo.example.org/a.pdf"},
+		{"10.1000/closed", "closed", ""},
+		{"10.1000/unknown", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		a := eutils.Article{DOI: tt.doi}
+		if err := c.Annotate(ctx, &a); err != nil {
+			t.Fatalf("%s: %v", tt.doi, err)
+		}
+		if a.OAStatus != tt.status || a.OAURL != tt.url {
+			t.Errorf("%s: got status %q, URL %q; want %q, %q", tt.doi, a.OAStatus, a.OAURL, tt.status, tt.url)
+		}
+	}
+}
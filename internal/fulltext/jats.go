@@ -0,0 +1,216 @@
+package fulltext
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Document is the readable content of a PMC (JATS) article.
+type Document struct {
+	Title string `json:"title,omitempty"`
+	// License is the license type or text, e.g. "open-access" or "CC BY 4.0".
+	License    string    `json:"license,omitempty"`
+	LicenseURL string    `json:"license_url,omitempty"`
+	Sections   []Section `json:"sections,omitempty"`
+	// HasBody is false when PMC supplied only front matter, as it does for
+	// articles whose publisher does not allow XML full text.
+	HasBody bool `json:"has_body"`
+}
+
+// Section is a titled run of paragraphs; nested sections are flattened with
+// their depth kept for headings.
+type Section struct {
+	Title      string   `json:"title,omitempty"`
+	Depth      int      `json:"depth"`
+	Paragraphs []string `json:"paragraphs"`
+}
+
+// skipped are body elements whose text is not running prose.
+var skipped = map[string]bool{
+	"fig":                    true,
+	"table-wrap":             true,
+	"disp-formula":           true,
+	"supplementary-material": true,
+}
+
+// ParseJATS extracts the title, license, abstract, and body sections from a
+// PMC efetch response.
+func ParseJATS(data []byte) (*Document, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	doc := &Document{}
+	var (
+		inArticle, inAbstract, inBody, inBack bool
+
+		depth int
+		cur   *Section
+	)
+	newSection := func(title string, d int) {
+		doc.Sections = append(doc.Sections, Section{Title: title, Depth: d})
+		cur = &doc.Sections[len(doc.Sections)-1]
+	}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing PMC XML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			switch {
+			case name == "sub-article" || (name == "article" && inArticle):
+				// Sub-articles (e.g. peer review reports) are not the article text.
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case name == "article":
+				inArticle = true
+			case name == "article-title" && doc.Title == "" && !inBody && !inBack:
+				text, err := innerText(dec)
+				if err != nil {
+					return nil, err
+				}
+				doc.Title = text
+			case name == "license":
+				if href := attr(t, "href"); href != "" {
+					doc.LicenseURL = href
+				}
+				if typ := attr(t, "license-type"); typ != "" && doc.License == "" {
+					doc.License = typ
+				}
+			case name == "license_ref":
+				text, err := innerText(dec)
+				if err != nil {
+					return nil, err
+				}
+				if doc.LicenseURL == "" {
+					doc.LicenseURL = text
+				}
+			case name == "abstract" && !inBody && !inAbstract && attr(t, "abstract-type") == "":
+				inAbstract = true
+				newSection("Abstract", 1)
+			case name == "body":
+				inBody, doc.HasBody = true, true
+				depth = 0
+				newSection("", 1)
+			case name == "back":
+				inBack = true
+			case (inBody || inAbstract) && skipped[name]:
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case inBody && name == "sec":
+				depth++
+			case (inBody || inAbstract) && name == "title" && cur != nil:
+				text, err := innerText(dec)
+				if err != nil {
+					return nil, err
+				}
+				d := depth
+				if inAbstract {
+					d = 2
+				}
+				newSection(text, d)
+			case (inBody || inAbstract) && name == "p" && cur != nil:
+				text, err := innerText(dec)
+				if err != nil {
+					return nil, err
+				}
+				if text != "" {
+					cur.Paragraphs = append(cur.Paragraphs, text)
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "abstract":
+				inAbstract = false
+			case "body":
+				inBody = false
+			case "back":
+				inBack = false
+			case "sec":
+				if inBody && depth > 0 {
+					depth--
+				}
+			}
+		}
+	}
+
+	// Drop headings with no paragraphs under them or any nested section.
+	kept := doc.Sections[:0]
+	for i, s := range doc.Sections {
+		nested := i+1 < len(doc.Sections) && doc.Sections[i+1].Depth > s.Depth
+		if len(s.Paragraphs) > 0 || (s.Title != "" && nested) {
+			kept = append(kept, s)
+		}
+	}
+	doc.Sections = kept
+	if doc.Title == "" && len(doc.Sections) == 0 {
+		return nil, fmt.Errorf("no article found in PMC response")
+	}
+	return doc, nil
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// innerText returns the whitespace-normalized text of the element just
+// opened, consuming it.
+func innerText(dec *xml.Decoder) (string, error) {
+	var b strings.Builder
+	level := 1
+	for level > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("parsing PMC XML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skipped[t.Name.Local] {
+				if err := dec.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			level++
+		case xml.EndElement:
+			level--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " "), nil
+}
+
+// Text renders the document as plain text: the title, then each section
+// with a Markdown-style heading and its paragraphs.
+func (d *Document) Text() string {
+	var b strings.Builder
+	if d.Title != "" {
+		b.WriteString("# " + d.Title + "\n")
+	}
+	for _, s := range d.Sections {
+		if s.Title != "" {
+			fmt.Fprintf(&b, "\n%s %s\n", strings.Repeat("#", s.Depth+1), s.Title)
+		}
+		for _, p := range s.Paragraphs {
+			b.WriteString("\n" + p + "\n")
+		}
+	}
+	return b.String()
+}
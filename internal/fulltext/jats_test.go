@@ -0,0 +1,80 @@
+package fulltext
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", name))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	return data
+}
+
+func TestParseJATS(t *testing.T) {
+	doc, err := ParseJATS(loadTestdata(t, "pmc_efetch.xml"))
+	if err != nil {
+		t.Fatalf("ParseJATS: %v", err)
+	}
+	if doc.Title != "Metformin in fragile X syndrome: a pilot trial" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	if doc.License != "open-access" || doc.LicenseURL != "https://creativecommons.org/licenses/by/4.0/" {
+		t.Errorf("license = %q %q", doc.License, doc.LicenseURL)
+	}
+	if !doc.HasBody {
+		t.Error("expected a body")
+	}
+
+	want := `# Metformin in fragile X syndrome: a pilot trial
+
+## Abstract
+
+### Background
+
+Fragile X syndrome has no approved therapy.
+
+### Results
+
+Metformin was well tolerated.
+
+## Introduction
+
+Fragile X syndrome (FXS) is the most common inherited cause of intellectual disability [1].
+
+## Methods
+
+### Participants
+
+We enrolled 24 participants.
+`
+	if got := doc.Text(); got != want {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, want)
+	}
+	for _, unwanted := range []string{"Graphical", "Figure", "cell", "Cited work", "Reviewer"} {
+		if strings.Contains(doc.Text(), unwanted) {
+			t.Errorf("Text() should not contain %q", unwanted)
+		}
+	}
+}
+
+func TestParseJATS_FrontMatterOnly(t *testing.T) {
+	doc, err := ParseJATS([]byte(`<pmc-articleset><article><front><article-meta>
+<title-group><article-title>Restricted</article-title></title-group>
+</article-meta></front></article></pmc-articleset>`))
+	if err != nil {
+		t.Fatalf("ParseJATS: %v", err)
+	}
+	if doc.HasBody || doc.Title != "Restricted" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+
+	if _, err := ParseJATS([]byte(`<pmc-articleset></pmc-articleset>`)); err == nil {
+		t.Error("expected error for an empty article set")
+	}
+}
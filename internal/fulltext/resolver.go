@@ -0,0 +1,58 @@
+package fulltext
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// EnvResolver sets the institutional link resolver or proxy URL template
+// used when no open-access PDF is found.
+const EnvResolver = "PUBMED_CLI_RESOLVER"
+
+// SourceResolver marks a PDF link built from the resolver template.
+const SourceResolver = "resolver"
+
+// ResolverURL fills a resolver template for a. {doi}, {pmid}, and {pmcid}
+// are replaced by the query-escaped IDs, and {url} by the article's
+// https://doi.org link as is, which is what EZproxy-style "login?url="
+// prefixes expect. It returns "" when the template names an ID a lacks.
+func ResolverURL(template string, a eutils.Article) string {
+	var doiURL string
+	if a.DOI != "" {
+		doiURL = "https://doi.org/" + a.DOI
+	}
+	fields := []struct{ name, value string }{
+		{"{doi}", url.QueryEscape(a.DOI)},
+		{"{pmid}", url.QueryEscape(a.PMID)},
+		{"{pmcid}", url.QueryEscape(a.PMCID)},
+		{"{url}", doiURL},
+	}
+	u := template
+	for _, f := range fields {
+		if !strings.Contains(u, f.name) {
+			continue
+		}
+		if f.value == "" {
+			return ""
+		}
+		u = strings.ReplaceAll(u, f.name, f.value)
+	}
+	return u
+}
+
+// ResolvePDF finds a PDF for a: from the PMC Open Access Subset, then
+// Unpaywall, then the Resolver template, whose link only works with the
+// access the user's institution provides.
+func (c *Client) ResolvePDF(ctx context.Context, a eutils.Article) (Result, error) {
+	r, err := c.Resolve(ctx, a, true)
+	if err != nil || r.Found() || c.Resolver == "" {
+		return r, err
+	}
+	if u := ResolverURL(c.Resolver, a); u != "" {
+		r.Source, r.Format, r.URL = SourceResolver, "pdf", u
+	}
+	return r, nil
+}
@@ -0,0 +1,59 @@
+package fulltext
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestResolverURL(t *testing.T) {
+	a := eutils.Article{PMID: "38000001", DOI: "10.1000/a(b)"}
+	tests := []struct {
+		template, want string
+	}{
+		{"https://ezproxy.example.edu/login?url={url}", "https://ezproxy.example.edu/login?url=https://doi.org/10.1000/a(b)"},
+		{"https://resolver.example.edu/openurl?id=doi:{doi}&pmid={pmid}", "https://resolver.example.edu/openurl?id=doi:10.1000%2Fa%28b%29&pmid=38000001"},
+		{"https://resolver.example.edu/?pmcid={pmcid}", ""},
+	}
+	for _, tt := range tests {
+		if got := ResolverURL(tt.template, a); got != tt.want {
+			t.Errorf("ResolverURL(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestResolvePDF(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	// An open-access PDF wins over the resolver.
+	c.Resolver = "https://proxy.example.edu/login?url={url}"
+	r, err := c.ResolvePDF(ctx, eutils.Article{PMID: "4", DOI: "10.1000/green"})
+	if err != nil || r.Source != SourceUnpaywall {
+		t.Fatalf("expected the Unpaywall PDF, got %+v (%v)", r, err)
+	}
+
+	r, err = c.ResolvePDF(ctx, eutils.Article{PMID: "3", DOI: "10.1000/closed"})
+	if err != nil {
+		t.Fatalf("ResolvePDF: %v", err)
+	}
+	if r.Source != SourceResolver || r.Format != "pdf" || r.URL != "https://proxy.example.edu/login?url=https://doi.org/10.1000/closed" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	c.Resolver = ""
+	if r, err := c.ResolvePDF(ctx, eutils.Article{PMID: "3", DOI: "10.1000/closed"}); err != nil || r.Found() {
+		t.Errorf("expected nothing without a resolver, got %+v (%v)", r, err)
+	}
+}
+
+func TestSave_NotPDF(t *testing.T) {
+	c := newTestClient(t)
+	r := Result{PMID: "3", Source: SourceResolver, Format: "pdf", URL: c.BaseURL + "/login"}
+	err := c.Save(context.Background(), &r, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "text/html") || r.Path != "" {
+		t.Errorf("expected a sign-in page to be refused, got %v (path %q)", err, r.Path)
+	}
+}
@@ -0,0 +1,164 @@
+// Package graph builds citation graphs by walking PubMed cited-by and
+// reference links outward from a seed article.
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Directions accepted by Options.Direction.
+const (
+	DirectionBoth       = "both"
+	DirectionCitedBy    = "cited-by"
+	DirectionReferences = "references"
+)
+
+// DefaultMaxNodes bounds a walk; citation neighborhoods grow quickly.
+const DefaultMaxNodes = 500
+
+// Linker is the subset of the E-utilities client the walk needs.
+type Linker interface {
+	CitedBy(ctx context.Context, pmid string) (*eutils.LinkResult, error)
+	References(ctx context.Context, pmid string) (*eutils.LinkResult, error)
+}
+
+// Options controls how far a walk reaches.
+type Options struct {
+	Depth     int    // link hops from the seed (minimum 1)
+	Direction string // DirectionBoth, DirectionCitedBy, or DirectionReferences
+	PerNode   int    // links followed per article in each direction (0 for all)
+	MaxNodes  int    // stop adding articles beyond this many (0 for DefaultMaxNodes)
+}
+
+// Node is an article in the graph. Title and Year are filled by Annotate.
+type Node struct {
+	PMID  string `json:"pmid"`
+	Title string `json:"title,omitempty"`
+	Year  string `json:"year,omitempty"`
+	Depth int    `json:"depth"`
+}
+
+// Edge records that From cites To.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is a citation graph rooted at Seed.
+type Graph struct {
+	Seed      string `json:"seed"`
+	Nodes     []Node `json:"nodes"`
+	Edges     []Edge `json:"edges"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// Build walks links breadth-first from seed. Every edge points from the
+// citing article to the cited one regardless of the direction walked.
+// Truncated is set when MaxNodes stopped the walk early.
+func Build(ctx context.Context, l Linker, seed string, opts Options) (*Graph, error) {
+	if opts.Depth < 1 {
+		opts.Depth = 1
+	}
+	if opts.MaxNodes <= 0 {
+		opts.MaxNodes = DefaultMaxNodes
+	}
+	switch opts.Direction {
+	case "":
+		opts.Direction = DirectionBoth
+	case DirectionBoth, DirectionCitedBy, DirectionReferences:
+	default:
+		return nil, fmt.Errorf("unknown direction %q", opts.Direction)
+	}
+
+	g := &Graph{Seed: seed, Nodes: []Node{{PMID: seed}}}
+	index := map[string]int{seed: 0}
+	edges := make(map[Edge]bool)
+
+	// follow adds a linked article and the edge to it, queueing articles
+	// not seen before for the next hop. Once the graph is full, links to
+	// new articles are dropped but edges between known ones are kept.
+	var next []string
+	follow := func(id string, depth int, e Edge) {
+		if _, ok := index[id]; !ok {
+			if len(g.Nodes) >= opts.MaxNodes {
+				g.Truncated = true
+				return
+			}
+			index[id] = len(g.Nodes)
+			g.Nodes = append(g.Nodes, Node{PMID: id, Depth: depth})
+			next = append(next, id)
+		}
+		if !edges[e] {
+			edges[e] = true
+			g.Edges = append(g.Edges, e)
+		}
+	}
+
+	frontier := []string{seed}
+	for depth := 1; depth <= opts.Depth && len(frontier) > 0; depth++ {
+		next = nil
+		for _, pmid := range frontier {
+			if opts.Direction != DirectionReferences {
+				res, err := l.CitedBy(ctx, pmid)
+				if err != nil {
+					return nil, fmt.Errorf("cited-by lookup for %s: %w", pmid, err)
+				}
+				for _, id := range limitLinks(res, opts.PerNode) {
+					follow(id, depth, Edge{From: id, To: pmid})
+				}
+			}
+			if opts.Direction != DirectionCitedBy {
+				res, err := l.References(ctx, pmid)
+				if err != nil {
+					return nil, fmt.Errorf("references lookup for %s: %w", pmid, err)
+				}
+				for _, id := range limitLinks(res, opts.PerNode) {
+					follow(id, depth, Edge{From: pmid, To: id})
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return g, nil
+}
+
+func limitLinks(res *eutils.LinkResult, n int) []string {
+	if res == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(res.Links))
+	for _, l := range res.Links {
+		if n > 0 && len(ids) >= n {
+			break
+		}
+		ids = append(ids, l.ID)
+	}
+	return ids
+}
+
+// PMIDs returns the PMID of every node, seed first.
+func (g *Graph) PMIDs() []string {
+	ids := make([]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[i] = n.PMID
+	}
+	return ids
+}
+
+// Annotate copies titles and years from fetched articles onto the nodes.
+func (g *Graph) Annotate(articles []eutils.Article) {
+	byPMID := make(map[string]eutils.Article, len(articles))
+	for _, a := range articles {
+		byPMID[a.PMID] = a
+	}
+	for i := range g.Nodes {
+		if a, ok := byPMID[g.Nodes[i].PMID]; ok {
+			g.Nodes[i].Title = a.Title
+			g.Nodes[i].Year = a.Year
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// fakeLinker serves a fixed citation network: citedBy[x] cite x, refs[x]
+// are cited by x.
+type fakeLinker struct {
+	citedBy map[string][]string
+	refs    map[string][]string
+}
+
+func links(source string, ids []string) *eutils.LinkResult {
+	res := &eutils.LinkResult{SourceID: source}
+	for _, id := range ids {
+		res.Links = append(res.Links, eutils.LinkItem{ID: id})
+	}
+	return res
+}
+
+func (f fakeLinker) CitedBy(_ context.Context, pmid string) (*eutils.LinkResult, error) {
+	return links(pmid, f.citedBy[pmid]), nil
+}
+
+func (f fakeLinker) References(_ context.Context, pmid string) (*eutils.LinkResult, error) {
+	return links(pmid, f.refs[pmid]), nil
+}
+
+var network = fakeLinker{
+	citedBy: map[string][]string{"1": {"2", "3"}, "2": {"4"}},
+	refs:    map[string][]string{"1": {"5"}, "3": {"5"}},
+}
+
+func TestBuild_DepthOne(t *testing.T) {
+	g, err := Build(context.Background(), network, "1", Options{Depth: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(g.Nodes) != 4 {
+		t.Fatalf("expected seed + 3 neighbors, got %+v", g.Nodes)
+	}
+	want := map[Edge]bool{{From: "2", To: "1"}: true, {From: "3", To: "1"}: true, {From: "1", To: "5"}: true}
+	if len(g.Edges) != len(want) {
+		t.Fatalf("expected %d edges, got %+v", len(want), g.Edges)
+	}
+	for _, e := range g.Edges {
+		if !want[e] {
+			t.Errorf("unexpected edge %+v", e)
+		}
+	}
+}
+
+func TestBuild_DepthTwoKeepsEdgesBetweenKnownNodes(t *testing.T) {
+	g, err := Build(context.Background(), network, "1", Options{Depth: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(g.Nodes) != 5 {
+		t.Fatalf("expected 5 nodes, got %+v", g.Nodes)
+	}
+	var sawRef, sawDeep bool
+	for _, e := range g.Edges {
+		if e == (Edge{From: "3", To: "5"}) {
+			sawRef = true
+		}
+		if e == (Edge{From: "4", To: "2"}) {
+			sawDeep = true
+		}
+	}
+	if !sawRef || !sawDeep {
+		t.Errorf("expected edges 3->5 and 4->2, got %+v", g.Edges)
+	}
+	for _, n := range g.Nodes {
+		if n.PMID == "4" && n.Depth != 2 {
+			t.Errorf("expected PMID 4 at depth 2, got %d", n.Depth)
+		}
+	}
+}
+
+func TestBuild_DirectionAndLimits(t *testing.T) {
+	g, err := Build(context.Background(), network, "1", Options{Depth: 2, Direction: DirectionCitedBy, MaxNodes: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(g.Nodes) != 3 || !g.Truncated {
+		t.Fatalf("expected 3 nodes and truncation, got %+v (truncated=%v)", g.Nodes, g.Truncated)
+	}
+	for _, n := range g.Nodes {
+		if n.PMID == "5" {
+			t.Error("cited-by walk should not follow references")
+		}
+	}
+
+	if _, err := Build(context.Background(), network, "1", Options{Direction: "sideways"}); err == nil {
+		t.Error("expected unknown direction to be rejected")
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	g := &Graph{Seed: "1", Nodes: []Node{{PMID: "1"}, {PMID: "2"}}}
+	g.Annotate([]eutils.Article{{PMID: "2", Title: "Second", Year: "2021"}})
+	if g.Nodes[1].Title != "Second" || g.Nodes[1].Year != "2021" {
+		t.Errorf("expected annotated node, got %+v", g.Nodes[1])
+	}
+	if g.Nodes[0].Title != "" {
+		t.Errorf("expected seed without metadata, got %+v", g.Nodes[0])
+	}
+}
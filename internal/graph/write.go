@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats accepted by Write.
+const (
+	FormatDOT     = "dot"
+	FormatGraphML = "graphml"
+)
+
+// FormatForPath picks a format from a file extension, defaulting to DOT.
+func FormatForPath(path string) string {
+	if strings.HasSuffix(strings.ToLower(path), ".graphml") {
+		return FormatGraphML
+	}
+	return FormatDOT
+}
+
+// Write serializes g in the given format.
+func Write(w io.Writer, g *Graph, format string) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	default:
+		return fmt.Errorf("unknown graph format %q (use dot or graphml)", format)
+	}
+}
+
+// WriteDOT writes g as a Graphviz digraph. Nodes are labeled with year and
+// a shortened title; the seed is drawn bold.
+func WriteDOT(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph citations {\n")
+	fmt.Fprintf(bw, "  rankdir=LR;\n  node [shape=box, fontsize=10];\n")
+	for _, n := range g.Nodes {
+		attrs := []string{
+			"label=" + dotQuote(nodeLabel(n)),
+			"pmid=" + dotQuote(n.PMID),
+			"year=" + dotQuote(n.Year),
+			"title=" + dotQuote(n.Title),
+			"depth=" + strconv.Itoa(n.Depth),
+		}
+		if n.PMID == g.Seed {
+			attrs = append(attrs, "style=bold")
+		}
+		fmt.Fprintf(bw, "  %s [%s];\n", dotQuote(n.PMID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
+
+func nodeLabel(n Node) string {
+	label := "PMID " + n.PMID
+	if n.Year != "" {
+		label += " (" + n.Year + ")"
+	}
+	if n.Title != "" {
+		title := []rune(n.Title)
+		if len(title) > 60 {
+			title = append(title[:57], []rune("...")...)
+		}
+		label += "\n" + string(title)
+	}
+	return label
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// WriteGraphML writes g as GraphML with pmid, title, year, and depth node
+// attributes, which Gephi and yEd import directly.
+func WriteGraphML(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	bw.WriteString(`  <key id="title" for="node" attr.name="title" attr.type="string"/>` + "\n")
+	bw.WriteString(`  <key id="year" for="node" attr.name="year" attr.type="string"/>` + "\n")
+	bw.WriteString(`  <key id="depth" for="node" attr.name="depth" attr.type="int"/>` + "\n")
+	bw.WriteString(`  <key id="seed" for="node" attr.name="seed" attr.type="boolean"/>` + "\n")
+	bw.WriteString(`  <graph id="citations" edgedefault="directed">` + "\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", xmlEscape(n.PMID))
+		fmt.Fprintf(bw, "      <data key=\"title\">%s</data>\n", xmlEscape(n.Title))
+		fmt.Fprintf(bw, "      <data key=\"year\">%s</data>\n", xmlEscape(n.Year))
+		fmt.Fprintf(bw, "      <data key=\"depth\">%d</data>\n", n.Depth)
+		fmt.Fprintf(bw, "      <data key=\"seed\">%t</data>\n", n.PMID == g.Seed)
+		bw.WriteString("    </node>\n")
+	}
+	for i, e := range g.Edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\"/>\n", i, xmlEscape(e.From), xmlEscape(e.To))
+	}
+	bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+var sample = &Graph{
+	Seed: "1",
+	Nodes: []Node{
+		{PMID: "1", Title: `A "quoted" title`, Year: "2020"},
+		{PMID: "2", Title: "Fragile X & autism", Year: "2022", Depth: 1},
+	},
+	Edges: []Edge{{From: "2", To: "1"}},
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, sample); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"digraph citations {",
+		`"1" [label="PMID 1 (2020)\nA \"quoted\" title"`,
+		`year="2022"`,
+		"style=bold",
+		`"2" -> "1";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected DOT to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, sample); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 {
+		t.Fatalf("expected 2 nodes and 1 edge, got %+v", doc.Graph)
+	}
+	if doc.Graph.Nodes[1].Data[0].Value != "Fragile X & autism" {
+		t.Errorf("expected title round-trip, got %q", doc.Graph.Nodes[1].Data[0].Value)
+	}
+	if e := doc.Graph.Edges[0]; e.Source != "2" || e.Target != "1" {
+		t.Errorf("unexpected edge %+v", e)
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	if FormatForPath("out/graph.GraphML") != FormatGraphML {
+		t.Error("expected .graphml to select GraphML")
+	}
+	if FormatForPath("graph.dot") != FormatDOT || FormatForPath("") != FormatDOT {
+		t.Error("expected DOT by default")
+	}
+}
@@ -0,0 +1,110 @@
+// Package history records the searches pubmed-cli has run so they can be
+// listed, rerun, and compared later.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// EnvDisable turns off history recording when set to a non-empty value.
+const EnvDisable = "PUBMED_CLI_NO_HISTORY"
+
+// Entry is one executed search.
+type Entry struct {
+	ID          int                  `json:"id"`
+	Time        time.Time            `json:"time"`
+	Query       string               `json:"query"`
+	Translation string               `json:"query_translation,omitempty"`
+	Options     eutils.SearchOptions `json:"options"`
+	Count       int                  `json:"count"`
+	// IDs are the PMIDs the search retrieved (up to its limit).
+	IDs []string `json:"ids"`
+}
+
+// Load reads the entries saved as JSON lines at path, as releases before
+// the user database kept them, oldest first; a missing file is an empty
+// history.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("parsing %s line %d: %w", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	return entries, nil
+}
+
+// Find returns the entry numbered id.
+func Find(entries []Entry, id int) (Entry, error) {
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("no history entry #%d (see pubmed history list)", id)
+}
+
+// Diff compares two runs of a search.
+type Diff struct {
+	From Entry `json:"from"`
+	To   Entry `json:"to"`
+	// Added and Removed are the PMIDs retrieved by only one of the runs.
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Common  int      `json:"common"`
+}
+
+// TranslationChanged reports whether PubMed translated the queries differently.
+func (d Diff) TranslationChanged() bool {
+	return d.From.Translation != d.To.Translation
+}
+
+// Compare diffs the PMIDs retrieved by from and to, keeping each run's order.
+func Compare(from, to Entry) Diff {
+	d := Diff{From: from, To: to, Added: []string{}, Removed: []string{}}
+	inFrom := make(map[string]bool, len(from.IDs))
+	for _, id := range from.IDs {
+		inFrom[id] = true
+	}
+	inTo := make(map[string]bool, len(to.IDs))
+	for _, id := range to.IDs {
+		inTo[id] = true
+		if inFrom[id] {
+			d.Common++
+		} else {
+			d.Added = append(d.Added, id)
+		}
+	}
+	for _, id := range from.IDs {
+		if !inTo[id] {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+	return d
+}
@@ -0,0 +1,65 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	entries, err := Load(filepath.Join(dir, "missing.jsonl"))
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("missing file should load as empty: %v, %v", entries, err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	first := Entry{ID: 1, Time: now, Query: "autism", Count: 3, IDs: []string{"1", "2", "3"},
+		Options: eutils.SearchOptions{Limit: 3, Sort: "date"}}
+	second := Entry{ID: 2, Time: now, Query: "fragile x", Count: 0}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range []Entry{first, second} {
+		if err := enc.Encode(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf.WriteString("\n") // blank lines are skipped
+	path := filepath.Join(dir, "history.jsonl")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Options.Sort != "date" || !slices.Equal(entries[0].IDs, first.IDs) {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if e, err := Find(entries, 2); err != nil || e.Query != "fragile x" {
+		t.Errorf("Find(2) = %+v, %v", e, err)
+	}
+	if _, err := Find(entries, 9); err == nil {
+		t.Error("expected error for unknown entry")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	from := Entry{ID: 1, Translation: "a", IDs: []string{"5", "4", "3"}}
+	to := Entry{ID: 2, Translation: "b", IDs: []string{"7", "5", "3"}}
+
+	d := Compare(from, to)
+	if !slices.Equal(d.Added, []string{"7"}) || !slices.Equal(d.Removed, []string{"4"}) || d.Common != 2 {
+		t.Errorf("unexpected diff: %+v", d)
+	}
+	if !d.TranslationChanged() {
+		t.Error("expected translation change")
+	}
+}
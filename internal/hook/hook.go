@@ -0,0 +1,121 @@
+// Package hook runs the post-processing commands a user configures, for
+// site-specific workflows: an output hook the printed results are piped
+// through before they are written, and an export hook run on each export
+// file once it is complete. Hooks run under the shell (sh -c, or cmd /C on
+// Windows), so they may use arguments, pipes, and environment variables.
+package hook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Environment variables holding the hook commands, which the output_hook
+// and export_hook config settings also set.
+const (
+	EnvOutput = "PUBMED_CLI_OUTPUT_HOOK"
+	EnvExport = "PUBMED_CLI_EXPORT_HOOK"
+)
+
+// Environment variables a hook runs with: the pubmed command that ran
+// (such as "search" or "alert run") and, for an export hook, the file and
+// its format (csv, tsv, xlsx, ris, bibtex, csl-json, json, or ndjson).
+const (
+	EnvCommand = "PUBMED_CLI_COMMAND"
+	EnvFile    = "PUBMED_CLI_FILE"
+	EnvFormat  = "PUBMED_CLI_FORMAT"
+)
+
+// shell returns the command line running command under goos's shell.
+func shell(goos, command string) []string {
+	if goos == "windows" {
+		return []string{"cmd", "/C", command}
+	}
+	return []string{"sh", "-c", command}
+}
+
+func newCmd(ctx context.Context, command string, env []string) *exec.Cmd {
+	argv := shell(runtime.GOOS, command)
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
+
+// Filter runs command with in as its standard input, copying its standard
+// output to out and its standard error to stderr. env holds KEY=value
+// variables added to the environment.
+func Filter(ctx context.Context, command string, env []string, in io.Reader, out, stderr io.Writer) error {
+	cmd := newCmd(ctx, command, env)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q: %w", command, err)
+	}
+	return nil
+}
+
+// Writer streams what is written to it through an output hook as it
+// arrives, so a long export reaches its destination while it is still
+// being written rather than all at the end. The hook starts with the first
+// write; a Writer nothing is written to never runs it.
+type Writer struct {
+	ctx     context.Context
+	command string
+	env     []string
+	out     io.Writer
+	stderr  io.Writer
+
+	pw   *io.PipeWriter
+	done chan error
+}
+
+// NewWriter returns a Writer piping its input through command to out, as
+// Filter does.
+func NewWriter(ctx context.Context, command string, env []string, out, stderr io.Writer) *Writer {
+	return &Writer{ctx: ctx, command: command, env: env, out: out, stderr: stderr}
+}
+
+// Write passes p to the hook's standard input, starting the hook first if
+// this is the first write.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.pw == nil {
+		pr, pw := io.Pipe()
+		w.pw, w.done = pw, make(chan error, 1)
+		go func() {
+			err := Filter(w.ctx, w.command, w.env, pr, w.out, w.stderr)
+			// A hook that exits without reading everything (head, say)
+			// must not leave the command blocked writing to it.
+			io.Copy(io.Discard, pr)
+			w.done <- err
+		}()
+	}
+	return w.pw.Write(p)
+}
+
+// Close ends the hook's input and waits for it to finish, returning its
+// error.
+func (w *Writer) Close() error {
+	if w.pw == nil {
+		return nil
+	}
+	w.pw.Close()
+	w.pw = nil
+	return <-w.done
+}
+
+// Run runs command with env added to the environment. Anything it prints
+// goes to w, keeping it apart from the results on standard output.
+func Run(ctx context.Context, command string, env []string, w io.Writer) error {
+	cmd := newCmd(ctx, command, env)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShell(t *testing.T) {
+	if got, want := shell("linux", "jq ."), []string{"sh", "-c", "jq ."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shell(linux) = %q, want %q", got, want)
+	}
+	if got, want := shell("windows", "jq ."), []string{"cmd", "/C", "jq ."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shell(windows) = %q, want %q", got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	var out, errOut bytes.Buffer
+	err := Filter(context.Background(), `tr a-z A-Z; echo "$PUBMED_CLI_COMMAND" >&2`,
+		[]string{EnvCommand + "=search"}, strings.NewReader("pmid 123\n"), &out, &errOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "PMID 123\n" {
+		t.Errorf("output = %q", out.String())
+	}
+	if errOut.String() != "search\n" {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+
+	err = Filter(context.Background(), "exit 3", nil, strings.NewReader(""), &out, &errOut)
+	if err == nil || !strings.Contains(err.Error(), `hook "exit 3"`) {
+		t.Errorf("failing hook: err = %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	path := filepath.Join(t.TempDir(), "refs.ris")
+	if err := os.WriteFile(path, []byte("TY  - JOUR\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var w bytes.Buffer
+	err := Run(context.Background(), `echo "$PUBMED_CLI_FORMAT"; echo "ER  -" >> "$PUBMED_CLI_FILE"`,
+		[]string{EnvFile + "=" + path, EnvFormat + "=ris"}, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.String() != "ris\n" {
+		t.Errorf("printed %q", w.String())
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "TY  - JOUR\nER  -\n" {
+		t.Errorf("file = %q", data)
+	}
+}
+
+// lineWriter reports each write on a channel.
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestWriter_Streams(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := make(lineWriter, 10)
+	w := NewWriter(context.Background(), "cat", nil, out, io.Discard)
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The line comes through before the input is closed.
+	select {
+	case got := <-out:
+		if got != "first\n" {
+			t.Errorf("output = %q", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("no output before Close")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriter_HookExitsEarly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	var out bytes.Buffer
+	w := NewWriter(context.Background(), "head -n 1", nil, &out, io.Discard)
+	line := []byte(strings.Repeat("x", 1023) + "\n")
+	for range 1000 {
+		if _, err := w.Write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != string(line) {
+		t.Errorf("output = %d bytes, want one line", out.Len())
+	}
+}
+
+func TestWriter_Unused(t *testing.T) {
+	w := NewWriter(context.Background(), "exit 1", nil, io.Discard, io.Discard)
+	if err := w.Close(); err != nil {
+		t.Errorf("hook ran with no output: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package i18n
+
+// spanish is the Spanish catalog.
+var spanish = map[string]string{
+	// Search
+	"No results found.":                 "No se encontraron resultados.",
+	"Found %d results":                  "%d resultados",
+	" (showing %d)":                     " (se muestran %d)",
+	"Query:":                            "Consulta:",
+	"Title":                             "Título",
+	"Year":                              "Año",
+	"Type":                              "Tipo",
+	"Score":                             "Puntuación",
+	"Consider adding:":                  "Considere añadir:",
+	"(major topic of %d of the top %d)": "(tema principal de %d de los primeros %d)",
+	"Use --csv output.csv to export":    "Use --csv salida.csv para exportar",
+
+	// Articles
+	"No articles found.":                 "No se encontraron artículos.",
+	"Source: %s (not in PubMed)":         "Fuente: %s (no está en PubMed)",
+	"Authors:":                           "Autores:",
+	"Book:":                              "Libro:",
+	"Journal:":                           "Revista:",
+	"DOI:":                               "DOI:",
+	"Type:":                              "Tipo:",
+	"Cited by:":                          "Citado por:",
+	"Influential citations:":             "Citas influyentes:",
+	"iCite:":                             "iCite:",
+	"Concepts:":                          "Conceptos:",
+	"Institutions:":                      "Instituciones:",
+	"Open access:":                       "Acceso abierto:",
+	"License:":                           "Licencia:",
+	"References:":                        "Referencias:",
+	"From Crossref:":                     "De Crossref:",
+	"MeSH:":                              "MeSH:",
+	"Tags:":                              "Etiquetas:",
+	"Note:":                              "Nota:",
+	"TLDR:":                              "En resumen:",
+	"Abstract:":                          "Resumen:",
+	"[use --full for complete abstract]": "[use --full para ver el resumen completo]",
+
+	// Links
+	"Cited By":                              "Citado por",
+	"References":                            "Referencias",
+	"Related Articles":                      "Artículos relacionados",
+	"No %s results for PMID %s.":            "Sin resultados de %s para el PMID %s.",
+	"%s for PMID %s (%d results)":           "%s del PMID %s (%d resultados)",
+	"%s for PMID %s (%d total, showing %d)": "%s del PMID %s (%d en total, se muestran %d)",
+	"(not found)":                           "(no encontrado)",
+
+	// MeSH
+	"Supplementary Concept, mapped to:": "Concepto suplementario, asignado a:",
+	"Tree Numbers:":                     "Números de árbol:",
+	"Scope Note:":                       "Nota de alcance:",
+	"Synonyms:":                         "Sinónimos:",
+	"Pharmacological Actions:":          "Acciones farmacológicas:",
+	"Annotation:":                       "Anotación:",
+	"No tree positions.":                "Sin posiciones en el árbol.",
+	"No MeSH terms match %s":            "Ningún término MeSH coincide con %s",
+	"MeSH terms matching %s":            "Términos MeSH que coinciden con %s",
+}
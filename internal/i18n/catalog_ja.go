@@ -0,0 +1,61 @@
+package i18n
+
+// japanese is the Japanese catalog.
+var japanese = map[string]string{
+	// Search
+	"No results found.":                 "結果が見つかりませんでした。",
+	"Found %d results":                  "%d 件の結果",
+	" (showing %d)":                     "（%d 件を表示）",
+	"Query:":                            "クエリ:",
+	"Title":                             "タイトル",
+	"Year":                              "年",
+	"Type":                              "種類",
+	"Score":                             "スコア",
+	"Consider adding:":                  "追加の候補:",
+	"(major topic of %d of the top %d)": "（上位%[2]d件中%[1]d件の主要トピック）",
+	"Use --csv output.csv to export":    "--csv output.csv でエクスポートできます",
+
+	// Articles
+	"No articles found.":                 "論文が見つかりませんでした。",
+	"Source: %s (not in PubMed)":         "出典: %s（PubMed 未収録）",
+	"Authors:":                           "著者:",
+	"Book:":                              "書籍:",
+	"Journal:":                           "雑誌:",
+	"DOI:":                               "DOI:",
+	"Type:":                              "種類:",
+	"Cited by:":                          "被引用数:",
+	"Influential citations:":             "影響力のある引用:",
+	"iCite:":                             "iCite:",
+	"Concepts:":                          "概念:",
+	"Institutions:":                      "所属機関:",
+	"Open access:":                       "オープンアクセス:",
+	"License:":                           "ライセンス:",
+	"References:":                        "参考文献数:",
+	"From Crossref:":                     "Crossref から補完:",
+	"MeSH:":                              "MeSH:",
+	"Tags:":                              "タグ:",
+	"Note:":                              "メモ:",
+	"TLDR:":                              "要点:",
+	"Abstract:":                          "抄録:",
+	"[use --full for complete abstract]": "[抄録全文は --full で表示]",
+
+	// Links
+	"Cited By":                              "被引用",
+	"References":                            "参考文献",
+	"Related Articles":                      "関連論文",
+	"No %s results for PMID %s.":            "PMID %[2]s の %[1]s の結果はありません。",
+	"%s for PMID %s (%d results)":           "PMID %[2]s の%[1]s（%[3]d 件）",
+	"%s for PMID %s (%d total, showing %d)": "PMID %[2]s の%[1]s（全 %[3]d 件中 %[4]d 件を表示）",
+	"(not found)":                           "（見つかりません）",
+
+	// MeSH
+	"Supplementary Concept, mapped to:": "補足概念（対応する見出し語）:",
+	"Tree Numbers:":                     "ツリー番号:",
+	"Scope Note:":                       "スコープノート:",
+	"Synonyms:":                         "同義語:",
+	"Pharmacological Actions:":          "薬理作用:",
+	"Annotation:":                       "注記:",
+	"No tree positions.":                "ツリー上の位置はありません。",
+	"No MeSH terms match %s":            "%s に一致する MeSH 用語はありません",
+	"MeSH terms matching %s":            "%s に一致する MeSH 用語",
+}
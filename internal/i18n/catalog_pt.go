@@ -0,0 +1,61 @@
+package i18n
+
+// portuguese is the Portuguese catalog.
+var portuguese = map[string]string{
+	// Search
+	"No results found.":                 "Nenhum resultado encontrado.",
+	"Found %d results":                  "%d resultados",
+	" (showing %d)":                     " (exibindo %d)",
+	"Query:":                            "Consulta:",
+	"Title":                             "Título",
+	"Year":                              "Ano",
+	"Type":                              "Tipo",
+	"Score":                             "Pontuação",
+	"Consider adding:":                  "Considere adicionar:",
+	"(major topic of %d of the top %d)": "(tema principal de %d dos primeiros %d)",
+	"Use --csv output.csv to export":    "Use --csv saida.csv para exportar",
+
+	// Articles
+	"No articles found.":                 "Nenhum artigo encontrado.",
+	"Source: %s (not in PubMed)":         "Fonte: %s (fora do PubMed)",
+	"Authors:":                           "Autores:",
+	"Book:":                              "Livro:",
+	"Journal:":                           "Periódico:",
+	"DOI:":                               "DOI:",
+	"Type:":                              "Tipo:",
+	"Cited by:":                          "Citado por:",
+	"Influential citations:":             "Citações influentes:",
+	"iCite:":                             "iCite:",
+	"Concepts:":                          "Conceitos:",
+	"Institutions:":                      "Instituições:",
+	"Open access:":                       "Acesso aberto:",
+	"License:":                           "Licença:",
+	"References:":                        "Referências:",
+	"From Crossref:":                     "Do Crossref:",
+	"MeSH:":                              "MeSH:",
+	"Tags:":                              "Etiquetas:",
+	"Note:":                              "Nota:",
+	"TLDR:":                              "Em resumo:",
+	"Abstract:":                          "Resumo:",
+	"[use --full for complete abstract]": "[use --full para ver o resumo completo]",
+
+	// Links
+	"Cited By":                              "Citado por",
+	"References":                            "Referências",
+	"Related Articles":                      "Artigos relacionados",
+	"No %s results for PMID %s.":            "Nenhum resultado de %s para o PMID %s.",
+	"%s for PMID %s (%d results)":           "%s do PMID %s (%d resultados)",
+	"%s for PMID %s (%d total, showing %d)": "%s do PMID %s (%d no total, exibindo %d)",
+	"(not found)":                           "(não encontrado)",
+
+	// MeSH
+	"Supplementary Concept, mapped to:": "Conceito suplementar, mapeado para:",
+	"Tree Numbers:":                     "Números da árvore:",
+	"Scope Note:":                       "Nota de escopo:",
+	"Synonyms:":                         "Sinônimos:",
+	"Pharmacological Actions:":          "Ações farmacológicas:",
+	"Annotation:":                       "Anotação:",
+	"No tree positions.":                "Sem posições na árvore.",
+	"No MeSH terms match %s":            "Nenhum termo MeSH corresponde a %s",
+	"MeSH terms matching %s":            "Termos MeSH correspondentes a %s",
+}
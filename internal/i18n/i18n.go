@@ -0,0 +1,87 @@
+// Package i18n translates the labels and messages of --human search,
+// fetch, link, MeSH, and full-text results, as rendered by internal/output;
+// the rest of the CLI's text is English only. The English text is the
+// message ID, in the manner of gettext: T returns a message's translation
+// in the selected language, or the message itself when the catalog has
+// none, so untranslated output stays readable.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Languages lists the supported languages, English first.
+var Languages = []string{"en", "es", "pt", "ja"}
+
+// catalogs maps a language to its translations, keyed by the English text.
+var catalogs = map[string]map[string]string{
+	"es": spanish,
+	"pt": portuguese,
+	"ja": japanese,
+}
+
+// lang is the selected language; "en" needs no catalog.
+var lang = "en"
+
+// Set selects a language by code or locale name, such as "es" or
+// "pt_BR.UTF-8". An empty name selects the language of the environment.
+func Set(name string) error {
+	if name == "" {
+		lang = FromEnv()
+		return nil
+	}
+	code := Normalize(name)
+	if code != "en" && catalogs[code] == nil {
+		return fmt.Errorf("unsupported language %q (available: %s)", name, strings.Join(Languages, ", "))
+	}
+	lang = code
+	return nil
+}
+
+// Language returns the selected language code.
+func Language() string {
+	return lang
+}
+
+// FromEnv returns the supported language named by LC_ALL, LC_MESSAGES, or
+// LANG, in that order of precedence, or "en".
+func FromEnv() string {
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if name := os.Getenv(v); name != "" {
+			if code := Normalize(name); catalogs[code] != nil {
+				return code
+			}
+			return "en"
+		}
+	}
+	return "en"
+}
+
+// Normalize reduces a locale name such as "pt_BR.UTF-8" or "ja-JP" to its
+// language code.
+func Normalize(name string) string {
+	name = strings.ToLower(name)
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "c" || name == "posix" {
+		return "en"
+	}
+	return name
+}
+
+// T returns the translation of msg.
+func T(msg string) string {
+	if t, ok := catalogs[lang][msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format. Translations may reorder the
+// arguments with explicit indexes such as %[2]s.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
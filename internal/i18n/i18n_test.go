@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+var verbRe = regexp.MustCompile(`%(?:\[(\d+)\])?([a-z])`)
+
+// verbs maps each argument position of a format to its verb.
+func verbs(format string) map[int]string {
+	out := make(map[int]string)
+	next := 1
+	for _, m := range verbRe.FindAllStringSubmatch(format, -1) {
+		if m[1] != "" {
+			next, _ = strconv.Atoi(m[1])
+		}
+		out[next] = m[2]
+		next++
+	}
+	return out
+}
+
+func TestCatalogs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for msg, tr := range catalog {
+			want, got := verbs(msg), verbs(tr)
+			if len(want) != len(got) {
+				t.Errorf("%s: %q uses %v, translation %q uses %v", lang, msg, want, tr, got)
+				continue
+			}
+			for i, v := range want {
+				if got[i] != v {
+					t.Errorf("%s: %q argument %d is %%%s, translation %q has %%%s", lang, msg, i, v, tr, got[i])
+				}
+			}
+		}
+		for msg := range spanish {
+			if _, ok := catalog[msg]; !ok {
+				t.Errorf("%s: missing %q", lang, msg)
+			}
+		}
+	}
+}
+
+func TestSetAndTranslate(t *testing.T) {
+	defer Set("en")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+	Set("")
+	if Language() != "pt" {
+		t.Errorf("LANG=pt_BR.UTF-8 selected %q", Language())
+	}
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	Set("")
+	if Language() != "en" {
+		t.Errorf("an unsupported LC_ALL should fall back to English, got %q", Language())
+	}
+
+	if err := Set("ja-JP"); err != nil {
+		t.Fatal(err)
+	}
+	if got := Sprintf("%s for PMID %s (%d results)", "関連論文", "123", 4); got != "PMID 123 の関連論文（4 件）" {
+		t.Errorf("Sprintf = %q", got)
+	}
+	if got := T("Not in any catalog"); got != "Not in any catalog" {
+		t.Errorf("untranslated message changed to %q", got)
+	}
+	if err := Set("de"); err == nil {
+		t.Error("expected an error for an unsupported --lang")
+	}
+}
@@ -0,0 +1,114 @@
+// Package icite adds NIH iCite citation metrics to PubMed records: the
+// Relative Citation Ratio (RCR), its NIH percentile, and the Approximate
+// Potential to Translate (APT) score.
+package icite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// DefaultURL is the iCite API.
+const DefaultURL = "https://icite.od.nih.gov/api"
+
+// BatchSize is the most PMIDs one iCite request may name.
+const BatchSize = 1000
+
+// Client queries the iCite API. Requests share the NCBI base client's
+// HTTP client, rate limit, and response size cap.
+type Client struct {
+	*ncbi.BaseClient
+	URL string
+}
+
+// NewClient creates an iCite client using an existing NCBI base client.
+func NewClient(base *ncbi.BaseClient) *Client {
+	return &Client{BaseClient: base, URL: DefaultURL}
+}
+
+// Pub is the part of an iCite publication record pubmed-cli uses. iCite
+// has no RCR or percentile for papers too recent to have a citation rate.
+type Pub struct {
+	PMID                  int      `json:"pmid"`
+	RelativeCitationRatio *float64 `json:"relative_citation_ratio"`
+	NIHPercentile         *float64 `json:"nih_percentile"`
+	APT                   *float64 `json:"apt"`
+	CitationCount         int      `json:"citation_count"`
+}
+
+// Pubs looks up pmids in iCite, BatchSize at a time, and returns the
+// records it has indexed by PMID.
+func (c *Client) Pubs(ctx context.Context, pmids []string) (map[string]Pub, error) {
+	pubs := make(map[string]Pub, len(pmids))
+	for start := 0; start < len(pmids); start += BatchSize {
+		end := min(start+BatchSize, len(pmids))
+		params := url.Values{}
+		params.Set("pmids", strings.Join(pmids[start:end], ","))
+		params.Set("fl", "pmid,relative_citation_ratio,nih_percentile,apt,citation_count")
+		body, err := c.GetURL(ctx, c.URL+"/pubs?"+params.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("iCite lookup failed: %w", err)
+		}
+		if body == nil {
+			continue
+		}
+		var resp struct {
+			Data []Pub `json:"data"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("parsing iCite response: %w", err)
+		}
+		for _, p := range resp.Data {
+			pubs[strconv.Itoa(p.PMID)] = p
+		}
+	}
+	return pubs, nil
+}
+
+// Enrich sets a's RCR, NIH percentile, and APT score from p.
+func Enrich(a *eutils.Article, p Pub) {
+	a.RCR = p.RelativeCitationRatio
+	a.NIHPercentile = p.NIHPercentile
+	a.APT = p.APT
+}
+
+// Annotate enriches each article iCite knows. Articles it does not know
+// are left unchanged.
+func (c *Client) Annotate(ctx context.Context, articles []eutils.Article) error {
+	pmids := make([]string, 0, len(articles))
+	for _, a := range articles {
+		if a.PMID != "" {
+			pmids = append(pmids, a.PMID)
+		}
+	}
+	pubs, err := c.Pubs(ctx, pmids)
+	if err != nil {
+		return err
+	}
+	for i := range articles {
+		if p, ok := pubs[articles[i].PMID]; ok {
+			Enrich(&articles[i], p)
+		}
+	}
+	return nil
+}
+
+// SortByRCR orders pmids by Relative Citation Ratio, highest first. PMIDs
+// without an RCR sort last; ties keep their original order.
+func SortByRCR(pmids []string, pubs map[string]Pub) {
+	sort.SliceStable(pmids, func(i, j int) bool {
+		ri, rj := pubs[pmids[i]].RelativeCitationRatio, pubs[pmids[j]].RelativeCitationRatio
+		if (ri != nil) != (rj != nil) {
+			return ri != nil
+		}
+		return ri != nil && *ri > *rj
+	})
+}
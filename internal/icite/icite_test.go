@@ -0,0 +1,63 @@
+package icite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	pubs, err := os.ReadFile(filepath.Join("..", "..", "testdata", "icite_pubs.json"))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pubs" || r.URL.Query().Get("pmids") != "38000001,38000002,38000003" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write(pubs)
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient(ncbi.NewBaseClient(ncbi.WithHTTPClient(srv.Client())))
+	c.URL = srv.URL
+	return c
+}
+
+func TestAnnotate(t *testing.T) {
+	c := newTestClient(t)
+	articles := []eutils.Article{{PMID: "38000001"}, {PMID: "38000002"}, {PMID: "38000003"}}
+	if err := c.Annotate(context.Background(), articles); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a := articles[0]
+	if a.RCR == nil || *a.RCR != 1.96 || a.NIHPercentile == nil || *a.NIHPercentile != 72.4 || a.APT == nil || *a.APT != 0.75 {
+		t.Errorf("unexpected enrichment: %+v", a)
+	}
+	if articles[1].RCR != nil || articles[1].APT != nil {
+		t.Errorf("unknown paper should be unchanged: %+v", articles[1])
+	}
+	if articles[2].RCR != nil || articles[2].NIHPercentile != nil || articles[2].APT == nil {
+		t.Errorf("recent paper should have an APT but no RCR: %+v", articles[2])
+	}
+}
+
+func TestSortByRCR(t *testing.T) {
+	c := newTestClient(t)
+	pubs, err := c.Pubs(context.Background(), []string{"38000001", "38000002", "38000003"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pmids := []string{"38000003", "38000002", "38000001"}
+	SortByRCR(pmids, pubs)
+	if got := strings.Join(pmids, ","); got != "38000001,38000003,38000002" {
+		t.Errorf("expected scored papers first, ties in order; got %s", got)
+	}
+}
@@ -0,0 +1,273 @@
+// Package journal looks up journals in the NLM Catalog via NCBI E-utilities.
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// Journal is an NLM Catalog serial record.
+type Journal struct {
+	NLMID     string `json:"nlm_id"`
+	Title     string `json:"title"`
+	MedlineTA string `json:"medline_ta,omitempty"`
+	ISOAbbrev string `json:"iso_abbreviation,omitempty"`
+	ISSNs     []ISSN `json:"issns,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Country   string `json:"country,omitempty"`
+	// CurrentlyIndexed reports whether MEDLINE currently indexes the journal.
+	CurrentlyIndexed bool `json:"currently_indexed"`
+	// Counts holds recent PubMed article counts per year, when requested.
+	Counts []analyze.YearCount `json:"recent_counts,omitempty"`
+}
+
+// ISSN is one of a journal's ISSNs with its medium (Print, Electronic).
+type ISSN struct {
+	ISSN string `json:"issn"`
+	Type string `json:"type,omitempty"`
+}
+
+// PubMedClause returns a PubMed query clause selecting the journal's
+// articles, preferring its MEDLINE abbreviation over an ISSN.
+func (j Journal) PubMedClause() string {
+	if j.MedlineTA != "" {
+		return strconv.Quote(j.MedlineTA) + "[ta]"
+	}
+	if len(j.ISSNs) > 0 {
+		return strconv.Quote(j.ISSNs[0].ISSN) + "[is]"
+	}
+	return ""
+}
+
+// Matches reports whether term names the journal exactly: its title,
+// MEDLINE or ISO abbreviation, or an ISSN, ignoring case and a trailing period.
+func (j Journal) Matches(term string) bool {
+	t := normalize(term)
+	for _, name := range []string{j.Title, j.MedlineTA, j.ISOAbbrev} {
+		if name != "" && normalize(name) == t {
+			return true
+		}
+	}
+	for _, is := range j.ISSNs {
+		if strings.EqualFold(is.ISSN, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(s), "."))
+}
+
+// issnRe matches an ISSN such as 1866-1947 or 0028-484X.
+var issnRe = regexp.MustCompile(`^\d{4}-?\d{3}[\dXx]$`)
+
+// IsISSN reports whether s looks like an ISSN.
+func IsISSN(s string) bool {
+	return issnRe.MatchString(strings.TrimSpace(s))
+}
+
+// Client provides NLM Catalog lookups.
+// It embeds ncbi.BaseClient for shared rate limiting and common parameters.
+type Client struct {
+	*ncbi.BaseClient
+}
+
+// NewClient creates an NLM Catalog client using an existing NCBI base client.
+func NewClient(base *ncbi.BaseClient) *Client {
+	return &Client{BaseClient: base}
+}
+
+// Lookup resolves a journal name, abbreviation, or ISSN to at most limit
+// NLM Catalog journals, exact matches first.
+func (c *Client) Lookup(ctx context.Context, term string, limit int) ([]Journal, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, fmt.Errorf("journal name cannot be empty")
+	}
+	ids, err := c.search(ctx, catalogQuery(term), limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	journals, err := c.summaries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	indexed, err := c.search(ctx, "("+uidClause(ids)+") AND currentlyindexed[All]", len(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range indexed {
+		for i := range journals {
+			if journals[i].NLMID == id {
+				journals[i].CurrentlyIndexed = true
+			}
+		}
+	}
+	sort.SliceStable(journals, func(a, b int) bool {
+		return journals[a].Matches(term) && !journals[b].Matches(term)
+	})
+	return journals, nil
+}
+
+// catalogQuery builds the NLM Catalog search for a journal name or ISSN,
+// limited to journals cited in NCBI databases.
+func catalogQuery(term string) string {
+	if IsISSN(term) {
+		return strconv.Quote(term) + "[issn]"
+	}
+	q := strconv.Quote(term)
+	return fmt.Sprintf("(%s[ta] OR %s[ti]) AND ncbijournals[All]", q, q)
+}
+
+func uidClause(ids []string) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = id + "[uid]"
+	}
+	return strings.Join(parts, " OR ")
+}
+
+type searchResponse struct {
+	Result struct {
+		IDList []string `json:"idlist"`
+	} `json:"esearchresult"`
+}
+
+func (c *Client) search(ctx context.Context, term string, limit int) ([]string, error) {
+	params := url.Values{}
+	params.Set("db", "nlmcatalog")
+	params.Set("term", term)
+	params.Set("retmode", "json")
+	params.Set("retmax", strconv.Itoa(limit))
+
+	body, err := c.DoGet(ctx, "esearch.fcgi", params)
+	if err != nil {
+		return nil, fmt.Errorf("NLM Catalog search failed: %w", err)
+	}
+	var resp searchResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing NLM Catalog search response: %w", err)
+	}
+	return resp.Result.IDList, nil
+}
+
+// esummaryResponse wraps the JSON returned by esummary.fcgi for nlmcatalog.
+type esummaryResponse struct {
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// esummaryRecord holds the fields we need from one NLM Catalog summary.
+type esummaryRecord struct {
+	UID         string `json:"uid"`
+	NLMUniqueID string `json:"nlmuniqueid"`
+	TitleMain   []struct {
+		Title string `json:"title"`
+	} `json:"titlemainlist"`
+	MedlineTA string `json:"medlineta"`
+	ISOAbbrev string `json:"isoabbreviation"`
+	ISSNList  []struct {
+		ISSN string `json:"issn"`
+		Type string `json:"issntype"`
+	} `json:"issnlist"`
+	PublicationInfo []struct {
+		Publisher string `json:"publisher"`
+	} `json:"publicationinfolist"`
+	Country string `json:"country"`
+}
+
+// summaries fetches the records for ids, in the order given.
+func (c *Client) summaries(ctx context.Context, ids []string) ([]Journal, error) {
+	params := url.Values{}
+	params.Set("db", "nlmcatalog")
+	params.Set("id", strings.Join(ids, ","))
+	params.Set("retmode", "json")
+
+	body, err := c.DoGet(ctx, "esummary.fcgi", params)
+	if err != nil {
+		return nil, fmt.Errorf("NLM Catalog fetch failed: %w", err)
+	}
+	var resp esummaryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing NLM Catalog summary: %w", err)
+	}
+
+	journals := make([]Journal, 0, len(ids))
+	for _, id := range ids {
+		raw, ok := resp.Result[id]
+		if !ok {
+			continue
+		}
+		var rec esummaryRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return nil, fmt.Errorf("parsing NLM Catalog record %s: %w", id, err)
+		}
+		journals = append(journals, journalFromSummary(id, rec))
+	}
+	return journals, nil
+}
+
+func journalFromSummary(id string, rec esummaryRecord) Journal {
+	j := Journal{
+		NLMID:     rec.NLMUniqueID,
+		MedlineTA: rec.MedlineTA,
+		ISOAbbrev: rec.ISOAbbrev,
+		Country:   rec.Country,
+	}
+	if j.NLMID == "" {
+		j.NLMID = id
+	}
+	if len(rec.TitleMain) > 0 {
+		j.Title = strings.TrimSuffix(strings.TrimSpace(rec.TitleMain[0].Title), ".")
+	}
+	for _, is := range rec.ISSNList {
+		if is.ISSN != "" {
+			j.ISSNs = append(j.ISSNs, ISSN{ISSN: is.ISSN, Type: is.Type})
+		}
+	}
+	if len(rec.PublicationInfo) > 0 {
+		j.Publisher = strings.TrimSpace(rec.PublicationInfo[0].Publisher)
+	}
+	return j
+}
+
+// Check is the result of validating a journal name used in a query.
+type Check struct {
+	Term string `json:"term"`
+	// Journal is the best NLM Catalog match, or nil when there is none.
+	Journal *Journal `json:"journal,omitempty"`
+	// Exact is set when Term is the journal's title, abbreviation, or ISSN.
+	Exact bool `json:"exact"`
+}
+
+// CheckTerms looks up each journal term, reporting its best match.
+func (c *Client) CheckTerms(ctx context.Context, terms []string) ([]Check, error) {
+	checks := make([]Check, 0, len(terms))
+	for _, term := range terms {
+		journals, err := c.Lookup(ctx, term, 5)
+		if err != nil {
+			return nil, err
+		}
+		ch := Check{Term: term}
+		if len(journals) > 0 {
+			j := journals[0]
+			ch.Journal = &j
+			ch.Exact = j.Matches(term)
+		}
+		checks = append(checks, ch)
+	}
+	return checks, nil
+}
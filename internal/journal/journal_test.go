@@ -0,0 +1,147 @@
+package journal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+func loadTestdata(t *testing.T, filename string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", filename))
+	if err != nil {
+		t.Fatalf("failed to load testdata/%s: %v", filename, err)
+	}
+	return data
+}
+
+// newCatalogServer serves nlmcatalog searches: the currentlyindexed check
+// returns indexed, every other search returns ids.
+func newCatalogServer(t *testing.T, ids, indexed []string, terms *[]string) *Client {
+	t.Helper()
+	summary := loadTestdata(t, "nlmcatalog_esummary.json")
+	idList := func(ids []string) string {
+		if len(ids) == 0 {
+			return `{"esearchresult":{"idlist":[]}}`
+		}
+		return `{"esearchresult":{"idlist":["` + strings.Join(ids, `","`) + `"]}}`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("db"); got != "nlmcatalog" {
+			t.Errorf("expected db=nlmcatalog, got %q", got)
+		}
+		switch r.URL.Path {
+		case "/esearch.fcgi":
+			term := q.Get("term")
+			if terms != nil {
+				*terms = append(*terms, term)
+			}
+			if strings.Contains(term, "currentlyindexed") {
+				w.Write([]byte(idList(indexed)))
+				return
+			}
+			w.Write([]byte(idList(ids)))
+		case "/esummary.fcgi":
+			w.Write(summary)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(ncbi.NewBaseClient(ncbi.WithBaseURL(srv.URL), ncbi.WithAPIKey("test-key")))
+}
+
+func TestLookup(t *testing.T) {
+	var terms []string
+	c := newCatalogServer(t, []string{"9918284782506676", "101483832"}, []string{"101483832"}, &terms)
+
+	journals, err := c.Lookup(context.Background(), "J Neurodev Disord", 5)
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if len(journals) != 2 {
+		t.Fatalf("expected 2 journals, got %d", len(journals))
+	}
+	j := journals[0]
+	if j.NLMID != "101483832" {
+		t.Fatalf("expected the exact match first, got %s", j.NLMID)
+	}
+	if j.Title != "Journal of neurodevelopmental disorders" || j.MedlineTA != "J Neurodev Disord" ||
+		j.Publisher != "BioMed Central" || j.Country != "England" {
+		t.Errorf("unexpected journal: %+v", j)
+	}
+	if len(j.ISSNs) != 2 || j.ISSNs[1] != (ISSN{ISSN: "1866-1955", Type: "Electronic"}) {
+		t.Errorf("unexpected ISSNs: %+v", j.ISSNs)
+	}
+	if !j.CurrentlyIndexed || journals[1].CurrentlyIndexed {
+		t.Errorf("indexing status not applied: %v %v", j.CurrentlyIndexed, journals[1].CurrentlyIndexed)
+	}
+	if want := `("J Neurodev Disord"[ta] OR "J Neurodev Disord"[ti]) AND ncbijournals[All]`; terms[0] != want {
+		t.Errorf("search term = %q, want %q", terms[0], want)
+	}
+	if want := `(9918284782506676[uid] OR 101483832[uid]) AND currentlyindexed[All]`; terms[1] != want {
+		t.Errorf("indexing term = %q, want %q", terms[1], want)
+	}
+}
+
+func TestLookup_ISSN(t *testing.T) {
+	var terms []string
+	c := newCatalogServer(t, nil, nil, &terms)
+	journals, err := c.Lookup(context.Background(), "1866-1955", 5)
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if len(journals) != 0 {
+		t.Errorf("expected no journals, got %d", len(journals))
+	}
+	if len(terms) != 1 || terms[0] != `"1866-1955"[issn]` {
+		t.Errorf("unexpected searches: %q", terms)
+	}
+
+	if _, err := c.Lookup(context.Background(), " ", 5); err == nil {
+		t.Error("expected an empty name to be rejected")
+	}
+}
+
+func TestCheckTerms(t *testing.T) {
+	c := newCatalogServer(t, []string{"101483832"}, []string{"101483832"}, nil)
+	checks, err := c.CheckTerms(context.Background(), []string{"1866-1947", "J Neurodev"})
+	if err != nil {
+		t.Fatalf("CheckTerms: %v", err)
+	}
+	if !checks[0].Exact || checks[0].Journal == nil {
+		t.Errorf("ISSN should match exactly: %+v", checks[0])
+	}
+	if checks[1].Exact || checks[1].Journal == nil || checks[1].Journal.MedlineTA != "J Neurodev Disord" {
+		t.Errorf("partial name should report the closest journal: %+v", checks[1])
+	}
+}
+
+func TestJournalHelpers(t *testing.T) {
+	j := Journal{Title: "Journal of neurodevelopmental disorders", MedlineTA: "J Neurodev Disord",
+		ISSNs: []ISSN{{ISSN: "1866-1947"}}}
+	if got := j.PubMedClause(); got != `"J Neurodev Disord"[ta]` {
+		t.Errorf("PubMedClause = %q", got)
+	}
+	if got := (Journal{ISSNs: j.ISSNs}).PubMedClause(); got != `"1866-1947"[is]` {
+		t.Errorf("PubMedClause without TA = %q", got)
+	}
+	for _, term := range []string{"journal of neurodevelopmental disorders.", "j neurodev disord", "1866-1947"} {
+		if !j.Matches(term) {
+			t.Errorf("expected %q to match", term)
+		}
+	}
+	if j.Matches("J Neurodev") {
+		t.Error("expected a partial name not to match")
+	}
+	if !IsISSN("0028-484X") || IsISSN("Nature") {
+		t.Error("IsISSN misclassified")
+	}
+}
@@ -0,0 +1,165 @@
+// Package keychain stores secrets such as API keys in the platform's
+// credential store through its command-line tools: security(1) on macOS and
+// secret-tool (libsecret) on Linux and the BSDs.
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Service is the name secrets are stored under.
+const Service = "pubmed-cli"
+
+// ErrNotFound is returned by Get when no secret is stored for an account.
+var ErrNotFound = errors.New("no secret stored in the keychain")
+
+// command is one credential-store invocation: its argv and, for stores that
+// read the secret from stdin, the input.
+type command struct {
+	argv  []string
+	stdin string
+}
+
+// getCommand returns the command that prints account's secret on goos.
+func getCommand(goos, account string) (command, error) {
+	switch goos {
+	case "darwin":
+		return command{argv: []string{"security", "find-generic-password", "-s", Service, "-a", account, "-w"}}, nil
+	case "windows":
+		return command{}, fmt.Errorf("the Windows credential store is not supported")
+	}
+	return command{argv: []string{"secret-tool", "lookup", "service", Service, "account", account}}, nil
+}
+
+// setCommand returns the command that stores secret for account on goos,
+// replacing any existing one. The secret goes on stdin, never in argv
+// where other users' ps could see it.
+func setCommand(goos, account, secret string) (command, error) {
+	switch goos {
+	case "darwin":
+		// A bare -w, last, makes security prompt for the password and then
+		// for it again.
+		return command{
+			argv:  []string{"security", "add-generic-password", "-U", "-s", Service, "-a", account, "-w"},
+			stdin: secret + "\n" + secret + "\n",
+		}, nil
+	case "windows":
+		return command{}, fmt.Errorf("the Windows credential store is not supported")
+	}
+	return command{
+		argv:  []string{"secret-tool", "store", "--label", Service + " " + account, "service", Service, "account", account},
+		stdin: secret,
+	}, nil
+}
+
+// deleteCommand returns the command that removes account's secret on goos.
+func deleteCommand(goos, account string) (command, error) {
+	switch goos {
+	case "darwin":
+		return command{argv: []string{"security", "delete-generic-password", "-s", Service, "-a", account}}, nil
+	case "windows":
+		return command{}, fmt.Errorf("the Windows credential store is not supported")
+	}
+	return command{argv: []string{"secret-tool", "clear", "service", Service, "account", account}}, nil
+}
+
+// Get returns the secret stored for account, or ErrNotFound.
+func Get(ctx context.Context, account string) (string, error) {
+	c, err := getCommand(runtime.GOOS, account)
+	if err != nil {
+		return "", err
+	}
+	out, err := run(ctx, c)
+	if err != nil {
+		if isNotFound(runtime.GOOS, err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	secret := strings.TrimSpace(out)
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set stores secret for account.
+func Set(ctx context.Context, account, secret string) error {
+	c, err := setCommand(runtime.GOOS, account, secret)
+	if err != nil {
+		return err
+	}
+	_, err = run(ctx, c)
+	return err
+}
+
+// securityItemNotFound is security(1)'s exit status for a missing item
+// (errSecItemNotFound).
+const securityItemNotFound = 44
+
+// isNotFound reports whether err, from a keychain tool on goos, means only
+// that no secret is stored: security exits 44, and secret-tool exits 1
+// without a message. Other failures, such as a locked keychain or denied
+// access, are reported as they are.
+func isNotFound(goos string, err error) bool {
+	var terr *toolError
+	var exit *exec.ExitError
+	if !errors.As(err, &terr) || !errors.As(err, &exit) {
+		return false
+	}
+	if goos == "darwin" {
+		return exit.ExitCode() == securityItemNotFound
+	}
+	return exit.ExitCode() == 1 && terr.msg == ""
+}
+
+// toolError is a keychain tool's failure and what it printed to stderr.
+type toolError struct {
+	tool string
+	err  error
+	msg  string
+}
+
+func (e *toolError) Error() string {
+	if e.msg != "" {
+		return fmt.Sprintf("%s failed: %v: %s", e.tool, e.err, e.msg)
+	}
+	return fmt.Sprintf("%s failed: %v", e.tool, e.err)
+}
+
+func (e *toolError) Unwrap() error { return e.err }
+
+func run(ctx context.Context, c command) (string, error) {
+	path, err := exec.LookPath(c.argv[0])
+	if err != nil {
+		return "", fmt.Errorf("keychain tool %s not found: %w", c.argv[0], err)
+	}
+	cmd := exec.CommandContext(ctx, path, c.argv[1:]...)
+	cmd.Stdin = strings.NewReader(c.stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", &toolError{tool: c.argv[0], err: err, msg: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.String(), nil
+}
+
+// Delete removes the secret stored for account. Removing one that is not
+// stored is not an error.
+func Delete(ctx context.Context, account string) error {
+	c, err := deleteCommand(runtime.GOOS, account)
+	if err != nil {
+		return err
+	}
+	_, err = run(ctx, c)
+	if runtime.GOOS == "darwin" && isNotFound(runtime.GOOS, err) {
+		return nil // security exits 44 when there is nothing to delete
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package keychain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		goos, get, set, stdin, del string
+	}{
+		{"darwin",
+			"security find-generic-password -s pubmed-cli -a zotero -w",
+			"security add-generic-password -U -s pubmed-cli -a zotero -w", "s3cret\ns3cret\n",
+			"security delete-generic-password -s pubmed-cli -a zotero"},
+		{"linux",
+			"secret-tool lookup service pubmed-cli account zotero",
+			"secret-tool store --label pubmed-cli zotero service pubmed-cli account zotero", "s3cret",
+			"secret-tool clear service pubmed-cli account zotero"},
+	}
+	for _, tt := range tests {
+		get, err := getCommand(tt.goos, "zotero")
+		if err != nil || strings.Join(get.argv, " ") != tt.get {
+			t.Errorf("%s get: %v, %v", tt.goos, get.argv, err)
+		}
+		set, err := setCommand(tt.goos, "zotero", "s3cret")
+		if err != nil || strings.Join(set.argv, " ") != tt.set || set.stdin != tt.stdin {
+			t.Errorf("%s set: %v (stdin %q), %v", tt.goos, set.argv, set.stdin, err)
+		}
+		if strings.Contains(strings.Join(set.argv, " "), "s3cret") {
+			t.Errorf("%s set: the secret is on the command line: %v", tt.goos, set.argv)
+		}
+		del, err := deleteCommand(tt.goos, "zotero")
+		if err != nil || strings.Join(del.argv, " ") != tt.del {
+			t.Errorf("%s delete: %v, %v", tt.goos, del.argv, err)
+		}
+	}
+	if _, err := getCommand("windows", "zotero"); err == nil {
+		t.Error("expected Windows to be unsupported")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	exit := func(code int, msg string) error {
+		_, err := run(context.Background(), command{argv: []string{"sh", "-c", fmt.Sprintf("printf %%s %q >&2; exit %d", msg, code)}})
+		return err
+	}
+	tests := []struct {
+		goos string
+		err  error
+		want bool
+	}{
+		{"darwin", exit(44, "The specified item could not be found in the keychain."), true},
+		{"darwin", exit(51, "User interaction is not allowed."), false},
+		{"darwin", exit(1, ""), false},
+		{"linux", exit(1, ""), true},
+		{"linux", exit(1, "Cannot autolaunch D-Bus without X11 $DISPLAY"), false},
+		{"linux", errors.New("keychain tool secret-tool not found"), false},
+	}
+	for _, tt := range tests {
+		if got := isNotFound(tt.goos, tt.err); got != tt.want {
+			t.Errorf("isNotFound(%s, %v) = %t, want %t", tt.goos, tt.err, got, tt.want)
+		}
+	}
+}
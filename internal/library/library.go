@@ -0,0 +1,283 @@
+// Package library keeps named collections of articles across sessions,
+// storing each article's metadata once however many collections hold it.
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Collection is a named, ordered set of PMIDs.
+type Collection struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	PMIDs   []string  `json:"pmids"`
+}
+
+// Library is the set of collections. internal/store keeps it in the user
+// database.
+type Library struct {
+	Collections []*Collection `json:"collections"`
+	// Articles holds the metadata of every PMID in a collection.
+	Articles map[string]eutils.Article `json:"articles"`
+	// Annotations holds the notes and tags recorded per PMID, whether or
+	// not the article is in a collection.
+	Annotations map[string]*Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation is the user's notes and tags on one article.
+type Annotation struct {
+	Notes []Note   `json:"notes,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// Note is one free-text note.
+type Note struct {
+	Text  string    `json:"text"`
+	Added time.Time `json:"added"`
+}
+
+// New returns an empty library.
+func New() *Library {
+	return &Library{Articles: make(map[string]eutils.Article)}
+}
+
+// Load reads a library saved as JSON at path, as releases before the user
+// database kept it; a missing file is an empty library.
+func Load(path string) (*Library, error) {
+	l := New()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading library: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if l.Articles == nil {
+		l.Articles = make(map[string]eutils.Article)
+	}
+	return l, nil
+}
+
+// Get returns the named collection, or nil.
+func (l *Library) Get(name string) *Collection {
+	for _, c := range l.Collections {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Missing returns the PMIDs whose metadata the library does not hold yet.
+func (l *Library) Missing(pmids []string) []string {
+	var out []string
+	for _, id := range pmids {
+		if _, ok := l.Articles[id]; !ok {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Add appends articles to the named collection, creating it if needed,
+// and returns how many were not already in it. Stored metadata is
+// refreshed with the given articles.
+func (l *Library) Add(name string, articles []eutils.Article, now time.Time) (int, error) {
+	if name == "" {
+		return 0, fmt.Errorf("a collection needs a name")
+	}
+	c := l.Get(name)
+	if c == nil {
+		c = &Collection{Name: name, Created: now, PMIDs: []string{}}
+		l.Collections = append(l.Collections, c)
+		sort.Slice(l.Collections, func(i, j int) bool { return l.Collections[i].Name < l.Collections[j].Name })
+	}
+	in := make(map[string]bool, len(c.PMIDs))
+	for _, id := range c.PMIDs {
+		in[id] = true
+	}
+	added := 0
+	for _, a := range articles {
+		if a.PMID == "" {
+			continue
+		}
+		a.Notes, a.Tags = nil, nil // annotations live in Annotations
+		l.Articles[a.PMID] = a
+		if !in[a.PMID] {
+			in[a.PMID] = true
+			c.PMIDs = append(c.PMIDs, a.PMID)
+			added++
+		}
+	}
+	c.Updated = now
+	return added, nil
+}
+
+// Remove drops pmids from the named collection and returns how many it held.
+func (l *Library) Remove(name string, pmids []string, now time.Time) (int, error) {
+	c := l.Get(name)
+	if c == nil {
+		return 0, fmt.Errorf("no collection named %q", name)
+	}
+	drop := make(map[string]bool, len(pmids))
+	for _, id := range pmids {
+		drop[id] = true
+	}
+	kept := c.PMIDs[:0]
+	for _, id := range c.PMIDs {
+		if !drop[id] {
+			kept = append(kept, id)
+		}
+	}
+	removed := len(c.PMIDs) - len(kept)
+	c.PMIDs = kept
+	c.Updated = now
+	l.prune()
+	return removed, nil
+}
+
+// Delete removes the named collection.
+func (l *Library) Delete(name string) error {
+	for i, c := range l.Collections {
+		if c.Name == name {
+			l.Collections = append(l.Collections[:i], l.Collections[i+1:]...)
+			l.prune()
+			return nil
+		}
+	}
+	return fmt.Errorf("no collection named %q", name)
+}
+
+// CollectionArticles returns the named collection's articles in the order they
+// were added.
+func (l *Library) CollectionArticles(name string) ([]eutils.Article, error) {
+	c := l.Get(name)
+	if c == nil {
+		return nil, fmt.Errorf("no collection named %q (see pubmed lib list)", name)
+	}
+	articles := make([]eutils.Article, 0, len(c.PMIDs))
+	for _, id := range c.PMIDs {
+		a, ok := l.Articles[id]
+		if !ok {
+			a = eutils.Article{PMID: id}
+		}
+		articles = append(articles, a)
+	}
+	return articles, nil
+}
+
+// prune drops metadata no collection refers to.
+func (l *Library) prune() {
+	used := make(map[string]bool)
+	for _, c := range l.Collections {
+		for _, id := range c.PMIDs {
+			used[id] = true
+		}
+	}
+	for id := range l.Articles {
+		if !used[id] {
+			delete(l.Articles, id)
+		}
+	}
+}
+
+// AddNote appends a note to pmid.
+func (l *Library) AddNote(pmid, text string, now time.Time) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("note text cannot be empty")
+	}
+	a := l.annotation(pmid)
+	a.Notes = append(a.Notes, Note{Text: text, Added: now})
+	return nil
+}
+
+// ClearNotes deletes pmid's notes.
+func (l *Library) ClearNotes(pmid string) {
+	if a := l.Annotations[pmid]; a != nil {
+		a.Notes = nil
+		l.dropEmpty(pmid)
+	}
+}
+
+// Tag applies changes to pmid's tags: "+name" or "name" adds a tag and
+// "-name" removes it. Tags are kept sorted.
+func (l *Library) Tag(pmid string, changes []string) error {
+	a := l.annotation(pmid)
+	for _, c := range changes {
+		remove := strings.HasPrefix(c, "-")
+		name := strings.TrimSpace(strings.TrimLeft(c, "+-"))
+		if name == "" || strings.ContainsAny(name, " \t,") {
+			l.dropEmpty(pmid)
+			return fmt.Errorf("invalid tag %q: use +name or -name without spaces or commas", c)
+		}
+		i := slices.Index(a.Tags, name)
+		switch {
+		case remove && i >= 0:
+			a.Tags = slices.Delete(a.Tags, i, i+1)
+		case !remove && i < 0:
+			a.Tags = append(a.Tags, name)
+		}
+	}
+	sort.Strings(a.Tags)
+	l.dropEmpty(pmid)
+	return nil
+}
+
+// Tagged returns the PMIDs carrying tag, in PMID order.
+func (l *Library) Tagged(tag string) []string {
+	var out []string
+	for id, a := range l.Annotations {
+		if slices.Contains(a.Tags, tag) {
+			out = append(out, id)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Annotate copies the library's notes and tags onto articles.
+func (l *Library) Annotate(articles []eutils.Article) {
+	for i := range articles {
+		a := l.Annotations[articles[i].PMID]
+		if a == nil {
+			continue
+		}
+		articles[i].Notes = nil
+		for _, n := range a.Notes {
+			articles[i].Notes = append(articles[i].Notes, n.Text)
+		}
+		articles[i].Tags = slices.Clone(a.Tags)
+	}
+}
+
+func (l *Library) annotation(pmid string) *Annotation {
+	if l.Annotations == nil {
+		l.Annotations = make(map[string]*Annotation)
+	}
+	a := l.Annotations[pmid]
+	if a == nil {
+		a = &Annotation{}
+		l.Annotations[pmid] = a
+	}
+	return a
+}
+
+func (l *Library) dropEmpty(pmid string) {
+	if a := l.Annotations[pmid]; a != nil && len(a.Notes) == 0 && len(a.Tags) == 0 {
+		delete(l.Annotations, pmid)
+	}
+}
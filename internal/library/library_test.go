@@ -0,0 +1,127 @@
+package library
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestLibrary_AddLoad(t *testing.T) {
+	dir := t.TempDir()
+	if l, err := Load(filepath.Join(dir, "missing.json")); err != nil || l.Articles == nil {
+		t.Fatalf("missing file should load as empty: %+v, %v", l, err)
+	}
+	l := New()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	a1 := eutils.Article{PMID: "1", Title: "One"}
+	a2 := eutils.Article{PMID: "2", Title: "Two"}
+	if n, err := l.Add("fxs", []eutils.Article{a2, a1}, now); err != nil || n != 2 {
+		t.Fatalf("Add = %d, %v", n, err)
+	}
+	if n, _ := l.Add("fxs", []eutils.Article{a1}, now); n != 0 {
+		t.Errorf("re-adding should add nothing, added %d", n)
+	}
+	if _, err := l.Add("", []eutils.Article{a1}, now); err == nil {
+		t.Error("expected empty name to be rejected")
+	}
+	l.Add("asd", []eutils.Article{a1}, now)
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "library.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(loaded.Collections) != 2 || loaded.Collections[0].Name != "asd" {
+		t.Fatalf("expected collections sorted by name, got %+v", loaded.Collections)
+	}
+	articles, err := loaded.CollectionArticles("fxs")
+	if err != nil || len(articles) != 2 || articles[0].Title != "Two" {
+		t.Errorf("CollectionArticles = %+v, %v", articles, err)
+	}
+	if got := loaded.Missing([]string{"1", "3"}); !slices.Equal(got, []string{"3"}) {
+		t.Errorf("Missing = %v", got)
+	}
+}
+
+func TestLibrary_RemoveDelete(t *testing.T) {
+	l := New()
+	now := time.Now()
+	l.Add("a", []eutils.Article{{PMID: "1"}, {PMID: "2"}}, now)
+	l.Add("b", []eutils.Article{{PMID: "2"}}, now)
+
+	if n, err := l.Remove("a", []string{"2", "9"}, now); err != nil || n != 1 {
+		t.Fatalf("Remove = %d, %v", n, err)
+	}
+	if _, ok := l.Articles["2"]; !ok {
+		t.Error("metadata still used by b was dropped")
+	}
+	if err := l.Delete("b"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := l.Articles["2"]; ok {
+		t.Error("unreferenced metadata kept after delete")
+	}
+	if err := l.Delete("b"); err == nil {
+		t.Error("expected deleting a missing collection to fail")
+	}
+	if _, err := l.Remove("zzz", nil, now); err == nil {
+		t.Error("expected removing from a missing collection to fail")
+	}
+}
+
+func TestLibrary_NotesAndTags(t *testing.T) {
+	l := New()
+	now := time.Now()
+
+	if err := l.AddNote("1", "  ", now); err == nil {
+		t.Error("expected empty note to be rejected")
+	}
+	l.AddNote("1", "key trial", now)
+	if err := l.Tag("1", []string{"+rct", "key-paper", "+rct"}); err != nil {
+		t.Fatalf("Tag: %v", err)
+	}
+	if err := l.Tag("1", []string{"-rct", "-missing"}); err != nil {
+		t.Fatalf("Tag: %v", err)
+	}
+	if err := l.Tag("2", []string{"+two words"}); err == nil {
+		t.Error("expected tag with a space to be rejected")
+	}
+	if _, ok := l.Annotations["2"]; ok {
+		t.Error("rejected tag left an empty annotation")
+	}
+	if got := l.Tagged("key-paper"); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("Tagged = %v", got)
+	}
+
+	articles := []eutils.Article{{PMID: "1"}, {PMID: "3"}}
+	l.Annotate(articles)
+	if !slices.Equal(articles[0].Notes, []string{"key trial"}) || !slices.Equal(articles[0].Tags, []string{"key-paper"}) {
+		t.Errorf("annotated article = %+v", articles[0])
+	}
+	if articles[1].Notes != nil || articles[1].Tags != nil {
+		t.Errorf("unannotated article changed: %+v", articles[1])
+	}
+
+	l.Add("c", articles, now)
+	if a := l.Articles["1"]; a.Notes != nil || a.Tags != nil {
+		t.Error("annotations copied into stored metadata")
+	}
+	l.ClearNotes("1")
+	l.Tag("1", []string{"-key-paper"})
+	if len(l.Annotations) != 0 {
+		t.Errorf("expected no annotations left, got %+v", l.Annotations)
+	}
+}
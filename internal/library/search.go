@@ -0,0 +1,196 @@
+package library
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Field weights: a term in a title or among the subject headings says
+// more about an article than one in passing in the abstract.
+const (
+	weightTitle    = 3
+	weightSubjects = 2
+	weightNotes    = 2
+	weightAbstract = 1
+)
+
+// BM25 parameters, at their usual values.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Hit is one article matching a library search.
+type Hit struct {
+	Article eutils.Article
+	Score   float64
+}
+
+// Search ranks the stored articles, and any PMID with notes or tags,
+// against query by BM25 over titles, abstracts, MeSH terms, keywords,
+// notes, and tags. Every query word must match; a trailing * matches any
+// word with that prefix. Hits come best first.
+func (l *Library) Search(query string) []Hit {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	type doc struct {
+		article eutils.Article
+		tf      map[string]float64
+		length  float64
+	}
+	var docs []doc
+	add := func(a eutils.Article) {
+		d := doc{article: a, tf: make(map[string]float64)}
+		index := func(text string, weight float64) {
+			for _, w := range words(text) {
+				d.tf[w] += weight
+				d.length += weight
+			}
+		}
+		index(a.Title, weightTitle)
+		index(a.Abstract, weightAbstract)
+		for _, m := range a.MeSHTerms {
+			index(m.Descriptor, weightSubjects)
+		}
+		for _, k := range a.Keywords {
+			index(k, weightSubjects)
+		}
+		if ann := l.Annotations[a.PMID]; ann != nil {
+			for _, n := range ann.Notes {
+				index(n.Text, weightNotes)
+			}
+			for _, t := range ann.Tags {
+				index(t, weightNotes)
+			}
+		}
+		docs = append(docs, d)
+	}
+	for _, a := range l.Articles {
+		add(a)
+	}
+	for id := range l.Annotations {
+		if _, ok := l.Articles[id]; !ok {
+			add(eutils.Article{PMID: id})
+		}
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var total float64
+	for _, d := range docs {
+		total += d.length
+	}
+	avg := total / float64(len(docs))
+	n := float64(len(docs))
+
+	// matched[i][t] is doc i's weighted frequency of query term t.
+	matched := make([][]float64, len(docs))
+	df := make([]float64, len(terms))
+	for i, d := range docs {
+		matched[i] = make([]float64, len(terms))
+		for j, t := range terms {
+			for w, f := range d.tf {
+				if t.match(w) {
+					matched[i][j] += f
+				}
+			}
+			if matched[i][j] > 0 {
+				df[j]++
+			}
+		}
+	}
+
+	var hits []Hit
+	for i, d := range docs {
+		score := 0.0
+		for j := range terms {
+			f := matched[i][j]
+			if f == 0 {
+				score = -1
+				break
+			}
+			idf := math.Log(1 + (n-df[j]+0.5)/(df[j]+0.5))
+			score += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*d.length/avg))
+		}
+		if score > 0 {
+			hits = append(hits, Hit{Article: d.article, Score: score})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Article.PMID < hits[j].Article.PMID
+	})
+	return hits
+}
+
+// searchTerm is one query word, stemmed, or a prefix.
+type searchTerm struct {
+	word   string
+	prefix bool
+}
+
+func (t searchTerm) match(w string) bool {
+	if t.prefix {
+		return strings.HasPrefix(w, t.word)
+	}
+	return w == t.word
+}
+
+func searchTerms(query string) []searchTerm {
+	var terms []searchTerm
+	for _, f := range strings.Fields(strings.ToLower(query)) {
+		prefix := strings.HasSuffix(f, "*")
+		for _, w := range wordsRaw(f) {
+			if prefix {
+				terms = append(terms, searchTerm{word: w, prefix: true})
+			} else {
+				terms = append(terms, searchTerm{word: Stem(w)})
+			}
+		}
+	}
+	return terms
+}
+
+// words splits text into lowercased, stemmed words.
+func words(text string) []string {
+	ws := wordsRaw(strings.ToLower(text))
+	for i, w := range ws {
+		ws[i] = Stem(w)
+	}
+	return ws
+}
+
+func wordsRaw(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Stem strips common English inflections so "signaling", "signaled", and
+// "signals" all index as "signal". Words under four letters once stripped
+// are kept whole. The word must already be lowercase.
+func Stem(w string) string {
+	switch {
+	case strings.HasSuffix(w, "ss"), strings.HasSuffix(w, "us"), strings.HasSuffix(w, "is"):
+		return w // "mass", "virus", "analysis"
+	}
+	for _, suffix := range []string{"ies", "ing", "ed", "s"} {
+		if rest, ok := strings.CutSuffix(w, suffix); ok && len(rest) >= 4 {
+			if suffix == "ies" {
+				rest += "y"
+			}
+			return rest
+		}
+	}
+	return w
+}
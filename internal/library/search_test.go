@@ -0,0 +1,64 @@
+package library
+
+import (
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestLibrary_Search(t *testing.T) {
+	l := New()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	l.Add("set", []eutils.Article{
+		{PMID: "1", Title: "Calcium signaling in neurons", Abstract: "We study calcium."},
+		{PMID: "2", Title: "Synaptic plasticity", Abstract: "Calcium signals shape plasticity in cortical neurons."},
+		{PMID: "3", Title: "Metformin in fragile X syndrome", MeSHTerms: []eutils.MeSHTerm{{Descriptor: "Fragile X Syndrome"}}},
+		{PMID: "4", Title: "Unrelated", Abstract: "Nothing about the topic."},
+	}, now)
+	l.AddNote("4", "Compare with the calcium signalling review", now)
+	l.AddNote("99", "calcium signaling, read later", now)
+
+	ids := func(hits []Hit) []string {
+		var out []string
+		for _, h := range hits {
+			out = append(out, h.Article.PMID)
+		}
+		return out
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"calcium signaling", []string{"1", "99", "2"}},
+		{"CALCIUM", []string{"1", "99", "4", "2"}},
+		{"fragile x metformin", []string{"3"}},
+		{"plastic*", []string{"2"}},
+		{"calcium zebrafish", nil},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got := ids(l.Search(tt.query))
+		if len(got) != len(tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	for w, want := range map[string]string{
+		"signaling": "signal", "signaled": "signal", "signals": "signal",
+		"studies": "study", "analysis": "analysis", "mass": "mass", "uses": "uses", "x": "x",
+	} {
+		if got := Stem(w); got != want {
+			t.Errorf("Stem(%q) = %q, want %q", w, got, want)
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package mesh
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// localIndexFile is the prebuilt index written next to the downloaded descriptor file.
+const localIndexFile = "descriptors.gob"
+
+// DescriptorFileURL returns the NLM download URL for the ASCII MeSH
+// descriptor file of the given year (e.g. d2025.bin).
+func DescriptorFileURL(year int) string {
+	return fmt.Sprintf("https://nlmpubs.nlm.nih.gov/projects/mesh/MESH_FILES/asciimesh/d%d.bin", year)
+}
+
+// SupplementalFileURL returns the NLM download URL for the ASCII
+// Supplementary Concept Record file of the given year (e.g. c2025.bin).
+func SupplementalFileURL(year int) string {
+	return fmt.Sprintf("https://nlmpubs.nlm.nih.gov/projects/mesh/MESH_FILES/asciimesh/c%d.bin", year)
+}
+
+// ErrNoLocalDB reports that no downloaded MeSH database exists in a directory.
+var ErrNoLocalDB = errors.New("no local MeSH database")
+
+// LocalDB serves MeSH lookups and tree navigation from a downloaded
+// descriptor file without contacting NCBI.
+type LocalDB struct {
+	Source  string
+	Records []MeSHRecord
+
+	byUI     map[string]int
+	byTerm   map[string]int
+	byTree   map[string]int
+	children map[string][]string
+}
+
+// localSnapshot is the gob-encoded form of a LocalDB.
+type localSnapshot struct {
+	Source  string
+	Records []MeSHRecord
+}
+
+// DownloadLocalDB fetches each ASCII MeSH file in urls into dir, parses
+// them, and writes a combined lookup index alongside. List the descriptor
+// file first so descriptor names take precedence over SCR synonyms.
+func DownloadLocalDB(ctx context.Context, hc *http.Client, dir string, urls ...string) (*LocalDB, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no MeSH files to download")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating MeSH directory: %w", err)
+	}
+
+	var (
+		names   []string
+		records []MeSHRecord
+	)
+	for _, url := range urls {
+		dest, err := downloadFile(ctx, hc, url, dir)
+		if err != nil {
+			return nil, err
+		}
+		recs, err := parseFile(dest)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, filepath.Base(dest))
+		records = append(records, recs...)
+	}
+
+	db := NewLocalDB(strings.Join(names, ", "), records)
+	if err := db.save(filepath.Join(dir, localIndexFile)); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func downloadFile(ctx context.Context, hc *http.Client, url, dir string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("downloading MeSH file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("NLM returned HTTP %d for %s", resp.StatusCode, url)
+	}
+
+	dest := filepath.Join(dir, path.Base(url))
+	tmp := dest + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", fmt.Errorf("creating %s: %w", tmp, err)
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return "", fmt.Errorf("writing MeSH file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("writing MeSH file: %w", err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return "", fmt.Errorf("saving MeSH file: %w", err)
+	}
+	return dest, nil
+}
+
+func parseFile(p string) ([]MeSHRecord, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseASCII(f)
+}
+
+// OpenLocalDB loads the index previously written by DownloadLocalDB.
+// It returns ErrNoLocalDB if dir holds no index.
+func OpenLocalDB(dir string) (*LocalDB, error) {
+	f, err := os.Open(filepath.Join(dir, localIndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoLocalDB
+		}
+		return nil, fmt.Errorf("opening local MeSH database: %w", err)
+	}
+	defer f.Close()
+
+	var snap localSnapshot
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("reading local MeSH database: %w", err)
+	}
+	return NewLocalDB(snap.Source, snap.Records), nil
+}
+
+// NewLocalDB indexes records by UI, heading, entry term, and tree number.
+func NewLocalDB(source string, records []MeSHRecord) *LocalDB {
+	db := &LocalDB{
+		Source:   source,
+		Records:  records,
+		byUI:     make(map[string]int, len(records)),
+		byTerm:   make(map[string]int, len(records)*4),
+		byTree:   make(map[string]int, len(records)*2),
+		children: make(map[string][]string),
+	}
+	for i, r := range records {
+		db.byUI[strings.ToUpper(r.UI)] = i
+		db.byTerm[normalizeTerm(r.Name)] = i
+		for _, tn := range r.TreeNumbers {
+			db.byTree[tn] = i
+			if p := parentTreeNumber(tn); p != "" {
+				db.children[p] = append(db.children[p], tn)
+			}
+		}
+	}
+	// Entry terms never shadow a heading of the same name.
+	for i, r := range records {
+		for _, et := range r.EntryTerms {
+			key := normalizeTerm(et)
+			if _, ok := db.byTerm[key]; !ok {
+				db.byTerm[key] = i
+			}
+		}
+	}
+	for p := range db.children {
+		sort.Strings(db.children[p])
+	}
+	return db
+}
+
+func (db *LocalDB) save(p string) error {
+	tmp := p + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("creating local MeSH index: %w", err)
+	}
+	bw := bufio.NewWriter(f)
+	if err := gob.NewEncoder(bw).Encode(localSnapshot{Source: db.Source, Records: db.Records}); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("writing local MeSH index: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("writing local MeSH index: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing local MeSH index: %w", err)
+	}
+	return os.Rename(tmp, p)
+}
+
+// Lookup finds a descriptor by UI, heading, or entry term (case-insensitive).
+func (db *LocalDB) Lookup(term string) (*MeSHRecord, bool) {
+	i, ok := db.find(term)
+	if !ok {
+		return nil, false
+	}
+	r := db.Records[i]
+	return &r, true
+}
+
+// Tree returns every tree position of term with ancestors and descendants
+// to the given depth.
+func (db *LocalDB) Tree(term string, depth int) (*Hierarchy, bool) {
+	i, ok := db.find(term)
+	if !ok {
+		return nil, false
+	}
+	r := db.Records[i]
+	h := &Hierarchy{Record: &r}
+	for _, tn := range r.TreeNumbers {
+		p := TreePath{TreeNumber: tn}
+		for anc := parentTreeNumber(tn); anc != ""; anc = parentTreeNumber(anc) {
+			if node, ok := db.node(anc); ok {
+				p.Ancestors = append([]TreeNode{node}, p.Ancestors...)
+			}
+		}
+		p.Children = db.descendants(tn, depth)
+		h.Paths = append(h.Paths, p)
+	}
+	return h, true
+}
+
+// Children returns descriptors directly beneath term at any tree position.
+func (db *LocalDB) Children(term string) ([]MeSHRecord, bool) {
+	i, ok := db.find(term)
+	if !ok {
+		return nil, false
+	}
+	var tns []string
+	for _, tn := range db.Records[i].TreeNumbers {
+		tns = append(tns, db.children[tn]...)
+	}
+	return db.recordsAt(tns, i), true
+}
+
+// Parents returns descriptors directly above term at any tree position.
+func (db *LocalDB) Parents(term string) ([]MeSHRecord, bool) {
+	i, ok := db.find(term)
+	if !ok {
+		return nil, false
+	}
+	var tns []string
+	for _, tn := range db.Records[i].TreeNumbers {
+		if p := parentTreeNumber(tn); p != "" {
+			tns = append(tns, p)
+		}
+	}
+	return db.recordsAt(tns, i), true
+}
+
+// Siblings returns descriptors sharing a parent with term, excluding term.
+func (db *LocalDB) Siblings(term string) ([]MeSHRecord, bool) {
+	i, ok := db.find(term)
+	if !ok {
+		return nil, false
+	}
+	var tns []string
+	for _, tn := range db.Records[i].TreeNumbers {
+		if p := parentTreeNumber(tn); p != "" {
+			tns = append(tns, db.children[p]...)
+		}
+	}
+	return db.recordsAt(tns, i), true
+}
+
+func (db *LocalDB) find(term string) (int, bool) {
+	term = strings.TrimSpace(term)
+	if i, ok := db.byUI[strings.ToUpper(term)]; ok {
+		return i, true
+	}
+	if i, ok := db.byTree[term]; ok {
+		return i, true
+	}
+	i, ok := db.byTerm[normalizeTerm(term)]
+	return i, ok
+}
+
+func (db *LocalDB) node(tn string) (TreeNode, bool) {
+	i, ok := db.byTree[tn]
+	if !ok {
+		return TreeNode{}, false
+	}
+	r := db.Records[i]
+	return TreeNode{UI: r.UI, Name: r.Name, TreeNumber: tn, ChildCount: len(db.children[tn])}, true
+}
+
+func (db *LocalDB) descendants(tn string, depth int) []TreeNode {
+	var out []TreeNode
+	for _, child := range db.children[tn] {
+		node, ok := db.node(child)
+		if !ok {
+			continue
+		}
+		if depth > 1 {
+			node.Children = db.descendants(child, depth-1)
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+// recordsAt maps tree numbers to distinct records, skipping the record at self.
+func (db *LocalDB) recordsAt(tns []string, self int) []MeSHRecord {
+	seen := map[int]bool{self: true}
+	var out []MeSHRecord
+	for _, tn := range tns {
+		i, ok := db.byTree[tn]
+		if !ok || seen[i] {
+			continue
+		}
+		seen[i] = true
+		out = append(out, db.Records[i])
+	}
+	return out
+}
+
+func normalizeTerm(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// ParseASCII parses NLM's ASCII MeSH descriptor (dYYYY.bin) and
+// Supplementary Concept Record (cYYYY.bin) formats, where each record
+// starts with "*NEWRECORD" followed by "KEY = value" lines.
+func ParseASCII(r io.Reader) ([]MeSHRecord, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		records []MeSHRecord
+		cur     *MeSHRecord
+	)
+	flush := func() {
+		if cur != nil && cur.UI != "" {
+			if cur.RecordType == "" {
+				cur.RecordType = recordType("", cur.UI)
+			}
+			records = append(records, *cur)
+		}
+		cur = nil
+	}
+
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if line == "*NEWRECORD" {
+			flush()
+			cur = &MeSHRecord{}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		key, value, ok := strings.Cut(line, " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "MH", "NM":
+			cur.Name = value
+		case "UI":
+			cur.UI = value
+		case "RECTYPE":
+			cur.RecordType = recordType("", value)
+		case "MN":
+			cur.TreeNumbers = append(cur.TreeNumbers, value)
+		case "MS", "NO":
+			cur.ScopeNote = value
+		case "AN":
+			cur.Annotation = value
+		case "ENTRY", "PRINT ENTRY", "SY":
+			term, _, _ := strings.Cut(value, "|")
+			cur.EntryTerms = append(cur.EntryTerms, term)
+		case "AQ":
+			cur.AllowableQualifiers = qualifierNames(value)
+		case "PA":
+			cur.PharmacologicalActions = append(cur.PharmacologicalActions, value)
+		case "HM":
+			cur.HeadingMappedTo = append(cur.HeadingMappedTo, splitHeadings(value)...)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading MeSH file: %w", err)
+	}
+	flush()
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no MeSH records found")
+	}
+	return records, nil
+}
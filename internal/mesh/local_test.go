@@ -0,0 +1,216 @@
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+const asciiTree = `*NEWRECORD
+RECTYPE = D
+MH = Nervous System Diseases
+MN = C10
+UI = D009422
+
+*NEWRECORD
+RECTYPE = D
+MH = Neurologic Manifestations
+MN = C10.597
+UI = D009461
+
+*NEWRECORD
+RECTYPE = D
+MH = Neurobehavioral Manifestations
+ENTRY = Neurobehavioral Signs|T033|NON|EQV|NLM (2000)|990101|abcdef
+MN = C10.597.606
+UI = D019954
+
+*NEWRECORD
+RECTYPE = D
+MH = Neuromuscular Manifestations
+MN = C10.597.617
+UI = D020879
+
+*NEWRECORD
+RECTYPE = D
+MH = Cognitive Dysfunction
+PRINT ENTRY = Cognitive Impairment|T048|NON|EQV|NLM (2011)|100101|abcdef
+MN = C10.597.606.150
+UI = D060825
+`
+
+func TestParseASCII_Fixture(t *testing.T) {
+	records, err := ParseASCII(bytes.NewReader(loadTestdata(t, "mesh_fetch.txt")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	r := records[0]
+	if r.UI != "D005600" || r.Name != "Fragile X Syndrome" {
+		t.Errorf("unexpected record identity: %s %q", r.UI, r.Name)
+	}
+	if len(r.TreeNumbers) != 3 || r.TreeNumbers[0] != "C10.597.606.360.320.322" {
+		t.Errorf("unexpected tree numbers: %v", r.TreeNumbers)
+	}
+	if len(r.EntryTerms) != 5 || r.EntryTerms[1] != "FXS" {
+		t.Errorf("expected entry terms stripped of qualifiers, got %v", r.EntryTerms)
+	}
+	if !strings.HasPrefix(r.ScopeNote, "A condition characterized genetically") {
+		t.Errorf("unexpected scope note: %q", r.ScopeNote)
+	}
+	if !strings.Contains(r.Annotation, "FRAGILE X CHROMOSOME") {
+		t.Errorf("unexpected annotation: %q", r.Annotation)
+	}
+}
+
+func TestParseASCII_SupplementaryConcept(t *testing.T) {
+	const scr = `*NEWRECORD
+RECTYPE = C
+NM = lecanemab
+RN = 12RHB0DH3C
+HM = *Antibodies, Monoclonal, Humanized
+HM = Alzheimer Disease/drug therapy
+NO = humanized IgG1 monoclonal antibody
+SY = Leqembi|NLM (2023)|230101|abcdef
+SY = BAN2401
+UI = C000627419
+`
+	records, err := ParseASCII(strings.NewReader(scr))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := records[0]
+	if r.Name != "lecanemab" || !r.IsSupplementary() {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if len(r.HeadingMappedTo) != 2 || r.HeadingMappedTo[0] != "Antibodies, Monoclonal, Humanized" || r.HeadingMappedTo[1] != "Alzheimer Disease" {
+		t.Errorf("unexpected headings mapped to: %v", r.HeadingMappedTo)
+	}
+	if len(r.EntryTerms) != 2 || r.EntryTerms[0] != "Leqembi" {
+		t.Errorf("unexpected synonyms: %v", r.EntryTerms)
+	}
+
+	db := NewLocalDB("c2025.bin", records)
+	if rec, ok := db.Lookup("leqembi"); !ok || rec.UI != "C000627419" {
+		t.Errorf("expected SCR lookup by synonym, got %+v, %v", rec, ok)
+	}
+}
+
+func TestParseASCII_Empty(t *testing.T) {
+	if _, err := ParseASCII(strings.NewReader("")); err == nil {
+		t.Fatal("expected error for empty input")
+	}
+}
+
+func TestLocalDB_LookupByEntryTermAndUI(t *testing.T) {
+	records, err := ParseASCII(strings.NewReader(asciiTree))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	db := NewLocalDB("test.bin", records)
+
+	for _, term := range []string{"cognitive impairment", "D060825", "Cognitive  Dysfunction"} {
+		rec, ok := db.Lookup(term)
+		if !ok || rec.UI != "D060825" {
+			t.Errorf("Lookup(%q) = %+v, %v; want D060825", term, rec, ok)
+		}
+	}
+	if _, ok := db.Lookup("not a descriptor"); ok {
+		t.Error("expected miss for unknown term")
+	}
+}
+
+func TestLocalDB_TreeNavigation(t *testing.T) {
+	records, _ := ParseASCII(strings.NewReader(asciiTree))
+	db := NewLocalDB("test.bin", records)
+
+	h, ok := db.Tree("Neurobehavioral Signs", 1)
+	if !ok {
+		t.Fatal("expected tree for entry term")
+	}
+	p := h.Paths[0]
+	if len(p.Ancestors) != 2 || p.Ancestors[0].Name != "Nervous System Diseases" {
+		t.Errorf("unexpected ancestors: %+v", p.Ancestors)
+	}
+	if len(p.Children) != 1 || p.Children[0].UI != "D060825" {
+		t.Errorf("unexpected children: %+v", p.Children)
+	}
+
+	h, _ = db.Tree("D009422", 2)
+	top := h.Paths[0].Children
+	if len(top) != 1 || top[0].ChildCount != 2 || len(top[0].Children) != 2 {
+		t.Errorf("expected two levels beneath the root, got %+v", top)
+	}
+	if top[0].Children[0].ChildCount != 1 || top[0].Children[0].Children != nil {
+		t.Errorf("expected depth to stop with counts, got %+v", top[0].Children[0])
+	}
+
+	siblings, _ := db.Siblings("D019954")
+	if len(siblings) != 1 || siblings[0].UI != "D020879" {
+		t.Errorf("unexpected siblings: %+v", siblings)
+	}
+	parents, _ := db.Parents("D019954")
+	if len(parents) != 1 || parents[0].UI != "D009461" {
+		t.Errorf("unexpected parents: %+v", parents)
+	}
+}
+
+func TestDownloadAndOpenLocalDB(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(asciiTree))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	if _, err := OpenLocalDB(dir); !errors.Is(err, ErrNoLocalDB) {
+		t.Fatalf("expected ErrNoLocalDB before download, got %v", err)
+	}
+
+	if _, err := DownloadLocalDB(context.Background(), srv.Client(), dir, srv.URL+"/d2025.bin"); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+
+	db, err := OpenLocalDB(dir)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if db.Source != "d2025.bin" || len(db.Records) != 5 {
+		t.Errorf("unexpected db: source=%q records=%d", db.Source, len(db.Records))
+	}
+}
+
+func TestClient_UsesLocalDBWithoutNetwork(t *testing.T) {
+	records, _ := ParseASCII(strings.NewReader(asciiTree))
+	db := NewLocalDB("test.bin", records)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected network call: %s", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	c := NewClient(ncbi.NewBaseClient(ncbi.WithBaseURL(srv.URL)), WithLocalDB(db))
+	rec, err := c.Lookup(context.Background(), "Cognitive Dysfunction")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.UI != "D060825" {
+		t.Errorf("expected D060825, got %q", rec.UI)
+	}
+
+	h, err := c.Tree(context.Background(), "D019954", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(h.Paths) != 1 {
+		t.Errorf("expected 1 path, got %d", len(h.Paths))
+	}
+}
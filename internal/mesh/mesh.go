@@ -4,30 +4,83 @@ package mesh
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
 	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
 )
 
-// MeSHRecord represents a MeSH descriptor record.
+// MeSHRecord represents a MeSH descriptor or Supplementary Concept Record.
 type MeSHRecord struct {
 	UI          string   `json:"ui"`
 	Name        string   `json:"name"`
+	RecordType  string   `json:"record_type,omitempty"`
 	ScopeNote   string   `json:"scope_note"`
 	TreeNumbers []string `json:"tree_numbers"`
 	EntryTerms  []string `json:"entry_terms"`
 	Annotation  string   `json:"annotation,omitempty"`
+
+	// HeadingMappedTo lists the descriptors an SCR is indexed under.
+	HeadingMappedTo []string `json:"heading_mapped_to,omitempty"`
+	// AllowableQualifiers names the qualifiers that may be paired with a descriptor.
+	AllowableQualifiers []string `json:"allowable_qualifiers,omitempty"`
+	// PharmacologicalActions names the drug-class descriptors a substance belongs to.
+	PharmacologicalActions []string `json:"pharmacological_actions,omitempty"`
+}
+
+// Record types.
+const (
+	RecordDescriptor    = "descriptor"
+	RecordSupplementary = "supplementary"
+	RecordQualifier     = "qualifier"
+)
+
+// IsSupplementary reports whether r is a Supplementary Concept Record
+// (chemicals, drugs, rare diseases) rather than a descriptor.
+func (r *MeSHRecord) IsSupplementary() bool {
+	return r.RecordType == RecordSupplementary
 }
 
+// ErrNotFound reports that no MeSH descriptor matches a term.
+var ErrNotFound = errors.New("not found")
+
 // Client provides MeSH lookup functionality.
 // It embeds ncbi.BaseClient for shared rate limiting and common parameters.
 type Client struct {
 	*ncbi.BaseClient
+	local *LocalDB
+	cache *cache.Store
+}
+
+// CacheTTL is how long cached MeSH responses are reused. Descriptors change
+// once a year, so a month is conservative.
+const CacheTTL = 30 * 24 * time.Hour
+
+// ClientOption configures a MeSH Client.
+type ClientOption func(*Client)
+
+// WithLocalDB serves lookups and tree navigation from a downloaded MeSH
+// database, falling back to NCBI only for terms it does not contain.
+func WithLocalDB(db *LocalDB) ClientOption {
+	return func(c *Client) { c.local = db }
+}
+
+// WithCache reuses NCBI MeSH responses from store across runs.
+func WithCache(store *cache.Store) ClientOption {
+	return func(c *Client) { c.cache = store }
 }
 
 // NewClient creates a new MeSH lookup client using an existing NCBI base client.
-func NewClient(base *ncbi.BaseClient) *Client {
-	return &Client{BaseClient: base}
+func NewClient(base *ncbi.BaseClient, opts ...ClientOption) *Client {
+	c := &Client{BaseClient: base}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // esearchResult for parsing MeSH search.
@@ -46,13 +99,19 @@ func (c *Client) Lookup(ctx context.Context, term string) (*MeSHRecord, error) {
 		return nil, fmt.Errorf("MeSH term cannot be empty")
 	}
 
+	if c.local != nil {
+		if rec, ok := c.local.Lookup(term); ok {
+			return rec, nil
+		}
+	}
+
 	// Step 1: Search for the term in MeSH database
 	ids, err := c.searchMeSH(ctx, term)
 	if err != nil {
 		return nil, err
 	}
 	if len(ids) == 0 {
-		return nil, fmt.Errorf("MeSH term %q not found", term)
+		return nil, fmt.Errorf("MeSH term %q %w", term, ErrNotFound)
 	}
 
 	// Step 2: Fetch the full record
@@ -64,6 +123,25 @@ func (c *Client) Lookup(ctx context.Context, term string) (*MeSHRecord, error) {
 	return record, nil
 }
 
+// get is DoGet behind the response cache, when one is configured.
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	if c.cache == nil {
+		return c.DoGet(ctx, endpoint, params)
+	}
+	// Keyed before DoGet adds credentials to params.
+	key := "mesh/" + endpoint + "?" + params.Encode()
+	if body, ok := c.cache.Get(key); ok {
+		return body, nil
+	}
+	body, err := c.DoGet(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	// A failed cache write only costs a refetch next time.
+	_ = c.cache.Put(key, body)
+	return body, nil
+}
+
 func (c *Client) searchMeSH(ctx context.Context, term string) ([]string, error) {
 	// Try exact MeSH heading match first, fall back to broad search
 	for _, query := range []string{
@@ -76,7 +154,7 @@ func (c *Client) searchMeSH(ctx context.Context, term string) ([]string, error)
 			"retmode": {"json"},
 		}
 
-		resp, err := c.DoGet(ctx, "esearch.fcgi", params)
+		resp, err := c.get(ctx, "esearch.fcgi", params)
 		if err != nil {
 			return nil, fmt.Errorf("MeSH search failed: %w", err)
 		}
@@ -101,27 +179,68 @@ type esummaryResponse struct {
 
 // esummaryRecord holds the fields we need from a single MeSH esummary record.
 type esummaryRecord struct {
-	UID       string   `json:"uid"`
-	ScopeNote string   `json:"ds_scopenote"`
-	MeshTerms []string `json:"ds_meshterms"`
-	MeshUI    string   `json:"ds_meshui"`
-	IdxLinks  []struct {
-		TreeNum string `json:"treenum"`
-	} `json:"ds_idxlinks"`
+	UID             string            `json:"uid"`
+	ScopeNote       string            `json:"ds_scopenote"`
+	MeshTerms       []string          `json:"ds_meshterms"`
+	MeshUI          string            `json:"ds_meshui"`
+	RecordType      string            `json:"ds_recordtype"`
+	HeadingMappedTo string            `json:"ds_headingmappedto"`
+	Subheadings     []json.RawMessage `json:"ds_subheading"`
+	PAList          []json.RawMessage `json:"ds_palist"`
+	IdxLinks        []esummaryLink    `json:"ds_idxlinks"`
+}
+
+// esummaryLink is one position of a descriptor in the MeSH tree. NCBI
+// reports the parent and children as Entrez UIDs, not MeSH UIs.
+type esummaryLink struct {
+	Parent   entrezUID   `json:"parent"`
+	TreeNum  string      `json:"treenum"`
+	Children []entrezUID `json:"children"`
+}
+
+// entrezUID handles UIDs that NCBI emits as either JSON numbers or strings.
+type entrezUID string
+
+func (u *entrezUID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*u = entrezUID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("cannot parse MeSH UID: %s", string(data))
+	}
+	if n == "0" {
+		*u = ""
+		return nil
+	}
+	*u = entrezUID(n.String())
+	return nil
 }
 
 func (c *Client) fetchMeSH(ctx context.Context, uid string) (*MeSHRecord, error) {
-	params := make(map[string][]string)
-	vals := map[string]string{
-		"db":      "mesh",
-		"id":      uid,
-		"retmode": "json",
+	recs, err := c.fetchSummaries(ctx, []string{uid})
+	if err != nil {
+		return nil, err
+	}
+	rec, ok := recs[uid]
+	if !ok {
+		return nil, fmt.Errorf("MeSH UID %s not found in response", uid)
 	}
-	for k, v := range vals {
-		params[k] = []string{v}
+	return recordFromSummary(rec), nil
+}
+
+// fetchSummaries retrieves esummary records for one or more MeSH UIDs in a
+// single request, keyed by UID. UIDs missing from the response are omitted.
+func (c *Client) fetchSummaries(ctx context.Context, uids []string) (map[string]esummaryRecord, error) {
+	params := map[string][]string{
+		"db":      {"mesh"},
+		"id":      {strings.Join(uids, ",")},
+		"retmode": {"json"},
 	}
 
-	body, err := c.DoGet(ctx, "esummary.fcgi", params)
+	body, err := c.get(ctx, "esummary.fcgi", params)
 	if err != nil {
 		return nil, fmt.Errorf("MeSH fetch failed: %w", err)
 	}
@@ -131,19 +250,27 @@ func (c *Client) fetchMeSH(ctx context.Context, uid string) (*MeSHRecord, error)
 		return nil, fmt.Errorf("parsing MeSH summary: %w", err)
 	}
 
-	raw, ok := resp.Result[uid]
-	if !ok {
-		return nil, fmt.Errorf("MeSH UID %s not found in response", uid)
-	}
-
-	var rec esummaryRecord
-	if err := json.Unmarshal(raw, &rec); err != nil {
-		return nil, fmt.Errorf("parsing MeSH record %s: %w", uid, err)
+	out := make(map[string]esummaryRecord, len(uids))
+	for _, uid := range uids {
+		raw, ok := resp.Result[uid]
+		if !ok {
+			continue
+		}
+		var rec esummaryRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return nil, fmt.Errorf("parsing MeSH record %s: %w", uid, err)
+		}
+		out[uid] = rec
 	}
+	return out, nil
+}
 
+func recordFromSummary(rec esummaryRecord) *MeSHRecord {
 	record := &MeSHRecord{
-		UI:        rec.MeshUI,
-		ScopeNote: rec.ScopeNote,
+		UI:              rec.MeshUI,
+		RecordType:      recordType(rec.RecordType, rec.MeshUI),
+		ScopeNote:       rec.ScopeNote,
+		HeadingMappedTo: splitHeadings(rec.HeadingMappedTo),
 	}
 
 	// First term is the heading; rest are entry terms
@@ -160,5 +287,59 @@ func (c *Client) fetchMeSH(ctx context.Context, uid string) (*MeSHRecord, error)
 		}
 	}
 
-	return record, nil
+	// Only plain qualifier names are used; other shapes are ignored.
+	for _, raw := range rec.Subheadings {
+		var name string
+		if json.Unmarshal(raw, &name) == nil && name != "" {
+			record.AllowableQualifiers = append(record.AllowableQualifiers, strings.ToLower(name))
+		}
+	}
+
+	for _, raw := range rec.PAList {
+		if name := paName(raw); name != "" {
+			record.PharmacologicalActions = append(record.PharmacologicalActions, name)
+		}
+	}
+
+	return record
+}
+
+// recordType normalizes NCBI's record type, falling back to the UI prefix
+// (D descriptor, C supplementary concept, Q qualifier).
+func recordType(raw, ui string) string {
+	raw = strings.ToLower(raw)
+	switch {
+	case strings.HasPrefix(raw, "suppl"):
+		return RecordSupplementary
+	case strings.HasPrefix(raw, "qual"):
+		return RecordQualifier
+	case strings.HasPrefix(raw, "desc"):
+		return RecordDescriptor
+	}
+	switch {
+	case strings.HasPrefix(ui, "C"):
+		return RecordSupplementary
+	case strings.HasPrefix(ui, "Q"):
+		return RecordQualifier
+	case strings.HasPrefix(ui, "D"):
+		return RecordDescriptor
+	}
+	return ""
+}
+
+// splitHeadings parses heading-mapped-to values such as
+// "*Antibodies, Monoclonal, Humanized; Alzheimer Disease/drug therapy",
+// dropping the major-topic star and any qualifier.
+func splitHeadings(s string) []string {
+	var out []string
+	for _, h := range strings.Split(s, ";") {
+		h = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(h), "*"))
+		if i := strings.Index(h, "/"); i >= 0 {
+			h = strings.TrimSpace(h[:i])
+		}
+		if h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
 }
@@ -2,6 +2,7 @@ package mesh
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
 	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
 )
 
@@ -77,6 +79,9 @@ func TestLookup_Success(t *testing.T) {
 	if record.Name != "Fragile X Syndrome" {
 		t.Errorf("expected name 'Fragile X Syndrome', got %q", record.Name)
 	}
+	if record.RecordType != RecordDescriptor {
+		t.Errorf("expected descriptor record type, got %q", record.RecordType)
+	}
 	if record.ScopeNote == "" {
 		t.Error("expected non-empty scope note")
 	}
@@ -103,6 +108,53 @@ func TestLookup_Success(t *testing.T) {
 	}
 }
 
+func TestRecordFromSummary_SupplementaryConcept(t *testing.T) {
+	rec := recordFromSummary(esummaryRecord{
+		MeshUI:          "C000627419",
+		MeshTerms:       []string{"lecanemab", "Leqembi"},
+		RecordType:      "supplementary",
+		HeadingMappedTo: "*Antibodies, Monoclonal, Humanized; Alzheimer Disease/drug therapy",
+	})
+	if !rec.IsSupplementary() {
+		t.Errorf("expected supplementary record, got %q", rec.RecordType)
+	}
+	want := []string{"Antibodies, Monoclonal, Humanized", "Alzheimer Disease"}
+	if len(rec.HeadingMappedTo) != 2 || rec.HeadingMappedTo[0] != want[0] || rec.HeadingMappedTo[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, rec.HeadingMappedTo)
+	}
+}
+
+func TestLookup_UsesCache(t *testing.T) {
+	searchFixture := loadTestdata(t, "mesh_search.json")
+	esummaryFixture := loadTestdata(t, "mesh_esummary.json")
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/esearch.fcgi" {
+			w.Write(searchFixture)
+		} else {
+			w.Write(esummaryFixture)
+		}
+	}))
+	defer srv.Close()
+
+	store := cache.NewStore(t.TempDir(), CacheTTL)
+	for i := 0; i < 2; i++ {
+		c := NewClient(ncbi.NewBaseClient(ncbi.WithBaseURL(srv.URL), ncbi.WithAPIKey("test-key")), WithCache(store))
+		record, err := c.Lookup(context.Background(), "Fragile X Syndrome")
+		if err != nil {
+			t.Fatalf("lookup %d: unexpected error: %v", i, err)
+		}
+		if record.UI != "D005600" {
+			t.Errorf("lookup %d: expected D005600, got %q", i, record.UI)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 network calls (search + summary) across both lookups, got %d", calls)
+	}
+}
+
 func TestLookup_NotFound(t *testing.T) {
 	emptySearch := `{"header":{"type":"esearch","version":"0.3"},"esearchresult":{"count":"0","retmax":"20","retstart":"0","idlist":[]}}`
 
@@ -113,8 +165,8 @@ func TestLookup_NotFound(t *testing.T) {
 
 	c := newTestClient(t, srv.URL)
 	_, err := c.Lookup(context.Background(), "nonexistent_mesh_term_xyz")
-	if err == nil {
-		t.Error("expected error for not found term, got nil")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
 	}
 }
 
@@ -0,0 +1,101 @@
+package mesh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultMemberLimit caps how many substances ActionMembers returns.
+const DefaultMemberLimit = 200
+
+// ActionMembers returns the substances (descriptors and SCRs) that carry
+// action as a Pharmacological Action, e.g. "Angiotensin II Type 1 Receptor
+// Blockers" → Losartan, Valsartan, ...
+func (c *Client) ActionMembers(ctx context.Context, action string, limit int) ([]MeSHRecord, error) {
+	action = strings.TrimSpace(action)
+	if action == "" {
+		return nil, fmt.Errorf("pharmacological action cannot be empty")
+	}
+	if limit <= 0 {
+		limit = DefaultMemberLimit
+	}
+
+	if c.local != nil {
+		if recs, ok := c.local.ActionMembers(action, limit); ok {
+			return recs, nil
+		}
+	}
+
+	params := map[string][]string{
+		"db":      {"mesh"},
+		"term":    {fmt.Sprintf("%q[Pharmacological Action]", action)},
+		"retmode": {"json"},
+		"retmax":  {strconv.Itoa(limit)},
+	}
+	body, err := c.get(ctx, "esearch.fcgi", params)
+	if err != nil {
+		return nil, fmt.Errorf("MeSH search failed: %w", err)
+	}
+	var result meshSearchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing MeSH search response: %w", err)
+	}
+	return c.fetchRecords(ctx, result.Result.IDList, "")
+}
+
+// ActionMembers returns locally indexed substances with the given action,
+// sorted by name. ok is false when action is not a known action heading.
+func (db *LocalDB) ActionMembers(action string, limit int) ([]MeSHRecord, bool) {
+	i, ok := db.find(action)
+	if !ok {
+		return nil, false
+	}
+	heading := db.Records[i].Name
+
+	var out []MeSHRecord
+	for _, r := range db.Records {
+		for _, pa := range r.PharmacologicalActions {
+			if pa == heading {
+				out = append(out, r)
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, true
+}
+
+// paName extracts an action heading from a ds_palist entry, which NCBI
+// emits either as a bare string or as an object naming the heading.
+func paName(raw json.RawMessage) string {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		return strings.TrimSpace(name)
+	}
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) != nil {
+		return ""
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lk := strings.ToLower(k)
+		if !strings.Contains(lk, "name") && !strings.Contains(lk, "term") && !strings.Contains(lk, "heading") {
+			continue
+		}
+		if json.Unmarshal(obj[k], &name) == nil && name != "" {
+			return strings.TrimSpace(name)
+		}
+	}
+	return ""
+}
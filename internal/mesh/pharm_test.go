@@ -0,0 +1,90 @@
+package mesh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const asciiPharm = `*NEWRECORD
+RECTYPE = D
+MH = Angiotensin II Type 1 Receptor Blockers
+MN = D27.505.519.389.745.085
+UI = D047228
+
+*NEWRECORD
+RECTYPE = D
+MH = Losartan
+PA = Angiotensin II Type 1 Receptor Blockers
+PA = Antihypertensive Agents
+MN = D03.383.129.308.500
+UI = D019808
+
+*NEWRECORD
+RECTYPE = D
+MH = Candesartan
+PA = Angiotensin II Type 1 Receptor Blockers
+UI = D000068838
+`
+
+func TestLocalDB_ActionMembers(t *testing.T) {
+	records, err := ParseASCII(strings.NewReader(asciiPharm))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	db := NewLocalDB("test.bin", records)
+
+	losartan, _ := db.Lookup("losartan")
+	if len(losartan.PharmacologicalActions) != 2 {
+		t.Fatalf("expected 2 actions, got %v", losartan.PharmacologicalActions)
+	}
+
+	members, ok := db.ActionMembers("angiotensin ii type 1 receptor blockers", 10)
+	if !ok {
+		t.Fatal("expected action heading to resolve")
+	}
+	if len(members) != 2 || members[0].Name != "Candesartan" || members[1].Name != "Losartan" {
+		t.Errorf("unexpected members: %+v", members)
+	}
+}
+
+func TestActionMembers_Online(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/esearch.fcgi":
+			if got := r.URL.Query().Get("term"); got != `"ACE Inhibitors"[Pharmacological Action]` {
+				t.Errorf("unexpected term %q", got)
+			}
+			w.Write([]byte(`{"esearchresult":{"count":"1","idlist":["68002216"]}}`))
+		case "/esummary.fcgi":
+			w.Write([]byte(`{"result":{"68002216":{"uid":"68002216","ds_meshui":"D002216","ds_meshterms":["Captopril"],
+				"ds_palist":[{"meshterm":"Angiotensin-Converting Enzyme Inhibitors"},"Antihypertensive Agents"]}}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	members, err := c.ActionMembers(context.Background(), "ACE Inhibitors", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 1 || members[0].Name != "Captopril" {
+		t.Fatalf("unexpected members: %+v", members)
+	}
+	want := []string{"Angiotensin-Converting Enzyme Inhibitors", "Antihypertensive Agents"}
+	got := members[0].PharmacologicalActions
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected actions %v, got %v", want, got)
+	}
+}
+
+func TestPAName_IgnoresUnknownShapes(t *testing.T) {
+	for _, raw := range []string{`123`, `{"uid":"68000959"}`, `null`} {
+		if got := paName(json.RawMessage(raw)); got != "" {
+			t.Errorf("paName(%s) = %q, want empty", raw, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package mesh
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Qualifier is a MeSH subheading that narrows a descriptor to one aspect,
+// as in "Fragile X Syndrome/drug therapy".
+type Qualifier struct {
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+// qualifiers is NLM's current topical qualifier list with the two-letter
+// abbreviations used in the AQ (allowable qualifiers) field.
+var qualifiers = []Qualifier{
+	{"abnormalities", "AB"},
+	{"administration & dosage", "AD"},
+	{"adverse effects", "AE"},
+	{"agonists", "AG"},
+	{"analogs & derivatives", "AA"},
+	{"analysis", "AN"},
+	{"anatomy & histology", "AH"},
+	{"antagonists & inhibitors", "AI"},
+	{"biosynthesis", "BI"},
+	{"blood", "BL"},
+	{"blood supply", "BS"},
+	{"cerebrospinal fluid", "CF"},
+	{"chemical synthesis", "CS"},
+	{"chemically induced", "CI"},
+	{"chemistry", "CH"},
+	{"classification", "CL"},
+	{"complications", "CO"},
+	{"congenital", "CN"},
+	{"contraindications", "CT"},
+	{"cytology", "CY"},
+	{"deficiency", "DF"},
+	{"diagnosis", "DI"},
+	{"diagnostic imaging", "DG"},
+	{"diagnostic use", "DU"},
+	{"diet therapy", "DH"},
+	{"drug effects", "DE"},
+	{"drug therapy", "DT"},
+	{"economics", "EC"},
+	{"education", "ED"},
+	{"embryology", "EM"},
+	{"enzymology", "EN"},
+	{"epidemiology", "EP"},
+	{"ethics", "ES"},
+	{"ethnology", "EH"},
+	{"etiology", "ET"},
+	{"genetics", "GE"},
+	{"growth & development", "GD"},
+	{"history", "HI"},
+	{"immunology", "IM"},
+	{"injuries", "IN"},
+	{"innervation", "IR"},
+	{"instrumentation", "IS"},
+	{"isolation & purification", "IP"},
+	{"legislation & jurisprudence", "LJ"},
+	{"manpower", "MA"},
+	{"metabolism", "ME"},
+	{"methods", "MT"},
+	{"microbiology", "MI"},
+	{"mortality", "MO"},
+	{"nursing", "NU"},
+	{"organization & administration", "OG"},
+	{"parasitology", "PS"},
+	{"pathogenicity", "PY"},
+	{"pathology", "PA"},
+	{"pharmacokinetics", "PK"},
+	{"pharmacology", "PD"},
+	{"physiology", "PH"},
+	{"physiopathology", "PP"},
+	{"poisoning", "PO"},
+	{"prevention & control", "PC"},
+	{"psychology", "PX"},
+	{"radiation effects", "RE"},
+	{"radiotherapy", "RT"},
+	{"rehabilitation", "RH"},
+	{"secondary", "SC"},
+	{"standards", "ST"},
+	{"statistics & numerical data", "SN"},
+	{"supply & distribution", "SD"},
+	{"surgery", "SU"},
+	{"therapeutic use", "TU"},
+	{"therapy", "TH"},
+	{"toxicity", "TO"},
+	{"transmission", "TM"},
+	{"transplantation", "TR"},
+	{"trends", "TD"},
+	{"ultrastructure", "US"},
+	{"urine", "UR"},
+	{"veterinary", "VE"},
+	{"virology", "VI"},
+}
+
+// Qualifiers returns every known qualifier, sorted by name.
+func Qualifiers() []Qualifier {
+	out := make([]Qualifier, len(qualifiers))
+	copy(out, qualifiers)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// LookupQualifier finds a qualifier by name ("drug therapy"), abbreviation
+// ("DT"), or name with "and" for "&" ("prevention and control").
+func LookupQualifier(s string) (Qualifier, error) {
+	key := normalizeTerm(strings.ReplaceAll(s, " and ", " & "))
+	for _, q := range qualifiers {
+		if key == q.Name || strings.EqualFold(key, q.Abbreviation) {
+			return q, nil
+		}
+	}
+	return Qualifier{}, fmt.Errorf("unknown MeSH qualifier %q", s)
+}
+
+// AllowsQualifier reports whether q may be paired with the record. Records
+// without an allowable-qualifier list (e.g. from NCBI esummary) allow any.
+func (r *MeSHRecord) AllowsQualifier(q Qualifier) bool {
+	if len(r.AllowableQualifiers) == 0 {
+		return true
+	}
+	for _, name := range r.AllowableQualifiers {
+		if name == q.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifierNames maps AQ abbreviations ("BL CF DT") to qualifier names,
+// skipping codes for retired qualifiers.
+func qualifierNames(abbrevs string) []string {
+	var out []string
+	for _, code := range strings.Fields(abbrevs) {
+		for _, q := range qualifiers {
+			if q.Abbreviation == code {
+				out = append(out, q.Name)
+				break
+			}
+		}
+	}
+	return out
+}
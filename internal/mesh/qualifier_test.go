@@ -0,0 +1,42 @@
+package mesh
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLookupQualifier(t *testing.T) {
+	for _, in := range []string{"drug therapy", "DT", "dt", "Drug  Therapy"} {
+		q, err := LookupQualifier(in)
+		if err != nil || q.Name != "drug therapy" {
+			t.Errorf("LookupQualifier(%q) = %+v, %v", in, q, err)
+		}
+	}
+	if q, err := LookupQualifier("prevention and control"); err != nil || q.Abbreviation != "PC" {
+		t.Errorf("expected 'and' to match '&', got %+v, %v", q, err)
+	}
+	if _, err := LookupQualifier("cooking"); err == nil {
+		t.Error("expected error for unknown qualifier")
+	}
+}
+
+func TestAllowsQualifier(t *testing.T) {
+	records, err := ParseASCII(bytes.NewReader(loadTestdata(t, "mesh_fetch.txt")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fxs := records[0]
+	dt, _ := LookupQualifier("drug therapy")
+	ae, _ := LookupQualifier("adverse effects")
+	if !fxs.AllowsQualifier(dt) {
+		t.Errorf("expected drug therapy allowed, got %v", fxs.AllowableQualifiers)
+	}
+	if fxs.AllowsQualifier(ae) {
+		t.Error("expected adverse effects not allowed for a disease")
+	}
+
+	unknown := MeSHRecord{Name: "No AQ"}
+	if !unknown.AllowsQualifier(ae) {
+		t.Error("records without an allowable list should allow any qualifier")
+	}
+}
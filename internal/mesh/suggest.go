@@ -0,0 +1,179 @@
+package mesh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultSuggestLimit caps the number of suggestions returned.
+const DefaultSuggestLimit = 10
+
+// Suggestion is a descriptor whose heading or entry term matches user input.
+type Suggestion struct {
+	UI      string `json:"ui"`
+	Name    string `json:"name"`
+	Matched string `json:"matched"` // heading or entry term that matched
+	Rank    int    `json:"rank"`    // lower is better; see rankTerm
+}
+
+// Suggest returns descriptors matching prefix, best matches first. Entry
+// terms count, so "heart attack" suggests "Myocardial Infarction".
+func (c *Client) Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	prefix = normalizeTerm(prefix)
+	if prefix == "" {
+		return nil, fmt.Errorf("MeSH prefix cannot be empty")
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+
+	if c.local != nil {
+		return c.local.Suggest(prefix, limit), nil
+	}
+
+	var ids []string
+	for _, query := range []string{prefix + "*", prefix} {
+		params := map[string][]string{
+			"db":      {"mesh"},
+			"term":    {query},
+			"retmode": {"json"},
+			"retmax":  {strconv.Itoa(limit * 2)},
+		}
+		body, err := c.get(ctx, "esearch.fcgi", params)
+		if err != nil {
+			return nil, fmt.Errorf("MeSH search failed: %w", err)
+		}
+		var result meshSearchResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("parsing MeSH search response: %w", err)
+		}
+		if len(result.Result.IDList) > 0 {
+			ids = result.Result.IDList
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	recs, err := c.fetchSummaries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Suggestion
+	for _, uid := range ids {
+		rec, ok := recs[uid]
+		if !ok || len(rec.MeshTerms) == 0 {
+			continue
+		}
+		s := bestMatch(prefix, rec.MeshUI, rec.MeshTerms[0], rec.MeshTerms[1:])
+		if s.Rank == rankSearchOnly {
+			// NCBI matched on something we don't see (e.g. a tree number); keep it last.
+			s.Matched = rec.MeshTerms[0]
+		}
+		out = append(out, s)
+	}
+	return rankSuggestions(out, limit), nil
+}
+
+// Suggest returns locally indexed descriptors matching prefix.
+func (db *LocalDB) Suggest(prefix string, limit int) []Suggestion {
+	prefix = normalizeTerm(prefix)
+	best := make(map[int]Suggestion)
+	for term, i := range db.byTerm {
+		r := db.Records[i]
+		rank, ok := rankTerm(prefix, term, normalizeTerm(r.Name) == term)
+		if !ok {
+			continue
+		}
+		matched := r.Name
+		if normalizeTerm(r.Name) != term {
+			for _, et := range r.EntryTerms {
+				if normalizeTerm(et) == term {
+					matched = et
+					break
+				}
+			}
+		}
+		if prev, seen := best[i]; !seen || rank < prev.Rank || (rank == prev.Rank && len(matched) < len(prev.Matched)) {
+			best[i] = Suggestion{UI: r.UI, Name: r.Name, Matched: matched, Rank: rank}
+		}
+	}
+	out := make([]Suggestion, 0, len(best))
+	for _, s := range best {
+		out = append(out, s)
+	}
+	return rankSuggestions(out, limit)
+}
+
+// Match ranks, best first.
+const (
+	rankExactHeading = iota
+	rankExactEntry
+	rankHeadingPrefix
+	rankEntryPrefix
+	rankWordPrefix
+	rankContains
+	rankSearchOnly
+)
+
+// rankTerm scores a normalized term against a normalized prefix.
+// ok is false when the term does not match at all.
+func rankTerm(prefix, term string, heading bool) (rank int, ok bool) {
+	switch {
+	case term == prefix && heading:
+		return rankExactHeading, true
+	case term == prefix:
+		return rankExactEntry, true
+	case strings.HasPrefix(term, prefix) && heading:
+		return rankHeadingPrefix, true
+	case strings.HasPrefix(term, prefix):
+		return rankEntryPrefix, true
+	case strings.Contains(" "+term, " "+prefix):
+		return rankWordPrefix, true
+	case strings.Contains(term, prefix):
+		return rankContains, true
+	}
+	return 0, false
+}
+
+// bestMatch picks the heading or entry term that best matches prefix.
+func bestMatch(prefix, ui, heading string, entries []string) Suggestion {
+	s := Suggestion{UI: ui, Name: heading, Rank: rankSearchOnly}
+	consider := func(term string, isHeading bool) {
+		rank, ok := rankTerm(prefix, normalizeTerm(term), isHeading)
+		if !ok {
+			return
+		}
+		if rank < s.Rank || (rank == s.Rank && len(term) < len(s.Matched)) {
+			s.Rank = rank
+			s.Matched = term
+		}
+	}
+	consider(heading, true)
+	for _, e := range entries {
+		consider(e, false)
+	}
+	return s
+}
+
+func rankSuggestions(s []Suggestion, limit int) []Suggestion {
+	sort.SliceStable(s, func(i, j int) bool {
+		if s[i].Rank != s[j].Rank {
+			return s[i].Rank < s[j].Rank
+		}
+		if len(s[i].Matched) != len(s[j].Matched) {
+			return len(s[i].Matched) < len(s[j].Matched)
+		}
+		return s[i].Name < s[j].Name
+	})
+	if len(s) > limit {
+		s = s[:limit]
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package mesh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocalDB_Suggest(t *testing.T) {
+	records, _ := ParseASCII(strings.NewReader(asciiTree))
+	db := NewLocalDB("test.bin", records)
+
+	got := db.Suggest("neuro", 10)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 suggestions, got %+v", got)
+	}
+	// Heading prefixes outrank entry-term prefixes; shorter matches win ties.
+	if got[0].Name != "Neurologic Manifestations" {
+		t.Errorf("expected Neurologic Manifestations first, got %+v", got[0])
+	}
+
+	got = db.Suggest("cognitive impairment", 10)
+	if len(got) != 1 || got[0].UI != "D060825" || got[0].Matched != "Cognitive Impairment" {
+		t.Fatalf("expected entry-term match for D060825, got %+v", got)
+	}
+
+	got = db.Suggest("manifestations", 1)
+	if len(got) != 1 {
+		t.Fatalf("expected limit to cap results, got %d", len(got))
+	}
+}
+
+func TestSuggest_Online(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/esearch.fcgi":
+			if got := r.URL.Query().Get("term"); got != "heart attack*" {
+				t.Errorf("expected truncated term, got %q", got)
+			}
+			w.Write([]byte(`{"esearchresult":{"count":"1","idlist":["68009203"]}}`))
+		case "/esummary.fcgi":
+			w.Write([]byte(`{"result":{"68009203":{"uid":"68009203","ds_meshui":"D009203",
+				"ds_meshterms":["Myocardial Infarction","Heart Attack","Infarction, Myocardial"]}}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	got, err := c.Suggest(context.Background(), "Heart  Attack", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 suggestion, got %+v", got)
+	}
+	if got[0].Name != "Myocardial Infarction" || got[0].Matched != "Heart Attack" {
+		t.Errorf("unexpected suggestion: %+v", got[0])
+	}
+}
+
+func TestSuggest_EmptyPrefix(t *testing.T) {
+	c := newTestClient(t, "http://example.invalid")
+	if _, err := c.Suggest(context.Background(), "  ", 5); err == nil {
+		t.Fatal("expected error for empty prefix")
+	}
+}
@@ -0,0 +1,368 @@
+package mesh
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TreeNode is a descriptor at one position in the MeSH hierarchy.
+// ChildCount is the number of positions directly beneath it; Children is
+// filled only when a deeper tree was requested.
+type TreeNode struct {
+	UI         string     `json:"ui"`
+	Name       string     `json:"name"`
+	TreeNumber string     `json:"tree_number"`
+	ChildCount int        `json:"child_count"`
+	Children   []TreeNode `json:"children,omitempty"`
+}
+
+// TreePath places a descriptor at one of its tree numbers, with the chain of
+// ancestors from the category root down to its immediate parent.
+type TreePath struct {
+	TreeNumber string     `json:"tree_number"`
+	Ancestors  []TreeNode `json:"ancestors"`
+	Children   []TreeNode `json:"children"`
+}
+
+// Hierarchy is a descriptor together with every position it occupies in the tree.
+type Hierarchy struct {
+	Record *MeSHRecord `json:"record"`
+	Paths  []TreePath  `json:"paths"`
+}
+
+// Children returns the descriptors directly beneath ui at any of its tree positions.
+// ui may be a MeSH UI (D005600), an Entrez MeSH UID (68005600), or a term.
+func (c *Client) Children(ctx context.Context, ui string) ([]MeSHRecord, error) {
+	if c.local != nil {
+		if recs, ok := c.local.Children(ui); ok {
+			return recs, nil
+		}
+	}
+	rec, err := c.resolveSummary(ctx, ui)
+	if err != nil {
+		return nil, err
+	}
+	var uids []string
+	for _, link := range rec.IdxLinks {
+		for _, child := range link.Children {
+			uids = append(uids, string(child))
+		}
+	}
+	return c.fetchRecords(ctx, uids, rec.UID)
+}
+
+// Parents returns the descriptors directly above ui at any of its tree positions.
+func (c *Client) Parents(ctx context.Context, ui string) ([]MeSHRecord, error) {
+	if c.local != nil {
+		if recs, ok := c.local.Parents(ui); ok {
+			return recs, nil
+		}
+	}
+	rec, err := c.resolveSummary(ctx, ui)
+	if err != nil {
+		return nil, err
+	}
+	var uids []string
+	for _, link := range rec.IdxLinks {
+		if link.Parent != "" {
+			uids = append(uids, string(link.Parent))
+		}
+	}
+	return c.fetchRecords(ctx, uids, rec.UID)
+}
+
+// Siblings returns descriptors that share a parent with ui, excluding ui itself.
+func (c *Client) Siblings(ctx context.Context, ui string) ([]MeSHRecord, error) {
+	if c.local != nil {
+		if recs, ok := c.local.Siblings(ui); ok {
+			return recs, nil
+		}
+	}
+	rec, err := c.resolveSummary(ctx, ui)
+	if err != nil {
+		return nil, err
+	}
+	var parentUIDs []string
+	for _, link := range rec.IdxLinks {
+		if link.Parent != "" {
+			parentUIDs = append(parentUIDs, string(link.Parent))
+		}
+	}
+	if len(parentUIDs) == 0 {
+		return nil, nil
+	}
+	parents, err := c.fetchSummaries(ctx, dedupe(parentUIDs, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Only children under the parent positions this descriptor occupies count as siblings.
+	var uids []string
+	for _, link := range rec.IdxLinks {
+		parent, ok := parents[string(link.Parent)]
+		if !ok {
+			continue
+		}
+		parentNum := parentTreeNumber(link.TreeNum)
+		for _, pl := range parent.IdxLinks {
+			if pl.TreeNum != parentNum {
+				continue
+			}
+			for _, child := range pl.Children {
+				uids = append(uids, string(child))
+			}
+		}
+	}
+	return c.fetchRecords(ctx, uids, rec.UID)
+}
+
+// Tree resolves a term and returns every tree position it occupies, with the
+// ancestor chain and descendants to the given depth (1 = immediate children).
+func (c *Client) Tree(ctx context.Context, term string, depth int) (*Hierarchy, error) {
+	if depth < 1 {
+		depth = 1
+	}
+	if c.local != nil {
+		if h, ok := c.local.Tree(term, depth); ok {
+			return h, nil
+		}
+	}
+	rec, err := c.resolveSummary(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hierarchy{Record: recordFromSummary(*rec)}
+	seen := map[string]esummaryRecord{rec.UID: *rec}
+
+	for _, link := range rec.IdxLinks {
+		if link.TreeNum == "" {
+			continue
+		}
+		path := TreePath{TreeNumber: link.TreeNum}
+
+		ancestors, err := c.ancestors(ctx, link, seen)
+		if err != nil {
+			return nil, err
+		}
+		path.Ancestors = ancestors
+
+		children, err := c.childNodes(ctx, link, seen, depth)
+		if err != nil {
+			return nil, err
+		}
+		path.Children = children
+
+		h.Paths = append(h.Paths, path)
+	}
+
+	return h, nil
+}
+
+// ancestors walks parent links from link up to the category root, returning
+// nodes root first. seen memoizes summaries across tree positions.
+func (c *Client) ancestors(ctx context.Context, link esummaryLink, seen map[string]esummaryRecord) ([]TreeNode, error) {
+	var chain []TreeNode
+	current := link
+	for current.Parent != "" {
+		parentNum := parentTreeNumber(current.TreeNum)
+		if parentNum == "" {
+			break
+		}
+		parent, err := c.cachedSummary(ctx, string(current.Parent), seen)
+		if err != nil {
+			return nil, err
+		}
+
+		next, ok := linkForTreeNumber(parent, parentNum)
+		if !ok {
+			break
+		}
+		chain = append(chain, TreeNode{UI: parent.MeshUI, Name: headingOf(parent), TreeNumber: parentNum})
+		current = next
+	}
+
+	// Reverse so the root comes first.
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// childNodes returns the descendants of link down to depth levels.
+func (c *Client) childNodes(ctx context.Context, link esummaryLink, seen map[string]esummaryRecord, depth int) ([]TreeNode, error) {
+	var missing []string
+	for _, child := range link.Children {
+		if _, ok := seen[string(child)]; !ok {
+			missing = append(missing, string(child))
+		}
+	}
+	if len(missing) > 0 {
+		recs, err := c.fetchSummaries(ctx, dedupe(missing, ""))
+		if err != nil {
+			return nil, err
+		}
+		for uid, r := range recs {
+			seen[uid] = r
+		}
+	}
+
+	var nodes []TreeNode
+	for _, child := range link.Children {
+		r, ok := seen[string(child)]
+		if !ok {
+			continue
+		}
+		node := TreeNode{UI: r.MeshUI, Name: headingOf(r)}
+		for _, cl := range r.IdxLinks {
+			if parentTreeNumber(cl.TreeNum) != link.TreeNum {
+				continue
+			}
+			node.TreeNumber = cl.TreeNum
+			node.ChildCount = len(cl.Children)
+			if depth > 1 && node.ChildCount > 0 {
+				grandchildren, err := c.childNodes(ctx, cl, seen, depth-1)
+				if err != nil {
+					return nil, err
+				}
+				node.Children = grandchildren
+			}
+			break
+		}
+		nodes = append(nodes, node)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].TreeNumber < nodes[j].TreeNumber })
+	return nodes, nil
+}
+
+func (c *Client) cachedSummary(ctx context.Context, uid string, seen map[string]esummaryRecord) (esummaryRecord, error) {
+	if r, ok := seen[uid]; ok {
+		return r, nil
+	}
+	recs, err := c.fetchSummaries(ctx, []string{uid})
+	if err != nil {
+		return esummaryRecord{}, err
+	}
+	r, ok := recs[uid]
+	if !ok {
+		return esummaryRecord{}, fmt.Errorf("MeSH UID %s not found in response", uid)
+	}
+	seen[uid] = r
+	return r, nil
+}
+
+// resolveSummary accepts a MeSH UI, an Entrez UID, or a free-text term and
+// returns the matching esummary record.
+func (c *Client) resolveSummary(ctx context.Context, ref string) (*esummaryRecord, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("MeSH term cannot be empty")
+	}
+
+	uid, ok := entrezUIDFor(ref)
+	if !ok {
+		ids, err := c.searchMeSH(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("MeSH term %q %w", ref, ErrNotFound)
+		}
+		uid = ids[0]
+	}
+
+	recs, err := c.fetchSummaries(ctx, []string{uid})
+	if err != nil {
+		return nil, err
+	}
+	rec, ok := recs[uid]
+	if !ok {
+		return nil, fmt.Errorf("MeSH UID %s not found in response", uid)
+	}
+	return &rec, nil
+}
+
+// fetchRecords fetches summaries for uids (deduplicated, excluding self) and
+// returns them as records in the order given.
+func (c *Client) fetchRecords(ctx context.Context, uids []string, self string) ([]MeSHRecord, error) {
+	uids = dedupe(uids, self)
+	if len(uids) == 0 {
+		return nil, nil
+	}
+	recs, err := c.fetchSummaries(ctx, uids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MeSHRecord, 0, len(uids))
+	for _, uid := range uids {
+		if r, ok := recs[uid]; ok {
+			out = append(out, *recordFromSummary(r))
+		}
+	}
+	return out, nil
+}
+
+// entrezUIDFor maps a descriptor UI like "D005600" to its Entrez UID
+// "68005600". All-digit input is treated as an Entrez UID already.
+func entrezUIDFor(ref string) (string, bool) {
+	if isDigits(ref) {
+		return ref, true
+	}
+	if len(ref) > 1 && (ref[0] == 'D' || ref[0] == 'd') && isDigits(ref[1:]) {
+		return "68" + ref[1:], true
+	}
+	return "", false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parentTreeNumber strips the last segment: "C10.597.606" → "C10.597".
+// Category roots like "C10" have no parent number.
+func parentTreeNumber(tn string) string {
+	i := strings.LastIndex(tn, ".")
+	if i < 0 {
+		return ""
+	}
+	return tn[:i]
+}
+
+func linkForTreeNumber(rec esummaryRecord, tn string) (esummaryLink, bool) {
+	for _, l := range rec.IdxLinks {
+		if l.TreeNum == tn {
+			return l, true
+		}
+	}
+	return esummaryLink{}, false
+}
+
+func headingOf(rec esummaryRecord) string {
+	if len(rec.MeshTerms) > 0 {
+		return rec.MeshTerms[0]
+	}
+	return ""
+}
+
+func dedupe(ids []string, exclude string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || id == exclude || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
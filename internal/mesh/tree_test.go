@@ -0,0 +1,184 @@
+package mesh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// treeFixture is a small slice of the C10 hierarchy:
+//
+//	C10 Nervous System Diseases
+//	└── C10.597 Neurologic Manifestations
+//	    ├── C10.597.606 Neurobehavioral Manifestations
+//	    │   └── C10.597.606.150 Cognitive Dysfunction
+//	    └── C10.597.617 Neuromuscular Manifestations
+var treeFixture = map[string]string{
+	"68009422": `{"uid":"68009422","ds_meshui":"D009422","ds_meshterms":["Nervous System Diseases"],
+		"ds_idxlinks":[{"parent":0,"treenum":"C10","children":[68009461]}]}`,
+	"68009461": `{"uid":"68009461","ds_meshui":"D009461","ds_meshterms":["Neurologic Manifestations"],
+		"ds_idxlinks":[{"parent":68009422,"treenum":"C10.597","children":[68019954,68020879]}]}`,
+	"68019954": `{"uid":"68019954","ds_meshui":"D019954","ds_meshterms":["Neurobehavioral Manifestations"],
+		"ds_idxlinks":[{"parent":68009461,"treenum":"C10.597.606","children":[68060825]}]}`,
+	"68020879": `{"uid":"68020879","ds_meshui":"D020879","ds_meshterms":["Neuromuscular Manifestations"],
+		"ds_idxlinks":[{"parent":"68009461","treenum":"C10.597.617","children":[]}]}`,
+	"68060825": `{"uid":"68060825","ds_meshui":"D060825","ds_meshterms":["Cognitive Dysfunction"],
+		"ds_idxlinks":[{"parent":68019954,"treenum":"C10.597.606.150","children":[]}]}`,
+}
+
+func newTreeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/esearch.fcgi":
+			w.Write([]byte(`{"esearchresult":{"count":"1","idlist":["68019954"]}}`))
+		case "/esummary.fcgi":
+			result := map[string]json.RawMessage{}
+			for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+				if raw, ok := treeFixture[id]; ok {
+					result[id] = json.RawMessage(raw)
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestChildren(t *testing.T) {
+	srv := newTreeServer(t)
+	c := newTestClient(t, srv.URL)
+
+	children, err := c.Children(context.Background(), "D019954")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(children) != 1 || children[0].Name != "Cognitive Dysfunction" {
+		t.Fatalf("expected [Cognitive Dysfunction], got %+v", children)
+	}
+}
+
+func TestParents(t *testing.T) {
+	srv := newTreeServer(t)
+	c := newTestClient(t, srv.URL)
+
+	parents, err := c.Parents(context.Background(), "68019954")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parents) != 1 || parents[0].UI != "D009461" {
+		t.Fatalf("expected [D009461], got %+v", parents)
+	}
+}
+
+func TestSiblings_ExcludesSelf(t *testing.T) {
+	srv := newTreeServer(t)
+	c := newTestClient(t, srv.URL)
+
+	siblings, err := c.Siblings(context.Background(), "D019954")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(siblings) != 1 || siblings[0].Name != "Neuromuscular Manifestations" {
+		t.Fatalf("expected [Neuromuscular Manifestations], got %+v", siblings)
+	}
+}
+
+func TestSiblings_RootHasNone(t *testing.T) {
+	srv := newTreeServer(t)
+	c := newTestClient(t, srv.URL)
+
+	siblings, err := c.Siblings(context.Background(), "D009422")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(siblings) != 0 {
+		t.Fatalf("expected no siblings for category root, got %+v", siblings)
+	}
+}
+
+func TestTree(t *testing.T) {
+	srv := newTreeServer(t)
+	c := newTestClient(t, srv.URL)
+
+	h, err := c.Tree(context.Background(), "Neurobehavioral Manifestations", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Record.UI != "D019954" {
+		t.Errorf("expected record D019954, got %q", h.Record.UI)
+	}
+	if len(h.Paths) != 1 {
+		t.Fatalf("expected 1 tree path, got %d", len(h.Paths))
+	}
+
+	p := h.Paths[0]
+	if p.TreeNumber != "C10.597.606" {
+		t.Errorf("expected tree number C10.597.606, got %q", p.TreeNumber)
+	}
+	if len(p.Ancestors) != 2 {
+		t.Fatalf("expected 2 ancestors, got %+v", p.Ancestors)
+	}
+	if p.Ancestors[0].TreeNumber != "C10" || p.Ancestors[1].TreeNumber != "C10.597" {
+		t.Errorf("expected ancestors root-first [C10 C10.597], got %+v", p.Ancestors)
+	}
+	if len(p.Children) != 1 || p.Children[0].TreeNumber != "C10.597.606.150" {
+		t.Errorf("expected child C10.597.606.150, got %+v", p.Children)
+	}
+}
+
+func TestTree_Depth(t *testing.T) {
+	srv := newTreeServer(t)
+	c := newTestClient(t, srv.URL)
+
+	h, err := c.Tree(context.Background(), "D009461", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	children := h.Paths[0].Children
+	if len(children) != 2 {
+		t.Fatalf("expected 2 children, got %+v", children)
+	}
+	nb := children[0]
+	if nb.ChildCount != 1 || len(nb.Children) != 1 || nb.Children[0].Name != "Cognitive Dysfunction" {
+		t.Errorf("expected grandchild beneath Neurobehavioral Manifestations, got %+v", nb)
+	}
+	if children[1].ChildCount != 0 || len(children[1].Children) != 0 {
+		t.Errorf("expected leaf Neuromuscular Manifestations, got %+v", children[1])
+	}
+}
+
+func TestEntrezUIDFor(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"D005600", "68005600", true},
+		{"68005600", "68005600", true},
+		{"Fragile X Syndrome", "", false},
+		{"D", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := entrezUIDFor(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("entrezUIDFor(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParentTreeNumber(t *testing.T) {
+	if got := parentTreeNumber("C10.597.606"); got != "C10.597" {
+		t.Errorf("expected C10.597, got %q", got)
+	}
+	if got := parentTreeNumber("C10"); got != "" {
+		t.Errorf("expected empty parent for category root, got %q", got)
+	}
+}
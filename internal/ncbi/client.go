@@ -11,8 +11,10 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
 	"golang.org/x/time/rate"
 )
 
@@ -47,8 +49,33 @@ type BaseClient struct {
 	HTTPClient *http.Client
 	Limiter    *rate.Limiter
 	MaxBytes   int64
+	// Log, if set, receives a line for every request and every rate-limit
+	// wait, for -v.
+	Log io.Writer
+	// Cache, if set, records the responses to DoGet and GetURL requests so
+	// an offline client can replay them.
+	Cache *cache.Store
+	// Offline forbids network requests: DoGet and GetURL are answered from
+	// Cache or fail with an OfflineError, and Send always fails. Missing
+	// lists the requests that failed.
+	Offline bool
+
+	mu      sync.Mutex
+	missing []string
+
+	// flights shares one response between identical concurrent DoGet
+	// calls, e.g. from batch jobs that fetch the same records.
+	flights flightGroup
 }
 
+// StatusError is an unsuccessful HTTP response from NCBI or another API.
+type StatusError struct {
+	Code int
+	msg  string
+}
+
+func (e *StatusError) Error() string { return e.msg }
+
 // Option configures a BaseClient.
 type Option func(*BaseClient)
 
@@ -87,6 +114,11 @@ func WithMaxResponseBytes(n int64) Option {
 	return func(c *BaseClient) { c.MaxBytes = n }
 }
 
+// WithLog writes a line for every request and rate-limit wait to w.
+func WithLog(w io.Writer) Option {
+	return func(c *BaseClient) { c.Log = w }
+}
+
 // NewBaseClient creates a new NCBI base client with the given options.
 func NewBaseClient(opts ...Option) *BaseClient {
 	c := &BaseClient{
@@ -106,8 +138,16 @@ func NewBaseClient(opts ...Option) *BaseClient {
 }
 
 // DoGet performs a rate-limited GET request with common NCBI parameters
-// and response size limits. Returns the response body.
+// and response size limits. Returns the response body. Identical requests
+// made while one is in flight wait for it and share its response, which
+// callers must not modify. A caller whose context ends stops waiting
+// without affecting the others.
 func (c *BaseClient) DoGet(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	key := paramsKey(endpoint, params)
+	if c.Offline {
+		return c.replay(key)
+	}
+
 	// Add common NCBI params once per request.
 	if c.APIKey != "" {
 		params.Set("api_key", c.APIKey)
@@ -124,11 +164,26 @@ func (c *BaseClient) DoGet(ctx context.Context, endpoint string, params url.Valu
 		return nil, fmt.Errorf("building URL: %w", err)
 	}
 	fullURL := u + "?" + params.Encode()
+	var desc string
+	if c.Log != nil {
+		desc = endpoint + " " + logParams(params)
+	}
+	body, err := c.flights.do(ctx, fullURL, func(ctx context.Context) ([]byte, error) {
+		return c.get(ctx, endpoint, fullURL, desc)
+	})
+	if err == nil {
+		c.record(key, params, body)
+	}
+	return body, err
+}
 
+// get performs DoGet's request, retrying rate-limit responses. desc
+// describes the request for the log.
+func (c *BaseClient) get(ctx context.Context, endpoint, fullURL, desc string) ([]byte, error) {
 	for attempt := 0; attempt <= ncbiMaxRetries; attempt++ {
 		// Wait for rate limiter token (respects context cancellation).
-		if err := c.Limiter.Wait(ctx); err != nil {
-			return nil, fmt.Errorf("rate limit wait: %w", err)
+		if err := c.wait(ctx); err != nil {
+			return nil, err
 		}
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
@@ -136,15 +191,20 @@ func (c *BaseClient) DoGet(ctx context.Context, endpoint string, params url.Valu
 			return nil, fmt.Errorf("creating request: %w", err)
 		}
 
+		start := time.Now()
 		resp, err := c.HTTPClient.Do(req)
 		if err != nil {
-			return nil, fmt.Errorf("executing request: %w", err)
+			c.logf("GET %s: %v", desc, withoutURL(err))
+			return nil, fmt.Errorf("executing request: %w", redactURL(err))
 		}
 
 		if resp.StatusCode == http.StatusTooManyRequests {
 			if attempt >= ncbiMaxRetries {
 				resp.Body.Close()
-				return nil, fmt.Errorf("NCBI rate limit exceeded (HTTP 429 after %d retries). Consider using an API key with --api-key or NCBI_API_KEY env var", ncbiMaxRetries)
+				return nil, &StatusError{
+					Code: http.StatusTooManyRequests,
+					msg:  fmt.Sprintf("NCBI rate limit exceeded (HTTP 429 after %d retries). Consider using an API key with --api-key or NCBI_API_KEY env var", ncbiMaxRetries),
+				}
 			}
 
 			retryAfter := retryAfterDuration(resp.Header.Get("Retry-After"))
@@ -156,6 +216,7 @@ func (c *BaseClient) DoGet(ctx context.Context, endpoint string, params url.Valu
 					retryAfter = ncbiMaxRetryWait
 				}
 			}
+			c.logf("GET %s -> 429 in %s; retrying in %s", desc, since(start), retryAfter)
 			if err := sleepWithContext(ctx, retryAfter); err != nil {
 				return nil, fmt.Errorf("rate limit retry canceled: %w", err)
 			}
@@ -165,13 +226,15 @@ func (c *BaseClient) DoGet(ctx context.Context, endpoint string, params url.Valu
 
 		if resp.StatusCode != http.StatusOK {
 			defer resp.Body.Close()
-			return nil, fmt.Errorf("NCBI returned HTTP %d for %s", resp.StatusCode, endpoint)
+			c.logf("GET %s -> %d in %s", desc, resp.StatusCode, since(start))
+			return nil, &StatusError{Code: resp.StatusCode, msg: fmt.Sprintf("NCBI returned HTTP %d for %s", resp.StatusCode, endpoint)}
 		}
 
 		// Guard against unbounded reads: read up to MaxBytes+1 to detect oversized responses.
 		r := io.LimitReader(resp.Body, c.MaxBytes+1)
 		body, err := io.ReadAll(r)
 		resp.Body.Close()
+		c.logf("GET %s -> %d in %s, %d bytes", desc, resp.StatusCode, since(start), len(body))
 		if err != nil {
 			return nil, fmt.Errorf("reading response: %w", err)
 		}
@@ -185,6 +248,63 @@ func (c *BaseClient) DoGet(ctx context.Context, endpoint string, params url.Valu
 	return nil, fmt.Errorf("unreachable request loop")
 }
 
+// GetURL performs a rate-limited GET of a full URL outside the E-utilities,
+// such as another bibliographic API, with the same response size limit. A
+// 404 returns a nil body, so lookups of unknown IDs are not errors.
+func (c *BaseClient) GetURL(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	key := urlKey(req)
+	if c.Offline {
+		return c.replay(key)
+	}
+	body, err := c.Send(req)
+	if err == nil {
+		c.record(key, req.URL.Query(), body)
+	}
+	return body, err
+}
+
+// Send performs a rate-limited request built by the caller, for APIs that
+// need a method, body, or headers GetURL does not set. Responses are
+// handled as in GetURL; they are not cached, and an offline client fails.
+func (c *BaseClient) Send(req *http.Request) ([]byte, error) {
+	if c.Offline {
+		return nil, c.Refuse(req.Method + " " + req.URL.Host + req.URL.Path)
+	}
+	if err := c.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	desc := req.URL.Host + req.URL.Path
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		c.logf("%s %s: %v", req.Method, desc, withoutURL(err))
+		return nil, fmt.Errorf("executing request: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.logf("%s %s -> %d in %s", req.Method, desc, resp.StatusCode, since(start))
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Code: resp.StatusCode, msg: fmt.Sprintf("HTTP %d", resp.StatusCode)}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.MaxBytes+1))
+	c.logf("%s %s -> %d in %s, %d bytes", req.Method, desc, resp.StatusCode, since(start), len(body))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if int64(len(body)) > c.MaxBytes {
+		return nil, fmt.Errorf("response exceeds maximum size of %d bytes", c.MaxBytes)
+	}
+	return body, nil
+}
+
 func retryAfterDuration(v string) time.Duration {
 	v = strings.TrimSpace(v)
 	if v == "" {
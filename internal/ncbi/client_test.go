@@ -2,14 +2,20 @@ package ncbi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
 )
 
 func TestNewBaseClient_Defaults(t *testing.T) {
@@ -144,7 +150,7 @@ func TestDoGet_ConcurrentRateLimitNoKey(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, _ = c.DoGet(context.Background(), "test.fcgi", make(map[string][]string))
+			_, _ = c.DoGet(context.Background(), "test.fcgi", map[string][]string{"id": {strconv.Itoa(i)}})
 		}()
 	}
 	wg.Wait()
@@ -201,7 +207,7 @@ func TestDoGet_ConcurrentRateLimitWithKey(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, _ = c.DoGet(context.Background(), "test.fcgi", make(map[string][]string))
+			_, _ = c.DoGet(context.Background(), "test.fcgi", map[string][]string{"id": {strconv.Itoa(i)}})
 		}()
 	}
 	wg.Wait()
@@ -288,6 +294,24 @@ func TestDoGet_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestDoGet_TransportErrorHidesKey(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close() // connections are refused
+
+	c := NewBaseClient(WithBaseURL(srv.URL), WithAPIKey("secret-key"))
+	_, err := c.DoGet(context.Background(), "esearch.fcgi", url.Values{"term": {"x"}})
+	if err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-key") || strings.Contains(err.Error(), "?") {
+		t.Errorf("error leaks the request query: %v", err)
+	}
+	var uerr *url.Error
+	if !errors.As(err, &uerr) {
+		t.Errorf("error %v no longer wraps *url.Error", err)
+	}
+}
+
 func TestDoGet_HTTPError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -339,3 +363,265 @@ func TestDoGet_URLJoinPath(t *testing.T) {
 
 	fmt.Println("received path:", receivedPath)
 }
+
+func TestGetURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if r.URL.Query().Get("api_key") != "" {
+				t.Errorf("NCBI parameters should not be sent elsewhere: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte("body"))
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewBaseClient(WithAPIKey("test"))
+	ctx := context.Background()
+	if body, err := c.GetURL(ctx, srv.URL+"/ok?x=1"); err != nil || string(body) != "body" {
+		t.Errorf("GetURL = %q, %v", body, err)
+	}
+	if body, err := c.GetURL(ctx, srv.URL+"/missing"); err != nil || body != nil {
+		t.Errorf("404 should return a nil body, got %q, %v", body, err)
+	}
+	if _, err := c.GetURL(ctx, srv.URL+"/broken"); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected an HTTP 500 error, got %v", err)
+	}
+}
+
+func TestDoGet_SharesInFlightRequests(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		hits = make(map[string]int)
+	)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Query().Get("id")]++
+		mu.Unlock()
+		<-release
+		fmt.Fprintf(w, "record %s", r.URL.Query().Get("id"))
+	}))
+	defer srv.Close()
+
+	c := NewBaseClient(WithBaseURL(srv.URL), WithAPIKey("k"))
+	ids := []string{"1", "1", "1", "1", "2"}
+	joined := make(chan struct{}, len(ids))
+	c.flights.joined = func() { joined <- struct{}{} }
+	bodies := make([]string, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := c.DoGet(context.Background(), "efetch.fcgi", map[string][]string{"id": {id}})
+			if err != nil {
+				t.Errorf("DoGet(%s): %v", id, err)
+			}
+			bodies[i] = string(body)
+		}()
+	}
+	// Let every caller join before the first response returns.
+	for range ids {
+		<-joined
+	}
+	close(release)
+	wg.Wait()
+
+	if hits["1"] != 1 || hits["2"] != 1 {
+		t.Errorf("expected one request per distinct URL, got %v", hits)
+	}
+	for i, id := range ids {
+		if bodies[i] != "record "+id {
+			t.Errorf("caller %d got %q", i, bodies[i])
+		}
+	}
+
+	// Once the first request is done, a repeat goes to the server again.
+	if _, err := c.DoGet(context.Background(), "efetch.fcgi", map[string][]string{"id": {"1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if hits["1"] != 2 {
+		t.Errorf("expected a sequential repeat to be sent, got %d requests", hits["1"])
+	}
+}
+
+func TestDoGet_InFlightCallerCanceled(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	arrived := make(chan struct{}, 1)
+	canceled := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "2" {
+			arrived <- struct{}{}
+			<-r.Context().Done()
+			close(canceled)
+			return
+		}
+		hits.Add(1)
+		<-release
+		fmt.Fprint(w, "record")
+	}))
+	defer srv.Close()
+
+	c := NewBaseClient(WithBaseURL(srv.URL), WithAPIKey("k"))
+	joined := make(chan struct{}, 2)
+	c.flights.joined = func() { joined <- struct{}{} }
+	params := func() url.Values { return url.Values{"id": {"1"}} }
+
+	// One caller giving up leaves the shared request to the other.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.DoGet(ctx, "efetch.fcgi", params())
+		first <- err
+	}()
+	second := make(chan string, 1)
+	go func() {
+		body, err := c.DoGet(context.Background(), "efetch.fcgi", params())
+		if err != nil {
+			t.Errorf("second caller: %v", err)
+		}
+		second <- string(body)
+	}()
+	<-joined
+	<-joined
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled caller: err = %v, want context.Canceled", err)
+	}
+	close(release)
+	if body := <-second; body != "record" {
+		t.Errorf("second caller got %q", body)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("expected one shared request, got %d", hits.Load())
+	}
+
+	// Once every caller has given up, the request itself is canceled.
+	ctx, cancel = context.WithCancel(context.Background())
+	go c.DoGet(ctx, "efetch.fcgi", url.Values{"id": {"2"}})
+	<-joined
+	<-arrived
+	cancel()
+	<-canceled
+}
+
+func TestDoGet_Log(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var log strings.Builder
+	c := NewBaseClient(WithBaseURL(srv.URL), WithAPIKey("secret-key-1234"), WithLog(&log))
+	params := map[string][]string{"db": {"pubmed"}, "id": {"1,2,3"}}
+	if _, err := c.DoGet(context.Background(), "efetch.fcgi", params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DoGet(context.Background(), "esearch.fcgi", map[string][]string{"term": {"asthma"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected two requests and a rate-limit wait, got:\n%s", log.String())
+	}
+	if !strings.HasPrefix(lines[0], "pubmed: GET efetch.fcgi api_key=****1234 db=pubmed id=<3 IDs> -> 200 in ") ||
+		!strings.HasSuffix(lines[0], ", 2 bytes") {
+		t.Errorf("unexpected request line %q", lines[0])
+	}
+	if strings.Contains(log.String(), "secret-key") || strings.Contains(log.String(), "email=") {
+		t.Errorf("log leaks the key or contact email:\n%s", log.String())
+	}
+	if !strings.HasPrefix(lines[1], "pubmed: rate limit: waited ") || !strings.HasSuffix(lines[1], "(10 requests/s with an API key)") {
+		t.Errorf("unexpected wait line %q", lines[1])
+	}
+}
+
+func TestCacheKeyOmitsContact(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+	store := cache.NewStore(t.TempDir(), 0)
+
+	online := NewBaseClient(WithBaseURL(srv.URL), WithAPIKey("secret"), WithCache(store))
+	online.Email = "me@example.org"
+	params := url.Values{"term": {"autism"}, "email": {"other@example.org"}}
+	if _, err := online.DoGet(context.Background(), "esearch.fcgi", params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := online.GetURL(context.Background(), srv.URL+"/works?mailto=me@example.org&search=fxs&api_key=k"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replayed under a different address, and reported without one.
+	offline := NewBaseClient(WithBaseURL(srv.URL), WithCache(store), WithOffline())
+	if _, err := offline.DoGet(context.Background(), "esearch.fcgi", url.Values{"term": {"autism"}}); err != nil {
+		t.Errorf("DoGet replay: %v", err)
+	}
+	if _, err := offline.GetURL(context.Background(), srv.URL+"/works?search=fxs&mailto=you@example.org"); err != nil {
+		t.Errorf("GetURL replay: %v", err)
+	}
+	_, err := offline.GetURL(context.Background(), srv.URL+"/works?search=rett&mailto=me@example.org&tool=x")
+	var oe *OfflineError
+	if !errors.As(err, &oe) || strings.Contains(oe.Request, "example.org") || strings.Contains(oe.Request, "tool") {
+		t.Errorf("expected an OfflineError without contact parameters, got %v", err)
+	}
+}
+
+func TestOfflineReplay(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, "response to %s", r.URL.Query().Get("term"))
+	}))
+	defer srv.Close()
+	store := cache.NewStore(t.TempDir(), 0)
+
+	online := NewBaseClient(WithBaseURL(srv.URL), WithAPIKey("secret"), WithCache(store))
+	if _, err := online.DoGet(context.Background(), "esearch.fcgi", url.Values{"term": {"autism"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := online.DoGet(context.Background(), "efetch.fcgi", url.Values{"WebEnv": {"MCID_1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := online.GetURL(context.Background(), srv.URL+"/works?term=fxs"); err != nil {
+		t.Fatal(err)
+	}
+
+	offline := NewBaseClient(WithBaseURL(srv.URL), WithCache(store), WithOffline())
+	body, err := offline.DoGet(context.Background(), "esearch.fcgi", url.Values{"term": {"autism"}})
+	if err != nil || string(body) != "response to autism" {
+		t.Errorf("replay = %q, %v", body, err)
+	}
+	if body, err := offline.GetURL(context.Background(), srv.URL+"/works?term=fxs"); err != nil || string(body) != "response to fxs" {
+		t.Errorf("replay of GetURL = %q, %v", body, err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err = offline.DoGet(context.Background(), "esearch.fcgi", url.Values{"term": {"fragile x"}})
+		var oe *OfflineError
+		if !errors.As(err, &oe) || oe.Request != "esearch.fcgi?term=fragile x" {
+			t.Errorf("expected an OfflineError, got %v", err)
+		}
+	}
+	if _, err := offline.DoGet(context.Background(), "efetch.fcgi", url.Values{"WebEnv": {"MCID_1"}}); err == nil {
+		t.Error("history requests should not be replayed")
+	}
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/batch", nil)
+	if _, err := offline.Send(req); err == nil {
+		t.Error("Send should fail offline")
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, all online, got %d", requests)
+	}
+	want := []string{"esearch.fcgi?term=fragile x", "efetch.fcgi?WebEnv=MCID_1", "POST " + strings.TrimPrefix(srv.URL, "http://") + "/batch"}
+	if got := offline.Missing(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Missing() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package ncbi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// logValueMax is the longest parameter value the log shows in full; longer
+// ones, such as the ID lists of efetch, are shortened.
+const logValueMax = 60
+
+// wait takes a token from the rate limiter, logging how long it waited
+// when the limit delayed the request.
+func (c *BaseClient) wait(ctx context.Context) error {
+	start := time.Now()
+	if err := c.Limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait: %w", err)
+	}
+	if waited := time.Since(start); waited >= time.Millisecond {
+		limit := fmt.Sprintf("%d requests/s without an API key", RateWithoutKey)
+		if c.APIKey != "" {
+			limit = fmt.Sprintf("%d requests/s with an API key", RateWithKey)
+		}
+		c.logf("rate limit: waited %s (%s)", waited.Round(time.Millisecond), limit)
+	}
+	return nil
+}
+
+func (c *BaseClient) logf(format string, args ...any) {
+	if c.Log == nil {
+		return
+	}
+	fmt.Fprintf(c.Log, "pubmed: "+format+"\n", args...)
+}
+
+// logParams renders request parameters for the log: tool and email are
+// left out, the API key is masked to its last four characters, and long
+// values are shortened.
+func logParams(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "tool" && k != "email" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := params.Get(k)
+		switch {
+		case k == "api_key":
+			v = maskKey(v)
+		case k == "id" && strings.Contains(v, ","):
+			v = fmt.Sprintf("<%d IDs>", strings.Count(v, ",")+1)
+		case len([]rune(v)) > logValueMax:
+			v = string([]rune(v)[:logValueMax]) + "..."
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return strings.Join(parts, " ")
+}
+
+// withoutURL drops the request URL, which carries the API key, from a
+// transport error.
+func withoutURL(err error) error {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return uerr.Err
+	}
+	return err
+}
+
+// redactURL cuts the query, which carries the API key, from the URL of a
+// transport error, keeping the error's type so it is still recognized as
+// a failure to reach the service.
+func redactURL(err error) error {
+	var uerr *url.Error
+	if !errors.As(err, &uerr) {
+		return err
+	}
+	u := uerr.URL
+	if i := strings.IndexByte(u, '?'); i >= 0 {
+		u = u[:i]
+	}
+	return &url.Error{Op: uerr.Op, URL: u, Err: uerr.Err}
+}
+
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
+
+func since(start time.Time) time.Duration {
+	return time.Since(start).Round(time.Millisecond)
+}
@@ -0,0 +1,126 @@
+package ncbi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+)
+
+// OfflineError is a request an offline client could not answer from its
+// cache.
+type OfflineError struct {
+	// Request is the request that was needed: the endpoint or URL and its
+	// parameters, without credentials.
+	Request string
+}
+
+func (e *OfflineError) Error() string {
+	return "offline: " + e.Request + " is not in the cache"
+}
+
+// WithCache records responses in store for replay when offline.
+func WithCache(store *cache.Store) Option {
+	return func(c *BaseClient) { c.Cache = store }
+}
+
+// WithOffline forbids network requests; see BaseClient.Offline.
+func WithOffline() Option {
+	return func(c *BaseClient) { c.Offline = true }
+}
+
+// Missing returns the requests an offline client could not answer, in the
+// order first made.
+func (c *BaseClient) Missing() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.missing...)
+}
+
+// replay answers the request cached under key, or refuses it.
+func (c *BaseClient) replay(key string) ([]byte, error) {
+	if c.Cache != nil {
+		if body, ok := c.Cache.Get(key); ok {
+			c.logf("offline: %s from cache, %d bytes", key, len(body))
+			return body, nil
+		}
+	}
+	return nil, c.Refuse(key)
+}
+
+// Refuse records that request could not be made offline and returns its
+// OfflineError, for clients making requests outside DoGet, GetURL, and
+// Send.
+func (c *BaseClient) Refuse(request string) error {
+	if r, err := url.QueryUnescape(request); err == nil {
+		request = r
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seen := false
+	for _, m := range c.missing {
+		seen = seen || m == request
+	}
+	if !seen {
+		c.missing = append(c.missing, request)
+	}
+	return &OfflineError{Request: request}
+}
+
+// record caches a response for replay. Requests naming an Entrez history
+// session are skipped: the WebEnv does not outlive the session, so an
+// offline run could never ask for them again.
+func (c *BaseClient) record(key string, params url.Values, body []byte) {
+	if c.Cache == nil || body == nil || params.Has("WebEnv") {
+		return
+	}
+	// A failed cache write only costs the entry offline.
+	_ = c.Cache.Put(key, body)
+}
+
+// contactParams identify the user or the tool to an API rather than the
+// response wanted, so cache keys, and the offline errors that show them,
+// leave them out.
+var contactParams = []string{"api_key", "email", "mailto", "tool"}
+
+// withoutContact returns params without contactParams, and reports
+// whether any were removed.
+func withoutContact(params url.Values) (url.Values, bool) {
+	var out url.Values
+	for _, p := range contactParams {
+		if !params.Has(p) {
+			continue
+		}
+		if out == nil {
+			out = make(url.Values, len(params))
+			for k, v := range params {
+				out[k] = v
+			}
+		}
+		delete(out, p)
+	}
+	if out == nil {
+		return params, false
+	}
+	return out, true
+}
+
+// paramsKey is the cache key of an E-utilities request.
+func paramsKey(endpoint string, params url.Values) string {
+	params, _ = withoutContact(params)
+	return endpoint + "?" + params.Encode()
+}
+
+// urlKey is the cache key of a GET request: its host, path, and query.
+func urlKey(req *http.Request) string {
+	key := req.URL.Host + req.URL.Path
+	query := req.URL.RawQuery
+	if params, stripped := withoutContact(req.URL.Query()); stripped {
+		query = params.Encode()
+	}
+	if query != "" {
+		key += "?" + query
+	}
+	return strings.TrimSuffix(key, "/")
+}
@@ -0,0 +1,149 @@
+package network
+
+import "math"
+
+// PageRank parameters.
+const (
+	damping       = 0.85
+	maxIterations = 100
+	tolerance     = 1e-10
+)
+
+// centrality holds a network's adjacency by node index. out follows edge
+// direction (both ways for undirected networks); neighbors ignores it.
+type centrality struct {
+	out       [][]int
+	weight    [][]float64
+	neighbors []map[int]bool
+}
+
+func newCentrality(n *Network) *centrality {
+	size := len(n.Nodes)
+	c := &centrality{out: make([][]int, size), weight: make([][]float64, size), neighbors: make([]map[int]bool, size)}
+	index := make(map[string]int, size)
+	for i, node := range n.Nodes {
+		index[node.ID] = i
+		c.neighbors[i] = make(map[int]bool)
+	}
+	for _, e := range n.Edges {
+		s, t := index[e.Source], index[e.Target]
+		w := float64(e.Weight)
+		if w <= 0 {
+			w = 1
+		}
+		c.out[s] = append(c.out[s], t)
+		c.weight[s] = append(c.weight[s], w)
+		if !n.Directed {
+			c.out[t] = append(c.out[t], s)
+			c.weight[t] = append(c.weight[t], w)
+		}
+		c.neighbors[s][t] = true
+		c.neighbors[t][s] = true
+	}
+	return c
+}
+
+func (c *centrality) degree(i int) int {
+	return len(c.neighbors[i])
+}
+
+// betweenness returns each node's share of the shortest paths between
+// other nodes (Brandes' algorithm, unweighted), normalized to [0, 1].
+func (c *centrality) betweenness() []float64 {
+	size := len(c.out)
+	bc := make([]float64, size)
+	if size < 3 {
+		return bc
+	}
+	var (
+		sigma = make([]float64, size)
+		dist  = make([]int, size)
+		delta = make([]float64, size)
+		preds = make([][]int, size)
+	)
+	for s := 0; s < size; s++ {
+		for i := range sigma {
+			sigma[i], dist[i], delta[i], preds[i] = 0, -1, 0, preds[i][:0]
+		}
+		sigma[s], dist[s] = 1, 0
+		order := make([]int, 0, size)
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			order = append(order, v)
+			for _, w := range c.out[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+		for i := len(order) - 1; i >= 0; i-- {
+			w := order[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				bc[w] += delta[w]
+			}
+		}
+	}
+	// Undirected paths are counted from both ends, which the (n-1)(n-2)
+	// ordered-pair normalization accounts for.
+	norm := float64((size - 1) * (size - 2))
+	for i := range bc {
+		bc[i] /= norm
+	}
+	return bc
+}
+
+// pageRank returns each node's PageRank, following edge weights. Nodes
+// without outgoing edges spread their rank evenly.
+func (c *centrality) pageRank() []float64 {
+	size := len(c.out)
+	if size == 0 {
+		return nil
+	}
+	total := make([]float64, size)
+	for i, ws := range c.weight {
+		for _, w := range ws {
+			total[i] += w
+		}
+	}
+	rank := make([]float64, size)
+	for i := range rank {
+		rank[i] = 1 / float64(size)
+	}
+	next := make([]float64, size)
+	for iter := 0; iter < maxIterations; iter++ {
+		dangling := 0.0
+		for i := range rank {
+			if total[i] == 0 {
+				dangling += rank[i]
+			}
+		}
+		base := (1-damping)/float64(size) + damping*dangling/float64(size)
+		for i := range next {
+			next[i] = base
+		}
+		for i, targets := range c.out {
+			for k, t := range targets {
+				next[t] += damping * rank[i] * c.weight[i][k] / total[i]
+			}
+		}
+		diff := 0.0
+		for i := range rank {
+			diff += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+		if diff < tolerance {
+			break
+		}
+	}
+	return rank
+}
@@ -0,0 +1,43 @@
+package network
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCentrality_Path(t *testing.T) {
+	// a - b - c: b lies on the only path between a and c.
+	n := &Network{
+		Nodes: []Node{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+		Edges: []Edge{{Source: "a", Target: "b", Weight: 1}, {Source: "b", Target: "c", Weight: 1}},
+	}
+	c := newCentrality(n)
+	bc := c.betweenness()
+	if bc[0] != 0 || bc[1] != 1 || bc[2] != 0 {
+		t.Errorf("betweenness = %v, want [0 1 0]", bc)
+	}
+	pr := c.pageRank()
+	sum := 0.0
+	for _, p := range pr {
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("PageRank sums to %v, want 1", sum)
+	}
+	if !(pr[1] > pr[0] && math.Abs(pr[0]-pr[2]) < 1e-9) {
+		t.Errorf("PageRank = %v, want the middle node highest and the ends equal", pr)
+	}
+}
+
+func TestCentrality_Directed(t *testing.T) {
+	// Both articles cite c; c cites nothing.
+	n := &Network{
+		Directed: true,
+		Nodes:    []Node{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+		Edges:    []Edge{{Source: "a", Target: "c", Weight: 1}, {Source: "b", Target: "c", Weight: 1}},
+	}
+	pr := newCentrality(n).pageRank()
+	if !(pr[2] > pr[0] && pr[2] > pr[1]) {
+		t.Errorf("PageRank = %v, want the cited article highest", pr)
+	}
+}
@@ -0,0 +1,202 @@
+// Package network builds co-authorship and citation networks over a set of
+// articles and ranks their members by centrality.
+package network
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Network types.
+const (
+	TypeCoauthor = "coauthor"
+	TypeCitation = "citation"
+)
+
+// DefaultMaxAuthors bounds the authors taken from one article; consortium
+// papers with hundreds of authors would otherwise swamp the network.
+const DefaultMaxAuthors = 50
+
+// Node is an author (co-author networks) or an article (citation networks).
+type Node struct {
+	// ID is "LastName Initials" for authors and the PMID for articles.
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Year  string `json:"year,omitempty"`
+	// Articles counts an author's articles in the set.
+	Articles int `json:"articles,omitempty"`
+	// Citations counts the citations an article receives from the set.
+	Citations int `json:"citations,omitempty"`
+	// Degree counts distinct neighbors, ignoring edge direction.
+	Degree           int     `json:"degree"`
+	DegreeCentrality float64 `json:"degree_centrality"`
+	Betweenness      float64 `json:"betweenness"`
+	PageRank         float64 `json:"pagerank"`
+}
+
+// Edge links two nodes. Citation edges point from the citing article to the
+// cited one; co-author edges are undirected and weighted by the number of
+// shared articles.
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Weight int    `json:"weight"`
+}
+
+// Network is a graph over a set of articles, with nodes ranked by PageRank.
+type Network struct {
+	Type     string `json:"type"`
+	Query    string `json:"query,omitempty"`
+	Articles int    `json:"articles"`
+	Directed bool   `json:"directed"`
+	Nodes    []Node `json:"nodes"`
+	Edges    []Edge `json:"edges"`
+}
+
+// Coauthor links authors who share an article, taking at most maxAuthors
+// from each article (0 for DefaultMaxAuthors).
+func Coauthor(articles []eutils.Article, maxAuthors int) *Network {
+	if maxAuthors <= 0 {
+		maxAuthors = DefaultMaxAuthors
+	}
+	n := &Network{Type: TypeCoauthor, Articles: len(articles), Nodes: []Node{}, Edges: []Edge{}}
+	index := make(map[string]int)
+	edges := make(map[[2]string]int)
+	for _, a := range articles {
+		var ids []string
+		seen := make(map[string]bool)
+		for _, au := range a.Authors {
+			id, label := authorKey(au)
+			if id == "" || seen[id] {
+				continue
+			}
+			if len(ids) == maxAuthors {
+				break
+			}
+			seen[id] = true
+			ids = append(ids, id)
+			i, ok := index[id]
+			if !ok {
+				i = len(n.Nodes)
+				index[id] = i
+				n.Nodes = append(n.Nodes, Node{ID: id, Label: label})
+			}
+			n.Nodes[i].Articles++
+		}
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				key := [2]string{ids[i], ids[j]}
+				if key[0] > key[1] {
+					key[0], key[1] = key[1], key[0]
+				}
+				if _, ok := edges[key]; !ok {
+					edges[key] = len(n.Edges)
+					n.Edges = append(n.Edges, Edge{Source: key[0], Target: key[1]})
+				}
+				n.Edges[edges[key]].Weight++
+			}
+		}
+	}
+	n.rank()
+	return n
+}
+
+// authorKey identifies an author by last name and initials, which is how
+// PubMed itself matches author names.
+func authorKey(a eutils.Author) (id, label string) {
+	if a.CollectiveName != "" {
+		return a.CollectiveName, a.CollectiveName
+	}
+	if a.LastName == "" {
+		return "", ""
+	}
+	id = a.LastName
+	if a.Initials != "" {
+		id += " " + a.Initials
+	}
+	label = a.DisplayName
+	if label == "" {
+		label = id
+	}
+	return id, label
+}
+
+// ReferenceLinker is the subset of the E-utilities client citation
+// networks need.
+type ReferenceLinker interface {
+	References(ctx context.Context, pmid string) (*eutils.LinkResult, error)
+}
+
+// Citation links the articles that cite one another, looking up each
+// article's references. Citations to articles outside the set are dropped.
+func Citation(ctx context.Context, l ReferenceLinker, articles []eutils.Article) (*Network, error) {
+	n := &Network{Type: TypeCitation, Articles: len(articles), Directed: true, Nodes: []Node{}, Edges: []Edge{}}
+	index := make(map[string]int, len(articles))
+	for _, a := range articles {
+		if _, ok := index[a.PMID]; ok {
+			continue
+		}
+		index[a.PMID] = len(n.Nodes)
+		n.Nodes = append(n.Nodes, Node{ID: a.PMID, Label: a.Title, Year: a.Year})
+	}
+	for _, node := range append([]Node(nil), n.Nodes...) {
+		res, err := l.References(ctx, node.ID)
+		if err != nil {
+			return nil, fmt.Errorf("references lookup for %s: %w", node.ID, err)
+		}
+		if res == nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, link := range res.Links {
+			i, ok := index[link.ID]
+			if !ok || link.ID == node.ID || seen[link.ID] {
+				continue
+			}
+			seen[link.ID] = true
+			n.Edges = append(n.Edges, Edge{Source: node.ID, Target: link.ID, Weight: 1})
+			n.Nodes[i].Citations++
+		}
+	}
+	n.rank()
+	return n, nil
+}
+
+// rank computes every node's centrality and orders the nodes by PageRank,
+// then degree, then ID.
+func (n *Network) rank() {
+	c := newCentrality(n)
+	for i := range n.Nodes {
+		n.Nodes[i].Degree = c.degree(i)
+		if len(n.Nodes) > 1 {
+			n.Nodes[i].DegreeCentrality = float64(n.Nodes[i].Degree) / float64(len(n.Nodes)-1)
+		}
+	}
+	for i, b := range c.betweenness() {
+		n.Nodes[i].Betweenness = b
+	}
+	for i, p := range c.pageRank() {
+		n.Nodes[i].PageRank = p
+	}
+	sort.SliceStable(n.Nodes, func(i, j int) bool {
+		a, b := n.Nodes[i], n.Nodes[j]
+		if a.PageRank != b.PageRank {
+			return a.PageRank > b.PageRank
+		}
+		if a.Degree != b.Degree {
+			return a.Degree > b.Degree
+		}
+		return a.ID < b.ID
+	})
+}
+
+// Top returns the k most central nodes (all of them when k <= 0).
+func (n *Network) Top(k int) []Node {
+	if k <= 0 || k > len(n.Nodes) {
+		k = len(n.Nodes)
+	}
+	return n.Nodes[:k]
+}
@@ -0,0 +1,78 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func author(last, initials string) eutils.Author {
+	return eutils.Author{LastName: last, Initials: initials, DisplayName: initials + " " + last}
+}
+
+func TestCoauthor(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", Authors: []eutils.Author{author("Smith", "J"), author("Lee", "K"), author("Park", "S")}},
+		{PMID: "2", Authors: []eutils.Author{author("Smith", "J"), author("Lee", "K")}},
+		{PMID: "3", Authors: []eutils.Author{author("Smith", "J"), author("Diaz", "M"), {CollectiveName: "FXS Consortium"}}},
+	}
+	n := Coauthor(articles, 0)
+	if n.Directed || n.Articles != 3 {
+		t.Fatalf("unexpected network: %+v", n)
+	}
+	if len(n.Nodes) != 5 {
+		t.Fatalf("expected 5 authors, got %+v", n.Nodes)
+	}
+	if len(n.Edges) != 6 {
+		t.Fatalf("expected 6 co-author links, got %+v", n.Edges)
+	}
+	for _, e := range n.Edges {
+		if e.Source == "Lee K" && e.Target == "Smith J" && e.Weight != 2 {
+			t.Errorf("Lee-Smith weight = %d, want 2", e.Weight)
+		}
+	}
+	top := n.Top(1)[0]
+	if top.ID != "Smith J" || top.Articles != 3 || top.Degree != 4 {
+		t.Errorf("top author = %+v", top)
+	}
+	if top.Betweenness <= 0 {
+		t.Errorf("expected the bridging author to have betweenness, got %v", top.Betweenness)
+	}
+}
+
+func TestCoauthor_MaxAuthors(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", Authors: []eutils.Author{author("A", "A"), author("B", "B"), author("C", "C")}},
+	}
+	n := Coauthor(articles, 2)
+	if len(n.Nodes) != 2 || len(n.Edges) != 1 {
+		t.Errorf("expected the first two authors only, got %+v", n)
+	}
+}
+
+type fakeLinker map[string][]string
+
+func (f fakeLinker) References(_ context.Context, pmid string) (*eutils.LinkResult, error) {
+	res := &eutils.LinkResult{SourceID: pmid}
+	for _, id := range f[pmid] {
+		res.Links = append(res.Links, eutils.LinkItem{ID: id})
+	}
+	return res, nil
+}
+
+func TestCitation(t *testing.T) {
+	articles := []eutils.Article{{PMID: "1", Year: "2020"}, {PMID: "2"}, {PMID: "3"}}
+	refs := fakeLinker{"2": {"1", "99"}, "3": {"1", "2", "2"}}
+	n, err := Citation(context.Background(), refs, articles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !n.Directed || len(n.Edges) != 3 {
+		t.Fatalf("expected 3 in-set citations, got %+v", n.Edges)
+	}
+	top := n.Top(1)[0]
+	if top.ID != "1" || top.Citations != 2 || top.Year != "2020" {
+		t.Errorf("top article = %+v", top)
+	}
+}
@@ -0,0 +1,59 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+var sample = &Network{
+	Type:     TypeCitation,
+	Directed: true,
+	Articles: 2,
+	Nodes: []Node{
+		{ID: "1", Label: "Fragile X & autism", Year: "2020", Citations: 1, Degree: 1, PageRank: 0.6},
+		{ID: "2", Label: "A follow-up", Degree: 1, PageRank: 0.4},
+	},
+	Edges: []Edge{{Source: "2", Target: "1", Weight: 1}},
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, sample); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+		t.Fatalf("GraphML is not well-formed: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		`edgedefault="directed"`,
+		`<data key="label">Fragile X &amp; autism</data>`,
+		`<data key="citations">1</data>`,
+		`<data key="pagerank">0.6</data>`,
+		`<edge id="e0" source="2" target="1">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected GraphML to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sample, FormatForPath("net.JSON")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got Network
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Type != TypeCitation || len(got.Nodes) != 2 || got.Edges[0].Source != "2" {
+		t.Errorf("round trip = %+v", got)
+	}
+	if err := Write(&buf, sample, FormatForPath("net.dot")); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
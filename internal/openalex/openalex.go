@@ -0,0 +1,163 @@
+// Package openalex enriches PubMed records with OpenAlex citation counts,
+// concept tags, and author institutions. PubMed itself has no citation
+// counts.
+package openalex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// DefaultURL is the OpenAlex API.
+const DefaultURL = "https://api.openalex.org"
+
+// EnvMailto sets the contact email sent with OpenAlex requests, which
+// routes them to OpenAlex's faster "polite" pool.
+const EnvMailto = "OPENALEX_MAILTO"
+
+// BatchSize is the most PMIDs one OpenAlex filter request may name.
+const BatchSize = 50
+
+// Concept tags kept per article: the top MaxConcepts scoring at least
+// MinConceptScore.
+const (
+	MaxConcepts     = 5
+	MinConceptScore = 0.3
+)
+
+// Client queries the OpenAlex API. Requests share the NCBI base client's
+// HTTP client, rate limit, and response size cap.
+type Client struct {
+	*ncbi.BaseClient
+	URL string
+	// Mailto identifies the caller to OpenAlex; it is optional.
+	Mailto string
+}
+
+// NewClient creates an OpenAlex client using an existing NCBI base client.
+func NewClient(base *ncbi.BaseClient, mailto string) *Client {
+	return &Client{BaseClient: base, URL: DefaultURL, Mailto: mailto}
+}
+
+// Work is the part of an OpenAlex work record pubmed-cli uses.
+type Work struct {
+	IDs struct {
+		PMID string `json:"pmid"`
+	} `json:"ids"`
+	CitedByCount int `json:"cited_by_count"`
+	Concepts     []struct {
+		DisplayName string  `json:"display_name"`
+		Score       float64 `json:"score"`
+	} `json:"concepts"`
+	Authorships []struct {
+		Institutions []struct {
+			DisplayName string `json:"display_name"`
+		} `json:"institutions"`
+	} `json:"authorships"`
+}
+
+// PMID is the work's PMID, taken from its PubMed URL.
+func (w Work) PMID() string {
+	id := strings.TrimRight(w.IDs.PMID, "/")
+	return id[strings.LastIndex(id, "/")+1:]
+}
+
+// Works looks up pmids in OpenAlex, BatchSize at a time, and returns the
+// works it knows indexed by PMID.
+func (c *Client) Works(ctx context.Context, pmids []string) (map[string]Work, error) {
+	works := make(map[string]Work, len(pmids))
+	for start := 0; start < len(pmids); start += BatchSize {
+		end := min(start+BatchSize, len(pmids))
+		params := url.Values{}
+		params.Set("filter", "pmid:"+strings.Join(pmids[start:end], "|"))
+		params.Set("per-page", fmt.Sprint(BatchSize))
+		params.Set("select", "ids,cited_by_count,concepts,authorships")
+		if c.Mailto != "" {
+			params.Set("mailto", c.Mailto)
+		}
+		body, err := c.GetURL(ctx, c.URL+"/works?"+params.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("OpenAlex lookup failed: %w", err)
+		}
+		if body == nil {
+			continue
+		}
+		var resp struct {
+			Results []Work `json:"results"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("parsing OpenAlex response: %w", err)
+		}
+		for _, w := range resp.Results {
+			works[w.PMID()] = w
+		}
+	}
+	return works, nil
+}
+
+// Enrich sets a's citation count, concept tags, and institutions from w.
+func Enrich(a *eutils.Article, w Work) {
+	a.CitationCount = w.CitedByCount
+	a.Concepts = nil
+	concepts := slices.Clone(w.Concepts)
+	sort.SliceStable(concepts, func(i, j int) bool { return concepts[i].Score > concepts[j].Score })
+	for _, c := range concepts {
+		if len(a.Concepts) == MaxConcepts || c.Score < MinConceptScore {
+			break
+		}
+		a.Concepts = append(a.Concepts, c.DisplayName)
+	}
+	a.Institutions = nil
+	seen := make(map[string]bool)
+	for _, au := range w.Authorships {
+		for _, inst := range au.Institutions {
+			if inst.DisplayName != "" && !seen[inst.DisplayName] {
+				seen[inst.DisplayName] = true
+				a.Institutions = append(a.Institutions, inst.DisplayName)
+			}
+		}
+	}
+}
+
+// Annotate enriches each article OpenAlex knows. Articles it does not know
+// are left unchanged.
+func (c *Client) Annotate(ctx context.Context, articles []eutils.Article) error {
+	pmids := make([]string, 0, len(articles))
+	for _, a := range articles {
+		if a.PMID != "" {
+			pmids = append(pmids, a.PMID)
+		}
+	}
+	works, err := c.Works(ctx, pmids)
+	if err != nil {
+		return err
+	}
+	for i := range articles {
+		if w, ok := works[articles[i].PMID]; ok {
+			Enrich(&articles[i], w)
+		}
+	}
+	return nil
+}
+
+// SortByCitations orders pmids by their OpenAlex citation counts, most
+// cited first. PMIDs OpenAlex does not know sort last; ties keep their
+// original order.
+func SortByCitations(pmids []string, works map[string]Work) {
+	sort.SliceStable(pmids, func(i, j int) bool {
+		wi, oki := works[pmids[i]]
+		wj, okj := works[pmids[j]]
+		if oki != okj {
+			return oki
+		}
+		return wi.CitedByCount > wj.CitedByCount
+	})
+}
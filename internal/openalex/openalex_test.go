@@ -0,0 +1,67 @@
+package openalex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	works, err := os.ReadFile(filepath.Join("..", "..", "testdata", "openalex_works.json"))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/works" || q.Get("filter") != "pmid:38000001|38000002|38000003" || q.Get("mailto") != "me@example.org" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write(works)
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient(ncbi.NewBaseClient(ncbi.WithHTTPClient(srv.Client())), "me@example.org")
+	c.URL = srv.URL
+	return c
+}
+
+func TestAnnotate(t *testing.T) {
+	c := newTestClient(t)
+	articles := []eutils.Article{{PMID: "38000001"}, {PMID: "38000002"}, {PMID: "38000003"}, {Source: "crossref"}}
+	if err := c.Annotate(context.Background(), articles); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a := articles[0]
+	if a.CitationCount != 12 {
+		t.Errorf("expected 12 citations, got %d", a.CitationCount)
+	}
+	if got := strings.Join(a.Concepts, "; "); got != "Fragile X syndrome; Medicine" {
+		t.Errorf("expected concepts by score above the cutoff, got %q", got)
+	}
+	if got := strings.Join(a.Institutions, "; "); got != "Cincinnati Children's Hospital Medical Center; University of Cincinnati" {
+		t.Errorf("expected distinct institutions, got %q", got)
+	}
+	if articles[1].CitationCount != 97 || articles[2].CitationCount != 0 {
+		t.Errorf("unexpected counts: %d, %d", articles[1].CitationCount, articles[2].CitationCount)
+	}
+}
+
+func TestSortByCitations(t *testing.T) {
+	c := newTestClient(t)
+	works, err := c.Works(context.Background(), []string{"38000001", "38000002", "38000003"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pmids := []string{"38000003", "38000001", "38000002"}
+	SortByCitations(pmids, works)
+	if got := strings.Join(pmids, ","); got != "38000002,38000001,38000003" {
+		t.Errorf("expected most cited first and unknown last, got %s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// writeArticlesBibTeX exports article details as BibTeX @article entries,
+// or @incollection and @book entries for Bookshelf records.
+func writeArticlesBibTeX(path string, articles []eutils.Article) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating BibTeX file: %w", err)
+	}
+	defer f.Close()
+
+	if err := writeBibTeX(f, articles); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeBibTeX writes BibTeX entries for articles to out.
+func writeBibTeX(out io.Writer, articles []eutils.Article) error {
+	w := bufio.NewWriter(out)
+	keys := make(map[string]int)
+	for i, a := range articles {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		writeBibTeXEntry(w, a, keys)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing BibTeX output: %w", err)
+	}
+
+	return nil
+}
+
+// writeBibTeXEntry writes one article's entry; keys counts the citation
+// keys used so far, so repeats are disambiguated.
+func writeBibTeXEntry(w *bufio.Writer, a eutils.Article, keys map[string]int) {
+	key := bibtexKey(a)
+	keys[key]++
+	if n := keys[key]; n > 1 {
+		// Disambiguate like citation managers do: smith2024fragile, smith2024fragileb, ...
+		key += string(rune('a' + n - 1))
+	}
+
+	fmt.Fprintf(w, "@%s{%s,\n", bibtexType(a), key)
+	writeBibTeXField(w, "author", bibtexAuthors(a.Authors))
+	writeBibTeXField(w, "title", a.Title)
+	writeBibTeXField(w, "journal", a.Journal)
+	writeBibTeXField(w, "booktitle", a.BookTitle)
+	writeBibTeXField(w, "series", a.CollectionTitle)
+	writeBibTeXField(w, "publisher", a.Publisher)
+	writeBibTeXField(w, "address", a.PublisherPlace)
+	writeBibTeXField(w, "year", a.Year)
+	writeBibTeXField(w, "month", a.Month)
+	writeBibTeXField(w, "volume", a.Volume)
+	writeBibTeXField(w, "number", a.Issue)
+	startPage, endPage := splitPages(a.Pages)
+	if endPage != "" {
+		writeBibTeXField(w, "pages", startPage+"--"+endPage)
+	} else {
+		writeBibTeXField(w, "pages", startPage)
+	}
+	writeBibTeXField(w, "doi", a.DOI)
+	writeBibTeXField(w, "pmid", a.PMID)
+	writeBibTeXField(w, "pmcid", a.PMCID)
+	writeBibTeXField(w, "url", a.OAURL)
+	writeBibTeXField(w, "abstract", a.Abstract)
+	writeBibTeXField(w, "keywords", strings.Join(a.Tags, ", "))
+	writeBibTeXField(w, "annote", strings.Join(a.Notes, "; "))
+	w.WriteString("}\n")
+}
+
+// bibtexType is the BibTeX entry type for a.
+func bibtexType(a eutils.Article) string {
+	switch {
+	case a.BookTitle != "":
+		return "incollection"
+	case a.IsBook():
+		return "book"
+	}
+	return "article"
+}
+
+func writeBibTeXField(w *bufio.Writer, name, value string) {
+	value = sanitizeRISValue(value)
+	if value == "" {
+		return
+	}
+	// DOIs are read verbatim by biblatex and most styles, and author lists
+	// are escaped name by name; escaping either here would corrupt them.
+	if name != "doi" && name != "author" {
+		value = escapeBibTeX(value)
+	}
+	_, _ = w.WriteString("  " + name + " = {" + value + "},\n")
+}
+
+// bibtexSpecial escapes characters that LaTeX would otherwise interpret.
+var bibtexSpecial = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+)
+
+func escapeBibTeX(s string) string {
+	return bibtexSpecial.Replace(s)
+}
+
+// bibtexAuthors joins authors as "Last, First and Last, First"; collective
+// names are braced so BibTeX does not split them into name parts.
+func bibtexAuthors(authors []eutils.Author) string {
+	names := make([]string, 0, len(authors))
+	for _, au := range authors {
+		if au.CollectiveName != "" {
+			names = append(names, "{"+escapeBibTeX(au.CollectiveName)+"}")
+			continue
+		}
+		if name := risAuthor(au); name != "" {
+			names = append(names, escapeBibTeX(name))
+		}
+	}
+	return strings.Join(names, " and ")
+}
+
+// bibtexKey builds a citation key from the first author's surname, the year,
+// and the first significant title word, e.g. "smith2024fragile". Articles
+// without authors fall back to the PMID.
+func bibtexKey(a eutils.Article) string {
+	var surname string
+	if len(a.Authors) > 0 {
+		surname = a.Authors[0].LastName
+		if surname == "" {
+			surname = a.Authors[0].CollectiveName
+		}
+	}
+	surname = keyWord(surname)
+	if surname == "" {
+		return "pmid" + a.PMID
+	}
+
+	var word string
+	for _, w := range strings.Fields(a.Title) {
+		w = keyWord(w)
+		if len(w) > 3 {
+			word = w
+			break
+		}
+	}
+	return surname + a.Year + word
+}
+
+// keyWord transliterates s to ASCII, lowercases it, and keeps only letters,
+// so keys stay valid across BibTeX implementations ("Müller" → "muller").
+func keyWord(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(normalizeText(s, true)) {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
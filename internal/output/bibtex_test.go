@@ -0,0 +1,73 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestWriteArticlesBibTeX(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "articles.bib")
+
+	articles := []eutils.Article{
+		{
+			PMID:  "38000001",
+			Title: "Testing BibTeX Export: 50% & more",
+			Authors: []eutils.Author{
+				{LastName: "Smith", ForeName: "Jane"},
+				{CollectiveName: "PubMed CLI Consortium"},
+			},
+			Journal: "Journal of CLI Testing",
+			Year:    "2026",
+			Volume:  "12",
+			Issue:   "3",
+			Pages:   "101-110",
+			DOI:     "10.1000/example_1",
+			PMCID:   "PMC123",
+		},
+		{
+			PMID:    "38000002",
+			Title:   "Testing again",
+			Authors: []eutils.Author{{LastName: "Smith", ForeName: "John"}},
+			Year:    "2026",
+		},
+	}
+
+	if err := writeArticlesBibTeX(path, articles); err != nil {
+		t.Fatalf("unexpected error writing BibTeX: %v", err)
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read BibTeX output: %v", err)
+	}
+	out := string(body)
+
+	expected := []string{
+		"@article{smith2026testing,",
+		"  author = {Smith, Jane and {PubMed CLI Consortium}},",
+		`  title = {Testing BibTeX Export: 50\% \& more},`,
+		"  journal = {Journal of CLI Testing},",
+		"  number = {3},",
+		"  pages = {101--110},",
+		"  doi = {10.1000/example_1},",
+		"  pmid = {38000001},",
+		"  pmcid = {PMC123},",
+		"@article{smith2026testingb,",
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected BibTeX output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestBibTeXKey_NoAuthors(t *testing.T) {
+	if got := bibtexKey(eutils.Article{PMID: "123", Title: "Anonymous"}); got != "pmid123" {
+		t.Errorf("expected pmid123, got %q", got)
+	}
+}
@@ -0,0 +1,227 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// CitationStyles lists the styles FormatCitations accepts.
+var CitationStyles = []string{"apa", "vancouver", "ama", "bibtex", "ris", "csl-json"}
+
+// FormatCitations writes one formatted citation per article. Text styles
+// (APA 7, Vancouver/NLM, AMA 11) print one citation per line; bibtex, ris,
+// and csl-json print the same records as the corresponding exports.
+func FormatCitations(w io.Writer, articles []eutils.Article, style string) error {
+	var cite func(eutils.Article) string
+	switch strings.ToLower(style) {
+	case "apa":
+		cite = citeAPA
+	case "vancouver":
+		cite = citeVancouver
+	case "ama":
+		cite = citeAMA
+	case "bibtex":
+		return writeBibTeX(w, articles)
+	case "ris":
+		return writeRIS(w, articles)
+	case "csl-json":
+		return writeCSLJSON(w, articles)
+	default:
+		return fmt.Errorf("unknown citation style %q (use %s)", style, strings.Join(CitationStyles, ", "))
+	}
+
+	for _, a := range articles {
+		if _, err := fmt.Fprintln(w, cite(a)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// citeAPA formats an APA 7th edition journal reference:
+// Smith, J. A., & Doe, R. (2024). Title. Journal, 12(3), 45-67. https://doi.org/...
+func citeAPA(a eutils.Article) string {
+	var names []string
+	for _, au := range a.Authors {
+		if au.CollectiveName != "" {
+			names = append(names, au.CollectiveName)
+			continue
+		}
+		name := au.LastName
+		if initials := apaInitials(au); initials != "" {
+			name += ", " + initials
+		}
+		names = append(names, name)
+	}
+
+	var b strings.Builder
+	switch n := len(names); {
+	case n == 0:
+	case n == 1:
+		b.WriteString(names[0])
+	case n <= 20:
+		b.WriteString(strings.Join(names[:n-1], ", ") + ", & " + names[n-1])
+	default:
+		// APA lists the first 19 authors, an ellipsis, then the last author.
+		b.WriteString(strings.Join(names[:19], ", ") + ", . . . " + names[n-1])
+	}
+	if b.Len() > 0 && !strings.HasSuffix(b.String(), ".") {
+		b.WriteString(".")
+	}
+
+	year := a.Year
+	if year == "" {
+		year = "n.d."
+	}
+	fmt.Fprintf(&b, " (%s). %s", year, sentence(a.Title))
+	if a.IsBook() {
+		if a.BookTitle != "" {
+			b.WriteString(" In " + sentence(a.BookTitle))
+		}
+		if a.Publisher != "" {
+			b.WriteString(" " + sentence(a.Publisher))
+		}
+	} else if a.Journal != "" {
+		b.WriteString(" " + a.Journal)
+		if a.Volume != "" {
+			b.WriteString(", " + a.Volume)
+			if a.Issue != "" {
+				b.WriteString("(" + a.Issue + ")")
+			}
+		}
+		if a.Pages != "" {
+			b.WriteString(", " + a.Pages)
+		}
+		b.WriteString(".")
+	}
+	if a.DOI != "" {
+		b.WriteString(" https://doi.org/" + a.DOI)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// apaInitials renders "JA" as "J. A.", falling back to the forename.
+func apaInitials(au eutils.Author) string {
+	initials := au.Initials
+	if initials == "" && au.ForeName != "" {
+		for _, part := range strings.Fields(au.ForeName) {
+			initials += string([]rune(part)[0])
+		}
+	}
+	var parts []string
+	for _, r := range initials {
+		parts = append(parts, string(r)+".")
+	}
+	return strings.Join(parts, " ")
+}
+
+// citeVancouver formats an NLM/ICMJE (Vancouver) reference, listing six
+// authors before "et al.":
+// Smith JA, Doe R. Title. J Abbrev. 2024;12(3):45-67. doi:... PMID: 123.
+func citeVancouver(a eutils.Article) string {
+	s := citeNLM(a, 6, 6)
+	if a.DOI != "" {
+		s += " doi:" + a.DOI + "."
+	}
+	if a.PMID != "" {
+		s += " PMID: " + a.PMID + "."
+	}
+	return s
+}
+
+// citeAMA formats an AMA 11th edition reference: all authors up to six,
+// otherwise the first three and "et al".
+func citeAMA(a eutils.Article) string {
+	s := citeNLM(a, 6, 3)
+	if a.DOI != "" {
+		s += " doi:" + a.DOI
+	}
+	return s
+}
+
+// citeNLM builds the author, title, and source parts shared by Vancouver
+// and AMA. Up to max authors are listed; longer lists are cut to keep.
+func citeNLM(a eutils.Article, max, keep int) string {
+	var names []string
+	for _, au := range a.Authors {
+		if au.CollectiveName != "" {
+			names = append(names, au.CollectiveName)
+		} else {
+			names = append(names, strings.TrimSpace(au.LastName+" "+au.Initials))
+		}
+	}
+	etAl := len(names) > max
+	if etAl {
+		names = names[:keep]
+	}
+
+	var b strings.Builder
+	if len(names) > 0 {
+		b.WriteString(strings.Join(names, ", "))
+		if etAl {
+			b.WriteString(", et al")
+		}
+		b.WriteString(". ")
+	}
+	b.WriteString(sentence(a.Title))
+	if a.IsBook() {
+		b.WriteString(nlmBookSource(a))
+		return strings.TrimSpace(b.String())
+	}
+
+	journal := a.JournalAbbrev
+	if journal == "" {
+		journal = a.Journal
+	}
+	if journal != "" {
+		b.WriteString(" " + strings.TrimSuffix(journal, ".") + ".")
+	}
+	if a.Year != "" {
+		b.WriteString(" " + a.Year)
+		if a.Volume != "" {
+			b.WriteString(";" + a.Volume)
+			if a.Issue != "" {
+				b.WriteString("(" + a.Issue + ")")
+			}
+		}
+		if a.Pages != "" {
+			b.WriteString(":" + a.Pages)
+		}
+		b.WriteString(".")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// nlmBookSource is the NLM source of a Bookshelf record:
+// " In: Book. Place: Publisher; 2024." for a chapter.
+func nlmBookSource(a eutils.Article) string {
+	var b strings.Builder
+	if a.BookTitle != "" {
+		b.WriteString(" In: " + sentence(a.BookTitle))
+	}
+	publisher := a.Publisher
+	if a.PublisherPlace != "" && publisher != "" {
+		publisher = a.PublisherPlace + ": " + publisher
+	}
+	switch {
+	case publisher != "" && a.Year != "":
+		b.WriteString(" " + publisher + "; " + a.Year + ".")
+	case publisher != "":
+		b.WriteString(" " + sentence(publisher))
+	case a.Year != "":
+		b.WriteString(" " + a.Year + ".")
+	}
+	return b.String()
+}
+
+// sentence returns s with terminal punctuation, as citation titles need.
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func citeArticle() eutils.Article {
+	return eutils.Article{
+		PMID:          "38000001",
+		Title:         "Metformin in fragile X syndrome",
+		Journal:       "Journal of Neurodevelopmental Disorders",
+		JournalAbbrev: "J Neurodev Disord",
+		Year:          "2024",
+		Volume:        "16",
+		Issue:         "1",
+		Pages:         "12-19",
+		DOI:           "10.1186/s11689-024-00001-1",
+		Authors: []eutils.Author{
+			{LastName: "Smith", ForeName: "Jane A", Initials: "JA"},
+			{LastName: "Doe", ForeName: "Robert", Initials: "R"},
+		},
+	}
+}
+
+func TestFormatCitations_TextStyles(t *testing.T) {
+	tests := []struct {
+		style, want string
+	}{
+		{"apa", "Smith, J. A., & Doe, R. (2024). Metformin in fragile X syndrome. Journal of Neurodevelopmental Disorders, 16(1), 12-19. https://doi.org/10.1186/s11689-024-00001-1\n"},
+		{"vancouver", "Smith JA, Doe R. Metformin in fragile X syndrome. J Neurodev Disord. 2024;16(1):12-19. doi:10.1186/s11689-024-00001-1. PMID: 38000001.\n"},
+		{"AMA", "Smith JA, Doe R. Metformin in fragile X syndrome. J Neurodev Disord. 2024;16(1):12-19. doi:10.1186/s11689-024-00001-1\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := FormatCitations(&buf, []eutils.Article{citeArticle()}, tt.style); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.style, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.style, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestFormatCitations_AuthorLists(t *testing.T) {
+	a := citeArticle()
+	a.Authors = nil
+	for i := 0; i < 7; i++ {
+		a.Authors = append(a.Authors, eutils.Author{LastName: fmt.Sprintf("Author%d", i), Initials: "A"})
+	}
+
+	if got := citeVancouver(a); !strings.HasPrefix(got, "Author0 A, Author1 A, Author2 A, Author3 A, Author4 A, Author5 A, et al. ") {
+		t.Errorf("Vancouver should list six authors then et al, got %q", got)
+	}
+	if got := citeAMA(a); !strings.HasPrefix(got, "Author0 A, Author1 A, Author2 A, et al. ") {
+		t.Errorf("AMA should list three authors then et al, got %q", got)
+	}
+
+	a.Authors = []eutils.Author{{CollectiveName: "FXS Consortium"}}
+	a.Year = ""
+	if got := citeAPA(a); !strings.HasPrefix(got, "FXS Consortium. (n.d.). ") {
+		t.Errorf("unexpected APA for group author without year: %q", got)
+	}
+}
+
+func TestFormatCitations_BibTeXAndRIS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := FormatCitations(&buf, []eutils.Article{citeArticle()}, "bibtex"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "@article{smith2024metformin,") {
+		t.Errorf("unexpected BibTeX: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := FormatCitations(&buf, []eutils.Article{citeArticle()}, "ris"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "TY  - JOUR\n") {
+		t.Errorf("unexpected RIS: %q", buf.String())
+	}
+
+	if err := FormatCitations(&buf, nil, "chicago"); err == nil {
+		t.Error("expected unknown style to be rejected")
+	}
+}
+
+func TestFormatCitations_BookRecords(t *testing.T) {
+	chapter := eutils.Article{
+		PMID: "20301558", Title: "FMR1 Disorders", BookTitle: "GeneReviews®", Year: "1993",
+		Publisher: "University of Washington, Seattle", PublisherPlace: "Seattle (WA)",
+		Authors: []eutils.Author{{LastName: "Hunter", ForeName: "Jessica Ezzell", Initials: "JE"}},
+	}
+	book := eutils.Article{
+		PMID: "21249951", Title: "Preventing Medication Errors", Year: "2007", DOI: "10.17226/11623",
+		Publisher: "National Academies Press (US)", PublisherPlace: "Washington (DC)",
+		Authors: []eutils.Author{{CollectiveName: "Committee on Identifying and Preventing Medication Errors"}},
+	}
+	tests := []struct {
+		style string
+		a     eutils.Article
+		want  string
+	}{
+		{"apa", chapter, "Hunter, J. E. (1993). FMR1 Disorders. In GeneReviews®. University of Washington, Seattle.\n"},
+		{"vancouver", chapter, "Hunter JE. FMR1 Disorders. In: GeneReviews®. Seattle (WA): University of Washington, Seattle; 1993. PMID: 20301558.\n"},
+		{"ama", book, "Committee on Identifying and Preventing Medication Errors. Preventing Medication Errors. Washington (DC): National Academies Press (US); 2007. doi:10.17226/11623\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := FormatCitations(&buf, []eutils.Article{tt.a}, tt.style); err != nil {
+			t.Fatalf("%s: %v", tt.style, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.style, buf.String(), tt.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := FormatCitations(&buf, []eutils.Article{chapter, book}, "ris"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"TY  - CHAP", "T2  - GeneReviews®", "PB  - University of Washington, Seattle", "CY  - Seattle (WA)", "TY  - BOOK"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("RIS missing %q:\n%s", want, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := FormatCitations(&buf, []eutils.Article{chapter, book}, "bibtex"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"@incollection{hunter1993disorders,", "booktitle = {GeneReviews®}", "@book{", "publisher = {National Academies Press (US)}"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("BibTeX missing %q:\n%s", want, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := FormatCitations(&buf, []eutils.Article{chapter, book}, "csl-json"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"type": "chapter"`, `"container-title": "GeneReviews®"`, `"publisher-place": "Seattle (WA)"`, `"type": "book"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("CSL-JSON missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// articleColumn is one selectable field of an article table export.
+type articleColumn struct {
+	Name   string
+	Header string
+	Value  func(a eutils.Article) string
+}
+
+// articleColumns lists the fields --columns can select, in the order shown
+// by ArticleColumnNames.
+var articleColumns = []articleColumn{
+	{"pmid", "PMID", func(a eutils.Article) string { return a.PMID }},
+	{"title", "Title", func(a eutils.Article) string { return a.Title }},
+	{"authors", "Authors", articleAuthors},
+	{"first_author", "FirstAuthor", func(a eutils.Article) string {
+		if len(a.Authors) == 0 {
+			return ""
+		}
+		return a.Authors[0].FullName()
+	}},
+	{"journal", "Journal", func(a eutils.Article) string { return a.Journal }},
+	{"journal_abbrev", "JournalAbbrev", func(a eutils.Article) string { return a.JournalAbbrev }},
+	{"year", "Year", func(a eutils.Article) string { return a.Year }},
+	{"month", "Month", func(a eutils.Article) string { return a.Month }},
+	{"pub_date", "PubDate", func(a eutils.Article) string { return a.PubDate }},
+	{"epub_date", "EPubDate", func(a eutils.Article) string { return a.EPubDate }},
+	{"volume", "Volume", func(a eutils.Article) string { return a.Volume }},
+	{"issue", "Issue", func(a eutils.Article) string { return a.Issue }},
+	{"pages", "Pages", func(a eutils.Article) string { return a.Pages }},
+	{"doi", "DOI", func(a eutils.Article) string { return a.DOI }},
+	{"pmcid", "PMCID", func(a eutils.Article) string { return a.PMCID }},
+	{"book_title", "BookTitle", func(a eutils.Article) string { return a.BookTitle }},
+	{"publisher", "Publisher", func(a eutils.Article) string { return a.Publisher }},
+	{"license", "License", func(a eutils.Article) string { return a.License }},
+	{"reference_count", "ReferenceCount", func(a eutils.Article) string {
+		if a.ReferenceCount == 0 {
+			return ""
+		}
+		return strconv.Itoa(a.ReferenceCount)
+	}},
+	{"source", "Source", func(a eutils.Article) string { return a.Source }},
+	{"citation_count", "CitationCount", func(a eutils.Article) string {
+		if a.CitationCount == 0 {
+			return ""
+		}
+		return strconv.Itoa(a.CitationCount)
+	}},
+	{"concepts", "Concepts", func(a eutils.Article) string { return strings.Join(a.Concepts, "; ") }},
+	{"institutions", "Institutions", func(a eutils.Article) string { return strings.Join(a.Institutions, "; ") }},
+	{"grants", "Grants", func(a eutils.Article) string { return grantList(a.Grants) }},
+	{"influential_citation_count", "InfluentialCitationCount", func(a eutils.Article) string {
+		if a.InfluentialCitationCount == 0 {
+			return ""
+		}
+		return strconv.Itoa(a.InfluentialCitationCount)
+	}},
+	{"tldr", "TLDR", func(a eutils.Article) string { return a.TLDR }},
+	{"rcr", "RCR", func(a eutils.Article) string { return metric(a.RCR) }},
+	{"nih_percentile", "NIHPercentile", func(a eutils.Article) string { return metric(a.NIHPercentile) }},
+	{"apt", "APT", func(a eutils.Article) string { return metric(a.APT) }},
+	{"oa_status", "OAStatus", func(a eutils.Article) string { return a.OAStatus }},
+	{"oa_url", "OAURL", func(a eutils.Article) string { return a.OAURL }},
+	{"language", "Language", func(a eutils.Article) string { return a.Language }},
+	{"type", "Type", func(a eutils.Article) string { return strings.Join(a.PublicationTypes, "; ") }},
+	{"abstract", "Abstract", func(a eutils.Article) string { return a.Abstract }},
+	{"mesh", "MeSH", articleMeSH},
+	{"tags", "Tags", func(a eutils.Article) string { return strings.Join(a.Tags, "; ") }},
+	{"notes", "Notes", func(a eutils.Article) string { return strings.Join(a.Notes, " | ") }},
+}
+
+// metric formats an optional iCite score, empty when absent.
+func metric(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// grantList formats grants as "agency ID", semicolon-separated.
+func grantList(grants []eutils.Grant) string {
+	parts := make([]string, len(grants))
+	for i, g := range grants {
+		parts[i] = strings.TrimSpace(g.Agency + " " + g.ID)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ArticleColumnNames returns the names accepted by ParseColumns.
+func ArticleColumnNames() []string {
+	names := make([]string, len(articleColumns))
+	for i, c := range articleColumns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// ParseColumns validates a comma-separated --columns list such as
+// "pmid,year,title,doi,mesh" and returns the names in the given order.
+func ParseColumns(spec string) ([]string, error) {
+	var names []string
+	for _, raw := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(raw))
+		if name == "" {
+			continue
+		}
+		if _, ok := findArticleColumn(name); !ok {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(ArticleColumnNames(), ", "))
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one column is required")
+	}
+	return names, nil
+}
+
+func findArticleColumn(name string) (articleColumn, bool) {
+	for _, c := range articleColumns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return articleColumn{}, false
+}
+
+// selectArticleColumns resolves names (already validated by ParseColumns).
+func selectArticleColumns(names []string) []articleColumn {
+	cols := make([]articleColumn, 0, len(names))
+	for _, name := range names {
+		if c, ok := findArticleColumn(name); ok {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// writeArticleColumnRows writes the selected columns for each article.
+func writeArticleColumnRows(w tableWriter, articles []eutils.Article, cols []articleColumn) {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Header
+	}
+	w.Write(header)
+	for _, a := range articles {
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			row[i] = c.Value(a)
+		}
+		w.Write(row)
+	}
+}
+
+// articleAuthors joins full author names with semicolons.
+func articleAuthors(a eutils.Article) string {
+	names := make([]string, len(a.Authors))
+	for i, au := range a.Authors {
+		names[i] = au.FullName()
+	}
+	return strings.Join(names, "; ")
+}
+
+// articleMeSH joins descriptors with semicolons, major topics prefixed with *.
+func articleMeSH(a eutils.Article) string {
+	terms := make([]string, len(a.MeSHTerms))
+	for i, m := range a.MeSHTerms {
+		if m.MajorTopic {
+			terms[i] = "*" + m.Descriptor
+		} else {
+			terms[i] = m.Descriptor
+		}
+	}
+	return strings.Join(terms, "; ")
+}
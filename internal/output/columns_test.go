@@ -0,0 +1,89 @@
+package output
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestParseColumns(t *testing.T) {
+	got, err := ParseColumns(" PMID, year,title,,doi ,mesh")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"pmid", "year", "title", "doi", "mesh"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("column %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	if _, err := ParseColumns("pmid,impact_factor"); err == nil {
+		t.Error("expected unknown column to be rejected")
+	}
+	if _, err := ParseColumns(" , "); err == nil {
+		t.Error("expected empty column list to be rejected")
+	}
+}
+
+func TestWriteArticlesRows_Columns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "articles.csv")
+	articles := []eutils.Article{
+		{
+			PMID:             "12345",
+			Year:             "2024",
+			PMCID:            "PMC999",
+			Language:         "eng",
+			PublicationTypes: []string{"Journal Article", "Review"},
+			MeSHTerms: []eutils.MeSHTerm{
+				{Descriptor: "Fragile X Syndrome", MajorTopic: true},
+				{Descriptor: "Humans"},
+			},
+		},
+	}
+	columns := []string{"year", "pmid", "pmcid", "language", "type", "mesh"}
+
+	cfg := OutputConfig{CSVFile: path, Columns: columns}
+	if err := exportTables(cfg, func(w tableWriter) { writeArticlesRows(w, articles, cfg.Columns) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows := readCSV(t, path)
+	wantHeader := []string{"Year", "PMID", "PMCID", "Language", "Type", "MeSH"}
+	wantRow := []string{"2024", "12345", "PMC999", "eng", "Journal Article; Review", "*Fragile X Syndrome; Humans"}
+	if len(rows) != 2 {
+		t.Fatalf("expected header + 1 row, got %d", len(rows))
+	}
+	for i := range wantHeader {
+		if rows[0][i] != wantHeader[i] {
+			t.Errorf("header[%d]: expected %q, got %q", i, wantHeader[i], rows[0][i])
+		}
+		if rows[1][i] != wantRow[i] {
+			t.Errorf("row[%d]: expected %q, got %q", i, wantRow[i], rows[1][i])
+		}
+	}
+}
+
+func TestWriteArticlesRows_ICiteColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "articles.csv")
+	rcr, apt := 1.96, 0.05
+	articles := []eutils.Article{{PMID: "12345", RCR: &rcr, APT: &apt}}
+	columns := []string{"pmid", "rcr", "nih_percentile", "apt"}
+
+	cfg := OutputConfig{CSVFile: path, Columns: columns}
+	if err := exportTables(cfg, func(w tableWriter) { writeArticlesRows(w, articles, cfg.Columns) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows := readCSV(t, path)
+	want := []string{"12345", "1.96", "", "0.05"}
+	for i := range want {
+		if rows[1][i] != want[i] {
+			t.Errorf("row[%d]: expected %q, got %q", i, want[i], rows[1][i])
+		}
+	}
+}
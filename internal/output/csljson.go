@@ -0,0 +1,132 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// cslItem is one CSL-JSON item, the format Zotero, Pandoc, and citeproc
+// processors read.
+type cslItem struct {
+	ID                  string    `json:"id"`
+	Type                string    `json:"type"`
+	Title               string    `json:"title,omitempty"`
+	ContainerTitle      string    `json:"container-title,omitempty"`
+	ContainerTitleShort string    `json:"container-title-short,omitempty"`
+	CollectionTitle     string    `json:"collection-title,omitempty"`
+	Publisher           string    `json:"publisher,omitempty"`
+	PublisherPlace      string    `json:"publisher-place,omitempty"`
+	Author              []cslName `json:"author,omitempty"`
+	Issued              *cslDate  `json:"issued,omitempty"`
+	Volume              string    `json:"volume,omitempty"`
+	Issue               string    `json:"issue,omitempty"`
+	Page                string    `json:"page,omitempty"`
+	DOI                 string    `json:"DOI,omitempty"`
+	PMID                string    `json:"PMID,omitempty"`
+	PMCID               string    `json:"PMCID,omitempty"`
+	Language            string    `json:"language,omitempty"`
+	Abstract            string    `json:"abstract,omitempty"`
+	URL                 string    `json:"URL,omitempty"`
+	Keyword             string    `json:"keyword,omitempty"`
+	Note                string    `json:"note,omitempty"`
+}
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// writeArticlesCSLJSON exports article details as a CSL-JSON array.
+func writeArticlesCSLJSON(path string, articles []eutils.Article) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating CSL-JSON file: %w", err)
+	}
+	defer f.Close()
+
+	if err := writeCSLJSON(f, articles); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeCSLJSON writes articles to out as a CSL-JSON array.
+func writeCSLJSON(out io.Writer, articles []eutils.Article) error {
+	items := make([]cslItem, len(articles))
+	for i, a := range articles {
+		items[i] = cslFromArticle(a)
+	}
+	return writeJSON(out, items)
+}
+
+func cslFromArticle(a eutils.Article) cslItem {
+	item := cslItem{
+		ID:                  "pmid:" + a.PMID,
+		Type:                "article-journal",
+		Title:               a.Title,
+		ContainerTitle:      a.Journal,
+		ContainerTitleShort: a.JournalAbbrev,
+		Volume:              a.Volume,
+		Issue:               a.Issue,
+		Page:                a.Pages,
+		DOI:                 a.DOI,
+		PMID:                a.PMID,
+		PMCID:               a.PMCID,
+		Language:            a.Language,
+		Abstract:            a.Abstract,
+		Keyword:             strings.Join(a.Tags, ", "),
+		Note:                strings.Join(a.Notes, "\n\n"),
+	}
+	if a.IsBook() {
+		item.Type = "book"
+		if a.BookTitle != "" {
+			item.Type = "chapter"
+			item.ContainerTitle = a.BookTitle
+		}
+		item.CollectionTitle = a.CollectionTitle
+		item.Publisher = a.Publisher
+		item.PublisherPlace = a.PublisherPlace
+	}
+	if a.PMID != "" {
+		item.URL = "https://pubmed.ncbi.nlm.nih.gov/" + a.PMID + "/"
+	}
+	for _, au := range a.Authors {
+		if au.CollectiveName != "" {
+			item.Author = append(item.Author, cslName{Literal: au.CollectiveName})
+		} else {
+			item.Author = append(item.Author, cslName{Family: au.LastName, Given: au.ForeName})
+		}
+	}
+	if year, err := strconv.Atoi(a.Year); err == nil {
+		parts := []int{year}
+		if month := cslMonth(a.Month); month > 0 {
+			parts = append(parts, month)
+		}
+		item.Issued = &cslDate{DateParts: [][]int{parts}}
+	}
+	return item
+}
+
+// cslMonth parses PubMed's month ("Mar" or "03"), returning 0 when unknown.
+func cslMonth(m string) int {
+	if n, err := strconv.Atoi(m); err == nil && n >= 1 && n <= 12 {
+		return n
+	}
+	if len(m) >= 3 {
+		if t, err := time.Parse("Jan", strings.ToUpper(m[:1])+strings.ToLower(m[1:3])); err == nil {
+			return int(t.Month())
+		}
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestWriteCSLJSON(t *testing.T) {
+	articles := []eutils.Article{
+		citeArticle(),
+		{PMID: "2", Title: "Consensus", Year: "2020", Month: "Mar", Authors: []eutils.Author{{CollectiveName: "FXS Consortium"}}},
+	}
+	articles[0].Month = "07"
+
+	var buf bytes.Buffer
+	if err := writeCSLJSON(&buf, articles); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	first := items[0]
+	for field, want := range map[string]string{
+		"id":                    "pmid:38000001",
+		"type":                  "article-journal",
+		"container-title-short": "J Neurodev Disord",
+		"page":                  "12-19",
+		"DOI":                   "10.1186/s11689-024-00001-1",
+		"PMID":                  "38000001",
+	} {
+		if first[field] != want {
+			t.Errorf("%s: expected %q, got %v", field, want, first[field])
+		}
+	}
+	author := first["author"].([]interface{})[0].(map[string]interface{})
+	if author["family"] != "Smith" || author["given"] != "Jane A" {
+		t.Errorf("unexpected author: %v", author)
+	}
+	issued, _ := json.Marshal(first["issued"])
+	if string(issued) != `{"date-parts":[[2024,7]]}` {
+		t.Errorf("unexpected issued date: %s", issued)
+	}
+
+	second := items[1]
+	literal := second["author"].([]interface{})[0].(map[string]interface{})["literal"]
+	if literal != "FXS Consortium" {
+		t.Errorf("expected collective author as literal, got %v", literal)
+	}
+	issued, _ = json.Marshal(second["issued"])
+	if string(issued) != `{"date-parts":[[2020,3]]}` {
+		t.Errorf("unexpected issued date: %s", issued)
+	}
+}
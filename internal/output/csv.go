@@ -1,29 +1,47 @@
 package output
 
 import (
+	"bufio"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/batch"
+	"github.com/henrybloomingdale/pubmed-cli/internal/compare"
+	"github.com/henrybloomingdale/pubmed-cli/internal/dedupe"
 	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/journal"
 	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+	"github.com/henrybloomingdale/pubmed-cli/internal/network"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/henrybloomingdale/pubmed-cli/internal/retraction"
+	"github.com/henrybloomingdale/pubmed-cli/internal/screen"
+	"github.com/henrybloomingdale/pubmed-cli/internal/stance"
+	"github.com/henrybloomingdale/pubmed-cli/internal/triage"
+	"github.com/henrybloomingdale/pubmed-cli/internal/zotero"
 )
 
-// writeSearchCSV exports search results to CSV.
-// If articles are provided, writes: PMID,Title,Year,Journal,DOI,Type.
-// Otherwise writes: Rank,PMID.
-func writeSearchCSV(path string, result *eutils.SearchResult, articles []eutils.Article) error {
-	w, f, err := createCSV(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
+// Default columns for article tables when --columns is not given.
+var (
+	defaultSearchColumns   = []string{"pmid", "title", "year", "journal", "doi", "type"}
+	defaultArticlesColumns = []string{"pmid", "title", "authors", "journal", "year", "doi", "abstract", "mesh"}
+)
 
+// writeSearchRows writes search results as table rows.
+// If articles are provided, writes the selected columns (default:
+// PMID,Title,Year,Journal,DOI,Type) in result order. Otherwise writes: Rank,PMID.
+func writeSearchRows(w tableWriter, result *eutils.SearchResult, articles []eutils.Article, columns []string) {
 	if len(articles) > 0 {
-		// Rich CSV with article details
-		w.Write([]string{"PMID", "Title", "Year", "Journal", "DOI", "Type"})
+		if len(columns) == 0 {
+			columns = defaultSearchColumns
+		}
 
 		// Index articles by PMID for lookup
 		byPMID := make(map[string]eutils.Article, len(articles))
@@ -31,21 +49,15 @@ func writeSearchCSV(path string, result *eutils.SearchResult, articles []eutils.
 			byPMID[a.PMID] = a
 		}
 
-		for _, id := range result.IDs {
+		ordered := make([]eutils.Article, len(result.IDs))
+		for i, id := range result.IDs {
 			a, ok := byPMID[id]
 			if !ok {
-				w.Write([]string{id, "", "", "", "", ""})
-				continue
+				a = eutils.Article{PMID: id}
 			}
-			w.Write([]string{
-				a.PMID,
-				a.Title,
-				a.Year,
-				a.Journal,
-				a.DOI,
-				strings.Join(a.PublicationTypes, "; "),
-			})
+			ordered[i] = a
 		}
+		writeArticleColumnRows(w, ordered, selectArticleColumns(columns))
 	} else {
 		// Simple PMID list
 		w.Write([]string{"Rank", "PMID"})
@@ -53,105 +65,458 @@ func writeSearchCSV(path string, result *eutils.SearchResult, articles []eutils.
 			w.Write([]string{strconv.Itoa(i + 1), id})
 		}
 	}
+}
 
-	w.Flush()
-	return w.Error()
+// writeArticlesRows writes article details as table rows.
+// Default columns: PMID,Title,Authors,Journal,Year,DOI,Abstract,MeSH
+func writeArticlesRows(w tableWriter, articles []eutils.Article, columns []string) {
+	if len(columns) == 0 {
+		columns = defaultArticlesColumns
+	}
+	writeArticleColumnRows(w, articles, selectArticleColumns(columns))
 }
 
-// writeArticlesCSV exports article details to CSV.
-// Columns: PMID,Title,Authors,Journal,Year,DOI,Abstract,MeSH
-func writeArticlesCSV(path string, articles []eutils.Article) error {
-	w, f, err := createCSV(path)
-	if err != nil {
-		return err
+// writeLinksRows writes link results as table rows.
+// Columns: PMID,Score
+func writeLinksRows(w tableWriter, result *eutils.LinkResult) {
+	w.Write([]string{"PMID", "Score"})
+
+	for _, link := range result.Links {
+		score := ""
+		if link.Score > 0 {
+			score = strconv.Itoa(link.Score)
+		}
+		w.Write([]string{link.ID, score})
 	}
-	defer f.Close()
+}
+
+// writeMeSHRows writes a MeSH record as table rows.
+// Columns: UI,Name,ScopeNote,TreeNumbers,EntryTerms,Annotation,RecordType,HeadingMappedTo,PharmacologicalActions
+func writeMeSHRows(w tableWriter, record *mesh.MeSHRecord) {
+	w.Write([]string{"UI", "Name", "ScopeNote", "TreeNumbers", "EntryTerms", "Annotation", "RecordType", "HeadingMappedTo", "PharmacologicalActions"})
+	w.Write([]string{
+		record.UI,
+		record.Name,
+		record.ScopeNote,
+		strings.Join(record.TreeNumbers, "; "),
+		strings.Join(record.EntryTerms, "; "),
+		record.Annotation,
+		record.RecordType,
+		strings.Join(record.HeadingMappedTo, "; "),
+		strings.Join(record.PharmacologicalActions, "; "),
+	})
+}
+
+// writeMeSHTreeRows writes a descriptor's tree positions as table rows.
+// Columns: Path,Relation,TreeNumber,UI,Name,ChildCount
+func writeMeSHTreeRows(w tableWriter, h *mesh.Hierarchy) {
+	w.Write([]string{"Path", "Relation", "TreeNumber", "UI", "Name", "ChildCount"})
+	for _, p := range h.Paths {
+		for _, a := range p.Ancestors {
+			w.Write([]string{p.TreeNumber, "ancestor", a.TreeNumber, a.UI, a.Name, ""})
+		}
+		w.Write([]string{p.TreeNumber, "self", p.TreeNumber, h.Record.UI, h.Record.Name, strconv.Itoa(len(p.Children))})
+		var walk func(nodes []mesh.TreeNode, relation string)
+		walk = func(nodes []mesh.TreeNode, relation string) {
+			for _, c := range nodes {
+				w.Write([]string{p.TreeNumber, relation, c.TreeNumber, c.UI, c.Name, strconv.Itoa(c.ChildCount)})
+				walk(c.Children, "descendant")
+			}
+		}
+		walk(p.Children, "child")
+	}
+}
+
+// writeMeSHSuggestionsRows writes ranked MeSH suggestions as table rows.
+// Columns: UI,Name,Matched,Rank
+func writeMeSHSuggestionsRows(w tableWriter, suggestions []mesh.Suggestion) {
+	w.Write([]string{"UI", "Name", "Matched", "Rank"})
+	for _, s := range suggestions {
+		w.Write([]string{s.UI, s.Name, s.Matched, strconv.Itoa(s.Rank)})
+	}
+}
+
+// writeMeSHQualifiersRows writes MeSH qualifiers as table rows.
+// Columns: Abbreviation,Name
+func writeMeSHQualifiersRows(w tableWriter, qualifiers []mesh.Qualifier) {
+	w.Write([]string{"Abbreviation", "Name"})
+	for _, q := range qualifiers {
+		w.Write([]string{q.Abbreviation, q.Name})
+	}
+}
 
-	w.Write([]string{"PMID", "Title", "Authors", "Journal", "Year", "DOI", "Abstract", "MeSH"})
+// writeMeSHRecordsRows writes a list of MeSH records as table rows.
+// Columns: UI,Name,RecordType
+func writeMeSHRecordsRows(w tableWriter, records []mesh.MeSHRecord) {
+	w.Write([]string{"UI", "Name", "RecordType"})
+	for _, r := range records {
+		w.Write([]string{r.UI, r.Name, r.RecordType})
+	}
+}
 
-	for _, a := range articles {
-		// Authors: semicolon-separated full names
-		names := make([]string, len(a.Authors))
-		for i, au := range a.Authors {
-			names[i] = au.FullName()
+// writeConceptMappingsRows writes concept-to-MeSH mappings as table rows.
+// Columns: Phrase,UI,Descriptor,Confidence,Via
+func writeConceptMappingsRows(w tableWriter, mappings []query.Mapping) {
+	w.Write([]string{"Phrase", "UI", "Descriptor", "Confidence", "Via"})
+	for _, m := range mappings {
+		w.Write([]string{m.Phrase, m.UI, m.Descriptor, strconv.FormatFloat(m.Confidence, 'f', 2, 64), m.Via})
+	}
+}
+
+// writeMeSHFrequencyRows writes descriptor frequencies as table rows.
+// Columns: Term,UI,Count,Percent
+func writeMeSHFrequencyRows(w tableWriter, report *analyze.MeSHReport) {
+	w.Write([]string{"Term", "UI", "Count", "Percent"})
+	for _, t := range report.Terms {
+		w.Write([]string{t.Term, t.UI, strconv.Itoa(t.Count), strconv.FormatFloat(t.Percent, 'f', 1, 64)})
+	}
+}
+
+// writeTriageRows writes ranked articles as table rows.
+// Columns: Rank,PMID,Year,Title,Score,Relevance,Matched,Missing,Justification
+func writeTriageRows(w tableWriter, results []triage.Result) {
+	w.Write([]string{"Rank", "PMID", "Year", "Title", "Score", "Relevance", "Matched", "Missing", "Justification"})
+	for _, r := range results {
+		w.Write([]string{strconv.Itoa(r.Rank), r.PMID, r.Year, r.Title,
+			strconv.FormatFloat(r.Score, 'f', 3, 64), strconv.FormatFloat(r.Relevance, 'f', 2, 64),
+			strings.Join(r.Matched, "; "), strings.Join(r.Missing, "; "), r.Justification})
+	}
+}
+
+// writeAffiliationRows writes countries, then institutions, as table rows.
+// Columns: Kind,Name,Country,Articles,Percent,PMIDs
+func writeAffiliationRows(w tableWriter, report *analyze.AffiliationReport) {
+	w.Write([]string{"Kind", "Name", "Country", "Articles", "Percent", "PMIDs"})
+	for _, c := range report.Countries {
+		w.Write([]string{"country", c.Name, "", strconv.Itoa(c.Articles), strconv.FormatFloat(c.Percent, 'f', 1, 64), strings.Join(c.PMIDs, "; ")})
+	}
+	for _, c := range report.Institutions {
+		w.Write([]string{"institution", c.Name, c.Country, strconv.Itoa(c.Articles), strconv.FormatFloat(c.Percent, 'f', 1, 64), strings.Join(c.PMIDs, "; ")})
+	}
+}
+
+// writeGrantRows writes funding agencies as table rows.
+// Columns: Agency,Country,Articles,Percent,GrantIDs,PMIDs
+func writeGrantRows(w tableWriter, report *analyze.GrantReport) {
+	w.Write([]string{"Agency", "Country", "Articles", "Percent", "GrantIDs", "PMIDs"})
+	for _, a := range report.Agencies {
+		w.Write([]string{a.Agency, a.Country, strconv.Itoa(a.Articles), strconv.FormatFloat(a.Percent, 'f', 1, 64),
+			strings.Join(a.GrantIDs, "; "), strings.Join(a.PMIDs, "; ")})
+	}
+}
+
+// writeKeywordRows writes keywords, bigrams, and author keywords as one
+// table. Columns: Kind,Term,Score,Articles
+func writeKeywordRows(w tableWriter, report *analyze.KeywordReport) {
+	w.Write([]string{"Kind", "Term", "Score", "Articles"})
+	for _, k := range report.Keywords {
+		w.Write([]string{"keyword", k.Term, strconv.FormatFloat(k.Score, 'f', 4, 64), strconv.Itoa(k.Articles)})
+	}
+	for _, k := range report.Bigrams {
+		w.Write([]string{"bigram", k.Term, strconv.FormatFloat(k.Score, 'f', 0, 64), strconv.Itoa(k.Articles)})
+	}
+	for _, t := range report.AuthorKeywords {
+		w.Write([]string{"author keyword", t.Term, "", strconv.Itoa(t.Count)})
+	}
+}
+
+// writeAbbreviationRows writes the glossary as table rows.
+// Columns: Short,Long,Articles,PMIDs,Variants
+func writeAbbreviationRows(w tableWriter, report *analyze.AbbreviationReport) {
+	w.Write([]string{"Short", "Long", "Articles", "PMIDs", "Variants"})
+	for _, a := range report.Abbreviations {
+		w.Write([]string{a.Short, a.Long, strconv.Itoa(a.Articles), strings.Join(a.PMIDs, ";"), strings.Join(a.Variants, "; ")})
+	}
+}
+
+// writeTrendsRows writes yearly counts as table rows, one column per query.
+// Columns: Year,<query>...
+func writeTrendsRows(w tableWriter, trends []analyze.Trend) {
+	header := []string{"Year"}
+	for _, t := range trends {
+		header = append(header, t.Query)
+	}
+	w.Write(header)
+	if len(trends) == 0 {
+		return
+	}
+	for i, c := range trends[0].Counts {
+		row := []string{strconv.Itoa(c.Year)}
+		for _, t := range trends {
+			row = append(row, strconv.Itoa(t.Counts[i].Count))
 		}
+		w.Write(row)
+	}
+}
 
-		// MeSH: semicolon-separated, major topics prefixed with *
-		meshTerms := make([]string, len(a.MeSHTerms))
-		for i, m := range a.MeSHTerms {
-			if m.MajorTopic {
-				meshTerms[i] = "*" + m.Descriptor
-			} else {
-				meshTerms[i] = m.Descriptor
+// writeJournalRows writes journal records as table rows, with one column
+// per year of recent article counts.
+// Columns: NLMID,Title,MedlineTA,ISOAbbreviation,ISSN,Publisher,CurrentlyIndexed[,YYYY...]
+func writeJournalRows(w tableWriter, journals []journal.Journal) {
+	header := []string{"NLMID", "Title", "MedlineTA", "ISOAbbreviation", "ISSN", "Publisher", "CurrentlyIndexed"}
+	var years []analyze.YearCount
+	if len(journals) > 0 {
+		years = journals[0].Counts
+	}
+	for _, y := range years {
+		header = append(header, strconv.Itoa(y.Year))
+	}
+	w.Write(header)
+	for _, j := range journals {
+		issns := make([]string, len(j.ISSNs))
+		for i, is := range j.ISSNs {
+			issns[i] = is.ISSN
+		}
+		row := []string{j.NLMID, j.Title, j.MedlineTA, j.ISOAbbrev, strings.Join(issns, "; "), j.Publisher,
+			strconv.FormatBool(j.CurrentlyIndexed)}
+		for i := range years {
+			count := ""
+			if i < len(j.Counts) {
+				count = strconv.Itoa(j.Counts[i].Count)
 			}
+			row = append(row, count)
+		}
+		w.Write(row)
+	}
+}
+
+// writeAlertResultsRows writes each alert's new PMIDs as table rows.
+// Columns: Alert,PMID,Title,Journal,Year,DOI
+func writeAlertResultsRows(w tableWriter, results []alert.Result) {
+	w.Write([]string{"Alert", "PMID", "Title", "Journal", "Year", "DOI"})
+	for _, r := range results {
+		byPMID := make(map[string]eutils.Article, len(r.Articles))
+		for _, a := range r.Articles {
+			byPMID[a.PMID] = a
+		}
+		for _, id := range r.New {
+			a := byPMID[id]
+			w.Write([]string{r.Name, id, a.Title, a.Journal, a.Year, a.DOI})
 		}
+	}
+}
 
+// writeDigestRows writes a digest's records, grouped as in its listing.
+// Columns: Journal,PMID,Title,First Author,Year,DOI
+func writeDigestRows(w tableWriter, d *alert.Digest) {
+	w.Write([]string{"Journal", "PMID", "Title", "First Author", "Year", "DOI"})
+	for _, g := range digestGroups(d) {
+		for _, a := range g.Articles {
+			w.Write([]string{g.Journal, a.PMID, a.Title, byline(a), a.Year, a.DOI})
+		}
+	}
+}
+
+// writeComparisonRows writes one row per compared field with a column per
+// article, mirroring the Markdown table.
+func writeComparisonRows(w tableWriter, profiles []compare.Profile) {
+	header := []string{"Field"}
+	for _, p := range profiles {
+		header = append(header, "PMID "+p.PMID)
+	}
+	w.Write(header)
+	row := []string{"Title"}
+	for _, p := range profiles {
+		row = append(row, p.Title)
+	}
+	w.Write(row)
+	for _, f := range compare.Fields {
+		row := []string{f.Name}
+		for _, p := range profiles {
+			row = append(row, f.Value(p))
+		}
+		w.Write(row)
+	}
+}
+
+// writePICORows writes PICO profiles as table rows.
+// Columns: PMID,Title,Year,Population,Intervention,Comparator,Outcomes,Design,N
+func writePICORows(w tableWriter, rows []compare.PICO) {
+	header := []string{"PMID", "Title", "Year"}
+	for _, f := range picoFields {
+		header = append(header, f.name)
+	}
+	w.Write(header)
+	for _, p := range rows {
+		row := []string{p.PMID, p.Title, p.Year}
+		for _, f := range picoFields {
+			row = append(row, f.value(p))
+		}
+		w.Write(row)
+	}
+}
+
+// writeScreeningRows writes screening decisions as table rows.
+// Columns: PMID,Decision,DecidedAt,Title,Journal,Year,DOI
+func writeScreeningRows(w tableWriter, items []*screen.Item) {
+	w.Write([]string{"PMID", "Decision", "DecidedAt", "Title", "Journal", "Year", "DOI"})
+	for _, it := range items {
+		decided := ""
+		if !it.DecidedAt.IsZero() {
+			decided = it.DecidedAt.UTC().Format(time.RFC3339)
+		}
+		a := it.Article
+		w.Write([]string{a.PMID, it.Decision, decided, a.Title, a.Journal, a.Year, a.DOI})
+	}
+}
+
+// writeDuplicatesRows writes removed duplicates as table rows.
+// Columns: DroppedSource,DroppedIndex,KeptSource,KeptIndex,Reason,Similarity,PMID,DOI,Title
+func writeDuplicatesRows(w tableWriter, dups []dedupe.Duplicate) {
+	w.Write([]string{"DroppedSource", "DroppedIndex", "KeptSource", "KeptIndex", "Reason", "Similarity", "PMID", "DOI", "Title"})
+	for _, d := range dups {
+		sim := ""
+		if d.Reason == dedupe.ByTitle {
+			sim = strconv.FormatFloat(d.Similarity, 'f', 2, 64)
+		}
+		a := d.Dropped.Article
 		w.Write([]string{
-			a.PMID,
-			a.Title,
-			strings.Join(names, "; "),
-			a.Journal,
-			a.Year,
-			a.DOI,
-			a.Abstract,
-			strings.Join(meshTerms, "; "),
+			d.Dropped.Source, strconv.Itoa(d.Dropped.Index),
+			d.Kept.Source, strconv.Itoa(d.Kept.Index),
+			d.Reason, sim, a.PMID, a.DOI, a.Title,
 		})
 	}
+}
 
-	w.Flush()
-	return w.Error()
+// tableWriter receives the header and data rows of a tabular export.
+// *csv.Writer satisfies it, as do tsvWriter and xlsxWriter.
+type tableWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
 }
 
-// writeLinksCSV exports link results to CSV.
-// Columns: PMID,Score
-func writeLinksCSV(path string, result *eutils.LinkResult) error {
-	w, f, err := createCSV(path)
+// exportTables writes the rows produced by write to each tabular export
+// requested in cfg (--csv, --tsv, and/or an .xlsx --out).
+func exportTables(cfg OutputConfig, write func(w tableWriter)) error {
+	exports := []struct {
+		path      string
+		name      string
+		newWriter func(io.Writer) tableWriter
+	}{
+		{cfg.CSVFile, "CSV", func(w io.Writer) tableWriter { return csv.NewWriter(w) }},
+		{cfg.TSVFile, "TSV", func(w io.Writer) tableWriter { return newTSVWriter(w) }},
+		{cfg.XLSXFile, "XLSX", func(w io.Writer) tableWriter { return newXLSXWriter(w) }},
+	}
+	for _, e := range exports {
+		if e.path == "" {
+			continue
+		}
+		if err := exportTable(e.path, e.newWriter, write); err != nil {
+			return fmt.Errorf("%s export failed: %w", e.name, err)
+		}
+	}
+	return nil
+}
+
+func exportTable(path string, newWriter func(io.Writer) tableWriter, write func(w tableWriter)) error {
+	f, err := os.Create(path)
 	if err != nil {
-		return err
+		return fmt.Errorf("creating export file: %w", err)
 	}
 	defer f.Close()
 
-	w.Write([]string{"PMID", "Score"})
+	w := newWriter(f)
+	write(w)
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
 
-	for _, link := range result.Links {
-		score := ""
-		if link.Score > 0 {
-			score = strconv.Itoa(link.Score)
+// tsvWriter writes tab-separated rows without quoting. Tabs and line
+// breaks inside fields become spaces so every record stays on one line,
+// which is what awk, cut, and spreadsheet imports expect.
+type tsvWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newTSVWriter(w io.Writer) *tsvWriter {
+	return &tsvWriter{w: bufio.NewWriter(w)}
+}
+
+var tsvFieldReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+func (t *tsvWriter) Write(record []string) error {
+	if t.err != nil {
+		return t.err
+	}
+	for i, field := range record {
+		if i > 0 {
+			t.w.WriteByte('\t')
 		}
-		w.Write([]string{link.ID, score})
+		t.w.WriteString(tsvFieldReplacer.Replace(field))
 	}
+	_, t.err = t.w.WriteString("\n")
+	return t.err
+}
 
-	w.Flush()
-	return w.Error()
+func (t *tsvWriter) Flush() {
+	if t.err == nil {
+		t.err = t.w.Flush()
+	}
 }
 
-// writeMeSHCSV exports a MeSH record to CSV.
-// Columns: UI,Name,ScopeNote,TreeNumbers,EntryTerms,Annotation
-func writeMeSHCSV(path string, record *mesh.MeSHRecord) error {
-	w, f, err := createCSV(path)
-	if err != nil {
-		return err
+func (t *tsvWriter) Error() error {
+	return t.err
+}
+
+// writeBatchRows writes one row per batch job.
+func writeBatchRows(w tableWriter, s batch.Summary) {
+	w.Write([]string{"Job", "Query", "Count", "Retrieved", "Out", "Exported", "Error", "Seconds"})
+	for _, r := range s.Jobs {
+		w.Write([]string{r.Name, r.Query, strconv.Itoa(r.Count), strconv.Itoa(len(r.PMIDs)), r.Out,
+			strconv.Itoa(r.Exported), r.Error, strconv.FormatFloat(r.Seconds, 'f', 1, 64)})
 	}
-	defer f.Close()
+}
 
-	w.Write([]string{"UI", "Name", "ScopeNote", "TreeNumbers", "EntryTerms", "Annotation"})
-	w.Write([]string{
-		record.UI,
-		record.Name,
-		record.ScopeNote,
-		strings.Join(record.TreeNumbers, "; "),
-		strings.Join(record.EntryTerms, "; "),
-		record.Annotation,
-	})
+// writeNetworkRows writes one row per network node, most central first.
+func writeNetworkRows(w tableWriter, n *network.Network) {
+	w.Write([]string{"ID", "Label", "Year", "Articles", "Citations", "Degree", "DegreeCentrality", "Betweenness", "PageRank"})
+	for _, node := range n.Nodes {
+		w.Write([]string{node.ID, node.Label, node.Year, strconv.Itoa(node.Articles), strconv.Itoa(node.Citations), strconv.Itoa(node.Degree),
+			strconv.FormatFloat(node.DegreeCentrality, 'f', 4, 64), strconv.FormatFloat(node.Betweenness, 'f', 4, 64),
+			strconv.FormatFloat(node.PageRank, 'f', 4, 64)})
+	}
+}
 
-	w.Flush()
-	return w.Error()
+// writeRetractionRows writes one row per flagged article.
+func writeRetractionRows(w tableWriter, r retraction.Report) {
+	w.Write([]string{"Reference", "PMID", "Title", "Journal", "Year", "DOI", "Statuses", "Notices"})
+	for _, f := range r.Flagged {
+		notices := make([]string, len(f.Notices))
+		for i, n := range f.Notices {
+			notices[i] = noticeText(n)
+		}
+		w.Write([]string{f.Reference, f.PMID, f.Title, f.Journal, f.Year, f.DOI, strings.Join(f.Statuses, "; "), strings.Join(notices, "; ")})
+	}
 }
 
-func createCSV(path string) (*csv.Writer, *os.File, error) {
-	f, err := os.Create(path)
-	if err != nil {
-		return nil, nil, fmt.Errorf("creating CSV file: %w", err)
+// writeStanceRows writes one row per citing article.
+func writeStanceRows(w tableWriter, r stance.Report) {
+	w.Write([]string{"PMID", "PMCID", "Title", "Journal", "Year", "Stance", "Contexts"})
+	for _, c := range r.Citations {
+		w.Write([]string{c.PMID, c.PMCID, c.Title, c.Journal, c.Year, c.Stance, strings.Join(c.Contexts, " | ")})
+	}
+}
+
+// writeZoteroRows writes one row per pushed or rejected article.
+func writeZoteroRows(w tableWriter, r *zotero.Report) {
+	w.Write([]string{"PMID", "DOI", "Key", "Status", "Message"})
+	for _, p := range r.Items {
+		w.Write([]string{p.PMID, p.DOI, p.Key, "created", ""})
+	}
+	for _, f := range r.Failed {
+		w.Write([]string{f.PMID, f.DOI, "", "failed", f.Message})
+	}
+}
+
+// writeFullTextRows writes where each article's full text was found.
+func writeFullTextRows(w tableWriter, results []fulltext.Result) {
+	w.Write([]string{"PMID", "PMCID", "DOI", "Source", "Format", "License", "LicenseURL", "URL", "Path"})
+	for _, r := range results {
+		w.Write([]string{r.PMID, r.PMCID, r.DOI, r.Source, r.Format, r.License, r.LicenseURL, r.URL, r.Path})
 	}
-	return csv.NewWriter(f), f, nil
 }
@@ -38,7 +38,7 @@ func TestWriteSearchCSV_WithArticles(t *testing.T) {
 		},
 	}
 
-	err := writeSearchCSV(path, result, articles)
+	err := exportTables(OutputConfig{CSVFile: path}, func(w tableWriter) { writeSearchRows(w, result, articles, nil) })
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -83,7 +83,7 @@ func TestWriteSearchCSV_WithoutArticles(t *testing.T) {
 		IDs:   []string{"111", "222"},
 	}
 
-	err := writeSearchCSV(path, result, nil)
+	err := exportTables(OutputConfig{CSVFile: path}, func(w tableWriter) { writeSearchRows(w, result, nil, nil) })
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -122,7 +122,7 @@ func TestWriteArticlesCSV(t *testing.T) {
 		},
 	}
 
-	err := writeArticlesCSV(path, articles)
+	err := exportTables(OutputConfig{CSVFile: path}, func(w tableWriter) { writeArticlesRows(w, articles, nil) })
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -167,7 +167,7 @@ func TestWriteLinksCSV(t *testing.T) {
 		},
 	}
 
-	err := writeLinksCSV(path, result)
+	err := exportTables(OutputConfig{CSVFile: path}, func(w tableWriter) { writeLinksRows(w, result) })
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -200,7 +200,7 @@ func TestWriteMeSHCSV(t *testing.T) {
 		EntryTerms:  []string{"FXS", "Martin-Bell"},
 	}
 
-	err := writeMeSHCSV(path, record)
+	err := exportTables(OutputConfig{CSVFile: path}, func(w tableWriter) { writeMeSHRows(w, record) })
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -221,6 +221,47 @@ func TestWriteMeSHCSV(t *testing.T) {
 	}
 }
 
+func TestWriteArticlesTSV(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "articles.tsv")
+
+	articles := []eutils.Article{
+		{
+			PMID:     "12345",
+			Title:    `Tabs	and "quotes", commas`,
+			Journal:  "Test Journal",
+			Year:     "2024",
+			Abstract: "BACKGROUND: line one.\n\nMETHODS: line two.",
+		},
+	}
+
+	if err := exportTables(OutputConfig{TSVFile: path}, func(w tableWriter) { writeArticlesRows(w, articles, nil) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read TSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header + 1 row, got %d lines:\n%s", len(lines), data)
+	}
+	if lines[0] != "PMID\tTitle\tAuthors\tJournal\tYear\tDOI\tAbstract\tMeSH" {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	fields := strings.Split(lines[1], "\t")
+	if len(fields) != 8 {
+		t.Fatalf("expected 8 fields, got %d: %q", len(fields), lines[1])
+	}
+	if fields[1] != `Tabs and "quotes", commas` {
+		t.Errorf("title should be unquoted with tabs replaced, got %q", fields[1])
+	}
+	if fields[6] != "BACKGROUND: line one.  METHODS: line two." {
+		t.Errorf("abstract should be on one line, got %q", fields[6])
+	}
+}
+
 // readCSV is a test helper that reads and parses a CSV file.
 func readCSV(t *testing.T, path string) [][]string {
 	t.Helper()
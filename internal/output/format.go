@@ -2,257 +2,2060 @@
 package output
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/batch"
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/compare"
+	"github.com/henrybloomingdale/pubmed-cli/internal/dedupe"
 	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/graph"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/journal"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
 	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+	"github.com/henrybloomingdale/pubmed-cli/internal/network"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/henrybloomingdale/pubmed-cli/internal/retraction"
+	"github.com/henrybloomingdale/pubmed-cli/internal/screen"
+	"github.com/henrybloomingdale/pubmed-cli/internal/stance"
+	"github.com/henrybloomingdale/pubmed-cli/internal/timeline"
+	"github.com/henrybloomingdale/pubmed-cli/internal/triage"
+	"github.com/henrybloomingdale/pubmed-cli/internal/zotero"
 )
 
 // OutputConfig controls which output mode(s) are active.
 type OutputConfig struct {
-	JSON    bool   // Structured JSON
-	Human   bool   // Rich terminal output with color
-	Full    bool   // Show full abstract (human mode)
-	CSVFile string // Export results to this CSV path (works alongside any mode)
-	RISFile string // Export results to this RIS path (works alongside any mode)
+	JSON       bool     // Structured JSON
+	NDJSON     bool     // One compact JSON object per line (articles or links)
+	Human      bool     // Rich terminal output with color
+	Full       bool     // Show full abstract (human mode)
+	CSVFile    string   // Export results to this CSV path (works alongside any mode)
+	TSVFile    string   // Export results to this TSV path (works alongside any mode)
+	XLSXFile   string   // Export results to this Excel workbook path (works alongside any mode)
+	Columns    []string // Article fields and order for CSV/TSV exports (nil for defaults)
+	RISFile    string   // Export results to this RIS path (works alongside any mode)
+	BibTeXFile string   // Export results to this BibTeX path (works alongside any mode)
+	CSLFile    string   // Export results to this CSL-JSON path (works alongside any mode)
+	Highlight  []string // Terms to highlight in human titles and abstracts, longest first
+	ASCII      bool     // Transliterate article text to ASCII (see NormalizeArticles)
+	// Suggestions are MeSH clauses human search output offers to add.
+	Suggestions []Suggestion
 }
 
 // FormatSearchResult writes search results.
-// articles may be non-nil when --human or --csv triggers an auto-fetch.
+// articles may be non-nil when --human, an export, or --ndjson triggers an
+// auto-fetch; RIS and BibTeX exports are written from them.
 func FormatSearchResult(w io.Writer, result *eutils.SearchResult, articles []eutils.Article, cfg OutputConfig) error {
-	if cfg.CSVFile != "" {
-		if err := writeSearchCSV(cfg.CSVFile, result, articles); err != nil {
-			return fmt.Errorf("CSV export failed: %w", err)
+	articles = NormalizeArticles(articles, cfg.ASCII)
+	if err := exportTables(cfg, func(w tableWriter) { writeSearchRows(w, result, articles, cfg.Columns) }); err != nil {
+		return err
+	}
+	if err := exportCitations(cfg, articles); err != nil {
+		return err
+	}
+	if cfg.NDJSON {
+		if articles != nil {
+			return writeNDJSON(w, versionArticles(articles))
 		}
+		return writeSearchNDJSON(w, result)
 	}
 	if cfg.JSON {
-		return writeJSON(w, result)
+		return writeJSON(w, versionedSearch{SchemaVersion: SchemaVersion, SearchResult: result})
 	}
 	if cfg.Human {
-		return formatSearchHuman(w, result, articles)
+		return formatSearchHuman(humanWriter(w), result, articles, newHighlighter(cfg.Highlight), cfg.Suggestions)
 	}
 	return formatSearchPlain(w, result)
 }
 
 // FormatArticles writes article details.
 func FormatArticles(w io.Writer, articles []eutils.Article, cfg OutputConfig) error {
-	if cfg.CSVFile != "" {
-		if err := writeArticlesCSV(cfg.CSVFile, articles); err != nil {
-			return fmt.Errorf("CSV export failed: %w", err)
-		}
+	articles = NormalizeArticles(articles, cfg.ASCII)
+	if err := exportTables(cfg, func(w tableWriter) { writeArticlesRows(w, articles, cfg.Columns) }); err != nil {
+		return err
 	}
-	if cfg.RISFile != "" {
-		if err := writeArticlesRIS(cfg.RISFile, articles); err != nil {
-			return fmt.Errorf("RIS export failed: %w", err)
-		}
+	if err := exportCitations(cfg, articles); err != nil {
+		return err
+	}
+	if cfg.NDJSON {
+		return writeNDJSON(w, versionArticles(articles))
 	}
 	if cfg.JSON {
-		return writeJSON(w, articles)
+		return writeJSON(w, versionArticles(articles))
 	}
 	if cfg.Human {
-		return formatArticlesHuman(w, articles, cfg.Full)
+		return formatArticlesHuman(humanWriter(w), articles, cfg.Full, newHighlighter(cfg.Highlight))
 	}
 	return formatArticlesPlain(w, articles)
 }
 
 // FormatLinks writes link results.
 func FormatLinks(w io.Writer, result *eutils.LinkResult, linkType string, cfg OutputConfig) error {
-	if cfg.CSVFile != "" {
-		if err := writeLinksCSV(cfg.CSVFile, result); err != nil {
-			return fmt.Errorf("CSV export failed: %w", err)
-		}
+	if err := exportTables(cfg, func(w tableWriter) { writeLinksRows(w, result) }); err != nil {
+		return err
+	}
+	if cfg.NDJSON {
+		return writeLinksNDJSON(w, result)
 	}
 	if cfg.JSON {
-		return writeJSON(w, result)
+		return writeJSON(w, versionedLinks{SchemaVersion: SchemaVersion, LinkResult: result})
 	}
 	if cfg.Human {
-		return formatLinksHuman(w, result, linkType)
+		return formatLinksHuman(humanWriter(w), result, linkType)
 	}
 	return formatLinksPlain(w, result, linkType)
 }
 
 // FormatMeSHRecord writes a MeSH record.
 func FormatMeSHRecord(w io.Writer, record *mesh.MeSHRecord, cfg OutputConfig) error {
-	if cfg.CSVFile != "" {
-		if err := writeMeSHCSV(cfg.CSVFile, record); err != nil {
-			return fmt.Errorf("CSV export failed: %w", err)
-		}
+	if err := exportTables(cfg, func(w tableWriter) { writeMeSHRows(w, record) }); err != nil {
+		return err
 	}
 	if cfg.JSON {
 		return writeJSON(w, record)
 	}
 	if cfg.Human {
-		return formatMeSHHuman(w, record)
+		return formatMeSHHuman(humanWriter(w), record)
 	}
 	return formatMeSHPlain(w, record)
 }
 
-// --- Plain text formatters (default) ---
+// FormatMeSHTree writes a descriptor's positions in the MeSH hierarchy.
+func FormatMeSHTree(w io.Writer, h *mesh.Hierarchy, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeMeSHTreeRows(w, h) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, h)
+	}
+	if cfg.Human {
+		return formatMeSHTreeHuman(humanWriter(w), h)
+	}
+	return formatMeSHTreePlain(w, h)
+}
 
-func formatSearchPlain(w io.Writer, result *eutils.SearchResult) error {
-	if result.Count == 0 {
-		fmt.Fprintln(w, "No results found.")
-		return nil
+// FormatMeSHSuggestions writes ranked descriptor suggestions for a prefix.
+func FormatMeSHSuggestions(w io.Writer, prefix string, suggestions []mesh.Suggestion, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeMeSHSuggestionsRows(w, suggestions) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		if suggestions == nil {
+			suggestions = []mesh.Suggestion{}
+		}
+		return writeJSON(w, suggestions)
+	}
+	if cfg.Human {
+		return formatMeSHSuggestionsHuman(humanWriter(w), prefix, suggestions)
 	}
+	return formatMeSHSuggestionsPlain(w, prefix, suggestions)
+}
 
-	fmt.Fprintf(w, "Found %d results", result.Count)
-	if len(result.IDs) < result.Count {
-		fmt.Fprintf(w, " (showing %d)", len(result.IDs))
+// FormatMeSHQualifiers writes qualifiers, either the full list (record nil)
+// or those allowed with a descriptor.
+func FormatMeSHQualifiers(w io.Writer, record *mesh.MeSHRecord, qualifiers []mesh.Qualifier, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeMeSHQualifiersRows(w, qualifiers) }); err != nil {
+		return err
 	}
-	fmt.Fprintln(w)
+	if cfg.JSON {
+		return writeJSON(w, qualifiers)
+	}
+	if cfg.Human {
+		return formatMeSHQualifiersHuman(humanWriter(w), record, qualifiers)
+	}
+	return formatMeSHQualifiersPlain(w, record, qualifiers)
+}
 
-	if result.QueryTranslation != "" {
-		fmt.Fprintf(w, "Query: %s\n", result.QueryTranslation)
+// FormatMeSHRecords writes a list of MeSH records by UI and name, such as
+// the members of a pharmacological action class.
+func FormatMeSHRecords(w io.Writer, title string, records []mesh.MeSHRecord, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeMeSHRecordsRows(w, records) }); err != nil {
+		return err
 	}
-	fmt.Fprintln(w)
+	if cfg.JSON {
+		if records == nil {
+			records = []mesh.MeSHRecord{}
+		}
+		return writeJSON(w, records)
+	}
+	if cfg.Human {
+		return formatMeSHRecordsHuman(humanWriter(w), title, records)
+	}
+	return formatMeSHRecordsPlain(w, title, records)
+}
 
-	for i, id := range result.IDs {
-		fmt.Fprintf(w, "  %d. PMID: %s\n", i+1, id)
+// FormatConceptMappings writes candidate descriptors for a natural-language question.
+func FormatConceptMappings(w io.Writer, mappings []query.Mapping, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeConceptMappingsRows(w, mappings) }); err != nil {
+		return err
 	}
+	if cfg.JSON {
+		if mappings == nil {
+			mappings = []query.Mapping{}
+		}
+		return writeJSON(w, mappings)
+	}
+	if cfg.Human {
+		return formatConceptMappingsHuman(humanWriter(w), mappings)
+	}
+	return formatConceptMappingsPlain(w, mappings)
+}
 
-	return nil
+// FormatMeSHFrequency writes descriptor frequencies across a result set.
+func FormatMeSHFrequency(w io.Writer, report *analyze.MeSHReport, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeMeSHFrequencyRows(w, report) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, report)
+	}
+	if cfg.Human {
+		return formatMeSHFrequencyHuman(humanWriter(w), report)
+	}
+	return formatMeSHFrequencyPlain(w, report)
 }
 
-func formatArticlesPlain(w io.Writer, articles []eutils.Article) error {
-	if len(articles) == 0 {
-		fmt.Fprintln(w, "No articles found.")
-		return nil
+// FormatAffiliations writes the countries and institutions of a result
+// set's authors and exports them as table rows.
+func FormatAffiliations(w io.Writer, report *analyze.AffiliationReport, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeAffiliationRows(w, report) }); err != nil {
+		return err
 	}
+	if cfg.JSON {
+		return writeJSON(w, report)
+	}
+	if cfg.Human {
+		return formatAffiliationsHuman(humanWriter(w), report)
+	}
+	return formatAffiliationsPlain(w, report)
+}
 
-	for i, a := range articles {
-		if i > 0 {
-			fmt.Fprintf(w, "\n%s\n\n", strings.Repeat("─", 80))
-		}
+// FormatGrants writes the funding agencies of a result set and exports
+// them as table rows.
+func FormatGrants(w io.Writer, report *analyze.GrantReport, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeGrantRows(w, report) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, report)
+	}
+	if cfg.Human {
+		return formatGrantsHuman(humanWriter(w), report)
+	}
+	return formatGrantsPlain(w, report)
+}
 
-		fmt.Fprintf(w, "PMID: %s\n", a.PMID)
-		fmt.Fprintf(w, "Title: %s\n", a.Title)
+// FormatKeywords writes the keywords, bigrams, author keywords, and
+// suggested query terms of a result set.
+func FormatKeywords(w io.Writer, report *analyze.KeywordReport, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeKeywordRows(w, report) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, report)
+	}
+	if cfg.Human {
+		return formatKeywordsHuman(humanWriter(w), report)
+	}
+	return formatKeywordsPlain(w, report)
+}
 
-		if len(a.Authors) > 0 {
-			names := make([]string, len(a.Authors))
-			for j, au := range a.Authors {
-				names[j] = au.FullName()
-			}
-			fmt.Fprintf(w, "Authors: %s\n", strings.Join(names, ", "))
-		}
+// FormatAbbreviations writes the glossary of abbreviations a result set
+// defines.
+func FormatAbbreviations(w io.Writer, report *analyze.AbbreviationReport, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeAbbreviationRows(w, report) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, report)
+	}
+	if cfg.Human {
+		return formatAbbreviationsHuman(humanWriter(w), report)
+	}
+	return formatAbbreviationsPlain(w, report)
+}
 
-		citation := a.Journal
-		if a.Volume != "" {
-			citation += " " + a.Volume
-			if a.Issue != "" {
-				citation += "(" + a.Issue + ")"
-			}
-		}
-		if a.Pages != "" {
-			citation += ":" + a.Pages
-		}
-		if a.Year != "" {
-			citation += " (" + a.Year + ")"
-		}
-		fmt.Fprintf(w, "Journal: %s\n", citation)
+// FormatTrends writes publication counts per year for one or more queries.
+func FormatTrends(w io.Writer, trends []analyze.Trend, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeTrendsRows(w, trends) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, trends)
+	}
+	if cfg.Human {
+		return formatTrendsHuman(humanWriter(w), trends)
+	}
+	return formatTrendsPlain(w, trends)
+}
 
-		if a.DOI != "" {
-			fmt.Fprintf(w, "DOI: %s\n", a.DOI)
-		}
-		if a.PMCID != "" {
-			fmt.Fprintf(w, "PMCID: %s\n", a.PMCID)
-		}
-		if len(a.PublicationTypes) > 0 {
-			fmt.Fprintf(w, "Type: %s\n", strings.Join(a.PublicationTypes, ", "))
-		}
-		if a.Abstract != "" {
-			fmt.Fprintln(w)
-			fmt.Fprintln(w, "Abstract:")
-			fmt.Fprintln(w, a.Abstract)
-		}
-		if len(a.MeSHTerms) > 0 {
-			fmt.Fprintln(w)
-			fmt.Fprintln(w, "MeSH Terms:")
-			for _, m := range a.MeSHTerms {
-				marker := "  "
-				if m.MajorTopic {
-					marker = "* "
-				}
-				term := m.Descriptor
-				if len(m.Qualifiers) > 0 {
-					term += " / " + strings.Join(m.Qualifiers, ", ")
-				}
-				fmt.Fprintf(w, "  %s%s\n", marker, term)
-			}
-		}
+// FormatJournals prints NLM Catalog journal records.
+func FormatJournals(w io.Writer, journals []journal.Journal, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeJournalRows(w, journals) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, journals)
 	}
+	if cfg.Human {
+		return formatJournalsHuman(humanWriter(w), journals)
+	}
+	return formatJournalsPlain(w, journals)
+}
 
-	return nil
+// FormatJournalChecks reports whether each journal named in a query matches
+// an NLM Catalog title.
+func FormatJournalChecks(w io.Writer, checks []journal.Check, cfg OutputConfig) error {
+	if cfg.JSON {
+		return writeJSON(w, checks)
+	}
+	if cfg.Human {
+		return formatJournalChecksHuman(humanWriter(w), checks)
+	}
+	return formatJournalChecksPlain(w, checks)
 }
 
-func formatLinksPlain(w io.Writer, result *eutils.LinkResult, linkType string) error {
-	if len(result.Links) == 0 {
-		fmt.Fprintf(w, "No %s results for PMID %s.\n", linkType, result.SourceID)
-		return nil
+// Suggestion is a MeSH descriptor the top search results share that the
+// query does not use: a major topic of Articles of the top Sample.
+type Suggestion struct {
+	Clause   string
+	Articles int
+	Sample   int
+}
+
+// FormatLint reports the problems found in a query and how PubMed
+// translated it.
+func FormatLint(w io.Writer, r *query.LintReport, cfg OutputConfig) error {
+	if cfg.JSON {
+		return writeJSON(w, r)
+	}
+	if cfg.Human {
+		return formatLintHuman(humanWriter(w), r)
 	}
+	return formatLintPlain(w, r)
+}
 
-	title := linkType
-	switch linkType {
-	case "cited-by":
-		title = "Cited By"
-	case "references":
-		title = "References"
-	case "related":
-		title = "Related Articles"
+// FormatCacheReport prints the size of each cache section and the
+// response cache's entries and hit rate per endpoint.
+func FormatCacheReport(w io.Writer, r cache.Report, cfg OutputConfig) error {
+	if cfg.JSON {
+		return writeJSON(w, r)
+	}
+	if cfg.Human {
+		return formatCacheReportHuman(humanWriter(w), r)
 	}
+	return formatCacheReportPlain(w, r)
+}
 
-	fmt.Fprintf(w, "%s for PMID %s (%d results):\n\n", title, result.SourceID, len(result.Links))
+// FormatBatchSummary reports each job of a batch run.
+func FormatBatchSummary(w io.Writer, s batch.Summary, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeBatchRows(w, s) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		if s.Jobs == nil {
+			s.Jobs = []batch.Result{}
+		}
+		return writeJSON(w, s)
+	}
+	if cfg.Human {
+		return formatBatchSummaryHuman(humanWriter(w), s)
+	}
+	return formatBatchSummaryPlain(w, s)
+}
 
-	for i, link := range result.Links {
-		if link.Score > 0 {
-			fmt.Fprintf(w, "  %d. PMID: %s (score: %d)\n", i+1, link.ID, link.Score)
-		} else {
-			fmt.Fprintf(w, "  %d. PMID: %s\n", i+1, link.ID)
+// FormatFullText prints each article's full text, or where it was saved
+// or can be read, with its license.
+func FormatFullText(w io.Writer, results []fulltext.Result, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeFullTextRows(w, results) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		if results == nil {
+			results = []fulltext.Result{}
 		}
+		return writeJSON(w, results)
+	}
+	if cfg.Human {
+		return formatFullTextHuman(humanWriter(w), results)
 	}
+	return formatFullTextPlain(w, results)
+}
 
-	return nil
+// FormatRetractionReport lists the retracted, corrected, and
+// expression-of-concern articles in a checked reference list.
+func FormatRetractionReport(w io.Writer, r retraction.Report, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeRetractionRows(w, r) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		if r.Flagged == nil {
+			r.Flagged = []retraction.Finding{}
+		}
+		return writeJSON(w, r)
+	}
+	if cfg.Human {
+		return formatRetractionReportHuman(humanWriter(w), r)
+	}
+	return formatRetractionReportPlain(w, r)
 }
 
-func formatMeSHPlain(w io.Writer, record *mesh.MeSHRecord) error {
-	fmt.Fprintf(w, "MeSH Term: %s\n", record.Name)
-	fmt.Fprintf(w, "UI: %s\n", record.UI)
+// FormatStanceReport lists how each citing article cites a paper, with
+// the citing sentences, and totals the stances.
+func FormatStanceReport(w io.Writer, r stance.Report, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeStanceRows(w, r) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, r)
+	}
+	if cfg.Human {
+		return formatStanceReportHuman(humanWriter(w), r)
+	}
+	return formatStanceReportPlain(w, r)
+}
 
-	if len(record.TreeNumbers) > 0 {
-		fmt.Fprintln(w)
-		fmt.Fprintln(w, "Tree Numbers:")
-		for _, tn := range record.TreeNumbers {
-			fmt.Fprintf(w, "  %s\n", tn)
+// FormatZoteroReport summarizes a push to a Zotero library.
+func FormatZoteroReport(w io.Writer, r *zotero.Report, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeZoteroRows(w, r) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		if r.Items == nil {
+			r.Items = []zotero.Pushed{}
 		}
+		return writeJSON(w, r)
 	}
-
-	if record.ScopeNote != "" {
-		fmt.Fprintln(w)
-		fmt.Fprintln(w, "Scope Note:")
-		fmt.Fprintf(w, "  %s\n", record.ScopeNote)
+	if cfg.Human {
+		return formatZoteroReportHuman(humanWriter(w), r)
 	}
+	return formatZoteroReportPlain(w, r)
+}
 
-	if len(record.EntryTerms) > 0 {
-		fmt.Fprintln(w)
-		fmt.Fprintln(w, "Entry Terms (synonyms):")
-		for _, et := range record.EntryTerms {
-			fmt.Fprintf(w, "  - %s\n", et)
+// FormatHistory lists recorded searches, oldest first.
+func FormatHistory(w io.Writer, entries []history.Entry, cfg OutputConfig) error {
+	if cfg.JSON {
+		if entries == nil {
+			entries = []history.Entry{}
 		}
+		return writeJSON(w, entries)
 	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No searches recorded yet.")
+		return nil
+	}
+	if cfg.Human {
+		return formatHistoryHuman(humanWriter(w), entries)
+	}
+	for _, e := range entries {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", e.ID, e.Time.Local().Format("2006-01-02 15:04"), e.Count, e.Query)
+	}
+	return nil
+}
 
-	if record.Annotation != "" {
-		fmt.Fprintln(w)
-		fmt.Fprintf(w, "Annotation: %s\n", record.Annotation)
+// FormatHistoryDiff reports how two runs of a search differ.
+func FormatHistoryDiff(w io.Writer, d history.Diff, cfg OutputConfig) error {
+	if cfg.JSON {
+		return writeJSON(w, d)
+	}
+	if cfg.Human {
+		return formatHistoryDiffHuman(humanWriter(w), d)
 	}
+	return formatHistoryDiffPlain(w, d)
+}
 
+// FormatCollections lists library collections.
+func FormatCollections(w io.Writer, collections []*library.Collection, cfg OutputConfig) error {
+	if cfg.JSON {
+		if collections == nil {
+			collections = []*library.Collection{}
+		}
+		return writeJSON(w, collections)
+	}
+	if len(collections) == 0 {
+		fmt.Fprintln(w, "No collections. Add articles with: pubmed lib add <collection> <pmid...>")
+		return nil
+	}
+	if cfg.Human {
+		w = humanWriter(w)
+	}
+	for _, c := range collections {
+		updated := "updated " + c.Updated.Local().Format("2006-01-02 15:04")
+		if cfg.Human {
+			fmt.Fprintf(w, "📚 %s  %s\n", bold.Render(c.Name),
+				dim.Render(fmt.Sprintf("%d articles · %s", len(c.PMIDs), updated)))
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d articles\t%s\n", c.Name, len(c.PMIDs), updated)
+	}
 	return nil
 }
 
-func writeJSON(w io.Writer, v interface{}) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	enc.SetEscapeHTML(false)
-	return enc.Encode(v)
+// FormatComparison writes article profiles side by side: a Markdown table
+// by default, one block per field with --human.
+func FormatComparison(w io.Writer, profiles []compare.Profile, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeComparisonRows(w, profiles) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, profiles)
+	}
+	if cfg.Human {
+		return formatComparisonHuman(humanWriter(w), profiles)
+	}
+	return formatComparisonMarkdown(w, profiles)
+}
+
+// FormatPICO writes PICO profiles, one per article, and exports them as
+// table rows.
+func FormatPICO(w io.Writer, rows []compare.PICO, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writePICORows(w, rows) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, rows)
+	}
+	if cfg.Human {
+		return formatPICOHuman(humanWriter(w), rows)
+	}
+	return formatPICOPlain(w, rows)
+}
+
+// FormatTriage writes articles ranked against question and exports them as
+// table rows.
+func FormatTriage(w io.Writer, question string, results []triage.Result, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeTriageRows(w, results) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, results)
+	}
+	if cfg.Human {
+		return formatTriageHuman(humanWriter(w), question, results)
+	}
+	return formatTriagePlain(w, question, results)
+}
+
+// FormatSummaries writes triage summaries of articles.
+func FormatSummaries(w io.Writer, summaries []compare.Summary, cfg OutputConfig) error {
+	if cfg.JSON {
+		return writeJSON(w, summaries)
+	}
+	if cfg.Human {
+		return formatSummariesHuman(humanWriter(w), summaries)
+	}
+	return formatSummariesPlain(w, summaries)
+}
+
+// FormatAlertResults writes the new results each alert found. Alerts with
+// nothing new are omitted from plain and human output.
+func FormatAlertResults(w io.Writer, results []alert.Result, cfg OutputConfig) error {
+	var articles []eutils.Article
+	for _, r := range results {
+		articles = append(articles, r.Articles...)
+	}
+	if err := exportTables(cfg, func(w tableWriter) { writeAlertResultsRows(w, results) }); err != nil {
+		return err
+	}
+	if err := exportCitations(cfg, articles); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, results)
+	}
+	if cfg.Human {
+		return formatAlertResultsHuman(humanWriter(w), results)
+	}
+	return formatAlertResultsPlain(w, results)
+}
+
+// FormatAlertDiff writes how an alert's results changed and exports the
+// articles that appeared or changed.
+func FormatAlertDiff(w io.Writer, d alert.Diff, cfg OutputConfig) error {
+	if err := exportCitations(cfg, d.Articles); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, d)
+	}
+	if cfg.Human {
+		return formatAlertDiffHuman(humanWriter(w), d)
+	}
+	return formatAlertDiffPlain(w, d)
+}
+
+// FormatDigest writes the records a topic added to PubMed recently, grouped
+// by journal with the busiest journals first.
+func FormatDigest(w io.Writer, d *alert.Digest, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeDigestRows(w, d) }); err != nil {
+		return err
+	}
+	if err := exportCitations(cfg, d.Articles); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, d)
+	}
+	if cfg.Human {
+		return formatDigestHuman(humanWriter(w), d)
+	}
+	return formatDigestPlain(w, d)
+}
+
+// digestGroup is one journal's share of a digest.
+type digestGroup struct {
+	Journal  string
+	Articles []eutils.Article
+}
+
+// digestGroups groups a digest's records by journal, largest group first and
+// otherwise alphabetically, keeping search order within each journal.
+// Records whose details could not be fetched carry only their PMID.
+func digestGroups(d *alert.Digest) []digestGroup {
+	byPMID := make(map[string]eutils.Article, len(d.Articles))
+	for _, a := range d.Articles {
+		byPMID[a.PMID] = a
+	}
+	index := make(map[string]int)
+	var groups []digestGroup
+	for _, id := range d.PMIDs {
+		a, ok := byPMID[id]
+		if !ok {
+			a = eutils.Article{PMID: id}
+		}
+		i, ok := index[a.Journal]
+		if !ok {
+			i = len(groups)
+			index[a.Journal] = i
+			groups = append(groups, digestGroup{Journal: a.Journal})
+		}
+		groups[i].Articles = append(groups[i].Articles, a)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Articles) != len(groups[j].Articles) {
+			return len(groups[i].Articles) > len(groups[j].Articles)
+		}
+		return groups[i].Journal < groups[j].Journal
+	})
+	return groups
+}
+
+// digestJournal names a digest group, including the one for records whose
+// journal is unknown.
+func digestJournal(g digestGroup) string {
+	if g.Journal == "" {
+		return "Unknown journal"
+	}
+	return g.Journal
+}
+
+// byline is an article's first author, followed by "et al." when there are
+// more.
+func byline(a eutils.Article) string {
+	if len(a.Authors) == 0 {
+		return ""
+	}
+	first := a.Authors[0]
+	name := first.CollectiveName
+	if first.LastName != "" {
+		name = strings.TrimSpace(first.LastName + " " + first.Initials)
+	}
+	if len(a.Authors) > 1 {
+		name += " et al."
+	}
+	return name
+}
+
+// alertSummary is the JSON form of a saved alert in listings.
+type alertSummary struct {
+	Name    string     `json:"name"`
+	Query   string     `json:"query"`
+	Created time.Time  `json:"created"`
+	LastRun *time.Time `json:"last_run,omitempty"`
+	Seen    int        `json:"seen"`
+}
+
+// FormatAlerts lists saved alerts.
+func FormatAlerts(w io.Writer, alerts []*alert.Alert, cfg OutputConfig) error {
+	if cfg.JSON {
+		summaries := make([]alertSummary, len(alerts))
+		for i, a := range alerts {
+			summaries[i] = alertSummary{Name: a.Name, Query: a.Query, Created: a.Created, Seen: len(a.Seen)}
+			if !a.LastRun.IsZero() {
+				lastRun := a.LastRun
+				summaries[i].LastRun = &lastRun
+			}
+		}
+		return writeJSON(w, summaries)
+	}
+	if len(alerts) == 0 {
+		fmt.Fprintln(w, "No saved alerts. Add one with: pubmed alert add <name> <query>")
+		return nil
+	}
+	if cfg.Human {
+		w = humanWriter(w)
+	}
+	for _, a := range alerts {
+		lastRun := "never run"
+		if !a.LastRun.IsZero() {
+			lastRun = "last run " + a.LastRun.Local().Format("2006-01-02 15:04")
+		}
+		if cfg.Human {
+			fmt.Fprintf(w, "🔔 %s  %s\n   %s\n", bold.Render(a.Name), cyan.Render(a.Query),
+				dim.Render(fmt.Sprintf("%s · %d seen", lastRun, len(a.Seen))))
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s, %d seen\n", a.Name, a.Query, lastRun, len(a.Seen))
+	}
+	return nil
+}
+
+// FormatDedupeResult exports the merged records of a deduplication to the
+// files in cfg and writes a summary of the duplicates that were removed.
+func FormatDedupeResult(w io.Writer, res dedupe.Result, cfg OutputConfig) error {
+	articles := make([]eutils.Article, len(res.Records))
+	for i, r := range res.Records {
+		articles[i] = r.Article
+	}
+	if err := exportTables(cfg, func(w tableWriter) { writeArticlesRows(w, articles, cfg.Columns) }); err != nil {
+		return err
+	}
+	if err := exportCitations(cfg, articles); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, res)
+	}
+	if cfg.Human {
+		return formatDedupeHuman(humanWriter(w), res)
+	}
+	return formatDedupePlain(w, res)
+}
+
+// FormatScreeningLog prints screening decisions with a tally; exports
+// carry the log rows (tables) or the screened articles (citations).
+func FormatScreeningLog(w io.Writer, items []*screen.Item, cfg OutputConfig) error {
+	articles := make([]eutils.Article, len(items))
+	for i, it := range items {
+		articles[i] = it.Article
+	}
+	if err := exportTables(cfg, func(w tableWriter) { writeScreeningRows(w, items) }); err != nil {
+		return err
+	}
+	if err := exportCitations(cfg, articles); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, items)
+	}
+	if cfg.Human {
+		return formatScreeningHuman(humanWriter(w), items)
+	}
+	return formatScreeningPlain(w, items)
+}
+
+// WriteDuplicatesReport writes one CSV row per removed duplicate.
+func WriteDuplicatesReport(path string, res dedupe.Result) error {
+	err := exportTable(path, func(w io.Writer) tableWriter { return csv.NewWriter(w) }, func(w tableWriter) {
+		writeDuplicatesRows(w, res.Duplicates)
+	})
+	if err != nil {
+		return fmt.Errorf("duplicates report failed: %w", err)
+	}
+	return nil
+}
+
+// FormatCitationGraph summarizes a citation graph written to path (empty
+// when the graph itself is not saved), or prints the graph as JSON.
+func FormatCitationGraph(w io.Writer, g *graph.Graph, path string, cfg OutputConfig) error {
+	if cfg.JSON {
+		return writeJSON(w, g)
+	}
+	if cfg.Human {
+		return formatCitationGraphHuman(humanWriter(w), g, path)
+	}
+	return formatCitationGraphPlain(w, g, path)
+}
+
+// FormatNetwork summarizes a network and its most central members, noting
+// where the network was written (empty when it was not saved), or prints
+// the whole network as JSON.
+func FormatNetwork(w io.Writer, n *network.Network, path string, top int, cfg OutputConfig) error {
+	if err := exportTables(cfg, func(w tableWriter) { writeNetworkRows(w, n) }); err != nil {
+		return err
+	}
+	if cfg.JSON {
+		return writeJSON(w, n)
+	}
+	if cfg.Human {
+		return formatNetworkHuman(humanWriter(w), n, path, top)
+	}
+	return formatNetworkPlain(w, n, path, top)
+}
+
+// FormatTimeline renders a timeline as Markdown or HTML, or prints its data
+// as JSON.
+func FormatTimeline(w io.Writer, t *timeline.Timeline, format string, cfg OutputConfig) error {
+	if cfg.JSON {
+		return writeJSON(w, t)
+	}
+	return timeline.Write(w, t, format)
+}
+
+// --- Plain text formatters (default) ---
+
+func formatSearchPlain(w io.Writer, result *eutils.SearchResult) error {
+	if result.Count == 0 {
+		fmt.Fprintln(w, "No results found.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Found %d results", result.Count)
+	if len(result.IDs) < result.Count {
+		fmt.Fprintf(w, " (showing %d)", len(result.IDs))
+	}
+	fmt.Fprintln(w)
+
+	if result.QueryTranslation != "" {
+		fmt.Fprintf(w, "Query: %s\n", result.QueryTranslation)
+	}
+	fmt.Fprintln(w)
+
+	for i, id := range result.IDs {
+		fmt.Fprintf(w, "  %d. PMID: %s\n", i+1, id)
+	}
+
+	return nil
+}
+
+func formatArticlesPlain(w io.Writer, articles []eutils.Article) error {
+	if len(articles) == 0 {
+		fmt.Fprintln(w, "No articles found.")
+		return nil
+	}
+
+	for i, a := range articles {
+		if i > 0 {
+			fmt.Fprintf(w, "\n%s\n\n", strings.Repeat("─", 80))
+		}
+
+		if a.PMID == "" && a.Source != "" {
+			fmt.Fprintf(w, "Source: %s (not in PubMed)\n", a.Source)
+		} else {
+			fmt.Fprintf(w, "PMID: %s\n", a.PMID)
+		}
+		fmt.Fprintf(w, "Title: %s\n", a.Title)
+
+		if len(a.Authors) > 0 {
+			names := make([]string, len(a.Authors))
+			for j, au := range a.Authors {
+				names[j] = au.FullName()
+			}
+			fmt.Fprintf(w, "Authors: %s\n", strings.Join(names, ", "))
+		}
+
+		if a.IsBook() {
+			fmt.Fprintf(w, "Book: %s\n", bookCitation(a))
+		} else {
+			citation := a.Journal
+			if a.Volume != "" {
+				citation += " " + a.Volume
+				if a.Issue != "" {
+					citation += "(" + a.Issue + ")"
+				}
+			}
+			if a.Pages != "" {
+				citation += ":" + a.Pages
+			}
+			if a.Year != "" {
+				citation += " (" + a.Year + ")"
+			}
+			fmt.Fprintf(w, "Journal: %s\n", citation)
+		}
+
+		if a.DOI != "" {
+			fmt.Fprintf(w, "DOI: %s\n", a.DOI)
+		}
+		if a.PMCID != "" {
+			fmt.Fprintf(w, "PMCID: %s\n", a.PMCID)
+		}
+		if len(a.PublicationTypes) > 0 {
+			fmt.Fprintf(w, "Type: %s\n", strings.Join(a.PublicationTypes, ", "))
+		}
+		if a.CitationCount > 0 {
+			fmt.Fprintf(w, "Cited by: %d\n", a.CitationCount)
+		}
+		if a.InfluentialCitationCount > 0 {
+			fmt.Fprintf(w, "Influential citations: %d\n", a.InfluentialCitationCount)
+		}
+		if icite := iciteMetrics(a); icite != "" {
+			fmt.Fprintf(w, "iCite: %s\n", icite)
+		}
+		if len(a.Concepts) > 0 {
+			fmt.Fprintf(w, "Concepts: %s\n", strings.Join(a.Concepts, ", "))
+		}
+		if len(a.Institutions) > 0 {
+			fmt.Fprintf(w, "Institutions: %s\n", strings.Join(a.Institutions, "; "))
+		}
+		if a.OAStatus != "" {
+			fmt.Fprintf(w, "Open access: %s\n", strings.TrimSpace(a.OAStatus+" "+a.OAURL))
+		}
+		if a.License != "" {
+			fmt.Fprintf(w, "License: %s\n", a.License)
+		}
+		if a.ReferenceCount > 0 {
+			fmt.Fprintf(w, "References: %d\n", a.ReferenceCount)
+		}
+		if len(a.CrossrefFields) > 0 {
+			fmt.Fprintf(w, "From Crossref: %s\n", strings.Join(a.CrossrefFields, ", "))
+		}
+		if len(a.Tags) > 0 {
+			fmt.Fprintf(w, "Tags: %s\n", strings.Join(a.Tags, ", "))
+		}
+		for _, n := range a.Notes {
+			fmt.Fprintf(w, "Note: %s\n", n)
+		}
+		if a.TLDR != "" {
+			fmt.Fprintf(w, "TLDR: %s\n", a.TLDR)
+		}
+		if a.Abstract != "" {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Abstract:")
+			fmt.Fprintln(w, a.Abstract)
+		}
+		if len(a.MeSHTerms) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "MeSH Terms:")
+			for _, m := range a.MeSHTerms {
+				marker := "  "
+				if m.MajorTopic {
+					marker = "* "
+				}
+				term := m.Descriptor
+				if len(m.Qualifiers) > 0 {
+					term += " / " + strings.Join(m.Qualifiers, ", ")
+				}
+				fmt.Fprintf(w, "  %s%s\n", marker, term)
+			}
+		}
+	}
+
+	return nil
+}
+
+// bookCitation is a Bookshelf record's source for article cards:
+// "GeneReviews® (University of Washington, Seattle, 1993)", or just the
+// publisher and year for a whole book.
+func bookCitation(a eutils.Article) string {
+	var details []string
+	for _, d := range []string{a.Publisher, a.Year} {
+		if d != "" {
+			details = append(details, d)
+		}
+	}
+	s := a.BookTitle
+	if len(details) > 0 {
+		s = strings.TrimSpace(s + " (" + strings.Join(details, ", ") + ")")
+	}
+	if a.CollectionTitle != "" {
+		s += "; " + a.CollectionTitle
+	}
+	return s
+}
+
+func formatLinksPlain(w io.Writer, result *eutils.LinkResult, linkType string) error {
+	if len(result.Links) == 0 {
+		fmt.Fprintf(w, "No %s results for PMID %s.\n", linkType, result.SourceID)
+		return nil
+	}
+
+	title := linkType
+	switch linkType {
+	case "cited-by":
+		title = "Cited By"
+	case "references":
+		title = "References"
+	case "related":
+		title = "Related Articles"
+	}
+
+	fmt.Fprintf(w, "%s for PMID %s (%d results):\n\n", title, result.SourceID, len(result.Links))
+
+	for i, link := range result.Links {
+		if link.Score > 0 {
+			fmt.Fprintf(w, "  %d. PMID: %s (score: %d)\n", i+1, link.ID, link.Score)
+		} else {
+			fmt.Fprintf(w, "  %d. PMID: %s\n", i+1, link.ID)
+		}
+	}
+
+	return nil
+}
+
+func formatMeSHPlain(w io.Writer, record *mesh.MeSHRecord) error {
+	fmt.Fprintf(w, "MeSH Term: %s\n", record.Name)
+	fmt.Fprintf(w, "UI: %s\n", record.UI)
+	if record.IsSupplementary() {
+		fmt.Fprintln(w, "Type: Supplementary Concept")
+	}
+
+	if len(record.HeadingMappedTo) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Heading Mapped To:")
+		for _, h := range record.HeadingMappedTo {
+			fmt.Fprintf(w, "  %s\n", h)
+		}
+	}
+
+	if len(record.TreeNumbers) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Tree Numbers:")
+		for _, tn := range record.TreeNumbers {
+			fmt.Fprintf(w, "  %s\n", tn)
+		}
+	}
+
+	if record.ScopeNote != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Scope Note:")
+		fmt.Fprintf(w, "  %s\n", record.ScopeNote)
+	}
+
+	if len(record.EntryTerms) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Entry Terms (synonyms):")
+		for _, et := range record.EntryTerms {
+			fmt.Fprintf(w, "  - %s\n", et)
+		}
+	}
+
+	if len(record.PharmacologicalActions) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Pharmacological Actions:")
+		for _, pa := range record.PharmacologicalActions {
+			fmt.Fprintf(w, "  - %s\n", pa)
+		}
+	}
+
+	if record.Annotation != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Annotation: %s\n", record.Annotation)
+	}
+
+	return nil
+}
+
+func formatMeSHTreePlain(w io.Writer, h *mesh.Hierarchy) error {
+	fmt.Fprintf(w, "MeSH Term: %s\n", h.Record.Name)
+	fmt.Fprintf(w, "UI: %s\n", h.Record.UI)
+
+	if len(h.Paths) == 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No tree positions.")
+		return nil
+	}
+
+	for _, p := range h.Paths {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Tree %s:\n", p.TreeNumber)
+		depth := 0
+		for _, a := range p.Ancestors {
+			fmt.Fprintf(w, "%s%s [%s]\n", strings.Repeat("  ", depth+1), a.Name, a.TreeNumber)
+			depth++
+		}
+		fmt.Fprintf(w, "%s* %s [%s]\n", strings.Repeat("  ", depth+1), h.Record.Name, p.TreeNumber)
+		writeTreeNodesPlain(w, p.Children, depth+2)
+	}
+
+	return nil
+}
+
+func writeTreeNodesPlain(w io.Writer, nodes []mesh.TreeNode, depth int) {
+	for _, c := range nodes {
+		fmt.Fprintf(w, "%s- %s [%s]%s\n", strings.Repeat("  ", depth), c.Name, c.TreeNumber, childCountSuffix(c))
+		writeTreeNodesPlain(w, c.Children, depth+1)
+	}
+}
+
+// childCountSuffix notes children that are not shown beneath a node.
+func childCountSuffix(n mesh.TreeNode) string {
+	if n.ChildCount == 0 || len(n.Children) > 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d)", n.ChildCount)
+}
+
+// FormatMeSHTreeMarkdown writes a descriptor's tree positions as nested
+// Markdown lists, one section per tree number.
+func FormatMeSHTreeMarkdown(w io.Writer, h *mesh.Hierarchy) error {
+	fmt.Fprintf(w, "# %s (%s)\n", h.Record.Name, h.Record.UI)
+	for _, p := range h.Paths {
+		fmt.Fprintf(w, "\n## %s\n\n", p.TreeNumber)
+		depth := 0
+		for _, a := range p.Ancestors {
+			fmt.Fprintf(w, "%s- %s `%s`\n", strings.Repeat("  ", depth), a.Name, a.TreeNumber)
+			depth++
+		}
+		fmt.Fprintf(w, "%s- **%s** `%s`\n", strings.Repeat("  ", depth), h.Record.Name, p.TreeNumber)
+		writeTreeNodesMarkdown(w, p.Children, depth+1)
+	}
+	return nil
+}
+
+func writeTreeNodesMarkdown(w io.Writer, nodes []mesh.TreeNode, depth int) {
+	for _, c := range nodes {
+		fmt.Fprintf(w, "%s- %s `%s`%s\n", strings.Repeat("  ", depth), c.Name, c.TreeNumber, childCountSuffix(c))
+		writeTreeNodesMarkdown(w, c.Children, depth+1)
+	}
+}
+
+func formatMeSHSuggestionsPlain(w io.Writer, prefix string, suggestions []mesh.Suggestion) error {
+	if len(suggestions) == 0 {
+		fmt.Fprintf(w, "No MeSH terms match %q.\n", prefix)
+		return nil
+	}
+	for _, s := range suggestions {
+		if s.Matched != "" && s.Matched != s.Name {
+			fmt.Fprintf(w, "%s\t%s (via %q)\n", s.UI, s.Name, s.Matched)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", s.UI, s.Name)
+		}
+	}
+	return nil
+}
+
+func formatMeSHQualifiersPlain(w io.Writer, record *mesh.MeSHRecord, qualifiers []mesh.Qualifier) error {
+	if record != nil {
+		fmt.Fprintf(w, "Qualifiers allowed with %s (%s):\n", record.Name, record.UI)
+		if len(record.AllowableQualifiers) == 0 {
+			fmt.Fprintln(w, "  (NCBI did not report allowable qualifiers; showing all)")
+		}
+	}
+	for _, q := range qualifiers {
+		fmt.Fprintf(w, "%s\t%s\n", q.Abbreviation, q.Name)
+	}
+	return nil
+}
+
+func formatMeSHRecordsPlain(w io.Writer, title string, records []mesh.MeSHRecord) error {
+	fmt.Fprintf(w, "%s (%d):\n", title, len(records))
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\n", r.UI, r.Name)
+	}
+	return nil
+}
+
+func formatConceptMappingsPlain(w io.Writer, mappings []query.Mapping) error {
+	if len(mappings) == 0 {
+		fmt.Fprintln(w, "No MeSH concepts found.")
+		return nil
+	}
+	for _, m := range mappings {
+		if m.UI == "" {
+			fmt.Fprintf(w, "%q\t(no MeSH mapping)\n", m.Phrase)
+			continue
+		}
+		fmt.Fprintf(w, "%q\t%s\t%s\t%.2f\t%s\n", m.Phrase, m.UI, m.Descriptor, m.Confidence, m.Via)
+	}
+	return nil
+}
+
+func formatMeSHFrequencyPlain(w io.Writer, report *analyze.MeSHReport) error {
+	kind := "MeSH headings"
+	if report.MajorOnly {
+		kind = "Major-topic MeSH headings"
+	}
+	fmt.Fprintf(w, "%s across %d articles (%d indexed):\n\n", kind, report.Articles, report.Indexed)
+	if len(report.Terms) == 0 {
+		fmt.Fprintln(w, "  No MeSH headings found.")
+		return nil
+	}
+	for i, t := range report.Terms {
+		fmt.Fprintf(w, "  %2d. %-40s %5d  %5.1f%%\n", i+1, t.Term, t.Count, t.Percent)
+	}
+	return nil
+}
+
+func formatAffiliationsPlain(w io.Writer, report *analyze.AffiliationReport) error {
+	fmt.Fprintf(w, "Affiliations of %d articles (%d give affiliations):\n", report.Articles, report.Affiliated)
+	if len(report.Countries) == 0 && len(report.Institutions) == 0 {
+		fmt.Fprintln(w, "\n  No affiliations found.")
+		return nil
+	}
+	fmt.Fprintln(w, "\nCountries:")
+	for i, c := range report.Countries {
+		fmt.Fprintf(w, "  %2d. %-30s %5d  %5.1f%%\n", i+1, c.Name, c.Articles, c.Percent)
+	}
+	fmt.Fprintln(w, "\nInstitutions:")
+	for i, c := range report.Institutions {
+		name := c.Name
+		if c.Country != "" {
+			name += " (" + c.Country + ")"
+		}
+		fmt.Fprintf(w, "  %2d. %-60s %5d  %5.1f%%\n", i+1, name, c.Articles, c.Percent)
+	}
+	return nil
+}
+
+func formatGrantsPlain(w io.Writer, report *analyze.GrantReport) error {
+	fmt.Fprintf(w, "Funding agencies across %d articles (%d list grants):\n\n", report.Articles, report.Funded)
+	if len(report.Agencies) == 0 {
+		fmt.Fprintln(w, "  No grants found.")
+		return nil
+	}
+	for i, a := range report.Agencies {
+		fmt.Fprintf(w, "  %2d. %-40s %5d  %5.1f%%\n", i+1, a.Agency, a.Articles, a.Percent)
+		if len(a.GrantIDs) > 0 {
+			fmt.Fprintf(w, "      %s\n", strings.Join(a.GrantIDs, ", "))
+		}
+	}
+	return nil
+}
+
+func formatKeywordsPlain(w io.Writer, report *analyze.KeywordReport) error {
+	fmt.Fprintf(w, "Keywords across %d articles (TF-IDF):\n\n", report.Articles)
+	if len(report.Keywords) == 0 {
+		fmt.Fprintln(w, "  No keywords found.")
+		return nil
+	}
+	for i, k := range report.Keywords {
+		fmt.Fprintf(w, "  %2d. %-30s %7.3f  %5d articles\n", i+1, k.Term, k.Score, k.Articles)
+	}
+	if len(report.Bigrams) > 0 {
+		fmt.Fprintf(w, "\nFrequent bigrams:\n\n")
+		for i, k := range report.Bigrams {
+			fmt.Fprintf(w, "  %2d. %-30s %5d uses  %5d articles\n", i+1, k.Term, int(k.Score), k.Articles)
+		}
+	}
+	if len(report.AuthorKeywords) > 0 {
+		fmt.Fprintf(w, "\nAuthor keywords:\n\n")
+		for i, t := range report.AuthorKeywords {
+			fmt.Fprintf(w, "  %2d. %-30s %5d  %5.1f%%\n", i+1, t.Term, t.Count, t.Percent)
+		}
+	}
+	if len(report.Suggestions) > 0 {
+		fmt.Fprintf(w, "\nSuggested query terms: %s\n", strings.Join(report.Suggestions, ", "))
+	}
+	return nil
+}
+
+func formatAbbreviationsPlain(w io.Writer, report *analyze.AbbreviationReport) error {
+	fmt.Fprintf(w, "Abbreviations defined in %d articles:\n\n", report.Articles)
+	if len(report.Abbreviations) == 0 {
+		fmt.Fprintln(w, "  No abbreviations found.")
+		return nil
+	}
+	for _, a := range report.Abbreviations {
+		fmt.Fprintf(w, "  %-12s %s  (%d articles)\n", a.Short, a.Long, a.Articles)
+		if len(a.Variants) > 0 {
+			fmt.Fprintf(w, "  %-12s also: %s\n", "", strings.Join(a.Variants, "; "))
+		}
+	}
+	return nil
+}
+
+func formatTrendsPlain(w io.Writer, trends []analyze.Trend) error {
+	for i, t := range trends {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d total):\n", t.Query, t.Total)
+		for _, c := range t.Counts {
+			fmt.Fprintf(w, "  %d  %7d\n", c.Year, c.Count)
+		}
+	}
+	return nil
+}
+
+func formatAlertResultsPlain(w io.Writer, results []alert.Result) error {
+	for _, r := range results {
+		if len(r.New) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d new for %q\n", r.Name, len(r.New), r.Query)
+		titles := alertTitles(r)
+		for _, id := range r.New {
+			fmt.Fprintf(w, "  %s  %s\n", id, titles[id])
+		}
+		if r.Truncated {
+			fmt.Fprintln(w, "  (more results than --limit; rerun with a higher --limit for the rest)")
+		}
+	}
+	return nil
+}
+
+func formatDigestPlain(w io.Writer, d *alert.Digest) error {
+	fmt.Fprintf(w, "%s: %d added to PubMed since %s for %q\n", d.Topic, d.Count, d.Since, d.Query)
+	for _, g := range digestGroups(d) {
+		fmt.Fprintf(w, "\n%s (%d)\n", digestJournal(g), len(g.Articles))
+		for _, a := range g.Articles {
+			line := "  " + a.PMID + "  " + a.Title
+			if by := byline(a); by != "" {
+				line += " (" + by + ")"
+			}
+			fmt.Fprintln(w, strings.TrimRight(line, " "))
+		}
+	}
+	if d.Truncated {
+		fmt.Fprintf(w, "\n(showing %d of %d; raise --limit for more)\n", len(d.PMIDs), d.Count)
+	}
+	return nil
+}
+
+// alertTitles indexes the titles of a result's fetched articles by PMID.
+func alertTitles(r alert.Result) map[string]string {
+	titles := make(map[string]string, len(r.Articles))
+	for _, a := range r.Articles {
+		titles[a.PMID] = a.Title
+	}
+	return titles
+}
+
+func formatDedupePlain(w io.Writer, res dedupe.Result) error {
+	fmt.Fprintf(w, "%d records in, %d unique, %d duplicates removed%s\n",
+		res.Input, len(res.Records), len(res.Duplicates), dedupeReasons(res))
+	for _, d := range res.Duplicates {
+		fmt.Fprintf(w, "  %s#%d -> %s#%d  %s  %s\n", d.Dropped.Source, d.Dropped.Index,
+			d.Kept.Source, d.Kept.Index, dedupeReason(d), truncate(recordLabel(d.Dropped), 80))
+	}
+	return nil
+}
+
+// dedupeReasons counts duplicates by match reason, e.g. " (2 PMID, 1 title)".
+func dedupeReasons(res dedupe.Result) string {
+	counts := map[string]int{}
+	for _, d := range res.Duplicates {
+		counts[d.Reason]++
+	}
+	var parts []string
+	for _, reason := range []string{dedupe.ByPMID, dedupe.ByDOI, dedupe.ByTitle} {
+		if counts[reason] > 0 {
+			label := strings.ToUpper(reason)
+			if reason == dedupe.ByTitle {
+				label = reason
+			}
+			parts = append(parts, fmt.Sprintf("%d %s", counts[reason], label))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func dedupeReason(d dedupe.Duplicate) string {
+	if d.Reason == dedupe.ByTitle {
+		return fmt.Sprintf("title %.2f", d.Similarity)
+	}
+	return strings.ToUpper(d.Reason)
+}
+
+func formatScreeningPlain(w io.Writer, items []*screen.Item) error {
+	fmt.Fprintln(w, screeningTally(items))
+	for _, it := range items {
+		d := it.Decision
+		if d == "" {
+			d = "-"
+		}
+		fmt.Fprintf(w, "  %-8s %s  %s\n", d, it.Article.PMID, truncate(it.Article.Title, 80))
+	}
+	return nil
+}
+
+// screeningTally summarizes decisions, e.g. "12 articles: 4 include, 6 exclude, 1 maybe, 1 undecided".
+func screeningTally(items []*screen.Item) string {
+	counts := map[string]int{}
+	for _, it := range items {
+		counts[it.Decision]++
+	}
+	noun := "articles"
+	if len(items) == 1 {
+		noun = "article"
+	}
+	parts := []string{}
+	for _, d := range screen.Decisions {
+		if counts[d] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[d], d))
+		}
+	}
+	if counts[""] > 0 {
+		parts = append(parts, fmt.Sprintf("%d undecided", counts[""]))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d %s", len(items), noun)
+	}
+	return fmt.Sprintf("%d %s: %s", len(items), noun, strings.Join(parts, ", "))
+}
+
+func formatHistoryDiffPlain(w io.Writer, d history.Diff) error {
+	fmt.Fprintf(w, "#%d -> #%d: %d -> %d results (%s)\n", d.From.ID, d.To.ID, d.From.Count, d.To.Count, signed(d.To.Count-d.From.Count))
+	if d.From.Query != d.To.Query {
+		fmt.Fprintf(w, "Query:\n  - %s\n  + %s\n", d.From.Query, d.To.Query)
+	}
+	if d.TranslationChanged() {
+		fmt.Fprintf(w, "Translation:\n  - %s\n  + %s\n", d.From.Translation, d.To.Translation)
+	}
+	fmt.Fprintf(w, "Retrieved PMIDs: %d in both, %d added, %d removed\n", d.Common, len(d.Added), len(d.Removed))
+	for _, id := range d.Added {
+		fmt.Fprintf(w, "  + %s\n", id)
+	}
+	for _, id := range d.Removed {
+		fmt.Fprintf(w, "  - %s\n", id)
+	}
+	return nil
+}
+
+func formatAlertDiffPlain(w io.Writer, d alert.Diff) error {
+	titles := articleTitles(d.Articles)
+	from := d.From.Format("2006-01-02 15:04")
+	if d.SeenBaseline {
+		from = "reported PMIDs"
+	}
+	fmt.Fprintf(w, "%s: %s -> %s, %d -> %d results (%s)\n", d.Name, from, d.To.Format("2006-01-02 15:04"),
+		d.FromCount, d.ToCount, signed(d.ToCount-d.FromCount))
+	fmt.Fprintf(w, "%d appeared, %d disappeared, %d changed status\n", len(d.Appeared), len(d.Disappeared), len(d.Changed))
+	for _, id := range d.Appeared {
+		fmt.Fprintf(w, "  + %s %s\n", id, titles[id])
+	}
+	for _, id := range d.Disappeared {
+		fmt.Fprintf(w, "  - %s\n", id)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(w, "  ~ %s %s -> %s %s\n", c.PMID, c.From, c.To, titles[c.PMID])
+	}
+	if d.Truncated {
+		fmt.Fprintln(w, "Note: results were cut off at --limit; some changes may be at the cutoff.")
+	}
+	return nil
+}
+
+// articleTitles maps PMIDs to titles.
+func articleTitles(articles []eutils.Article) map[string]string {
+	titles := make(map[string]string, len(articles))
+	for _, a := range articles {
+		titles[a.PMID] = a.Title
+	}
+	return titles
+}
+
+// signed formats n with an explicit sign, e.g. "+4" or "-2".
+func signed(n int) string {
+	return fmt.Sprintf("%+d", n)
+}
+
+func formatComparisonMarkdown(w io.Writer, profiles []compare.Profile) error {
+	header := []string{""}
+	rule := []string{"---"}
+	for _, p := range profiles {
+		header = append(header, markdownCell(comparisonHeading(p)))
+		rule = append(rule, "---")
+	}
+	fmt.Fprintf(w, "| %s |\n| %s |\n", strings.Join(header, " | "), strings.Join(rule, " | "))
+	for _, f := range compare.Fields {
+		row := []string{"**" + f.Name + "**"}
+		for _, p := range profiles {
+			row = append(row, markdownCell(valueOrMissing(f.Value(p))))
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+	}
+	fmt.Fprintln(w, "\n_Extracted from PubMed indexing and abstracts; verify against the full text._")
+	return nil
+}
+
+// picoFields are the labelled fields of a PICO profile, in display order.
+var picoFields = []struct {
+	name  string
+	value func(p compare.PICO) string
+}{
+	{"Population", func(p compare.PICO) string { return p.Population }},
+	{"Intervention", func(p compare.PICO) string { return p.Intervention }},
+	{"Comparator", func(p compare.PICO) string { return p.Comparator }},
+	{"Outcomes", func(p compare.PICO) string { return p.Outcomes }},
+	{"Design", func(p compare.PICO) string { return p.Design }},
+	{"N", func(p compare.PICO) string {
+		if p.SampleSize == 0 {
+			return ""
+		}
+		return strconv.Itoa(p.SampleSize)
+	}},
+}
+
+func formatPICOPlain(w io.Writer, rows []compare.PICO) error {
+	for i, p := range rows {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		heading := "PMID " + p.PMID
+		if p.Year != "" {
+			heading += " (" + p.Year + ")"
+		}
+		fmt.Fprintf(w, "%s %s\n", heading, p.Title)
+		for _, f := range picoFields {
+			fmt.Fprintf(w, "  %-13s %s\n", f.name+":", valueOrMissing(f.value(p)))
+		}
+	}
+	return nil
+}
+
+func formatTriagePlain(w io.Writer, question string, results []triage.Result) error {
+	fmt.Fprintf(w, "Ranked against: %s\n", question)
+	for _, r := range results {
+		year := ""
+		if r.Year != "" {
+			year = " (" + r.Year + ")"
+		}
+		fmt.Fprintf(w, "\n%2d. [%.2f] PMID %s%s %s\n", r.Rank, r.Relevance, r.PMID, year, r.Title)
+		if r.Justification != "" {
+			fmt.Fprintf(w, "    %s\n", r.Justification)
+		}
+		if len(r.Missing) > 0 {
+			fmt.Fprintf(w, "    Missing: %s\n", strings.Join(r.Missing, ", "))
+		}
+	}
+	return nil
+}
+
+func formatSummariesPlain(w io.Writer, summaries []compare.Summary) error {
+	for i, s := range summaries {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		heading := "PMID " + s.PMID
+		if s.Year != "" {
+			heading += " (" + s.Year + ")"
+		}
+		fmt.Fprintf(w, "%s %s\n", heading, s.Title)
+		if s.Summary == "" {
+			fmt.Fprintln(w, "No abstract to summarize.")
+			continue
+		}
+		fmt.Fprintln(w, s.Summary)
+	}
+	return nil
+}
+
+// comparisonHeading labels a compared article, e.g. "PMID 1 (Lancet 2024)".
+func comparisonHeading(p compare.Profile) string {
+	source := strings.TrimSpace(p.Journal + " " + p.Year)
+	if source == "" {
+		return "PMID " + p.PMID
+	}
+	return "PMID " + p.PMID + " (" + source + ")"
+}
+
+func valueOrMissing(v string) string {
+	if v == "" {
+		return "not reported"
+	}
+	return v
+}
+
+// markdownCell makes v safe inside a Markdown table cell.
+func markdownCell(v string) string {
+	v = strings.Join(strings.Fields(v), " ")
+	return strings.ReplaceAll(v, "|", "\\|")
+}
+
+func formatCacheReportPlain(w io.Writer, r cache.Report) error {
+	fmt.Fprintf(w, "Cache: %s\n", r.Dir)
+	if len(r.Sections) == 0 {
+		fmt.Fprintln(w, "Empty.")
+		return nil
+	}
+	for _, s := range r.Sections {
+		fmt.Fprintf(w, "%s\t%d entries\t%s\n", s.Name, s.Entries, FormatBytes(s.Bytes))
+	}
+	if len(r.Endpoints) > 0 {
+		fmt.Fprintln(w, "\nResponses by endpoint:")
+		for _, e := range r.Endpoints {
+			fmt.Fprintf(w, "%s\t%d entries\t%s\t%s\n", e.Name, e.Entries, FormatBytes(e.Bytes), hitRate(e))
+		}
+	}
+	return nil
+}
+
+func formatBatchSummaryPlain(w io.Writer, s batch.Summary) error {
+	for _, r := range s.Jobs {
+		status := fmt.Sprintf("%d results", r.Count)
+		if r.Out != "" {
+			status += fmt.Sprintf(", %d exported to %s", r.Exported, r.Out)
+		}
+		if !r.OK() {
+			status = "failed: " + r.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, r.Query, status)
+	}
+	fmt.Fprintf(w, "%d jobs, %d failed, %s\n", len(s.Jobs), s.Failed, s.Finished.Sub(s.Started).Round(time.Millisecond))
+	return nil
+}
+
+func formatRetractionReportPlain(w io.Writer, r retraction.Report) error {
+	for _, f := range r.Flagged {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", findingLabel(f), strings.ToUpper(strings.Join(f.Statuses, ", ")), findingCitation(f))
+		for _, n := range f.Notices {
+			fmt.Fprintf(w, "  %s\n", noticeText(n))
+		}
+	}
+	fmt.Fprintln(w, retractionSummary(r))
+	return nil
+}
+
+// findingLabel identifies a flagged article by its input reference, if
+// any, and PMID.
+func findingLabel(f retraction.Finding) string {
+	if f.Reference != "" {
+		return f.Reference + " (PMID " + f.PMID + ")"
+	}
+	return "PMID " + f.PMID
+}
+
+func findingCitation(f retraction.Finding) string {
+	var parts []string
+	if f.Journal != "" {
+		parts = append(parts, f.Journal)
+	}
+	if f.Year != "" {
+		parts = append(parts, f.Year)
+	}
+	if len(parts) == 0 {
+		return f.Title
+	}
+	return f.Title + " (" + strings.Join(parts, " ") + ")"
+}
+
+func noticeText(n retraction.Notice) string {
+	text := n.Label
+	if n.Source != "" {
+		text += ": " + n.Source
+	}
+	if n.PMID != "" {
+		text += " (PMID " + n.PMID + ")"
+	}
+	return text
+}
+
+func retractionSummary(r retraction.Report) string {
+	s := fmt.Sprintf("%d of %d references flagged", len(r.Flagged), r.Checked)
+	if len(r.Unmatched) > 0 {
+		s += fmt.Sprintf(", %d not found in PubMed", len(r.Unmatched))
+	}
+	return s
+}
+
+// iciteMetrics summarizes a's iCite scores, e.g. "RCR 1.96, NIH
+// percentile 72.4, APT 0.75", or returns "" when it has none.
+func iciteMetrics(a eutils.Article) string {
+	var parts []string
+	if a.RCR != nil {
+		parts = append(parts, "RCR "+metric(a.RCR))
+	}
+	if a.NIHPercentile != nil {
+		parts = append(parts, "NIH percentile "+metric(a.NIHPercentile))
+	}
+	if a.APT != nil {
+		parts = append(parts, "APT "+metric(a.APT))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatStanceReportPlain(w io.Writer, r stance.Report) error {
+	for _, c := range r.Citations {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.PMID, strings.ToUpper(c.Stance), citingCitation(c))
+		for _, s := range c.Contexts {
+			fmt.Fprintf(w, "  > %s\n", s)
+		}
+	}
+	fmt.Fprintln(w, stanceSummary(r))
+	return nil
+}
+
+func citingCitation(c stance.Citation) string {
+	var parts []string
+	if c.Journal != "" {
+		parts = append(parts, c.Journal)
+	}
+	if c.Year != "" {
+		parts = append(parts, c.Year)
+	}
+	if len(parts) == 0 {
+		return c.Title
+	}
+	return c.Title + " (" + strings.Join(parts, " ") + ")"
+}
+
+// stanceSummary totals the stances, e.g. "20 of 132 citing articles: 3
+// supporting, 1 contrasting, 9 mentioning, 7 unknown (no PMC full text)".
+func stanceSummary(r stance.Report) string {
+	var counts []string
+	for _, st := range stance.Stances {
+		if n := r.Counts[st]; n > 0 {
+			label := st
+			if st == stance.Unknown {
+				label += " (no PMC full text)"
+			}
+			counts = append(counts, fmt.Sprintf("%d %s", n, label))
+		}
+	}
+	s := fmt.Sprintf("%d of %d citing articles of PMID %s", len(r.Citations), r.Total, r.PMID)
+	if len(counts) > 0 {
+		s += ": " + strings.Join(counts, ", ")
+	}
+	return s
+}
+
+func formatZoteroReportPlain(w io.Writer, r *zotero.Report) error {
+	for _, f := range r.Failed {
+		fmt.Fprintf(w, "FAILED\t%s\t%s\n", failureLabel(f), f.Message)
+	}
+	fmt.Fprintln(w, zoteroSummary(r))
+	return nil
+}
+
+// failureLabel identifies a rejected article by PMID, or by DOI when it
+// has none.
+func failureLabel(f zotero.Failure) string {
+	switch {
+	case f.PMID != "":
+		return "PMID " + f.PMID
+	case f.DOI != "":
+		return "DOI " + f.DOI
+	}
+	return "-"
+}
+
+func zoteroSummary(r *zotero.Report) string {
+	s := fmt.Sprintf("Pushed %d items", len(r.Items))
+	if r.Collection != "" {
+		s += fmt.Sprintf(" to collection %q", r.Collection)
+	}
+	s += fmt.Sprintf(" in %s (%d attachments, %d notes)", r.Library, r.Attachments, r.Notes)
+	if len(r.Failed) > 0 {
+		s += fmt.Sprintf(", %d failed", len(r.Failed))
+	}
+	return s
+}
+
+func formatFullTextPlain(w io.Writer, results []fulltext.Result) error {
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "PMID %s: %s\n", r.PMID, fullTextSummary(r))
+		if r.Found() {
+			fmt.Fprintf(w, "License: %s\n", licenseText(r))
+		}
+		if r.Document != nil && r.Path == "" {
+			fmt.Fprintf(w, "\n%s", r.Document.Text())
+		}
+	}
+	return nil
+}
+
+// fullTextSummary says where an article's full text was found.
+func fullTextSummary(r fulltext.Result) string {
+	switch {
+	case !r.Found():
+		return "no open-access full text found"
+	case r.Path != "":
+		return fmt.Sprintf("%s %s saved to %s", fullTextSource(r), strings.ToUpper(r.Format), r.Path)
+	default:
+		return fmt.Sprintf("%s %s at %s", fullTextSource(r), strings.ToUpper(r.Format), r.URL)
+	}
+}
+
+func fullTextSource(r fulltext.Result) string {
+	if r.Source == fulltext.SourcePMC {
+		return r.PMCID
+	}
+	if r.Source == fulltext.SourceResolver {
+		return "Institutional resolver"
+	}
+	if r.HostType == fulltext.HostRepository {
+		return "Unpaywall repository"
+	}
+	return "Unpaywall"
+}
+
+// licenseText reports a license and its URL, or that none was given.
+func licenseText(r fulltext.Result) string {
+	switch {
+	case r.License != "" && r.LicenseURL != "":
+		return r.License + " (" + r.LicenseURL + ")"
+	case r.License != "":
+		return r.License
+	case r.LicenseURL != "":
+		return r.LicenseURL
+	}
+	return "not stated; check the publisher's terms before reuse"
+}
+
+// hitRate describes an endpoint's recorded lookups.
+func hitRate(u cache.Usage) string {
+	if u.Hits+u.Misses == 0 {
+		return "no lookups recorded"
+	}
+	return fmt.Sprintf("%.0f%% hits (%d of %d)", 100*u.HitRate(), u.Hits, u.Hits+u.Misses)
+}
+
+// FormatBytes renders n bytes with a binary unit, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatJournalsPlain(w io.Writer, journals []journal.Journal) error {
+	if len(journals) == 0 {
+		fmt.Fprintln(w, "No journals found.")
+		return nil
+	}
+	for i, j := range journals {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (NLM ID %s)\n", j.Title, j.NLMID)
+		if j.MedlineTA != "" {
+			fmt.Fprintf(w, "  Abbreviation: %s\n", j.MedlineTA)
+		}
+		if j.ISOAbbrev != "" && j.ISOAbbrev != j.MedlineTA {
+			fmt.Fprintf(w, "  ISO abbreviation: %s\n", j.ISOAbbrev)
+		}
+		if len(j.ISSNs) > 0 {
+			fmt.Fprintf(w, "  ISSN: %s\n", journalISSNs(j))
+		}
+		if j.Publisher != "" {
+			fmt.Fprintf(w, "  Publisher: %s\n", j.Publisher)
+		}
+		fmt.Fprintf(w, "  MEDLINE: %s\n", indexingStatus(j))
+		if len(j.Counts) > 0 {
+			counts := make([]string, len(j.Counts))
+			for k, c := range j.Counts {
+				counts[k] = fmt.Sprintf("%d: %d", c.Year, c.Count)
+			}
+			fmt.Fprintf(w, "  PubMed articles: %s\n", strings.Join(counts, ", "))
+		}
+	}
+	return nil
+}
+
+// journalISSNs lists a journal's ISSNs with their media, e.g. "1866-1947 (Print)".
+func journalISSNs(j journal.Journal) string {
+	parts := make([]string, len(j.ISSNs))
+	for i, is := range j.ISSNs {
+		parts[i] = is.ISSN
+		if is.Type != "" {
+			parts[i] += " (" + is.Type + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func indexingStatus(j journal.Journal) string {
+	if j.CurrentlyIndexed {
+		return "currently indexed"
+	}
+	return "not currently indexed"
+}
+
+func formatJournalChecksPlain(w io.Writer, checks []journal.Check) error {
+	if len(checks) == 0 {
+		fmt.Fprintln(w, "No journal terms ([ta], [journal], [is]) in the query.")
+		return nil
+	}
+	for _, ch := range checks {
+		switch {
+		case ch.Journal == nil:
+			fmt.Fprintf(w, "NOT FOUND  %q: no NLM Catalog journal matches\n", ch.Term)
+		case ch.Exact:
+			fmt.Fprintf(w, "OK         %q: %s (%s)\n", ch.Term, ch.Journal.Title, indexingStatus(*ch.Journal))
+		default:
+			fmt.Fprintf(w, "INEXACT    %q: closest is %s; use %s\n", ch.Term, ch.Journal.Title, ch.Journal.PubMedClause())
+		}
+	}
+	return nil
+}
+
+func formatLintPlain(w io.Writer, r *query.LintReport) error {
+	if len(r.Problems) == 0 {
+		fmt.Fprintln(w, "No problems found.")
+	}
+	for _, p := range r.Problems {
+		fmt.Fprintf(w, "%-8s %s\n", strings.ToUpper(p.Severity), p.Message)
+		if p.Hint != "" {
+			fmt.Fprintf(w, "         %s\n", p.Hint)
+		}
+	}
+	if r.Translation != "" {
+		fmt.Fprintf(w, "\nQuery:       %s\nTranslation: %s\n", r.Query, r.Translation)
+		for _, d := range r.Diff {
+			if d.Op != "=" {
+				fmt.Fprintf(w, "  %s %s\n", d.Op, d.Text)
+			}
+		}
+	}
+	return nil
+}
+
+// recordLabel names a record by its title, falling back to identifiers.
+func recordLabel(r dedupe.Record) string {
+	switch {
+	case r.Article.Title != "":
+		return r.Article.Title
+	case r.Article.PMID != "":
+		return "PMID " + r.Article.PMID
+	default:
+		return "DOI " + r.Article.DOI
+	}
+}
+
+// exportCitations writes the RIS, BibTeX, and/or CSL-JSON files requested in cfg.
+func exportCitations(cfg OutputConfig, articles []eutils.Article) error {
+	if cfg.RISFile != "" {
+		if err := writeArticlesRIS(cfg.RISFile, articles); err != nil {
+			return fmt.Errorf("RIS export failed: %w", err)
+		}
+	}
+	if cfg.BibTeXFile != "" {
+		if err := writeArticlesBibTeX(cfg.BibTeXFile, articles); err != nil {
+			return fmt.Errorf("BibTeX export failed: %w", err)
+		}
+	}
+	if cfg.CSLFile != "" {
+		if err := writeArticlesCSLJSON(cfg.CSLFile, articles); err != nil {
+			return fmt.Errorf("CSL-JSON export failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func formatCitationGraphPlain(w io.Writer, g *graph.Graph, path string) error {
+	fmt.Fprintf(w, "Citation graph for PMID %s: %d articles, %d citations\n", g.Seed, len(g.Nodes), len(g.Edges))
+	if g.Truncated {
+		fmt.Fprintln(w, "  (truncated at --max-nodes)")
+	}
+	if path != "" {
+		fmt.Fprintf(w, "Written to %s\n", path)
+	}
+	return nil
+}
+
+func formatNetworkPlain(w io.Writer, n *network.Network, path string, top int) error {
+	fmt.Fprintln(w, networkHeading(n))
+	if path != "" {
+		fmt.Fprintf(w, "Written to %s\n", path)
+	}
+	nodes := n.Top(top)
+	if len(nodes) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\nMost central %s:\n", networkNodeNoun(n))
+	for i, node := range nodes {
+		fmt.Fprintf(w, "  %d. %s\t%s\tdegree %d\tbetweenness %.3f\tPageRank %.3f\n",
+			i+1, networkNodeLabel(n, node), networkNodeCount(n, node), node.Degree, node.Betweenness, node.PageRank)
+	}
+	return nil
+}
+
+func networkHeading(n *network.Network) string {
+	if n.Type == network.TypeCoauthor {
+		return fmt.Sprintf("Co-author network: %d authors, %d links from %d articles", len(n.Nodes), len(n.Edges), n.Articles)
+	}
+	return fmt.Sprintf("Citation network: %d articles, %d citations within the set", len(n.Nodes), len(n.Edges))
+}
+
+func networkNodeNoun(n *network.Network) string {
+	if n.Type == network.TypeCoauthor {
+		return "authors"
+	}
+	return "articles"
+}
+
+func networkNodeLabel(n *network.Network, node network.Node) string {
+	if n.Type == network.TypeCoauthor {
+		return node.ID
+	}
+	label := "PMID " + node.ID
+	if node.Year != "" {
+		label += " (" + node.Year + ")"
+	}
+	if node.Label != "" {
+		label += " " + truncate(node.Label, 60)
+	}
+	return label
+}
+
+func networkNodeCount(n *network.Network, node network.Node) string {
+	if n.Type == network.TypeCoauthor {
+		return fmt.Sprintf("%d articles", node.Articles)
+	}
+	return fmt.Sprintf("%d citations", node.Citations)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// writeNDJSON writes each item as one compact JSON object per line, so
+// consumers can stream results without buffering a whole document.
+func writeNDJSON[T any](w io.Writer, items []T) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ndjsonPMID is the per-line record for search results without article details.
+type ndjsonPMID struct {
+	SchemaVersion string `json:"schema_version"`
+	PMID          string `json:"pmid"`
+}
+
+func writeSearchNDJSON(w io.Writer, result *eutils.SearchResult) error {
+	rows := make([]ndjsonPMID, len(result.IDs))
+	for i, id := range result.IDs {
+		rows[i] = ndjsonPMID{SchemaVersion: SchemaVersion, PMID: id}
+	}
+	return writeNDJSON(w, rows)
+}
+
+// ndjsonLink is the per-line record for link results; each line carries
+// its source so streams from several commands can be merged.
+type ndjsonLink struct {
+	SchemaVersion string `json:"schema_version"`
+	SourceID      string `json:"source_id"`
+	ID            string `json:"id"`
+	Score         int    `json:"score,omitempty"`
+}
+
+func writeLinksNDJSON(w io.Writer, result *eutils.LinkResult) error {
+	rows := make([]ndjsonLink, len(result.Links))
+	for i, l := range result.Links {
+		rows[i] = ndjsonLink{SchemaVersion: SchemaVersion, SourceID: result.SourceID, ID: l.ID, Score: l.Score}
+	}
+	return writeNDJSON(w, rows)
 }
@@ -7,8 +7,27 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/batch"
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/compare"
+	"github.com/henrybloomingdale/pubmed-cli/internal/dedupe"
 	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/graph"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/journal"
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+	"github.com/henrybloomingdale/pubmed-cli/internal/network"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/henrybloomingdale/pubmed-cli/internal/retraction"
+	"github.com/henrybloomingdale/pubmed-cli/internal/screen"
+	"github.com/henrybloomingdale/pubmed-cli/internal/stance"
+	"github.com/henrybloomingdale/pubmed-cli/internal/triage"
+	"github.com/henrybloomingdale/pubmed-cli/internal/zotero"
 )
 
 func TestFormatSearchJSON(t *testing.T) {
@@ -119,6 +138,44 @@ func TestFormatArticleJSON(t *testing.T) {
 	}
 }
 
+func TestFormatArticlesNDJSON(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "111", Title: "First"},
+		{PMID: "222", Title: "Second, with \"quotes\""},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatArticles(&buf, articles, OutputConfig{NDJSON: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per article, got %d:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var a eutils.Article
+		if err := json.Unmarshal([]byte(line), &a); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i+1, err)
+		}
+		if a.PMID != articles[i].PMID {
+			t.Errorf("line %d: expected PMID %s, got %s", i+1, articles[i].PMID, a.PMID)
+		}
+	}
+}
+
+func TestFormatSearchNDJSON_PMIDsOnly(t *testing.T) {
+	result := &eutils.SearchResult{Count: 2, IDs: []string{"111", "222"}}
+
+	var buf bytes.Buffer
+	if err := FormatSearchResult(&buf, result, nil, OutputConfig{NDJSON: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := buf.String(); got != "{\"schema_version\":\"1.13\",\"pmid\":\"111\"}\n{\"schema_version\":\"1.13\",\"pmid\":\"222\"}\n" {
+		t.Errorf("unexpected NDJSON:\n%s", got)
+	}
+}
+
 func TestFormatArticlePlain(t *testing.T) {
 	articles := []eutils.Article{
 		{
@@ -270,6 +327,33 @@ func TestFormatLinksJSON(t *testing.T) {
 	}
 }
 
+func TestFormatLinksNDJSON(t *testing.T) {
+	result := &eutils.LinkResult{
+		SourceID: "12345",
+		Links: []eutils.LinkItem{
+			{ID: "111", Score: 99},
+			{ID: "222", Score: 88},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatLinks(&buf, result, "related", OutputConfig{NDJSON: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	var link map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &link); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	if link["source_id"] != "12345" || link["id"] != "222" || link["score"] != float64(88) {
+		t.Errorf("unexpected link object: %v", link)
+	}
+}
+
 func TestFormatLinksPlain(t *testing.T) {
 	result := &eutils.LinkResult{
 		SourceID: "12345",
@@ -311,3 +395,819 @@ func TestFormatLinksEmpty(t *testing.T) {
 		t.Errorf("expected 'no results' message, got %q", out)
 	}
 }
+
+func TestFormatMeSHTreePlain(t *testing.T) {
+	h := &mesh.Hierarchy{
+		Record: &mesh.MeSHRecord{UI: "D019954", Name: "Neurobehavioral Manifestations"},
+		Paths: []mesh.TreePath{{
+			TreeNumber: "C10.597.606",
+			Ancestors: []mesh.TreeNode{
+				{UI: "D009422", Name: "Nervous System Diseases", TreeNumber: "C10"},
+				{UI: "D009461", Name: "Neurologic Manifestations", TreeNumber: "C10.597"},
+			},
+			Children: []mesh.TreeNode{
+				{UI: "D060825", Name: "Cognitive Dysfunction", TreeNumber: "C10.597.606.150"},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatMeSHTree(&buf, h, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"Tree C10.597.606:",
+		"  Nervous System Diseases [C10]",
+		"    Neurologic Manifestations [C10.597]",
+		"      * Neurobehavioral Manifestations [C10.597.606]",
+		"        - Cognitive Dysfunction [C10.597.606.150]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatMeSHTreeMarkdown(t *testing.T) {
+	h := &mesh.Hierarchy{
+		Record: &mesh.MeSHRecord{UI: "D009461", Name: "Neurologic Manifestations"},
+		Paths: []mesh.TreePath{{
+			TreeNumber: "C10.597",
+			Ancestors:  []mesh.TreeNode{{UI: "D009422", Name: "Nervous System Diseases", TreeNumber: "C10"}},
+			Children: []mesh.TreeNode{
+				{Name: "Neurobehavioral Manifestations", TreeNumber: "C10.597.606", ChildCount: 1, Children: []mesh.TreeNode{
+					{Name: "Cognitive Dysfunction", TreeNumber: "C10.597.606.150", ChildCount: 3},
+				}},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatMeSHTreeMarkdown(&buf, h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# Neurologic Manifestations (D009461)\n\n## C10.597\n\n" +
+		"- Nervous System Diseases `C10`\n" +
+		"  - **Neurologic Manifestations** `C10.597`\n" +
+		"    - Neurobehavioral Manifestations `C10.597.606`\n" +
+		"      - Cognitive Dysfunction `C10.597.606.150` (3)\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestFormatMeSHSuggestionsPlain(t *testing.T) {
+	suggestions := []mesh.Suggestion{
+		{UI: "D009203", Name: "Myocardial Infarction", Matched: "Heart Attack"},
+		{UI: "D006331", Name: "Heart Diseases", Matched: "Heart Diseases"},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatMeSHSuggestions(&buf, "heart", suggestions, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"D009203\tMyocardial Infarction (via \"Heart Attack\")\n",
+		"D006331\tHeart Diseases\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := FormatMeSHSuggestions(&buf, "zzz", nil, OutputConfig{JSON: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected empty JSON array, got %q", buf.String())
+	}
+}
+
+func TestFormatMeSHQualifiersPlain(t *testing.T) {
+	record := &mesh.MeSHRecord{UI: "D005600", Name: "Fragile X Syndrome", AllowableQualifiers: []string{"drug therapy"}}
+	qualifiers := []mesh.Qualifier{{Name: "drug therapy", Abbreviation: "DT"}}
+
+	var buf bytes.Buffer
+	if err := FormatMeSHQualifiers(&buf, record, qualifiers, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Qualifiers allowed with Fragile X Syndrome (D005600):\nDT\tdrug therapy\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatConceptMappingsPlain(t *testing.T) {
+	mappings := []query.Mapping{
+		{Phrase: "breast cancer", UI: "D001943", Descriptor: "Breast Neoplasms", Confidence: 0.9, Via: query.ViaEntryTerm},
+		{Phrase: "chemo", Via: query.ViaUnmapped},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatConceptMappings(&buf, mappings, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "\"breast cancer\"\tD001943\tBreast Neoplasms\t0.90\tentry term\n\"chemo\"\t(no MeSH mapping)\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatMeSHFrequency(t *testing.T) {
+	report := &analyze.MeSHReport{
+		Articles:  4,
+		Indexed:   3,
+		MajorOnly: true,
+		Terms: []analyze.TermCount{
+			{Term: "Fragile X Syndrome", UI: "D005600", Count: 3, Percent: 75},
+			{Term: "Autism Spectrum Disorder", UI: "D000067877", Count: 1, Percent: 25},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatMeSHFrequency(&buf, report, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "across 4 articles (3 indexed)") {
+		t.Errorf("expected article counts, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Fragile X Syndrome") || !strings.Contains(out, "75.0%") {
+		t.Errorf("expected term with percentage, got:\n%s", out)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "mesh.csv")
+	if err := FormatMeSHFrequency(&bytes.Buffer{}, report, OutputConfig{CSVFile: csvPath}); err != nil {
+		t.Fatalf("unexpected CSV error: %v", err)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "Term,UI,Count,Percent\nFragile X Syndrome,D005600,3,75.0\n") {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+}
+
+func TestFormatTrends(t *testing.T) {
+	trends := []analyze.Trend{
+		{Query: "autism", Total: 25, Counts: []analyze.YearCount{{Year: 2020, Count: 10}, {Year: 2021, Count: 15}}},
+		{Query: "fxs", Total: 3, Counts: []analyze.YearCount{{Year: 2020, Count: 1}, {Year: 2021, Count: 2}}},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatTrends(&buf, trends, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "autism (25 total):\n  2020       10\n  2021       15\n") {
+		t.Errorf("unexpected plain output:\n%s", buf.String())
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "trends.csv")
+	if err := FormatTrends(&bytes.Buffer{}, trends, OutputConfig{CSVFile: csvPath}); err != nil {
+		t.Fatalf("unexpected CSV error: %v", err)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if string(data) != "Year,autism,fxs\n2020,10,1\n2021,15,2\n" {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+}
+
+func TestFormatAlertResults(t *testing.T) {
+	results := []alert.Result{
+		{Name: "quiet", Query: "nothing"},
+		{Name: "fxs", Query: "fragile x", New: []string{"2", "1"}, Truncated: true,
+			Articles: []eutils.Article{{PMID: "1", Title: "Older"}, {PMID: "2", Title: "Newer"}}},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatAlertResults(&buf, results, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "fxs: 2 new for \"fragile x\"\n  2  Newer\n  1  Older\n  (more results than --limit; rerun with a higher --limit for the rest)\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := FormatAlertResults(&buf, results[:1], OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without new results, got %q", buf.String())
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "alerts.csv")
+	if err := FormatAlertResults(&bytes.Buffer{}, results, OutputConfig{CSVFile: csvPath}); err != nil {
+		t.Fatalf("unexpected CSV error: %v", err)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if string(data) != "Alert,PMID,Title,Journal,Year,DOI\nfxs,2,Newer,,,\nfxs,1,Older,,,\n" {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+}
+
+func TestFormatDigest(t *testing.T) {
+	d := &alert.Digest{
+		Topic: "fxs", Query: "fragile x", Days: 1, Since: "2026/03/09", Count: 4,
+		PMIDs: []string{"4", "3", "2"}, Truncated: true,
+		Articles: []eutils.Article{
+			{PMID: "4", Title: "Solo", Journal: "Brain"},
+			{PMID: "3", Title: "First", Journal: "Neurology", Authors: []eutils.Author{{LastName: "Smith", Initials: "J"}, {LastName: "Lee", Initials: "K"}}},
+			{PMID: "2", Title: "Second", Journal: "Neurology", Authors: []eutils.Author{{CollectiveName: "FXS Consortium"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatDigest(&buf, d, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "fxs: 4 added to PubMed since 2026/03/09 for \"fragile x\"\n" +
+		"\nNeurology (2)\n  3  First (Smith J et al.)\n  2  Second (FXS Consortium)\n" +
+		"\nBrain (1)\n  4  Solo\n" +
+		"\n(showing 3 of 4; raise --limit for more)\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "digest.csv")
+	if err := FormatDigest(&bytes.Buffer{}, d, OutputConfig{CSVFile: csvPath}); err != nil {
+		t.Fatalf("unexpected CSV error: %v", err)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "Journal,PMID,Title,First Author,Year,DOI\nNeurology,3,First,Smith J et al.,,\n") {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+}
+
+func TestFormatDedupeResult(t *testing.T) {
+	kept := dedupe.Record{Source: "a.ris", Index: 1, Article: eutils.Article{PMID: "1", Title: "Kept"}}
+	res := dedupe.Result{
+		Input:   3,
+		Records: []dedupe.Record{kept},
+		Duplicates: []dedupe.Duplicate{
+			{Kept: kept, Dropped: dedupe.Record{Source: "b.csv", Index: 4, Article: eutils.Article{PMID: "1"}}, Reason: dedupe.ByPMID},
+			{Kept: kept, Dropped: dedupe.Record{Source: "b.csv", Index: 7, Article: eutils.Article{Title: "Kept."}}, Reason: dedupe.ByTitle, Similarity: 0.95},
+		},
+	}
+
+	dir := t.TempDir()
+	risPath := filepath.Join(dir, "merged.ris")
+	var buf bytes.Buffer
+	if err := FormatDedupeResult(&buf, res, OutputConfig{RISFile: risPath}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "3 records in, 1 unique, 2 duplicates removed (1 PMID, 1 title)\n" +
+		"  b.csv#4 -> a.ris#1  PMID  PMID 1\n" +
+		"  b.csv#7 -> a.ris#1  title 0.95  Kept.\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	ris, err := os.ReadFile(risPath)
+	if err != nil {
+		t.Fatalf("reading RIS: %v", err)
+	}
+	if strings.Count(string(ris), "ER  -") != 1 {
+		t.Errorf("expected one merged RIS record:\n%s", ris)
+	}
+
+	reportPath := filepath.Join(dir, "duplicates.csv")
+	if err := WriteDuplicatesReport(reportPath, res); err != nil {
+		t.Fatalf("unexpected report error: %v", err)
+	}
+	report, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	wantReport := "DroppedSource,DroppedIndex,KeptSource,KeptIndex,Reason,Similarity,PMID,DOI,Title\n" +
+		"b.csv,4,a.ris,1,pmid,,1,,\n" +
+		"b.csv,7,a.ris,1,title,0.95,,,Kept.\n"
+	if string(report) != wantReport {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
+
+func TestFormatScreeningLog(t *testing.T) {
+	items := []*screen.Item{
+		{Article: eutils.Article{PMID: "1", Title: "Kept"}, Decision: screen.Include,
+			DecidedAt: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{Article: eutils.Article{PMID: "2", Title: "Pending"}},
+	}
+
+	var buf bytes.Buffer
+	csvPath := filepath.Join(t.TempDir(), "screening.csv")
+	if err := FormatScreeningLog(&buf, items, OutputConfig{CSVFile: csvPath}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "2 articles: 1 include, 1 undecided\n  include  1  Kept\n  -        2  Pending\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if string(data) != "PMID,Decision,DecidedAt,Title,Journal,Year,DOI\n1,include,2026-03-01T09:30:00Z,Kept,,,\n2,,,Pending,,,\n" {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+}
+
+func TestFormatJournals(t *testing.T) {
+	journals := []journal.Journal{{
+		NLMID: "101483832", Title: "Journal of neurodevelopmental disorders", MedlineTA: "J Neurodev Disord",
+		ISOAbbrev: "J Neurodev Disord", ISSNs: []journal.ISSN{{ISSN: "1866-1947", Type: "Print"}, {ISSN: "1866-1955", Type: "Electronic"}},
+		Publisher: "BioMed Central", CurrentlyIndexed: true,
+		Counts: []analyze.YearCount{{Year: 2024, Count: 61}, {Year: 2025, Count: 70}},
+	}}
+
+	var buf bytes.Buffer
+	csvPath := filepath.Join(t.TempDir(), "journals.csv")
+	if err := FormatJournals(&buf, journals, OutputConfig{CSVFile: csvPath}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Journal of neurodevelopmental disorders (NLM ID 101483832)\n" +
+		"  Abbreviation: J Neurodev Disord\n" +
+		"  ISSN: 1866-1947 (Print), 1866-1955 (Electronic)\n" +
+		"  Publisher: BioMed Central\n" +
+		"  MEDLINE: currently indexed\n" +
+		"  PubMed articles: 2024: 61, 2025: 70\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "NLMID,Title,MedlineTA,ISOAbbreviation,ISSN,Publisher,CurrentlyIndexed,2024,2025\n"+
+		"101483832,Journal of neurodevelopmental disorders,J Neurodev Disord,J Neurodev Disord,1866-1947; 1866-1955,BioMed Central,true,61,70\n") {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+}
+
+func TestFormatJournalChecksPlain(t *testing.T) {
+	j := journal.Journal{Title: "Journal of neurodevelopmental disorders", MedlineTA: "J Neurodev Disord", CurrentlyIndexed: true}
+	checks := []journal.Check{
+		{Term: "J Neurodev Disord", Journal: &j, Exact: true},
+		{Term: "J Neurodev", Journal: &j},
+		{Term: "J Nowhere"},
+	}
+	var buf bytes.Buffer
+	if err := FormatJournalChecks(&buf, checks, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "OK         \"J Neurodev Disord\": Journal of neurodevelopmental disorders (currently indexed)\n" +
+		"INEXACT    \"J Neurodev\": closest is Journal of neurodevelopmental disorders; use \"J Neurodev Disord\"[ta]\n" +
+		"NOT FOUND  \"J Nowhere\": no NLM Catalog journal matches\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]int{0, 7, 14, 28}); got != "▁▂▄█" {
+		t.Errorf("sparkline = %q", got)
+	}
+	if got := sparkline([]int{0, 0}); got != "▁▁" {
+		t.Errorf("sparkline of zeros = %q", got)
+	}
+}
+
+func TestFormatCitationGraphPlain(t *testing.T) {
+	g := &graph.Graph{
+		Seed:  "1",
+		Nodes: []graph.Node{{PMID: "1"}, {PMID: "2", Depth: 1}},
+		Edges: []graph.Edge{{From: "2", To: "1"}},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatCitationGraph(&buf, g, "graph.dot", OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "PMID 1: 2 articles, 1 citations") || !strings.Contains(out, "Written to graph.dot") {
+		t.Errorf("unexpected summary:\n%s", out)
+	}
+}
+
+func TestFormatCacheReport(t *testing.T) {
+	r := cache.Report{
+		Dir:       "/tmp/pubmed-cli",
+		Sections:  []cache.Usage{{Name: "mesh", Entries: 2, Bytes: 3 << 20}, {Name: "responses", Entries: 4, Bytes: 2048}},
+		Endpoints: []cache.Usage{{Name: "mesh/esearch.fcgi", Entries: 4, Bytes: 2048, Hits: 3, Misses: 1}, {Name: "other", Entries: 0}},
+	}
+	var buf bytes.Buffer
+	if err := FormatCacheReport(&buf, r, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Cache: /tmp/pubmed-cli\n" +
+		"mesh\t2 entries\t3.0 MiB\n" +
+		"responses\t4 entries\t2.0 KiB\n" +
+		"\nResponses by endpoint:\n" +
+		"mesh/esearch.fcgi\t4 entries\t2.0 KiB\t75% hits (3 of 4)\n" +
+		"other\t0 entries\t0 B\tno lookups recorded\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatHistoryDiff(t *testing.T) {
+	d := history.Compare(
+		history.Entry{ID: 1, Query: "autism", Translation: `"autism"[All]`, Count: 3, IDs: []string{"3", "2", "1"}},
+		history.Entry{ID: 4, Query: "autism", Translation: `"autistic disorder"[MeSH]`, Count: 4, IDs: []string{"4", "3", "2"}},
+	)
+	var buf bytes.Buffer
+	if err := FormatHistoryDiff(&buf, d, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "#1 -> #4: 3 -> 4 results (+1)\n" +
+		"Translation:\n  - \"autism\"[All]\n  + \"autistic disorder\"[MeSH]\n" +
+		"Retrieved PMIDs: 2 in both, 1 added, 1 removed\n" +
+		"  + 4\n  - 1\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatBatchSummary(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := batch.Summary{Started: start, Finished: start.Add(1500 * time.Millisecond)}
+	s.Add(batch.Result{Name: "trials", Query: "fxs", Count: 12, PMIDs: []string{"1", "2"}, Out: "trials.ris", Exported: 2})
+	s.Add(batch.Result{Name: "reviews", Query: "fxs AND review", Error: "search failed: timeout"})
+
+	var buf bytes.Buffer
+	if err := FormatBatchSummary(&buf, s, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "trials\tfxs\t12 results, 2 exported to trials.ris\n" +
+		"reviews\tfxs AND review\tfailed: search failed: timeout\n" +
+		"2 jobs, 1 failed, 1.5s\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := FormatBatchSummary(&buf, s, OutputConfig{JSON: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"failed": 1`) || !strings.Contains(buf.String(), `"pmids": [`) {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
+
+func TestFormatFullText(t *testing.T) {
+	results := []fulltext.Result{
+		{PMID: "1", PMCID: "PMC9", Source: fulltext.SourcePMC, Format: "xml", URL: "https://pmc.ncbi.nlm.nih.gov/articles/PMC9/", License: "open-access",
+			LicenseURL: "https://creativecommons.org/licenses/by/4.0/",
+			Document:   &fulltext.Document{Title: "A trial", Sections: []fulltext.Section{{Title: "Methods", Depth: 1, Paragraphs: []string{"We did it."}}}}},
+		{PMID: "2", Source: fulltext.SourceUnpaywall, Format: "pdf", URL: "https://example.org/a.pdf"},
+		{PMID: "3"},
+	}
+	var buf bytes.Buffer
+	if err := FormatFullText(&buf, results, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "PMID 1: PMC9 XML at https://pmc.ncbi.nlm.nih.gov/articles/PMC9/\n" +
+		"License: open-access (https://creativecommons.org/licenses/by/4.0/)\n" +
+		"\n# A trial\n\n## Methods\n\nWe did it.\n" +
+		"\nPMID 2: Unpaywall PDF at https://example.org/a.pdf\n" +
+		"License: not stated; check the publisher's terms before reuse\n" +
+		"\nPMID 3: no open-access full text found\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatKeywords(t *testing.T) {
+	report := &analyze.KeywordReport{
+		Articles:       3,
+		Keywords:       []analyze.Keyword{{Term: "lovastatin", Score: 0.8466, Articles: 1}},
+		Bigrams:        []analyze.Keyword{{Term: "gamma power", Score: 3, Articles: 2}},
+		AuthorKeywords: []analyze.TermCount{{Term: "EEG", Count: 2, Percent: 66.67}},
+		Suggestions:    []string{`"gamma power"`, "EEG"},
+	}
+	var buf bytes.Buffer
+	if err := FormatKeywords(&buf, report, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Keywords across 3 articles (TF-IDF):",
+		"   1. lovastatin                       0.847      1 articles",
+		"   1. gamma power                        3 uses      2 articles",
+		"   1. EEG                                2   66.7%",
+		`Suggested query terms: "gamma power", EEG`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatAbbreviations(t *testing.T) {
+	report := &analyze.AbbreviationReport{
+		Articles: 4,
+		Abbreviations: []analyze.Abbreviation{
+			{Short: "CA", Long: "cancer antigen", Articles: 3, PMIDs: []string{"1", "3", "4"}, Variants: []string{"carbonic anhydrase"}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := FormatAbbreviations(&buf, report, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Abbreviations defined in 4 articles:",
+		"  CA           cancer antigen  (3 articles)",
+		"also: carbonic anhydrase",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatNetwork(t *testing.T) {
+	n := &network.Network{
+		Type: network.TypeCoauthor, Articles: 3,
+		Nodes: []network.Node{
+			{ID: "Smith J", Articles: 3, Degree: 4, Betweenness: 0.6667, PageRank: 0.3125},
+			{ID: "Lee K", Articles: 2, Degree: 2, PageRank: 0.2},
+		},
+		Edges: []network.Edge{{Source: "Lee K", Target: "Smith J", Weight: 2}},
+	}
+	var buf bytes.Buffer
+	if err := FormatNetwork(&buf, n, "net.graphml", 1, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Co-author network: 2 authors, 1 links from 3 articles\n" +
+		"Written to net.graphml\n" +
+		"\nMost central authors:\n" +
+		"  1. Smith J\t3 articles\tdegree 4\tbetweenness 0.667\tPageRank 0.312\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatRetractionReport(t *testing.T) {
+	r := retraction.Report{
+		Checked: 3,
+		Flagged: []retraction.Finding{{
+			Reference: "refs.ris #2", PMID: "31000001", Title: "A retracted trial", Journal: "J Things", Year: "2019",
+			Statuses: []string{retraction.Retracted},
+			Notices:  []retraction.Notice{{Label: "Retraction", Source: "J Things. 2021;4:5", PMID: "31000004"}},
+		}},
+		Unmatched: []string{"refs.ris #3"},
+	}
+	var buf bytes.Buffer
+	if err := FormatRetractionReport(&buf, r, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "refs.ris #2 (PMID 31000001)\tRETRACTED\tA retracted trial (J Things 2019)\n" +
+		"  Retraction: J Things. 2021;4:5 (PMID 31000004)\n" +
+		"1 of 3 references flagged, 1 not found in PubMed\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatStanceReport(t *testing.T) {
+	r := stance.NewReport("38000001", 12, []stance.Citation{
+		{PMID: "39000001", Title: "A follow-up trial", Journal: "J Things", Year: "2025", Stance: stance.Supporting,
+			Contexts: []string{"Our results are consistent with the pilot trial [2]."}},
+		{PMID: "39000002", Title: "A review", Stance: stance.Unknown},
+	})
+	var buf bytes.Buffer
+	if err := FormatStanceReport(&buf, r, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "39000001\tSUPPORTING\tA follow-up trial (J Things 2025)\n" +
+		"  > Our results are consistent with the pilot trial [2].\n" +
+		"39000002\tUNKNOWN\tA review\n" +
+		"2 of 12 citing articles of PMID 38000001: 1 supporting, 1 unknown (no PMC full text)\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatZoteroReport(t *testing.T) {
+	r := &zotero.Report{
+		Library:     "users/42",
+		Collection:  "My Review",
+		Items:       []zotero.Pushed{{PMID: "38000001", Key: "ITEM0001"}},
+		Attachments: 2,
+		Notes:       1,
+		Failed:      []zotero.Failure{{DOI: "10.1000/xyz", Message: "Invalid item"}},
+	}
+	var buf bytes.Buffer
+	if err := FormatZoteroReport(&buf, r, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FAILED\tDOI 10.1000/xyz\tInvalid item\n" +
+		"Pushed 1 items to collection \"My Review\" in users/42 (2 attachments, 1 notes), 1 failed\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatComparison_Markdown(t *testing.T) {
+	profiles := []compare.Profile{
+		{PMID: "1", Journal: "Lancet", Year: "2024", Design: "Randomized controlled trial", SampleSize: 120, Findings: "A | B improved."},
+		{PMID: "2", Design: "Cohort study", Population: "Humans; adults"},
+	}
+	var buf bytes.Buffer
+	if err := FormatComparison(&buf, profiles, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "|  | PMID 1 (Lancet 2024) | PMID 2 |\n" +
+		"| --- | --- | --- |\n" +
+		"| **Design** | Randomized controlled trial | Cohort study |\n" +
+		"| **Population** | not reported | Humans; adults |\n" +
+		"| **N** | 120 | not reported |\n" +
+		"| **Outcomes** | not reported | not reported |\n" +
+		"| **Findings** | A \\| B improved. | not reported |\n" +
+		"\n_Extracted from PubMed indexing and abstracts; verify against the full text._\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestFormatArticles_BookCard(t *testing.T) {
+	chapter := eutils.Article{
+		PMID: "20301558", Title: "FMR1 Disorders", BookTitle: "GeneReviews®", Year: "1993",
+		Publisher: "University of Washington, Seattle", CollectionTitle: "Gene reviews series",
+	}
+	var buf bytes.Buffer
+	if err := FormatArticles(&buf, []eutils.Article{chapter}, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Book: GeneReviews® (University of Washington, Seattle, 1993); Gene reviews series\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %q in:\n%s", want, buf.String())
+	}
+	if strings.Contains(buf.String(), "Journal:") {
+		t.Errorf("book records should not get a Journal line:\n%s", buf.String())
+	}
+}
+
+func TestFormatLint_Plain(t *testing.T) {
+	r := &query.LintReport{
+		Query:       "autism[tiabs]",
+		Problems:    []query.Problem{{Severity: query.SeverityError, Message: "unknown field tag [tiabs]", Hint: "did you mean [tiab]?"}},
+		Translation: "autism[All Fields]",
+		Diff:        []query.DiffPart{{Op: "-", Text: "autism[tiabs]"}, {Op: "+", Text: "autism[All Fields]"}},
+	}
+	var buf bytes.Buffer
+	if err := FormatLint(&buf, r, OutputConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"ERROR    unknown field tag [tiabs]", "did you mean [tiab]?", "Translation: autism[All Fields]", "  - autism[tiabs]", "  + autism[All Fields]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatSummaries_Plain(t *testing.T) {
+	summaries := []compare.Summary{
+		{PMID: "1", Title: "Metformin in FXS", Year: "2024", Summary: "Randomized controlled trial of 120 participants."},
+		{PMID: "2", Title: "A letter"},
+	}
+	var buf bytes.Buffer
+	if err := FormatSummaries(&buf, summaries, OutputConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "PMID 1 (2024) Metformin in FXS\nRandomized controlled trial of 120 participants.\n\nPMID 2 A letter\nNo abstract to summarize.\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatPICO_CSV(t *testing.T) {
+	rows := []compare.PICO{{PMID: "1", Title: "Metformin versus placebo", Year: "2024", Intervention: "Metformin", Comparator: "placebo", SampleSize: 120}}
+	path := filepath.Join(t.TempDir(), "pico.csv")
+	var buf bytes.Buffer
+	if err := FormatPICO(&buf, rows, OutputConfig{CSVFile: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "PMID,Title,Year,Population,Intervention,Comparator,Outcomes,Design,N\n" +
+		"1,Metformin versus placebo,2024,,Metformin,placebo,,,120\n"
+	if string(data) != want {
+		t.Errorf("CSV = %q, want %q", data, want)
+	}
+	if !strings.Contains(buf.String(), "  Intervention: Metformin\n") || !strings.Contains(buf.String(), "  Population:   not reported\n") {
+		t.Errorf("plain output:\n%s", buf.String())
+	}
+}
+
+func TestFormatGrants_CSV(t *testing.T) {
+	report := &analyze.GrantReport{Articles: 4, Funded: 2, Agencies: []analyze.AgencyCount{
+		{Agency: "NIMH NIH HHS", Country: "United States", Articles: 2, Percent: 50, GrantIDs: []string{"R01 MH1", "R01 MH2"}, PMIDs: []string{"1", "2"}},
+	}}
+	path := filepath.Join(t.TempDir(), "grants.csv")
+	var buf bytes.Buffer
+	if err := FormatGrants(&buf, report, OutputConfig{CSVFile: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Agency,Country,Articles,Percent,GrantIDs,PMIDs\n" +
+		"NIMH NIH HHS,United States,2,50.0,R01 MH1; R01 MH2,1; 2\n"
+	if string(data) != want {
+		t.Errorf("CSV = %q, want %q", data, want)
+	}
+	if !strings.Contains(buf.String(), "across 4 articles (2 list grants)") || !strings.Contains(buf.String(), "R01 MH1, R01 MH2") {
+		t.Errorf("plain output:\n%s", buf.String())
+	}
+}
+
+func TestFormatAffiliations_CSV(t *testing.T) {
+	report := &analyze.AffiliationReport{Articles: 3, Affiliated: 2,
+		Countries:    []analyze.PlaceCount{{Name: "Canada", Articles: 2, Percent: 100, PMIDs: []string{"1", "2"}}},
+		Institutions: []analyze.PlaceCount{{Name: "University of Toronto", Country: "Canada", Articles: 2, Percent: 100, PMIDs: []string{"1", "2"}}},
+	}
+	path := filepath.Join(t.TempDir(), "places.csv")
+	var buf bytes.Buffer
+	if err := FormatAffiliations(&buf, report, OutputConfig{CSVFile: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Kind,Name,Country,Articles,Percent,PMIDs\n" +
+		"country,Canada,,2,100.0,1; 2\n" +
+		"institution,University of Toronto,Canada,2,100.0,1; 2\n"
+	if string(data) != want {
+		t.Errorf("CSV = %q, want %q", data, want)
+	}
+	if !strings.Contains(buf.String(), "University of Toronto (Canada)") {
+		t.Errorf("plain output:\n%s", buf.String())
+	}
+}
+
+func TestFormatTriage_CSV(t *testing.T) {
+	results := []triage.Result{
+		{Rank: 1, PMID: "3", Year: "2024", Title: "Metformin for fragile X syndrome", Score: 2.5, Relevance: 1,
+			Matched: []string{"metformin", "fragile"}, Missing: []string{"language"}, Justification: "Metformin for fragile X syndrome"},
+		{Rank: 2, PMID: "1", Title: "Sleep in autism", Missing: []string{"metformin", "fragile", "language"}},
+	}
+	path := filepath.Join(t.TempDir(), "triage.csv")
+	var buf bytes.Buffer
+	if err := FormatTriage(&buf, "metformin fragile language", results, OutputConfig{CSVFile: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Rank,PMID,Year,Title,Score,Relevance,Matched,Missing,Justification\n" +
+		"1,3,2024,Metformin for fragile X syndrome,2.500,1.00,metformin; fragile,language,Metformin for fragile X syndrome\n" +
+		"2,1,,Sleep in autism,0.000,0.00,,metformin; fragile; language,\n"
+	if string(data) != want {
+		t.Errorf("CSV = %q, want %q", data, want)
+	}
+	if !strings.Contains(buf.String(), " 1. [1.00] PMID 3 (2024) Metformin") || !strings.Contains(buf.String(), "    Missing: language\n") {
+		t.Errorf("plain output:\n%s", buf.String())
+	}
+}
+
+func TestFormatAlertDiff_Plain(t *testing.T) {
+	d := alert.Diff{
+		Name: "fxs", From: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), To: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+		FromCount: 2, ToCount: 2,
+		Appeared: []string{"3"}, Disappeared: []string{"1"},
+		Changed:  []alert.Change{{PMID: "2", From: alert.AheadOfPrint, To: alert.Published}},
+		Articles: []eutils.Article{{PMID: "3", Title: "New trial"}, {PMID: "2", Title: "Early paper"}},
+	}
+	var buf bytes.Buffer
+	if err := FormatAlertDiff(&buf, d, OutputConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "fxs: 2026-01-01 09:00 -> 2026-02-01 09:00, 2 -> 2 results (+0)\n" +
+		"1 appeared, 1 disappeared, 1 changed status\n" +
+		"  + 3 New trial\n" +
+		"  - 1\n" +
+		"  ~ 2 ahead of print -> published Early paper\n"
+	if buf.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
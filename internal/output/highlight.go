@@ -0,0 +1,57 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// highlighter marks query terms inside titles and abstracts in human output.
+// A nil highlighter renders text unchanged.
+type highlighter struct {
+	re *regexp.Regexp
+}
+
+// newHighlighter matches terms case-insensitively at word starts, extending
+// to the end of the word so plurals and other inflections light up too.
+// Terms should be ordered longest first so phrases win over their words.
+func newHighlighter(terms []string) *highlighter {
+	var alts []string
+	for _, t := range terms {
+		if t = strings.TrimSpace(t); t != "" {
+			alts = append(alts, regexp.QuoteMeta(t))
+		}
+	}
+	if len(alts) == 0 {
+		return nil
+	}
+	return &highlighter{re: regexp.MustCompile(`(?i)\b(?:` + strings.Join(alts, "|") + `)\w*`)}
+}
+
+// render styles text with base and each match with highlightStyle. Segments
+// are rendered separately so escape sequences never nest, which would
+// otherwise cut the base style short after the first match.
+func (h *highlighter) render(text string, base lipgloss.Style) string {
+	if h == nil {
+		return base.Render(text)
+	}
+	matches := h.re.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return base.Render(text)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m[0] > last {
+			b.WriteString(base.Render(text[last:m[0]]))
+		}
+		b.WriteString(highlightStyle.Render(text[m[0]:m[1]]))
+		last = m[1]
+	}
+	if last < len(text) {
+		b.WriteString(base.Render(text[last:]))
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestHighlighter_Render(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(detectedProfile) })
+
+	h := newHighlighter([]string{"fragile x syndrome", "syndrome", "autism"})
+	out := h.render("Fragile X syndrome and autistic traits; other syndromes", lipgloss.NewStyle())
+
+	for _, want := range []string{
+		highlightStyle.Render("Fragile X syndrome"),
+		highlightStyle.Render("syndromes"),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %q", want, out)
+		}
+	}
+	if strings.Contains(out, highlightStyle.Render("autistic")) {
+		t.Errorf("expected whole-word prefix match only, got %q", out)
+	}
+	if plain := stripANSI(out); plain != "Fragile X syndrome and autistic traits; other syndromes" {
+		t.Errorf("highlighting changed the text: %q", plain)
+	}
+}
+
+func TestHighlighter_Nil(t *testing.T) {
+	if h := newHighlighter([]string{" ", ""}); h != nil {
+		t.Fatal("expected nil highlighter without terms")
+	}
+	var h *highlighter
+	if got := h.render("plain text", lipgloss.NewStyle()); got != "plain text" {
+		t.Errorf("expected text unchanged, got %q", got)
+	}
+}
+
+func stripANSI(s string) string {
+	var b strings.Builder
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			inEscape = true
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
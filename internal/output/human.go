@@ -3,30 +3,35 @@ package output
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
-	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/batch"
+	"github.com/henrybloomingdale/pubmed-cli/internal/cache"
+	"github.com/henrybloomingdale/pubmed-cli/internal/compare"
+	"github.com/henrybloomingdale/pubmed-cli/internal/dedupe"
 	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/fulltext"
+	"github.com/henrybloomingdale/pubmed-cli/internal/graph"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/i18n"
+	"github.com/henrybloomingdale/pubmed-cli/internal/journal"
 	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+	"github.com/henrybloomingdale/pubmed-cli/internal/network"
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+	"github.com/henrybloomingdale/pubmed-cli/internal/retraction"
+	"github.com/henrybloomingdale/pubmed-cli/internal/screen"
+	"github.com/henrybloomingdale/pubmed-cli/internal/stance"
+	"github.com/henrybloomingdale/pubmed-cli/internal/triage"
+	"github.com/henrybloomingdale/pubmed-cli/internal/zotero"
 )
 
-// --- Styles ---
-
-var (
-	cyan       = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
-	bold       = lipgloss.NewStyle().Bold(true)
-	dim        = lipgloss.NewStyle().Faint(true)
-	green      = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
-	yellow     = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
-	magenta    = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
-	labelStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("4"))
-	boxStyle   = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("6")).
-			Padding(0, 1)
-)
+// Styles are defined in theme.go and rebuilt by ConfigureStyles.
 
 // truncate cuts a string to maxLen characters, appending "…" if truncated.
 func truncate(s string, maxLen int) string {
@@ -43,21 +48,21 @@ func truncate(s string, maxLen int) string {
 
 // --- Search ---
 
-func formatSearchHuman(w io.Writer, result *eutils.SearchResult, articles []eutils.Article) error {
+func formatSearchHuman(w io.Writer, result *eutils.SearchResult, articles []eutils.Article, hl *highlighter, suggestions []Suggestion) error {
 	if result.Count == 0 {
-		fmt.Fprintln(w, "🔬 No results found.")
+		fmt.Fprintln(w, "🔬 "+i18n.T("No results found."))
 		return nil
 	}
 
 	// Header
-	header := fmt.Sprintf("🔬 Found %d results", result.Count)
+	header := "🔬 " + i18n.Sprintf("Found %d results", result.Count)
 	if len(result.IDs) < result.Count {
-		header += fmt.Sprintf(" (showing %d)", len(result.IDs))
+		header += i18n.Sprintf(" (showing %d)", len(result.IDs))
 	}
 	fmt.Fprintln(w, bold.Render(header))
 
 	if result.QueryTranslation != "" {
-		fmt.Fprintf(w, "   Query: %s\n", dim.Render(result.QueryTranslation))
+		fmt.Fprintf(w, "   %s %s\n", i18n.T("Query:"), dim.Render(result.QueryTranslation))
 	}
 	fmt.Fprintln(w)
 
@@ -81,23 +86,18 @@ func formatSearchHuman(w io.Writer, result *eutils.SearchResult, articles []euti
 			}
 			rows = append(rows, []string{
 				cyan.Render(a.PMID),
-				bold.Render(truncate(a.Title, 50)),
+				hl.render(truncate(a.Title, 50), bold),
 				a.Year,
 				pubType,
 			})
 		}
 
 		t := table.New().
-			Headers("PMID", "Title", "Year", "Type").
+			Headers("PMID", i18n.T("Title"), i18n.T("Year"), i18n.T("Type")).
 			Rows(rows...).
-			Border(lipgloss.NormalBorder()).
-			BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("8"))).
-			StyleFunc(func(row, col int) lipgloss.Style {
-				if row == table.HeaderRow {
-					return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("4"))
-				}
-				return lipgloss.NewStyle()
-			})
+			Border(tableBorder()).
+			BorderStyle(borderStyle).
+			StyleFunc(headerStyleFunc)
 
 		fmt.Fprintln(w, t.Render())
 	} else {
@@ -110,28 +110,31 @@ func formatSearchHuman(w io.Writer, result *eutils.SearchResult, articles []euti
 		t := table.New().
 			Headers("#", "PMID").
 			Rows(rows...).
-			Border(lipgloss.NormalBorder()).
-			BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("8"))).
-			StyleFunc(func(row, col int) lipgloss.Style {
-				if row == table.HeaderRow {
-					return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("4"))
-				}
-				return lipgloss.NewStyle()
-			})
+			Border(tableBorder()).
+			BorderStyle(borderStyle).
+			StyleFunc(headerStyleFunc)
 
 		fmt.Fprintln(w, t.Render())
 	}
 
 	fmt.Fprintln(w)
-	fmt.Fprintln(w, dim.Render("💾 Use --csv output.csv to export"))
+	if len(suggestions) > 0 {
+		fmt.Fprintln(w, bold.Render("💡 "+i18n.T("Consider adding:")))
+		for _, s := range suggestions {
+			fmt.Fprintf(w, "   %s %s\n", cyan.Render(s.Clause),
+				dim.Render(i18n.Sprintf("(major topic of %d of the top %d)", s.Articles, s.Sample)))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, dim.Render("💾 "+i18n.T("Use --csv output.csv to export")))
 	return nil
 }
 
 // --- Fetch / Articles ---
 
-func formatArticlesHuman(w io.Writer, articles []eutils.Article, full bool) error {
+func formatArticlesHuman(w io.Writer, articles []eutils.Article, full bool, hl *highlighter) error {
 	if len(articles) == 0 {
-		fmt.Fprintln(w, "No articles found.")
+		fmt.Fprintln(w, i18n.T("No articles found."))
 		return nil
 	}
 
@@ -141,8 +144,11 @@ func formatArticlesHuman(w io.Writer, articles []eutils.Article, full bool) erro
 		}
 
 		// Title card
-		titleLine := bold.Render(a.Title)
+		titleLine := hl.render(a.Title, bold)
 		meta := cyan.Render("PMID: " + a.PMID)
+		if a.PMID == "" && a.Source != "" {
+			meta = yellow.Render(i18n.Sprintf("Source: %s (not in PubMed)", a.Source))
+		}
 		if a.Year != "" {
 			meta += dim.Render(" · ") + a.Year
 		}
@@ -156,29 +162,60 @@ func formatArticlesHuman(w io.Writer, articles []eutils.Article, full bool) erro
 			for j, au := range a.Authors {
 				names[j] = au.FullName()
 			}
-			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render("Authors:"), strings.Join(names, ", "))
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Authors:")), strings.Join(names, ", "))
 		}
 
-		citation := a.Journal
-		if a.Volume != "" {
-			citation += " " + a.Volume
-			if a.Issue != "" {
-				citation += "(" + a.Issue + ")"
+		if a.IsBook() {
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Book:")), bookCitation(a))
+		} else {
+			citation := a.Journal
+			if a.Volume != "" {
+				citation += " " + a.Volume
+				if a.Issue != "" {
+					citation += "(" + a.Issue + ")"
+				}
 			}
+			if a.Pages != "" {
+				citation += ":" + a.Pages
+			}
+			if a.Year != "" {
+				citation += " (" + a.Year + ")"
+			}
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Journal:")), citation)
 		}
-		if a.Pages != "" {
-			citation += ":" + a.Pages
-		}
-		if a.Year != "" {
-			citation += " (" + a.Year + ")"
-		}
-		fmt.Fprintf(w, "  %s %s\n", labelStyle.Render("Journal:"), citation)
 
 		if a.DOI != "" {
-			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render("DOI:"), yellow.Render(a.DOI))
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("DOI:")), yellow.Render(a.DOI))
 		}
 		if len(a.PublicationTypes) > 0 {
-			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render("Type:"), strings.Join(a.PublicationTypes, ", "))
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Type:")), strings.Join(a.PublicationTypes, ", "))
+		}
+		if a.CitationCount > 0 {
+			fmt.Fprintf(w, "  %s %d\n", labelStyle.Render(i18n.T("Cited by:")), a.CitationCount)
+		}
+		if a.InfluentialCitationCount > 0 {
+			fmt.Fprintf(w, "  %s %d\n", labelStyle.Render(i18n.T("Influential citations:")), a.InfluentialCitationCount)
+		}
+		if icite := iciteMetrics(a); icite != "" {
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("iCite:")), icite)
+		}
+		if len(a.Concepts) > 0 {
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Concepts:")), strings.Join(a.Concepts, ", "))
+		}
+		if len(a.Institutions) > 0 {
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Institutions:")), strings.Join(a.Institutions, "; "))
+		}
+		if a.OAStatus != "" {
+			fmt.Fprintf(w, "  %s %s %s\n", labelStyle.Render(i18n.T("Open access:")), green.Render(a.OAStatus), a.OAURL)
+		}
+		if a.License != "" {
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("License:")), a.License)
+		}
+		if a.ReferenceCount > 0 {
+			fmt.Fprintf(w, "  %s %d\n", labelStyle.Render(i18n.T("References:")), a.ReferenceCount)
+		}
+		if len(a.CrossrefFields) > 0 {
+			fmt.Fprintf(w, "  %s\n", dim.Render(i18n.T("From Crossref:")+" "+strings.Join(a.CrossrefFields, ", ")))
 		}
 
 		// MeSH terms
@@ -191,21 +228,34 @@ func formatArticlesHuman(w io.Writer, articles []eutils.Article, full bool) erro
 				}
 				terms = append(terms, t)
 			}
-			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render("MeSH:"), strings.Join(terms, ", "))
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("MeSH:")), strings.Join(terms, ", "))
+		}
+
+		// Library annotations
+		if len(a.Tags) > 0 {
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Tags:")), magenta.Render("#"+strings.Join(a.Tags, " #")))
+		}
+		for _, n := range a.Notes {
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Note:")), n)
+		}
+
+		if a.TLDR != "" {
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("TLDR:")), a.TLDR)
 		}
 
 		// Abstract
 		if a.Abstract != "" {
 			fmt.Fprintln(w)
-			fmt.Fprintf(w, "  %s\n", labelStyle.Render("Abstract:"))
+			fmt.Fprintf(w, "  %s\n", labelStyle.Render(i18n.T("Abstract:")))
 			abstract := a.Abstract
 			if !full && utf8.RuneCountInString(abstract) > 500 {
 				runes := []rune(abstract)
 				abstract = string(runes[:497]) + "..."
-				fmt.Fprintf(w, "  %s\n", abstract)
-				fmt.Fprintf(w, "  %s\n", dim.Render("[use --full for complete abstract]"))
+				fmt.Fprintf(w, "  %s\n", hl.render(abstract, plain))
+				fmt.Fprintf(w, "  %s\n", dim.Render(i18n.T("[use --full for complete abstract]")))
 			} else {
-				fmt.Fprintf(w, "  %s\n", abstract)
+				fmt.Fprintf(w, "  %s\n", hl.render(abstract, plain))
 			}
 		}
 	}
@@ -231,15 +281,12 @@ func formatLinksHuman(w io.Writer, result *eutils.LinkResult, linkType string) e
 	}
 
 	if len(result.Links) == 0 {
-		fmt.Fprintf(w, "%s No %s results for PMID %s.\n", emoji, linkType, cyan.Render(result.SourceID))
+		fmt.Fprintf(w, "%s %s\n", emoji, i18n.Sprintf("No %s results for PMID %s.", linkType, cyan.Render(result.SourceID)))
 		return nil
 	}
 
-	fmt.Fprintf(w, "%s %s for PMID %s (%d results)\n\n",
-		emoji,
-		bold.Render(title),
-		cyan.Render(result.SourceID),
-		len(result.Links))
+	fmt.Fprintf(w, "%s %s\n\n", emoji,
+		i18n.Sprintf("%s for PMID %s (%d results)", bold.Render(i18n.T(title)), cyan.Render(result.SourceID), len(result.Links)))
 
 	var rows [][]string
 	hasScores := false
@@ -267,20 +314,15 @@ func formatLinksHuman(w io.Writer, result *eutils.LinkResult, linkType string) e
 
 	headers := []string{"#", "PMID"}
 	if hasScores {
-		headers = append(headers, "Score")
+		headers = append(headers, i18n.T("Score"))
 	}
 
 	t := table.New().
 		Headers(headers...).
 		Rows(rows...).
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("8"))).
-		StyleFunc(func(row, col int) lipgloss.Style {
-			if row == table.HeaderRow {
-				return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("4"))
-			}
-			return lipgloss.NewStyle()
-		})
+		Border(tableBorder()).
+		BorderStyle(borderStyle).
+		StyleFunc(headerStyleFunc)
 
 	fmt.Fprintln(w, t.Render())
 	return nil
@@ -288,6 +330,7 @@ func formatLinksHuman(w io.Writer, result *eutils.LinkResult, linkType string) e
 
 // FormatLinksWithArticles writes link results with full article details for human mode.
 func FormatLinksWithArticles(w io.Writer, result *eutils.LinkResult, articles []eutils.Article, articleMap map[string]eutils.Article, linkType string, limit int) error {
+	w = humanWriter(w)
 	emoji := "🔗"
 	title := linkType
 	switch linkType {
@@ -303,7 +346,7 @@ func FormatLinksWithArticles(w io.Writer, result *eutils.LinkResult, articles []
 	}
 
 	if len(result.Links) == 0 {
-		fmt.Fprintf(w, "%s No %s results for PMID %s.\n", emoji, linkType, cyan.Render(result.SourceID))
+		fmt.Fprintf(w, "%s %s\n", emoji, i18n.Sprintf("No %s results for PMID %s.", linkType, cyan.Render(result.SourceID)))
 		return nil
 	}
 
@@ -312,12 +355,8 @@ func FormatLinksWithArticles(w io.Writer, result *eutils.LinkResult, articles []
 		showing = len(result.Links)
 	}
 
-	fmt.Fprintf(w, "%s %s for PMID %s (%d total, showing %d)\n\n",
-		emoji,
-		bold.Render(title),
-		cyan.Render(result.SourceID),
-		len(result.Links),
-		showing)
+	fmt.Fprintf(w, "%s %s\n\n", emoji,
+		i18n.Sprintf("%s for PMID %s (%d total, showing %d)", bold.Render(i18n.T(title)), cyan.Render(result.SourceID), len(result.Links), showing))
 
 	// Check if we have scores
 	hasScores := false
@@ -333,7 +372,7 @@ func FormatLinksWithArticles(w io.Writer, result *eutils.LinkResult, articles []
 		link := result.Links[i]
 		article, found := articleMap[link.ID]
 
-		titleText := dim.Render("(not found)")
+		titleText := dim.Render(i18n.T("(not found)"))
 		yearText := ""
 		if found {
 			titleText = truncate(article.Title, 55)
@@ -356,22 +395,17 @@ func FormatLinksWithArticles(w io.Writer, result *eutils.LinkResult, articles []
 		rows = append(rows, row)
 	}
 
-	headers := []string{"#", "PMID", "Title", "Year"}
+	headers := []string{"#", "PMID", i18n.T("Title"), i18n.T("Year")}
 	if hasScores {
-		headers = append(headers, "Score")
+		headers = append(headers, i18n.T("Score"))
 	}
 
 	t := table.New().
 		Headers(headers...).
 		Rows(rows...).
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("8"))).
-		StyleFunc(func(row, col int) lipgloss.Style {
-			if row == table.HeaderRow {
-				return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("4"))
-			}
-			return lipgloss.NewStyle()
-		})
+		Border(tableBorder()).
+		BorderStyle(borderStyle).
+		StyleFunc(headerStyleFunc)
 
 	fmt.Fprintln(w, t.Render())
 	return nil
@@ -383,9 +417,18 @@ func formatMeSHHuman(w io.Writer, record *mesh.MeSHRecord) error {
 	// Name + UI header
 	fmt.Fprintf(w, "🏷️  %s  %s\n\n", bold.Render(record.Name), dim.Render(record.UI))
 
+	// Supplementary concepts are indexed under their mapped descriptors
+	if len(record.HeadingMappedTo) > 0 {
+		fmt.Fprintf(w, "  %s\n", labelStyle.Render(i18n.T("Supplementary Concept, mapped to:")))
+		for _, h := range record.HeadingMappedTo {
+			fmt.Fprintf(w, "    %s %s\n", magenta.Render("→"), h)
+		}
+		fmt.Fprintln(w)
+	}
+
 	// Tree numbers
 	if len(record.TreeNumbers) > 0 {
-		fmt.Fprintf(w, "  %s\n", labelStyle.Render("Tree Numbers:"))
+		fmt.Fprintf(w, "  %s\n", labelStyle.Render(i18n.T("Tree Numbers:")))
 		for _, tn := range record.TreeNumbers {
 			fmt.Fprintf(w, "    %s %s\n", magenta.Render("├"), tn)
 		}
@@ -394,7 +437,7 @@ func formatMeSHHuman(w io.Writer, record *mesh.MeSHRecord) error {
 
 	// Scope note
 	if record.ScopeNote != "" {
-		fmt.Fprintf(w, "  %s\n", labelStyle.Render("Scope Note:"))
+		fmt.Fprintf(w, "  %s\n", labelStyle.Render(i18n.T("Scope Note:")))
 		// Word-wrap at ~80 chars
 		wrapped := wordWrap(record.ScopeNote, 76)
 		for _, line := range strings.Split(wrapped, "\n") {
@@ -405,7 +448,7 @@ func formatMeSHHuman(w io.Writer, record *mesh.MeSHRecord) error {
 
 	// Entry terms (synonyms)
 	if len(record.EntryTerms) > 0 {
-		fmt.Fprintf(w, "  %s ", labelStyle.Render("Synonyms:"))
+		fmt.Fprintf(w, "  %s ", labelStyle.Render(i18n.T("Synonyms:")))
 		colored := make([]string, len(record.EntryTerms))
 		for i, et := range record.EntryTerms {
 			colored[i] = yellow.Render(et)
@@ -414,15 +457,784 @@ func formatMeSHHuman(w io.Writer, record *mesh.MeSHRecord) error {
 		fmt.Fprintln(w)
 	}
 
+	// Drug classes
+	if len(record.PharmacologicalActions) > 0 {
+		fmt.Fprintf(w, "  %s\n", labelStyle.Render(i18n.T("Pharmacological Actions:")))
+		for _, pa := range record.PharmacologicalActions {
+			fmt.Fprintf(w, "    %s %s\n", magenta.Render("├"), pa)
+		}
+		fmt.Fprintln(w)
+	}
+
 	// Annotation
 	if record.Annotation != "" {
-		fmt.Fprintf(w, "  %s %s\n", labelStyle.Render("Annotation:"), record.Annotation)
+		fmt.Fprintf(w, "  %s %s\n", labelStyle.Render(i18n.T("Annotation:")), record.Annotation)
+	}
+
+	return nil
+}
+
+func formatMeSHTreeHuman(w io.Writer, h *mesh.Hierarchy) error {
+	fmt.Fprintf(w, "🌳 %s  %s\n", bold.Render(h.Record.Name), dim.Render(h.Record.UI))
+
+	if len(h.Paths) == 0 {
+		fmt.Fprintf(w, "\n  %s\n", dim.Render(i18n.T("No tree positions.")))
+		return nil
+	}
+
+	for _, p := range h.Paths {
+		fmt.Fprintf(w, "\n  %s\n", labelStyle.Render(p.TreeNumber))
+		indent := "    "
+		for _, a := range p.Ancestors {
+			fmt.Fprintf(w, "%s%s %s\n", indent, a.Name, dim.Render(a.TreeNumber))
+			indent += "  "
+		}
+		fmt.Fprintf(w, "%s%s %s\n", indent, green.Render("▶ "+h.Record.Name), dim.Render(p.TreeNumber))
+		writeTreeNodesHuman(w, p.Children, indent+"  ")
 	}
 
 	return nil
 }
 
+func writeTreeNodesHuman(w io.Writer, nodes []mesh.TreeNode, prefix string) {
+	for i, c := range nodes {
+		branch, next := "├─", "│ "
+		if i == len(nodes)-1 {
+			branch, next = "└─", "  "
+		}
+		count := ""
+		if s := childCountSuffix(c); s != "" {
+			count = " " + yellow.Render("+"+strings.Trim(s, " ()"))
+		}
+		fmt.Fprintf(w, "%s%s %s %s%s\n", prefix, magenta.Render(branch), c.Name, dim.Render(c.TreeNumber), count)
+		writeTreeNodesHuman(w, c.Children, prefix+magenta.Render(next)+" ")
+	}
+}
+
 // wordWrap wraps text at the given width, breaking at spaces.
+func formatMeSHSuggestionsHuman(w io.Writer, prefix string, suggestions []mesh.Suggestion) error {
+	if len(suggestions) == 0 {
+		fmt.Fprintf(w, "🔍 %s\n", i18n.Sprintf("No MeSH terms match %s", bold.Render(prefix)))
+		return nil
+	}
+
+	fmt.Fprintf(w, "🔍 %s\n\n", i18n.Sprintf("MeSH terms matching %s", bold.Render(prefix)))
+	for _, s := range suggestions {
+		line := fmt.Sprintf("  %s %s", dim.Render(s.UI), s.Name)
+		if s.Matched != "" && s.Matched != s.Name {
+			line += " " + yellow.Render("← "+s.Matched)
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	return nil
+}
+
+func formatMeSHQualifiersHuman(w io.Writer, record *mesh.MeSHRecord, qualifiers []mesh.Qualifier) error {
+	if record != nil {
+		fmt.Fprintf(w, "🏷️  %s  %s\n", bold.Render(record.Name), dim.Render(record.UI))
+		if len(record.AllowableQualifiers) == 0 {
+			fmt.Fprintf(w, "  %s\n", dim.Render("Allowable qualifiers not reported; showing all."))
+		}
+		fmt.Fprintln(w)
+	}
+	for _, q := range qualifiers {
+		fmt.Fprintf(w, "  %s  %s\n", yellow.Render(q.Abbreviation), q.Name)
+	}
+	return nil
+}
+
+func formatMeSHRecordsHuman(w io.Writer, title string, records []mesh.MeSHRecord) error {
+	fmt.Fprintf(w, "💊 %s  %s\n\n", bold.Render(title), dim.Render(fmt.Sprintf("%d records", len(records))))
+	for _, r := range records {
+		fmt.Fprintf(w, "  %s %s\n", dim.Render(r.UI), r.Name)
+	}
+	return nil
+}
+
+func formatConceptMappingsHuman(w io.Writer, mappings []query.Mapping) error {
+	if len(mappings) == 0 {
+		fmt.Fprintf(w, "🧭 %s\n", dim.Render("No MeSH concepts found."))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(mappings))
+	for _, m := range mappings {
+		if m.UI == "" {
+			rows = append(rows, []string{m.Phrase, "—", "", "", m.Via})
+			continue
+		}
+		rows = append(rows, []string{m.Phrase, m.Descriptor, m.UI, fmt.Sprintf("%.0f%%", m.Confidence*100), m.Via})
+	}
+
+	t := table.New().
+		Headers("Phrase", "Descriptor", "UI", "Confidence", "Via").
+		Rows(rows...).
+		Border(tableBorder()).
+		BorderStyle(borderStyle).
+		StyleFunc(headerStyleFunc)
+
+	fmt.Fprintf(w, "🧭 %s\n", bold.Render("MeSH concepts"))
+	fmt.Fprintln(w, t.Render())
+	return nil
+}
+
+func formatMeSHFrequencyHuman(w io.Writer, report *analyze.MeSHReport) error {
+	kind := "MeSH headings"
+	if report.MajorOnly {
+		kind = "Major-topic MeSH headings"
+	}
+	fmt.Fprintf(w, "📊 %s  %s\n\n", bold.Render(kind),
+		dim.Render(fmt.Sprintf("%d articles, %d indexed", report.Articles, report.Indexed)))
+	if len(report.Terms) == 0 {
+		fmt.Fprintf(w, "  %s\n", dim.Render("No MeSH headings found."))
+		return nil
+	}
+
+	width := 0
+	for _, t := range report.Terms {
+		if n := len([]rune(t.Term)); n > width {
+			width = n
+		}
+	}
+	for _, t := range report.Terms {
+		bar := strings.Repeat("█", int(t.Percent/100*30+0.5))
+		fmt.Fprintf(w, "  %-*s %s %s\n", width, t.Term, green.Render(bar),
+			dim.Render(fmt.Sprintf("%d (%.1f%%)", t.Count, t.Percent)))
+	}
+	return nil
+}
+
+func formatAffiliationsHuman(w io.Writer, report *analyze.AffiliationReport) error {
+	fmt.Fprintf(w, "🌍 %s  %s\n", bold.Render("Affiliations"),
+		dim.Render(fmt.Sprintf("%d articles, %d give affiliations", report.Articles, report.Affiliated)))
+	if len(report.Countries) == 0 && len(report.Institutions) == 0 {
+		fmt.Fprintf(w, "\n  %s\n", dim.Render("No affiliations found."))
+		return nil
+	}
+
+	width := 0
+	for _, c := range report.Countries {
+		width = max(width, len([]rune(c.Name)))
+	}
+	fmt.Fprintf(w, "\n  %s\n", labelStyle.Render("Countries"))
+	for _, c := range report.Countries {
+		bar := strings.Repeat("█", int(c.Percent/100*30+0.5))
+		fmt.Fprintf(w, "  %-*s %s %s\n", width, c.Name, green.Render(bar),
+			dim.Render(fmt.Sprintf("%d (%.1f%%)", c.Articles, c.Percent)))
+	}
+	fmt.Fprintf(w, "\n  %s\n", labelStyle.Render("Institutions"))
+	for i, c := range report.Institutions {
+		fmt.Fprintf(w, "  %s %s  %s\n", cyan.Render(fmt.Sprintf("%2d.", i+1)), truncate(c.Name, 70),
+			dim.Render(strings.TrimPrefix(fmt.Sprintf("%s · %d (%.1f%%)", c.Country, c.Articles, c.Percent), " · ")))
+	}
+	return nil
+}
+
+func formatGrantsHuman(w io.Writer, report *analyze.GrantReport) error {
+	fmt.Fprintf(w, "💰 %s  %s\n\n", bold.Render("Funding agencies"),
+		dim.Render(fmt.Sprintf("%d articles, %d list grants", report.Articles, report.Funded)))
+	if len(report.Agencies) == 0 {
+		fmt.Fprintf(w, "  %s\n", dim.Render("No grants found."))
+		return nil
+	}
+
+	width := 0
+	for _, a := range report.Agencies {
+		width = max(width, len([]rune(a.Agency)))
+	}
+	for _, a := range report.Agencies {
+		bar := strings.Repeat("█", int(a.Percent/100*30+0.5))
+		fmt.Fprintf(w, "  %-*s %s %s\n", width, a.Agency, green.Render(bar),
+			dim.Render(fmt.Sprintf("%d (%.1f%%)", a.Articles, a.Percent)))
+		if len(a.GrantIDs) > 0 {
+			fmt.Fprintf(w, "  %-*s %s\n", width, "", cyan.Render(truncate(strings.Join(a.GrantIDs, ", "), 80)))
+		}
+	}
+	return nil
+}
+
+func formatKeywordsHuman(w io.Writer, report *analyze.KeywordReport) error {
+	fmt.Fprintf(w, "🔑 %s  %s\n\n", bold.Render("Keywords"), dim.Render(fmt.Sprintf("%d articles, TF-IDF", report.Articles)))
+	if len(report.Keywords) == 0 {
+		fmt.Fprintf(w, "  %s\n", dim.Render("No keywords found."))
+		return nil
+	}
+	for i, k := range report.Keywords {
+		fmt.Fprintf(w, "  %s %s  %s\n", cyan.Render(fmt.Sprintf("%2d.", i+1)), bold.Render(k.Term),
+			dim.Render(fmt.Sprintf("%.3f · %d articles", k.Score, k.Articles)))
+	}
+	if len(report.Bigrams) > 0 {
+		fmt.Fprintf(w, "\n  %s\n", labelStyle.Render("Frequent bigrams"))
+		for _, k := range report.Bigrams {
+			fmt.Fprintf(w, "  %s  %s\n", k.Term, dim.Render(fmt.Sprintf("%d uses · %d articles", int(k.Score), k.Articles)))
+		}
+	}
+	if len(report.AuthorKeywords) > 0 {
+		fmt.Fprintf(w, "\n  %s\n", labelStyle.Render("Author keywords"))
+		for _, t := range report.AuthorKeywords {
+			fmt.Fprintf(w, "  %s  %s\n", t.Term, dim.Render(fmt.Sprintf("%d (%.1f%%)", t.Count, t.Percent)))
+		}
+	}
+	if len(report.Suggestions) > 0 {
+		fmt.Fprintf(w, "\n  %s %s\n", labelStyle.Render("Try adding:"), green.Render(strings.Join(report.Suggestions, ", ")))
+	}
+	return nil
+}
+
+func formatAbbreviationsHuman(w io.Writer, report *analyze.AbbreviationReport) error {
+	fmt.Fprintf(w, "🔤 %s  %s\n\n", bold.Render("Abbreviations"), dim.Render(fmt.Sprintf("defined in %d articles", report.Articles)))
+	if len(report.Abbreviations) == 0 {
+		fmt.Fprintf(w, "  %s\n", dim.Render("No abbreviations found."))
+		return nil
+	}
+	for _, a := range report.Abbreviations {
+		fmt.Fprintf(w, "  %s  %s  %s\n", cyan.Render(a.Short), a.Long, dim.Render(fmt.Sprintf("%d articles", a.Articles)))
+		if len(a.Variants) > 0 {
+			fmt.Fprintf(w, "    %s %s\n", labelStyle.Render("also:"), strings.Join(a.Variants, "; "))
+		}
+	}
+	return nil
+}
+
+func formatTrendsHuman(w io.Writer, trends []analyze.Trend) error {
+	peak := 0
+	for _, t := range trends {
+		for _, c := range t.Counts {
+			if c.Count > peak {
+				peak = c.Count
+			}
+		}
+	}
+
+	for i, t := range trends {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		counts := make([]int, len(t.Counts))
+		for j, c := range t.Counts {
+			counts[j] = c.Count
+		}
+		fmt.Fprintf(w, "📈 %s  %s  %s\n\n", bold.Render(t.Query), cyan.Render(sparkline(counts)),
+			dim.Render(fmt.Sprintf("%d total", t.Total)))
+		for _, c := range t.Counts {
+			bar := ""
+			if peak > 0 {
+				bar = strings.Repeat("█", int(float64(c.Count)/float64(peak)*30+0.5))
+			}
+			fmt.Fprintf(w, "  %d %s %s\n", c.Year, green.Render(bar), dim.Render(fmt.Sprintf("%d", c.Count)))
+		}
+	}
+	return nil
+}
+
+// sparklineTicks are the eighth-block glyphs a sparkline is drawn with.
+var sparklineTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a row of block glyphs scaled to their maximum.
+func sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if peak > 0 {
+			i = v * (len(sparklineTicks) - 1) / peak
+		}
+		b.WriteRune(sparklineTicks[i])
+	}
+	return b.String()
+}
+
+func formatAlertResultsHuman(w io.Writer, results []alert.Result) error {
+	for _, r := range results {
+		if len(r.New) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "🔔 %s  %s  %s\n", bold.Render(r.Name), green.Render(fmt.Sprintf("%d new", len(r.New))), dim.Render(r.Query))
+		titles := alertTitles(r)
+		for _, id := range r.New {
+			fmt.Fprintf(w, "   %s  %s\n", cyan.Render(id), truncate(titles[id], 90))
+		}
+		if r.Truncated {
+			fmt.Fprintf(w, "   %s\n", yellow.Render("More results than --limit; rerun with a higher --limit for the rest"))
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func formatDigestHuman(w io.Writer, d *alert.Digest) error {
+	fmt.Fprintf(w, "📰 %s  %s  %s\n", bold.Render(d.Topic),
+		green.Render(fmt.Sprintf("%d added since %s", d.Count, d.Since)), dim.Render(d.Query))
+	for _, g := range digestGroups(d) {
+		fmt.Fprintf(w, "\n   %s %s\n", labelStyle.Render(digestJournal(g)), dim.Render(fmt.Sprintf("(%d)", len(g.Articles))))
+		for _, a := range g.Articles {
+			fmt.Fprintf(w, "   %s  %s\n", cyan.Render(a.PMID), truncate(a.Title, 90))
+			if by := byline(a); by != "" {
+				fmt.Fprintf(w, "             %s\n", dim.Render(by))
+			}
+		}
+	}
+	if d.Truncated {
+		fmt.Fprintf(w, "\n   %s\n", yellow.Render(fmt.Sprintf("Showing %d of %d; raise --limit for more", len(d.PMIDs), d.Count)))
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+func formatDedupeHuman(w io.Writer, res dedupe.Result) error {
+	fmt.Fprintf(w, "🧹 %s  %s\n", bold.Render(fmt.Sprintf("%d unique of %d records", len(res.Records), res.Input)),
+		dim.Render(strings.TrimSpace(fmt.Sprintf("%d duplicates removed%s", len(res.Duplicates), dedupeReasons(res)))))
+	if len(res.Duplicates) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	for _, d := range res.Duplicates {
+		fmt.Fprintf(w, "   %s → %s  %s  %s\n",
+			yellow.Render(fmt.Sprintf("%s#%d", d.Dropped.Source, d.Dropped.Index)),
+			green.Render(fmt.Sprintf("%s#%d", d.Kept.Source, d.Kept.Index)),
+			cyan.Render(dedupeReason(d)), truncate(recordLabel(d.Dropped), 70))
+	}
+	return nil
+}
+
+func formatScreeningHuman(w io.Writer, items []*screen.Item) error {
+	fmt.Fprintf(w, "📋 %s\n", bold.Render(screeningTally(items)))
+	if len(items) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	for _, it := range items {
+		var tag string
+		switch it.Decision {
+		case screen.Include:
+			tag = green.Render("✓ include")
+		case screen.Exclude:
+			tag = magenta.Render("✗ exclude")
+		case screen.Maybe:
+			tag = yellow.Render("? maybe  ")
+		default:
+			tag = dim.Render("· pending")
+		}
+		fmt.Fprintf(w, "   %s  %s  %s\n", tag, cyan.Render(it.Article.PMID), truncate(it.Article.Title, 70))
+	}
+	return nil
+}
+
+func formatHistoryHuman(w io.Writer, entries []history.Entry) error {
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s  %s  %s  %s\n", cyan.Render(fmt.Sprintf("#%-4d", e.ID)),
+			dim.Render(e.Time.Local().Format("2006-01-02 15:04")),
+			yellow.Render(fmt.Sprintf("%8d", e.Count)), bold.Render(e.Query))
+	}
+	return nil
+}
+
+func formatAlertDiffHuman(w io.Writer, d alert.Diff) error {
+	titles := articleTitles(d.Articles)
+	change := dim.Render("±0")
+	switch delta := d.ToCount - d.FromCount; {
+	case delta > 0:
+		change = green.Render(signed(delta))
+	case delta < 0:
+		change = magenta.Render(signed(delta))
+	}
+	from := d.From.Format("Jan 2, 2006")
+	if d.SeenBaseline {
+		from = "reported PMIDs"
+	}
+	fmt.Fprintf(w, "🔁 %s  %s\n", bold.Render(fmt.Sprintf("%s: %s → %s, %d → %d results", d.Name, from,
+		d.To.Format("Jan 2, 2006"), d.FromCount, d.ToCount)), change)
+	fmt.Fprintf(w, "   %s\n", dim.Render(d.Query))
+	if len(d.Appeared)+len(d.Disappeared)+len(d.Changed) == 0 {
+		fmt.Fprintf(w, "   %s\n", dim.Render("No changes"))
+	}
+	if len(d.Appeared) > 0 {
+		fmt.Fprintf(w, "   %s\n", labelStyle.Render(fmt.Sprintf("Appeared (%d):", len(d.Appeared))))
+		for _, id := range d.Appeared {
+			fmt.Fprintf(w, "     %s %s\n", green.Render("+ "+id), truncate(titles[id], 80))
+		}
+	}
+	if len(d.Disappeared) > 0 {
+		fmt.Fprintf(w, "   %s\n", labelStyle.Render(fmt.Sprintf("Disappeared (%d):", len(d.Disappeared))))
+		for _, id := range d.Disappeared {
+			fmt.Fprintf(w, "     %s\n", magenta.Render("- "+id))
+		}
+	}
+	if len(d.Changed) > 0 {
+		fmt.Fprintf(w, "   %s\n", labelStyle.Render(fmt.Sprintf("Changed status (%d):", len(d.Changed))))
+		for _, c := range d.Changed {
+			to := cyan.Render(c.To)
+			if c.To != alert.Published && c.To != alert.AheadOfPrint {
+				to = yellow.Render(c.To)
+			}
+			fmt.Fprintf(w, "     %s %s → %s  %s\n", cyan.Render("~ "+c.PMID), dim.Render(c.From), to, truncate(titles[c.PMID], 60))
+		}
+	}
+	if d.Truncated {
+		fmt.Fprintf(w, "   %s\n", dim.Render("Results were cut off at --limit; some changes may be at the cutoff."))
+	}
+	return nil
+}
+
+func formatHistoryDiffHuman(w io.Writer, d history.Diff) error {
+	change := dim.Render("±0")
+	switch delta := d.To.Count - d.From.Count; {
+	case delta > 0:
+		change = green.Render(signed(delta))
+	case delta < 0:
+		change = magenta.Render(signed(delta))
+	}
+	fmt.Fprintf(w, "🔁 %s  %s\n", bold.Render(fmt.Sprintf("#%d → #%d: %d → %d results", d.From.ID, d.To.ID, d.From.Count, d.To.Count)), change)
+	if d.From.Query != d.To.Query {
+		fmt.Fprintf(w, "   %s\n     %s\n     %s\n", labelStyle.Render("Query:"), magenta.Render("- "+d.From.Query), green.Render("+ "+d.To.Query))
+	}
+	if d.TranslationChanged() {
+		fmt.Fprintf(w, "   %s\n     %s\n     %s\n", labelStyle.Render("Translation:"),
+			magenta.Render("- "+d.From.Translation), green.Render("+ "+d.To.Translation))
+	}
+	fmt.Fprintf(w, "   %s %s\n", labelStyle.Render("Retrieved PMIDs:"),
+		dim.Render(fmt.Sprintf("%d in both, %d added, %d removed", d.Common, len(d.Added), len(d.Removed))))
+	for _, id := range d.Added {
+		fmt.Fprintf(w, "     %s\n", green.Render("+ "+id))
+	}
+	for _, id := range d.Removed {
+		fmt.Fprintf(w, "     %s\n", magenta.Render("- "+id))
+	}
+	return nil
+}
+
+func formatComparisonHuman(w io.Writer, profiles []compare.Profile) error {
+	for i, p := range profiles {
+		fmt.Fprintf(w, "%s %s  %s\n", cyan.Render(fmt.Sprintf("[%d]", i+1)), bold.Render(truncate(p.Title, 80)), dim.Render(comparisonHeading(p)))
+	}
+	for _, f := range compare.Fields {
+		fmt.Fprintf(w, "\n%s\n", labelStyle.Render(f.Name))
+		for i, p := range profiles {
+			v := f.Value(p)
+			if v == "" {
+				v = dim.Render("not reported")
+			}
+			fmt.Fprintf(w, "  %s %s\n", cyan.Render(fmt.Sprintf("[%d]", i+1)), v)
+		}
+	}
+	fmt.Fprintf(w, "\n%s\n", dim.Render("Extracted from PubMed indexing and abstracts; verify against the full text."))
+	return nil
+}
+
+func formatBatchSummaryHuman(w io.Writer, s batch.Summary) error {
+	for _, r := range s.Jobs {
+		if !r.OK() {
+			fmt.Fprintf(w, "%s %s  %s\n", magenta.Render("✗"), bold.Render(r.Name), magenta.Render(r.Error))
+			continue
+		}
+		fmt.Fprintf(w, "%s %s  %s\n", green.Render("✓"), bold.Render(r.Name), yellow.Render(fmt.Sprintf("%d results", r.Count)))
+		if r.Out != "" {
+			fmt.Fprintf(w, "   %s\n", dim.Render(fmt.Sprintf("%d exported to %s", r.Exported, r.Out)))
+		}
+	}
+	summary := fmt.Sprintf("%d jobs in %s", len(s.Jobs), s.Finished.Sub(s.Started).Round(time.Millisecond))
+	if s.Failed > 0 {
+		summary += fmt.Sprintf(", %d failed", s.Failed)
+	}
+	fmt.Fprintf(w, "\n%s\n", dim.Render(summary))
+	return nil
+}
+
+func formatRetractionReportHuman(w io.Writer, r retraction.Report) error {
+	for _, f := range r.Flagged {
+		fmt.Fprintf(w, "%s %s  %s\n", magenta.Render("⚠"), cyan.Render(findingLabel(f)), magenta.Render(strings.ToUpper(strings.Join(f.Statuses, ", "))))
+		fmt.Fprintf(w, "   %s\n", bold.Render(truncate(f.Title, 80)))
+		for _, n := range f.Notices {
+			fmt.Fprintf(w, "   %s\n", dim.Render(noticeText(n)))
+		}
+		fmt.Fprintln(w)
+	}
+	if len(r.Flagged) == 0 {
+		fmt.Fprintln(w, green.Render("✓ "+retractionSummary(r)))
+		return nil
+	}
+	fmt.Fprintln(w, yellow.Render(retractionSummary(r)))
+	return nil
+}
+
+func formatStanceReportHuman(w io.Writer, r stance.Report) error {
+	for _, c := range r.Citations {
+		label := dim.Render(strings.ToUpper(c.Stance))
+		switch c.Stance {
+		case stance.Supporting:
+			label = green.Render(strings.ToUpper(c.Stance))
+		case stance.Contrasting:
+			label = magenta.Render(strings.ToUpper(c.Stance))
+		}
+		fmt.Fprintf(w, "%s  %s\n", cyan.Render("PMID "+c.PMID), label)
+		fmt.Fprintf(w, "   %s\n", bold.Render(truncate(citingCitation(c), 80)))
+		for _, s := range c.Contexts {
+			fmt.Fprintf(w, "   %s\n", dim.Render("> "+s))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, yellow.Render(stanceSummary(r)))
+	return nil
+}
+
+func formatZoteroReportHuman(w io.Writer, r *zotero.Report) error {
+	for _, f := range r.Failed {
+		fmt.Fprintf(w, "%s %s  %s\n", magenta.Render("✗"), cyan.Render(failureLabel(f)), dim.Render(f.Message))
+	}
+	if len(r.Failed) > 0 {
+		fmt.Fprintln(w, yellow.Render(zoteroSummary(r)))
+		return nil
+	}
+	fmt.Fprintln(w, green.Render("✓ "+zoteroSummary(r)))
+	return nil
+}
+
+func formatFullTextHuman(w io.Writer, results []fulltext.Result) error {
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		status := green.Render(fullTextSummary(r))
+		if !r.Found() {
+			status = dim.Render(fullTextSummary(r))
+		}
+		fmt.Fprintf(w, "📖 %s  %s\n", cyan.Render("PMID "+r.PMID), status)
+		if r.Found() {
+			fmt.Fprintf(w, "   %s %s\n", labelStyle.Render(i18n.T("License:")), yellow.Render(licenseText(r)))
+		}
+		if r.Document == nil || r.Path != "" {
+			continue
+		}
+		for _, line := range strings.Split(r.Document.Text(), "\n") {
+			if strings.HasPrefix(line, "#") {
+				line = bold.Render(strings.TrimLeft(line, "# "))
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	return nil
+}
+
+func formatCacheReportHuman(w io.Writer, r cache.Report) error {
+	fmt.Fprintf(w, "🗄  %s  %s\n", bold.Render("Cache"), dim.Render(r.Dir))
+	if len(r.Sections) == 0 {
+		fmt.Fprintln(w, dim.Render("   empty"))
+		return nil
+	}
+	fmt.Fprintln(w)
+	for _, s := range r.Sections {
+		fmt.Fprintf(w, "   %s %s  %s\n", cyan.Render(fmt.Sprintf("%-12s", s.Name)), bold.Render(fmt.Sprintf("%9s", FormatBytes(s.Bytes))),
+			dim.Render(fmt.Sprintf("%d entries", s.Entries)))
+	}
+	if len(r.Endpoints) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\n   %s\n", labelStyle.Render("Responses by endpoint"))
+	for _, e := range r.Endpoints {
+		rate := dim.Render(hitRate(e))
+		if e.Hits+e.Misses > 0 {
+			rate = green.Render(hitRate(e))
+		}
+		fmt.Fprintf(w, "   %s %s  %s  %s\n", cyan.Render(fmt.Sprintf("%-22s", e.Name)), fmt.Sprintf("%9s", FormatBytes(e.Bytes)),
+			dim.Render(fmt.Sprintf("%5d entries", e.Entries)), rate)
+	}
+	return nil
+}
+
+func formatJournalsHuman(w io.Writer, journals []journal.Journal) error {
+	if len(journals) == 0 {
+		fmt.Fprintln(w, yellow.Render("No journals found."))
+		return nil
+	}
+	for i, j := range journals {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "📰 %s  %s\n", bold.Render(j.Title), dim.Render("NLM ID "+j.NLMID))
+		if j.MedlineTA != "" {
+			fmt.Fprintf(w, "   %s %s\n", labelStyle.Render("Abbreviation:"), cyan.Render(j.MedlineTA))
+		}
+		if j.ISOAbbrev != "" && j.ISOAbbrev != j.MedlineTA {
+			fmt.Fprintf(w, "   %s %s\n", labelStyle.Render("ISO:"), j.ISOAbbrev)
+		}
+		if len(j.ISSNs) > 0 {
+			fmt.Fprintf(w, "   %s %s\n", labelStyle.Render("ISSN:"), journalISSNs(j))
+		}
+		if j.Publisher != "" {
+			fmt.Fprintf(w, "   %s %s\n", labelStyle.Render("Publisher:"), j.Publisher)
+		}
+		status := yellow.Render("⚠ " + indexingStatus(j))
+		if j.CurrentlyIndexed {
+			status = green.Render("✓ " + indexingStatus(j))
+		}
+		fmt.Fprintf(w, "   %s %s\n", labelStyle.Render("MEDLINE:"), status)
+		if len(j.Counts) > 0 {
+			counts := make([]int, len(j.Counts))
+			for k, c := range j.Counts {
+				counts[k] = c.Count
+			}
+			first, last := j.Counts[0], j.Counts[len(j.Counts)-1]
+			fmt.Fprintf(w, "   %s %s %s\n", labelStyle.Render("PubMed articles:"), cyan.Render(sparkline(counts)),
+				dim.Render(fmt.Sprintf("%d in %d → %d in %d", first.Count, first.Year, last.Count, last.Year)))
+		}
+	}
+	return nil
+}
+
+func formatJournalChecksHuman(w io.Writer, checks []journal.Check) error {
+	if len(checks) == 0 {
+		fmt.Fprintln(w, dim.Render("No journal terms ([ta], [journal], [is]) in the query."))
+		return nil
+	}
+	for _, ch := range checks {
+		switch {
+		case ch.Journal == nil:
+			fmt.Fprintf(w, "%s %s %s\n", yellow.Render("✗"), bold.Render(strconv.Quote(ch.Term)), dim.Render("no NLM Catalog journal matches"))
+		case ch.Exact:
+			fmt.Fprintf(w, "%s %s → %s %s\n", green.Render("✓"), bold.Render(strconv.Quote(ch.Term)), ch.Journal.Title,
+				dim.Render("("+indexingStatus(*ch.Journal)+")"))
+		default:
+			fmt.Fprintf(w, "%s %s → closest is %s; use %s\n", yellow.Render("⚠"), bold.Render(strconv.Quote(ch.Term)),
+				ch.Journal.Title, cyan.Render(ch.Journal.PubMedClause()))
+		}
+	}
+	return nil
+}
+
+func formatPICOHuman(w io.Writer, rows []compare.PICO) error {
+	for i, p := range rows {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		meta := cyan.Render("PMID " + p.PMID)
+		if p.Year != "" {
+			meta += dim.Render(" · " + p.Year)
+		}
+		fmt.Fprintf(w, "🧪 %s\n   %s\n", meta, bold.Render(p.Title))
+		for _, f := range picoFields {
+			v := f.value(p)
+			if v == "" {
+				v = dim.Render("not reported")
+			}
+			fmt.Fprintf(w, "   %s %s\n", labelStyle.Render(fmt.Sprintf("%-13s", f.name+":")), v)
+		}
+	}
+	fmt.Fprintf(w, "\n%s\n", dim.Render("Extracted from PubMed indexing and abstracts; verify against the full text."))
+	return nil
+}
+
+func formatTriageHuman(w io.Writer, question string, results []triage.Result) error {
+	fmt.Fprintf(w, "🎯 %s %s\n", bold.Render("Triage"), question)
+	for _, r := range results {
+		bar := strings.Repeat("█", int(r.Relevance*10+0.5)) + strings.Repeat("░", 10-int(r.Relevance*10+0.5))
+		meta := cyan.Render("PMID " + r.PMID)
+		if r.Year != "" {
+			meta += dim.Render(" · " + r.Year)
+		}
+		fmt.Fprintf(w, "\n%s %s %s %s\n", dim.Render(fmt.Sprintf("%2d.", r.Rank)), green.Render(bar),
+			dim.Render(fmt.Sprintf("%.2f", r.Relevance)), meta)
+		fmt.Fprintf(w, "    %s\n", bold.Render(r.Title))
+		if r.Justification != "" {
+			fmt.Fprintf(w, "    %s\n", truncate(r.Justification, 160))
+		}
+		if len(r.Missing) > 0 {
+			fmt.Fprintf(w, "    %s %s\n", labelStyle.Render("Missing:"), yellow.Render(strings.Join(r.Missing, ", ")))
+		}
+	}
+	return nil
+}
+
+func formatSummariesHuman(w io.Writer, summaries []compare.Summary) error {
+	for i, s := range summaries {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		meta := cyan.Render("PMID " + s.PMID)
+		if s.Year != "" {
+			meta += dim.Render(" · " + s.Year)
+		}
+		fmt.Fprintf(w, "📝 %s\n   %s\n", meta, bold.Render(s.Title))
+		if s.Summary == "" {
+			fmt.Fprintf(w, "   %s\n", dim.Render("No abstract to summarize"))
+			continue
+		}
+		fmt.Fprintf(w, "   %s\n", s.Summary)
+	}
+	return nil
+}
+
+func formatLintHuman(w io.Writer, r *query.LintReport) error {
+	fmt.Fprintf(w, "🔎 %s %s\n", bold.Render("Lint"), r.Query)
+	if len(r.Problems) == 0 {
+		fmt.Fprintf(w, "   %s %s\n", green.Render("✓"), "No problems found")
+	}
+	for _, p := range r.Problems {
+		mark := yellow.Render("⚠")
+		if p.Severity == query.SeverityError {
+			mark = yellow.Render("✗")
+		}
+		fmt.Fprintf(w, "   %s %s\n", mark, p.Message)
+		if p.Hint != "" {
+			fmt.Fprintf(w, "     %s\n", dim.Render(p.Hint))
+		}
+	}
+	if r.Translation != "" {
+		fmt.Fprintf(w, "\n   %s\n   ", bold.Render("PubMed searched"))
+		for i, d := range r.Diff {
+			if i > 0 {
+				fmt.Fprint(w, " ")
+			}
+			switch d.Op {
+			case "-":
+				fmt.Fprint(w, yellow.Render("[-"+d.Text+"-]"))
+			case "+":
+				fmt.Fprint(w, green.Render("{+"+d.Text+"+}"))
+			default:
+				fmt.Fprint(w, d.Text)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func formatCitationGraphHuman(w io.Writer, g *graph.Graph, path string) error {
+	fmt.Fprintf(w, "🕸️  %s %s\n", bold.Render("Citation graph for PMID"), green.Render(g.Seed))
+	fmt.Fprintf(w, "   %s articles, %s citations\n",
+		bold.Render(fmt.Sprintf("%d", len(g.Nodes))), bold.Render(fmt.Sprintf("%d", len(g.Edges))))
+	if g.Truncated {
+		fmt.Fprintf(w, "   %s\n", yellow.Render("Truncated at --max-nodes"))
+	}
+	if path != "" {
+		fmt.Fprintf(w, "   %s %s\n", dim.Render("Written to"), path)
+	}
+	return nil
+}
+
+func formatNetworkHuman(w io.Writer, n *network.Network, path string, top int) error {
+	fmt.Fprintf(w, "🕸️  %s\n", bold.Render(networkHeading(n)))
+	if path != "" {
+		fmt.Fprintf(w, "   %s %s\n", dim.Render("Written to"), path)
+	}
+	nodes := n.Top(top)
+	if len(nodes) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\n   %s\n", labelStyle.Render("Most central "+networkNodeNoun(n)))
+	for i, node := range nodes {
+		fmt.Fprintf(w, "   %s %s\n", cyan.Render(fmt.Sprintf("%2d.", i+1)), bold.Render(networkNodeLabel(n, node)))
+		fmt.Fprintf(w, "       %s  %s\n", yellow.Render(networkNodeCount(n, node)),
+			dim.Render(fmt.Sprintf("degree %d · betweenness %.3f · PageRank %.3f", node.Degree, node.Betweenness, node.PageRank)))
+	}
+	return nil
+}
+
 func wordWrap(text string, width int) string {
 	words := strings.Fields(text)
 	if len(words) == 0 {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/i18n"
 	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
 )
 
@@ -21,7 +22,7 @@ func TestFormatSearchHuman_WithArticles(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatSearchHuman(&buf, result, articles)
+	err := formatSearchHuman(&buf, result, articles, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -45,6 +46,23 @@ func TestFormatSearchHuman_WithArticles(t *testing.T) {
 	}
 }
 
+func TestFormatSearchHuman_Suggestions(t *testing.T) {
+	result := &eutils.SearchResult{Count: 1, IDs: []string{"111"}}
+	articles := []eutils.Article{{PMID: "111", Title: "EEG Biomarkers in FXS", Year: "2024"}}
+	suggestions := []Suggestion{{Clause: `"Electroencephalography"[mh]`, Articles: 12, Sample: 20}}
+
+	var buf bytes.Buffer
+	if err := formatSearchHuman(&buf, result, articles, nil, suggestions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Consider adding:", `"Electroencephalography"[mh]`, "major topic of 12 of the top 20"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+}
+
 func TestTruncate_UTF8Safe(t *testing.T) {
 	input := "αβγδεζηθικλμ"
 	got := truncate(input, 6)
@@ -63,7 +81,7 @@ func TestFormatSearchHuman_Empty(t *testing.T) {
 	result := &eutils.SearchResult{Count: 0, IDs: []string{}}
 
 	var buf bytes.Buffer
-	err := formatSearchHuman(&buf, result, nil)
+	err := formatSearchHuman(&buf, result, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -81,7 +99,7 @@ func TestFormatSearchHuman_PMIDsOnly(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatSearchHuman(&buf, result, nil)
+	err := formatSearchHuman(&buf, result, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -114,7 +132,7 @@ func TestFormatArticlesHuman_Card(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatArticlesHuman(&buf, articles, false)
+	err := formatArticlesHuman(&buf, articles, false, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -140,6 +158,31 @@ func TestFormatArticlesHuman_Card(t *testing.T) {
 	}
 }
 
+func TestFormatArticlesHuman_Localized(t *testing.T) {
+	if err := i18n.Set("es"); err != nil {
+		t.Fatal(err)
+	}
+	defer i18n.Set("en")
+	articles := []eutils.Article{{
+		PMID:    "12345",
+		Title:   "Test Article Title",
+		Authors: []eutils.Author{{LastName: "Smith", ForeName: "John"}},
+		Journal: "Test Journal",
+	}}
+
+	var buf bytes.Buffer
+	if err := formatArticlesHuman(&buf, articles, false, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "Autores:") || !strings.Contains(out, "Revista:") {
+		t.Errorf("expected Spanish labels:\n%s", out)
+	}
+	if strings.Contains(out, "Authors:") {
+		t.Errorf("English label left in Spanish output:\n%s", out)
+	}
+}
+
 func TestFormatArticlesHuman_TruncatedAbstract(t *testing.T) {
 	longAbstract := strings.Repeat("Word ", 200) // ~1000 chars
 	articles := []eutils.Article{
@@ -152,7 +195,7 @@ func TestFormatArticlesHuman_TruncatedAbstract(t *testing.T) {
 
 	// Without --full: abstract should be truncated
 	var buf bytes.Buffer
-	err := formatArticlesHuman(&buf, articles, false)
+	err := formatArticlesHuman(&buf, articles, false, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -163,7 +206,7 @@ func TestFormatArticlesHuman_TruncatedAbstract(t *testing.T) {
 
 	// With --full: abstract should be complete
 	var bufFull bytes.Buffer
-	err = formatArticlesHuman(&bufFull, articles, true)
+	err = formatArticlesHuman(&bufFull, articles, true, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -176,7 +219,7 @@ func TestFormatArticlesHuman_TruncatedAbstract(t *testing.T) {
 
 func TestFormatArticlesHuman_Empty(t *testing.T) {
 	var buf bytes.Buffer
-	err := formatArticlesHuman(&buf, []eutils.Article{}, false)
+	err := formatArticlesHuman(&buf, []eutils.Article{}, false, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -0,0 +1,198 @@
+package output
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// PubMed records mix precomposed letters ("é") with decomposed ones ("e"
+// followed by a combining acute accent), which some formats and reference
+// managers render differently. Every output composes them first, and
+// --ascii goes on to transliterate to plain ASCII for tools that cannot
+// read UTF-8.
+
+// combining lists, for each combining mark, pairs of a base letter and the
+// precomposed letter it forms with the mark.
+var combining = map[rune]string{
+	'\u0300': "AÀEÈIÌOÒUÙaàeèiìoòuù",
+	'\u0301': "AÁEÉIÍOÓUÚYÝaáeéiíoóuúyýCĆcćNŃnńSŚsśZŹzźLĹlĺRŔrŕ",
+	'\u0302': "AÂEÊIÎOÔUÛaâeêiîoôuûCĈcĉGĜgĝHĤhĥJĴjĵSŜsŝWŴwŵYŶyŷ",
+	'\u0303': "AÃNÑOÕaãnñoõIĨiĩUŨuũ",
+	'\u0304': "AĀaāEĒeēIĪiīOŌoōUŪuū",
+	'\u0306': "AĂaăGĞgğUŬuŭ",
+	'\u0307': "CĊcċEĖeėGĠgġIİZŻzż",
+	'\u0308': "AÄEËIÏOÖUÜaäeëiïoöuüyÿYŸ",
+	'\u030a': "AÅaåUŮuů",
+	'\u030b': "OŐoőUŰuű",
+	'\u030c': "CČcčDĎdďEĚeěNŇnňRŘrřSŠsšTŤtťZŽzž",
+	'\u0327': "CÇcçSŞsşTŢtţGĢgģKĶkķLĻlļNŅnņRŖrŗ",
+	'\u0328': "AĄaąEĘeęIĮiįUŲuų",
+}
+
+var (
+	// composed maps a base letter and combining mark to the precomposed
+	// letter; base maps a precomposed letter back to its base letter.
+	composed = map[[2]rune]rune{}
+	base     = map[rune]rune{}
+)
+
+func init() {
+	for mark, pairs := range combining {
+		runes := []rune(pairs)
+		for i := 0; i+1 < len(runes); i += 2 {
+			composed[[2]rune{runes[i], mark}] = runes[i+1]
+			base[runes[i+1]] = runes[i]
+		}
+	}
+}
+
+// asciiReplacements transliterates letters without a base letter plus
+// accent, Greek letters (spelled out, as PubMed's own search does), and
+// typographic punctuation.
+var asciiReplacements = map[rune]string{
+	'ß': "ss", 'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'Ø': "O", 'ø': "o",
+	'Ł': "L", 'ł': "l", 'Đ': "D", 'đ': "d", 'Ð': "D", 'ð': "d", 'Þ': "Th", 'þ': "th", 'ı': "i",
+
+	'α': "alpha", 'β': "beta", 'γ': "gamma", 'δ': "delta", 'ε': "epsilon", 'ζ': "zeta",
+	'η': "eta", 'θ': "theta", 'ι': "iota", 'κ': "kappa", 'λ': "lambda", 'μ': "mu",
+	'ν': "nu", 'ξ': "xi", 'ο': "omicron", 'π': "pi", 'ρ': "rho", 'σ': "sigma", 'ς': "sigma",
+	'τ': "tau", 'υ': "upsilon", 'φ': "phi", 'χ': "chi", 'ψ': "psi", 'ω': "omega",
+	'Α': "Alpha", 'Β': "Beta", 'Γ': "Gamma", 'Δ': "Delta", 'Ε': "Epsilon", 'Ζ': "Zeta",
+	'Η': "Eta", 'Θ': "Theta", 'Ι': "Iota", 'Κ': "Kappa", 'Λ': "Lambda", 'Μ': "Mu",
+	'Ν': "Nu", 'Ξ': "Xi", 'Ο': "Omicron", 'Π': "Pi", 'Ρ': "Rho", 'Σ': "Sigma",
+	'Τ': "Tau", 'Υ': "Upsilon", 'Φ': "Phi", 'Χ': "Chi", 'Ψ': "Psi", 'Ω': "Omega",
+	'µ': "mu",
+
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '−': "-",
+	'‘': "'", '’': "'", '‚': "'", '′': "'", '“': `"`, '”': `"`, '„': `"`, '″': `"`,
+	'…': "...", '±': "+/-", '×': "x", '÷': "/", '≤': "<=", '≥': ">=", '≈': "~",
+	'→': "->", '←': "<-", '°': "deg", '·': ".", '•': "*",
+	'²': "2", '³': "3", '¹': "1", '½': "1/2", '¼': "1/4", '¾': "3/4",
+	'©': "(C)", '®': "(R)", '™': "(TM)",
+}
+
+// normalizeText composes accented letters and turns non-breaking and
+// other special spaces into plain ones. With ascii it also transliterates
+// to ASCII, replacing anything it cannot spell with "?".
+func normalizeText(s string, ascii bool) string {
+	if isPlainASCII(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	var prev rune = -1
+	flush := func() {
+		if prev < 0 {
+			return
+		}
+		switch {
+		case !ascii || prev < utf8.RuneSelf:
+			b.WriteRune(prev)
+		case base[prev] != 0:
+			b.WriteRune(base[prev])
+		case asciiReplacements[prev] != "":
+			b.WriteString(asciiReplacements[prev])
+		default:
+			b.WriteByte('?')
+		}
+		prev = -1
+	}
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			if c, ok := composed[[2]rune{prev, r}]; ok {
+				prev = c
+				continue
+			}
+			if ascii {
+				continue // an accent with no precomposed form is dropped
+			}
+		}
+		if unicode.Is(unicode.Zs, r) {
+			r = ' '
+		}
+		flush()
+		prev = r
+	}
+	flush()
+	return b.String()
+}
+
+func isPlainASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeArticles returns copies of articles with their text normalized
+// by normalizeText, so every format renders the same characters. The Format
+// functions apply it themselves; callers formatting articles another way,
+// such as FormatCitations, apply it first.
+func NormalizeArticles(articles []eutils.Article, ascii bool) []eutils.Article {
+	if articles == nil {
+		return nil
+	}
+	norm := func(s string) string { return normalizeText(s, ascii) }
+	normAll := func(ss []string) []string {
+		if ss == nil {
+			return nil
+		}
+		out := make([]string, len(ss))
+		for i, s := range ss {
+			out[i] = norm(s)
+		}
+		return out
+	}
+	out := make([]eutils.Article, len(articles))
+	for i, a := range articles {
+		a.Title = norm(a.Title)
+		a.Abstract = norm(a.Abstract)
+		if a.AbstractSections != nil {
+			sections := make([]eutils.AbstractSection, len(a.AbstractSections))
+			for j, s := range a.AbstractSections {
+				sections[j] = eutils.AbstractSection{Label: norm(s.Label), Text: norm(s.Text)}
+			}
+			a.AbstractSections = sections
+		}
+		if a.Authors != nil {
+			authors := make([]eutils.Author, len(a.Authors))
+			for j, au := range a.Authors {
+				authors[j] = eutils.Author{
+					LastName:       norm(au.LastName),
+					ForeName:       norm(au.ForeName),
+					Initials:       norm(au.Initials),
+					DisplayName:    norm(au.DisplayName),
+					CollectiveName: norm(au.CollectiveName),
+					Affiliation:    norm(au.Affiliation),
+				}
+			}
+			a.Authors = authors
+		}
+		a.Journal = norm(a.Journal)
+		a.JournalAbbrev = norm(a.JournalAbbrev)
+		a.BookTitle = norm(a.BookTitle)
+		a.Publisher = norm(a.Publisher)
+		a.PublisherPlace = norm(a.PublisherPlace)
+		a.CollectionTitle = norm(a.CollectionTitle)
+		a.Keywords = normAll(a.Keywords)
+		a.Concepts = normAll(a.Concepts)
+		a.Institutions = normAll(a.Institutions)
+		if a.Grants != nil {
+			grants := make([]eutils.Grant, len(a.Grants))
+			for j, g := range a.Grants {
+				grants[j] = eutils.Grant{ID: g.ID, Acronym: g.Acronym, Agency: norm(g.Agency), Country: norm(g.Country)}
+			}
+			a.Grants = grants
+		}
+		a.TLDR = norm(a.TLDR)
+		a.Notes = normAll(a.Notes)
+		a.Tags = normAll(a.Tags)
+		out[i] = a
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in, want, ascii string
+	}{
+		{"Müller", "Müller", "Muller"},
+		{"Mu\u0308ller", "Müller", "Muller"},
+		{"A\u0301lvarez", "Álvarez", "Alvarez"},
+		{"TNF-α and IL-1β", "TNF-α and IL-1β", "TNF-alpha and IL-1beta"},
+		{"Łukasz Øster", "Łukasz Øster", "Lukasz Oster"},
+		{"10\u00a0mg – 20 mg", "10 mg – 20 mg", "10 mg - 20 mg"},
+		{"q\u0323uoted", "q\u0323uoted", "quoted"},
+		{"汉字", "汉字", "??"},
+		{"plain", "plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := normalizeText(tt.in, false); got != tt.want {
+			t.Errorf("normalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := normalizeText(tt.in, true); got != tt.ascii {
+			t.Errorf("normalizeText(%q, ascii) = %q, want %q", tt.in, got, tt.ascii)
+		}
+	}
+}
+
+func TestNormalizeArticles_SameTextInEveryFormat(t *testing.T) {
+	articles := []eutils.Article{{
+		PMID:    "1",
+		Title:   "Étude of IL-1β",
+		Authors: []eutils.Author{{LastName: "Müller", ForeName: "José"}},
+		Year:    "2024",
+	}}
+	dir := t.TempDir()
+	cfg := OutputConfig{
+		CSVFile:    filepath.Join(dir, "out.csv"),
+		RISFile:    filepath.Join(dir, "out.ris"),
+		BibTeXFile: filepath.Join(dir, "out.bib"),
+		ASCII:      true,
+	}
+	var buf bytes.Buffer
+	if err := FormatArticles(&buf, articles, cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{cfg.CSVFile, cfg.RISFile, cfg.BibTeXFile} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := string(data)
+		if !strings.Contains(got, "Etude of IL-1beta") || !strings.Contains(got, "Muller") {
+			t.Errorf("%s not transliterated:\n%s", filepath.Base(path), got)
+		}
+		if !isPlainASCII(got) {
+			t.Errorf("%s has non-ASCII text:\n%s", filepath.Base(path), got)
+		}
+	}
+	if articles[0].Title != "Étude of IL-1β" {
+		t.Error("NormalizeArticles modified the caller's articles")
+	}
+}
+
+func TestBibTeXKey_Transliterated(t *testing.T) {
+	a := eutils.Article{PMID: "1", Year: "2024", Title: "Über Ängste", Authors: []eutils.Author{{LastName: "Müller"}}}
+	if got := bibtexKey(a); got != "muller2024uber" {
+		t.Errorf("bibtexKey = %q, want muller2024uber", got)
+	}
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// OutFormats lists the formats --out can write, in help order.
+var OutFormats = []string{"json", "ndjson", "csv", "tsv", "xlsx", "ris", "bibtex", "csl-json"}
+
+// outExtensions maps file extensions to the format --out infers from them.
+var outExtensions = map[string]string{
+	".json":   "json",
+	".ndjson": "ndjson",
+	".jsonl":  "ndjson",
+	".csv":    "csv",
+	".tsv":    "tsv",
+	".tab":    "tsv",
+	".xlsx":   "xlsx",
+	".ris":    "ris",
+	".bib":    "bibtex",
+	".bibtex": "bibtex",
+}
+
+// OutFormat resolves the format for an --out path: the override when given
+// (--out-format), otherwise the path's extension.
+func OutFormat(path, override string) (string, error) {
+	if override != "" {
+		format := strings.ToLower(override)
+		for _, f := range OutFormats {
+			if f == format {
+				return format, nil
+			}
+		}
+		return "", fmt.Errorf("unknown format %q (use %s)", override, strings.Join(OutFormats, ", "))
+	}
+
+	// CSL-JSON shares .json with plain JSON, so it needs the longer suffix.
+	if strings.HasSuffix(strings.ToLower(path), ".csl.json") {
+		return "csl-json", nil
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if format, ok := outExtensions[ext]; ok {
+		return format, nil
+	}
+	if ext == "" {
+		return "", fmt.Errorf("cannot infer a format for %q without an extension; set --out-format", path)
+	}
+	return "", fmt.Errorf("cannot infer a format from %q; set --out-format (%s)", ext, strings.Join(OutFormats, ", "))
+}
@@ -0,0 +1,41 @@
+package output
+
+import "testing"
+
+func TestOutFormat(t *testing.T) {
+	tests := []struct {
+		path, override, want string
+	}{
+		{"results.json", "", "json"},
+		{"results.JSONL", "", "ndjson"},
+		{"refs.csv", "", "csv"},
+		{"refs.tsv", "", "tsv"},
+		{"refs.xlsx", "", "xlsx"},
+		{"refs.ris", "", "ris"},
+		{"refs.bib", "", "bibtex"},
+		{"refs.csl.json", "", "csl-json"},
+		{"refs.txt", "CSV", "csv"},
+		{"refs", "bibtex", "bibtex"},
+		{"refs.csv", "ris", "ris"},
+	}
+	for _, tt := range tests {
+		got, err := OutFormat(tt.path, tt.override)
+		if err != nil {
+			t.Errorf("OutFormat(%q, %q): unexpected error: %v", tt.path, tt.override, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("OutFormat(%q, %q) = %q, want %q", tt.path, tt.override, got, tt.want)
+		}
+	}
+
+	for _, bad := range []struct{ path, override string }{
+		{"refs.txt", ""},
+		{"refs", ""},
+		{"refs.csv", "docx"},
+	} {
+		if _, err := OutFormat(bad.path, bad.override); err == nil {
+			t.Errorf("OutFormat(%q, %q): expected error", bad.path, bad.override)
+		}
+	}
+}
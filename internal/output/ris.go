@@ -3,6 +3,7 @@ package output
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -17,44 +18,77 @@ func writeArticlesRIS(path string, articles []eutils.Article) error {
 	}
 	defer f.Close()
 
-	w := bufio.NewWriter(f)
-	for i, a := range articles {
-		writeRISTag(w, "TY", "JOUR")
-		writeRISTag(w, "TI", a.Title)
+	if err := writeRIS(f, articles); err != nil {
+		return err
+	}
+	return f.Close()
+}
 
-		for _, au := range a.Authors {
-			writeRISTag(w, "AU", risAuthor(au))
+// writeRIS writes RIS records for articles to out.
+func writeRIS(out io.Writer, articles []eutils.Article) error {
+	w := bufio.NewWriter(out)
+	for i, a := range articles {
+		if i > 0 {
+			w.WriteString("\n")
 		}
+		writeRISRecord(w, a)
+	}
 
-		writeRISTag(w, "PY", a.Year)
-		writeRISTag(w, "JO", a.Journal)
-		writeRISTag(w, "VL", a.Volume)
-		writeRISTag(w, "IS", a.Issue)
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing RIS output: %w", err)
+	}
 
-		startPage, endPage := splitPages(a.Pages)
-		writeRISTag(w, "SP", startPage)
-		writeRISTag(w, "EP", endPage)
+	return nil
+}
 
-		writeRISTag(w, "DO", a.DOI)
-		writeRISTag(w, "AB", a.Abstract)
-		if a.PMID != "" {
-			writeRISTag(w, "ID", "PMID:"+a.PMID)
-			writeRISTag(w, "UR", "https://pubmed.ncbi.nlm.nih.gov/"+a.PMID+"/")
-		}
-		writeRISTag(w, "ER", "")
+// writeRISRecord writes one article's RIS record.
+func writeRISRecord(w *bufio.Writer, a eutils.Article) {
+	writeRISTag(w, "TY", risType(a))
+	writeRISTag(w, "TI", a.Title)
 
-		if i < len(articles)-1 {
-			if _, err := w.WriteString("\n"); err != nil {
-				return fmt.Errorf("writing RIS separator: %w", err)
-			}
-		}
+	for _, au := range a.Authors {
+		writeRISTag(w, "AU", risAuthor(au))
 	}
 
-	if err := w.Flush(); err != nil {
-		return fmt.Errorf("flushing RIS output: %w", err)
+	writeRISTag(w, "PY", a.Year)
+	writeRISTag(w, "JO", a.Journal)
+	writeRISTag(w, "T2", a.BookTitle)
+	writeRISTag(w, "T3", a.CollectionTitle)
+	writeRISTag(w, "PB", a.Publisher)
+	writeRISTag(w, "CY", a.PublisherPlace)
+	writeRISTag(w, "VL", a.Volume)
+	writeRISTag(w, "IS", a.Issue)
+
+	startPage, endPage := splitPages(a.Pages)
+	writeRISTag(w, "SP", startPage)
+	writeRISTag(w, "EP", endPage)
+
+	writeRISTag(w, "DO", a.DOI)
+	writeRISTag(w, "AB", a.Abstract)
+	for _, tag := range a.Tags {
+		writeRISTag(w, "KW", tag)
+	}
+	for _, n := range a.Notes {
+		writeRISTag(w, "N1", n)
 	}
+	if a.PMID != "" {
+		writeRISTag(w, "ID", "PMID:"+a.PMID)
+		writeRISTag(w, "UR", "https://pubmed.ncbi.nlm.nih.gov/"+a.PMID+"/")
+	}
+	writeRISTag(w, "L1", a.OAURL)
+	writeRISTag(w, "ER", "")
+}
 
-	return nil
+// risType is the RIS reference type: CHAP or BOOK for Bookshelf records,
+// JOUR otherwise.
+func risType(a eutils.Article) string {
+	switch {
+	case a.BookTitle != "":
+		return "CHAP"
+	case a.IsBook():
+		return "BOOK"
+	}
+	return "JOUR"
 }
 
 func writeRISTag(w *bufio.Writer, tag, value string) {
@@ -28,6 +28,9 @@ func TestWriteArticlesRIS(t *testing.T) {
 			Issue:   "3",
 			Pages:   "101-110",
 			DOI:     "10.1000/example",
+			Tags:    []string{"key-paper"},
+			Notes:   []string{"Check the supplement"},
+			OAURL:   "https://repo.example.org/a.pdf",
 		},
 	}
 
@@ -54,8 +57,11 @@ func TestWriteArticlesRIS(t *testing.T) {
 		"EP  - 110",
 		"DO  - 10.1000/example",
 		"AB  - Line one. Line two.",
+		"KW  - key-paper",
+		"N1  - Check the supplement",
 		"ID  - PMID:38000001",
 		"UR  - https://pubmed.ncbi.nlm.nih.gov/38000001/",
+		"L1  - https://repo.example.org/a.pdf",
 		"ER  -",
 	}
 	for _, want := range expected {
@@ -0,0 +1,153 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// SchemaVersion versions the --json and --ndjson output of search, fetch,
+// and the link commands. A minor bump adds optional fields; a major bump
+// removes, renames, or retypes a field. See docs/json-schema.md.
+const SchemaVersion = "1.13"
+
+// versionedSearch is the --json document for search.
+type versionedSearch struct {
+	SchemaVersion string `json:"schema_version"`
+	*eutils.SearchResult
+}
+
+// versionedArticle is one article in fetch --json and --ndjson output.
+type versionedArticle struct {
+	SchemaVersion string `json:"schema_version"`
+	eutils.Article
+}
+
+// versionedLinks is the --json document for cited-by, references, and related.
+type versionedLinks struct {
+	SchemaVersion string `json:"schema_version"`
+	*eutils.LinkResult
+}
+
+func versionArticles(articles []eutils.Article) []versionedArticle {
+	out := make([]versionedArticle, len(articles))
+	for i, a := range articles {
+		out[i] = versionedArticle{SchemaVersion: SchemaVersion, Article: a}
+	}
+	return out
+}
+
+// schemaDoc describes one documented output.
+type schemaDoc struct {
+	Name        string
+	Description string
+	Value       interface{}
+}
+
+var schemaDocs = []schemaDoc{
+	{"search", "search --json: result count, PMIDs, and query translation", versionedSearch{}},
+	{"fetch", "fetch --json: an array of articles (each --ndjson line is one article)", []versionedArticle{}},
+	{"links", "cited-by, references, and related --json: linked PMIDs with scores", versionedLinks{}},
+	{"search-ndjson", "search --ndjson without article details: one PMID per line", ndjsonPMID{}},
+	{"links-ndjson", "cited-by, references, and related --ndjson: one link per line", ndjsonLink{}},
+}
+
+// SchemaNames lists the documented outputs with a one-line description each.
+func SchemaNames() [][2]string {
+	out := make([][2]string, len(schemaDocs))
+	for i, d := range schemaDocs {
+		out[i] = [2]string{d.Name, d.Description}
+	}
+	return out
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) for a documented output.
+// Schemas are derived from the Go types, so they cannot drift from the JSON
+// they describe.
+func JSONSchema(name string) ([]byte, error) {
+	for _, d := range schemaDocs {
+		if d.Name != name {
+			continue
+		}
+		doc := typeSchema(reflect.TypeOf(d.Value))
+		doc["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+		doc["$id"] = fmt.Sprintf("https://github.com/drpedapati/pubmed-cli/schema/%s.json", d.Name)
+		doc["title"] = "pubmed " + d.Name
+		doc["description"] = d.Description
+		doc["version"] = SchemaVersion
+		return json.MarshalIndent(doc, "", "  ")
+	}
+	names := make([]string, len(schemaDocs))
+	for i, d := range schemaDocs {
+		names[i] = d.Name
+	}
+	return nil, fmt.Errorf("unknown schema %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// typeSchema maps a Go type to a JSON Schema fragment. Fields without
+// omitempty are required; objects allow additional properties so that
+// minor-version additions stay compatible.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		props := make(map[string]interface{})
+		var required []string
+		collectFields(t, props, &required)
+		sort.Strings(required)
+		s := map[string]interface{}{"type": "object", "properties": props}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func collectFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, props, required)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = typeSchema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// TestJSONSchema_Golden fails whenever an output struct changes. Bump
+// SchemaVersion as described in docs/json-schema.md, then regenerate with
+// UPDATE_SCHEMA=1 go test ./internal/output/.
+func TestJSONSchema_Golden(t *testing.T) {
+	for _, entry := range SchemaNames() {
+		name := entry[0]
+		got, err := JSONSchema(name)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		got = append(got, '\n')
+
+		path := filepath.Join("..", "..", "testdata", "schema", name+".json")
+		if os.Getenv("UPDATE_SCHEMA") != "" {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: reading golden schema: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s schema changed; bump SchemaVersion per docs/json-schema.md and run UPDATE_SCHEMA=1 go test ./internal/output/", name)
+		}
+	}
+}
+
+func TestJSONSchema_Fetch(t *testing.T) {
+	data, err := JSONSchema("fetch")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc struct {
+		Type  string `json:"type"`
+		Items struct {
+			Properties map[string]interface{} `json:"properties"`
+			Required   []string               `json:"required"`
+		} `json:"items"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if doc.Type != "array" || doc.Version != SchemaVersion {
+		t.Errorf("expected versioned array schema, got type=%q version=%q", doc.Type, doc.Version)
+	}
+	for _, field := range []string{"schema_version", "pmid", "title", "mesh_terms"} {
+		if _, ok := doc.Items.Properties[field]; !ok {
+			t.Errorf("expected property %q in article schema", field)
+		}
+	}
+	for _, field := range doc.Items.Required {
+		if field == "doi" {
+			t.Error("omitempty fields such as doi should not be required")
+		}
+	}
+
+	if _, err := JSONSchema("synth"); err == nil {
+		t.Error("expected unknown schema to be rejected")
+	}
+}
+
+func TestFormatArticles_JSONHasSchemaVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := FormatArticles(&buf, []eutils.Article{{PMID: "1"}}, OutputConfig{JSON: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if parsed[0]["schema_version"] != SchemaVersion || parsed[0]["pmid"] != "1" {
+		t.Errorf("expected versioned article, got %v", parsed[0])
+	}
+}
@@ -0,0 +1,172 @@
+package output
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// ArticleStream writes articles to the configured exports batch by batch,
+// so exports too large to hold in memory can be written as they are
+// fetched. Only formats that can be appended to stream: NDJSON on the
+// output writer, and CSV, TSV, RIS, and BibTeX files.
+type ArticleStream struct {
+	ndjson *json.Encoder
+	tables []tableWriter
+	ris    *bufio.Writer
+	bibtex *bufio.Writer
+	keys   map[string]int
+	files  []*os.File
+	cols   []articleColumn
+	row    []string
+	ascii  bool
+	count  int
+}
+
+// StreamableConfig reports why cfg cannot be streamed, or nil if it can.
+func StreamableConfig(cfg OutputConfig) error {
+	switch {
+	case cfg.JSON:
+		return fmt.Errorf("JSON output cannot be streamed; use --ndjson")
+	case cfg.XLSXFile != "":
+		return fmt.Errorf("Excel workbooks cannot be streamed; use CSV or TSV")
+	case cfg.CSLFile != "":
+		return fmt.Errorf("CSL-JSON cannot be streamed; use RIS, BibTeX, or NDJSON")
+	case !cfg.NDJSON && cfg.CSVFile == "" && cfg.TSVFile == "" && cfg.RISFile == "" && cfg.BibTeXFile == "":
+		return fmt.Errorf("nothing to stream to; use --ndjson or a CSV, TSV, RIS, or BibTeX export")
+	}
+	return nil
+}
+
+// NewArticleStream creates the export files in cfg and writes NDJSON to w
+// when cfg.NDJSON is set.
+func NewArticleStream(w io.Writer, cfg OutputConfig) (*ArticleStream, error) {
+	if err := StreamableConfig(cfg); err != nil {
+		return nil, err
+	}
+	s := &ArticleStream{keys: make(map[string]int), ascii: cfg.ASCII}
+	if cfg.NDJSON {
+		s.ndjson = json.NewEncoder(w)
+		s.ndjson.SetEscapeHTML(false)
+	}
+	create := func(path, name string) (*os.File, error) {
+		f, err := os.Create(path)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("creating %s file: %w", name, err)
+		}
+		s.files = append(s.files, f)
+		return f, nil
+	}
+
+	columns := cfg.Columns
+	if len(columns) == 0 {
+		columns = defaultArticlesColumns
+	}
+	s.cols = selectArticleColumns(columns)
+	s.row = make([]string, len(s.cols))
+	header := make([]string, len(s.cols))
+	for i, c := range s.cols {
+		header[i] = c.Header
+	}
+	if cfg.CSVFile != "" {
+		f, err := create(cfg.CSVFile, "CSV")
+		if err != nil {
+			return nil, err
+		}
+		s.tables = append(s.tables, csv.NewWriter(f))
+	}
+	if cfg.TSVFile != "" {
+		f, err := create(cfg.TSVFile, "TSV")
+		if err != nil {
+			return nil, err
+		}
+		s.tables = append(s.tables, newTSVWriter(f))
+	}
+	for _, t := range s.tables {
+		t.Write(header)
+	}
+	if cfg.RISFile != "" {
+		f, err := create(cfg.RISFile, "RIS")
+		if err != nil {
+			return nil, err
+		}
+		s.ris = bufio.NewWriter(f)
+	}
+	if cfg.BibTeXFile != "" {
+		f, err := create(cfg.BibTeXFile, "BibTeX")
+		if err != nil {
+			return nil, err
+		}
+		s.bibtex = bufio.NewWriter(f)
+	}
+	return s, nil
+}
+
+// Write appends articles to every export.
+func (s *ArticleStream) Write(articles []eutils.Article) error {
+	articles = NormalizeArticles(articles, s.ascii)
+	for _, a := range articles {
+		if s.ndjson != nil {
+			if err := s.ndjson.Encode(versionedArticle{SchemaVersion: SchemaVersion, Article: a}); err != nil {
+				return err
+			}
+		}
+		// The table writers copy each row out, so one slice serves them all.
+		for i, c := range s.cols {
+			s.row[i] = c.Value(a)
+		}
+		for _, t := range s.tables {
+			t.Write(s.row)
+		}
+		if s.ris != nil {
+			if s.count > 0 {
+				s.ris.WriteString("\n")
+			}
+			writeRISRecord(s.ris, a)
+		}
+		if s.bibtex != nil {
+			if s.count > 0 {
+				s.bibtex.WriteString("\n")
+			}
+			writeBibTeXEntry(s.bibtex, a, s.keys)
+		}
+		s.count++
+	}
+	for _, t := range s.tables {
+		t.Flush()
+		if err := t.Error(); err != nil {
+			return fmt.Errorf("writing table export: %w", err)
+		}
+	}
+	return nil
+}
+
+// Count returns how many articles have been written.
+func (s *ArticleStream) Count() int {
+	return s.count
+}
+
+// Close flushes and closes the export files.
+func (s *ArticleStream) Close() error {
+	var first error
+	for _, w := range []*bufio.Writer{s.ris, s.bibtex} {
+		if w != nil {
+			if err := w.Flush(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	for _, f := range s.files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	s.files = nil
+	return first
+}
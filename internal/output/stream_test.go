@@ -0,0 +1,90 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestArticleStream_MatchesWholeExport(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", Title: "Fragile X syndrome", Authors: []eutils.Author{{LastName: "Hunter", ForeName: "Jane"}}, Journal: "J Test", Year: "2020"},
+		{PMID: "2", Title: "Fragile X carriers", Authors: []eutils.Author{{LastName: "Hunter", ForeName: "Jane"}}, Journal: "J Test", Year: "2020"},
+		{PMID: "3", Title: "Autism", Authors: []eutils.Author{{LastName: "Smith", ForeName: "Ann"}}, Journal: "J Test", Year: "2021"},
+	}
+	dir := t.TempDir()
+	cfg := OutputConfig{
+		NDJSON:     true,
+		CSVFile:    filepath.Join(dir, "out.csv"),
+		RISFile:    filepath.Join(dir, "out.ris"),
+		BibTeXFile: filepath.Join(dir, "out.bib"),
+	}
+	var ndjson bytes.Buffer
+	s, err := NewArticleStream(&ndjson, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two batches, with the BibTeX key collision split across them.
+	if err := s.Write(articles[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(articles[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Count() != 3 {
+		t.Errorf("Count = %d, want 3", s.Count())
+	}
+	if lines := strings.Split(strings.TrimSpace(ndjson.String()), "\n"); len(lines) != 3 {
+		t.Errorf("got %d NDJSON lines, want 3", len(lines))
+	}
+
+	var ris, bib bytes.Buffer
+	if err := writeRIS(&ris, articles); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBibTeX(&bib, articles); err != nil {
+		t.Fatal(err)
+	}
+	var table bytes.Buffer
+	cw := csv.NewWriter(&table)
+	writeArticlesRows(cw, articles, nil)
+	cw.Flush()
+
+	for path, want := range map[string]string{
+		cfg.RISFile:    ris.String(),
+		cfg.BibTeXFile: bib.String(),
+		cfg.CSVFile:    table.String(),
+	} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s differs from the whole export:\n%s\nwant:\n%s", filepath.Base(path), got, want)
+		}
+	}
+}
+
+func TestStreamableConfig(t *testing.T) {
+	for _, cfg := range []OutputConfig{
+		{JSON: true},
+		{NDJSON: true, XLSXFile: "out.xlsx"},
+		{NDJSON: true, CSLFile: "out.json"},
+		{},
+	} {
+		if StreamableConfig(cfg) == nil {
+			t.Errorf("StreamableConfig(%+v) = nil, want error", cfg)
+		}
+	}
+	if err := StreamableConfig(OutputConfig{CSVFile: "out.csv"}); err != nil {
+		t.Errorf("StreamableConfig(CSV) = %v", err)
+	}
+}
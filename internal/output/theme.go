@@ -0,0 +1,174 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/muesli/termenv"
+)
+
+// Themes accepted by StyleOptions.Theme.
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// StyleOptions controls how --human output is rendered.
+type StyleOptions struct {
+	Color bool   // ANSI colors and text attributes (off for NO_COLOR / --no-color)
+	ASCII bool   // replace emoji and box-drawing glyphs with plain ASCII
+	Theme string // ThemeDark (default) or ThemeLight
+}
+
+// palette holds the ANSI colors for one theme.
+type palette struct {
+	accent, success, warning, highlight, label, border lipgloss.Color
+}
+
+var palettes = map[string]palette{
+	// The 16-color defaults read well on dark backgrounds.
+	ThemeDark: {accent: "6", success: "2", warning: "3", highlight: "5", label: "4", border: "8"},
+	// Darker 256-color shades stay legible on light backgrounds, where
+	// bright cyan and yellow wash out.
+	ThemeLight: {accent: "30", success: "28", warning: "130", highlight: "90", label: "25", border: "245"},
+}
+
+// Styles shared by the human formatters.
+var (
+	cyan        lipgloss.Style
+	bold        lipgloss.Style
+	dim         lipgloss.Style
+	green       lipgloss.Style
+	yellow      lipgloss.Style
+	magenta     lipgloss.Style
+	labelStyle  lipgloss.Style
+	boxStyle    lipgloss.Style
+	borderStyle lipgloss.Style
+	headerStyle lipgloss.Style
+
+	highlightStyle lipgloss.Style
+	plain          lipgloss.Style
+
+	asciiOutput bool
+)
+
+// detectedProfile is the terminal's color profile before any override, so
+// ConfigureStyles can turn color back on.
+var detectedProfile = lipgloss.ColorProfile()
+
+func init() {
+	applyPalette(palettes[ThemeDark])
+}
+
+// ConfigureStyles applies opts to all subsequent human output.
+func ConfigureStyles(opts StyleOptions) error {
+	theme := opts.Theme
+	if theme == "" {
+		theme = ThemeDark
+	}
+	p, ok := palettes[theme]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (use %s or %s)", opts.Theme, ThemeDark, ThemeLight)
+	}
+
+	if opts.Color {
+		lipgloss.SetColorProfile(detectedProfile)
+	} else {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	asciiOutput = opts.ASCII
+	applyPalette(p)
+	return nil
+}
+
+func applyPalette(p palette) {
+	cyan = lipgloss.NewStyle().Foreground(p.accent)
+	bold = lipgloss.NewStyle().Bold(true)
+	dim = lipgloss.NewStyle().Faint(true)
+	green = lipgloss.NewStyle().Foreground(p.success)
+	yellow = lipgloss.NewStyle().Foreground(p.warning)
+	magenta = lipgloss.NewStyle().Foreground(p.highlight)
+	labelStyle = lipgloss.NewStyle().Bold(true).Foreground(p.label)
+	borderStyle = lipgloss.NewStyle().Foreground(p.border)
+	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(p.label)
+	highlightStyle = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(p.warning)
+	plain = lipgloss.NewStyle()
+	boxStyle = lipgloss.NewStyle().
+		Border(tableBorder()).
+		BorderForeground(p.accent).
+		Padding(0, 1)
+}
+
+// tableBorder is the border for tables and boxes: box-drawing lines, or
+// +, -, and | in ASCII mode.
+func tableBorder() lipgloss.Border {
+	if asciiOutput {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.NormalBorder()
+}
+
+// headerStyleFunc styles a table's header row.
+func headerStyleFunc(row, col int) lipgloss.Style {
+	if row == table.HeaderRow {
+		return headerStyle
+	}
+	return lipgloss.NewStyle()
+}
+
+// asciiGlyphs maps the symbols used by the human formatters to ASCII.
+var asciiGlyphs = strings.NewReplacer(
+	"…", "...",
+	"·", "-",
+	"—", "-",
+	"–", "-",
+	"→", "->",
+	"←", "<-",
+	"▶", ">",
+	"├─", "|-",
+	"└─", "`-",
+	"├", "|",
+	"└", "`",
+	"│", "|",
+	"─", "-",
+	"█", "#",
+	"▁", "_",
+	"▂", ".",
+	"▃", ":",
+	"▄", "-",
+	"▅", "=",
+	"▆", "+",
+	"▇", "*",
+	"✓", "+",
+	"✗", "x",
+	"⚠", "!",
+)
+
+// emojiRe matches pictographs (with an optional variation selector) and the
+// spacing that follows them, which have no ASCII rendering worth keeping.
+var emojiRe = regexp.MustCompile(`[\x{1F300}-\x{1FAFF}\x{2600}-\x{26FF}]\x{FE0F}? *`)
+
+// humanWriter wraps w for human output, transliterating symbols to ASCII
+// when ASCII mode is on (e.g. TERM=dumb).
+func humanWriter(w io.Writer) io.Writer {
+	if !asciiOutput {
+		return w
+	}
+	return asciiWriter{w}
+}
+
+type asciiWriter struct {
+	w io.Writer
+}
+
+func (a asciiWriter) Write(p []byte) (int, error) {
+	s := emojiRe.ReplaceAllString(asciiGlyphs.Replace(string(p)), "")
+	if _, err := io.WriteString(a.w, s); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+)
+
+func TestConfigureStyles_NoColor(t *testing.T) {
+	if err := ConfigureStyles(StyleOptions{Color: false}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { ConfigureStyles(StyleOptions{Color: true}) })
+
+	var buf bytes.Buffer
+	result := &eutils.SearchResult{Count: 1, IDs: []string{"111"}}
+	if err := FormatSearchResult(&buf, result, nil, OutputConfig{Human: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no ANSI escapes with color off, got %q", buf.String())
+	}
+}
+
+func TestConfigureStyles_ASCII(t *testing.T) {
+	if err := ConfigureStyles(StyleOptions{Color: false, ASCII: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { ConfigureStyles(StyleOptions{Color: true}) })
+
+	h := &mesh.Hierarchy{
+		Record: &mesh.MeSHRecord{UI: "D019954", Name: "Neurobehavioral Manifestations"},
+		Paths: []mesh.TreePath{{
+			TreeNumber: "C10.597.606",
+			Ancestors:  []mesh.TreeNode{{UI: "D009422", Name: "Nervous System Diseases", TreeNumber: "C10"}},
+			Children:   []mesh.TreeNode{{UI: "D060825", Name: "Cognitive Dysfunction", TreeNumber: "C10.597.606.150"}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatMeSHTree(&buf, h, OutputConfig{Human: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for i, r := range out {
+		if r > 127 {
+			t.Fatalf("expected ASCII-only output, found %q at %d in:\n%s", r, i, out)
+		}
+	}
+	if !strings.Contains(out, "Cognitive Dysfunction") {
+		t.Errorf("expected tree content to survive transliteration, got:\n%s", out)
+	}
+}
+
+func TestConfigureStyles_UnknownTheme(t *testing.T) {
+	if err := ConfigureStyles(StyleOptions{Color: true, Theme: "solarized"}); err == nil {
+		t.Error("expected unknown theme to be rejected")
+	}
+	if err := ConfigureStyles(StyleOptions{Color: true, Theme: ThemeLight}); err != nil {
+		t.Errorf("expected light theme to be accepted, got: %v", err)
+	}
+	ConfigureStyles(StyleOptions{Color: true})
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestExportTables_XLSX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "refs.xlsx")
+	articles := []eutils.Article{{PMID: "111", Title: "Tics & <Tourette>", Year: "2024"}}
+
+	err := exportTables(OutputConfig{XLSXFile: path}, func(w tableWriter) {
+		writeArticlesRows(w, articles, []string{"pmid", "title", "year"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("not a zip archive: %v", err)
+	}
+	defer zr.Close()
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing workbook part %s", name)
+		}
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">PMID</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">111</t></is></c>`,
+		`Tics &amp; &lt;Tourette&gt;`,
+		`<c r="C2"`,
+		`</sheetData></worksheet>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet missing %q:\n%s", want, sheet)
+		}
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumn(i); got != want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
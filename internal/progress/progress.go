@@ -0,0 +1,177 @@
+// Package progress reports how far a long operation has got: a bar (or a
+// spinner, when the total is unknown) redrawn in place on a terminal, and
+// periodic logfmt lines otherwise, so a fetch of thousands of records is
+// not mistaken for a hang in a terminal or a CI log.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogInterval is how often a Reporter writing to a log prints a line.
+const LogInterval = 5 * time.Second
+
+// redrawInterval limits how often a bar is redrawn.
+const redrawInterval = 100 * time.Millisecond
+
+const barWidth = 24
+
+var spinner = []string{"|", "/", "-", `\`}
+
+// Reporter reports the progress of one operation. A nil *Reporter reports
+// nothing, so callers need not check whether progress is wanted.
+type Reporter struct {
+	w     io.Writer
+	label string
+	tty   bool
+	now   func() time.Time
+
+	mu      sync.Mutex
+	total   int
+	done    int
+	start   time.Time
+	last    time.Time
+	frame   int
+	width   int
+	drawn   bool
+	stopped bool
+}
+
+// New starts reporting the progress of the operation label through total
+// units to w, redrawing a bar when tty is set and printing a line every
+// LogInterval otherwise. A total of 0 or less means it is not known.
+func New(w io.Writer, label string, total int, tty bool) *Reporter {
+	return newReporter(w, label, total, tty, time.Now)
+}
+
+func newReporter(w io.Writer, label string, total int, tty bool, now func() time.Time) *Reporter {
+	start := now()
+	return &Reporter{w: w, label: label, total: total, tty: tty, now: now, start: start, last: start}
+}
+
+// SetTotal changes the number of units, for an operation whose size is
+// learned only once it has begun.
+func (r *Reporter) SetTotal(total int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total = total
+}
+
+// Add records n more units done.
+func (r *Reporter) Add(n int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done += n
+	r.update()
+}
+
+// Set records that done units are complete, as when an operation resumes
+// partway through.
+func (r *Reporter) Set(done int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done = done
+	r.update()
+}
+
+// Done finishes the report: the bar is drawn a last time and its line
+// ended, or a final log line is printed. Later calls do nothing.
+func (r *Reporter) Done() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+	r.stopped = true
+	now := r.now()
+	if r.tty {
+		r.draw(now)
+		fmt.Fprintln(r.w)
+		return
+	}
+	r.log(now)
+}
+
+// update redraws the bar, or prints a log line, if it is time to.
+func (r *Reporter) update() {
+	if r.stopped {
+		return
+	}
+	now := r.now()
+	if r.tty {
+		if now.Sub(r.last) >= redrawInterval || !r.drawn {
+			r.last, r.drawn = now, true
+			r.draw(now)
+			r.frame++
+		}
+		return
+	}
+	if now.Sub(r.last) >= LogInterval {
+		r.last = now
+		r.log(now)
+	}
+}
+
+// draw writes the bar over the current line, padding it to hide the end
+// of a longer earlier one.
+func (r *Reporter) draw(now time.Time) {
+	var line string
+	if r.total > 0 {
+		filled := min(barWidth, barWidth*r.done/r.total)
+		line = fmt.Sprintf("%s [%s%s] %d/%d %3d%%", r.label,
+			strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled),
+			r.done, r.total, r.percent())
+		if eta, ok := r.eta(now); ok {
+			line += " ETA " + eta.String()
+		}
+	} else {
+		line = fmt.Sprintf("%s %s %d", r.label, spinner[r.frame%len(spinner)], r.done)
+	}
+	pad := max(0, r.width-len(line))
+	r.width = len(line)
+	fmt.Fprintf(r.w, "\r%s%s", line, strings.Repeat(" ", pad))
+}
+
+// log prints one logfmt line of the operation's state.
+func (r *Reporter) log(now time.Time) {
+	elapsed := now.Sub(r.start).Round(time.Second)
+	line := fmt.Sprintf("progress task=%q done=%d", r.label, r.done)
+	if r.total > 0 {
+		line += fmt.Sprintf(" total=%d percent=%d", r.total, r.percent())
+	}
+	line += fmt.Sprintf(" elapsed=%s", elapsed)
+	if eta, ok := r.eta(now); ok && !r.stopped {
+		line += fmt.Sprintf(" eta=%s", eta)
+	}
+	fmt.Fprintln(r.w, line)
+}
+
+func (r *Reporter) percent() int {
+	return min(100, 100*r.done/r.total)
+}
+
+// eta estimates the time left from the rate so far.
+func (r *Reporter) eta(now time.Time) (time.Duration, bool) {
+	elapsed := now.Sub(r.start)
+	if r.total <= 0 || r.done <= 0 || r.done >= r.total || elapsed <= 0 {
+		return 0, false
+	}
+	left := time.Duration(float64(elapsed) * float64(r.total-r.done) / float64(r.done))
+	return left.Round(time.Second), true
+}
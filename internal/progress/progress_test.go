@@ -0,0 +1,84 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source advanced by the tests.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func TestReporter_Log(t *testing.T) {
+	c := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var buf bytes.Buffer
+	r := newReporter(&buf, "Fetching articles", 1000, false, c.now)
+
+	r.Add(200)
+	if buf.Len() != 0 {
+		t.Fatalf("logged before the interval: %q", buf.String())
+	}
+	c.t = c.t.Add(LogInterval)
+	r.Add(300)
+	c.t = c.t.Add(5 * time.Second)
+	r.Add(500)
+	r.Done()
+	r.Done()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		`progress task="Fetching articles" done=500 total=1000 percent=50 elapsed=5s eta=5s`,
+		`progress task="Fetching articles" done=1000 total=1000 percent=100 elapsed=10s`,
+		`progress task="Fetching articles" done=1000 total=1000 percent=100 elapsed=10s`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestReporter_Bar(t *testing.T) {
+	c := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var buf bytes.Buffer
+	r := newReporter(&buf, "Fetching", 4, true, c.now)
+
+	c.t = c.t.Add(time.Second)
+	r.Add(1)
+	r.Add(1) // within the redraw interval
+	if got := strings.Count(buf.String(), "\r"); got != 1 {
+		t.Errorf("drew %d times, want 1: %q", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Fetching [======                  ] 1/4  25% ETA 3s") {
+		t.Errorf("bar = %q", buf.String())
+	}
+
+	r.Done()
+	out := buf.String()
+	if !strings.HasSuffix(out, "\n") || !strings.Contains(out, "2/4  50%") {
+		t.Errorf("final bar = %q", out)
+	}
+}
+
+func TestReporter_Spinner(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(&buf, "Checking", 0, true)
+	r.Add(3)
+	if !strings.Contains(buf.String(), "Checking | 3") {
+		t.Errorf("spinner = %q", buf.String())
+	}
+}
+
+func TestReporter_Nil(t *testing.T) {
+	var r *Reporter
+	r.SetTotal(10)
+	r.Add(1)
+	r.Set(5)
+	r.Done()
+}
@@ -0,0 +1,38 @@
+package query
+
+import "strings"
+
+// maxClassMembers caps how many member substances ActionClause lists by name.
+const maxClassMembers = 25
+
+// MeSHTerm builds a MeSH field clause such as
+// "Fragile X Syndrome/drug therapy"[mh]. An empty qualifier searches the
+// descriptor alone; major restricts to articles where it is a major topic.
+func MeSHTerm(descriptor, qualifier string, major bool) string {
+	term := descriptor
+	if qualifier != "" {
+		term += "/" + qualifier
+	}
+	tag := "[mh]"
+	if major {
+		tag = "[majr]"
+	}
+	return Quote(term) + tag
+}
+
+// ActionClause builds a drug-class clause: articles indexed with any
+// substance having the pharmacological action, plus member names in title or
+// abstract for records not yet indexed.
+func ActionClause(action string, members []string) string {
+	clauses := []string{Quote(action) + "[pa]"}
+	for i, m := range members {
+		if i == maxClassMembers {
+			break
+		}
+		clauses = append(clauses, Quote(m)+"[tiab]")
+	}
+	if len(clauses) == 1 {
+		return clauses[0]
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")"
+}
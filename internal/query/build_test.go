@@ -0,0 +1,31 @@
+package query
+
+import "testing"
+
+func TestMeSHTerm(t *testing.T) {
+	tests := []struct {
+		descriptor, qualifier string
+		major                 bool
+		want                  string
+	}{
+		{"Fragile X Syndrome", "", false, `"Fragile X Syndrome"[mh]`},
+		{"Fragile X Syndrome", "drug therapy", false, `"Fragile X Syndrome/drug therapy"[mh]`},
+		{"Autism Spectrum Disorder", "genetics", true, `"Autism Spectrum Disorder/genetics"[majr]`},
+	}
+	for _, tt := range tests {
+		if got := MeSHTerm(tt.descriptor, tt.qualifier, tt.major); got != tt.want {
+			t.Errorf("MeSHTerm(%q, %q, %v) = %s, want %s", tt.descriptor, tt.qualifier, tt.major, got, tt.want)
+		}
+	}
+}
+
+func TestActionClause(t *testing.T) {
+	if got := ActionClause("ACE Inhibitors", nil); got != `"ACE Inhibitors"[pa]` {
+		t.Errorf("unexpected clause without members: %s", got)
+	}
+	got := ActionClause("Angiotensin II Type 1 Receptor Blockers", []string{"Losartan", "Valsartan"})
+	want := `("Angiotensin II Type 1 Receptor Blockers"[pa] OR "Losartan"[tiab] OR "Valsartan"[tiab])`
+	if got != want {
+		t.Errorf("ActionClause() = %s, want %s", got, want)
+	}
+}
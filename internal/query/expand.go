@@ -0,0 +1,139 @@
+// Package query parses and rewrites PubMed search queries.
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+)
+
+// maxEntryTerms caps how many entry terms are OR-ed into one concept group,
+// keeping expanded queries well under PubMed's URL limits.
+const maxEntryTerms = 8
+
+// Resolver maps a free-text concept to a MeSH descriptor.
+type Resolver interface {
+	Lookup(ctx context.Context, term string) (*mesh.MeSHRecord, error)
+}
+
+// Expansion records how one free-text concept was rewritten.
+type Expansion struct {
+	Concept    string   `json:"concept"`
+	UI         string   `json:"ui"`
+	Descriptor string   `json:"descriptor"`
+	Terms      []string `json:"terms"`
+	Clause     string   `json:"clause"`
+}
+
+// Expand rewrites each free-text concept in q as
+// ("concept"[tiab] OR "entry term"[tiab] OR "Descriptor"[mh]).
+// Concepts are the operands between top-level AND/OR/NOT operators; operands
+// that already carry field tags, wildcards, or parentheses are left as written,
+// as are concepts that do not name a descriptor or one of its entry terms.
+func Expand(ctx context.Context, q string, r Resolver) (string, []Expansion, error) {
+	parts := Split(q)
+	var expansions []Expansion
+	for i, p := range parts {
+		if p.Operator != "" {
+			continue
+		}
+		concept, ok := freeText(p.Text)
+		if !ok {
+			continue
+		}
+		rec, err := r.Lookup(ctx, concept)
+		if errors.Is(err, mesh.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("expanding %q: %w", concept, err)
+		}
+		if !namesRecord(rec, concept) {
+			continue
+		}
+		e := expansionFor(concept, rec)
+		parts[i].Text = e.Clause
+		expansions = append(expansions, e)
+	}
+	return Join(parts), expansions, nil
+}
+
+func expansionFor(concept string, rec *mesh.MeSHRecord) Expansion {
+	e := Expansion{Concept: concept, UI: rec.UI, Descriptor: rec.Name}
+	seen := make(map[string]bool)
+	add := func(t string) bool {
+		key := strings.ToLower(t)
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		e.Terms = append(e.Terms, t)
+		return true
+	}
+	add(concept)
+	add(rec.Name)
+	entries := 0
+	for _, et := range rec.EntryTerms {
+		// Permuted forms like "Infarction, Myocardial" never appear in prose.
+		if entries == maxEntryTerms || strings.Contains(et, ",") {
+			continue
+		}
+		if add(et) {
+			entries++
+		}
+	}
+
+	clauses := make([]string, 0, len(e.Terms)+1)
+	for _, t := range e.Terms {
+		clauses = append(clauses, Quote(t)+"[tiab]")
+	}
+	if rec.IsSupplementary() {
+		// SCRs are indexed as substance names and under their mapped headings.
+		clauses = append(clauses, Quote(rec.Name)+"[nm]")
+		for _, h := range rec.HeadingMappedTo {
+			clauses = append(clauses, Quote(h)+"[mh]")
+		}
+	} else {
+		clauses = append(clauses, Quote(rec.Name)+"[mh]")
+	}
+	e.Clause = "(" + strings.Join(clauses, " OR ") + ")"
+	return e
+}
+
+// freeText reports whether an operand is plain words (optionally a single
+// quoted phrase) and returns it unquoted.
+func freeText(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	if s == "" || strings.ContainsAny(s, `[]()*"`) {
+		return "", false
+	}
+	return strings.Join(strings.Fields(s), " "), true
+}
+
+// namesRecord reports whether concept is the descriptor's heading or one of
+// its entry terms, so loose esearch hits are not mistaken for a mapping.
+func namesRecord(rec *mesh.MeSHRecord, concept string) bool {
+	if rec == nil {
+		return false
+	}
+	if strings.EqualFold(rec.Name, concept) {
+		return true
+	}
+	for _, et := range rec.EntryTerms {
+		if strings.EqualFold(et, concept) {
+			return true
+		}
+	}
+	return false
+}
+
+// Quote wraps a term in double quotes for use with a PubMed field tag.
+func Quote(term string) string {
+	return `"` + strings.ReplaceAll(term, `"`, "") + `"`
+}
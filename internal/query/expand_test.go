@@ -0,0 +1,79 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+)
+
+type fakeResolver map[string]*mesh.MeSHRecord
+
+func (f fakeResolver) Lookup(_ context.Context, term string) (*mesh.MeSHRecord, error) {
+	if rec, ok := f[strings.ToLower(term)]; ok {
+		return rec, nil
+	}
+	return nil, fmt.Errorf("MeSH term %q %w", term, mesh.ErrNotFound)
+}
+
+var heartAttack = &mesh.MeSHRecord{
+	UI:         "D009203",
+	Name:       "Myocardial Infarction",
+	EntryTerms: []string{"Infarction, Myocardial", "Heart Attack", "Myocardial Infarct"},
+}
+
+func TestExpand(t *testing.T) {
+	r := fakeResolver{
+		"heart attack": heartAttack,
+		// A loose search hit that does not name the concept.
+		"aspirin dosing": {UI: "D001241", Name: "Aspirin"},
+	}
+
+	got, exps, err := Expand(context.Background(), `heart attack AND aspirin dosing AND 2020[dp]`, r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `("heart attack"[tiab] OR "Myocardial Infarction"[tiab] OR "Myocardial Infarct"[tiab] OR "Myocardial Infarction"[mh]) AND aspirin dosing AND 2020[dp]`
+	if got != want {
+		t.Errorf("Expand() =\n  %s\nwant\n  %s", got, want)
+	}
+	if len(exps) != 1 || exps[0].UI != "D009203" || exps[0].Concept != "heart attack" {
+		t.Errorf("unexpected expansions: %+v", exps)
+	}
+}
+
+func TestExpand_PropagatesLookupErrors(t *testing.T) {
+	_, _, err := Expand(context.Background(), "sleep", errResolver{})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected lookup error, got %v", err)
+	}
+}
+
+type errResolver struct{}
+
+func (errResolver) Lookup(context.Context, string) (*mesh.MeSHRecord, error) {
+	return nil, errors.New("boom")
+}
+
+func TestExpand_SupplementaryConcept(t *testing.T) {
+	r := fakeResolver{"leqembi": {
+		UI:              "C000627419",
+		Name:            "lecanemab",
+		RecordType:      mesh.RecordSupplementary,
+		EntryTerms:      []string{"Leqembi"},
+		HeadingMappedTo: []string{"Antibodies, Monoclonal, Humanized"},
+	}}
+
+	got, _, err := Expand(context.Background(), "leqembi", r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `("leqembi"[tiab] OR "lecanemab"[tiab] OR "lecanemab"[nm] OR "Antibodies, Monoclonal, Humanized"[mh])`
+	if got != want {
+		t.Errorf("Expand() =\n  %s\nwant\n  %s", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package query
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// HighlightTerm is a word or phrase from a query worth marking in titles
+// and abstracts. MeSH is set for descriptor terms, whose entry-term synonyms
+// callers may add.
+type HighlightTerm struct {
+	Text string
+	MeSH bool
+}
+
+// segmentBreak splits a query into operands at Boolean operators and parentheses.
+var segmentBreak = regexp.MustCompile(`\s+(?:AND|OR|NOT)\s+|[()]`)
+
+// taggedTerm matches one operand: an optionally quoted term with an optional field tag.
+var taggedTerm = regexp.MustCompile(`^("?)(.+?)("?)(?:\[([^\]]+)\])?$`)
+
+// textFields are the field tags whose terms can appear in article text.
+// Untagged terms count too; tags such as [dp], [pt], or [au] do not.
+var textFields = map[string]bool{
+	"":                       true,
+	"all fields":             true,
+	"mesh terms":             true,
+	"mh":                     true,
+	"majr":                   true,
+	"mesh major topic":       true,
+	"tiab":                   true,
+	"title/abstract":         true,
+	"ti":                     true,
+	"title":                  true,
+	"ab":                     true,
+	"abstract":               true,
+	"tw":                     true,
+	"text word":              true,
+	"nm":                     true,
+	"supplementary concept":  true,
+	"pa":                     true,
+	"pharmacological action": true,
+}
+
+var meshFields = map[string]bool{"mesh terms": true, "mh": true, "majr": true, "mesh major topic": true}
+
+// minHighlightLen drops fragments such as the "x" in "fragile x" that
+// would light up every other word.
+const minHighlightLen = 3
+
+// HighlightTerms extracts the text terms from a query or its PubMed
+// translation, longest first and without duplicates. Boolean operators,
+// date ranges, and terms under non-text field tags are skipped; MeSH
+// qualifiers ("/drug therapy") and truncation wildcards are trimmed.
+func HighlightTerms(q string) []HighlightTerm {
+	var terms []HighlightTerm
+	seen := make(map[string]int)
+	add := func(text, tag string) {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !textFields[tag] {
+			return
+		}
+		switch text {
+		case "AND", "OR", "NOT":
+			return
+		}
+		if i := strings.Index(text, "/"); i > 0 && tag != "" {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(text, "*"))
+		if utf8.RuneCountInString(text) < minHighlightLen || !hasLetter(text) {
+			return
+		}
+
+		key := strings.ToLower(text)
+		if i, ok := seen[key]; ok {
+			terms[i].MeSH = terms[i].MeSH || meshFields[tag]
+			return
+		}
+		seen[key] = len(terms)
+		terms = append(terms, HighlightTerm{Text: text, MeSH: meshFields[tag]})
+	}
+	for _, seg := range segmentBreak.Split(q, -1) {
+		m := taggedTerm.FindStringSubmatch(strings.TrimSpace(seg))
+		if m == nil {
+			continue
+		}
+		text, tag := m[2], m[4]
+		// An untagged, unquoted operand is several search words, not a phrase.
+		if m[1] == "" && tag == "" {
+			for _, word := range strings.Fields(text) {
+				add(word, "")
+			}
+			continue
+		}
+		add(text, tag)
+	}
+	// Longest first, so phrases are matched before the words inside them.
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i].Text) > len(terms[j].Text) })
+	return terms
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package query
+
+import "testing"
+
+func TestHighlightTerms_Translation(t *testing.T) {
+	translation := `("fragile x syndrome"[MeSH Terms] OR ("fragile"[All Fields] AND "x"[All Fields] AND "syndrome"[All Fields]) OR "fragile x syndrome"[All Fields]) AND "review"[pt] AND 2020/01/01:2025/12/31[Date - Publication]`
+
+	terms := HighlightTerms(translation)
+	want := []HighlightTerm{
+		{Text: "fragile x syndrome", MeSH: true},
+		{Text: "syndrome"},
+		{Text: "fragile"},
+	}
+	if len(terms) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, terms)
+	}
+	for i := range want {
+		if terms[i] != want[i] {
+			t.Errorf("term %d: expected %+v, got %+v", i, want[i], terms[i])
+		}
+	}
+}
+
+func TestHighlightTerms_RawQuery(t *testing.T) {
+	terms := HighlightTerms(`"Fragile X Syndrome/drug therapy"[mh] AND metformin AND autis* AND Smith J[au]`)
+	got := make(map[string]bool)
+	for _, term := range terms {
+		got[term.Text] = true
+	}
+	for _, want := range []string{"Fragile X Syndrome", "metformin", "autis"} {
+		if !got[want] {
+			t.Errorf("expected term %q in %+v", want, terms)
+		}
+	}
+	if got["Smith J"] || got["Smith"] || got["AND"] {
+		t.Errorf("expected author tags and operators to be skipped, got %+v", terms)
+	}
+}
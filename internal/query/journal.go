@@ -0,0 +1,27 @@
+package query
+
+import "strings"
+
+// journalFields are the PubMed field tags that name a journal.
+var journalFields = map[string]bool{"ta": true, "journal": true, "jour": true, "jt": true, "is": true}
+
+// JournalTerms returns the journal names and ISSNs a query searches for
+// under [ta], [journal], [jt], or [is] tags, without duplicates.
+func JournalTerms(q string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, seg := range segmentBreak.Split(q, -1) {
+		m := taggedTerm.FindStringSubmatch(strings.TrimSpace(seg))
+		if m == nil || !journalFields[strings.ToLower(strings.TrimSpace(m[4]))] {
+			continue
+		}
+		text := strings.TrimSpace(m[2])
+		key := strings.ToLower(text)
+		if text == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, text)
+	}
+	return terms
+}
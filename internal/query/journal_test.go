@@ -0,0 +1,23 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJournalTerms(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{`"fragile x syndrome" AND "J Neurodev Disord"[ta]`, []string{"J Neurodev Disord"}},
+		{`autism AND (Nature[journal] OR "1866-1955"[is] OR nature[ta])`, []string{"Nature", "1866-1955"}},
+		{`"Lancet Neurol"[Journal] AND 2024[dp]`, []string{"Lancet Neurol"}},
+		{`metformin[tiab] AND smith j[au]`, nil},
+	}
+	for _, tt := range tests {
+		if got := JournalTerms(tt.q); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("JournalTerms(%q) = %q, want %q", tt.q, got, tt.want)
+		}
+	}
+}
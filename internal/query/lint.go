@@ -0,0 +1,459 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Problem severities. PubMed runs a query with errors anyway, but it is
+// unlikely to mean what was typed.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is one thing wrong with a query.
+type Problem struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// DiffPart is a run of a query's words that PubMed's translation kept
+// (Op "="), dropped ("-"), or added ("+").
+type DiffPart struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// LintReport is the result of checking a query: the problems found in its
+// text and reported by PubMed, and how PubMed translated it.
+type LintReport struct {
+	Query       string     `json:"query"`
+	Problems    []Problem  `json:"problems"`
+	Translation string     `json:"translation,omitempty"`
+	Diff        []DiffPart `json:"diff,omitempty"`
+}
+
+// Errors counts the report's problems of error severity.
+func (r *LintReport) Errors() int {
+	n := 0
+	for _, p := range r.Problems {
+		if p.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// knownTags are the field tags PubMed accepts, as abbreviations and full
+// names, lower case. EInfo's field list adds to them when it is available.
+var knownTags = []string{
+	"ad", "affiliation", "all", "all fields", "au", "author", "1au", "author - first",
+	"lastau", "author - last", "auid", "author - identifier", "book", "cn",
+	"corporate author", "cois", "conflict of interest statements", "crdt",
+	"create date", "dcom", "completion date", "doi", "dp", "publication date",
+	"ec/rn number", "rn", "ed", "editor", "edat", "entry date", "filter", "sb",
+	"subset", "fau", "full author name", "fir", "full investigator name", "gr",
+	"grant number", "ir", "investigator", "is", "isbn", "issn", "ip", "issue",
+	"jour", "journal", "jt", "ta", "la", "lang", "language", "lid",
+	"location id", "lr", "date - last revision", "majr", "mesh major topic",
+	"mh", "mesh", "mesh terms", "mhda", "mesh date", "nm",
+	"supplementary concept", "ot", "other term", "owner", "pa",
+	"pharmacological action", "pg", "pagination", "pl", "place of publication",
+	"pmid", "uid", "ps", "personal name as subject", "pt", "publication type",
+	"pubn", "publisher", "sh", "subheading", "si", "secondary source id",
+	"text word", "tw", "ti", "title", "tiab", "title/abstract",
+	"tt", "transliterated title", "vi", "volume", "pdat",
+	"epdat", "electronic date of publication", "ppdat", "print date of publication",
+	"lastrevision", "aid", "article identifier", "pmcid", "mid", "manuscript id",
+}
+
+// noexpTags take the :noexp suffix, and proximityTags the :~N suffix.
+var (
+	noexpTags     = map[string]bool{"mh": true, "mesh": true, "mesh terms": true, "majr": true, "mesh major topic": true, "sh": true, "subheading": true}
+	proximityTags = map[string]bool{"ti": true, "title": true, "tiab": true, "title/abstract": true, "ad": true, "affiliation": true}
+)
+
+// Lint checks q for unbalanced quotes, parentheses, and brackets, unknown
+// field tags, and misplaced Boolean operators. fields, from EInfo, extends
+// the built-in list of field tags; it may be nil.
+func Lint(q string, fields []eutils.SearchField) []Problem {
+	known := make(map[string]bool, len(knownTags)+2*len(fields))
+	for _, t := range knownTags {
+		known[t] = true
+	}
+	for _, f := range fields {
+		known[strings.ToLower(f.Name)] = true
+		known[strings.ToLower(f.FullName)] = true
+	}
+	l := &linter{known: known}
+	l.check(q)
+	return l.problems
+}
+
+// NoticeProblems turns the errors and warnings ESearch reported for a
+// query into problems.
+func NoticeProblems(n eutils.SearchNotices) []Problem {
+	var out []Problem
+	for _, f := range n.FieldsNotFound {
+		out = append(out, Problem{Severity: SeverityError, Message: fmt.Sprintf("PubMed does not know the field [%s]", f)})
+	}
+	for _, p := range n.PhrasesNotFound {
+		out = append(out, Problem{Severity: SeverityWarning, Message: fmt.Sprintf("PubMed found no match for %q and left it out", p)})
+	}
+	for _, p := range n.QuotedPhrasesNotFound {
+		out = append(out, Problem{Severity: SeverityWarning, Message: fmt.Sprintf("PubMed found no match for the quoted phrase %s", p),
+			Hint: "unquote it to let PubMed map it to MeSH and search its words"})
+	}
+	for _, p := range n.PhrasesIgnored {
+		out = append(out, Problem{Severity: SeverityWarning, Message: fmt.Sprintf("PubMed ignored %q", p)})
+	}
+	for _, m := range n.Messages {
+		out = append(out, Problem{Severity: SeverityWarning, Message: "PubMed: " + m})
+	}
+	return out
+}
+
+// token kinds.
+const (
+	tokTerm = iota
+	tokOperator
+	tokOpen
+	tokClose
+)
+
+type token struct {
+	kind   int
+	text   string
+	tagged bool
+}
+
+type linter struct {
+	known    map[string]bool
+	problems []Problem
+	seen     map[string]bool
+}
+
+func (l *linter) add(severity, hint, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if l.seen == nil {
+		l.seen = make(map[string]bool)
+	}
+	if l.seen[msg] {
+		return
+	}
+	l.seen[msg] = true
+	l.problems = append(l.problems, Problem{Severity: severity, Message: msg, Hint: hint})
+}
+
+func (l *linter) check(q string) {
+	if strings.TrimSpace(q) == "" {
+		l.add(SeverityError, "", "the query is empty")
+		return
+	}
+	l.checkStructure(l.tokenize(q))
+}
+
+// tokenize splits q into terms, operators, and parentheses, checking
+// quotes, field tags, and truncation on the way.
+func (l *linter) tokenize(q string) []token {
+	var toks []token
+	for i := 0; i < len(q); {
+		c := q[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: tokOpen})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: tokClose})
+			i++
+		case c == ']':
+			l.add(SeverityError, "", "']' without a matching '['")
+			i++
+		case c == '[':
+			end := strings.IndexByte(q[i:], ']')
+			if end < 0 {
+				l.add(SeverityError, "close the tag with ']'", "unclosed field tag %q", q[i:])
+				return toks
+			}
+			tag := q[i+1 : i+end]
+			i += end + 1
+			n := len(toks)
+			if n == 0 || toks[n-1].kind != tokTerm || toks[n-1].tagged {
+				l.add(SeverityError, "put the tag right after a term, as in autism[tiab]", "field tag [%s] has no search term", tag)
+				continue
+			}
+			toks[n-1].tagged = true
+			l.checkTag(tag)
+		case c == '"':
+			end := strings.IndexByte(q[i+1:], '"')
+			if end < 0 {
+				l.add(SeverityError, "close the phrase with another '\"'", "unbalanced quotes: %s is never closed", q[i:])
+				toks = append(toks, token{kind: tokTerm, text: q[i:]})
+				return toks
+			}
+			phrase := q[i : i+end+2]
+			if strings.TrimSpace(phrase[1:len(phrase)-1]) == "" {
+				l.add(SeverityError, "", "empty quoted phrase")
+			}
+			toks = append(toks, token{kind: tokTerm, text: phrase})
+			i += end + 2
+		default:
+			j := i
+			for j < len(q) && !isSpace(q[j]) && !strings.ContainsRune("()[]\"", rune(q[j])) {
+				j++
+			}
+			word := q[i:j]
+			i = j
+			switch word {
+			case "AND", "OR", "NOT":
+				toks = append(toks, token{kind: tokOperator, text: word})
+				continue
+			case "or", "not":
+				l.add(SeverityWarning, fmt.Sprintf("write %s to combine terms", strings.ToUpper(word)),
+					"lowercase %q is searched as a word; PubMed only treats upper-case AND, OR, and NOT as operators", word)
+			case "&&", "&", "||", "|", "!":
+				l.add(SeverityWarning, "use AND, OR, or NOT", "%q is not a PubMed operator", word)
+			}
+			l.checkTruncation(word)
+			toks = append(toks, token{kind: tokTerm, text: word})
+		}
+	}
+	return toks
+}
+
+// checkStructure checks parentheses and the placement of operators.
+func (l *linter) checkStructure(toks []token) {
+	type group struct {
+		last      int // kind of the previous token; -1 at the start
+		lastOp    string
+		and, or   bool
+		mixedSeen bool
+	}
+	stack := []*group{{last: -1}}
+	for _, t := range toks {
+		g := stack[len(stack)-1]
+		switch t.kind {
+		case tokTerm:
+			g.last = tokTerm
+		case tokOperator:
+			switch g.last {
+			case -1, tokOpen:
+				where := "the query"
+				if len(stack) > 1 {
+					where = "a group"
+				}
+				l.add(SeverityError, "remove it or add a term before it", "%s at the start of %s has nothing to combine", t.text, where)
+			case tokOperator:
+				l.add(SeverityError, "remove one of them or add a term between them", "%s follows %s with no term between them", t.text, g.lastOp)
+			}
+			switch t.text {
+			case "AND":
+				g.and = true
+			case "OR":
+				g.or = true
+			}
+			if g.and && g.or && !g.mixedSeen {
+				g.mixedSeen = true
+				l.add(SeverityWarning, "add parentheses, as in (a OR b) AND c",
+					"AND and OR are mixed without parentheses; PubMed combines terms strictly left to right")
+			}
+			g.last, g.lastOp = tokOperator, t.text
+		case tokOpen:
+			g.last = tokOpen
+			stack = append(stack, &group{last: -1})
+		case tokClose:
+			if len(stack) == 1 {
+				l.add(SeverityError, "remove it or add the matching '('", "unbalanced parentheses: ')' without a matching '('")
+				continue
+			}
+			switch g.last {
+			case -1:
+				l.add(SeverityError, "", "empty parentheses '()'")
+			case tokOperator:
+				l.add(SeverityError, "remove it or add a term after it", "%s before ')' has nothing to combine", g.lastOp)
+			}
+			stack = stack[:len(stack)-1]
+			stack[len(stack)-1].last = tokTerm
+		}
+	}
+	if g := stack[len(stack)-1]; g.last == tokOperator && len(stack) == 1 {
+		l.add(SeverityError, "remove it or add a term after it", "the query ends with %s", g.lastOp)
+	}
+	if open := len(stack) - 1; open > 0 {
+		l.add(SeverityError, "add the missing ')'", "unbalanced parentheses: %d '(' never closed", open)
+	}
+}
+
+// checkTag reports a field tag PubMed does not know, suggesting the
+// closest one it does.
+func (l *linter) checkTag(tag string) {
+	name := strings.ToLower(strings.TrimSpace(tag))
+	if base, ok := strings.CutSuffix(name, ":noexp"); ok {
+		if !noexpTags[base] {
+			l.add(SeverityError, "use it with [mh], [majr], or [sh]", "[%s]: :noexp only applies to MeSH tags", tag)
+			return
+		}
+		name = base
+	}
+	if i := strings.Index(name, ":~"); i >= 0 {
+		base := name[:i]
+		if !proximityTags[base] {
+			l.add(SeverityError, "use it with [ti], [tiab], or [ad]", "[%s]: proximity search only applies to title, title/abstract, and affiliation", tag)
+			return
+		}
+		name = base
+	}
+	if name == "" {
+		l.add(SeverityError, "", "empty field tag []")
+		return
+	}
+	if l.known[name] {
+		return
+	}
+	hint := ""
+	if s := closestTag(name, l.known); s != "" {
+		hint = fmt.Sprintf("did you mean [%s]?", s)
+	}
+	l.add(SeverityError, hint, "unknown field tag [%s]; PubMed searches the term in all fields instead", tag)
+}
+
+// checkTruncation reports a wildcard PubMed will not expand: it needs at
+// least four characters before the asterisk.
+func (l *linter) checkTruncation(word string) {
+	i := strings.IndexByte(word, '*')
+	if i < 0 {
+		return
+	}
+	if i < 4 {
+		l.add(SeverityWarning, "", "%q: PubMed needs at least four characters before '*' and searches it without truncation", word)
+	}
+}
+
+// closestTag returns the known tag nearest to name, or "" if none is
+// within two edits.
+func closestTag(name string, known map[string]bool) string {
+	names := make([]string, 0, len(known))
+	for k := range known {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	best, bestDist := "", 3
+	for _, k := range names {
+		if d := editDistance(name, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	if bestDist >= len(name) {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// TranslationDiff compares the words of q with those of PubMed's
+// translation of it, showing which terms PubMed kept, dropped, and added
+// (typically MeSH terms and synonyms from automatic term mapping).
+func TranslationDiff(q, translation string) []DiffPart {
+	a, b := diffWords(q), diffWords(translation)
+	// Longest common subsequence, compared case-insensitively.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if strings.EqualFold(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var parts []DiffPart
+	emit := func(op, word string) {
+		if n := len(parts); n > 0 && parts[n-1].Op == op {
+			parts[n-1].Text += " " + word
+			return
+		}
+		parts = append(parts, DiffPart{Op: op, Text: word})
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && strings.EqualFold(a[i], b[j]):
+			emit("=", b[j])
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			emit("-", a[i])
+			i++
+		default:
+			emit("+", b[j])
+			j++
+		}
+	}
+	return parts
+}
+
+// diffWords splits a query into words, keeping quoted phrases and their
+// field tags whole and parentheses separate.
+func diffWords(q string) []string {
+	var words []string
+	var b strings.Builder
+	inQuote, inTag := false, false
+	flush := func() {
+		if b.Len() > 0 {
+			words = append(words, b.String())
+			b.Reset()
+		}
+	}
+	for i := 0; i < len(q); i++ {
+		c := q[i]
+		switch {
+		case c == '"' && !inTag:
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			inTag = true
+		case c == ']':
+			inTag = false
+		case inTag:
+		case c == '(' || c == ')':
+			flush()
+			words = append(words, string(c))
+			continue
+		case isSpace(c):
+			flush()
+			continue
+		}
+		b.WriteByte(c)
+	}
+	flush()
+	return words
+}
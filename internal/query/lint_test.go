@@ -0,0 +1,77 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want []string // substrings of the problems' messages, in order
+	}{
+		{"clean", `("fragile x syndrome"[mh] OR fmr1[tiab]) AND sleep[tiab:~3] NOT review[pt]`, nil},
+		{"full-name and noexp tags", `autism[Title/Abstract] AND "child"[MeSH Terms:noexp]`, nil},
+		{"unknown tag", `autism[tiabs]`, []string{"unknown field tag [tiabs]"}},
+		{"tag without term", `autism AND [tiab]`, []string{"[tiab] has no search term", "ends with AND"}},
+		{"noexp on a text tag", `autism[tiab:noexp]`, []string{":noexp only applies to MeSH tags"}},
+		{"unclosed quote", `"fragile x syndrome AND sleep`, []string{"unbalanced quotes"}},
+		{"unclosed paren", `(autism OR asd AND sleep`, []string{"AND and OR are mixed", "'(' never closed"}},
+		{"extra paren", `autism) AND sleep`, []string{"')' without a matching '('"}},
+		{"leading operator", `AND autism`, []string{"AND at the start of the query"}},
+		{"doubled operator", `autism AND OR sleep`, []string{"OR follows AND", "AND and OR are mixed"}},
+		{"trailing operator", `autism AND`, []string{"ends with AND"}},
+		{"operator before close", `(autism OR) AND sleep`, []string{"OR before ')'"}},
+		{"empty group", `autism AND ()`, []string{"empty parentheses"}},
+		{"lowercase or", `autism or asd`, []string{`lowercase "or"`}},
+		{"short truncation", `ab* AND autism`, []string{"four characters before '*'"}},
+		{"empty", "  ", []string{"the query is empty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lint(tt.q, nil)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Lint(%q) = %+v, want %d problems", tt.q, got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !strings.Contains(got[i].Message, w) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, got[i].Message, w)
+				}
+			}
+		})
+	}
+}
+
+func TestLint_SuggestsTag(t *testing.T) {
+	got := Lint(`autism[tiabs]`, nil)
+	if len(got) != 1 || got[0].Hint != "did you mean [tiab]?" {
+		t.Errorf("Lint = %+v, want a hint for [tiab]", got)
+	}
+}
+
+func TestLint_EInfoFields(t *testing.T) {
+	q := `x[newfield]`
+	if got := Lint(q, nil); len(got) != 1 {
+		t.Fatalf("Lint without fields = %+v, want one problem", got)
+	}
+	if got := Lint(q, []eutils.SearchField{{Name: "NEWFIELD", FullName: "New Field"}}); len(got) != 0 {
+		t.Errorf("Lint with EInfo fields = %+v, want none", got)
+	}
+}
+
+func TestTranslationDiff(t *testing.T) {
+	got := TranslationDiff(`autism AND sleepp[tiab]`,
+		`("autistic disorder"[MeSH Terms] OR autism[All Fields]) AND sleepp[tiab]`)
+	want := []DiffPart{
+		{"-", "autism"},
+		{"+", `( "autistic disorder"[MeSH Terms] OR autism[All Fields] )`},
+		{"=", "AND sleepp[tiab]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslationDiff = %+v, want %+v", got, want)
+	}
+}
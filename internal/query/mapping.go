@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+)
+
+// Searcher runs a PubMed search; its translation set exposes how PubMed's
+// automatic term mapping (ATM) read each phrase.
+type Searcher interface {
+	Search(ctx context.Context, query string, opts *eutils.SearchOptions) (*eutils.SearchResult, error)
+}
+
+// Mapping is a candidate descriptor for one phrase of a natural-language question.
+type Mapping struct {
+	Phrase     string  `json:"phrase"`
+	UI         string  `json:"ui,omitempty"`
+	Descriptor string  `json:"descriptor,omitempty"`
+	Confidence float64 `json:"confidence"`
+	Via        string  `json:"via"`
+}
+
+// Mapping sources, strongest first.
+const (
+	ViaHeading   = "heading"      // phrase is the descriptor name
+	ViaEntryTerm = "entry term"   // phrase is a listed synonym
+	ViaATM       = "term mapping" // PubMed mapped the phrase through UMLS
+	ViaUnmapped  = "unmapped"
+)
+
+var confidenceFor = map[string]float64{
+	ViaHeading:   1.0,
+	ViaEntryTerm: 0.9,
+	ViaATM:       0.7,
+	ViaUnmapped:  0,
+}
+
+// atmMeSH matches the MeSH-side terms in an ATM translation.
+var atmMeSH = regexp.MustCompile(`"([^"]+)"\[(?:MeSH Terms|Supplementary Concept)\]`)
+
+// MapConcepts runs text through PubMed's automatic term mapping and resolves
+// each mapped phrase to a descriptor. Phrases PubMed searched only as free
+// text are returned with Via == ViaUnmapped.
+func MapConcepts(ctx context.Context, text string, s Searcher, r Resolver) ([]Mapping, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("text to map cannot be empty")
+	}
+
+	result, err := s.Search(ctx, text, &eutils.SearchOptions{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out  []Mapping
+		seen = make(map[string]bool)
+	)
+	for _, tr := range result.Translations {
+		terms := atmMeSH.FindAllStringSubmatch(tr.To, -1)
+		if len(terms) == 0 {
+			out = append(out, Mapping{Phrase: tr.From, Confidence: confidenceFor[ViaUnmapped], Via: ViaUnmapped})
+			continue
+		}
+		for _, m := range terms {
+			rec, err := r.Lookup(ctx, m[1])
+			if errors.Is(err, mesh.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("resolving %q: %w", m[1], err)
+			}
+			if seen[rec.UI] {
+				continue
+			}
+			seen[rec.UI] = true
+
+			via := ViaATM
+			switch {
+			case strings.EqualFold(rec.Name, tr.From):
+				via = ViaHeading
+			case namesRecord(rec, tr.From):
+				via = ViaEntryTerm
+			}
+			out = append(out, Mapping{
+				Phrase:     tr.From,
+				UI:         rec.UI,
+				Descriptor: rec.Name,
+				Confidence: confidenceFor[via],
+				Via:        via,
+			})
+		}
+	}
+	return out, nil
+}
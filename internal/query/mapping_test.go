@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+type fakeSearcher []eutils.Translation
+
+func (f fakeSearcher) Search(context.Context, string, *eutils.SearchOptions) (*eutils.SearchResult, error) {
+	return &eutils.SearchResult{Translations: f}, nil
+}
+
+func TestMapConcepts(t *testing.T) {
+	s := fakeSearcher{
+		{From: "memory", To: `"memory"[MeSH Terms] OR "memory"[All Fields]`},
+		{From: "chemo", To: `"drug therapy"[MeSH Subheading] OR "chemo"[All Fields]`},
+		{From: "breast cancer", To: `"breast neoplasms"[MeSH Terms] OR ("breast"[All Fields] AND "cancer"[All Fields])`},
+		{From: "survivors", To: `"cancer survivors"[MeSH Terms] OR "survivors"[All Fields] OR "survivor"[All Fields]`},
+	}
+	r := fakeResolver{
+		"memory":           {UI: "D008568", Name: "Memory"},
+		"breast neoplasms": {UI: "D001943", Name: "Breast Neoplasms", EntryTerms: []string{"Breast Cancer"}},
+		"cancer survivors": {UI: "D000073116", Name: "Cancer Survivors"},
+	}
+
+	got, err := MapConcepts(context.Background(), "memory problems after chemo in breast cancer survivors", s, r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Mapping{
+		{Phrase: "memory", UI: "D008568", Descriptor: "Memory", Confidence: 1.0, Via: ViaHeading},
+		{Phrase: "chemo", Via: ViaUnmapped},
+		{Phrase: "breast cancer", UI: "D001943", Descriptor: "Breast Neoplasms", Confidence: 0.9, Via: ViaEntryTerm},
+		{Phrase: "survivors", UI: "D000073116", Descriptor: "Cancer Survivors", Confidence: 0.7, Via: ViaATM},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d mappings, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("mapping %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMapConcepts_Empty(t *testing.T) {
+	if _, err := MapConcepts(context.Background(), " ", fakeSearcher{}, fakeResolver{}); err == nil {
+		t.Fatal("expected error for empty text")
+	}
+}
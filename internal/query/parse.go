@@ -0,0 +1,87 @@
+package query
+
+import "strings"
+
+// Part is one top-level piece of a query: either an operand (which may be a
+// parenthesized group, quoted phrase, or tagged term) or a Boolean operator.
+type Part struct {
+	Text     string
+	Operator string // "AND", "OR", or "NOT"; empty for operands
+}
+
+// Split breaks q at top-level Boolean operators. PubMed only treats upper-case
+// AND/OR/NOT as operators, and operators inside parentheses, quotes, or field
+// tags belong to their operand.
+func Split(q string) []Part {
+	var (
+		parts   []Part
+		depth   int
+		inQuote bool
+		inTag   bool
+		start   int
+	)
+	flush := func(end int) {
+		if text := strings.TrimSpace(q[start:end]); text != "" {
+			parts = append(parts, Part{Text: text})
+		}
+	}
+
+	for i := 0; i < len(q); i++ {
+		switch c := q[i]; {
+		case c == '"' && !inTag:
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			inTag = true
+		case c == ']':
+			inTag = false
+		case inTag:
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && isSpace(c):
+			op, n := operatorAt(q, i+1)
+			if op == "" {
+				continue
+			}
+			flush(i)
+			parts = append(parts, Part{Operator: op})
+			i += n
+			start = i + 1
+		}
+	}
+	flush(len(q))
+	return parts
+}
+
+// Join reassembles parts into a query string.
+func Join(parts []Part) string {
+	words := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p.Operator != "" {
+			words = append(words, p.Operator)
+		} else {
+			words = append(words, p.Text)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// operatorAt reports a Boolean operator beginning at q[i] and followed by
+// whitespace, returning it and its length.
+func operatorAt(q string, i int) (string, int) {
+	for _, op := range []string{"AND", "OR", "NOT"} {
+		end := i + len(op)
+		if end < len(q) && q[i:end] == op && isSpace(q[end]) {
+			return op, len(op)
+		}
+	}
+	return "", 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
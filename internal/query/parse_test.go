@@ -0,0 +1,50 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want []Part
+	}{
+		{
+			name: "single operand",
+			q:    "fragile x syndrome",
+			want: []Part{{Text: "fragile x syndrome"}},
+		},
+		{
+			name: "top-level operators",
+			q:    "autism AND sleep NOT review[pt]",
+			want: []Part{{Text: "autism"}, {Operator: "AND"}, {Text: "sleep"}, {Operator: "NOT"}, {Text: "review[pt]"}},
+		},
+		{
+			name: "lower-case operators are words",
+			q:    "salt and pepper",
+			want: []Part{{Text: "salt and pepper"}},
+		},
+		{
+			name: "grouped and quoted operators stay inside operand",
+			q:    `(a OR b) AND "rock AND roll" AND "x y"[tiab]`,
+			want: []Part{{Text: "(a OR b)"}, {Operator: "AND"}, {Text: `"rock AND roll"`}, {Operator: "AND"}, {Text: `"x y"[tiab]`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split(tt.q)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %+v, want %+v", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoin_RoundTrip(t *testing.T) {
+	q := `(a OR b) AND "c d"[mh] NOT e`
+	if got := Join(Split(q)); got != q {
+		t.Errorf("Join(Split(%q)) = %q", q, got)
+	}
+}
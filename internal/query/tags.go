@@ -0,0 +1,34 @@
+package query
+
+// FieldTag is a PubMed search field tag, as in "autism"[tiab].
+type FieldTag struct {
+	Tag  string
+	Name string
+}
+
+// FieldTags lists the commonly used PubMed field tags, most useful first,
+// for shell completion and help.
+var FieldTags = []FieldTag{
+	{"mh", "MeSH Terms"},
+	{"majr", "MeSH Major Topic"},
+	{"sh", "MeSH Subheading"},
+	{"tiab", "Title/Abstract"},
+	{"ti", "Title"},
+	{"tw", "Text Word"},
+	{"au", "Author"},
+	{"1au", "First Author"},
+	{"lastau", "Last Author"},
+	{"ad", "Affiliation"},
+	{"ta", "Journal"},
+	{"dp", "Publication Date"},
+	{"edat", "Entry Date"},
+	{"pt", "Publication Type"},
+	{"la", "Language"},
+	{"nm", "Supplementary Concept"},
+	{"pa", "Pharmacological Action"},
+	{"sb", "Subset"},
+	{"gr", "Grant Number"},
+	{"pmid", "PMID"},
+	{"doi", "DOI"},
+	{"all", "All Fields"},
+}
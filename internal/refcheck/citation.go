@@ -0,0 +1,312 @@
+package refcheck
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+)
+
+var (
+	// reClaimAuthors matches the authors of a free-text citation:
+	// "Smith et al.", "Smith and Jones", or "Smith & Jones".
+	reClaimAuthors = regexp.MustCompile(`(\p{Lu}[\p{L}'-]+)(?:\s+et\s+al\b\.?|\s+(?:and|&)\s+(\p{Lu}[\p{L}'-]+))`)
+	// reClaimAuthorYear matches a single author next to a year: "Smith
+	// (2019)", "(Smith, 2019)", or "Smith 2019".
+	reClaimAuthorYear = regexp.MustCompile(`(\p{Lu}[\p{L}'-]+),?\s+\(?((?:19|20)\d{2})\b`)
+	// reClaimYear matches a publication year.
+	reClaimYear = regexp.MustCompile(`\b(?:19|20)\d{2}\b`)
+)
+
+// ParseCitation parses one citation, either a formatted reference such as
+// a line of a reference list or a free-text claim such as "Smith et al.
+// 2019 showed X in NEJM". A free-text claim yields what it names of the
+// first author, year, and journal, with its other content words as
+// Keywords to search on.
+func ParseCitation(text string) ParsedReference {
+	text = strings.Join(strings.Fields(text), " ")
+	if refs, _ := ParseReferences(text); len(refs) == 1 && formatted(refs[0]) {
+		return refs[0]
+	}
+	return parseClaim(text, text, 1)
+}
+
+// ParseCitations parses a list of citations, such as a manuscript's
+// reference section or a file of claims, split into entries as
+// ParseReferences splits them, each parsed as ParseCitation would.
+func ParseCitations(text string) []ParsedReference {
+	refs, _ := ParseReferences(text)
+	for i, ref := range refs {
+		if formatted(ref) {
+			continue
+		}
+		body := ref.Raw
+		for _, re := range []*regexp.Regexp{reNumberedDot, reNumberedBracket, reNumberedParen} {
+			body = re.ReplaceAllString(body, "")
+		}
+		refs[i] = parseClaim(ref.Raw, body, ref.Index)
+	}
+	return refs
+}
+
+// formatted reports whether ref parsed as a formatted reference, with a
+// title and a journal, volume, pages, or DOI, rather than as prose.
+func formatted(ref ParsedReference) bool {
+	return ref.Title != "" && (ref.Journal != "" || ref.Volume != "" || ref.Pages != "" || ref.DOI != "")
+}
+
+// parseClaim parses text as a free-text citation.
+func parseClaim(raw, text string, index int) ParsedReference {
+	ref := ParsedReference{Raw: raw, Index: index, PMID: ExtractPMID(text), DOI: ExtractDOI(text)}
+	rest := rePMID.ReplaceAllString(text, " ")
+	rest = reDOI.ReplaceAllString(rest, " ")
+
+	if m := reClaimAuthors.FindStringSubmatchIndex(rest); m != nil && !claimStopwords[strings.ToLower(rest[m[2]:m[3]])] {
+		ref.Authors = append(ref.Authors, rest[m[2]:m[3]])
+		if m[4] >= 0 {
+			ref.Authors = append(ref.Authors, rest[m[4]:m[5]])
+		}
+		rest = rest[:m[0]] + " " + rest[m[1]:]
+	}
+	for _, m := range reClaimAuthorYear.FindAllStringSubmatchIndex(rest, -1) {
+		name := rest[m[2]:m[3]]
+		if claimStopwords[strings.ToLower(name)] {
+			continue
+		}
+		if len(ref.Authors) == 0 {
+			ref.Authors = []string{name}
+		}
+		rest = rest[:m[2]] + strings.Repeat(" ", m[3]-m[2]) + rest[m[3]:]
+		break
+	}
+	if y := reClaimYear.FindString(rest); y != "" {
+		ref.Year = y
+		rest = strings.Replace(rest, y, " ", 1)
+	}
+	if name, start, end := findJournal(rest); name != "" {
+		ref.Journal = name
+		rest = rest[:start] + " " + rest[end:]
+	}
+
+	for _, w := range significantWords(rest, 100) {
+		if !claimStopwords[w] && !unicode.IsDigit([]rune(w)[0]) {
+			ref.Keywords = append(ref.Keywords, w)
+		}
+		if len(ref.Keywords) == 6 {
+			break
+		}
+	}
+	return ref
+}
+
+// claimStopwords are the words of a free-text claim that say nothing about
+// the article cited, and capitalized words that open a sentence rather
+// than name an author.
+var claimStopwords = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(`
+		according also among article authors demonstrate demonstrated
+		demonstrates during evidence find finding findings found however
+		journal paper previous previously prior published recent recently
+		report reported reports research researchers review show showed shown
+		shows since study studies suggest suggested suggests trial until using
+		when where while work in the a an and as by for from of on see
+		january february march april may june july august september october
+		november december`) {
+		m[w] = true
+	}
+	return m
+}()
+
+// journalNames maps the short names and full titles of widely cited
+// journals to their NLM abbreviations, so a claim that names "NEJM" can be
+// matched against PubMed's records.
+var journalNames = map[string]string{
+	"nejm":                            "N Engl J Med",
+	"new england journal of medicine": "N Engl J Med",
+	"n engl j med":                    "N Engl J Med",
+	"jama":                            "JAMA",
+	"journal of the american medical association": "JAMA",
+	"jama pediatrics":                "JAMA Pediatr",
+	"jama psychiatry":                "JAMA Psychiatry",
+	"jama neurology":                 "JAMA Neurol",
+	"jama oncology":                  "JAMA Oncol",
+	"jama internal medicine":         "JAMA Intern Med",
+	"jama network open":              "JAMA Netw Open",
+	"bmj":                            "BMJ",
+	"british medical journal":        "BMJ",
+	"lancet":                         "Lancet",
+	"the lancet":                     "Lancet",
+	"lancet psychiatry":              "Lancet Psychiatry",
+	"lancet neurology":               "Lancet Neurol",
+	"lancet oncology":                "Lancet Oncol",
+	"lancet infectious diseases":     "Lancet Infect Dis",
+	"nature":                         "Nature",
+	"nature medicine":                "Nat Med",
+	"nature neuroscience":            "Nat Neurosci",
+	"nature genetics":                "Nat Genet",
+	"nature communications":          "Nat Commun",
+	"science":                        "Science",
+	"science translational medicine": "Sci Transl Med",
+	"cell":                           "Cell",
+	"neuron":                         "Neuron",
+	"pnas":                           "Proc Natl Acad Sci U S A",
+	"proceedings of the national academy of sciences": "Proc Natl Acad Sci U S A",
+	"plos one":                                "PLoS One",
+	"plos medicine":                           "PLoS Med",
+	"annals of internal medicine":             "Ann Intern Med",
+	"annals of neurology":                     "Ann Neurol",
+	"american journal of psychiatry":          "Am J Psychiatry",
+	"biological psychiatry":                   "Biol Psychiatry",
+	"molecular psychiatry":                    "Mol Psychiatry",
+	"journal of clinical oncology":            "J Clin Oncol",
+	"jco":                                     "J Clin Oncol",
+	"journal of clinical investigation":       "J Clin Invest",
+	"jci":                                     "J Clin Invest",
+	"cochrane database of systematic reviews": "Cochrane Database Syst Rev",
+	"cochrane review":                         "Cochrane Database Syst Rev",
+	"circulation":                             "Circulation",
+	"pediatrics":                              "Pediatrics",
+	"neurology":                               "Neurology",
+	"brain":                                   "Brain",
+	"gastroenterology":                        "Gastroenterology",
+	"diabetes care":                           "Diabetes Care",
+	"cmaj":                                    "CMAJ",
+	"elife":                                   "Elife",
+}
+
+// journalPatterns matches the names in journalNames, longest first so
+// "JAMA Pediatrics" wins over "JAMA".
+var journalPatterns = func() []*regexp.Regexp {
+	names := make([]string, 0, len(journalNames))
+	for n := range journalNames {
+		names = append(names, n)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	out := make([]*regexp.Regexp, len(names))
+	for i, n := range names {
+		out[i] = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(n) + `\b`)
+	}
+	return out
+}()
+
+// findJournal returns the NLM abbreviation of the journal a claim names,
+// and where the name is in s. Names must be capitalized, so "nature" and
+// "science" in running text are not taken for journals.
+func findJournal(s string) (name string, start, end int) {
+	for _, re := range journalPatterns {
+		for _, loc := range re.FindAllStringIndex(s, -1) {
+			if r, _ := utf8.DecodeRuneInString(s[loc[0]:]); unicode.IsUpper(r) {
+				return journalNames[strings.ToLower(s[loc[0]:loc[1]])], loc[0], loc[1]
+			}
+		}
+	}
+	return "", 0, 0
+}
+
+// topicWords returns up to n words describing what ref is about: the
+// significant words of its title, or its keywords when it has no title.
+func topicWords(ref ParsedReference, n int) []string {
+	if ref.Title != "" {
+		return significantWords(ref.Title, n)
+	}
+	if len(ref.Keywords) > n {
+		return ref.Keywords[:n]
+	}
+	return ref.Keywords
+}
+
+// scoreKeywords returns the share of keywords found in an article's title,
+// abstract, or MeSH headings, comparing word stems.
+func scoreKeywords(keywords []string, article eutils.Article) float64 {
+	if len(keywords) == 0 {
+		return 0.0
+	}
+	text := []string{article.Title, article.Abstract}
+	for _, m := range article.MeSHTerms {
+		text = append(text, m.Descriptor)
+	}
+	stems := make(map[string]bool)
+	for _, w := range strings.Fields(NormalizeTitle(strings.Join(text, " "))) {
+		stems[library.Stem(w)] = true
+	}
+	found := 0
+	for _, k := range keywords {
+		if stems[library.Stem(strings.ToLower(k))] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(keywords))
+}
+
+// Mismatches lists the fields ref claims that art contradicts: its first
+// author, DOI, year, title, pages, and journal.
+func Mismatches(ref ParsedReference, art eutils.Article) []string {
+	var out []string
+	if len(ref.Authors) > 0 && len(art.Authors) > 0 && !strings.EqualFold(ref.Authors[0], art.Authors[0].LastName) {
+		out = append(out, fmt.Sprintf("First author: %s → %s", ref.Authors[0], art.Authors[0].LastName))
+	}
+	out = append(out, describeDiffs(ref, art)...)
+	if ref.Journal != "" && scoreJournal(ref.Journal, art.Journal, art.JournalAbbrev) < 0.5 {
+		journal := art.JournalAbbrev
+		if journal == "" {
+			journal = art.Journal
+		}
+		out = append(out, fmt.Sprintf("Journal: %s → %s", ref.Journal, journal))
+	}
+	return out
+}
+
+// ResolveCitation verifies a single citation, as parsed by ParseCitation.
+// A citation giving journal, year, volume, first page, and first author is
+// looked up with ECitMatch first; others go through the tiers of Resolve.
+// Every field the citation claims is then checked against the match, so a
+// real article cited with the wrong author, year, or journal is reported
+// as corrected rather than verified.
+func (r *Resolver) ResolveCitation(ctx context.Context, ref ParsedReference) VerifiedReference {
+	var vr VerifiedReference
+	if art := r.citationMatch(ctx, ref); art != nil {
+		vr = VerifiedReference{
+			Parsed: ref, Status: StatusVerifiedExact, Match: art,
+			Confidence: ScoreMatch(ref, *art).Total, QueryTiers: []string{"ecitmatch"},
+		}
+	} else {
+		vr = r.Resolve(ctx, ref)
+	}
+	if vr.Match != nil {
+		vr.Corrections = Mismatches(ref, *vr.Match)
+		if len(vr.Corrections) > 0 && vr.Status == StatusVerifiedExact {
+			vr.Status = StatusVerifiedCorrected
+		}
+	}
+	return vr
+}
+
+// citationMatch finds ref with ECitMatch, or returns nil if it lacks the
+// fields ECitMatch needs or does not match.
+func (r *Resolver) citationMatch(ctx context.Context, ref ParsedReference) *eutils.Article {
+	if ref.PMID != "" || ref.Journal == "" || ref.Year == "" || ref.Volume == "" || ref.Pages == "" || len(ref.Authors) == 0 {
+		return nil
+	}
+	firstPage, _, _ := strings.Cut(ref.Pages, "-")
+	firstPage, _, _ = strings.Cut(firstPage, "–")
+	matches, err := r.client.MatchCitations(ctx, []eutils.Citation{{
+		Journal: ref.Journal, Year: ref.Year, Volume: ref.Volume,
+		FirstPage: firstPage, Author: ref.Authors[0], Key: "1",
+	}})
+	if err != nil || matches["1"] == "" {
+		return nil
+	}
+	return r.fetchByPMID(ctx, matches["1"])
+}
@@ -0,0 +1,94 @@
+package refcheck
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestParseCitation(t *testing.T) {
+	tests := []struct {
+		in       string
+		authors  []string
+		year     string
+		journal  string
+		keywords []string
+	}{
+		{
+			in:       "Smith et al. 2019 showed that metformin improves language in NEJM",
+			authors:  []string{"Smith"},
+			year:     "2019",
+			journal:  "N Engl J Med",
+			keywords: []string{"metformin", "improves", "language"},
+		},
+		{
+			in:       "As Bear and Huber (2004) reported in Trends Neurosci, mGluR5 drives fragile X phenotypes.",
+			authors:  []string{"Bear", "Huber"},
+			year:     "2004",
+			keywords: []string{"trends", "neurosci", "mglur5", "drives", "fragile", "phenotypes"},
+		},
+		{
+			in:       "(Jones, 2021) found nature exposure lowers anxiety in JAMA Pediatrics",
+			authors:  []string{"Jones"},
+			year:     "2021",
+			journal:  "JAMA Pediatr",
+			keywords: []string{"nature", "exposure", "lowers", "anxiety"},
+		},
+	}
+	for _, tt := range tests {
+		ref := ParseCitation(tt.in)
+		if !reflect.DeepEqual(ref.Authors, tt.authors) || ref.Year != tt.year || ref.Journal != tt.journal || ref.Title != "" {
+			t.Errorf("ParseCitation(%q) = authors %v, year %q, journal %q, title %q", tt.in, ref.Authors, ref.Year, ref.Journal, ref.Title)
+		}
+		if !reflect.DeepEqual(ref.Keywords, tt.keywords) {
+			t.Errorf("ParseCitation(%q) keywords = %v, want %v", tt.in, ref.Keywords, tt.keywords)
+		}
+	}
+
+	// A formatted reference is parsed as one.
+	ref := ParseCitation("Bear MF, Huber KM, Warren ST. The mGluR theory of fragile X mental retardation. Trends Neurosci. 2004;27(7):370-377.")
+	if ref.Title != "The mGluR theory of fragile X mental retardation" || ref.Volume != "27" || ref.Pages != "370-377" || len(ref.Keywords) != 0 {
+		t.Errorf("unexpected reference: %+v", ref)
+	}
+}
+
+func TestParseCitations(t *testing.T) {
+	refs := ParseCitations(`1. Smith et al. 2019 showed metformin improves language in NEJM
+2. Bear MF, Huber KM, Warren ST. The mGluR theory of fragile X mental retardation. Trends Neurosci. 2004;27(7):370-377.`)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 citations, got %d", len(refs))
+	}
+	if refs[0].Index != 1 || refs[0].Journal != "N Engl J Med" || len(refs[0].Keywords) != 3 {
+		t.Errorf("unexpected claim: %+v", refs[0])
+	}
+	if refs[1].Index != 2 || refs[1].Journal != "Trends Neurosci" || refs[1].Volume != "27" {
+		t.Errorf("unexpected reference: %+v", refs[1])
+	}
+}
+
+func TestMismatches(t *testing.T) {
+	art := eutils.Article{
+		PMID: "15219735", Year: "2004", Journal: "Trends in neurosciences", JournalAbbrev: "Trends Neurosci",
+		Authors: []eutils.Author{{LastName: "Bear"}, {LastName: "Huber"}},
+	}
+	if got := Mismatches(ParseCitation("Bear et al. 2004 in Trends Neurosci"), art); len(got) != 0 {
+		t.Errorf("unexpected mismatches: %v", got)
+	}
+	got := Mismatches(ParseCitation("Huber et al. 2006 showed mGluR theory in NEJM"), art)
+	want := []string{"First author: Huber → Bear", "Year: 2006 → 2004", "Journal: N Engl J Med → Trends Neurosci"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Mismatches = %v, want %v", got, want)
+	}
+}
+
+func TestScoreMatch_Keywords(t *testing.T) {
+	ref := ParseCitation("Smith et al. 2019 showed metformin improves language in NEJM")
+	art := eutils.Article{
+		Title: "Metformin and language outcomes", Abstract: "Metformin improves expressive language.",
+		Year: "2019", JournalAbbrev: "N Engl J Med", Authors: []eutils.Author{{LastName: "Smith"}},
+	}
+	if s := ScoreMatch(ref, art); s.Title != 1 || s.Total < 0.95 {
+		t.Errorf("unexpected score: %+v", s)
+	}
+}
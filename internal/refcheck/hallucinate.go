@@ -86,8 +86,8 @@ func (h *HallucinationDetector) gatherSignals(ctx context.Context, ref ParsedRef
 	}
 
 	// Check if author publishes on a related topic (using title keywords).
-	if s.authorPublishes && ref.Title != "" {
-		keywords := significantWords(ref.Title, 2)
+	if s.authorPublishes {
+		keywords := topicWords(ref, 2)
 		if len(keywords) > 0 {
 			topicQuery := firstAuthor + "[au] AND " + strings.Join(keywords, " AND ")
 			topicResult, err := h.client.Search(ctx, topicQuery, &eutils.SearchOptions{Limit: 1})
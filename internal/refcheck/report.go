@@ -46,7 +46,11 @@ func FormatJSON(w io.Writer, report Report) error {
 func FormatHuman(w io.Writer, report Report) error {
 	s := report.Summary
 
-	fmt.Fprintf(w, "Reference Check Report: %s\n", report.DocumentPath)
+	if report.DocumentPath != "" {
+		fmt.Fprintf(w, "Reference Check Report: %s\n", report.DocumentPath)
+	} else {
+		fmt.Fprintf(w, "Reference Check Report\n")
+	}
 	fmt.Fprintf(w, "═══════════════════════════════════════════════\n\n")
 	fmt.Fprintf(w, "Total references: %d\n\n", s.Total)
 
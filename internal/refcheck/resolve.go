@@ -100,7 +100,7 @@ func (r *Resolver) Resolve(ctx context.Context, ref ParsedReference) VerifiedRef
 	}
 
 	// Tier 3: Relaxed search — first author + key title words.
-	if len(ref.Authors) > 0 || ref.Title != "" {
+	if len(ref.Authors) > 0 || ref.Title != "" || len(ref.Keywords) > 0 {
 		arts := r.searchRelaxed(ctx, ref)
 		if best, score := r.bestMatch(ref, arts); best != nil && score.Total >= 0.55 {
 			vr.Match = best
@@ -174,11 +174,8 @@ func (r *Resolver) searchByAuthorYear(ctx context.Context, ref ParsedReference)
 		parts = append(parts, ref.Year+"[dp]")
 	}
 	// Add 2-3 significant title words.
-	if ref.Title != "" {
-		keywords := significantWords(ref.Title, 3)
-		for _, kw := range keywords {
-			parts = append(parts, kw)
-		}
+	for _, kw := range topicWords(ref, 3) {
+		parts = append(parts, kw)
 	}
 	if len(parts) == 0 {
 		return nil
@@ -193,10 +190,7 @@ func (r *Resolver) searchRelaxed(ctx context.Context, ref ParsedReference) []eut
 	if len(ref.Authors) > 0 {
 		parts = append(parts, ref.Authors[0]+"[au]")
 	}
-	if ref.Title != "" {
-		keywords := significantWords(ref.Title, 5)
-		parts = append(parts, keywords...)
-	}
+	parts = append(parts, topicWords(ref, 5)...)
 	if len(parts) == 0 {
 		return nil
 	}
@@ -110,8 +110,11 @@ func ScoreMatch(ref ParsedReference, article eutils.Article) MatchScore {
 		return ms
 	}
 
-	// Title scoring
+	// Title scoring, or keyword scoring for a free-text citation
 	ms.Title = scoreTitle(ref.Title, article.Title)
+	if ref.Title == "" {
+		ms.Title = scoreKeywords(ref.Keywords, article)
+	}
 
 	// Author scoring
 	ms.AuthorHit = scoreAuthors(ref.Authors, article.Authors)
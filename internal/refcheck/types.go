@@ -28,6 +28,9 @@ type ParsedReference struct {
 	Pages   string
 	DOI     string // Extracted DOI
 	PMID    string // Extracted PMID
+	// Keywords are the content words of a free-text citation, searched and
+	// scored in place of a title when there is none.
+	Keywords []string
 }
 
 // MatchScore breaks down how well a PubMed article matches a parsed reference.
@@ -0,0 +1,152 @@
+// Package refine proposes concrete refinements to a PubMed query from a
+// sample of its results, and runs the interactive loop that applies them.
+package refine
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/analyze"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Kinds of refinement.
+const (
+	KindMeSH = "mesh"
+	KindDate = "date"
+	KindNot  = "not"
+)
+
+// Thresholds for proposing refinements.
+const (
+	// maxMeSH bounds the MeSH terms proposed in one round.
+	maxMeSH = 2
+	// minMeSHShare is the share of sampled results a descriptor must be a
+	// major topic of to be proposed.
+	minMeSHShare = 0.2
+	// dateRestrictAbove is the result count above which a date range is
+	// proposed; wideDateAbove narrows it from ten years to five.
+	dateRestrictAbove = 200
+	wideDateAbove     = 2000
+)
+
+// Proposal is one refinement: the clause it adds and the resulting query.
+type Proposal struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Clause      string `json:"clause"`
+	Query       string `json:"query"`
+	// Count is the refined query's result count, once known.
+	Count int `json:"count"`
+}
+
+var (
+	dateFieldRe = regexp.MustCompile(`(?i)\[(dp|pdat|edat|crdt|mhda|publication date|date - publication)\]`)
+	// opinionTypes are publication types that rarely answer a research
+	// question directly.
+	opinionTypes = []string{"Editorial", "Letter", "Comment"}
+)
+
+// Propose suggests refinements of q, whose total result count is count,
+// from a sample of its top results: MeSH descriptors many of them share,
+// a date range when there are many results, and NOT clauses dropping
+// animal-only studies or opinion pieces found in the sample. year is the
+// current year.
+func Propose(q string, count int, sample []eutils.Article, year int) []Proposal {
+	var out []Proposal
+	lower := strings.ToLower(q)
+	add := func(kind, desc, clause string) {
+		out = append(out, Proposal{Kind: kind, Description: desc, Clause: clause, Query: And(q, clause)})
+	}
+
+	for _, t := range MissingMeSH(q, "", sample, maxMeSH) {
+		add(KindMeSH, fmt.Sprintf("Add MeSH term %q (major topic of %d of the top %d)", t.Term, t.Count, len(sample)),
+			fmt.Sprintf(`"%s"[mh]`, t.Term))
+	}
+
+	if count > dateRestrictAbove && !dateFieldRe.MatchString(q) {
+		span := 10
+		if count > wideDateAbove {
+			span = 5
+		}
+		from := year - span + 1
+		add(KindDate, fmt.Sprintf("Restrict to the last %d years (%d-%d)", span, from, year), fmt.Sprintf("%d:%d[dp]", from, year))
+	}
+
+	if !strings.Contains(lower, "animals") && animalOnly(sample) > 0 {
+		add(KindNot, fmt.Sprintf("Exclude animal-only studies (%d of the top %d)", animalOnly(sample), len(sample)),
+			"NOT (animals[mh] NOT humans[mh])")
+	}
+	if n := opinionPieces(sample); n > 0 && !strings.Contains(lower, "editorial[pt]") {
+		add(KindNot, fmt.Sprintf("Exclude editorials, letters, and comments (%d of the top %d)", n, len(sample)),
+			"NOT (editorial[pt] OR letter[pt] OR comment[pt])")
+	}
+	return out
+}
+
+// MissingMeSH returns up to max descriptors that are a major topic of at
+// least a fifth of sample, and of two or more articles, but that neither q
+// nor PubMed's translation of it mentions, most frequent first.
+func MissingMeSH(q, translation string, sample []eutils.Article, max int) []analyze.TermCount {
+	if len(sample) == 0 {
+		return nil
+	}
+	lower := strings.ToLower(q + " " + translation)
+	var out []analyze.TermCount
+	for _, t := range analyze.MeSHFrequency(sample, true, 0).Terms {
+		if len(out) == max || float64(t.Count) < minMeSHShare*float64(len(sample)) || t.Count < 2 {
+			break
+		}
+		if strings.Contains(lower, strings.ToLower(t.Term)) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// And adds clause to q, parenthesizing q when it has a top-level OR so the
+// clause applies to all of it. Clauses starting with NOT are joined bare.
+func And(q, clause string) string {
+	if strings.Contains(strings.ToUpper(q), " OR ") && !(strings.HasPrefix(q, "(") && strings.HasSuffix(q, ")")) {
+		q = "(" + q + ")"
+	}
+	if strings.HasPrefix(clause, "NOT ") {
+		return q + " " + clause
+	}
+	return q + " AND " + clause
+}
+
+func animalOnly(articles []eutils.Article) int {
+	n := 0
+	for _, a := range articles {
+		var animals, humans bool
+		for _, m := range a.MeSHTerms {
+			switch m.Descriptor {
+			case "Animals":
+				animals = true
+			case "Humans":
+				humans = true
+			}
+		}
+		if animals && !humans {
+			n++
+		}
+	}
+	return n
+}
+
+func opinionPieces(articles []eutils.Article) int {
+	n := 0
+	for _, a := range articles {
+		for _, pt := range a.PublicationTypes {
+			if slices.Contains(opinionTypes, pt) {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
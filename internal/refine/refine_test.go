@@ -0,0 +1,66 @@
+package refine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func sampleArticles() []eutils.Article {
+	fxs := eutils.MeSHTerm{Descriptor: "Fragile X Syndrome", MajorTopic: true}
+	eeg := eutils.MeSHTerm{Descriptor: "Electroencephalography", MajorTopic: true}
+	humans := eutils.MeSHTerm{Descriptor: "Humans"}
+	animals := eutils.MeSHTerm{Descriptor: "Animals"}
+	return []eutils.Article{
+		{PMID: "1", Title: "Resting EEG in FXS", Year: "2024", MeSHTerms: []eutils.MeSHTerm{fxs, eeg, humans}},
+		{PMID: "2", MeSHTerms: []eutils.MeSHTerm{fxs, eeg, humans}},
+		{PMID: "3", MeSHTerms: []eutils.MeSHTerm{fxs, animals}},
+		{PMID: "4", MeSHTerms: []eutils.MeSHTerm{fxs, humans}, PublicationTypes: []string{"Journal Article", "Letter"}},
+	}
+}
+
+func TestPropose(t *testing.T) {
+	props := Propose("fragile x syndrome OR fxs", 5000, sampleArticles(), 2026)
+	var clauses []string
+	for _, p := range props {
+		clauses = append(clauses, p.Clause)
+	}
+	want := []string{
+		`"Electroencephalography"[mh]`,
+		"2022:2026[dp]",
+		"NOT (animals[mh] NOT humans[mh])",
+		"NOT (editorial[pt] OR letter[pt] OR comment[pt])",
+	}
+	if strings.Join(clauses, "|") != strings.Join(want, "|") {
+		t.Fatalf("clauses = %q, want %q", clauses, want)
+	}
+	if props[0].Query != `(fragile x syndrome OR fxs) AND "Electroencephalography"[mh]` {
+		t.Errorf("MeSH query = %q", props[0].Query)
+	}
+	if props[0].Kind != KindMeSH || props[1].Kind != KindDate || props[2].Kind != KindNot {
+		t.Errorf("kinds = %+v", props)
+	}
+}
+
+func TestPropose_SkipsWhatTheQueryHas(t *testing.T) {
+	q := `fragile x syndrome AND "Electroencephalography"[mh] AND 2020:2025[dp] NOT (animals[mh] NOT humans[mh]) NOT (editorial[pt] OR letter[pt])`
+	props := Propose(q, 150, sampleArticles()[:2], 2026)
+	if len(props) != 0 {
+		t.Errorf("expected no proposals, got %+v", props)
+	}
+}
+
+func TestAnd(t *testing.T) {
+	cases := map[[2]string]string{
+		{"a OR b", "c[mh]"}:     "(a OR b) AND c[mh]",
+		{"(a OR b)", "c[mh]"}:   "(a OR b) AND c[mh]",
+		{"a", "NOT letter[pt]"}: "a NOT letter[pt]",
+		{"a AND b", "2020[dp]"}: "a AND b AND 2020[dp]",
+	}
+	for in, want := range cases {
+		if got := And(in[0], in[1]); got != want {
+			t.Errorf("And(%q, %q) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package refine
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/query"
+)
+
+// Kinds of relaxation, alongside KindDate for dropping date limits.
+const (
+	KindDrop  = "drop"
+	KindField = "field"
+)
+
+// fieldSwaps widen a field tag: title to title/abstract, major topic to
+// any MeSH heading, and unexploded MeSH to exploded.
+var fieldSwaps = []struct {
+	re          *regexp.Regexp
+	repl        string
+	description string
+}{
+	{regexp.MustCompile(`(?i)\[(ti|title)\]`), "[tiab]", "Search titles and abstracts instead of titles only"},
+	{regexp.MustCompile(`(?i)\[(majr|mesh major topic)\]`), "[mh]", "Match MeSH headings that are not the major topic"},
+	{regexp.MustCompile(`(?i)\[(mh|mesh|mesh terms|majr|mesh major topic):noexp\]`), "[$1]", "Include narrower MeSH headings (drop :noexp)"},
+}
+
+// Relax suggests broader versions of q, which found nothing: dropping each
+// clause in turn, widening title-only and major-topic field tags, and, when
+// dated says the search had date limits outside the query, removing them.
+// A KindDate proposal with Query equal to q means removing those limits.
+// Counts are left for the caller to fill in.
+func Relax(q string, dated bool) []Proposal {
+	var out []Proposal
+	seen := map[string]bool{q: true}
+	add := func(kind, desc, clause, relaxed string) {
+		relaxed = strings.TrimSpace(relaxed)
+		if relaxed == "" || seen[relaxed] {
+			return
+		}
+		seen[relaxed] = true
+		out = append(out, Proposal{Kind: kind, Description: desc, Clause: clause, Query: relaxed})
+	}
+
+	parts := query.Split(unwrap(q))
+	operands, or := 0, false
+	for _, p := range parts {
+		if p.Operator == "" {
+			operands++
+		}
+		or = or || p.Operator == "OR"
+	}
+	// Dropping a term from an OR would narrow the query, not widen it.
+	if operands > 1 && !or {
+		for i, p := range parts {
+			if p.Operator != "" {
+				continue
+			}
+			var rest []query.Part
+			clause := p.Text
+			switch {
+			case i > 0:
+				if parts[i-1].Operator == "NOT" {
+					clause = "NOT " + clause
+				}
+				rest = append(append(rest, parts[:i-1]...), parts[i+1:]...)
+			case len(parts) > 2 && parts[1].Operator == "AND":
+				rest = parts[2:]
+			default:
+				continue // the rest would start with NOT
+			}
+			kind, desc := KindDrop, "Drop "+clause
+			if dateFieldRe.MatchString(p.Text) {
+				kind, desc = KindDate, "Remove the date limit "+p.Text
+			}
+			add(kind, desc, clause, query.Join(rest))
+		}
+	}
+
+	for _, s := range fieldSwaps {
+		if m := s.re.FindString(q); m != "" {
+			add(KindField, s.description, m, s.re.ReplaceAllString(q, s.repl))
+		}
+	}
+
+	if dated {
+		out = append(out, Proposal{Kind: KindDate, Description: "Remove the date limits", Query: q})
+	}
+	return out
+}
+
+// unwrap removes parentheses enclosing all of q.
+func unwrap(q string) string {
+	q = strings.TrimSpace(q)
+	for strings.HasPrefix(q, "(") && strings.HasSuffix(q, ")") {
+		inner := q[1 : len(q)-1]
+		if depth := balance(inner); depth != 0 {
+			break
+		}
+		q = strings.TrimSpace(inner)
+	}
+	return q
+}
+
+// balance returns the nesting depth s's parentheses end at, or a negative
+// depth as soon as one closes before it opens.
+func balance(s string) int {
+	depth := 0
+	for _, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth < 0 {
+				return depth
+			}
+		}
+	}
+	return depth
+}
@@ -0,0 +1,45 @@
+package refine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelax(t *testing.T) {
+	got := Relax(`(fragile x[majr] AND sleep[ti] AND 2024[dp] NOT review[pt])`, true)
+	var queries, kinds []string
+	for _, p := range got {
+		queries = append(queries, p.Query)
+		kinds = append(kinds, p.Kind)
+	}
+	wantQueries := []string{
+		"sleep[ti] AND 2024[dp] NOT review[pt]",
+		"fragile x[majr] AND 2024[dp] NOT review[pt]",
+		"fragile x[majr] AND sleep[ti] NOT review[pt]",
+		"fragile x[majr] AND sleep[ti] AND 2024[dp]",
+		"(fragile x[majr] AND sleep[tiab] AND 2024[dp] NOT review[pt])",
+		"(fragile x[mh] AND sleep[ti] AND 2024[dp] NOT review[pt])",
+		"(fragile x[majr] AND sleep[ti] AND 2024[dp] NOT review[pt])",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("queries = %q\nwant %q", queries, wantQueries)
+	}
+	wantKinds := []string{KindDrop, KindDrop, KindDate, KindDrop, KindField, KindField, KindDate}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("kinds = %q, want %q", kinds, wantKinds)
+	}
+	if got[3].Clause != "NOT review[pt]" {
+		t.Errorf("clause = %q, want the NOT clause", got[3].Clause)
+	}
+}
+
+func TestRelax_KeepsORTerms(t *testing.T) {
+	got := Relax(`autism OR asd`, false)
+	if len(got) != 0 {
+		t.Errorf("Relax = %+v, want nothing for an OR query", got)
+	}
+	got = Relax(`"x"[mh:noexp] AND y`, false)
+	if len(got) != 3 || got[2].Query != `"x"[mh] AND y` {
+		t.Errorf("Relax = %+v, want the :noexp dropped last", got)
+	}
+}
@@ -0,0 +1,190 @@
+package refine
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// SampleSize is how many top results are fetched to derive proposals.
+const SampleSize = 50
+
+// shownHits is how many top results each round lists.
+const shownHits = 5
+
+// ErrQuit is returned when the user leaves a session without accepting a
+// query.
+var ErrQuit = errors.New("refinement cancelled")
+
+// Client runs the searches a session needs. *eutils.Client satisfies it.
+type Client interface {
+	Search(ctx context.Context, query string, opts *eutils.SearchOptions) (*eutils.SearchResult, error)
+	Count(ctx context.Context, query string, opts *eutils.SearchOptions) (int, error)
+	Fetch(ctx context.Context, pmids []string) ([]eutils.Article, error)
+}
+
+// Session refines a query interactively: each round shows the result count,
+// the top hits, and proposed refinements with their counts, and reads the
+// user's choice from In.
+type Session struct {
+	Client Client
+	In     io.Reader
+	Out    io.Writer
+	// Year is the current year, for date-range proposals; zero means now.
+	Year int
+}
+
+// round is what a session learned about one query.
+type round struct {
+	count     int
+	hits      []eutils.Article
+	proposals []Proposal
+}
+
+// Run refines q until the user is done, returning the accepted query, or
+// ErrQuit. End of input accepts the current query.
+func (s *Session) Run(ctx context.Context, q string) (string, error) {
+	if s.Year == 0 {
+		s.Year = time.Now().Year()
+	}
+	var (
+		history  = []string{q}
+		rounds   = make(map[string]*round)
+		rejected = make(map[string]bool)
+		in       = bufio.NewScanner(s.In)
+	)
+	for {
+		cur := history[len(history)-1]
+		r, ok := rounds[cur]
+		if !ok {
+			var err error
+			if r, err = s.evaluate(ctx, cur); err != nil {
+				return "", err
+			}
+			rounds[cur] = r
+		}
+		var offered []Proposal
+		for _, p := range r.proposals {
+			if !rejected[p.Clause] {
+				offered = append(offered, p)
+			}
+		}
+		s.show(cur, r, offered)
+
+		fmt.Fprint(s.Out, "\nAccept [number], reject [x number], edit [e], undo [u], done [d], quit [q]: ")
+		if !in.Scan() {
+			fmt.Fprintln(s.Out)
+			return cur, in.Err()
+		}
+		choice := strings.TrimSpace(in.Text())
+		switch {
+		case choice == "":
+		case choice == "d":
+			return cur, nil
+		case choice == "q":
+			return "", ErrQuit
+		case choice == "u":
+			if len(history) > 1 {
+				history = history[:len(history)-1]
+			}
+		case choice == "e":
+			fmt.Fprint(s.Out, "New query: ")
+			if in.Scan() {
+				if edited := strings.TrimSpace(in.Text()); edited != "" {
+					history = append(history, edited)
+				}
+			}
+		case strings.HasPrefix(choice, "x"):
+			if p, ok := pick(offered, strings.TrimSpace(choice[1:])); ok {
+				rejected[p.Clause] = true
+			} else {
+				fmt.Fprintf(s.Out, "No refinement %q.\n", strings.TrimSpace(choice[1:]))
+			}
+		default:
+			if p, ok := pick(offered, choice); ok {
+				history = append(history, p.Query)
+			} else {
+				fmt.Fprintf(s.Out, "Unrecognized choice %q.\n", choice)
+			}
+		}
+	}
+}
+
+// evaluate searches q, fetches its top results, and counts the results of
+// each proposed refinement.
+func (s *Session) evaluate(ctx context.Context, q string) (*round, error) {
+	res, err := s.Client.Search(ctx, q, &eutils.SearchOptions{Limit: SampleSize})
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	r := &round{count: res.Count}
+	if len(res.IDs) > 0 {
+		sample, err := s.Client.Fetch(ctx, res.IDs)
+		if err != nil {
+			return nil, fmt.Errorf("fetch failed: %w", err)
+		}
+		r.hits = orderByIDs(sample, res.IDs)
+	}
+	r.proposals = Propose(q, res.Count, r.hits, s.Year)
+	for i := range r.proposals {
+		n, err := s.Client.Count(ctx, r.proposals[i].Query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("counting %q: %w", r.proposals[i].Query, err)
+		}
+		r.proposals[i].Count = n
+	}
+	return r, nil
+}
+
+func (s *Session) show(q string, r *round, offered []Proposal) {
+	fmt.Fprintf(s.Out, "\nQuery: %s\n%d results\n", q, r.count)
+	for i, a := range r.hits {
+		if i == shownHits {
+			break
+		}
+		title := []rune(a.Title)
+		if len(title) > 90 {
+			title = append(title[:87], []rune("...")...)
+		}
+		fmt.Fprintf(s.Out, "  %d. %s (%s) PMID %s\n", i+1, string(title), a.Year, a.PMID)
+	}
+	if len(offered) == 0 {
+		fmt.Fprintln(s.Out, "\nNo further refinements to propose.")
+		return
+	}
+	fmt.Fprintln(s.Out, "\nRefinements:")
+	for i, p := range offered {
+		fmt.Fprintf(s.Out, "  [%d] %s -> %d results\n      %s\n", i+1, p.Description, p.Count, p.Clause)
+	}
+}
+
+func pick(offered []Proposal, choice string) (Proposal, bool) {
+	n, err := strconv.Atoi(choice)
+	if err != nil || n < 1 || n > len(offered) {
+		return Proposal{}, false
+	}
+	return offered[n-1], true
+}
+
+// orderByIDs returns articles in the order of ids; efetch does not
+// guarantee it.
+func orderByIDs(articles []eutils.Article, ids []string) []eutils.Article {
+	byPMID := make(map[string]eutils.Article, len(articles))
+	for _, a := range articles {
+		byPMID[a.PMID] = a
+	}
+	ordered := make([]eutils.Article, 0, len(ids))
+	for _, id := range ids {
+		if a, ok := byPMID[id]; ok {
+			ordered = append(ordered, a)
+		}
+	}
+	return ordered
+}
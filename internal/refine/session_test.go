@@ -0,0 +1,66 @@
+package refine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// fakeClient returns 300 results and the sample articles for any query.
+type fakeClient struct {
+	searched []string
+}
+
+func (f *fakeClient) Search(_ context.Context, q string, _ *eutils.SearchOptions) (*eutils.SearchResult, error) {
+	f.searched = append(f.searched, q)
+	return &eutils.SearchResult{Count: 300, IDs: []string{"4", "3", "2", "1"}}, nil
+}
+
+func (f *fakeClient) Count(_ context.Context, q string, _ *eutils.SearchOptions) (int, error) {
+	return 100, nil
+}
+
+func (f *fakeClient) Fetch(_ context.Context, pmids []string) ([]eutils.Article, error) {
+	return sampleArticles(), nil
+}
+
+func TestSession_Run(t *testing.T) {
+	client := &fakeClient{}
+	var out bytes.Buffer
+	s := &Session{Client: client, In: strings.NewReader("x 1\n1\nu\n3\nd\n"), Out: &out, Year: 2026}
+	got, err := s.Run(context.Background(), "fxs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Rejecting the first MeSH term makes the second [1]; after accepting
+	// and undoing it, [3] is the animal-study exclusion.
+	if want := "fxs NOT (animals[mh] NOT humans[mh])"; got != want {
+		t.Errorf("query = %q, want %q", got, want)
+	}
+	// The original query is searched once despite being shown three times.
+	if len(client.searched) != 3 {
+		t.Errorf("searched %q", client.searched)
+	}
+	if !strings.Contains(out.String(), "  4. Resting EEG in FXS (2024) PMID 1") || !strings.Contains(out.String(), "-> 100 results") {
+		t.Errorf("unexpected session output:\n%s", out.String())
+	}
+}
+
+func TestSession_Quit(t *testing.T) {
+	s := &Session{Client: &fakeClient{}, In: strings.NewReader("e\nautism\nq\n"), Out: &bytes.Buffer{}}
+	if _, err := s.Run(context.Background(), "fxs"); !errors.Is(err, ErrQuit) {
+		t.Errorf("expected ErrQuit, got %v", err)
+	}
+}
+
+func TestSession_EOFAccepts(t *testing.T) {
+	s := &Session{Client: &fakeClient{}, In: strings.NewReader("e\nautism\n"), Out: &bytes.Buffer{}}
+	got, err := s.Run(context.Background(), "fxs")
+	if err != nil || got != "autism" {
+		t.Errorf("got %q, %v; want the edited query", got, err)
+	}
+}
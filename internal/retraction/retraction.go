@@ -0,0 +1,110 @@
+// Package retraction flags retracted, corrected, and expression-of-concern
+// articles from their PubMed publication types and CommentsCorrections
+// links.
+package retraction
+
+import "github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+
+// Statuses, most serious first.
+const (
+	Retracted           = "retracted"
+	ExpressionOfConcern = "expression of concern"
+	Corrected           = "corrected"
+)
+
+// Statuses lists the statuses in order of severity.
+var Statuses = []string{Retracted, ExpressionOfConcern, Corrected}
+
+// retractedType is the publication type PubMed gives retracted articles.
+const retractedType = "Retracted Publication"
+
+// noticeTypes maps the CommentsCorrections links that flag an article to
+// the status they imply and a label for the linked notice.
+var noticeTypes = map[string]struct{ status, label string }{
+	"RetractionIn":              {Retracted, "Retraction"},
+	"ExpressionOfConcernIn":     {ExpressionOfConcern, "Expression of concern"},
+	"ErratumIn":                 {Corrected, "Erratum"},
+	"CorrectedandRepublishedIn": {Corrected, "Corrected and republished"},
+}
+
+// Notice is a published notice about an article.
+type Notice struct {
+	Label  string `json:"label"`
+	Source string `json:"source,omitempty"`
+	PMID   string `json:"pmid,omitempty"`
+}
+
+// Finding is the status of one checked article.
+type Finding struct {
+	// Reference identifies the input the article came from, such as a RIS
+	// record, when it is not simply the PMID.
+	Reference string   `json:"reference,omitempty"`
+	PMID      string   `json:"pmid"`
+	Title     string   `json:"title"`
+	Journal   string   `json:"journal,omitempty"`
+	Year      string   `json:"year,omitempty"`
+	DOI       string   `json:"doi,omitempty"`
+	Statuses  []string `json:"statuses"`
+	Notices   []Notice `json:"notices,omitempty"`
+}
+
+// Flagged reports whether the article is retracted, corrected, or under an
+// expression of concern.
+func (f Finding) Flagged() bool {
+	return len(f.Statuses) > 0
+}
+
+// Check reports an article's statuses, in order of severity, and the
+// notices behind them. Notices themselves (retraction notices, errata) are
+// not flagged; citing one is not a problem.
+func Check(a eutils.Article) Finding {
+	f := Finding{PMID: a.PMID, Title: a.Title, Journal: a.JournalAbbrev, Year: a.Year, DOI: a.DOI, Statuses: []string{}}
+	if f.Journal == "" {
+		f.Journal = a.Journal
+	}
+	found := make(map[string]bool)
+	for _, pt := range a.PublicationTypes {
+		if pt == retractedType {
+			found[Retracted] = true
+		}
+	}
+	for _, cc := range a.CommentsCorrections {
+		nt, ok := noticeTypes[cc.Type]
+		if !ok {
+			continue
+		}
+		found[nt.status] = true
+		f.Notices = append(f.Notices, Notice{Label: nt.label, Source: cc.Source, PMID: cc.PMID})
+	}
+	for _, s := range Statuses {
+		if found[s] {
+			f.Statuses = append(f.Statuses, s)
+		}
+	}
+	return f
+}
+
+// Report is the result of checking a reference list.
+type Report struct {
+	Checked int       `json:"checked"`
+	Flagged []Finding `json:"flagged"`
+	// Unmatched lists references that could not be found in PubMed.
+	Unmatched []string `json:"unmatched,omitempty"`
+}
+
+// NewReport checks articles, keeping the flagged ones. references, when
+// given, labels each article by its position in the input.
+func NewReport(articles []eutils.Article, references []string) Report {
+	r := Report{Checked: len(articles), Flagged: []Finding{}}
+	for i, a := range articles {
+		f := Check(a)
+		if !f.Flagged() {
+			continue
+		}
+		if i < len(references) {
+			f.Reference = references[i]
+		}
+		r.Flagged = append(r.Flagged, f)
+	}
+	return r
+}
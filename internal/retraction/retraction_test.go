@@ -0,0 +1,65 @@
+package retraction
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestCheck(t *testing.T) {
+	a := eutils.Article{
+		PMID:             "31000001",
+		Title:            "A retracted trial",
+		Journal:          "Journal of Things",
+		PublicationTypes: []string{"Journal Article", "Retracted Publication"},
+		CommentsCorrections: []eutils.CommentCorrection{
+			{Type: "ErratumIn", Source: "J Things. 2020;3:4", PMID: "31000003"},
+			{Type: "RetractionIn", Source: "J Things. 2021;4:5", PMID: "31000004"},
+		},
+	}
+	f := Check(a)
+	if !f.Flagged() {
+		t.Fatal("expected article to be flagged")
+	}
+	if want := []string{Retracted, Corrected}; !reflect.DeepEqual(f.Statuses, want) {
+		t.Errorf("statuses = %v, want %v", f.Statuses, want)
+	}
+	if len(f.Notices) != 2 || f.Notices[0].Label != "Erratum" || f.Notices[1].PMID != "31000004" {
+		t.Errorf("notices = %+v", f.Notices)
+	}
+	if f.Journal != "Journal of Things" {
+		t.Errorf("journal = %q", f.Journal)
+	}
+}
+
+func TestCheck_NotFlagged(t *testing.T) {
+	cases := map[string]eutils.Article{
+		"clean": {PMID: "1", PublicationTypes: []string{"Journal Article"}},
+		"notice": {
+			PMID:                "2",
+			PublicationTypes:    []string{"Retraction of Publication"},
+			CommentsCorrections: []eutils.CommentCorrection{{Type: "RetractionOf", PMID: "1"}},
+		},
+	}
+	for name, a := range cases {
+		if f := Check(a); f.Flagged() {
+			t.Errorf("%s: unexpectedly flagged as %v", name, f.Statuses)
+		}
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1"},
+		{PMID: "2", CommentsCorrections: []eutils.CommentCorrection{{Type: "ExpressionOfConcernIn"}}},
+	}
+	r := NewReport(articles, []string{"refs.ris #1", "refs.ris #2"})
+	if r.Checked != 2 || len(r.Flagged) != 1 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	f := r.Flagged[0]
+	if f.Reference != "refs.ris #2" || !reflect.DeepEqual(f.Statuses, []string{ExpressionOfConcern}) {
+		t.Errorf("flagged = %+v", f)
+	}
+}
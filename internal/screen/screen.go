@@ -0,0 +1,190 @@
+// Package screen keeps a title/abstract screening log: the articles under
+// review and the include/exclude/maybe decision recorded for each.
+package screen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Screening decisions.
+const (
+	Include = "include"
+	Exclude = "exclude"
+	Maybe   = "maybe"
+)
+
+// Decisions lists the valid decisions in display order.
+var Decisions = []string{Include, Exclude, Maybe}
+
+// DefaultPath is the log file used when none is given.
+const DefaultPath = "screening.json"
+
+// Item is one article under review and its decision, if any.
+type Item struct {
+	Article   eutils.Article `json:"article"`
+	Decision  string         `json:"decision,omitempty"`
+	DecidedAt time.Time      `json:"decided_at,omitzero"`
+}
+
+// Progress counts decisions across a log.
+type Progress struct {
+	Total   int `json:"total"`
+	Include int `json:"include"`
+	Exclude int `json:"exclude"`
+	Maybe   int `json:"maybe"`
+}
+
+// Decided is the number of items with a decision.
+func (p Progress) Decided() int {
+	return p.Include + p.Exclude + p.Maybe
+}
+
+// Log is a screening session backed by a JSON file.
+type Log struct {
+	path    string
+	Query   string    `json:"query,omitempty"`
+	Created time.Time `json:"created"`
+	Items   []*Item   `json:"items"`
+}
+
+// Load reads the log stored at path; a missing file is an empty log.
+func Load(path string) (*Log, error) {
+	l := &Log{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading screening log: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Path returns the file the log is saved to.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Save writes the log back to its file, replacing it atomically.
+func (l *Log) Save() error {
+	if dir := filepath.Dir(l.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating screening log directory: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing screening log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("writing screening log: %w", err)
+	}
+	return nil
+}
+
+// Add appends the articles not already in the log and returns how many
+// were added. Existing decisions are kept.
+func (l *Log) Add(articles []eutils.Article) int {
+	seen := make(map[string]bool, len(l.Items))
+	for _, it := range l.Items {
+		seen[it.Article.PMID] = true
+	}
+	added := 0
+	for _, a := range articles {
+		if seen[a.PMID] {
+			continue
+		}
+		seen[a.PMID] = true
+		l.Items = append(l.Items, &Item{Article: a})
+		added++
+	}
+	return added
+}
+
+// Decide records decision for item i; an empty decision clears it.
+func (l *Log) Decide(i int, decision string, now time.Time) error {
+	if i < 0 || i >= len(l.Items) {
+		return fmt.Errorf("no item %d", i)
+	}
+	if decision != "" && !ValidDecision(decision) {
+		return fmt.Errorf("unknown decision %q (use include, exclude, or maybe)", decision)
+	}
+	it := l.Items[i]
+	it.Decision = decision
+	it.DecidedAt = time.Time{}
+	if decision != "" {
+		it.DecidedAt = now
+	}
+	return nil
+}
+
+// Next returns the first undecided item at or after from, wrapping around
+// to the start, or -1 when every item is decided.
+func (l *Log) Next(from int) int {
+	n := len(l.Items)
+	for k := 0; k < n; k++ {
+		i := (from + k) % n
+		if i < 0 {
+			i += n
+		}
+		if l.Items[i].Decision == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// Progress counts the decisions made so far.
+func (l *Log) Progress() Progress {
+	p := Progress{Total: len(l.Items)}
+	for _, it := range l.Items {
+		switch it.Decision {
+		case Include:
+			p.Include++
+		case Exclude:
+			p.Exclude++
+		case Maybe:
+			p.Maybe++
+		}
+	}
+	return p
+}
+
+// Filter returns the items with the given decision; "undecided" selects
+// items without one and an empty decision selects all.
+func (l *Log) Filter(decision string) []*Item {
+	if decision == "" {
+		return l.Items
+	}
+	var out []*Item
+	for _, it := range l.Items {
+		if it.Decision == decision || (decision == "undecided" && it.Decision == "") {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// ValidDecision reports whether d is one of Decisions.
+func ValidDecision(d string) bool {
+	for _, v := range Decisions {
+		if d == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package screen
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestLogSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "screening.json")
+	l, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load missing file: %v", err)
+	}
+	if len(l.Items) != 0 {
+		t.Fatalf("expected empty log, got %d items", len(l.Items))
+	}
+
+	l.Query = "fragile x"
+	if n := l.Add([]eutils.Article{{PMID: "1"}, {PMID: "2"}, {PMID: "1"}}); n != 2 {
+		t.Errorf("Add = %d, want 2", n)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := l.Decide(1, Include, now); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.Query != "fragile x" || len(got.Items) != 2 {
+		t.Fatalf("unexpected log: %+v", got)
+	}
+	if it := got.Items[1]; it.Decision != Include || !it.DecidedAt.Equal(now) {
+		t.Errorf("decision not persisted: %+v", it)
+	}
+	if n := got.Add([]eutils.Article{{PMID: "2"}, {PMID: "3"}}); n != 1 || got.Items[1].Decision != Include {
+		t.Errorf("Add should keep existing decisions and append new articles (added %d)", n)
+	}
+}
+
+func TestLogDecide(t *testing.T) {
+	l := &Log{}
+	l.Add([]eutils.Article{{PMID: "1"}})
+	if err := l.Decide(0, "perhaps", time.Now()); err == nil {
+		t.Error("expected unknown decision to be rejected")
+	}
+	if err := l.Decide(3, Include, time.Now()); err == nil {
+		t.Error("expected out-of-range item to be rejected")
+	}
+	l.Decide(0, Maybe, time.Now())
+	l.Decide(0, "", time.Now())
+	if it := l.Items[0]; it.Decision != "" || !it.DecidedAt.IsZero() {
+		t.Errorf("expected decision to be cleared, got %+v", it)
+	}
+}
+
+func TestLogNextProgressFilter(t *testing.T) {
+	l := &Log{}
+	l.Add([]eutils.Article{{PMID: "1"}, {PMID: "2"}, {PMID: "3"}, {PMID: "4"}})
+	now := time.Now()
+	l.Decide(0, Include, now)
+	l.Decide(2, Exclude, now)
+
+	if got := l.Next(0); got != 1 {
+		t.Errorf("Next(0) = %d, want 1", got)
+	}
+	if got := l.Next(2); got != 3 {
+		t.Errorf("Next(2) = %d, want 3", got)
+	}
+	l.Decide(3, Maybe, now)
+	if got := l.Next(2); got != 1 {
+		t.Errorf("Next(2) should wrap to 1, got %d", got)
+	}
+	l.Decide(1, Include, now)
+	if got := l.Next(0); got != -1 {
+		t.Errorf("Next with everything decided = %d, want -1", got)
+	}
+
+	p := l.Progress()
+	if p.Total != 4 || p.Include != 2 || p.Exclude != 1 || p.Maybe != 1 || p.Decided() != 4 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+	if got := l.Filter(Include); len(got) != 2 || got[0].Article.PMID != "1" || got[1].Article.PMID != "2" {
+		t.Errorf("Filter(include) = %+v", got)
+	}
+	if got := l.Filter(""); len(got) != 4 {
+		t.Errorf("Filter(\"\") returned %d items", len(got))
+	}
+	l.Decide(3, "", now)
+	if got := l.Filter("undecided"); len(got) != 1 || got[0].Article.PMID != "4" {
+		t.Errorf("Filter(undecided) = %+v", got)
+	}
+}
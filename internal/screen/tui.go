@@ -0,0 +1,202 @@
+package screen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Run opens the screening UI on out, reading keys from the terminal (so
+// stdin stays free for piped PMIDs) and saving after every decision. It
+// returns when the user quits or every item has a decision.
+func Run(l *Log, out io.Writer) error {
+	m := newModel(l, time.Now)
+	final, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithInputTTY(), tea.WithOutput(out)).Run()
+	if err != nil {
+		return err
+	}
+	return final.(model).err
+}
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true)
+	dimStyle     = lipgloss.NewStyle().Faint(true)
+	barStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	decisionTags = map[string]lipgloss.Style{
+		Include: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2")),
+		Exclude: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1")),
+		Maybe:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3")),
+	}
+)
+
+// model is the bubbletea state for one screening session.
+type model struct {
+	log    *Log
+	cur    int
+	scroll int
+	width  int
+	height int
+	now    func() time.Time
+	err    error
+}
+
+func newModel(l *Log, now func() time.Time) model {
+	cur := l.Next(0)
+	if cur < 0 {
+		cur = 0
+	}
+	return model{log: l, cur: cur, width: 80, height: 24, now: now}
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "i", "y":
+			return m.decide(Include)
+		case "e", "n":
+			return m.decide(Exclude)
+		case "m", "?":
+			return m.decide(Maybe)
+		case "u", "backspace":
+			return m.decide("")
+		case "right", "l", "tab":
+			m.move(1)
+		case "left", "h", "shift+tab":
+			m.move(-1)
+		case "down", "j":
+			m.scroll++
+		case "up", "k":
+			if m.scroll > 0 {
+				m.scroll--
+			}
+		}
+	}
+	return m, nil
+}
+
+// decide records d for the current item, saves, and moves to the next
+// undecided item (staying put when clearing a decision).
+func (m model) decide(d string) (tea.Model, tea.Cmd) {
+	if len(m.log.Items) == 0 {
+		return m, tea.Quit
+	}
+	if err := m.log.Decide(m.cur, d, m.now()); err != nil {
+		m.err = err
+		return m, tea.Quit
+	}
+	if err := m.log.Save(); err != nil {
+		m.err = err
+		return m, tea.Quit
+	}
+	if d == "" {
+		return m, nil
+	}
+	next := m.log.Next(m.cur + 1)
+	if next < 0 {
+		return m, tea.Quit
+	}
+	m.cur, m.scroll = next, 0
+	return m, nil
+}
+
+func (m *model) move(delta int) {
+	n := len(m.log.Items)
+	if n == 0 {
+		return
+	}
+	m.cur = (m.cur + delta + n) % n
+	m.scroll = 0
+}
+
+func (m model) View() string {
+	if len(m.log.Items) == 0 {
+		return "Nothing to screen.\n"
+	}
+	width := m.width
+	if width > 100 {
+		width = 100
+	}
+	var b strings.Builder
+
+	p := m.log.Progress()
+	fmt.Fprintf(&b, "%s  %d/%d decided  %s %d  %s %d  %s %d\n",
+		titleStyle.Render("Screening"), p.Decided(), p.Total,
+		decisionTags[Include].Render("include"), p.Include,
+		decisionTags[Exclude].Render("exclude"), p.Exclude,
+		decisionTags[Maybe].Render("maybe"), p.Maybe)
+	b.WriteString(barStyle.Render(progressBar(p.Decided(), p.Total, width)) + "\n\n")
+
+	it := m.log.Items[m.cur]
+	a := it.Article
+	status := dimStyle.Render("undecided")
+	if it.Decision != "" {
+		status = decisionTags[it.Decision].Render(strings.ToUpper(it.Decision))
+	}
+	fmt.Fprintf(&b, "[%d/%d] %s\n", m.cur+1, p.Total, status)
+	b.WriteString(dimStyle.Render(articleMeta(a)) + "\n\n")
+	b.WriteString(titleStyle.Render(lipgloss.NewStyle().Width(width).Render(a.Title)) + "\n\n")
+
+	header := strings.Count(b.String(), "\n")
+	room := m.height - header - 3
+	if room < 3 {
+		room = 3
+	}
+	abstract := a.Abstract
+	if abstract == "" {
+		abstract = dimStyle.Render("(no abstract)")
+	}
+	lines := strings.Split(lipgloss.NewStyle().Width(width).Render(abstract), "\n")
+	start := m.scroll
+	if start > len(lines)-room {
+		start = len(lines) - room
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + room
+	if end > len(lines) {
+		end = len(lines)
+	}
+	b.WriteString(strings.Join(lines[start:end], "\n") + "\n")
+	if end < len(lines) {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  … %d more lines (j/↓)", len(lines)-end)) + "\n")
+	}
+
+	b.WriteString("\n" + dimStyle.Render("i include · e exclude · m maybe · u undo · ←/→ move · j/k scroll · q quit"))
+	return b.String()
+}
+
+// articleMeta is the PMID, journal, and year line shown above the title.
+func articleMeta(a eutils.Article) string {
+	parts := []string{"PMID " + a.PMID}
+	if a.Journal != "" {
+		parts = append(parts, a.Journal)
+	}
+	if a.Year != "" {
+		parts = append(parts, a.Year)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// progressBar draws done/total as a bar width cells wide.
+func progressBar(done, total, width int) string {
+	if total == 0 || width <= 0 {
+		return ""
+	}
+	filled := done * width / total
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
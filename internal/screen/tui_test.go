@@ -0,0 +1,79 @@
+package screen
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func key(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModelDecisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "screening.json")
+	l, _ := Load(path)
+	l.Add([]eutils.Article{
+		{PMID: "1", Title: "First", Abstract: "Background."},
+		{PMID: "2", Title: "Second"},
+		{PMID: "3", Title: "Third"},
+	})
+	l.Decide(0, Exclude, time.Now())
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var tm tea.Model = newModel(l, func() time.Time { return now })
+	if m := tm.(model); m.cur != 1 {
+		t.Fatalf("expected to resume at the first undecided item, got %d", m.cur)
+	}
+
+	tm, _ = tm.Update(key("i"))
+	if l.Items[1].Decision != Include || tm.(model).cur != 2 {
+		t.Fatalf("include should record and advance: %+v cur=%d", l.Items[1], tm.(model).cur)
+	}
+	saved, err := Load(path)
+	if err != nil || saved.Items[1].Decision != Include {
+		t.Fatalf("decision not saved: %v", err)
+	}
+
+	tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	tm, _ = tm.Update(key("u"))
+	if l.Items[1].Decision != "" || tm.(model).cur != 1 {
+		t.Errorf("undo should clear and stay: %+v cur=%d", l.Items[1], tm.(model).cur)
+	}
+
+	tm, _ = tm.Update(key("m"))
+	_, cmd := tm.Update(key("e"))
+	if l.Items[1].Decision != Maybe || l.Items[2].Decision != Exclude {
+		t.Errorf("unexpected decisions: %+v %+v", l.Items[1], l.Items[2])
+	}
+	if cmd == nil {
+		t.Fatal("expected the UI to quit once every item is decided")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected a quit command")
+	}
+}
+
+func TestModelView(t *testing.T) {
+	l := &Log{}
+	l.Add([]eutils.Article{{PMID: "7", Title: "Metformin in fragile X", Journal: "J Neurodev Disord", Year: "2024"}})
+	view := newModel(l, time.Now).View()
+	for _, want := range []string{"0/1 decided", "PMID 7 · J Neurodev Disord · 2024", "Metformin in fragile X", "(no abstract)", "q quit"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	if got := progressBar(1, 4, 8); got != "██░░░░░░" {
+		t.Errorf("progressBar = %q", got)
+	}
+	if got := progressBar(0, 0, 8); got != "" {
+		t.Errorf("progressBar with no items = %q", got)
+	}
+}
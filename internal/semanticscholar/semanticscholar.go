@@ -0,0 +1,143 @@
+// Package semanticscholar enriches PubMed records with Semantic Scholar's
+// influential citation counts and TLDR summaries.
+package semanticscholar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// DefaultURL is the Semantic Scholar Graph API.
+const DefaultURL = "https://api.semanticscholar.org/graph/v1"
+
+// EnvAPIKey sets the Semantic Scholar API key, which raises its rate limit.
+// The API also works without one.
+const EnvAPIKey = "S2_API_KEY"
+
+// BatchSize is the most papers one batch request may name.
+const BatchSize = 500
+
+// Client queries the Semantic Scholar Graph API. Requests share the NCBI
+// base client's HTTP client, rate limit, and response size cap.
+type Client struct {
+	*ncbi.BaseClient
+	URL    string
+	APIKey string
+}
+
+// NewClient creates a Semantic Scholar client using an existing NCBI base
+// client.
+func NewClient(base *ncbi.BaseClient, apiKey string) *Client {
+	return &Client{BaseClient: base, URL: DefaultURL, APIKey: apiKey}
+}
+
+// Paper is the part of a Semantic Scholar paper record pubmed-cli uses.
+type Paper struct {
+	ExternalIDs struct {
+		PubMed string `json:"PubMed"`
+	} `json:"externalIds"`
+	CitationCount            int `json:"citationCount"`
+	InfluentialCitationCount int `json:"influentialCitationCount"`
+	TLDR                     *struct {
+		Text string `json:"text"`
+	} `json:"tldr"`
+}
+
+// Papers looks up pmids in Semantic Scholar, BatchSize at a time, and
+// returns the papers it knows indexed by PMID.
+func (c *Client) Papers(ctx context.Context, pmids []string) (map[string]Paper, error) {
+	papers := make(map[string]Paper, len(pmids))
+	for start := 0; start < len(pmids); start += BatchSize {
+		batch := pmids[start:min(start+BatchSize, len(pmids))]
+		ids := make([]string, len(batch))
+		for i, id := range batch {
+			ids[i] = "PMID:" + id
+		}
+		body, err := json.Marshal(map[string][]string{"ids": ids})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			c.URL+"/paper/batch?fields=externalIds,citationCount,influentialCitationCount,tldr", bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.APIKey != "" {
+			req.Header.Set("x-api-key", c.APIKey)
+		}
+		data, err := c.Send(req)
+		if err != nil {
+			return nil, fmt.Errorf("Semantic Scholar lookup failed: %w", err)
+		}
+		if data == nil {
+			continue
+		}
+		// The response lists one entry per requested ID, null for papers
+		// Semantic Scholar does not know.
+		var resp []*Paper
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("parsing Semantic Scholar response: %w", err)
+		}
+		for i, p := range resp {
+			if p != nil && i < len(batch) {
+				papers[batch[i]] = *p
+			}
+		}
+	}
+	return papers, nil
+}
+
+// Enrich sets a's influential citation count and TLDR from p.
+func Enrich(a *eutils.Article, p Paper) {
+	a.InfluentialCitationCount = p.InfluentialCitationCount
+	a.TLDR = ""
+	if p.TLDR != nil {
+		a.TLDR = p.TLDR.Text
+	}
+}
+
+// Annotate enriches each article Semantic Scholar knows. Articles it does
+// not know are left unchanged.
+func (c *Client) Annotate(ctx context.Context, articles []eutils.Article) error {
+	pmids := make([]string, 0, len(articles))
+	for _, a := range articles {
+		if a.PMID != "" {
+			pmids = append(pmids, a.PMID)
+		}
+	}
+	papers, err := c.Papers(ctx, pmids)
+	if err != nil {
+		return err
+	}
+	for i := range articles {
+		if p, ok := papers[articles[i].PMID]; ok {
+			Enrich(&articles[i], p)
+		}
+	}
+	return nil
+}
+
+// SortByInfluence orders pmids by their influential citation counts, then
+// by total citations, most influential first. PMIDs Semantic Scholar does
+// not know sort last; ties keep their original order.
+func SortByInfluence(pmids []string, papers map[string]Paper) {
+	sort.SliceStable(pmids, func(i, j int) bool {
+		pi, oki := papers[pmids[i]]
+		pj, okj := papers[pmids[j]]
+		if oki != okj {
+			return oki
+		}
+		if pi.InfluentialCitationCount != pj.InfluentialCitationCount {
+			return pi.InfluentialCitationCount > pj.InfluentialCitationCount
+		}
+		return pi.CitationCount > pj.CitationCount
+	})
+}
@@ -0,0 +1,69 @@
+package semanticscholar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	batch, err := os.ReadFile(filepath.Join("..", "..", "testdata", "s2_batch.json"))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/paper/batch" || r.Header.Get("x-api-key") != "key" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var body struct {
+			IDs []string `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || strings.Join(body.IDs, ",") != "PMID:38000001,PMID:38000002,PMID:38000003" {
+			t.Errorf("unexpected body %v (%v)", body, err)
+		}
+		w.Write(batch)
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient(ncbi.NewBaseClient(ncbi.WithHTTPClient(srv.Client())), "key")
+	c.URL = srv.URL
+	return c
+}
+
+func TestAnnotate(t *testing.T) {
+	c := newTestClient(t)
+	articles := []eutils.Article{{PMID: "38000001"}, {PMID: "38000002"}, {PMID: "38000003"}}
+	if err := c.Annotate(context.Background(), articles); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if articles[0].InfluentialCitationCount != 3 || !strings.HasPrefix(articles[0].TLDR, "Metformin did not") {
+		t.Errorf("unexpected enrichment: %+v", articles[0])
+	}
+	if articles[1].InfluentialCitationCount != 0 || articles[1].TLDR != "" {
+		t.Errorf("unknown paper should be unchanged: %+v", articles[1])
+	}
+	if articles[2].InfluentialCitationCount != 3 || articles[2].TLDR != "" {
+		t.Errorf("unexpected enrichment without a TLDR: %+v", articles[2])
+	}
+}
+
+func TestSortByInfluence(t *testing.T) {
+	c := newTestClient(t)
+	papers, err := c.Papers(context.Background(), []string{"38000001", "38000002", "38000003"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pmids := []string{"38000002", "38000003", "38000001"}
+	SortByInfluence(pmids, papers)
+	if got := strings.Join(pmids, ","); got != "38000001,38000003,38000002" {
+		t.Errorf("expected influence, then citations, with unknown last; got %s", got)
+	}
+}
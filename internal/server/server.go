@@ -0,0 +1,379 @@
+// Package server exposes the PubMed search, fetch, link, and MeSH lookups
+// as a JSON HTTP API with API-key authentication and per-client rate limits.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+	"github.com/henrybloomingdale/pubmed-cli/internal/output"
+	"golang.org/x/time/rate"
+)
+
+// Request limits.
+const (
+	// MaxSearchLimit caps the PMIDs one search request returns.
+	MaxSearchLimit = 1000
+	// MaxFetchPMIDs caps the articles one fetch request retrieves.
+	MaxFetchPMIDs = 200
+	// DefaultRate and DefaultBurst are each client's request allowance.
+	DefaultRate  = 5
+	DefaultBurst = 10
+	// MaxClients caps the clients whose allowances are tracked; past it,
+	// the least recently seen is forgotten.
+	MaxClients = 10000
+)
+
+// PubMed is the E-utilities client the server calls. *eutils.Client satisfies it.
+type PubMed interface {
+	Search(ctx context.Context, query string, opts *eutils.SearchOptions) (*eutils.SearchResult, error)
+	Fetch(ctx context.Context, pmids []string) ([]eutils.Article, error)
+	CitedBy(ctx context.Context, pmid string) (*eutils.LinkResult, error)
+	References(ctx context.Context, pmid string) (*eutils.LinkResult, error)
+	Related(ctx context.Context, pmid string) (*eutils.LinkResult, error)
+}
+
+// MeSH looks up descriptors. *mesh.Client satisfies it.
+type MeSH interface {
+	Lookup(ctx context.Context, term string) (*mesh.MeSHRecord, error)
+}
+
+// Options configures a Server.
+type Options struct {
+	// APIKeys are the accepted client keys; with none, requests are not authenticated.
+	APIKeys []string
+	// Rate and Burst bound each client's requests per second (defaults
+	// DefaultRate and DefaultBurst).
+	Rate  float64
+	Burst int
+	// Log receives the details of upstream failures, which clients see
+	// only as "upstream request failed"; nil discards them.
+	Log io.Writer
+}
+
+// Server serves the JSON API.
+type Server struct {
+	pubmed PubMed
+	mesh   MeSH
+	keys   [][]byte
+	rate   rate.Limit
+	burst  int
+	log    io.Writer
+	now    func() time.Time
+
+	mu       sync.Mutex
+	limiters map[string]*clientLimiter
+}
+
+// clientLimiter is one client's allowance and when it was last used.
+type clientLimiter struct {
+	*rate.Limiter
+	seen time.Time
+}
+
+// New returns a server backed by the given clients.
+func New(p PubMed, m MeSH, opts Options) *Server {
+	s := &Server{
+		pubmed:   p,
+		mesh:     m,
+		rate:     rate.Limit(opts.Rate),
+		burst:    opts.Burst,
+		log:      opts.Log,
+		now:      time.Now,
+		limiters: make(map[string]*clientLimiter),
+	}
+	if s.log == nil {
+		s.log = io.Discard
+	}
+	if s.rate <= 0 {
+		s.rate = DefaultRate
+	}
+	if s.burst <= 0 {
+		s.burst = DefaultBurst
+	}
+	for _, k := range opts.APIKeys {
+		if k = strings.TrimSpace(k); k != "" {
+			s.keys = append(s.keys, []byte(k))
+		}
+	}
+	return s
+}
+
+// Authenticated reports whether the server requires an API key.
+func (s *Server) Authenticated() bool {
+	return len(s.keys) > 0
+}
+
+// Handler returns the HTTP handler for all routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /v1/search", s.guard(s.handleSearch))
+	mux.Handle("GET /v1/fetch", s.guard(s.handleFetch))
+	mux.Handle("GET /v1/cited-by", s.guard(s.linkHandler(s.pubmed.CitedBy)))
+	mux.Handle("GET /v1/references", s.guard(s.linkHandler(s.pubmed.References)))
+	mux.Handle("GET /v1/related", s.guard(s.linkHandler(s.pubmed.Related)))
+	mux.Handle("GET /v1/mesh", s.guard(s.handleMeSH))
+	return mux
+}
+
+// guard wraps h with API-key authentication and per-client rate limiting.
+func (s *Server) guard(h func(http.ResponseWriter, *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := clientIP(r)
+		if s.Authenticated() {
+			key := requestKey(r)
+			if !s.validKey(key) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="pubmed-cli"`)
+				writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+				return
+			}
+			client = "key:" + key
+		}
+		if !s.limiter(client).Allow() {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		if err := h(w, r); err != nil {
+			var bad badRequest
+			if errors.As(err, &bad) {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			// Upstream errors can carry request details, so they are
+			// logged rather than returned.
+			fmt.Fprintf(s.log, "%s %s: %v\n", r.Method, r.URL.Path, err)
+			writeError(w, http.StatusBadGateway, "upstream request failed")
+		}
+	})
+}
+
+// requestKey reads the client key from "Authorization: Bearer" or X-API-Key.
+func requestKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+func (s *Server) validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	ok := false
+	for _, k := range s.keys {
+		if subtle.ConstantTimeCompare(k, []byte(key)) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+func (s *Server) limiter(client string) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	l, ok := s.limiters[client]
+	if !ok {
+		if len(s.limiters) >= MaxClients {
+			s.evict(now)
+		}
+		l = &clientLimiter{Limiter: rate.NewLimiter(s.rate, s.burst)}
+		s.limiters[client] = l
+	}
+	l.seen = now
+	return l.Limiter
+}
+
+// evict forgets the clients idle long enough for their allowance to have
+// refilled, whose fresh limiters would behave the same, or failing that the
+// least recently seen client.
+func (s *Server) evict(now time.Time) {
+	refill := time.Duration(float64(s.burst) / float64(s.rate) * float64(time.Second))
+	var oldest string
+	for client, l := range s.limiters {
+		if now.Sub(l.seen) >= refill {
+			delete(s.limiters, client)
+			continue
+		}
+		if oldest == "" || l.seen.Before(s.limiters[oldest].seen) {
+			oldest = client
+		}
+	}
+	if len(s.limiters) >= MaxClients {
+		delete(s.limiters, oldest)
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// badRequest marks errors caused by the request rather than upstream NCBI.
+type badRequest struct{ msg string }
+
+func (e badRequest) Error() string { return e.msg }
+
+func badRequestf(format string, args ...any) error {
+	return badRequest{fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	term := strings.TrimSpace(q.Get("q"))
+	if term == "" {
+		return badRequestf("q is required")
+	}
+	opts := &eutils.SearchOptions{Limit: 20, Sort: q.Get("sort"), MinDate: q.Get("mindate"), MaxDate: q.Get("maxdate")}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > MaxSearchLimit {
+			return badRequestf("limit must be between 1 and %d", MaxSearchLimit)
+		}
+		opts.Limit = n
+	}
+	switch opts.Sort {
+	case "", "relevance", "date", "cited":
+	default:
+		return badRequestf("sort must be relevance, date, or cited")
+	}
+
+	result, err := s.pubmed.Search(r.Context(), term, opts)
+	if err != nil {
+		return err
+	}
+	var articles []eutils.Article
+	if fetch, _ := strconv.ParseBool(q.Get("fetch")); fetch && len(result.IDs) > 0 {
+		for start := 0; start < len(result.IDs); start += MaxFetchPMIDs {
+			end := min(start+MaxFetchPMIDs, len(result.IDs))
+			batch, err := s.pubmed.Fetch(r.Context(), result.IDs[start:end])
+			if err != nil {
+				return err
+			}
+			articles = append(articles, batch...)
+		}
+	}
+	writeJSON(w, http.StatusOK, searchResponse{SchemaVersion: output.SchemaVersion, SearchResult: result, Articles: articles})
+	return nil
+}
+
+// searchResponse is search --json output plus the fetched articles.
+type searchResponse struct {
+	SchemaVersion string `json:"schema_version"`
+	*eutils.SearchResult
+	Articles []eutils.Article `json:"articles,omitempty"`
+}
+
+func (s *Server) handleFetch(w http.ResponseWriter, r *http.Request) error {
+	pmids, err := pmidList(r.URL.Query().Get("pmid"))
+	if err != nil {
+		return err
+	}
+	if len(pmids) > MaxFetchPMIDs {
+		return badRequestf("at most %d PMIDs per request", MaxFetchPMIDs)
+	}
+	articles, err := s.pubmed.Fetch(r.Context(), pmids)
+	if err != nil {
+		return err
+	}
+	return render(w, func(buf *bytes.Buffer) error {
+		return output.FormatArticles(buf, articles, output.OutputConfig{JSON: true})
+	})
+}
+
+func (s *Server) linkHandler(link func(context.Context, string) (*eutils.LinkResult, error)) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		pmids, err := pmidList(r.URL.Query().Get("pmid"))
+		if err != nil {
+			return err
+		}
+		if len(pmids) != 1 {
+			return badRequestf("give exactly one pmid")
+		}
+		result, err := link(r.Context(), pmids[0])
+		if err != nil {
+			return err
+		}
+		return render(w, func(buf *bytes.Buffer) error {
+			return output.FormatLinks(buf, result, "", output.OutputConfig{JSON: true})
+		})
+	}
+}
+
+func (s *Server) handleMeSH(w http.ResponseWriter, r *http.Request) error {
+	term := strings.TrimSpace(r.URL.Query().Get("term"))
+	if term == "" {
+		return badRequestf("term is required")
+	}
+	record, err := s.mesh.Lookup(r.Context(), term)
+	if errors.Is(err, mesh.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return render(w, func(buf *bytes.Buffer) error {
+		return output.FormatMeSHRecord(buf, record, output.OutputConfig{JSON: true})
+	})
+}
+
+// pmidList parses a comma-separated PMID parameter.
+func pmidList(v string) ([]string, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, badRequestf("pmid is required")
+	}
+	var pmids []string
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.Trim(p, "0123456789") != "" {
+			return nil, badRequestf("invalid PMID %q: only digits are allowed", p)
+		}
+		pmids = append(pmids, p)
+	}
+	return pmids, nil
+}
+
+// render writes the JSON produced by format, which shares the CLI's
+// --json output (including schema_version).
+func render(w http.ResponseWriter, format func(*bytes.Buffer) error) error {
+	var buf bytes.Buffer
+	if err := format(&buf); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/mesh"
+)
+
+type fakePubMed struct {
+	searchOpts *eutils.SearchOptions
+	fetched    [][]string
+	err        error
+}
+
+func (f *fakePubMed) Search(ctx context.Context, q string, opts *eutils.SearchOptions) (*eutils.SearchResult, error) {
+	f.searchOpts = opts
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &eutils.SearchResult{Count: 2, IDs: []string{"1", "2"}}, nil
+}
+
+func (f *fakePubMed) Fetch(ctx context.Context, pmids []string) ([]eutils.Article, error) {
+	f.fetched = append(f.fetched, pmids)
+	articles := make([]eutils.Article, len(pmids))
+	for i, id := range pmids {
+		articles[i] = eutils.Article{PMID: id, Title: "Title " + id}
+	}
+	return articles, nil
+}
+
+func (f *fakePubMed) CitedBy(ctx context.Context, pmid string) (*eutils.LinkResult, error) {
+	return &eutils.LinkResult{SourceID: pmid, Links: []eutils.LinkItem{{ID: "9"}}}, nil
+}
+
+func (f *fakePubMed) References(ctx context.Context, pmid string) (*eutils.LinkResult, error) {
+	return &eutils.LinkResult{SourceID: pmid}, nil
+}
+
+func (f *fakePubMed) Related(ctx context.Context, pmid string) (*eutils.LinkResult, error) {
+	return &eutils.LinkResult{SourceID: pmid}, nil
+}
+
+type fakeMeSH struct{}
+
+func (fakeMeSH) Lookup(ctx context.Context, term string) (*mesh.MeSHRecord, error) {
+	if term == "nothing" {
+		return nil, mesh.ErrNotFound
+	}
+	return &mesh.MeSHRecord{UI: "D005600", Name: term}, nil
+}
+
+func get(t *testing.T, h http.Handler, target string, header ...string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var body map[string]any
+	if strings.HasPrefix(strings.TrimSpace(rec.Body.String()), "{") {
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid JSON: %v\n%s", target, err, rec.Body.String())
+		}
+	}
+	return rec, body
+}
+
+func TestSearchAndFetch(t *testing.T) {
+	pm := &fakePubMed{}
+	h := New(pm, fakeMeSH{}, Options{}).Handler()
+
+	rec, body := get(t, h, "/v1/search?q=fragile+x&limit=5&sort=date&mindate=2020&maxdate=2024&fetch=true")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("search status %d: %s", rec.Code, rec.Body.String())
+	}
+	if pm.searchOpts.Limit != 5 || pm.searchOpts.Sort != "date" || pm.searchOpts.MinDate != "2020" || pm.searchOpts.MaxDate != "2024" {
+		t.Errorf("unexpected search options: %+v", pm.searchOpts)
+	}
+	if body["schema_version"] == nil || body["count"] != float64(2) {
+		t.Errorf("unexpected search body: %v", body)
+	}
+	if articles, _ := body["articles"].([]any); len(articles) != 2 {
+		t.Errorf("expected fetched articles, got %v", body["articles"])
+	}
+
+	rec, _ = get(t, h, "/v1/fetch?pmid=1,2")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"Title 2"`) {
+		t.Errorf("fetch: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec, body = get(t, h, "/v1/cited-by?pmid=7")
+	if rec.Code != http.StatusOK || body["source_id"] != "7" {
+		t.Errorf("cited-by: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec, body = get(t, h, "/v1/mesh?term=Fragile+X+Syndrome")
+	if rec.Code != http.StatusOK || body["name"] != "Fragile X Syndrome" {
+		t.Errorf("mesh: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	pm := &fakePubMed{}
+	h := New(pm, fakeMeSH{}, Options{Rate: 1000, Burst: 1000}).Handler()
+
+	for _, target := range []string{
+		"/v1/search",
+		"/v1/search?q=x&limit=0",
+		"/v1/search?q=x&sort=newest",
+		"/v1/fetch?pmid=12a",
+		"/v1/related?pmid=1,2",
+		"/v1/mesh",
+	} {
+		rec, body := get(t, h, target)
+		if rec.Code != http.StatusBadRequest || body["error"] == nil {
+			t.Errorf("%s: expected 400 with an error, got %d %s", target, rec.Code, rec.Body.String())
+		}
+	}
+
+	if rec, _ := get(t, h, "/v1/mesh?term=nothing"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown MeSH term: expected 404, got %d", rec.Code)
+	}
+
+	pm.err = errors.New("NCBI unavailable")
+	if rec, _ := get(t, h, "/v1/search?q=x"); rec.Code != http.StatusBadGateway {
+		t.Errorf("upstream failure: expected 502, got %d", rec.Code)
+	}
+}
+
+func TestUpstreamErrorLogged(t *testing.T) {
+	var log bytes.Buffer
+	pm := &fakePubMed{err: errors.New(`executing request: Get "https://eutils.ncbi.nlm.nih.gov/esearch.fcgi?api_key=secret": refused`)}
+	h := New(pm, fakeMeSH{}, Options{Log: &log}).Handler()
+
+	rec, body := get(t, h, "/v1/search?q=x")
+	if rec.Code != http.StatusBadGateway || body["error"] != "upstream request failed" {
+		t.Errorf("expected 502 with a generic error, got %d %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "secret") {
+		t.Errorf("response leaks the upstream error: %s", rec.Body.String())
+	}
+	if !strings.Contains(log.String(), "GET /v1/search: executing request") {
+		t.Errorf("upstream error not logged: %q", log.String())
+	}
+}
+
+func TestLimiterEviction(t *testing.T) {
+	s := New(&fakePubMed{}, fakeMeSH{}, Options{Rate: 1, Burst: 1})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	for i := 0; i < MaxClients; i++ {
+		s.limiter(strconv.Itoa(i))
+	}
+
+	// Before the allowances refill, only the least recently seen client
+	// makes room.
+	now = now.Add(500 * time.Millisecond)
+	s.limiter("0")
+	s.limiter("new")
+	if len(s.limiters) != MaxClients {
+		t.Errorf("tracked %d clients, want the cap of %d", len(s.limiters), MaxClients)
+	}
+	if _, ok := s.limiters["0"]; !ok {
+		t.Error("evicted a recently seen client")
+	}
+
+	// Once they have refilled, every idle client is forgotten.
+	now = now.Add(2 * time.Second)
+	s.limiter("0")
+	s.limiter("newer")
+	if len(s.limiters) != 2 {
+		t.Errorf("tracked %d clients after they went idle, want 2", len(s.limiters))
+	}
+}
+
+func TestAuth(t *testing.T) {
+	h := New(&fakePubMed{}, fakeMeSH{}, Options{APIKeys: []string{"secret", " "}}).Handler()
+
+	if rec, _ := get(t, h, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("healthz should not need a key, got %d", rec.Code)
+	}
+	if rec, _ := get(t, h, "/v1/fetch?pmid=1"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a key, got %d", rec.Code)
+	}
+	if rec, _ := get(t, h, "/v1/fetch?pmid=1", "Authorization", "Bearer wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a wrong key, got %d", rec.Code)
+	}
+	if rec, _ := get(t, h, "/v1/fetch?pmid=1", "Authorization", "Bearer secret"); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with a bearer key, got %d", rec.Code)
+	}
+	if rec, _ := get(t, h, "/v1/fetch?pmid=1", "X-API-Key", "secret"); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with X-API-Key, got %d", rec.Code)
+	}
+}
+
+func TestRateLimitPerClient(t *testing.T) {
+	h := New(&fakePubMed{}, fakeMeSH{}, Options{APIKeys: []string{"a", "b"}, Rate: 0.001, Burst: 2}).Handler()
+
+	for i := 0; i < 2; i++ {
+		if rec, _ := get(t, h, "/v1/fetch?pmid=1", "X-API-Key", "a"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d within burst: got %d", i+1, rec.Code)
+		}
+	}
+	rec, _ := get(t, h, "/v1/fetch?pmid=1", "X-API-Key", "a")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 429 with Retry-After after the burst, got %d", rec.Code)
+	}
+	if rec, _ := get(t, h, "/v1/fetch?pmid=1", "X-API-Key", "b"); rec.Code != http.StatusOK {
+		t.Errorf("another client should have its own allowance, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,205 @@
+package stance
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// marker stands in for a citation of the paper while a paragraph is split
+// into sentences.
+const marker = '\x00'
+
+// skipped are body elements whose text is not running prose.
+var skipped = map[string]bool{
+	"fig":                    true,
+	"table-wrap":             true,
+	"disp-formula":           true,
+	"supplementary-material": true,
+}
+
+// Contexts returns the sentences of a PMC (JATS) article's body that cite
+// the reference with PMID pmid or DOI doi. It returns an empty, non-nil
+// slice when the article does not cite the paper in its body.
+func Contexts(data []byte, pmid, doi string) ([]string, error) {
+	refs, err := refIDs(data, pmid, doi)
+	if err != nil {
+		return nil, err
+	}
+	contexts := []string{}
+	if len(refs) == 0 {
+		return contexts, nil
+	}
+
+	dec := newDecoder(data)
+	inBody := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing PMC XML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "sub-article":
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case t.Name.Local == "body":
+				inBody = true
+			case inBody && skipped[t.Name.Local]:
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case inBody && t.Name.Local == "p":
+				text, err := paragraph(dec, refs)
+				if err != nil {
+					return nil, err
+				}
+				for _, s := range sentences(text) {
+					if strings.ContainsRune(s, marker) {
+						contexts = append(contexts, strings.Join(strings.Fields(strings.ReplaceAll(s, string(marker), "")), " "))
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "body" {
+				inBody = false
+			}
+		}
+	}
+	return contexts, nil
+}
+
+func newDecoder(data []byte) *xml.Decoder {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+	return dec
+}
+
+// refIDs returns the ids of the reference-list entries whose pub-id is
+// pmid or doi.
+func refIDs(data []byte, pmid, doi string) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	dec := newDecoder(data)
+	var ref string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing PMC XML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "sub-article":
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case t.Name.Local == "ref":
+				ref = attr(t, "id")
+			case t.Name.Local == "pub-id" && ref != "":
+				var id string
+				if err := dec.DecodeElement(&id, &t); err != nil {
+					return nil, fmt.Errorf("parsing PMC XML: %w", err)
+				}
+				id = strings.TrimSpace(id)
+				switch attr(t, "pub-id-type") {
+				case "pmid":
+					if pmid != "" && id == pmid {
+						ids[ref] = true
+					}
+				case "doi":
+					if doi != "" && strings.EqualFold(id, doi) {
+						ids[ref] = true
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "ref" {
+				ref = ""
+			}
+		}
+	}
+}
+
+// paragraph returns the text of the paragraph just opened, consuming it,
+// with a marker before each citation of refs.
+func paragraph(dec *xml.Decoder, refs map[string]bool) (string, error) {
+	var b strings.Builder
+	level := 1
+	for level > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("parsing PMC XML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skipped[t.Name.Local] {
+				if err := dec.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			if t.Name.Local == "xref" && attr(t, "ref-type") == "bibr" {
+				for _, rid := range strings.Fields(attr(t, "rid")) {
+					if refs[rid] {
+						b.WriteRune(marker)
+						break
+					}
+				}
+			}
+			level++
+		case xml.EndElement:
+			level--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	return b.String(), nil
+}
+
+// sentences splits text after a full stop, question mark, or exclamation
+// mark that is followed by whitespace and a capital letter or digit.
+func sentences(text string) []string {
+	var out []string
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(".?!", runes[i]) {
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && unicode.IsSpace(runes[j]) {
+			j++
+		}
+		if j == i+1 || j == len(runes) || !(unicode.IsUpper(runes[j]) || unicode.IsDigit(runes[j])) {
+			continue
+		}
+		out = append(out, string(runes[start:i+1]))
+		start = j
+	}
+	if rest := strings.TrimSpace(string(runes[start:])); rest != "" {
+		out = append(out, rest)
+	}
+	return out
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
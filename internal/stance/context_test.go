@@ -0,0 +1,61 @@
+package stance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func loadTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", name))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	return data
+}
+
+func TestContexts(t *testing.T) {
+	data := loadTestdata(t, "pmc_citing.xml")
+	want := []string{
+		"A pilot trial of metformin in children reported improved behavior [2, 3].",
+		"Our results are consistent with the pilot trial [2].",
+	}
+	for _, tc := range []struct{ pmid, doi string }{{"38000001", ""}, {"", "10.1000/PILOT.1"}} {
+		got, err := Contexts(data, tc.pmid, tc.doi)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%+v: expected %d contexts, got %q", tc, len(want), got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%+v: context %d: got %q, want %q", tc, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestContexts_NotCited(t *testing.T) {
+	got, err := Contexts(loadTestdata(t, "pmc_citing.xml"), "12345", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected an empty, non-nil slice, got %#v", got)
+	}
+}
+
+func TestSentences(t *testing.T) {
+	got := sentences("One [1]. Two, e.g. three? 4 is a number.")
+	want := []string{"One [1].", "Two, e.g. three?", "4 is a number."}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sentence %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
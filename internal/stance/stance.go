@@ -0,0 +1,125 @@
+// Package stance finds where a citing article cites a paper in its PMC
+// full text and classifies how it cites it: supporting, contrasting, or
+// merely mentioning. Classification uses cue phrases in the citing
+// sentences, so it is a triage aid rather than a reading of the paper.
+package stance
+
+import (
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Stances, from a citing paper's sentences about the cited one. Unknown
+// means the citing paper has no PMC full text to read.
+const (
+	Supporting  = "supporting"
+	Contrasting = "contrasting"
+	Mentioning  = "mentioning"
+	Unknown     = "unknown"
+)
+
+// Stances lists the stances in report order.
+var Stances = []string{Supporting, Contrasting, Mentioning, Unknown}
+
+// contrastingCues are checked before supportingCues, so that "failed to
+// replicate" is not read as "replicate".
+var contrastingCues = []string{
+	"in contrast", "contrary to", "unlike", "inconsistent with", "not consistent with",
+	"at odds with", "in conflict with", "conflicts with", "contradict", "disagree",
+	"failed to replicate", "failed to confirm", "did not replicate", "did not confirm",
+	"could not replicate", "could not confirm", "not replicated", "not confirmed",
+	"differ from", "differs from", "differed from", "challenge", "refute", "dispute",
+}
+
+var supportingCues = []string{
+	"consistent with", "in line with", "in agreement with", "agrees with", "in accordance with",
+	"confirm", "corroborat", "replicat", "support", "similar to", "similarly",
+	"as previously reported", "as previously shown", "as reported by", "extend",
+}
+
+// Citation is one citing article and the sentences in which it cites the
+// paper.
+type Citation struct {
+	PMID     string   `json:"pmid"`
+	PMCID    string   `json:"pmcid,omitempty"`
+	Title    string   `json:"title"`
+	Journal  string   `json:"journal,omitempty"`
+	Year     string   `json:"year,omitempty"`
+	Stance   string   `json:"stance"`
+	Contexts []string `json:"contexts,omitempty"`
+}
+
+// NewCitation describes citing article a from its citing sentences;
+// contexts is nil when a's full text could not be read.
+func NewCitation(a eutils.Article, contexts []string) Citation {
+	c := Citation{PMID: a.PMID, PMCID: a.PMCID, Title: a.Title, Journal: a.JournalAbbrev, Year: a.Year, Contexts: contexts}
+	if c.Journal == "" {
+		c.Journal = a.Journal
+	}
+	c.Stance = Unknown
+	if contexts != nil {
+		c.Stance = Classify(contexts)
+	}
+	return c
+}
+
+// Classify returns the stance of a set of citing sentences: contrasting
+// when at least as many sentences contrast as support, supporting when
+// any support, and mentioning otherwise, including when the full text
+// never cites the paper in its body.
+func Classify(contexts []string) string {
+	var supporting, contrasting int
+	for _, s := range contexts {
+		switch sentenceStance(s) {
+		case Supporting:
+			supporting++
+		case Contrasting:
+			contrasting++
+		}
+	}
+	switch {
+	case contrasting > 0 && contrasting >= supporting:
+		return Contrasting
+	case supporting > 0:
+		return Supporting
+	}
+	return Mentioning
+}
+
+func sentenceStance(s string) string {
+	s = strings.ToLower(s)
+	for _, cue := range contrastingCues {
+		if strings.Contains(s, cue) {
+			return Contrasting
+		}
+	}
+	for _, cue := range supportingCues {
+		if strings.Contains(s, cue) {
+			return Supporting
+		}
+	}
+	return Mentioning
+}
+
+// Report is the stance of each citing article of a paper.
+type Report struct {
+	PMID string `json:"pmid"`
+	// Total is the number of citing articles in PubMed; Citations covers
+	// at most --limit of them.
+	Total     int            `json:"total"`
+	Citations []Citation     `json:"citations"`
+	Counts    map[string]int `json:"counts"`
+}
+
+// NewReport totals the stances of citations of pmid.
+func NewReport(pmid string, total int, citations []Citation) Report {
+	r := Report{PMID: pmid, Total: total, Citations: citations, Counts: make(map[string]int)}
+	if r.Citations == nil {
+		r.Citations = []Citation{}
+	}
+	for _, c := range citations {
+		r.Counts[c.Stance]++
+	}
+	return r
+}
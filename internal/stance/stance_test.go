@@ -0,0 +1,42 @@
+package stance
+
+import (
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		contexts []string
+		want     string
+	}{
+		{[]string{"Our findings are consistent with Smith et al. [2]."}, Supporting},
+		{[]string{"Unlike the pilot trial [2], we saw no benefit."}, Contrasting},
+		{[]string{"We failed to replicate the effect [2]."}, Contrasting},
+		{[]string{"This confirms [2].", "In contrast to [2], weight did not change."}, Contrasting},
+		{[]string{"This confirms [2].", "This was also replicated [2].", "However, unlike [2], ..."}, Supporting},
+		{[]string{"Metformin has been tried in FXS [2]."}, Mentioning},
+		{[]string{}, Mentioning},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.contexts); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.contexts, got, tt.want)
+		}
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	citations := []Citation{
+		NewCitation(eutils.Article{PMID: "1", Journal: "J Things"}, []string{"This confirms [2]."}),
+		NewCitation(eutils.Article{PMID: "2", JournalAbbrev: "J T"}, nil),
+		NewCitation(eutils.Article{PMID: "3"}, []string{}),
+	}
+	if citations[0].Journal != "J Things" || citations[1].Journal != "J T" {
+		t.Errorf("unexpected journals: %+v", citations)
+	}
+	r := NewReport("38000001", 10, citations)
+	if r.Counts[Supporting] != 1 || r.Counts[Unknown] != 1 || r.Counts[Mentioning] != 1 || r.Total != 10 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+}
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+)
+
+// Alerts reads the saved alerts.
+func (db *DB) Alerts() (*alert.Book, error) {
+	b, err := readAlerts(db.db)
+	if err != nil {
+		return nil, fmt.Errorf("reading alerts: %w", err)
+	}
+	return b, nil
+}
+
+// SaveAlerts writes b, changing only the alerts that differ from the
+// stored ones.
+func (db *DB) SaveAlerts(b *alert.Book) error {
+	if err := db.update(func(tx *sql.Tx) error { return writeAlerts(tx, b) }); err != nil {
+		return fmt.Errorf("saving alerts: %w", err)
+	}
+	return nil
+}
+
+func readAlerts(q querier) (*alert.Book, error) {
+	b := &alert.Book{}
+	byName := make(map[string]*alert.Alert)
+	err := eachRow(q, "SELECT name, query, created, last_run FROM alerts ORDER BY name", func(rows *sql.Rows) error {
+		var name, query, created, lastRun string
+		if err := rows.Scan(&name, &query, &created, &lastRun); err != nil {
+			return err
+		}
+		a := &alert.Alert{Name: name, Query: query}
+		var err error
+		if a.Created, err = parseTime(created); err != nil {
+			return err
+		}
+		if a.LastRun, err = parseTime(lastRun); err != nil {
+			return err
+		}
+		byName[name] = a
+		b.Alerts = append(b.Alerts, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = eachRow(q, "SELECT alert, pmid FROM alert_seen ORDER BY alert, position", func(rows *sql.Rows) error {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return err
+		}
+		if a := byName[name]; a != nil {
+			a.Seen = append(a.Seen, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = eachRow(q, "SELECT alert, data FROM alert_snapshots ORDER BY alert, position", func(rows *sql.Rows) error {
+		var name, data string
+		if err := rows.Scan(&name, &data); err != nil {
+			return err
+		}
+		var s alert.Snapshot
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			return fmt.Errorf("alert %q snapshot: %w", name, err)
+		}
+		if a := byName[name]; a != nil {
+			a.Snapshots = append(a.Snapshots, s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// writeAlerts brings the stored alerts in line with b. Seen PMIDs only
+// grow as alerts run, so new ones are appended rather than the list
+// rewritten.
+func writeAlerts(tx *sql.Tx, b *alert.Book) error {
+	stored, err := readAlerts(tx)
+	if err != nil {
+		return err
+	}
+	old := make(map[string]*alert.Alert, len(stored.Alerts))
+	for _, a := range stored.Alerts {
+		old[a.Name] = a
+	}
+	for _, a := range b.Alerts {
+		prev := old[a.Name]
+		delete(old, a.Name)
+		if prev == nil {
+			prev = &alert.Alert{}
+		}
+		if _, err := tx.Exec(`INSERT INTO alerts (name, query, created, last_run) VALUES (?, ?, ?, ?)
+			ON CONFLICT (name) DO UPDATE SET query = excluded.query, created = excluded.created, last_run = excluded.last_run`,
+			a.Name, a.Query, formatTime(a.Created), formatTime(a.LastRun)); err != nil {
+			return err
+		}
+		from := len(prev.Seen)
+		if from > len(a.Seen) || !slices.Equal(prev.Seen, a.Seen[:from]) {
+			if _, err := tx.Exec("DELETE FROM alert_seen WHERE alert = ?", a.Name); err != nil {
+				return err
+			}
+			from = 0
+		}
+		for i := from; i < len(a.Seen); i++ {
+			if _, err := tx.Exec("INSERT INTO alert_seen (alert, position, pmid) VALUES (?, ?, ?)", a.Name, i, a.Seen[i]); err != nil {
+				return err
+			}
+		}
+		if err := writeSnapshots(tx, a.Name, prev.Snapshots, a.Snapshots); err != nil {
+			return err
+		}
+	}
+	for name := range old {
+		if _, err := tx.Exec("DELETE FROM alerts WHERE name = ?", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSnapshots(tx *sql.Tx, name string, prev, snapshots []alert.Snapshot) error {
+	if len(prev) == len(snapshots) {
+		same := true
+		for i := range prev {
+			if !prev[i].Time.Equal(snapshots[i].Time) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return nil
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM alert_snapshots WHERE alert = ?", name); err != nil {
+		return err
+	}
+	for i, s := range snapshots {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO alert_snapshots (alert, position, data) VALUES (?, ?, ?)", name, i, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
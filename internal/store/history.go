@@ -0,0 +1,83 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+)
+
+// History reads the search history, oldest first.
+func (db *DB) History() ([]history.Entry, error) {
+	entries, err := readHistory(db.db)
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	return entries, nil
+}
+
+// AppendHistory numbers e after the last entry, stores it, and returns it.
+func (db *DB) AppendHistory(e history.Entry) (history.Entry, error) {
+	err := db.update(func(tx *sql.Tx) error {
+		if err := tx.QueryRow("SELECT COALESCE(MAX(id), 0) + 1 FROM history").Scan(&e.ID); err != nil {
+			return err
+		}
+		return insertHistory(tx, e)
+	})
+	if err != nil {
+		return e, fmt.Errorf("recording history: %w", err)
+	}
+	return e, nil
+}
+
+func readHistory(q querier) ([]history.Entry, error) {
+	var entries []history.Entry
+	err := eachRow(q, "SELECT id, time, query, translation, options, count, ids FROM history ORDER BY id", func(rows *sql.Rows) error {
+		var e history.Entry
+		var t, opts, ids string
+		if err := rows.Scan(&e.ID, &t, &e.Query, &e.Translation, &opts, &e.Count, &ids); err != nil {
+			return err
+		}
+		var err error
+		if e.Time, err = parseTime(t); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(opts), &e.Options); err != nil {
+			return fmt.Errorf("history #%d options: %w", e.ID, err)
+		}
+		e.IDs = splitIDs(ids)
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+func insertHistory(tx *sql.Tx, e history.Entry) error {
+	opts, err := json.Marshal(e.Options)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO history (id, time, query, translation, options, count, ids) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		e.ID, formatTime(e.Time), e.Query, e.Translation, string(opts), e.Count, joinIDs(e.IDs))
+	return err
+}
+
+// replaceHistory swaps the stored history for entries.
+func replaceHistory(tx *sql.Tx, entries []history.Entry) error {
+	if _, err := tx.Exec("DELETE FROM history"); err != nil {
+		return err
+	}
+	last := 0
+	for _, e := range entries {
+		// Keep entries in order even if an archive numbered them oddly.
+		if e.ID <= last {
+			e.ID = last + 1
+		}
+		last = e.ID
+		if err := insertHistory(tx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+)
+
+// Legacy file names: the JSON stores earlier releases kept in the config
+// directory.
+const (
+	LegacyLibrary = "library.json"
+	LegacyAlerts  = "alerts.json"
+	LegacyHistory = "history.jsonl"
+)
+
+// legacyMigration is the index of importLegacy in migrations.
+const legacyMigration = 1
+
+// importLegacy copies the JSON stores in dir into a new database.
+func importLegacy(tx *sql.Tx, dir string) error {
+	l, err := library.Load(filepath.Join(dir, LegacyLibrary))
+	if err != nil {
+		return err
+	}
+	if err := writeLibrary(tx, l); err != nil {
+		return err
+	}
+	b, err := alert.Load(filepath.Join(dir, LegacyAlerts))
+	if err != nil {
+		return err
+	}
+	if err := writeAlerts(tx, b); err != nil {
+		return err
+	}
+	entries, err := history.Load(filepath.Join(dir, LegacyHistory))
+	if err != nil {
+		return err
+	}
+	return replaceHistory(tx, entries)
+}
+
+// retireLegacy renames imported JSON stores to NAME.imported, so it is
+// clear they are no longer read. A file that cannot be renamed is left;
+// the database never reads it again either way.
+func retireLegacy(dir string) {
+	for _, name := range []string{LegacyLibrary, LegacyAlerts, LegacyHistory} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		os.Rename(path, path+".imported")
+	}
+}
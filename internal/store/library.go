@@ -0,0 +1,309 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+)
+
+// Library reads the library: its collections, stored articles, and notes
+// and tags.
+func (db *DB) Library() (*library.Library, error) {
+	l := library.New()
+	if err := readLibrary(db.db, l); err != nil {
+		return nil, fmt.Errorf("reading library: %w", err)
+	}
+	return l, nil
+}
+
+// SaveLibrary writes l, changing only the rows that differ from the
+// stored library.
+func (db *DB) SaveLibrary(l *library.Library) error {
+	if err := db.update(func(tx *sql.Tx) error { return writeLibrary(tx, l) }); err != nil {
+		return fmt.Errorf("saving library: %w", err)
+	}
+	return nil
+}
+
+// querier is what reading needs from a *sql.DB or *sql.Tx.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func readLibrary(q querier, l *library.Library) error {
+	byName := make(map[string]*library.Collection)
+	err := eachRow(q, "SELECT name, created, updated FROM collections ORDER BY name", func(rows *sql.Rows) error {
+		var name, created, updated string
+		if err := rows.Scan(&name, &created, &updated); err != nil {
+			return err
+		}
+		c := &library.Collection{Name: name, PMIDs: []string{}}
+		var err error
+		if c.Created, err = parseTime(created); err != nil {
+			return err
+		}
+		if c.Updated, err = parseTime(updated); err != nil {
+			return err
+		}
+		byName[name] = c
+		l.Collections = append(l.Collections, c)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	items, err := readItems(q)
+	if err != nil {
+		return err
+	}
+	for name, ids := range items {
+		if c := byName[name]; c != nil {
+			c.PMIDs = ids
+		}
+	}
+
+	articles, err := readArticles(q)
+	if err != nil {
+		return err
+	}
+	for id, data := range articles {
+		var a eutils.Article
+		if err := json.Unmarshal([]byte(data), &a); err != nil {
+			return fmt.Errorf("article %s: %w", id, err)
+		}
+		l.Articles[id] = a
+	}
+
+	annotations, err := readAnnotations(q)
+	if err != nil {
+		return err
+	}
+	if len(annotations) > 0 {
+		l.Annotations = annotations
+	}
+	return nil
+}
+
+// readItems returns each collection's PMIDs in the order they were added.
+func readItems(q querier) (map[string][]string, error) {
+	items := make(map[string][]string)
+	err := eachRow(q, "SELECT collection, pmid FROM collection_items ORDER BY collection, position", func(rows *sql.Rows) error {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return err
+		}
+		items[name] = append(items[name], id)
+		return nil
+	})
+	return items, err
+}
+
+// readArticles returns each stored article's JSON by PMID.
+func readArticles(q querier) (map[string]string, error) {
+	articles := make(map[string]string)
+	err := eachRow(q, "SELECT pmid, data FROM articles", func(rows *sql.Rows) error {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return err
+		}
+		articles[id] = data
+		return nil
+	})
+	return articles, err
+}
+
+func readAnnotations(q querier) (map[string]*library.Annotation, error) {
+	out := make(map[string]*library.Annotation)
+	get := func(id string) *library.Annotation {
+		if out[id] == nil {
+			out[id] = &library.Annotation{}
+		}
+		return out[id]
+	}
+	err := eachRow(q, "SELECT pmid, text, added FROM notes ORDER BY pmid, position", func(rows *sql.Rows) error {
+		var id, text, added string
+		if err := rows.Scan(&id, &text, &added); err != nil {
+			return err
+		}
+		t, err := parseTime(added)
+		if err != nil {
+			return err
+		}
+		a := get(id)
+		a.Notes = append(a.Notes, library.Note{Text: text, Added: t})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = eachRow(q, "SELECT pmid, tag FROM tags ORDER BY pmid, tag", func(rows *sql.Rows) error {
+		var id, tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return err
+		}
+		a := get(id)
+		a.Tags = append(a.Tags, tag)
+		return nil
+	})
+	return out, err
+}
+
+// writeLibrary brings the stored library in line with l.
+func writeLibrary(tx *sql.Tx, l *library.Library) error {
+	if err := writeCollections(tx, l.Collections); err != nil {
+		return err
+	}
+	if err := writeArticles(tx, l.Articles); err != nil {
+		return err
+	}
+	return writeAnnotations(tx, l.Annotations)
+}
+
+func writeCollections(tx *sql.Tx, collections []*library.Collection) error {
+	type stored struct{ created, updated string }
+	old := make(map[string]stored)
+	err := eachRow(tx, "SELECT name, created, updated FROM collections", func(rows *sql.Rows) error {
+		var name string
+		var s stored
+		if err := rows.Scan(&name, &s.created, &s.updated); err != nil {
+			return err
+		}
+		old[name] = s
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	items, err := readItems(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range collections {
+		s, found := old[c.Name]
+		delete(old, c.Name)
+		created, updated := formatTime(c.Created), formatTime(c.Updated)
+		if found && s.created == created && s.updated == updated && slices.Equal(items[c.Name], c.PMIDs) {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO collections (name, created, updated) VALUES (?, ?, ?)
+			ON CONFLICT (name) DO UPDATE SET created = excluded.created, updated = excluded.updated`,
+			c.Name, created, updated); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM collection_items WHERE collection = ?", c.Name); err != nil {
+			return err
+		}
+		for i, id := range c.PMIDs {
+			if _, err := tx.Exec("INSERT OR IGNORE INTO collection_items (collection, position, pmid) VALUES (?, ?, ?)", c.Name, i, id); err != nil {
+				return err
+			}
+		}
+	}
+	for name := range old {
+		if _, err := tx.Exec("DELETE FROM collections WHERE name = ?", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeArticles(tx *sql.Tx, articles map[string]eutils.Article) error {
+	old, err := readArticles(tx)
+	if err != nil {
+		return err
+	}
+	for id, a := range articles {
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		prev, found := old[id]
+		delete(old, id)
+		if found && prev == string(data) {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO articles (pmid, data) VALUES (?, ?)
+			ON CONFLICT (pmid) DO UPDATE SET data = excluded.data`, id, string(data)); err != nil {
+			return err
+		}
+	}
+	for id := range old {
+		if _, err := tx.Exec("DELETE FROM articles WHERE pmid = ?", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeAnnotations(tx *sql.Tx, annotations map[string]*library.Annotation) error {
+	old, err := readAnnotations(tx)
+	if err != nil {
+		return err
+	}
+	for id, a := range annotations {
+		prev := old[id]
+		delete(old, id)
+		if prev != nil && sameAnnotation(prev, a) {
+			continue
+		}
+		if err := deleteAnnotation(tx, id); err != nil {
+			return err
+		}
+		for i, n := range a.Notes {
+			if _, err := tx.Exec("INSERT INTO notes (pmid, position, text, added) VALUES (?, ?, ?, ?)", id, i, n.Text, formatTime(n.Added)); err != nil {
+				return err
+			}
+		}
+		for _, tag := range a.Tags {
+			if _, err := tx.Exec("INSERT OR IGNORE INTO tags (pmid, tag) VALUES (?, ?)", id, tag); err != nil {
+				return err
+			}
+		}
+	}
+	for id := range old {
+		if err := deleteAnnotation(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteAnnotation(tx *sql.Tx, id string) error {
+	if _, err := tx.Exec("DELETE FROM notes WHERE pmid = ?", id); err != nil {
+		return err
+	}
+	_, err := tx.Exec("DELETE FROM tags WHERE pmid = ?", id)
+	return err
+}
+
+func sameAnnotation(a, b *library.Annotation) bool {
+	if !slices.Equal(a.Tags, b.Tags) || len(a.Notes) != len(b.Notes) {
+		return false
+	}
+	for i := range a.Notes {
+		if a.Notes[i].Text != b.Notes[i].Text || !a.Notes[i].Added.Equal(b.Notes[i].Added) {
+			return false
+		}
+	}
+	return true
+}
+
+// eachRow runs query and calls fn for each row.
+func eachRow(q querier, query string, fn func(*sql.Rows) error, args ...any) error {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
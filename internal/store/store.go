@@ -0,0 +1,269 @@
+// Package store keeps pubmed-cli's user data (library collections with
+// their articles, notes, and tags; saved alerts; and the search history)
+// in one SQLite database, so commands read and update single rows instead
+// of rewriting a JSON file that grows with every saved article.
+//
+// The schema is versioned by migrations applied when the database is
+// opened. The first run imports the JSON files earlier releases wrote.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+
+	_ "modernc.org/sqlite" // pure-Go driver, registered as "sqlite"
+)
+
+// FileName is the database's name in the config directory.
+const FileName = "pubmed.db"
+
+// DB is an open user database.
+type DB struct {
+	db   *sql.DB
+	path string
+}
+
+// DefaultPath returns the database location, honoring PUBMED_CLI_CONFIG_DIR
+// and otherwise using the platform config directory (e.g.
+// ~/.config/pubmed-cli/pubmed.db).
+func DefaultPath() (string, error) {
+	if d := os.Getenv(alert.EnvConfigDir); d != "" {
+		return filepath.Join(d, FileName), nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(base, "pubmed-cli", FileName), nil
+}
+
+// Open opens the database at path, creating it if needed, and brings its
+// schema up to date.
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating database directory: %w", err)
+	}
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)"
+	sqldb, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	// One connection serializes this process's writes; other processes
+	// wait on the busy timeout.
+	sqldb.SetMaxOpenConns(1)
+	db := &DB{db: sqldb, path: path}
+	if err := db.migrate(); err != nil {
+		sqldb.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Path returns the database file.
+func (db *DB) Path() string { return db.path }
+
+// Close closes the database.
+func (db *DB) Close() error { return db.db.Close() }
+
+// Version returns the schema version: the number of migrations applied.
+func (db *DB) Version() (int, error) {
+	var v int
+	err := db.db.QueryRow("PRAGMA user_version").Scan(&v)
+	return v, err
+}
+
+// update runs fn in a transaction, committing it if fn succeeds.
+func (db *DB) update(fn func(tx *sql.Tx) error) error {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// migration is one step of the schema's history. dir is the database's
+// directory, where legacy files are found.
+type migration func(tx *sql.Tx, dir string) error
+
+// migrations are applied in order, each in its own transaction, and the
+// count applied is kept in PRAGMA user_version. Append new steps; never
+// edit or reorder released ones.
+var migrations = []migration{
+	execMigration(schemaV1),
+	importLegacy,
+}
+
+const schemaV1 = `
+CREATE TABLE collections (
+	name    TEXT PRIMARY KEY,
+	created TEXT NOT NULL,
+	updated TEXT NOT NULL
+);
+CREATE TABLE collection_items (
+	collection TEXT NOT NULL REFERENCES collections(name) ON DELETE CASCADE,
+	position   INTEGER NOT NULL,
+	pmid       TEXT NOT NULL,
+	PRIMARY KEY (collection, pmid)
+);
+CREATE TABLE articles (
+	pmid TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+CREATE TABLE notes (
+	pmid     TEXT NOT NULL,
+	position INTEGER NOT NULL,
+	text     TEXT NOT NULL,
+	added    TEXT NOT NULL,
+	PRIMARY KEY (pmid, position)
+);
+CREATE TABLE tags (
+	pmid TEXT NOT NULL,
+	tag  TEXT NOT NULL,
+	PRIMARY KEY (pmid, tag)
+);
+CREATE INDEX tags_by_tag ON tags (tag);
+CREATE TABLE alerts (
+	name     TEXT PRIMARY KEY,
+	query    TEXT NOT NULL,
+	created  TEXT NOT NULL,
+	last_run TEXT NOT NULL
+);
+CREATE TABLE alert_seen (
+	alert    TEXT NOT NULL REFERENCES alerts(name) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	pmid     TEXT NOT NULL,
+	PRIMARY KEY (alert, position)
+);
+CREATE TABLE alert_snapshots (
+	alert    TEXT NOT NULL REFERENCES alerts(name) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	data     TEXT NOT NULL,
+	PRIMARY KEY (alert, position)
+);
+CREATE TABLE history (
+	id          INTEGER PRIMARY KEY,
+	time        TEXT NOT NULL,
+	query       TEXT NOT NULL,
+	translation TEXT NOT NULL,
+	options     TEXT NOT NULL,
+	count       INTEGER NOT NULL,
+	ids         TEXT NOT NULL
+);
+`
+
+func execMigration(stmts string) migration {
+	return func(tx *sql.Tx, dir string) error {
+		_, err := tx.Exec(stmts)
+		return err
+	}
+}
+
+// migrate applies the migrations the database has not had.
+func (db *DB) migrate() error {
+	v, err := db.Version()
+	if err != nil {
+		return err
+	}
+	if v > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this pubmed-cli supports (%d); upgrade pubmed-cli", v, len(migrations))
+	}
+	dir := filepath.Dir(db.path)
+	imported := false
+	for i := v; i < len(migrations); i++ {
+		err := db.update(func(tx *sql.Tx) error {
+			if err := migrations[i](tx, dir); err != nil {
+				return err
+			}
+			// PRAGMA takes no parameters.
+			_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migrating to schema version %d: %w", i+1, err)
+		}
+		imported = imported || i == legacyMigration
+	}
+	if imported {
+		retireLegacy(dir)
+	}
+	return nil
+}
+
+// timeLayout stores times as sortable text.
+const timeLayout = time.RFC3339Nano
+
+func formatTime(t time.Time) string { return t.UTC().Format(timeLayout) }
+
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q in database", s)
+	}
+	return t, nil
+}
+
+// joinIDs and splitIDs store an ordered ID list in one column.
+func joinIDs(ids []string) string { return strings.Join(ids, ",") }
+
+func splitIDs(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// Replace swaps the stored library, alerts, and history for the given
+// ones in one transaction, as restoring a backup does. A nil l or b, or
+// nil entries, leaves that store as it is.
+func (db *DB) Replace(l *library.Library, b *alert.Book, entries []history.Entry) error {
+	return db.update(func(tx *sql.Tx) error {
+		if l != nil {
+			if err := writeLibrary(tx, l); err != nil {
+				return fmt.Errorf("restoring library: %w", err)
+			}
+		}
+		if b != nil {
+			if err := writeAlerts(tx, b); err != nil {
+				return fmt.Errorf("restoring alerts: %w", err)
+			}
+		}
+		if entries != nil {
+			if err := replaceHistory(tx, entries); err != nil {
+				return fmt.Errorf("restoring history: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Holding lists the stores that hold data: "library", "alerts", and
+// "history".
+func (db *DB) Holding() ([]string, error) {
+	var held []string
+	for _, s := range []struct{ name, query string }{
+		{"library", "SELECT EXISTS (SELECT 1 FROM collections) OR EXISTS (SELECT 1 FROM notes) OR EXISTS (SELECT 1 FROM tags)"},
+		{"alerts", "SELECT EXISTS (SELECT 1 FROM alerts)"},
+		{"history", "SELECT EXISTS (SELECT 1 FROM history)"},
+	} {
+		var ok bool
+		if err := db.db.QueryRow(s.query).Scan(&ok); err != nil {
+			return nil, err
+		}
+		if ok {
+			held = append(held, s.name)
+		}
+	}
+	return held, nil
+}
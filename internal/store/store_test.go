@@ -0,0 +1,251 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/history"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+)
+
+func openTest(t *testing.T, dir string) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// changes returns the rows written through db's connection so far.
+func changes(t *testing.T, db *DB) int {
+	t.Helper()
+	var n int
+	if err := db.db.QueryRow("SELECT total_changes()").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestOpen_Migrates(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	db := openTest(t, dir)
+	if v, err := db.Version(); err != nil || v != len(migrations) {
+		t.Fatalf("Version = %d, %v; want %d", v, err, len(migrations))
+	}
+	db.Close()
+
+	// Reopening an up-to-date database applies nothing.
+	db = openTest(t, dir)
+	if v, _ := db.Version(); v != len(migrations) {
+		t.Errorf("Version after reopening = %d", v)
+	}
+	if _, err := db.db.Exec("PRAGMA user_version = 99"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if _, err := Open(filepath.Join(dir, FileName)); err == nil {
+		t.Error("expected a newer schema to be refused")
+	}
+}
+
+func TestLibrary_RoundTrip(t *testing.T) {
+	db := openTest(t, t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	l, err := db.Library()
+	if err != nil || len(l.Collections) != 0 || l.Articles == nil {
+		t.Fatalf("empty library = %+v, %v", l, err)
+	}
+	l.Add("fxs", []eutils.Article{{PMID: "2", Title: "Two"}, {PMID: "1", Title: "One"}}, now)
+	l.Add("asd", []eutils.Article{{PMID: "3", Title: "Three"}}, now)
+	l.AddNote("1", "key paper", now)
+	l.Tag("1", []string{"+rct", "+key"})
+	l.Tag("9", []string{"+later"})
+	if err := db.SaveLibrary(l); err != nil {
+		t.Fatalf("SaveLibrary: %v", err)
+	}
+
+	got, err := db.Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Collections) != 2 || got.Collections[0].Name != "asd" {
+		t.Fatalf("collections = %+v", got.Collections)
+	}
+	if fxs := got.Get("fxs"); !slices.Equal(fxs.PMIDs, []string{"2", "1"}) || !fxs.Created.Equal(now) {
+		t.Errorf("fxs = %+v", fxs)
+	}
+	if got.Articles["2"].Title != "Two" || len(got.Articles) != 3 {
+		t.Errorf("articles = %+v", got.Articles)
+	}
+	ann := got.Annotations["1"]
+	if ann == nil || len(ann.Notes) != 1 || ann.Notes[0].Text != "key paper" || !slices.Equal(ann.Tags, []string{"key", "rct"}) {
+		t.Errorf("annotation = %+v", ann)
+	}
+	if slices.Compare(got.Tagged("later"), []string{"9"}) != 0 {
+		t.Errorf("Tagged(later) = %v", got.Tagged("later"))
+	}
+
+	got.Delete("asd")
+	got.ClearNotes("1")
+	if err := db.SaveLibrary(got); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := db.Library()
+	if len(again.Collections) != 1 || len(again.Articles) != 2 {
+		t.Errorf("after delete: %+v, %d articles", again.Collections, len(again.Articles))
+	}
+	if ann := again.Annotations["1"]; ann == nil || len(ann.Notes) != 0 || len(ann.Tags) != 2 {
+		t.Errorf("after clearing notes: %+v", ann)
+	}
+}
+
+func TestSaveLibrary_WritesOnlyChanges(t *testing.T) {
+	db := openTest(t, t.TempDir())
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	l := library.New()
+	var articles []eutils.Article
+	for i := range 500 {
+		articles = append(articles, eutils.Article{PMID: strconv.Itoa(i + 1), Title: "T"})
+	}
+	l.Add("big", articles, now)
+	if err := db.SaveLibrary(l); err != nil {
+		t.Fatal(err)
+	}
+
+	l, _ = db.Library()
+	before := changes(t, db)
+	if err := db.SaveLibrary(l); err != nil {
+		t.Fatal(err)
+	}
+	if n := changes(t, db) - before; n != 0 {
+		t.Errorf("saving an unchanged library wrote %d rows", n)
+	}
+
+	l.AddNote(articles[0].PMID, "read this", now)
+	before = changes(t, db)
+	if err := db.SaveLibrary(l); err != nil {
+		t.Fatal(err)
+	}
+	if n := changes(t, db) - before; n != 1 {
+		t.Errorf("adding a note wrote %d rows, want 1", n)
+	}
+}
+
+func TestAlerts_RoundTrip(t *testing.T) {
+	db := openTest(t, t.TempDir())
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	b, err := db.Alerts()
+	if err != nil || len(b.Alerts) != 0 {
+		t.Fatalf("empty alerts = %+v, %v", b, err)
+	}
+	a, _ := b.Add("fxs", "fragile x syndrome", now)
+	a.Seen = []string{"3", "2"}
+	a.AddSnapshot(alert.Snapshot{Time: now, Count: 2, Status: map[string]string{"3": alert.Published}})
+	b.Add("asd", "autism", now)
+	if err := db.SaveAlerts(b); err != nil {
+		t.Fatalf("SaveAlerts: %v", err)
+	}
+
+	got, err := db.Alerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fxs := got.Get("fxs")
+	if len(got.Alerts) != 2 || fxs == nil || !slices.Equal(fxs.Seen, []string{"3", "2"}) || len(fxs.Snapshots) != 1 ||
+		fxs.Snapshots[0].Status["3"] != alert.Published || !fxs.LastRun.IsZero() {
+		t.Fatalf("alerts = %+v", got.Alerts)
+	}
+
+	// A run appends seen PMIDs; only the new ones are written.
+	fxs.Seen = append(fxs.Seen, "4")
+	fxs.LastRun = now.Add(time.Hour)
+	got.Remove("asd")
+	before := changes(t, db)
+	if err := db.SaveAlerts(got); err != nil {
+		t.Fatal(err)
+	}
+	if n := changes(t, db) - before; n != 3 { // alert row, one seen PMID, deleted alert
+		t.Errorf("saving a run wrote %d rows, want 3", n)
+	}
+	again, _ := db.Alerts()
+	if len(again.Alerts) != 1 || !slices.Equal(again.Get("fxs").Seen, []string{"3", "2", "4"}) || !again.Get("fxs").LastRun.Equal(fxs.LastRun) {
+		t.Errorf("after run: %+v", again.Alerts)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	db := openTest(t, t.TempDir())
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	first, err := db.AppendHistory(history.Entry{Time: now, Query: "autism", Count: 3, IDs: []string{"1", "2", "3"},
+		Options: eutils.SearchOptions{Limit: 3, Sort: "date"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := db.AppendHistory(history.Entry{Time: now, Query: "fragile x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("ids = %d, %d; want 1, 2", first.ID, second.ID)
+	}
+	entries, err := db.History()
+	if err != nil || len(entries) != 2 || entries[0].Options.Sort != "date" || !slices.Equal(entries[0].IDs, first.IDs) || entries[1].IDs != nil {
+		t.Errorf("History = %+v, %v", entries, err)
+	}
+}
+
+func TestOpen_ImportsLegacyFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	l := library.New()
+	l.Add("fxs", []eutils.Article{{PMID: "1", Title: "One"}}, now)
+	l.Tag("1", []string{"+key"})
+	b := &alert.Book{}
+	b.Add("fxs", "fragile x", now)
+	writeJSON(t, filepath.Join(dir, LegacyLibrary), l)
+	writeJSON(t, filepath.Join(dir, LegacyAlerts), b)
+	e := history.Entry{ID: 1, Time: now, Query: "autism"}
+	writeJSON(t, filepath.Join(dir, LegacyHistory), e)
+
+	db := openTest(t, dir)
+	got, err := db.Library()
+	if err != nil || len(got.Collections) != 1 || got.Articles["1"].Title != "One" || got.Tagged("key") == nil {
+		t.Errorf("imported library = %+v, %v", got, err)
+	}
+	if alerts, err := db.Alerts(); err != nil || alerts.Get("fxs") == nil {
+		t.Errorf("imported alerts = %+v, %v", alerts, err)
+	}
+	if entries, err := db.History(); err != nil || len(entries) != 1 || entries[0].Query != "autism" {
+		t.Errorf("imported history = %+v, %v", entries, err)
+	}
+	for _, name := range []string{LegacyLibrary, LegacyAlerts, LegacyHistory} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s still in place after import", name)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name+".imported")); err != nil {
+			t.Errorf("%s not kept as .imported: %v", name, err)
+		}
+	}
+}
+
+func writeJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
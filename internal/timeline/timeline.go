@@ -0,0 +1,99 @@
+// Package timeline arranges articles chronologically, grouped by year, with
+// a one-line summary of each, and renders the result as Markdown or HTML.
+package timeline
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/compare"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Undated groups articles with no publication year; it sorts last.
+const Undated = "Undated"
+
+// maxSummary bounds a summary line, in runes.
+const maxSummary = 240
+
+// Entry is one article on the timeline.
+type Entry struct {
+	PMID    string `json:"pmid"`
+	Title   string `json:"title"`
+	Journal string `json:"journal,omitempty"`
+	// Summary is the article's main finding, taken from its abstract's
+	// conclusions (or closing sentence); empty without an abstract.
+	Summary string `json:"summary,omitempty"`
+	// Landmark marks the article a citation timeline descends from.
+	Landmark bool `json:"landmark,omitempty"`
+}
+
+// Year is the articles published in one year.
+type Year struct {
+	Year    string  `json:"year"`
+	Entries []Entry `json:"entries"`
+}
+
+// Timeline is a titled, chronological list of articles.
+type Timeline struct {
+	Title string `json:"title"`
+	// Query or Landmark records what the timeline was built from.
+	Query    string `json:"query,omitempty"`
+	Landmark string `json:"landmark,omitempty"`
+	Years    []Year `json:"years"`
+}
+
+// Build groups articles by year, oldest first, keeping the given order
+// within a year. landmark, when set, is the PMID the timeline descends from.
+func Build(title string, articles []eutils.Article, landmark string) *Timeline {
+	t := &Timeline{Title: title, Landmark: landmark, Years: []Year{}}
+	index := make(map[string]int)
+	for _, a := range articles {
+		year := a.Year
+		if year == "" {
+			year = Undated
+		}
+		i, ok := index[year]
+		if !ok {
+			i = len(t.Years)
+			index[year] = i
+			t.Years = append(t.Years, Year{Year: year})
+		}
+		journal := a.JournalAbbrev
+		if journal == "" {
+			journal = a.Journal
+		}
+		t.Years[i].Entries = append(t.Years[i].Entries, Entry{
+			PMID:     a.PMID,
+			Title:    a.Title,
+			Journal:  journal,
+			Summary:  summarize(a),
+			Landmark: a.PMID == landmark,
+		})
+	}
+	sort.SliceStable(t.Years, func(i, j int) bool {
+		a, b := t.Years[i].Year, t.Years[j].Year
+		if a == Undated || b == Undated {
+			return b == Undated && a != Undated
+		}
+		return a < b
+	})
+	return t
+}
+
+// Len returns the number of articles on the timeline.
+func (t *Timeline) Len() int {
+	n := 0
+	for _, y := range t.Years {
+		n += len(y.Entries)
+	}
+	return n
+}
+
+func summarize(a eutils.Article) string {
+	s := compare.Extract(a).Findings
+	if r := []rune(s); len(r) > maxSummary {
+		s = strings.TrimSpace(string(r[:maxSummary-3])) + "..."
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package timeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestBuild(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "3", Title: "Follow-up", Year: "2021", Abstract: "We followed them up. In conclusion, it lasted."},
+		{PMID: "1", Title: "Landmark trial", Year: "2018", JournalAbbrev: "N Engl J Med",
+			AbstractSections: []eutils.AbstractSection{{Label: "CONCLUSIONS", Text: "Metformin helped. More work is needed."}}},
+		{PMID: "4", Title: "Letter"},
+		{PMID: "2", Title: "Replication", Year: "2021"},
+	}
+	tl := Build("Metformin in FXS", articles, "1")
+	var years []string
+	for _, y := range tl.Years {
+		years = append(years, y.Year)
+	}
+	if got := strings.Join(years, ","); got != "2018,2021,"+Undated {
+		t.Fatalf("years = %s", got)
+	}
+	if tl.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", tl.Len())
+	}
+	first := tl.Years[0].Entries[0]
+	if !first.Landmark || first.Journal != "N Engl J Med" || first.Summary != "Metformin helped." {
+		t.Errorf("landmark entry = %+v", first)
+	}
+	if got := tl.Years[1].Entries; got[0].PMID != "3" || got[1].PMID != "2" {
+		t.Errorf("expected input order within a year, got %+v", got)
+	}
+	if tl.Years[1].Entries[0].Summary != "In conclusion, it lasted." {
+		t.Errorf("summary = %q", tl.Years[1].Entries[0].Summary)
+	}
+}
+
+func TestSummarize_Truncates(t *testing.T) {
+	a := eutils.Article{Abstract: strings.Repeat("word ", 100)}
+	if s := summarize(a); len([]rune(s)) > maxSummary || !strings.HasSuffix(s, "...") {
+		t.Errorf("summary not truncated: %q", s)
+	}
+}
@@ -0,0 +1,95 @@
+package timeline
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Formats accepted by Write.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Write renders t in the given format.
+func Write(w io.Writer, t *Timeline, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return WriteMarkdown(w, t)
+	case FormatHTML:
+		return WriteHTML(w, t)
+	default:
+		return fmt.Errorf("unknown timeline format %q (use markdown or html)", format)
+	}
+}
+
+// WriteMarkdown renders t as a Markdown document: a heading per year and a
+// bullet per article with its PubMed link and summary.
+func WriteMarkdown(w io.Writer, t *Timeline) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", t.Title)
+	for _, y := range t.Years {
+		fmt.Fprintf(&b, "\n## %s\n\n", y.Year)
+		for _, e := range y.Entries {
+			title := markdownEscaper.Replace(e.Title)
+			if e.Landmark {
+				title = "**" + title + "**"
+			}
+			fmt.Fprintf(&b, "- %s", title)
+			if e.Journal != "" {
+				fmt.Fprintf(&b, " _%s_", markdownEscaper.Replace(e.Journal))
+			}
+			fmt.Fprintf(&b, " [PMID %s](%s)\n", e.PMID, pubmedURL(e.PMID))
+			if e.Summary != "" {
+				fmt.Fprintf(&b, "  %s\n", markdownEscaper.Replace(e.Summary))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscaper escapes the characters that would otherwise turn titles
+// into links or emphasis.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`)
+
+func pubmedURL(pmid string) string {
+	return "https://pubmed.ncbi.nlm.nih.gov/" + pmid + "/"
+}
+
+var htmlTemplate = template.Must(template.New("timeline").Funcs(template.FuncMap{"pubmedURL": pubmedURL}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.45; color: #222; }
+section { border-left: 3px solid #2a7ab0; margin-left: 0.5rem; padding-left: 1.25rem; }
+h2 { margin: 1.5rem 0 0.5rem -2rem; padding-left: 0.5rem; background: #fff; color: #2a7ab0; }
+article { margin-bottom: 1rem; }
+article.landmark h3 { color: #b0442a; }
+h3 { font-size: 1rem; margin: 0; }
+.meta { color: #666; font-size: 0.9rem; }
+p { margin: 0.25rem 0 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Years}}<section>
+<h2>{{.Year}}</h2>
+{{range .Entries}}<article{{if .Landmark}} class="landmark"{{end}}>
+<h3>{{.Title}}</h3>
+<div class="meta">{{if .Journal}}{{.Journal}} · {{end}}<a href="{{pubmedURL .PMID}}">PMID {{.PMID}}</a></div>
+{{if .Summary}}<p>{{.Summary}}</p>
+{{end}}</article>
+{{end}}</section>
+{{end}}</body>
+</html>
+`))
+
+// WriteHTML renders t as a standalone HTML page.
+func WriteHTML(w io.Writer, t *Timeline) error {
+	return htmlTemplate.Execute(w, t)
+}
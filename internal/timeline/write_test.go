@@ -0,0 +1,52 @@
+package timeline
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var sample = &Timeline{
+	Title: "Fragile X <timeline>",
+	Years: []Year{
+		{Year: "2018", Entries: []Entry{{PMID: "1", Title: "A *landmark* trial", Journal: "Lancet", Summary: "It worked.", Landmark: true}}},
+		{Year: "2021", Entries: []Entry{{PMID: "2", Title: "A follow-up"}}},
+	},
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sample, FormatMarkdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# Fragile X <timeline>\n" +
+		"\n## 2018\n\n" +
+		"- **A \\*landmark\\* trial** _Lancet_ [PMID 1](https://pubmed.ncbi.nlm.nih.gov/1/)\n" +
+		"  It worked.\n" +
+		"\n## 2021\n\n" +
+		"- A follow-up [PMID 2](https://pubmed.ncbi.nlm.nih.gov/2/)\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sample, FormatHTML); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>Fragile X &lt;timeline&gt;</title>",
+		`<article class="landmark">`,
+		`<a href="https://pubmed.ncbi.nlm.nih.gov/2/">PMID 2</a>`,
+		"<p>It worked.</p>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML to contain %q, got:\n%s", want, out)
+		}
+	}
+	if err := Write(&buf, sample, "pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
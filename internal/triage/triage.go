@@ -0,0 +1,211 @@
+// Package triage ranks a list of articles by how closely they match a
+// question. Ranking is lexical, BM25 over titles, abstracts, MeSH terms,
+// and keywords, so it orders a list for reading rather than judging it:
+// an article that answers the question in other words ranks low.
+package triage
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/library"
+)
+
+// Field weights, as in library search: a term in the title or among the
+// subject headings says more about an article than one in the abstract.
+const (
+	weightTitle    = 3
+	weightSubjects = 2
+	weightAbstract = 1
+)
+
+// BM25 parameters, at their usual values.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Result is one article's place in a triaged list.
+type Result struct {
+	Rank  int    `json:"rank"`
+	PMID  string `json:"pmid"`
+	Title string `json:"title"`
+	Year  string `json:"year,omitempty"`
+	// Score is the article's BM25 score against the question, and
+	// Relevance the score as a share of the best in the list, from 0 to 1.
+	Score     float64 `json:"score"`
+	Relevance float64 `json:"relevance"`
+	// Matched lists the question terms the article contains, and Missing
+	// those it lacks.
+	Matched []string `json:"matched"`
+	Missing []string `json:"missing,omitempty"`
+	// Justification is the sentence of the title or abstract that matches
+	// the question best.
+	Justification string `json:"justification,omitempty"`
+}
+
+// Terms returns the content words of question: lowercased, stemmed, and
+// without stopwords or repeats, in order.
+func Terms(question string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, w := range words(question) {
+		if stopwords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// Rank scores articles against question and returns them best first.
+// Articles matching no term of the question come last, with a score of
+// zero, in the order given.
+func Rank(question string, articles []eutils.Article) []Result {
+	terms := Terms(question)
+
+	type doc struct {
+		tf     map[string]float64
+		length float64
+	}
+	docs := make([]doc, len(articles))
+	df := make(map[string]float64)
+	var total float64
+	for i, a := range articles {
+		d := doc{tf: make(map[string]float64)}
+		index := func(text string, weight float64) {
+			for _, w := range words(text) {
+				d.tf[w] += weight
+				d.length += weight
+			}
+		}
+		index(a.Title, weightTitle)
+		index(a.Abstract, weightAbstract)
+		for _, m := range a.MeSHTerms {
+			index(m.Descriptor, weightSubjects)
+		}
+		for _, k := range a.Keywords {
+			index(k, weightSubjects)
+		}
+		for _, t := range terms {
+			if d.tf[t] > 0 {
+				df[t]++
+			}
+		}
+		docs[i] = d
+		total += d.length
+	}
+
+	n := float64(len(articles))
+	avg := 1.0
+	if total > 0 {
+		avg = total / n
+	}
+	idf := make(map[string]float64, len(terms))
+	for _, t := range terms {
+		idf[t] = math.Log(1 + (n-df[t]+0.5)/(df[t]+0.5))
+	}
+
+	results := make([]Result, len(articles))
+	for i, a := range articles {
+		r := Result{PMID: a.PMID, Title: a.Title, Year: a.Year}
+		d := docs[i]
+		for _, t := range terms {
+			f := d.tf[t]
+			if f == 0 {
+				r.Missing = append(r.Missing, t)
+				continue
+			}
+			r.Matched = append(r.Matched, t)
+			r.Score += idf[t] * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*d.length/avg))
+		}
+		if r.Score > 0 {
+			r.Justification = justify(a, idf)
+		}
+		results[i] = r
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	best := 0.0
+	if len(results) > 0 {
+		best = results[0].Score
+	}
+	for i := range results {
+		results[i].Rank = i + 1
+		if best > 0 {
+			results[i].Relevance = math.Round(100*results[i].Score/best) / 100
+		}
+		results[i].Score = math.Round(1000*results[i].Score) / 1000
+	}
+	return results
+}
+
+// justify returns the sentence of a's title or abstract carrying the most
+// question-term weight, counting each term once, preferring the title on a
+// tie.
+func justify(a eutils.Article, idf map[string]float64) string {
+	best, bestScore := "", 0.0
+	for _, s := range append([]string{a.Title}, sentences(a.Abstract)...) {
+		score := 0.0
+		seen := make(map[string]bool)
+		for _, w := range words(s) {
+			if !seen[w] {
+				seen[w] = true
+				score += idf[w]
+			}
+		}
+		if score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+	return best
+}
+
+// words splits text into lowercased, stemmed words.
+func words(text string) []string {
+	ws := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range ws {
+		ws[i] = library.Stem(w)
+	}
+	return ws
+}
+
+// sentenceEnd matches the end of a sentence: a period, question mark, or
+// exclamation point followed by space and a capital letter or digit.
+var sentenceEnd = regexp.MustCompile(`[.?!]\s+[A-Z0-9]`)
+
+func sentences(text string) []string {
+	text = strings.TrimSpace(text)
+	var out []string
+	for text != "" {
+		loc := sentenceEnd.FindStringIndex(text)
+		if loc == nil {
+			out = append(out, text)
+			break
+		}
+		out = append(out, strings.TrimSpace(text[:loc[0]+1]))
+		text = strings.TrimSpace(text[loc[1]-1:])
+	}
+	return out
+}
+
+// stopwords are the function words of questions, which say nothing about
+// what an article should be about.
+var stopwords = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(`
+		a about all also an and any are as at be been being between but by
+		can could did do does for from had has have how i if in into is it
+		its may might more most of on or our should than that the their
+		there these this those to was we were what when where whether which
+		while who why will with would`) {
+		m[w] = true
+	}
+	return m
+}()
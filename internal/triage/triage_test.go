@@ -0,0 +1,42 @@
+package triage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestTerms(t *testing.T) {
+	got := Terms("Does metformin improve language in fragile X syndrome? Metformin trials")
+	want := []string{"metformin", "improve", "language", "fragile", "x", "syndrome", "trial"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms = %v, want %v", got, want)
+	}
+}
+
+func TestRank(t *testing.T) {
+	articles := []eutils.Article{
+		{PMID: "1", Title: "Sleep in autism", Abstract: "We studied sleep."},
+		{PMID: "2", Title: "Lovastatin in fragile X syndrome", Abstract: "Background on statins. Lovastatin was well tolerated in fragile X syndrome."},
+		{PMID: "3", Title: "Metformin for fragile X syndrome: a trial", Abstract: "Children were enrolled. Metformin improved expressive language in fragile X syndrome."},
+	}
+	got := Rank("Does metformin improve language in fragile X syndrome?", articles)
+	var order []string
+	for _, r := range got {
+		order = append(order, r.PMID)
+	}
+	if !reflect.DeepEqual(order, []string{"3", "2", "1"}) {
+		t.Fatalf("order = %v, want [3 2 1]", order)
+	}
+	top := got[0]
+	if top.Rank != 1 || top.Relevance != 1 || top.Justification != "Metformin improved expressive language in fragile X syndrome." {
+		t.Errorf("top = %+v", top)
+	}
+	if !reflect.DeepEqual(got[1].Missing, []string{"metformin", "improve", "language"}) {
+		t.Errorf("Missing = %v", got[1].Missing)
+	}
+	if last := got[2]; last.Score != 0 || last.Relevance != 0 || last.Justification != "" || last.Matched != nil {
+		t.Errorf("unmatched article = %+v", last)
+	}
+}
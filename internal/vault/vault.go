@@ -0,0 +1,315 @@
+// Package vault writes articles as a Markdown vault for Obsidian (which
+// Notion can also import): one note per article with YAML frontmatter,
+// wiki-links between citing and cited articles, and an index note that
+// links them all.
+package vault
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultTitle names the index note when the export has no query or
+// collection name.
+const DefaultTitle = "PubMed export"
+
+// MaxMostCited is how many articles the index lists as most cited within
+// the vault.
+const MaxMostCited = 10
+
+// frontmatter is an article note's YAML properties.
+type frontmatter struct {
+	Title   string   `yaml:"title"`
+	Aliases []string `yaml:"aliases,omitempty"`
+	PMID    string   `yaml:"pmid,omitempty"`
+	DOI     string   `yaml:"doi,omitempty"`
+	PMCID   string   `yaml:"pmcid,omitempty"`
+	Journal string   `yaml:"journal,omitempty"`
+	Year    string   `yaml:"year,omitempty"`
+	Authors []string `yaml:"authors,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+}
+
+// Write writes a note per article into dir, named by NoteName, and an
+// index note named after title. refs maps a PMID to the PMIDs it
+// references; only references between articles in the vault become links.
+// Existing notes of the same name are replaced. It returns the path of
+// the index note.
+func Write(dir, title string, articles []eutils.Article, refs map[string][]string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", dir, err)
+	}
+	if title == "" {
+		title = DefaultTitle
+	}
+
+	byPMID := make(map[string]eutils.Article, len(articles))
+	for _, a := range articles {
+		if a.PMID != "" {
+			byPMID[a.PMID] = a
+		}
+	}
+	cites := make(map[string][]string)
+	citedBy := make(map[string][]string)
+	for _, a := range articles {
+		for _, ref := range refs[a.PMID] {
+			if _, ok := byPMID[ref]; ok && ref != a.PMID {
+				cites[a.PMID] = append(cites[a.PMID], ref)
+				citedBy[ref] = append(citedBy[ref], a.PMID)
+			}
+		}
+	}
+
+	for _, a := range articles {
+		note, err := articleNote(a, cites[a.PMID], citedBy[a.PMID], byPMID, title)
+		if err != nil {
+			return "", err
+		}
+		if err := writeNote(dir, NoteName(a), note); err != nil {
+			return "", err
+		}
+	}
+	index, err := indexNote(title, articles, cites, citedBy)
+	if err != nil {
+		return "", err
+	}
+	name := fileName(title)
+	if err := writeNote(dir, name, index); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".md"), nil
+}
+
+// NoteName is an article note's name: its PMID, or its DOI for records
+// without one.
+func NoteName(a eutils.Article) string {
+	if a.PMID != "" {
+		return a.PMID
+	}
+	return fileName("doi " + a.DOI)
+}
+
+// fileName makes s safe as a note name on every platform and in
+// wiki-links.
+func fileName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|#^[]`, r) {
+			return '-'
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// link is a wiki-link to pmid's note, shown as its short citation.
+func link(pmid string, byPMID map[string]eutils.Article) string {
+	return "[[" + pmid + "|" + shortCite(byPMID[pmid]) + "]]"
+}
+
+// shortCite is an author-year label, e.g. "Smith et al. 2024".
+func shortCite(a eutils.Article) string {
+	var author string
+	switch len(a.Authors) {
+	case 0:
+		author = a.Journal
+	case 1:
+		author = surname(a.Authors[0])
+	case 2:
+		author = surname(a.Authors[0]) + " & " + surname(a.Authors[1])
+	default:
+		author = surname(a.Authors[0]) + " et al."
+	}
+	return strings.TrimSpace(fileName(author + " " + a.Year))
+}
+
+// surname is an author's last name, or a group author's name.
+func surname(au eutils.Author) string {
+	if au.LastName != "" {
+		return au.LastName
+	}
+	return au.FullName()
+}
+
+// tag makes s an Obsidian tag: letters, digits, and dashes, with an
+// optional prefix such as "mesh/".
+func tag(prefix, s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127 {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	t := strings.Trim(b.String(), "-")
+	if t == "" {
+		return ""
+	}
+	return prefix + t
+}
+
+func articleNote(a eutils.Article, cites, citedBy []string, byPMID map[string]eutils.Article, title string) (string, error) {
+	fm := frontmatter{
+		Title:   a.Title,
+		Aliases: []string{shortCite(a)},
+		PMID:    a.PMID,
+		DOI:     a.DOI,
+		PMCID:   a.PMCID,
+		Journal: a.Journal,
+		Year:    a.Year,
+	}
+	for _, au := range a.Authors {
+		fm.Authors = append(fm.Authors, au.FullName())
+	}
+	for _, m := range a.MeSHTerms {
+		if t := tag("mesh/", m.Descriptor); t != "" {
+			fm.Tags = append(fm.Tags, t)
+		}
+	}
+	for _, t := range a.Tags {
+		if t = tag("", t); t != "" {
+			fm.Tags = append(fm.Tags, t)
+		}
+	}
+
+	var b strings.Builder
+	if err := writeFrontmatter(&b, fm); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "# %s\n\n", a.Title)
+	if len(fm.Authors) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(fm.Authors, ", "))
+	}
+	var links []string
+	if a.PMID != "" {
+		links = append(links, "[PubMed](https://pubmed.ncbi.nlm.nih.gov/"+a.PMID+"/)")
+	}
+	if a.DOI != "" {
+		links = append(links, "[DOI](https://doi.org/"+a.DOI+")")
+	}
+	if a.PMCID != "" {
+		links = append(links, "[PMC](https://pmc.ncbi.nlm.nih.gov/articles/"+a.PMCID+"/)")
+	}
+	source := strings.TrimSpace("*" + a.Journal + "* " + a.Year)
+	if a.Journal == "" {
+		source = a.Year
+	}
+	fmt.Fprintf(&b, "%s\n", strings.TrimSpace(source+" · "+strings.Join(links, " · ")))
+	fmt.Fprintf(&b, "\nPart of [[%s]].\n", fileName(title))
+
+	if a.Abstract != "" {
+		fmt.Fprintf(&b, "\n## Abstract\n\n%s\n", a.Abstract)
+	}
+	if len(cites) > 0 {
+		b.WriteString("\n## Cites\n\n")
+		for _, pmid := range cites {
+			fmt.Fprintf(&b, "- %s %s\n", link(pmid, byPMID), byPMID[pmid].Title)
+		}
+	}
+	if len(citedBy) > 0 {
+		b.WriteString("\n## Cited by\n\n")
+		for _, pmid := range citedBy {
+			fmt.Fprintf(&b, "- %s %s\n", link(pmid, byPMID), byPMID[pmid].Title)
+		}
+	}
+	if len(a.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, n := range a.Notes {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	return b.String(), nil
+}
+
+// indexNote links every article, most cited within the vault first, then
+// by year, newest first.
+func indexNote(title string, articles []eutils.Article, cites, citedBy map[string][]string) (string, error) {
+	byPMID := make(map[string]eutils.Article, len(articles))
+	for _, a := range articles {
+		byPMID[a.PMID] = a
+	}
+	entry := func(a eutils.Article) string {
+		if a.PMID == "" {
+			return "[[" + NoteName(a) + "|" + shortCite(a) + "]] " + a.Title
+		}
+		return link(a.PMID, byPMID) + " " + a.Title
+	}
+	links := 0
+	for _, c := range cites {
+		links += len(c)
+	}
+
+	var b strings.Builder
+	if err := writeFrontmatter(&b, frontmatter{Title: title, Tags: []string{"pubmed-export"}}); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "# %s\n\n%d articles, %d citation links between them.\n", title, len(articles), links)
+
+	var cited []eutils.Article
+	for _, a := range articles {
+		if len(citedBy[a.PMID]) > 0 {
+			cited = append(cited, a)
+		}
+	}
+	sort.SliceStable(cited, func(i, j int) bool { return len(citedBy[cited[i].PMID]) > len(citedBy[cited[j].PMID]) })
+	if len(cited) > MaxMostCited {
+		cited = cited[:MaxMostCited]
+	}
+	if len(cited) > 0 {
+		b.WriteString("\n## Most cited in this set\n\n")
+		for _, a := range cited {
+			fmt.Fprintf(&b, "- %s (cited by %d)\n", entry(a), len(citedBy[a.PMID]))
+		}
+	}
+
+	years := make(map[string][]eutils.Article)
+	for _, a := range articles {
+		years[a.Year] = append(years[a.Year], a)
+	}
+	order := make([]string, 0, len(years))
+	for y := range years {
+		order = append(order, y)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(order)))
+	for _, y := range order {
+		heading := y
+		if heading == "" {
+			heading = "Undated"
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", heading)
+		for _, a := range years[y] {
+			fmt.Fprintf(&b, "- %s\n", entry(a))
+		}
+	}
+	return b.String(), nil
+}
+
+func writeFrontmatter(b *strings.Builder, fm frontmatter) error {
+	data, err := yaml.Marshal(fm)
+	if err != nil {
+		return fmt.Errorf("encoding frontmatter: %w", err)
+	}
+	b.WriteString("---\n")
+	b.Write(data)
+	b.WriteString("---\n\n")
+	return nil
+}
+
+func writeNote(dir, name, note string) error {
+	path := filepath.Join(dir, name+".md")
+	if err := os.WriteFile(path, []byte(note), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	articles := []eutils.Article{
+		{
+			PMID: "38000001", Title: "Metformin in fragile X: a pilot trial", Journal: "J Neurodev Disord", Year: "2023",
+			DOI:       "10.1000/pilot.1",
+			Authors:   []eutils.Author{{LastName: "Smith", ForeName: "Jane"}, {LastName: "Doe", ForeName: "Al"}, {LastName: "Roe", ForeName: "Bo"}},
+			MeSHTerms: []eutils.MeSHTerm{{Descriptor: "Fragile X Syndrome"}, {Descriptor: "Metformin"}},
+			Abstract:  "Metformin was well tolerated.",
+			Tags:      []string{"to read"},
+		},
+		{
+			PMID: "38000002", Title: "Metformin in adults", Year: "2025",
+			Authors: []eutils.Author{{CollectiveName: "FXS Consortium"}},
+			Notes:   []string{"Replicates the pilot."},
+		},
+	}
+	refs := map[string][]string{"38000002": {"38000001", "35000003"}}
+
+	index, err := Write(dir, "fragile x: metformin", articles, refs)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if index != filepath.Join(dir, "fragile x- metformin.md") {
+		t.Errorf("index path = %q", index)
+	}
+
+	pilot := readNote(t, dir, "38000001")
+	for _, want := range []string{
+		"---\ntitle: 'Metformin in fragile X: a pilot trial'\n",
+		"aliases:\n    - Smith et al. 2023\n",
+		"doi: 10.1000/pilot.1\n",
+		"    - mesh/Fragile-X-Syndrome\n    - mesh/Metformin\n    - to-read\n",
+		"[DOI](https://doi.org/10.1000/pilot.1)",
+		"Part of [[fragile x- metformin]].",
+		"## Cited by\n\n- [[38000002|FXS Consortium 2025]] Metformin in adults\n",
+	} {
+		if !strings.Contains(pilot, want) {
+			t.Errorf("pilot note missing %q:\n%s", want, pilot)
+		}
+	}
+	adults := readNote(t, dir, "38000002")
+	if !strings.Contains(adults, "## Cites\n\n- [[38000001|Smith et al. 2023]] Metformin in fragile X: a pilot trial\n") ||
+		strings.Contains(adults, "35000003") || !strings.Contains(adults, "## Notes\n\n- Replicates the pilot.\n") {
+		t.Errorf("unexpected adults note:\n%s", adults)
+	}
+
+	idx := readNote(t, dir, "fragile x- metformin")
+	for _, want := range []string{
+		"2 articles, 1 citation links between them.",
+		"## Most cited in this set\n\n- [[38000001|Smith et al. 2023]] Metformin in fragile X: a pilot trial (cited by 1)\n",
+		"## 2025\n\n- [[38000002|FXS Consortium 2025]] Metformin in adults\n\n## 2023\n",
+	} {
+		if !strings.Contains(idx, want) {
+			t.Errorf("index missing %q:\n%s", want, idx)
+		}
+	}
+}
+
+func TestTag(t *testing.T) {
+	tests := map[string]string{
+		"Fragile X Syndrome":               "mesh/Fragile-X-Syndrome",
+		"Receptors, Metabotropic (mGluR5)": "mesh/Receptors-Metabotropic-mGluR5",
+		"!!":                               "",
+	}
+	for in, want := range tests {
+		if got := tag("mesh/", in); got != want {
+			t.Errorf("tag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func readNote(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name+".md"))
+	if err != nil {
+		t.Fatalf("reading note: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,196 @@
+// Package webhook posts notifications when an alert run finds new papers
+// or a batch run finishes, to Slack, Discord, or any endpoint that accepts
+// a JSON POST.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/batch"
+)
+
+// EnvURL sets the webhook URL when --webhook is not given.
+const EnvURL = "PUBMED_CLI_WEBHOOK"
+
+// Events.
+const (
+	EventAlertResults  = "alert.new_results"
+	EventBatchFinished = "batch.finished"
+)
+
+// Kinds of endpoint, detected from the webhook URL by Kind.
+const (
+	KindSlack   = "slack"
+	KindDiscord = "discord"
+	KindJSON    = "json"
+)
+
+// MaxListed is how many new articles per alert the message text lists.
+const MaxListed = 5
+
+// discordLimit is the most characters a Discord message may have.
+const discordLimit = 2000
+
+// Event is a notification. Generic endpoints receive it as JSON; Slack
+// and Discord receive only its Text.
+type Event struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	// Text is a human-readable summary, the message posted to chat.
+	Text string `json:"text"`
+	Data any    `json:"data"`
+}
+
+// Article identifies a new article in an alert event.
+type Article struct {
+	PMID    string `json:"pmid"`
+	Title   string `json:"title,omitempty"`
+	Journal string `json:"journal,omitempty"`
+	Year    string `json:"year,omitempty"`
+	DOI     string `json:"doi,omitempty"`
+	URL     string `json:"url"`
+}
+
+// AlertResults is the data of an EventAlertResults event.
+type AlertResults struct {
+	Total  int           `json:"total"`
+	Alerts []AlertResult `json:"alerts"`
+}
+
+// AlertResult is one alert's new articles.
+type AlertResult struct {
+	Name      string    `json:"name"`
+	Query     string    `json:"query"`
+	Truncated bool      `json:"truncated,omitempty"`
+	Articles  []Article `json:"articles"`
+}
+
+// AlertEvent summarizes the alerts in results that found new articles.
+func AlertEvent(results []alert.Result, now time.Time) Event {
+	data := AlertResults{Alerts: []AlertResult{}}
+	var b strings.Builder
+	for _, r := range results {
+		if len(r.New) == 0 {
+			continue
+		}
+		titles := make(map[string]Article, len(r.Articles))
+		for _, a := range r.Articles {
+			titles[a.PMID] = Article{PMID: a.PMID, Title: a.Title, Journal: a.Journal, Year: a.Year, DOI: a.DOI}
+		}
+		ar := AlertResult{Name: r.Name, Query: r.Query, Truncated: r.Truncated}
+		for _, pmid := range r.New {
+			a := titles[pmid]
+			a.PMID, a.URL = pmid, "https://pubmed.ncbi.nlm.nih.gov/"+pmid+"/"
+			ar.Articles = append(ar.Articles, a)
+		}
+		data.Alerts = append(data.Alerts, ar)
+		data.Total += len(r.New)
+
+		fmt.Fprintf(&b, "\n%s: %d new", r.Name, len(r.New))
+		if r.Truncated {
+			b.WriteString(" (more not listed)")
+		}
+		for i, a := range ar.Articles {
+			if i == MaxListed {
+				fmt.Fprintf(&b, "\n• …and %d more", len(ar.Articles)-MaxListed)
+				break
+			}
+			title := a.Title
+			if title == "" {
+				title = "PMID " + a.PMID
+			}
+			fmt.Fprintf(&b, "\n• %s %s", title, a.URL)
+		}
+	}
+	text := fmt.Sprintf("pubmed alert run: %d new %s", data.Total, plural(data.Total, "result"))
+	return Event{Event: EventAlertResults, Time: now, Text: text + b.String(), Data: data}
+}
+
+// BatchEvent summarizes a finished batch run.
+func BatchEvent(s batch.Summary) Event {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pubmed batch run %s finished in %s: %d of %d jobs succeeded",
+		s.Spec, s.Finished.Sub(s.Started).Round(time.Second), len(s.Jobs)-s.Failed, len(s.Jobs))
+	for _, j := range s.Jobs {
+		if j.OK() {
+			fmt.Fprintf(&b, "\n• %s: %d %s", j.Name, j.Count, plural(j.Count, "result"))
+			if j.Out != "" {
+				fmt.Fprintf(&b, ", exported %d to %s", j.Exported, j.Out)
+			}
+			continue
+		}
+		fmt.Fprintf(&b, "\n• %s: failed: %s", j.Name, j.Error)
+	}
+	return Event{Event: EventBatchFinished, Time: s.Finished, Text: b.String(), Data: s}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+// Kind reports what kind of endpoint u is: Slack and Discord incoming
+// webhooks are recognized by host, and anything else gets the JSON event.
+func Kind(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return KindJSON
+	}
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return KindSlack
+	case (host == "discord.com" || host == "discordapp.com") && strings.HasPrefix(parsed.Path, "/api/webhooks/"):
+		return KindDiscord
+	}
+	return KindJSON
+}
+
+// Payload encodes e for an endpoint of the given kind.
+func Payload(kind string, e Event) ([]byte, error) {
+	switch kind {
+	case KindSlack:
+		return json.Marshal(map[string]string{"text": e.Text})
+	case KindDiscord:
+		text := []rune(e.Text)
+		if len(text) > discordLimit {
+			text = append(text[:discordLimit-1], '…')
+		}
+		return json.Marshal(map[string]string{"content": string(text)})
+	}
+	return json.Marshal(e)
+}
+
+// Post sends e to the webhook at u. Any 2xx response is success.
+func Post(ctx context.Context, client *http.Client, u string, e Event) error {
+	body, err := Payload(Kind(u), e)
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("posting webhook: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
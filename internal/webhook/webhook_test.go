@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/alert"
+	"github.com/henrybloomingdale/pubmed-cli/internal/batch"
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestKind(t *testing.T) {
+	tests := map[string]string{
+		"https://hooks.slack.com/services/T0/B0/x":   KindSlack,
+		"https://discord.com/api/webhooks/1/abc":     KindDiscord,
+		"https://discordapp.com/api/webhooks/1/abc":  KindDiscord,
+		"https://discord.com/channels/1":             KindJSON,
+		"https://example.org/hooks/pubmed?token=abc": KindJSON,
+	}
+	for u, want := range tests {
+		if got := Kind(u); got != want {
+			t.Errorf("Kind(%q) = %q, want %q", u, got, want)
+		}
+	}
+}
+
+func TestAlertEvent(t *testing.T) {
+	now := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	results := []alert.Result{
+		{Name: "quiet", Query: "q", New: []string{}},
+		{Name: "fxs-trials", Query: "fragile x", New: []string{"39000001", "39000002"},
+			Articles: []eutils.Article{{PMID: "39000001", Title: "Metformin in adults", Year: "2026"}}},
+	}
+	e := AlertEvent(results, now)
+	want := "pubmed alert run: 2 new results\n" +
+		"fxs-trials: 2 new\n" +
+		"• Metformin in adults https://pubmed.ncbi.nlm.nih.gov/39000001/\n" +
+		"• PMID 39000002 https://pubmed.ncbi.nlm.nih.gov/39000002/"
+	if e.Text != want {
+		t.Errorf("Text = %q, want %q", e.Text, want)
+	}
+	data := e.Data.(AlertResults)
+	if e.Event != EventAlertResults || data.Total != 2 || len(data.Alerts) != 1 || data.Alerts[0].Articles[0].Year != "2026" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+}
+
+func TestBatchEvent(t *testing.T) {
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	s := batch.Summary{Spec: "jobs.yaml", Started: start, Finished: start.Add(95 * time.Second)}
+	s.Add(batch.Result{Name: "trials", Count: 12, Out: "trials.ris", Exported: 12})
+	s.Add(batch.Result{Name: "reviews", Error: "search failed"})
+	want := "pubmed batch run jobs.yaml finished in 1m35s: 1 of 2 jobs succeeded\n" +
+		"• trials: 12 results, exported 12 to trials.ris\n" +
+		"• reviews: failed: search failed"
+	if got := BatchEvent(s).Text; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	e := Event{Event: EventBatchFinished, Text: "done", Data: map[string]int{"failed": 0}}
+	if err := Post(context.Background(), srv.Client(), srv.URL+"/hook", e); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if got["event"] != EventBatchFinished || got["text"] != "done" {
+		t.Errorf("unexpected payload: %v", got)
+	}
+	if err := Post(context.Background(), srv.Client(), srv.URL+"/gone", e); err == nil || !strings.Contains(err.Error(), "410") {
+		t.Errorf("expected an HTTP 410 error, got %v", err)
+	}
+}
+
+func TestPayload_Chat(t *testing.T) {
+	e := Event{Text: strings.Repeat("x", 2500)}
+	slack, _ := Payload(KindSlack, e)
+	if !strings.HasPrefix(string(slack), `{"text":"xxx`) {
+		t.Errorf("unexpected Slack payload %.40s", slack)
+	}
+	discord, _ := Payload(KindDiscord, e)
+	var msg struct{ Content string }
+	if err := json.Unmarshal(discord, &msg); err != nil || len([]rune(msg.Content)) != discordLimit {
+		t.Errorf("Discord message should be cut to %d characters, got %d (%v)", discordLimit, len([]rune(msg.Content)), err)
+	}
+}
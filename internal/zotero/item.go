@@ -0,0 +1,112 @@
+package zotero
+
+import (
+	"html"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+// Creator is an item's author.
+type Creator struct {
+	CreatorType string `json:"creatorType"`
+	FirstName   string `json:"firstName,omitempty"`
+	LastName    string `json:"lastName,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// Tag is an item tag.
+type Tag struct {
+	Tag string `json:"tag"`
+}
+
+// Item is a Zotero item as written to the API: a journal article, or a
+// linked-URL attachment or note belonging to one. Zotero rejects fields
+// that do not apply to an item type, so every field is omitted when empty.
+type Item struct {
+	ItemType            string    `json:"itemType"`
+	Title               string    `json:"title,omitempty"`
+	Creators            []Creator `json:"creators,omitempty"`
+	AbstractNote        string    `json:"abstractNote,omitempty"`
+	PublicationTitle    string    `json:"publicationTitle,omitempty"`
+	JournalAbbreviation string    `json:"journalAbbreviation,omitempty"`
+	Volume              string    `json:"volume,omitempty"`
+	Issue               string    `json:"issue,omitempty"`
+	Pages               string    `json:"pages,omitempty"`
+	Date                string    `json:"date,omitempty"`
+	Language            string    `json:"language,omitempty"`
+	DOI                 string    `json:"DOI,omitempty"`
+	URL                 string    `json:"url,omitempty"`
+	Extra               string    `json:"extra,omitempty"`
+	Tags                []Tag     `json:"tags,omitempty"`
+	Collections         []string  `json:"collections,omitempty"`
+	ParentItem          string    `json:"parentItem,omitempty"`
+	LinkMode            string    `json:"linkMode,omitempty"`
+	Note                string    `json:"note,omitempty"`
+}
+
+// NewItem converts a to a journal article item in collection (none when
+// empty). The PMID and PMCID go in Extra, where Zotero's citation styles
+// look for them, and library tags become item tags.
+func NewItem(a eutils.Article, collection string) Item {
+	it := Item{
+		ItemType:            "journalArticle",
+		Title:               a.Title,
+		AbstractNote:        a.Abstract,
+		PublicationTitle:    a.Journal,
+		JournalAbbreviation: a.JournalAbbrev,
+		Volume:              a.Volume,
+		Issue:               a.Issue,
+		Pages:               a.Pages,
+		Date:                strings.TrimSpace(a.Year + " " + a.Month),
+		Language:            a.Language,
+		DOI:                 a.DOI,
+	}
+	if a.PMID != "" {
+		it.URL = "https://pubmed.ncbi.nlm.nih.gov/" + a.PMID + "/"
+	}
+	var extra []string
+	if a.PMID != "" {
+		extra = append(extra, "PMID: "+a.PMID)
+	}
+	if a.PMCID != "" {
+		extra = append(extra, "PMCID: "+a.PMCID)
+	}
+	it.Extra = strings.Join(extra, "\n")
+	for _, au := range a.Authors {
+		if au.CollectiveName != "" {
+			it.Creators = append(it.Creators, Creator{CreatorType: "author", Name: au.CollectiveName})
+			continue
+		}
+		it.Creators = append(it.Creators, Creator{CreatorType: "author", FirstName: au.ForeName, LastName: au.LastName})
+	}
+	for _, t := range a.Tags {
+		it.Tags = append(it.Tags, Tag{Tag: t})
+	}
+	if collection != "" {
+		it.Collections = []string{collection}
+	}
+	return it
+}
+
+// Children returns the child items for a's Zotero item parent: linked-URL
+// attachments for its PMC copy and its open-access copy, and a note for
+// each library note.
+func Children(a eutils.Article, parent string) []Item {
+	var items []Item
+	link := func(title, url string) {
+		items = append(items, Item{ItemType: "attachment", LinkMode: "linked_url", Title: title, URL: url, ParentItem: parent})
+	}
+	pmc := ""
+	if a.PMCID != "" {
+		pmc = "https://pmc.ncbi.nlm.nih.gov/articles/" + a.PMCID + "/"
+		link("Full Text (PMC)", pmc)
+	}
+	if a.OAURL != "" && a.OAURL != pmc {
+		link("Open Access Copy", a.OAURL)
+	}
+	for _, n := range a.Notes {
+		items = append(items, Item{ItemType: "note", Note: "<p>" + html.EscapeString(n) + "</p>", ParentItem: parent})
+	}
+	return items
+}
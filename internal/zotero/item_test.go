@@ -0,0 +1,33 @@
+package zotero
+
+import (
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+)
+
+func TestNewItem(t *testing.T) {
+	a := eutils.Article{
+		PMID: "38000001", Title: "T", Year: "2024", Month: "Mar",
+		Authors: []eutils.Author{{LastName: "Smith", ForeName: "Jane"}, {CollectiveName: "FXS Network"}},
+		Tags:    []string{"rct"},
+	}
+	it := NewItem(a, "")
+	if it.Date != "2024 Mar" || it.URL != "https://pubmed.ncbi.nlm.nih.gov/38000001/" || len(it.Collections) != 0 {
+		t.Errorf("unexpected item: %+v", it)
+	}
+	if it.Creators[0].LastName != "Smith" || it.Creators[1].Name != "FXS Network" || it.Tags[0].Tag != "rct" {
+		t.Errorf("unexpected creators or tags: %+v", it)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	a := eutils.Article{PMCID: "PMC9", OAURL: "https://pmc.ncbi.nlm.nih.gov/articles/PMC9/"}
+	items := Children(a, "P1")
+	if len(items) != 1 || items[0].LinkMode != "linked_url" || items[0].ParentItem != "P1" {
+		t.Errorf("an OA copy in PMC should not be linked twice, got %+v", items)
+	}
+	if items := Children(eutils.Article{}, "P1"); len(items) != 0 {
+		t.Errorf("expected no children, got %+v", items)
+	}
+}
@@ -0,0 +1,256 @@
+// Package zotero creates Zotero items for PubMed articles through the
+// Zotero Web API, so references reach a Zotero library without an RIS
+// round trip.
+package zotero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// DefaultURL is the Zotero Web API.
+const DefaultURL = "https://api.zotero.org"
+
+// EnvAPIKey sets the Zotero API key, overriding the one in the keychain.
+const EnvAPIKey = "ZOTERO_API_KEY"
+
+// KeychainAccount is the keychain account the API key is stored under.
+const KeychainAccount = "zotero"
+
+// BatchSize is the most objects one Zotero write request may contain.
+const BatchSize = 50
+
+// Client writes to one Zotero library. Requests share the NCBI base
+// client's HTTP client, rate limit, and response size cap.
+type Client struct {
+	*ncbi.BaseClient
+	URL    string
+	APIKey string
+	// Library is the library path, "users/<id>" or "groups/<id>".
+	Library string
+}
+
+// NewClient creates a Zotero client for library using an existing NCBI base
+// client.
+func NewClient(base *ncbi.BaseClient, apiKey, library string) *Client {
+	return &Client{BaseClient: base, URL: DefaultURL, APIKey: apiKey, Library: library}
+}
+
+// KeyInfo describes the owner of an API key.
+type KeyInfo struct {
+	UserID   int    `json:"userID"`
+	Username string `json:"username"`
+}
+
+// UserLibrary is the library path of a user's personal library.
+func UserLibrary(userID int) string {
+	return "users/" + strconv.Itoa(userID)
+}
+
+// GroupLibrary is the library path of a group library.
+func GroupLibrary(groupID string) string {
+	return "groups/" + groupID
+}
+
+// Key returns who the client's API key belongs to, which also checks that
+// the key is valid.
+func (c *Client) Key(ctx context.Context) (*KeyInfo, error) {
+	body, err := c.do(ctx, http.MethodGet, "/keys/current", nil)
+	if err != nil {
+		return nil, fmt.Errorf("checking Zotero API key: %w", err)
+	}
+	if body == nil {
+		return nil, fmt.Errorf("checking Zotero API key: key not recognized")
+	}
+	var info KeyInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("parsing Zotero key info: %w", err)
+	}
+	return &info, nil
+}
+
+// Collection returns the key of the collection named name, creating it when
+// the library has none by that name.
+func (c *Client) Collection(ctx context.Context, name string) (string, error) {
+	const page = 100
+	for start := 0; ; start += page {
+		body, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/%s/collections?limit=%d&start=%d", c.Library, page, start), nil)
+		if err != nil {
+			return "", fmt.Errorf("listing Zotero collections: %w", err)
+		}
+		var collections []struct {
+			Key  string `json:"key"`
+			Data struct {
+				Name string `json:"name"`
+			} `json:"data"`
+		}
+		if body != nil {
+			if err := json.Unmarshal(body, &collections); err != nil {
+				return "", fmt.Errorf("parsing Zotero collections: %w", err)
+			}
+		}
+		for _, col := range collections {
+			if strings.EqualFold(col.Data.Name, name) {
+				return col.Key, nil
+			}
+		}
+		if len(collections) < page {
+			break
+		}
+	}
+
+	resp, err := c.write(ctx, "/"+c.Library+"/collections", []any{map[string]string{"name": name}})
+	if err != nil {
+		return "", fmt.Errorf("creating Zotero collection %q: %w", name, err)
+	}
+	key, ok := resp.Success["0"]
+	if !ok {
+		return "", fmt.Errorf("creating Zotero collection %q: %s", name, resp.failure("0"))
+	}
+	return key, nil
+}
+
+// Pushed is one article created as a Zotero item.
+type Pushed struct {
+	PMID string `json:"pmid,omitempty"`
+	DOI  string `json:"doi,omitempty"`
+	Key  string `json:"key"`
+}
+
+// Failure is an article or child object Zotero rejected.
+type Failure struct {
+	PMID    string `json:"pmid,omitempty"`
+	DOI     string `json:"doi,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report summarizes a push.
+type Report struct {
+	Library       string    `json:"library"`
+	Collection    string    `json:"collection,omitempty"`
+	CollectionKey string    `json:"collection_key,omitempty"`
+	Items         []Pushed  `json:"items"`
+	Attachments   int       `json:"attachments"`
+	Notes         int       `json:"notes"`
+	Failed        []Failure `json:"failed,omitempty"`
+}
+
+// Push creates an item for each article in the collection with key
+// collection (none when empty), then its children: linked-URL attachments
+// for the PMC and open-access copies and a note for each library note.
+// Rejected objects are reported, not returned as errors.
+func (c *Client) Push(ctx context.Context, articles []eutils.Article, collection string) (*Report, error) {
+	r := &Report{Library: c.Library, CollectionKey: collection}
+	var children []Item
+	var owners []eutils.Article
+	for start := 0; start < len(articles); start += BatchSize {
+		batch := articles[start:min(start+BatchSize, len(articles))]
+		items := make([]any, len(batch))
+		for i, a := range batch {
+			items[i] = NewItem(a, collection)
+		}
+		resp, err := c.write(ctx, "/"+c.Library+"/items", items)
+		if err != nil {
+			return r, fmt.Errorf("creating Zotero items: %w", err)
+		}
+		for i, a := range batch {
+			idx := strconv.Itoa(i)
+			key, ok := resp.Success[idx]
+			if !ok {
+				r.Failed = append(r.Failed, Failure{PMID: a.PMID, DOI: a.DOI, Message: resp.failure(idx)})
+				continue
+			}
+			r.Items = append(r.Items, Pushed{PMID: a.PMID, DOI: a.DOI, Key: key})
+			for _, child := range Children(a, key) {
+				children = append(children, child)
+				owners = append(owners, a)
+			}
+		}
+	}
+
+	for start := 0; start < len(children); start += BatchSize {
+		end := min(start+BatchSize, len(children))
+		items := make([]any, 0, end-start)
+		for _, child := range children[start:end] {
+			items = append(items, child)
+		}
+		resp, err := c.write(ctx, "/"+c.Library+"/items", items)
+		if err != nil {
+			return r, fmt.Errorf("creating Zotero attachments and notes: %w", err)
+		}
+		for i, child := range children[start:end] {
+			idx := strconv.Itoa(i)
+			if _, ok := resp.Success[idx]; !ok {
+				a := owners[start+i]
+				r.Failed = append(r.Failed, Failure{PMID: a.PMID, DOI: a.DOI, Message: child.ItemType + ": " + resp.failure(idx)})
+				continue
+			}
+			if child.ItemType == "note" {
+				r.Notes++
+			} else {
+				r.Attachments++
+			}
+		}
+	}
+	return r, nil
+}
+
+// writeResponse is Zotero's reply to a multi-object write, keyed by each
+// object's index in the request.
+type writeResponse struct {
+	Success map[string]string `json:"success"`
+	Failed  map[string]struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"failed"`
+}
+
+func (r *writeResponse) failure(idx string) string {
+	if f, ok := r.Failed[idx]; ok {
+		return fmt.Sprintf("%s (HTTP %d)", f.Message, f.Code)
+	}
+	return "not created"
+}
+
+func (c *Client) write(ctx context.Context, path string, objects []any) (*writeResponse, error) {
+	body, err := c.do(ctx, http.MethodPost, path, objects)
+	if err != nil {
+		return nil, err
+	}
+	var resp writeResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing Zotero response: %w", err)
+	}
+	return &resp, nil
+}
+
+// do sends an authenticated API request, with v as its JSON body when set.
+func (c *Client) do(ctx context.Context, method, path string, v any) ([]byte, error) {
+	var body io.Reader
+	if v != nil {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Zotero-API-Version", "3")
+	req.Header.Set("Zotero-API-Key", c.APIKey)
+	if v != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.Send(req)
+}
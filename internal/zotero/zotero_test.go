@@ -0,0 +1,104 @@
+package zotero
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/henrybloomingdale/pubmed-cli/internal/eutils"
+	"github.com/henrybloomingdale/pubmed-cli/internal/ncbi"
+)
+
+// fakeZotero records written objects and rejects items titled "bad".
+type fakeZotero struct {
+	t       *testing.T
+	written [][]map[string]any
+}
+
+func (f *fakeZotero) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Zotero-API-Key") != "key" || r.Header.Get("Zotero-API-Version") != "3" {
+		f.t.Errorf("missing API headers on %s", r.URL)
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/keys/current":
+		w.Write([]byte(`{"key":"key","userID":42,"username":"reviewer"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/users/42/collections":
+		w.Write([]byte(`[{"key":"OLD1","data":{"key":"OLD1","name":"Other"}}]`))
+	case r.Method == http.MethodPost:
+		var objects []map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&objects); err != nil {
+			f.t.Fatalf("decoding write: %v", err)
+		}
+		f.written = append(f.written, objects)
+		success := map[string]string{}
+		failed := map[string]any{}
+		for i, o := range objects {
+			idx := strconv.Itoa(i)
+			if o["title"] == "bad" {
+				failed[idx] = map[string]any{"code": 400, "message": "Invalid field"}
+				continue
+			}
+			success[idx] = "KEY" + idx
+		}
+		json.NewEncoder(w).Encode(map[string]any{"success": success, "failed": failed})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeZotero) {
+	t.Helper()
+	fake := &fakeZotero{t: t}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	c := NewClient(ncbi.NewBaseClient(ncbi.WithHTTPClient(srv.Client())), "key", "")
+	c.URL = srv.URL
+	return c, fake
+}
+
+func TestPush(t *testing.T) {
+	c, fake := newTestClient(t)
+	ctx := context.Background()
+
+	info, err := c.Key(ctx)
+	if err != nil || info.UserID != 42 {
+		t.Fatalf("Key = %+v, %v", info, err)
+	}
+	c.Library = UserLibrary(info.UserID)
+
+	col, err := c.Collection(ctx, "My Review")
+	if err != nil || col != "KEY0" {
+		t.Fatalf("expected the collection to be created, got %q, %v", col, err)
+	}
+
+	articles := []eutils.Article{
+		{PMID: "1", Title: "Good", PMCID: "PMC9", OAURL: "https://repo.example.org/a.pdf", Notes: []string{"a < b"}},
+		{PMID: "2", Title: "bad"},
+	}
+	r, err := c.Push(ctx, articles, col)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.Items) != 1 || r.Items[0].PMID != "1" || r.Attachments != 2 || r.Notes != 1 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	if len(r.Failed) != 1 || r.Failed[0].PMID != "2" || !strings.Contains(r.Failed[0].Message, "Invalid field") {
+		t.Errorf("expected the rejected item to be reported, got %+v", r.Failed)
+	}
+
+	items := fake.written[1]
+	if items[0]["collections"].([]any)[0] != "KEY0" || items[0]["extra"] != "PMID: 1\nPMCID: PMC9" {
+		t.Errorf("unexpected item: %v", items[0])
+	}
+	children := fake.written[2]
+	if len(children) != 3 || children[0]["parentItem"] != "KEY0" || children[2]["note"] != "<p>a &lt; b</p>" {
+		t.Errorf("unexpected children: %v", children)
+	}
+	if _, ok := children[0]["collections"]; ok {
+		t.Error("child items must not name a collection")
+	}
+}